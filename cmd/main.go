@@ -14,8 +14,9 @@ import (
 	impl "robot_scheduler/internal/dao"
 	"robot_scheduler/internal/database"
 	"robot_scheduler/internal/logger"
-	"robot_scheduler/internal/minio_client"
+	"robot_scheduler/internal/pcd"
 	"robot_scheduler/internal/service"
+	"robot_scheduler/internal/storage"
 
 	_ "robot_scheduler/docs"
 
@@ -31,6 +32,9 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+// @securityDefinitions.apikey DeviceAuth
+// @in header
+// @name Authorization
 func main() {
 	// 初始化配置
 	cfgPath := "configs/config.yaml"
@@ -70,11 +74,15 @@ func main() {
 		// 不阻止程序启动，只记录错误
 	}
 
-	// 初始化MinIO客户端
-	if cfg.Minio != nil && cfg.Minio.Enabled {
-		if err := minio_client.Init(cfg.Minio); err != nil {
-			logger.Fatal("failed to init minio", zap.Error(err))
-		}
+	// 初始化对象存储（MinIO 或本地磁盘）
+	if err := storage.Init(cfg); err != nil {
+		logger.Fatal("failed to init object storage", zap.Error(err))
+	}
+	if store := storage.Backend(); store != nil {
+		logger.Info("object storage initialized", zap.String("type", store.Type()))
+	}
+	if cfg.Minio != nil {
+		pcd.SetMaxDecompressed(int64(cfg.Minio.MaxDecompressed) << 20)
 	}
 
 	// 初始化HTTP服务器
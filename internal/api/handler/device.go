@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"robot_scheduler/internal/logger"
@@ -47,7 +48,7 @@ func (h *DeviceHandler) CreateDevice(c *gin.Context) {
 	device, err := h.deviceService.CreateDevice(c.Request.Context(), &req)
 	if err != nil {
 		logger.Error("failed to create device", zap.Error(err))
-		InternalServerError(c, "创建设备失败: "+err.Error())
+		deviceError(c, err, "创建设备失败: ")
 		return
 	}
 
@@ -128,7 +129,7 @@ func (h *DeviceHandler) UpdateDevice(c *gin.Context) {
 
 	if err := h.deviceService.UpdateDevice(c.Request.Context(), uint(id), &req); err != nil {
 		logger.Error("failed to update device", zap.Error(err), zap.Uint("id", uint(id)))
-		InternalServerError(c, "更新设备失败: "+err.Error())
+		deviceError(c, err, "更新设备失败: ")
 		return
 	}
 
@@ -169,25 +170,31 @@ func (h *DeviceHandler) DeleteDevice(c *gin.Context) {
 
 // ListDevices 查询设备列表
 // @Summary 查询设备列表
-// @Description 查询所有设备
+// @Description 查询所有设备，指定 locationId 时只返回挂载在该位置节点及其下级节点上的设备；指定 loadedSemanticMapId（及 loadedSemanticMapVersion）时只返回已加载该地图（版本）的设备，供调度选择可执行任务的机器人
 // @Tags 设备管理
 // @Accept json
 // @Produce json
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页大小"
+// @Param locationId query int false "位置节点ID"
+// @Param loadedSemanticMapId query int false "已加载的语义地图ID"
+// @Param loadedSemanticMapVersion query int false "已加载的语义地图版本号"
 // @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /devices [get]
 // @Security BearerAuth
 func (h *DeviceHandler) ListDevices(c *gin.Context) {
 	logger.Info("handling list devices request")
 
-	var pageReq dto.PageRequest
-	if err := c.ShouldBindQuery(&pageReq); err != nil {
-		logger.Error("invalid pagination parameters", zap.Error(err))
-		BadRequest(c, "无效的分页参数: "+err.Error())
+	var req dto.DeviceListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid query parameters", zap.Error(err))
+		BadRequest(c, "无效的查询参数: "+err.Error())
 		return
 	}
 
-	devices, err := h.deviceService.ListDevices(c.Request.Context(), pageReq)
+	devices, err := h.deviceService.ListDevices(c.Request.Context(), req)
 	if err != nil {
 		logger.Error("failed to list devices", zap.Error(err))
 		InternalServerError(c, "查询设备列表失败: "+err.Error())
@@ -196,3 +203,12 @@ func (h *DeviceHandler) ListDevices(c *gin.Context) {
 
 	Success(c, devices)
 }
+
+// deviceError 按错误类型返回设备请求的响应，型号目录校验失败视为参数错误
+func deviceError(c *gin.Context, err error, prefix string) {
+	if errors.Is(err, service.ErrInvalidDeviceModel) {
+		BadRequest(c, prefix+err.Error())
+		return
+	}
+	locationError(c, err, prefix)
+}
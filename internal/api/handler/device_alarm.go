@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeviceAlarmHandler 设备告警处理器
+type DeviceAlarmHandler struct {
+	alarmService *service.DeviceAlarmService
+}
+
+func NewDeviceAlarmHandler(alarmService *service.DeviceAlarmService) *DeviceAlarmHandler {
+	return &DeviceAlarmHandler{
+		alarmService: alarmService,
+	}
+}
+
+// ListAlarms 查询设备告警
+// @Summary 查询设备告警
+// @Description 按设备、级别、错误码、是否解除/确认及时间范围分页查询告警
+// @Tags 设备告警
+// @Accept json
+// @Produce json
+// @Param deviceId query int false "设备ID"
+// @Param severity query string false "告警级别(info/warning/critical)"
+// @Param code query string false "错误码"
+// @Param active query bool false "是否未解除"
+// @Param acknowledged query bool false "是否已确认"
+// @Param startTime query string false "产生时间下限(RFC3339)"
+// @Param endTime query string false "产生时间上限(RFC3339)"
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页大小"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/alarms [get]
+// @Security BearerAuth
+func (h *DeviceAlarmHandler) ListAlarms(c *gin.Context) {
+	logger.Info("handling list device alarms request")
+
+	var req dto.DeviceAlarmListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid query parameters", zap.Error(err))
+		BadRequest(c, "无效的查询参数: "+err.Error())
+		return
+	}
+
+	alarms, err := h.alarmService.ListAlarms(c.Request.Context(), req)
+	if err != nil {
+		logger.Error("failed to list device alarms", zap.Error(err))
+		InternalServerError(c, "查询设备告警失败: "+err.Error())
+		return
+	}
+
+	Success(c, alarms)
+}
+
+// GetAlarm 获取告警详情
+// @Summary 获取告警详情
+// @Description 根据告警ID获取告警详情
+// @Tags 设备告警
+// @Accept json
+// @Produce json
+// @Param alarmId path int true "告警ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "告警不存在"
+// @Router /devices/alarms/{alarmId} [get]
+// @Security BearerAuth
+func (h *DeviceAlarmHandler) GetAlarm(c *gin.Context) {
+	id, ok := parseAlarmID(c)
+	if !ok {
+		return
+	}
+
+	logger.Info("handling get device alarm request", zap.Uint("id", id))
+
+	alarm, err := h.alarmService.GetAlarm(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("failed to get device alarm", zap.Error(err), zap.Uint("id", id))
+		NotFound(c, "告警不存在")
+		return
+	}
+
+	Success(c, alarm)
+}
+
+// AcknowledgeAlarm 确认告警
+// @Summary 确认告警
+// @Description 记录当前用户已确认该告警
+// @Tags 设备告警
+// @Accept json
+// @Produce json
+// @Param alarmId path int true "告警ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/alarms/{alarmId}/ack [post]
+// @Security BearerAuth
+func (h *DeviceAlarmHandler) AcknowledgeAlarm(c *gin.Context) {
+	id, ok := parseAlarmID(c)
+	if !ok {
+		return
+	}
+
+	logger.Info("handling acknowledge device alarm request", zap.Uint("id", id))
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	alarm, err := h.alarmService.AcknowledgeAlarm(c.Request.Context(), id, userName)
+	if err != nil {
+		logger.Error("failed to acknowledge device alarm", zap.Error(err), zap.Uint("id", id))
+		InternalServerError(c, "确认告警失败: "+err.Error())
+		return
+	}
+
+	Success(c, alarm)
+}
+
+// ClearAlarm 手动解除告警
+// @Summary 手动解除告警
+// @Description 手动解除告警，设备不再有严重告警时自动恢复为在线状态
+// @Tags 设备告警
+// @Accept json
+// @Produce json
+// @Param alarmId path int true "告警ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/alarms/{alarmId}/clear [post]
+// @Security BearerAuth
+func (h *DeviceAlarmHandler) ClearAlarm(c *gin.Context) {
+	id, ok := parseAlarmID(c)
+	if !ok {
+		return
+	}
+
+	logger.Info("handling clear device alarm request", zap.Uint("id", id))
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	alarm, err := h.alarmService.ClearAlarm(c.Request.Context(), id, userName)
+	if err != nil {
+		logger.Error("failed to clear device alarm", zap.Error(err), zap.Uint("id", id))
+		InternalServerError(c, "解除告警失败: "+err.Error())
+		return
+	}
+
+	Success(c, alarm)
+}
+
+// ReportAlarms 设备告警上报
+// @Summary 设备告警上报
+// @Description 设备上报告警或告警恢复，同一错误码未解除时不会重复产生告警
+// @Tags 设备接入
+// @Accept json
+// @Produce json
+// @Param request body dto.DeviceAlarmReportRequest true "告警列表"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "设备凭证无效"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /device-agent/alarms [post]
+// @Security DeviceAuth
+func (h *DeviceAlarmHandler) ReportAlarms(c *gin.Context) {
+	deviceID := c.GetUint(string(middleware.DeviceIDKey))
+
+	var req dto.DeviceAlarmReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	if err := h.alarmService.ReportAlarms(c.Request.Context(), deviceID, req.Alarms); err != nil {
+		logger.Error("failed to save device alarms", zap.Error(err), zap.Uint("deviceID", deviceID))
+		InternalServerError(c, "告警上报失败: "+err.Error())
+		return
+	}
+
+	Success(c, gin.H{"message": "ok"})
+}
+
+// parseAlarmID 解析路径中的告警ID，失败时直接写入错误响应
+func parseAlarmID(c *gin.Context) (uint, bool) {
+	idStr := c.Param("alarmId")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid alarm id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的告警ID")
+		return 0, false
+	}
+	return uint(id), true
+}
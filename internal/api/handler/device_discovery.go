@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeviceDiscoveryHandler 局域网设备发现处理器
+type DeviceDiscoveryHandler struct {
+	discoveryService *service.DeviceDiscoveryService
+}
+
+func NewDeviceDiscoveryHandler(discoveryService *service.DeviceDiscoveryService) *DeviceDiscoveryHandler {
+	return &DeviceDiscoveryHandler{
+		discoveryService: discoveryService,
+	}
+}
+
+// ScanDevices 扫描局域网设备
+// @Summary 扫描局域网设备
+// @Description 在配置的网卡上发送 UDP 广播/组播探测，返回已发现但未注册的设备
+// @Tags 设备发现
+// @Accept json
+// @Produce json
+// @Success 200 {object} Response "成功"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/discovery/scan [post]
+// @Security BearerAuth
+func (h *DeviceDiscoveryHandler) ScanDevices(c *gin.Context) {
+	logger.Info("handling scan lan devices request")
+
+	list, err := h.discoveryService.Scan(c.Request.Context())
+	if err != nil {
+		logger.Error("failed to scan lan devices", zap.Error(err))
+		InternalServerError(c, "扫描设备失败: "+err.Error())
+		return
+	}
+
+	Success(c, list)
+}
+
+// ListDiscoveredDevices 查询已发现未注册设备
+// @Summary 查询已发现未注册设备
+// @Description 列出通过扫描或设备主动广播发现、但尚未登记的设备
+// @Tags 设备发现
+// @Accept json
+// @Produce json
+// @Success 200 {object} Response "成功"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/discovery [get]
+// @Security BearerAuth
+func (h *DeviceDiscoveryHandler) ListDiscoveredDevices(c *gin.Context) {
+	logger.Info("handling list discovered devices request")
+
+	list, err := h.discoveryService.ListDiscovered(c.Request.Context())
+	if err != nil {
+		logger.Error("failed to list discovered devices", zap.Error(err))
+		InternalServerError(c, "查询已发现设备失败: "+err.Error())
+		return
+	}
+
+	Success(c, list)
+}
+
+// AdoptDevice 认领已发现设备
+// @Summary 认领已发现设备
+// @Description 将已发现设备登记为正式设备，未填写的字段使用设备应答中的值
+// @Tags 设备发现
+// @Accept json
+// @Produce json
+// @Param key path string true "发现记录标识"
+// @Param request body dto.DeviceAdoptRequest false "覆盖的设备信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/discovery/{key}/adopt [post]
+// @Security BearerAuth
+func (h *DeviceDiscoveryHandler) AdoptDevice(c *gin.Context) {
+	key := c.Param("key")
+
+	var req dto.DeviceAdoptRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error("invalid request parameters", zap.Error(err))
+			BadRequest(c, "无效的请求参数: "+err.Error())
+			return
+		}
+	}
+
+	logger.Info("handling adopt discovered device request", zap.String("key", key))
+
+	device, err := h.discoveryService.Adopt(c.Request.Context(), key, &req)
+	if err != nil {
+		logger.Error("failed to adopt discovered device", zap.Error(err), zap.String("key", key))
+		InternalServerError(c, "认领设备失败: "+err.Error())
+		return
+	}
+
+	Success(c, device)
+}
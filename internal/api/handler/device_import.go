@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ImportDevices 批量导入设备
+// @Summary 批量导入设备
+// @Description 上传 CSV 或 JSON 文件批量导入设备，先逐行校验并返回逐行报告；dryRun=true 时仅预览，否则全部通过后在同一事务中写入
+// @Tags 设备管理
+// @Accept multipart/form-data
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param file formData file false "导入文件（.csv/.json），也可直接作为请求体上传"
+// @Param format query string false "文件格式(csv/json)，为空时根据文件扩展名或 Content-Type 判断"
+// @Param dryRun query bool false "仅校验预览，不写入"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/import [post]
+// @Security BearerAuth
+func (h *DeviceHandler) ImportDevices(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+	format := strings.ToLower(c.Query("format"))
+
+	logger.Info("handling import devices request", zap.String("format", format), zap.Bool("dryRun", dryRun))
+
+	var (
+		reader   io.Reader
+		fileName string
+	)
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			logger.Error("import file missing", zap.Error(err))
+			BadRequest(c, "请上传导入文件: "+err.Error())
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			logger.Error("failed to open import file", zap.Error(err))
+			BadRequest(c, "无法读取导入文件: "+err.Error())
+			return
+		}
+		defer file.Close()
+		reader = file
+		fileName = fileHeader.Filename
+	} else {
+		reader = c.Request.Body
+	}
+
+	if format == "" {
+		format = detectDeviceFileFormat(fileName, c.ContentType())
+	}
+	if format != service.DeviceFileFormatCSV && format != service.DeviceFileFormatJSON {
+		BadRequest(c, "无法识别的导入格式，请通过 format 参数指定 csv 或 json")
+		return
+	}
+
+	resp, err := h.deviceService.ImportDevices(c.Request.Context(), format, reader, dryRun)
+	if err != nil {
+		logger.Error("failed to import devices", zap.Error(err))
+		BadRequest(c, "导入设备失败: "+err.Error())
+		return
+	}
+
+	Success(c, resp)
+}
+
+// ExportDevices 导出设备
+// @Summary 导出设备
+// @Description 导出全部设备为 CSV 或 JSON 文件，不包含登录用户名、密码及设备凭证，导出文件可直接用于导入
+// @Tags 设备管理
+// @Produce text/csv
+// @Produce json
+// @Param format query string false "文件格式(csv/json)，默认 csv"
+// @Success 200 {file} file "导出文件"
+// @Failure 400 {object} Response "参数错误"
+// @Router /devices/export [get]
+// @Security BearerAuth
+func (h *DeviceHandler) ExportDevices(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", service.DeviceFileFormatCSV))
+
+	logger.Info("handling export devices request", zap.String("format", format))
+
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case service.DeviceFileFormatCSV:
+	case service.DeviceFileFormatJSON:
+		contentType = "application/json; charset=utf-8"
+	default:
+		BadRequest(c, "无效的导出格式，仅支持 csv 或 json")
+		return
+	}
+
+	fileName := fmt.Sprintf("devices_%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+
+	if err := h.deviceService.ExportDevices(c.Request.Context(), format, c.Writer); err != nil {
+		// 响应头已写出，只能记录日志并中断
+		logger.Error("failed to export devices", zap.Error(err))
+		c.Abort()
+		return
+	}
+}
+
+// detectDeviceFileFormat 根据文件扩展名或 Content-Type 判断导入格式
+func detectDeviceFileFormat(fileName, contentType string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return service.DeviceFileFormatCSV
+	case ".json":
+		return service.DeviceFileFormatJSON
+	}
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return service.DeviceFileFormatCSV
+	case strings.Contains(contentType, "json"):
+		return service.DeviceFileFormatJSON
+	}
+	return ""
+}
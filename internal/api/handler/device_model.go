@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"strconv"
+
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeviceModelHandler 设备型号目录处理器
+type DeviceModelHandler struct {
+	modelService *service.DeviceModelService
+}
+
+func NewDeviceModelHandler(modelService *service.DeviceModelService) *DeviceModelHandler {
+	return &DeviceModelHandler{
+		modelService: modelService,
+	}
+}
+
+// CreateModel 创建设备型号
+// @Summary 创建设备型号
+// @Description 在设备型号目录中登记新的厂商/型号，登记后即可创建对应类型的设备
+// @Tags 设备型号
+// @Accept json
+// @Produce json
+// @Param request body dto.DeviceModelCreateRequest true "型号信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /device-models [post]
+// @Security BearerAuth
+func (h *DeviceModelHandler) CreateModel(c *gin.Context) {
+	logger.Info("handling create device model request")
+
+	var req dto.DeviceModelCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	model, err := h.modelService.CreateModel(c.Request.Context(), &req)
+	if err != nil {
+		logger.Error("failed to create device model", zap.Error(err))
+		InternalServerError(c, "创建设备型号失败: "+err.Error())
+		return
+	}
+
+	Success(c, model)
+}
+
+// GetModel 获取设备型号
+// @Summary 获取设备型号
+// @Description 根据ID获取设备型号
+// @Tags 设备型号
+// @Accept json
+// @Produce json
+// @Param id path int true "型号ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "型号不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /device-models/{id} [get]
+// @Security BearerAuth
+func (h *DeviceModelHandler) GetModel(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid device model id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的型号ID")
+		return
+	}
+
+	logger.Info("handling get device model request", zap.Uint("id", uint(id)))
+
+	model, err := h.modelService.GetModel(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to get device model", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "获取设备型号失败: "+err.Error())
+		return
+	}
+
+	if model == nil {
+		logger.Warn("device model not found", zap.Uint("id", uint(id)))
+		NotFound(c, "设备型号不存在")
+		return
+	}
+
+	Success(c, model)
+}
+
+// UpdateModel 更新设备型号
+// @Summary 更新设备型号
+// @Description 更新设备型号信息，厂商不可修改；停用后不能再创建该型号的设备
+// @Tags 设备型号
+// @Accept json
+// @Produce json
+// @Param id path int true "型号ID"
+// @Param request body dto.DeviceModelUpdateRequest true "型号信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /device-models/{id} [put]
+// @Security BearerAuth
+func (h *DeviceModelHandler) UpdateModel(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid device model id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的型号ID")
+		return
+	}
+
+	logger.Info("handling update device model request", zap.Uint("id", uint(id)))
+
+	var req dto.DeviceModelUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	model, err := h.modelService.UpdateModel(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		logger.Error("failed to update device model", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "更新设备型号失败: "+err.Error())
+		return
+	}
+
+	Success(c, model)
+}
+
+// DeleteModel 删除设备型号
+// @Summary 删除设备型号
+// @Description 删除设备型号，仍有设备使用该型号时不允许删除
+// @Tags 设备型号
+// @Accept json
+// @Produce json
+// @Param id path int true "型号ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /device-models/{id} [delete]
+// @Security BearerAuth
+func (h *DeviceModelHandler) DeleteModel(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid device model id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的型号ID")
+		return
+	}
+
+	logger.Info("handling delete device model request", zap.Uint("id", uint(id)))
+
+	if err := h.modelService.DeleteModel(c.Request.Context(), uint(id)); err != nil {
+		logger.Error("failed to delete device model", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "删除设备型号失败: "+err.Error())
+		return
+	}
+
+	Success(c, gin.H{"message": "删除成功"})
+}
+
+// ListModels 查询设备型号列表
+// @Summary 查询设备型号列表
+// @Description 按厂商、类型、启用状态分页查询设备型号
+// @Tags 设备型号
+// @Accept json
+// @Produce json
+// @Param company query string false "设备厂商"
+// @Param type query string false "设备类型"
+// @Param enabled query bool false "是否启用"
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页大小"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /device-models [get]
+// @Security BearerAuth
+func (h *DeviceModelHandler) ListModels(c *gin.Context) {
+	logger.Info("handling list device models request")
+
+	var req dto.DeviceModelListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid query parameters", zap.Error(err))
+		BadRequest(c, "无效的查询参数: "+err.Error())
+		return
+	}
+
+	models, err := h.modelService.ListModels(c.Request.Context(), req)
+	if err != nil {
+		logger.Error("failed to list device models", zap.Error(err))
+		InternalServerError(c, "查询设备型号失败: "+err.Error())
+		return
+	}
+
+	Success(c, models)
+}
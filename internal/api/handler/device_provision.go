@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeviceProvisionHandler 设备注册与凭证处理器
+type DeviceProvisionHandler struct {
+	provisionService *service.DeviceProvisionService
+}
+
+func NewDeviceProvisionHandler(provisionService *service.DeviceProvisionService) *DeviceProvisionHandler {
+	return &DeviceProvisionHandler{
+		provisionService: provisionService,
+	}
+}
+
+// CreateEnrollmentCode 创建设备注册码
+// @Summary 创建设备注册码
+// @Description 创建一次性设备注册码，注册码明文只在本次响应中返回
+// @Tags 设备注册
+// @Accept json
+// @Produce json
+// @Param request body dto.DeviceEnrollmentCodeCreateRequest true "注册码参数"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/enrollment-codes [post]
+// @Security BearerAuth
+func (h *DeviceProvisionHandler) CreateEnrollmentCode(c *gin.Context) {
+	logger.Info("handling create device enrollment code request")
+
+	var req dto.DeviceEnrollmentCodeCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	resp, err := h.provisionService.CreateEnrollmentCode(c.Request.Context(), userName, &req)
+	if err != nil {
+		logger.Error("failed to create device enrollment code", zap.Error(err))
+		InternalServerError(c, "创建注册码失败: "+err.Error())
+		return
+	}
+
+	Success(c, resp)
+}
+
+// RevokeCredential 吊销设备凭证
+// @Summary 吊销设备凭证
+// @Description 吊销设备的全部凭证，设备需使用新的注册码重新注册
+// @Tags 设备注册
+// @Accept json
+// @Produce json
+// @Param id path int true "设备ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/{id}/credential [delete]
+// @Security BearerAuth
+func (h *DeviceProvisionHandler) RevokeCredential(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid device id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的设备ID")
+		return
+	}
+
+	logger.Info("handling revoke device credential request", zap.Uint("id", uint(id)))
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	if err := h.provisionService.RevokeCredential(c.Request.Context(), uint(id), userName); err != nil {
+		logger.Error("failed to revoke device credential", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "吊销设备凭证失败: "+err.Error())
+		return
+	}
+
+	Success(c, gin.H{"message": "吊销成功"})
+}
+
+// ListTelemetry 查询设备遥测记录
+// @Summary 查询设备遥测记录
+// @Description 按时间倒序分页查询设备上报的遥测数据，指定 frame 时把标明坐标系的位姿换算到该坐标系
+// @Tags 设备注册
+// @Accept json
+// @Produce json
+// @Param id path int true "设备ID"
+// @Param frame query string false "返回位姿所用的坐标系"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或无法换算到指定坐标系"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/{id}/telemetry [get]
+// @Security BearerAuth
+func (h *DeviceProvisionHandler) ListTelemetry(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid device id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的设备ID")
+		return
+	}
+
+	var pageReq dto.PageRequest
+	if err := c.ShouldBindQuery(&pageReq); err != nil {
+		logger.Error("invalid pagination parameters", zap.Error(err))
+		BadRequest(c, "无效的分页参数: "+err.Error())
+		return
+	}
+
+	frameName := c.Query("frame")
+	logger.Info("handling list device telemetry request", zap.Uint("id", uint(id)), zap.String("frame", frameName))
+
+	records, err := h.provisionService.ListTelemetry(c.Request.Context(), uint(id), pageReq, frameName)
+	if err != nil {
+		logger.Error("failed to list device telemetry", zap.Error(err), zap.Uint("id", uint(id)))
+		if errors.Is(err, frame.ErrNoTransform) {
+			BadRequest(c, "查询设备遥测记录失败: "+err.Error())
+			return
+		}
+		InternalServerError(c, "查询设备遥测记录失败: "+err.Error())
+		return
+	}
+
+	Success(c, records)
+}
+
+// Enroll 设备注册
+// @Summary 设备注册
+// @Description 机器人使用注册码注册，返回长期设备凭证（无需用户登录）；序列号已注册时须在 token 中携带该设备当前的凭证
+// @Tags 设备接入
+// @Accept json
+// @Produce json
+// @Param request body dto.DeviceEnrollRequest true "设备自报信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "注册码无效"
+// @Failure 409 {object} Response "序列号已注册且未携带该设备的凭证"
+// @Router /device-agent/enroll [post]
+func (h *DeviceProvisionHandler) Enroll(c *gin.Context) {
+	logger.Info("handling device enroll request")
+
+	var req dto.DeviceEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	resp, err := h.provisionService.Enroll(c.Request.Context(), c.ClientIP(), &req)
+	if err != nil {
+		logger.Warn("device enroll failed", zap.Error(err), zap.String("serialNumber", req.SerialNumber))
+		if errors.Is(err, service.ErrDeviceAlreadyEnrolled) {
+			Error(c, http.StatusConflict, "设备注册失败: "+err.Error())
+			return
+		}
+		Unauthorized(c, "设备注册失败: "+err.Error())
+		return
+	}
+
+	Success(c, resp)
+}
+
+// Heartbeat 设备心跳
+// @Summary 设备心跳
+// @Description 设备定期上报心跳，刷新在线状态
+// @Tags 设备接入
+// @Accept json
+// @Produce json
+// @Param request body dto.DeviceHeartbeatRequest false "心跳信息"
+// @Success 200 {object} Response "成功"
+// @Failure 401 {object} Response "设备凭证无效"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /device-agent/heartbeat [post]
+// @Security DeviceAuth
+func (h *DeviceProvisionHandler) Heartbeat(c *gin.Context) {
+	deviceID := c.GetUint(string(middleware.DeviceIDKey))
+
+	var req dto.DeviceHeartbeatRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error("invalid request parameters", zap.Error(err))
+			BadRequest(c, "无效的请求参数: "+err.Error())
+			return
+		}
+	}
+
+	if err := h.provisionService.Heartbeat(c.Request.Context(), deviceID, &req); err != nil {
+		logger.Error("failed to handle device heartbeat", zap.Error(err), zap.Uint("deviceID", deviceID))
+		InternalServerError(c, "心跳处理失败: "+err.Error())
+		return
+	}
+
+	Success(c, gin.H{"message": "ok"})
+}
+
+// ReportTelemetry 设备遥测上报
+// @Summary 设备遥测上报
+// @Description 设备上报位姿、电量等遥测数据
+// @Tags 设备接入
+// @Accept json
+// @Produce json
+// @Param request body dto.DeviceTelemetryRequest true "遥测数据"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "设备凭证无效"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /device-agent/telemetry [post]
+// @Security DeviceAuth
+func (h *DeviceProvisionHandler) ReportTelemetry(c *gin.Context) {
+	deviceID := c.GetUint(string(middleware.DeviceIDKey))
+
+	var req dto.DeviceTelemetryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	if err := h.provisionService.ReportTelemetry(c.Request.Context(), deviceID, &req); err != nil {
+		logger.Error("failed to save device telemetry", zap.Error(err), zap.Uint("deviceID", deviceID))
+		InternalServerError(c, "遥测上报失败: "+err.Error())
+		return
+	}
+
+	Success(c, gin.H{"message": "ok"})
+}
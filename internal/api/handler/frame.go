@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FrameHandler 坐标系变换处理器
+type FrameHandler struct {
+	frameService *service.FrameService
+}
+
+func NewFrameHandler(frameService *service.FrameService) *FrameHandler {
+	return &FrameHandler{
+		frameService: frameService,
+	}
+}
+
+// ListFrames 查询坐标系
+// @Summary 查询坐标系
+// @Description 返回已登记变换涉及的全部坐标系名称。点云地图的坐标系为 pcd/{id}，二维栅格地图为 grid/{id}，其余(如 building/A)由变换自行定义
+// @Tags 坐标系
+// @Accept json
+// @Produce json
+// @Success 200 {object} Response "成功"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/frames [get]
+// @Security BearerAuth
+func (h *FrameHandler) ListFrames(c *gin.Context) {
+	logger.Debug("handling list frames request")
+
+	frames, err := h.frameService.ListFrames(c.Request.Context())
+	if err != nil {
+		logger.Error("failed to list frames", zap.Error(err))
+		InternalServerError(c, "查询坐标系失败: "+err.Error())
+		return
+	}
+
+	Success(c, frames)
+}
+
+// ListFrameTransforms 查询坐标系变换列表
+// @Summary 查询坐标系变换列表
+// @Description 查询已登记的坐标系变换，指定 frame 时只返回与该坐标系直接相连的变换
+// @Tags 坐标系
+// @Accept json
+// @Produce json
+// @Param frame query string false "坐标系名称"
+// @Success 200 {object} Response "成功"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/frames/transforms [get]
+// @Security BearerAuth
+func (h *FrameHandler) ListFrameTransforms(c *gin.Context) {
+	frameName := c.Query("frame")
+	logger.Debug("handling list frame transforms request", zap.String("frame", frameName))
+
+	transforms, err := h.frameService.ListTransforms(c.Request.Context(), frameName)
+	if err != nil {
+		logger.Error("failed to list frame transforms", zap.Error(err))
+		InternalServerError(c, "查询坐标系变换失败: "+err.Error())
+		return
+	}
+
+	Success(c, transforms)
+}
+
+// GetFrameTransform 获取坐标系变换
+// @Summary 获取坐标系变换
+// @Description 根据ID获取坐标系变换
+// @Tags 坐标系
+// @Accept json
+// @Produce json
+// @Param id path int true "变换ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "坐标系变换不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/frames/transforms/{id} [get]
+// @Security BearerAuth
+func (h *FrameHandler) GetFrameTransform(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid frame transform id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的坐标系变换ID")
+		return
+	}
+
+	transform, err := h.frameService.GetTransform(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to get frame transform", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "获取坐标系变换失败: "+err.Error())
+		return
+	}
+	if transform == nil {
+		NotFound(c, "坐标系变换不存在")
+		return
+	}
+
+	Success(c, transform)
+}
+
+// CreateFrameTransform 创建坐标系变换
+// @Summary 创建坐标系变换
+// @Description 登记子坐标系到父坐标系的刚体变换，平面变换只需填写 x、y、yaw。任意两个坐标系之间只能有一条换算链，两坐标系已可经其他变换换算时返回 400
+// @Tags 坐标系
+// @Accept json
+// @Produce json
+// @Param request body dto.FrameTransformRequest true "变换信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或与已有变换构成环路"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/frames/transforms [post]
+// @Security BearerAuth
+func (h *FrameHandler) CreateFrameTransform(c *gin.Context) {
+	var req dto.FrameTransformRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling create frame transform request", zap.String("parent", req.ParentFrame), zap.String("child", req.ChildFrame))
+
+	transform, err := h.frameService.CreateTransform(c.Request.Context(), &req, userName)
+	if err != nil {
+		logger.Error("failed to create frame transform", zap.Error(err))
+		frameError(c, err, "创建坐标系变换失败: ")
+		return
+	}
+
+	Success(c, transform)
+}
+
+// UpdateFrameTransform 更新坐标系变换
+// @Summary 更新坐标系变换
+// @Description 更新坐标系变换，更新后同样不能与其他变换构成环路
+// @Tags 坐标系
+// @Accept json
+// @Produce json
+// @Param id path int true "变换ID"
+// @Param request body dto.FrameTransformRequest true "变换信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或与已有变换构成环路"
+// @Failure 404 {object} Response "坐标系变换不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/frames/transforms/{id} [put]
+// @Security BearerAuth
+func (h *FrameHandler) UpdateFrameTransform(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid frame transform id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的坐标系变换ID")
+		return
+	}
+
+	var req dto.FrameTransformRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	logger.Info("handling update frame transform request", zap.Uint("id", uint(id)))
+
+	transform, err := h.frameService.UpdateTransform(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		logger.Error("failed to update frame transform", zap.Error(err), zap.Uint("id", uint(id)))
+		frameError(c, err, "更新坐标系变换失败: ")
+		return
+	}
+	if transform == nil {
+		NotFound(c, "坐标系变换不存在")
+		return
+	}
+
+	Success(c, transform)
+}
+
+// DeleteFrameTransform 删除坐标系变换
+// @Summary 删除坐标系变换
+// @Description 删除坐标系变换，依赖该变换的坐标换算随之不可用
+// @Tags 坐标系
+// @Accept json
+// @Produce json
+// @Param id path int true "变换ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/frames/transforms/{id} [delete]
+// @Security BearerAuth
+func (h *FrameHandler) DeleteFrameTransform(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid frame transform id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的坐标系变换ID")
+		return
+	}
+
+	logger.Info("handling delete frame transform request", zap.Uint("id", uint(id)))
+
+	if err := h.frameService.DeleteTransform(c.Request.Context(), uint(id)); err != nil {
+		logger.Error("failed to delete frame transform", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "删除坐标系变换失败: "+err.Error())
+		return
+	}
+
+	Success(c, gin.H{"message": "删除成功"})
+}
+
+// ConvertPoses 位姿坐标换算
+// @Summary 位姿坐标换算
+// @Description 沿已登记的变换链把一组位姿从 from 坐标系换算到 to 坐标系，同时返回组合后的变换；两坐标系不连通时返回 400
+// @Tags 坐标系
+// @Accept json
+// @Produce json
+// @Param request body dto.FrameConvertRequest true "换算请求"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或无法换算"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/frames/convert [post]
+// @Security BearerAuth
+func (h *FrameHandler) ConvertPoses(c *gin.Context) {
+	var req dto.FrameConvertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	logger.Debug("handling convert poses request", zap.String("from", req.From), zap.String("to", req.To))
+
+	resp, err := h.frameService.ConvertPoses(c.Request.Context(), &req)
+	if err != nil {
+		logger.Error("failed to convert poses", zap.Error(err))
+		frameError(c, err, "位姿换算失败: ")
+		return
+	}
+
+	Success(c, resp)
+}
+
+// frameError 变换无效或无法换算时返回 400，其余返回 500
+func frameError(c *gin.Context, err error, prefix string) {
+	if errors.Is(err, service.ErrInvalidFrameTransform) || errors.Is(err, frame.ErrNoTransform) {
+		BadRequest(c, prefix+err.Error())
+		return
+	}
+	InternalServerError(c, prefix+err.Error())
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// LocationHandler 位置层级处理器
+type LocationHandler struct {
+	locationService *service.LocationService
+}
+
+func NewLocationHandler(locationService *service.LocationService) *LocationHandler {
+	return &LocationHandler{
+		locationService: locationService,
+	}
+}
+
+// GetLocationTree 获取位置树
+// @Summary 获取位置树
+// @Description 按 园区 → 楼栋 → 楼层 → 区域 返回嵌套的位置树，指定 rootId 时只返回该节点及其下级
+// @Tags 位置层级
+// @Accept json
+// @Produce json
+// @Param rootId query int false "根节点ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "位置节点不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /locations/tree [get]
+// @Security BearerAuth
+func (h *LocationHandler) GetLocationTree(c *gin.Context) {
+	var req dto.LocationTreeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid query parameters", zap.Error(err))
+		BadRequest(c, "无效的查询参数: "+err.Error())
+		return
+	}
+
+	logger.Debug("handling get location tree request", zap.Uintp("rootID", req.RootID))
+
+	tree, err := h.locationService.GetTree(c.Request.Context(), req.RootID)
+	if err != nil {
+		logger.Error("failed to get location tree", zap.Error(err))
+		InternalServerError(c, "获取位置树失败: "+err.Error())
+		return
+	}
+	if tree == nil {
+		NotFound(c, "位置节点不存在")
+		return
+	}
+
+	Success(c, tree)
+}
+
+// ListLocations 查询位置节点
+// @Summary 查询位置节点
+// @Description 按上级节点与层级查询位置节点，不分页
+// @Tags 位置层级
+// @Accept json
+// @Produce json
+// @Param parentId query int false "上级节点ID，只返回其直接下级"
+// @Param level query string false "层级" Enums(site, building, floor, area)
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /locations [get]
+// @Security BearerAuth
+func (h *LocationHandler) ListLocations(c *gin.Context) {
+	var req dto.LocationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid query parameters", zap.Error(err))
+		BadRequest(c, "无效的查询参数: "+err.Error())
+		return
+	}
+
+	logger.Debug("handling list locations request")
+
+	locations, err := h.locationService.ListLocations(c.Request.Context(), req)
+	if err != nil {
+		logger.Error("failed to list locations", zap.Error(err))
+		InternalServerError(c, "查询位置节点失败: "+err.Error())
+		return
+	}
+
+	Success(c, locations)
+}
+
+// GetLocation 获取位置节点
+// @Summary 获取位置节点
+// @Description 根据ID获取位置节点，path 为从园区到上级节点的路径
+// @Tags 位置层级
+// @Accept json
+// @Produce json
+// @Param id path int true "位置节点ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "位置节点不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /locations/{id} [get]
+// @Security BearerAuth
+func (h *LocationHandler) GetLocation(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid location id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的位置节点ID")
+		return
+	}
+
+	location, err := h.locationService.GetLocation(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to get location", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "获取位置节点失败: "+err.Error())
+		return
+	}
+	if location == nil {
+		NotFound(c, "位置节点不存在")
+		return
+	}
+
+	Success(c, location)
+}
+
+// CreateLocation 创建位置节点
+// @Summary 创建位置节点
+// @Description 创建园区、楼栋、楼层或区域。园区没有上级节点，楼栋、楼层、区域的上级分别必须是园区、楼栋、楼层，同一上级下名称不可重复
+// @Tags 位置层级
+// @Accept json
+// @Produce json
+// @Param request body dto.LocationCreateRequest true "位置节点信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或层级不符"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /locations [post]
+// @Security BearerAuth
+func (h *LocationHandler) CreateLocation(c *gin.Context) {
+	var req dto.LocationCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling create location request", zap.String("name", req.Name), zap.String("level", string(req.Level)))
+
+	location, err := h.locationService.CreateLocation(c.Request.Context(), &req, userName)
+	if err != nil {
+		logger.Error("failed to create location", zap.Error(err))
+		locationError(c, err, "创建位置节点失败: ")
+		return
+	}
+
+	Success(c, location)
+}
+
+// UpdateLocation 更新位置节点
+// @Summary 更新位置节点
+// @Description 修改名称、说明或移动到同一层级的其他上级节点，层级不可修改
+// @Tags 位置层级
+// @Accept json
+// @Produce json
+// @Param id path int true "位置节点ID"
+// @Param request body dto.LocationUpdateRequest true "位置节点信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或层级不符"
+// @Failure 404 {object} Response "位置节点不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /locations/{id} [put]
+// @Security BearerAuth
+func (h *LocationHandler) UpdateLocation(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid location id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的位置节点ID")
+		return
+	}
+
+	var req dto.LocationUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	logger.Info("handling update location request", zap.Uint("id", uint(id)))
+
+	location, err := h.locationService.UpdateLocation(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		logger.Error("failed to update location", zap.Error(err), zap.Uint("id", uint(id)))
+		locationError(c, err, "更新位置节点失败: ")
+		return
+	}
+	if location == nil {
+		NotFound(c, "位置节点不存在")
+		return
+	}
+
+	Success(c, location)
+}
+
+// DeleteLocation 删除位置节点
+// @Summary 删除位置节点
+// @Description 删除位置节点，仍有下级节点或挂载的点云地图、语义地图、设备时返回 409
+// @Tags 位置层级
+// @Accept json
+// @Produce json
+// @Param id path int true "位置节点ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 409 {object} Response "位置节点仍在使用"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /locations/{id} [delete]
+// @Security BearerAuth
+func (h *LocationHandler) DeleteLocation(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid location id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的位置节点ID")
+		return
+	}
+
+	logger.Info("handling delete location request", zap.Uint("id", uint(id)))
+
+	if err := h.locationService.DeleteLocation(c.Request.Context(), uint(id)); err != nil {
+		logger.Error("failed to delete location", zap.Error(err), zap.Uint("id", uint(id)))
+		locationError(c, err, "删除位置节点失败: ")
+		return
+	}
+
+	Success(c, gin.H{"message": "删除成功"})
+}
+
+// locationError 位置节点无效时返回 400，仍在使用时返回 409，其余返回 500
+func locationError(c *gin.Context, err error, prefix string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidLocation):
+		BadRequest(c, prefix+err.Error())
+	case errors.Is(err, service.ErrLocationInUse):
+		Error(c, http.StatusConflict, prefix+err.Error())
+	default:
+		InternalServerError(c, prefix+err.Error())
+	}
+}
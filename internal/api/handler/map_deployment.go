@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MapDeploymentHandler 地图下发处理器
+type MapDeploymentHandler struct {
+	deploymentService *service.MapDeploymentService
+}
+
+func NewMapDeploymentHandler(deploymentService *service.MapDeploymentService) *MapDeploymentHandler {
+	return &MapDeploymentHandler{
+		deploymentService: deploymentService,
+	}
+}
+
+// DeployMap 下发地图到设备
+// @Summary 下发地图到设备
+// @Description 把语义地图指定版本（默认当前版本）连同其点云或栅格底图通过设备型号登记的驱动（HTTP 或 SFTP）上传到设备，后台执行，通过下发记录查询进度；成功后设备记录当前加载的地图版本。设备已有未结束的下发时返回 409
+// @Tags 设备管理
+// @Accept json
+// @Produce json
+// @Param id path int true "设备ID"
+// @Param request body dto.MapDeployRequest true "下发请求"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "设备不存在"
+// @Failure 409 {object} Response "已有未结束的下发"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/{id}/maps [post]
+// @Security BearerAuth
+func (h *MapDeploymentHandler) DeployMap(c *gin.Context) {
+	deviceID, ok := parseDeviceID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MapDeployRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request body", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	logger.Info("handling deploy map request", zap.Uint("deviceID", deviceID), zap.Uint("semanticMapID", req.SemanticMapID))
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	deployment, err := h.deploymentService.DeployMap(c.Request.Context(), deviceID, userName, &req)
+	if err != nil {
+		logger.Error("failed to deploy map", zap.Error(err), zap.Uint("deviceID", deviceID))
+		switch {
+		case errors.Is(err, service.ErrInvalidMapDeployment):
+			BadRequest(c, "下发地图失败: "+err.Error())
+		case errors.Is(err, service.ErrMapDeploymentInProgress):
+			Error(c, http.StatusConflict, "下发地图失败: "+err.Error())
+		default:
+			InternalServerError(c, "下发地图失败: "+err.Error())
+		}
+		return
+	}
+	if deployment == nil {
+		NotFound(c, "设备不存在")
+		return
+	}
+
+	Success(c, deployment)
+}
+
+// ListDeployments 查询设备的地图下发记录
+// @Summary 查询设备的地图下发记录
+// @Description 按创建时间倒序返回设备的全部地图下发记录，含状态与上传进度
+// @Tags 设备管理
+// @Accept json
+// @Produce json
+// @Param id path int true "设备ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "设备不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/{id}/maps [get]
+// @Security BearerAuth
+func (h *MapDeploymentHandler) ListDeployments(c *gin.Context) {
+	deviceID, ok := parseDeviceID(c)
+	if !ok {
+		return
+	}
+
+	logger.Info("handling list map deployments request", zap.Uint("deviceID", deviceID))
+
+	deployments, err := h.deploymentService.ListDeployments(c.Request.Context(), deviceID)
+	if err != nil {
+		logger.Error("failed to list map deployments", zap.Error(err), zap.Uint("deviceID", deviceID))
+		InternalServerError(c, "查询下发记录失败: "+err.Error())
+		return
+	}
+	if deployments == nil {
+		NotFound(c, "设备不存在")
+		return
+	}
+
+	Success(c, deployments)
+}
+
+// GetDeployment 获取地图下发记录
+// @Summary 获取地图下发记录
+// @Description 根据下发记录ID获取状态与上传进度
+// @Tags 设备管理
+// @Accept json
+// @Produce json
+// @Param id path int true "设备ID"
+// @Param deploymentId path int true "下发记录ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "下发记录不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /devices/{id}/maps/{deploymentId} [get]
+// @Security BearerAuth
+func (h *MapDeploymentHandler) GetDeployment(c *gin.Context) {
+	deviceID, ok := parseDeviceID(c)
+	if !ok {
+		return
+	}
+	idStr := c.Param("deploymentId")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid map deployment id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的下发记录ID")
+		return
+	}
+
+	logger.Info("handling get map deployment request", zap.Uint("deviceID", deviceID), zap.Uint("id", uint(id)))
+
+	deployment, err := h.deploymentService.GetDeployment(c.Request.Context(), deviceID, uint(id))
+	if err != nil {
+		logger.Error("failed to get map deployment", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "获取下发记录失败: "+err.Error())
+		return
+	}
+	if deployment == nil {
+		NotFound(c, "下发记录不存在")
+		return
+	}
+
+	Success(c, deployment)
+}
+
+// parseDeviceID 解析路径中的设备ID，失败时直接写入错误响应
+func parseDeviceID(c *gin.Context) (uint, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid device id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的设备ID")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ListPCDFileVersions 查询点云地图版本列表
+// @Summary 查询点云地图版本列表
+// @Description 按版本号倒序返回点云地图的全部历史版本
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "点云地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/versions [get]
+// @Security BearerAuth
+func (h *PCDFileHandler) ListPCDFileVersions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid pcd file id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的点云地图ID")
+		return
+	}
+
+	logger.Info("handling list pcd file versions request", zap.Uint("id", uint(id)))
+
+	versions, err := h.pcdService.ListPCDFileVersions(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to list pcd file versions", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "查询点云地图版本失败: "+err.Error())
+		return
+	}
+	if versions == nil {
+		NotFound(c, "点云地图不存在")
+		return
+	}
+
+	Success(c, versions)
+}
+
+// GetPCDFileVersion 获取点云地图版本详情
+// @Summary 获取点云地图版本详情
+// @Description 获取点云地图指定版本的文件与元数据
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "点云地图或版本不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/versions/{version} [get]
+// @Security BearerAuth
+func (h *PCDFileHandler) GetPCDFileVersion(c *gin.Context) {
+	id, version, ok := parseMapVersionParams(c, "无效的点云地图ID")
+	if !ok {
+		return
+	}
+
+	logger.Info("handling get pcd file version request", zap.Uint("id", id), zap.Int("version", version))
+
+	resp, err := h.pcdService.GetPCDFileVersion(c.Request.Context(), id, version)
+	if err != nil {
+		logger.Error("failed to get pcd file version", zap.Error(err), zap.Uint("id", id), zap.Int("version", version))
+		InternalServerError(c, "获取点云地图版本失败: "+err.Error())
+		return
+	}
+	if resp == nil {
+		NotFound(c, "点云地图或版本不存在")
+		return
+	}
+
+	Success(c, resp)
+}
+
+// RollbackPCDFile 回滚点云地图
+// @Summary 回滚点云地图
+// @Description 将点云地图的当前版本指向指定历史版本，恢复该版本的文件与元数据，不产生新版本
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "点云地图或版本不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/versions/{version}/rollback [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) RollbackPCDFile(c *gin.Context) {
+	id, version, ok := parseMapVersionParams(c, "无效的点云地图ID")
+	if !ok {
+		return
+	}
+
+	logger.Info("handling rollback pcd file request", zap.Uint("id", id), zap.Int("version", version))
+
+	file, err := h.pcdService.RollbackPCDFile(c.Request.Context(), id, version)
+	if err != nil {
+		logger.Error("failed to rollback pcd file", zap.Error(err), zap.Uint("id", id), zap.Int("version", version))
+		InternalServerError(c, "回滚点云地图失败: "+err.Error())
+		return
+	}
+	if file == nil {
+		NotFound(c, "点云地图或版本不存在")
+		return
+	}
+
+	Success(c, file)
+}
+
+// ListSemanticMapVersions 查询语义地图版本列表
+// @Summary 查询语义地图版本列表
+// @Description 按版本号倒序返回语义地图的全部历史版本
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "语义地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/versions [get]
+// @Security BearerAuth
+func (h *SemanticMapHandler) ListSemanticMapVersions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid semantic map id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的语义地图ID")
+		return
+	}
+
+	logger.Info("handling list semantic map versions request", zap.Uint("id", uint(id)))
+
+	versions, err := h.semanticService.ListSemanticMapVersions(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to list semantic map versions", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "查询语义地图版本失败: "+err.Error())
+		return
+	}
+	if versions == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	Success(c, versions)
+}
+
+// GetSemanticMapVersion 获取语义地图版本详情
+// @Summary 获取语义地图版本详情
+// @Description 获取语义地图指定版本的语义信息
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "语义地图或版本不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/versions/{version} [get]
+// @Security BearerAuth
+func (h *SemanticMapHandler) GetSemanticMapVersion(c *gin.Context) {
+	id, version, ok := parseMapVersionParams(c, "无效的语义地图ID")
+	if !ok {
+		return
+	}
+
+	logger.Info("handling get semantic map version request", zap.Uint("id", id), zap.Int("version", version))
+
+	resp, err := h.semanticService.GetSemanticMapVersion(c.Request.Context(), id, version)
+	if err != nil {
+		logger.Error("failed to get semantic map version", zap.Error(err), zap.Uint("id", id), zap.Int("version", version))
+		InternalServerError(c, "获取语义地图版本失败: "+err.Error())
+		return
+	}
+	if resp == nil {
+		NotFound(c, "语义地图或版本不存在")
+		return
+	}
+
+	Success(c, resp)
+}
+
+// RollbackSemanticMap 回滚语义地图
+// @Summary 回滚语义地图
+// @Description 将语义地图的当前版本指向指定历史版本，恢复该版本的语义信息，不产生新版本。必须携带 If-Match 请求头，期间已被他人修改时返回 409；编辑锁由他人持有时返回 423
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param version path int true "版本号"
+// @Param If-Match header string true "获取语义地图时响应头中的 ETag"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "语义地图或版本不存在"
+// @Failure 409 {object} Response "修订号已过期"
+// @Failure 423 {object} Response "编辑锁由他人持有"
+// @Failure 428 {object} Response "缺少 If-Match 请求头"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/versions/{version}/rollback [post]
+// @Security BearerAuth
+func (h *SemanticMapHandler) RollbackSemanticMap(c *gin.Context) {
+	id, version, ok := parseMapVersionParams(c, "无效的语义地图ID")
+	if !ok {
+		return
+	}
+
+	ifMatch, ok := parseIfMatch(c, true)
+	if !ok {
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling rollback semantic map request", zap.Uint("id", id), zap.Int("version", version))
+
+	semanticMap, err := h.semanticService.RollbackSemanticMap(c.Request.Context(), id, version, userName, ifMatch)
+	if err != nil {
+		logger.Error("failed to rollback semantic map", zap.Error(err), zap.Uint("id", id), zap.Int("version", version))
+		h.semanticEditError(c, id, err, "回滚语义地图失败: ")
+		return
+	}
+	if semanticMap == nil {
+		NotFound(c, "语义地图或版本不存在")
+		return
+	}
+
+	c.Header("ETag", semanticMapETag(semanticMap.Revision))
+	Success(c, semanticMap)
+}
+
+// parseMapVersionParams 解析路径中的地图ID与版本号，解析失败时直接返回 400
+func parseMapVersionParams(c *gin.Context, invalidIDMessage string) (uint, int, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid map id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, invalidIDMessage)
+		return 0, 0, false
+	}
+
+	versionStr := c.Param("version")
+	version, err := strconv.Atoi(versionStr)
+	if err != nil || version <= 0 {
+		logger.Error("invalid map version", zap.String("version", versionStr), zap.Error(err))
+		BadRequest(c, "无效的版本号")
+		return 0, 0, false
+	}
+	return uint(id), version, true
+}
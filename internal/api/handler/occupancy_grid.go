@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/pcd"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OccupancyGridHandler 二维占据栅格地图处理器
+type OccupancyGridHandler struct {
+	gridService      *service.OccupancyGridService
+	operationService *service.UserOperationService
+}
+
+func NewOccupancyGridHandler(gridService *service.OccupancyGridService, operationService *service.UserOperationService) *OccupancyGridHandler {
+	return &OccupancyGridHandler{
+		gridService:      gridService,
+		operationService: operationService,
+	}
+}
+
+// GetOccupancyGrid 获取栅格地图详情
+// @Summary 获取栅格地图详情
+// @Description 根据ID获取二维占据栅格地图的分辨率、原点、尺寸等信息
+// @Tags 栅格地图
+// @Accept json
+// @Produce json
+// @Param id path int true "栅格地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "栅格地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/occupancy-grids/{id} [get]
+// @Security BearerAuth
+func (h *OccupancyGridHandler) GetOccupancyGrid(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid occupancy grid id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的栅格地图ID")
+		return
+	}
+
+	logger.Debug("handling get occupancy grid request", zap.Uint("id", uint(id)))
+
+	grid, err := h.gridService.GetOccupancyGridByID(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to get occupancy grid", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "获取栅格地图失败: "+err.Error())
+		return
+	}
+	if grid == nil {
+		NotFound(c, "栅格地图不存在")
+		return
+	}
+
+	Success(c, grid)
+}
+
+// ListOccupancyGrids 查询栅格地图列表
+// @Summary 查询栅格地图列表
+// @Description 分页查询二维占据栅格地图
+// @Tags 栅格地图
+// @Accept json
+// @Produce json
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页大小"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/occupancy-grids [get]
+// @Security BearerAuth
+func (h *OccupancyGridHandler) ListOccupancyGrids(c *gin.Context) {
+	logger.Info("handling list occupancy grids request")
+
+	var pageReq dto.PageRequest
+	if err := c.ShouldBindQuery(&pageReq); err != nil {
+		logger.Error("invalid pagination parameters", zap.Error(err))
+		BadRequest(c, "无效的分页参数: "+err.Error())
+		return
+	}
+
+	grids, err := h.gridService.ListOccupancyGrids(c.Request.Context(), pageReq)
+	if err != nil {
+		logger.Error("failed to list occupancy grids", zap.Error(err))
+		InternalServerError(c, "查询栅格地图列表失败: "+err.Error())
+		return
+	}
+
+	Success(c, grids)
+}
+
+// ListPCDOccupancyGrids 查询点云地图生成的栅格地图
+// @Summary 查询点云地图生成的栅格地图
+// @Description 按创建时间倒序返回由指定点云地图生成的二维占据栅格地图
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/occupancy-grids [get]
+// @Security BearerAuth
+func (h *OccupancyGridHandler) ListPCDOccupancyGrids(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid pcd file id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的点云地图ID")
+		return
+	}
+
+	grids, err := h.gridService.ListByPCDFile(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to list pcd occupancy grids", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "查询栅格地图列表失败: "+err.Error())
+		return
+	}
+
+	Success(c, grids)
+}
+
+// maxMapServerYAMLSize 导入时 YAML 描述文件的大小上限
+const maxMapServerYAMLSize = 64 << 10
+
+// ImportOccupancyGrid 导入 ROS map_server 地图
+// @Summary 导入 ROS map_server 地图
+// @Description 上传 PGM 图像(P5/P2，8 位)与 YAML 描述(resolution、origin、negate、occupied_thresh、free_thresh)，校验后存入 MinIO 并创建栅格地图，可作为语义地图的底图
+// @Tags 栅格地图
+// @Accept multipart/form-data
+// @Produce json
+// @Param image formData file true "PGM 图像"
+// @Param yaml formData file true "map_server YAML 描述"
+// @Param name formData string false "栅格地图名称，默认取 YAML 中 image 的文件名"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或地图格式不符"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/occupancy-grids/import [post]
+// @Security BearerAuth
+func (h *OccupancyGridHandler) ImportOccupancyGrid(c *gin.Context) {
+	var req dto.OccupancyGridImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	imageHeader, err := c.FormFile("image")
+	if err != nil {
+		BadRequest(c, "请上传 PGM 图像: "+err.Error())
+		return
+	}
+	yamlHeader, err := c.FormFile("yaml")
+	if err != nil {
+		BadRequest(c, "请上传 YAML 描述文件: "+err.Error())
+		return
+	}
+	if yamlHeader.Size > maxMapServerYAMLSize {
+		BadRequest(c, "YAML 描述文件过大")
+		return
+	}
+
+	yamlFile, err := yamlHeader.Open()
+	if err != nil {
+		BadRequest(c, "无法读取 YAML 描述文件: "+err.Error())
+		return
+	}
+	yamlData, err := io.ReadAll(io.LimitReader(yamlFile, maxMapServerYAMLSize))
+	yamlFile.Close()
+	if err != nil {
+		BadRequest(c, "无法读取 YAML 描述文件: "+err.Error())
+		return
+	}
+	image, err := imageHeader.Open()
+	if err != nil {
+		BadRequest(c, "无法读取 PGM 图像: "+err.Error())
+		return
+	}
+	defer image.Close()
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	req.UserName, _ = userNameValue.(string)
+
+	logger.Info("handling import occupancy grid request", zap.String("name", req.Name), zap.String("image", imageHeader.Filename))
+
+	grid, err := h.gridService.ImportROSMap(c.Request.Context(), &req, yamlData, image)
+	if err != nil {
+		logger.Error("failed to import occupancy grid", zap.Error(err))
+		if errors.Is(err, pcd.ErrInvalidMapServer) || errors.Is(err, pcd.ErrGridTooLarge) {
+			BadRequest(c, "导入栅格地图失败: "+err.Error())
+			return
+		}
+		InternalServerError(c, "导入栅格地图失败: "+err.Error())
+		return
+	}
+
+	Success(c, grid)
+}
+
+// DownloadOccupancyGrid 下载栅格地图
+// @Summary 下载栅格地图
+// @Description 由服务端代理下载栅格地图的 PGM 图像或 YAML 描述，两个文件放在同一目录即可被 ROS map_server 加载
+// @Tags 栅格地图
+// @Produce application/octet-stream
+// @Param id path int true "栅格地图ID"
+// @Param file query string false "下载内容：image（默认）为 PGM 图像，yaml 为描述文件"
+// @Success 200 {file} file "栅格地图文件"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "栅格地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/occupancy-grids/{id}/download [get]
+// @Security BearerAuth
+func (h *OccupancyGridHandler) DownloadOccupancyGrid(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid occupancy grid id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的栅格地图ID")
+		return
+	}
+
+	file := c.Query("file")
+	logger.Info("handling download occupancy grid request", zap.Uint("id", uint(id)), zap.String("file", file))
+
+	download, err := h.gridService.OpenDownload(c.Request.Context(), uint(id), file)
+	if err != nil {
+		logger.Error("failed to open occupancy grid download", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "下载栅格地图失败: "+err.Error())
+		return
+	}
+	if download == nil {
+		NotFound(c, "栅格地图不存在")
+		return
+	}
+	defer download.Object.Close()
+
+	serveMapDownload(c, h.operationService, download, gin.H{"mode": "stream", "type": "occupancy_grid", "fileName": download.FileName})
+}
+
+// DeleteOccupancyGrid 删除栅格地图
+// @Summary 删除栅格地图
+// @Description 删除二维占据栅格地图并清理其 PGM 与 YAML 对象
+// @Tags 栅格地图
+// @Accept json
+// @Produce json
+// @Param id path int true "栅格地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/occupancy-grids/{id} [delete]
+// @Security BearerAuth
+func (h *OccupancyGridHandler) DeleteOccupancyGrid(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid occupancy grid id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的栅格地图ID")
+		return
+	}
+
+	logger.Info("handling delete occupancy grid request", zap.Uint("id", uint(id)))
+
+	if err := h.gridService.DeleteOccupancyGrid(c.Request.Context(), uint(id)); err != nil {
+		logger.Error("failed to delete occupancy grid", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "删除栅格地图失败: "+err.Error())
+		return
+	}
+
+	Success(c, gin.H{"message": "删除成功"})
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetPCDDownloadURL 获取点云地图下载链接
+// @Summary 获取点云地图下载链接
+// @Description 返回短时有效的预签名 GET URL（MinIO 预签名链接，或本地存储由本服务签发的链接）
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Param variant query string false "下载内容：空为原始点云，preview 预览点云，thumbnail 缩略图"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "点云地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/download-url [get]
+// @Security BearerAuth
+func (h *PCDFileHandler) GetPCDDownloadURL(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid pcd file id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的点云地图ID")
+		return
+	}
+
+	variant := c.Query("variant")
+	logger.Info("handling get pcd download url request", zap.Uint("id", uint(id)), zap.String("variant", variant))
+
+	resp, err := h.pcdService.GetDownloadURL(c.Request.Context(), uint(id), variant)
+	if err != nil {
+		logger.Error("failed to generate pcd download url", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "生成下载链接失败: "+err.Error())
+		return
+	}
+	if resp == nil {
+		NotFound(c, "点云地图不存在")
+		return
+	}
+
+	detail := gin.H{"mode": "presigned", "fileName": resp.FileName}
+	if variant != "" {
+		detail["variant"] = variant
+	}
+	recordMapDownload(c, h.operationService, resp.ID, resp.Name, detail)
+	Success(c, resp)
+}
+
+// DownloadPCDFile 下载点云地图
+// @Summary 下载点云地图
+// @Description 由服务端代理读取存储对象并流式返回，支持 Range 断点续传，适用于无法直连对象存储的客户端；传输到文件末尾后记录一次下载操作
+// @Tags 点云地图
+// @Produce application/octet-stream
+// @Param id path int true "点云地图ID"
+// @Param variant query string false "下载内容：空为原始点云，preview 预览点云，thumbnail 缩略图"
+// @Param Range header string false "字节范围，如 bytes=0-1048575"
+// @Success 200 {file} file "点云文件"
+// @Success 206 {file} file "部分内容"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "点云地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/download [get]
+// @Security BearerAuth
+func (h *PCDFileHandler) DownloadPCDFile(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid pcd file id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的点云地图ID")
+		return
+	}
+
+	rangeHeader := c.GetHeader("Range")
+	variant := c.Query("variant")
+	logger.Info("handling download pcd file request", zap.Uint("id", uint(id)), zap.String("range", rangeHeader), zap.String("variant", variant))
+
+	download, err := h.pcdService.OpenDownload(c.Request.Context(), uint(id), variant)
+	if err != nil {
+		logger.Error("failed to open pcd download", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "下载点云地图失败: "+err.Error())
+		return
+	}
+	if download == nil {
+		NotFound(c, "点云地图不存在")
+		return
+	}
+	defer download.Object.Close()
+
+	detail := gin.H{"mode": "stream", "fileName": download.FileName}
+	if rangeHeader != "" {
+		detail["range"] = rangeHeader
+	}
+	if variant != "" {
+		detail["variant"] = variant
+	}
+	serveMapDownload(c, h.operationService, download, detail)
+}
+
+// serveMapDownload 流式返回下载对象，传输到文件末尾后才记录下载操作，
+// 断点续传的多次 Range 请求只在取得最后一段时记录一次
+func serveMapDownload(c *gin.Context, operationService *service.UserOperationService, download *service.PCDDownload, detail gin.H) {
+	// ServeContent 负责 Range/If-Range/If-Modified-Since 处理
+	c.Header("Content-Type", download.ContentType)
+	c.Header("Content-Disposition", download.ContentDisposition())
+	if download.Info.ETag != "" {
+		c.Header("ETag", `"`+download.Info.ETag+`"`)
+	}
+	http.ServeContent(c.Writer, c.Request, download.FileName, download.Info.LastModified, download.Object)
+
+	if !downloadCompleted(c, download.Info.Size) {
+		logger.Debug("map download not completed", zap.Uint("id", download.FileID), zap.Int("status", c.Writer.Status()), zap.Int("written", c.Writer.Size()))
+		return
+	}
+	recordMapDownload(c, operationService, download.FileID, download.Name, detail)
+}
+
+// downloadCompleted 判断响应是否完整写出并到达文件末尾：200 须写满整个文件，206 须为单段且截止于最后一个字节
+func downloadCompleted(c *gin.Context, size int64) bool {
+	written := int64(c.Writer.Size())
+	switch c.Writer.Status() {
+	case http.StatusOK:
+		return written == size
+	case http.StatusPartialContent:
+		var start, end, total int64
+		if _, err := fmt.Sscanf(c.Writer.Header().Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total); err != nil {
+			return false
+		}
+		return end == total-1 && written == end-start+1
+	default:
+		return false
+	}
+}
+
+// recordMapDownload 记录地图下载操作，记录失败不影响下载
+func recordMapDownload(c *gin.Context, operationService *service.UserOperationService, fileID uint, name string, detail gin.H) {
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	extra, _ := json.Marshal(detail)
+	ip := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	_, err := operationService.CreateOperation(c.Request.Context(), &dto.UserOperationCreateRequest{
+		UserName:   userName,
+		Operation:  entity.OperationDownload,
+		Module:     "map",
+		TargetID:   &fileID,
+		TargetName: &name,
+		IP:         &ip,
+		UserAgent:  &userAgent,
+		ExtraInfo:  string(extra),
+	})
+	if err != nil {
+		logger.Warn("failed to record map download operation", zap.Error(err), zap.Uint("id", fileID))
+	}
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
 
 	"robot_scheduler/internal/api/middleware"
@@ -14,24 +16,29 @@ import (
 
 // PCDFileHandler 点云地图处理器
 type PCDFileHandler struct {
-	pcdService *service.PCDFileService
+	pcdService       *service.PCDFileService
+	jobService       *service.PCDJobService
+	operationService *service.UserOperationService
 }
 
-func NewPCDFileHandler(pcdService *service.PCDFileService) *PCDFileHandler {
+func NewPCDFileHandler(pcdService *service.PCDFileService, jobService *service.PCDJobService, operationService *service.UserOperationService) *PCDFileHandler {
 	return &PCDFileHandler{
-		pcdService: pcdService,
+		pcdService:       pcdService,
+		jobService:       jobService,
+		operationService: operationService,
 	}
 }
 
 // GetPCDUploadToken 获取点云地图上传凭证
 // @Summary 获取点云地图上传凭证
-// @Description 返回 MinIO 预签名上传 URL
+// @Description 返回预签名上传 URL（MinIO 预签名链接，或本地存储由本服务签发的链接）
 // @Tags 点云地图
 // @Accept json
 // @Produce json
 // @Param request body dto.PCDFileUploadTokenRequest true "上传文件信息"
 // @Success 200 {object} Response "成功"
 // @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未识别到当前用户"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /maps/pcd-files/upload-token [post]
 // @Security BearerAuth
@@ -52,6 +59,10 @@ func (h *PCDFileHandler) GetPCDUploadToken(c *gin.Context) {
 	resp, err := h.pcdService.GenerateUploadToken(c.Request.Context(), userName, &req)
 	if err != nil {
 		logger.Error("failed to generate pcd upload token", zap.Error(err))
+		if errors.Is(err, service.ErrUploadUserRequired) {
+			Unauthorized(c, "生成上传凭证失败: "+err.Error())
+			return
+		}
 		InternalServerError(c, "生成上传凭证失败: "+err.Error())
 		return
 	}
@@ -59,6 +70,42 @@ func (h *PCDFileHandler) GetPCDUploadToken(c *gin.Context) {
 	Success(c, resp)
 }
 
+// CompletePCDUpload 完成点云地图上传
+// @Summary 完成点云地图上传
+// @Description 校验上传对象的归属与大小后创建点云地图，analysisStatus 为 pending；解析点云、比对校验和(MD5、SHA-256)与按内容去重由后台解析任务完成，结果见点云地图的 analysisStatus 与任务列表
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param request body dto.PCDFileCompleteUploadRequest true "上传完成信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/complete-upload [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) CompletePCDUpload(c *gin.Context) {
+	logger.Info("handling complete pcd upload request")
+
+	var req dto.PCDFileCompleteUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid complete upload request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	// 从 JWT 上下文中获取用户名
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	file, err := h.pcdService.CompleteUpload(c.Request.Context(), userName, &req)
+	if err != nil {
+		logger.Error("failed to complete pcd upload", zap.Error(err), zap.String("objectKey", req.ObjectKey))
+		locationError(c, err, "完成点云地图上传失败: ")
+		return
+	}
+
+	Success(c, file)
+}
+
 // CreatePCDFile 创建点云地图
 // @Summary 创建点云地图
 // @Description 创建新点云地图
@@ -81,10 +128,13 @@ func (h *PCDFileHandler) CreatePCDFile(c *gin.Context) {
 		return
 	}
 
-	file, err := h.pcdService.CreatePCDFile(c.Request.Context(), &req)
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	file, err := h.pcdService.CreatePCDFile(c.Request.Context(), userName, &req)
 	if err != nil {
 		logger.Error("failed to create pcd file", zap.Error(err))
-		InternalServerError(c, "创建点云地图失败: "+err.Error())
+		locationError(c, err, "创建点云地图失败: ")
 		return
 	}
 
@@ -163,9 +213,12 @@ func (h *PCDFileHandler) UpdatePCDFile(c *gin.Context) {
 
 	logger.Info("handling update pcd file request", zap.Uint("id", uint(id)))
 
-	if err := h.pcdService.UpdatePCDFile(c.Request.Context(), uint(id), &req); err != nil {
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	if err := h.pcdService.UpdatePCDFile(c.Request.Context(), uint(id), userName, &req); err != nil {
 		logger.Error("failed to update pcd file", zap.Error(err), zap.Uint("id", uint(id)))
-		InternalServerError(c, "更新点云地图失败: "+err.Error())
+		locationError(c, err, "更新点云地图失败: ")
 		return
 	}
 
@@ -174,13 +227,16 @@ func (h *PCDFileHandler) UpdatePCDFile(c *gin.Context) {
 
 // DeletePCDFile 删除点云地图
 // @Summary 删除点云地图
-// @Description 删除点云地图（软删除）
+// @Description 删除点云地图（软删除），MinIO 对象在保留期过后清理；仍被语义地图或任务引用时拒绝删除，除非指定 cascade=true；引用方中有待执行或执行中的任务时还需指定 force=true
 // @Tags 点云地图
 // @Accept json
 // @Produce json
 // @Param id path int true "点云地图ID"
+// @Param cascade query bool false "是否一并删除引用该地图的语义地图与任务"
+// @Param force query bool false "级联删除时是否同时删除待执行或执行中的任务"
 // @Success 200 {object} Response "成功"
 // @Failure 400 {object} Response "参数错误"
+// @Failure 409 {object} Response "仍被引用"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /maps/pcd-files/{id} [delete]
 // @Security BearerAuth
@@ -193,10 +249,17 @@ func (h *PCDFileHandler) DeletePCDFile(c *gin.Context) {
 		return
 	}
 
-	logger.Info("handling delete pcd file request", zap.Uint("id", uint(id)))
+	cascade, _ := strconv.ParseBool(c.DefaultQuery("cascade", "false"))
+	force, _ := strconv.ParseBool(c.DefaultQuery("force", "false"))
+
+	logger.Info("handling delete pcd file request", zap.Uint("id", uint(id)), zap.Bool("cascade", cascade), zap.Bool("force", force))
 
-	if err := h.pcdService.DeletePCDFile(c.Request.Context(), uint(id)); err != nil {
+	if err := h.pcdService.DeletePCDFile(c.Request.Context(), uint(id), cascade, force); err != nil {
 		logger.Error("failed to delete pcd file", zap.Error(err), zap.Uint("id", uint(id)))
+		if errors.Is(err, service.ErrPCDFileInUse) {
+			Error(c, http.StatusConflict, "删除点云地图失败: "+err.Error())
+			return
+		}
 		InternalServerError(c, "删除点云地图失败: "+err.Error())
 		return
 	}
@@ -206,25 +269,30 @@ func (h *PCDFileHandler) DeletePCDFile(c *gin.Context) {
 
 // ListPCDFiles 查询点云地图列表
 // @Summary 查询点云地图列表
-// @Description 查询所有点云地图
+// @Description 查询所有点云地图，指定 locationId 时只返回挂载在该位置节点及其下级节点上的点云地图，指定 integrityStatus 时只返回该校验结果的点云地图
 // @Tags 点云地图
 // @Accept json
 // @Produce json
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页大小"
+// @Param locationId query int false "位置节点ID"
+// @Param integrityStatus query string false "完整性校验结果" Enums(ok, corrupted, missing)
 // @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /maps/pcd-files [get]
 // @Security BearerAuth
 func (h *PCDFileHandler) ListPCDFiles(c *gin.Context) {
 	logger.Info("handling list pcd files request")
 
-	var pageReq dto.PageRequest
-	if err := c.ShouldBindQuery(&pageReq); err != nil {
-		logger.Error("invalid pagination parameters", zap.Error(err))
-		BadRequest(c, "无效的分页参数: "+err.Error())
+	var req dto.PCDFileListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid query parameters", zap.Error(err))
+		BadRequest(c, "无效的查询参数: "+err.Error())
 		return
 	}
 
-	files, err := h.pcdService.ListPCDFiles(c.Request.Context(), pageReq)
+	files, err := h.pcdService.ListPCDFiles(c.Request.Context(), req)
 	if err != nil {
 		logger.Error("failed to list pcd files", zap.Error(err))
 		InternalServerError(c, "查询点云地图列表失败: "+err.Error())
@@ -233,3 +301,59 @@ func (h *PCDFileHandler) ListPCDFiles(c *gin.Context) {
 
 	Success(c, files)
 }
+
+// AnalyzePCDFile 重新解析点云地图
+// @Summary 重新解析点云地图
+// @Description 重新读取 MinIO 中的点云文件，校验文件头并刷新点数、字段与包围盒等元数据；内容与记录的 SHA-256 不一致时标记为损坏并返回错误
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/analyze [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) AnalyzePCDFile(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid pcd file id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的点云地图ID")
+		return
+	}
+
+	logger.Info("handling analyze pcd file request", zap.Uint("id", uint(id)))
+
+	file, err := h.pcdService.AnalyzePCDFile(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to analyze pcd file", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "解析点云地图失败: "+err.Error())
+		return
+	}
+
+	Success(c, file)
+}
+
+// ReconcilePCDStorage 点云地图存储对账
+// @Summary 点云地图存储对账
+// @Description 比对 MinIO 中的点云对象与点云地图记录，报告没有记录引用的孤立对象以及对象已丢失的记录，不做任何修改
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Success 200 {object} Response "成功"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/reconcile [get]
+// @Security BearerAuth
+func (h *PCDFileHandler) ReconcilePCDStorage(c *gin.Context) {
+	logger.Info("handling reconcile pcd storage request")
+
+	report, err := h.pcdService.Reconcile(c.Request.Context())
+	if err != nil {
+		logger.Error("failed to reconcile pcd storage", zap.Error(err))
+		InternalServerError(c, "存储对账失败: "+err.Error())
+		return
+	}
+
+	Success(c, report)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GeneratePCDPreview 重新生成点云地图预览
+// @Summary 重新生成点云地图预览
+// @Description 创建后台任务，对点云做体素降采样生成预览 PCD 与俯视缩略图；已有未结束的预览任务时直接返回该任务
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/preview [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) GeneratePCDPreview(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid pcd file id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的点云地图ID")
+		return
+	}
+
+	logger.Info("handling generate pcd preview request", zap.Uint("id", uint(id)))
+
+	job, err := h.jobService.EnqueuePreview(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to enqueue pcd preview job", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "创建预览任务失败: "+err.Error())
+		return
+	}
+
+	Success(c, job)
+}
+
+// VerifyPCDFile 校验点云地图完整性
+// @Summary 校验点云地图完整性
+// @Description 创建后台任务，重新读取点云对象计算 SHA-256 并与记录比对，结果写入点云地图的 integrity 字段；已有未结束的校验任务时直接返回该任务
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/verify [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) VerifyPCDFile(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid pcd file id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的点云地图ID")
+		return
+	}
+
+	logger.Info("handling verify pcd file request", zap.Uint("id", uint(id)))
+
+	job, err := h.jobService.EnqueueIntegrityCheck(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to enqueue pcd integrity job", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "创建完整性校验任务失败: "+err.Error())
+		return
+	}
+
+	Success(c, job)
+}
+
+// ListPCDJobs 获取点云地图后台任务
+// @Summary 获取点云地图后台任务
+// @Description 按创建时间倒序返回点云地图的预览生成等后台任务及其状态
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/jobs [get]
+// @Security BearerAuth
+func (h *PCDFileHandler) ListPCDJobs(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid pcd file id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的点云地图ID")
+		return
+	}
+
+	logger.Debug("handling list pcd jobs request", zap.Uint("id", uint(id)))
+
+	jobs, err := h.jobService.ListJobs(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Error("failed to list pcd jobs", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "获取任务列表失败: "+err.Error())
+		return
+	}
+
+	Success(c, jobs)
+}
+
+// GeneratePCDOccupancyGrid 由点云地图生成二维占据栅格
+// @Summary 由点云地图生成二维占据栅格
+// @Description 创建后台任务，按高度带将点云投影为 ROS map_server 格式的占据栅格（PGM + YAML），完成后登记为点云地图的衍生栅格地图；未指定的参数使用配置默认值
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param id path int true "点云地图ID"
+// @Param request body dto.PCDOccupancyGridRequest true "栅格生成参数"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/{id}/occupancy-grids [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) GeneratePCDOccupancyGrid(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid pcd file id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的点云地图ID")
+		return
+	}
+
+	var req dto.PCDOccupancyGridRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid occupancy grid request", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling generate pcd occupancy grid request", zap.Uint("id", uint(id)), zap.String("userName", userName))
+
+	job, err := h.jobService.EnqueueOccupancyGrid(c.Request.Context(), uint(id), userName, &req)
+	if err != nil {
+		logger.Error("failed to enqueue pcd occupancy grid job", zap.Error(err), zap.Uint("id", uint(id)))
+		InternalServerError(c, "创建栅格生成任务失败: "+err.Error())
+		return
+	}
+
+	Success(c, job)
+}
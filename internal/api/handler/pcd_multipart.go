@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// InitPCDMultipartUpload 初始化点云地图分片上传
+// @Summary 初始化点云地图分片上传
+// @Description 创建分片上传并返回对象 Key 与分片规划，适用于数 GB 的大文件
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param request body dto.PCDMultipartInitRequest true "上传文件信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未识别到当前用户"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/multipart [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) InitPCDMultipartUpload(c *gin.Context) {
+	logger.Info("handling init pcd multipart upload request")
+
+	var req dto.PCDMultipartInitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid multipart upload request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	// 从 JWT 上下文中获取用户名
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	resp, err := h.pcdService.InitMultipartUpload(c.Request.Context(), userName, &req)
+	if err != nil {
+		logger.Error("failed to init pcd multipart upload", zap.Error(err))
+		if errors.Is(err, service.ErrUploadUserRequired) {
+			Unauthorized(c, "初始化分片上传失败: "+err.Error())
+			return
+		}
+		InternalServerError(c, "初始化分片上传失败: "+err.Error())
+		return
+	}
+
+	Success(c, resp)
+}
+
+// GetPCDPartUploadURLs 获取分片上传 URL
+// @Summary 获取分片上传 URL
+// @Description 为指定分片返回预签名 PUT URL，可重复调用以续传；每次调用都会顺延未完成上传的保留时间
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param request body dto.PCDMultipartPartURLRequest true "分片信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/multipart/part-urls [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) GetPCDPartUploadURLs(c *gin.Context) {
+	logger.Info("handling get pcd part upload urls request")
+
+	var req dto.PCDMultipartPartURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid part url request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	// 从 JWT 上下文中获取用户名
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	resp, err := h.pcdService.PresignUploadParts(c.Request.Context(), userName, &req)
+	if err != nil {
+		logger.Error("failed to presign pcd upload parts", zap.Error(err), zap.String("objectKey", req.ObjectKey))
+		InternalServerError(c, "生成分片上传URL失败: "+err.Error())
+		return
+	}
+
+	Success(c, resp)
+}
+
+// ListPCDUploadedParts 查询已上传分片
+// @Summary 查询已上传分片
+// @Description 返回已上传的分片与缺失的分片序号，用于断点续传
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param objectKey query string true "对象 Key"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/multipart/parts [get]
+// @Security BearerAuth
+func (h *PCDFileHandler) ListPCDUploadedParts(c *gin.Context) {
+	var req dto.PCDMultipartPartsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid list parts request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	logger.Info("handling list pcd uploaded parts request", zap.String("objectKey", req.ObjectKey))
+
+	// 从 JWT 上下文中获取用户名
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	resp, err := h.pcdService.ListUploadedParts(c.Request.Context(), userName, req.ObjectKey)
+	if err != nil {
+		logger.Error("failed to list pcd uploaded parts", zap.Error(err), zap.String("objectKey", req.ObjectKey))
+		InternalServerError(c, "查询已上传分片失败: "+err.Error())
+		return
+	}
+
+	Success(c, resp)
+}
+
+// CompletePCDMultipartUpload 完成分片上传
+// @Summary 完成分片上传
+// @Description 合并全部分片并校验大小后创建点云地图，点云解析与校验和比对由后台解析任务完成；内容与已有点云地图相同时复用已有对象
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param request body dto.PCDFileCompleteUploadRequest true "上传完成信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/multipart/complete [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) CompletePCDMultipartUpload(c *gin.Context) {
+	logger.Info("handling complete pcd multipart upload request")
+
+	var req dto.PCDFileCompleteUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid complete multipart request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	// 从 JWT 上下文中获取用户名
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	file, err := h.pcdService.CompleteMultipartUpload(c.Request.Context(), userName, &req)
+	if err != nil {
+		logger.Error("failed to complete pcd multipart upload", zap.Error(err), zap.String("objectKey", req.ObjectKey))
+		locationError(c, err, "完成分片上传失败: ")
+		return
+	}
+
+	Success(c, file)
+}
+
+// AbortPCDMultipartUpload 放弃分片上传
+// @Summary 放弃分片上传
+// @Description 放弃分片上传并删除已上传的分片
+// @Tags 点云地图
+// @Accept json
+// @Produce json
+// @Param request body dto.PCDMultipartAbortRequest true "上传信息"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/pcd-files/multipart/abort [post]
+// @Security BearerAuth
+func (h *PCDFileHandler) AbortPCDMultipartUpload(c *gin.Context) {
+	var req dto.PCDMultipartAbortRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid abort multipart request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	logger.Info("handling abort pcd multipart upload request", zap.String("objectKey", req.ObjectKey))
+
+	// 从 JWT 上下文中获取用户名
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	if err := h.pcdService.AbortMultipartUpload(c.Request.Context(), userName, req.ObjectKey); err != nil {
+		logger.Error("failed to abort pcd multipart upload", zap.Error(err), zap.String("objectKey", req.ObjectKey))
+		InternalServerError(c, "放弃分片上传失败: "+err.Error())
+		return
+	}
+
+	Success(c, gin.H{"message": "已放弃上传"})
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"strconv"
 
+	"robot_scheduler/internal/api/middleware"
 	"robot_scheduler/internal/logger"
 	"robot_scheduler/internal/model/dto"
 	"robot_scheduler/internal/service"
@@ -24,13 +25,13 @@ func NewSemanticMapHandler(semanticService *service.SemanticMapService) *Semanti
 
 // CreateSemanticMap 创建语义地图
 // @Summary 创建语义地图
-// @Description 创建新语义地图
+// @Description 创建新语义地图，底图为三维点云地图(pcdFileId)或二维栅格地图(occupancyGridId)，二者必须且只能指定一个
 // @Tags 语义地图
 // @Accept json
 // @Produce json
 // @Param request body dto.SemanticMapCreateRequest true "语义地图信息"
 // @Success 200 {object} Response "成功"
-// @Failure 400 {object} Response "参数错误"
+// @Failure 400 {object} Response "参数错误或底图无效"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /maps/semantic-maps [post]
 // @Security BearerAuth
@@ -47,7 +48,7 @@ func (h *SemanticMapHandler) CreateSemanticMap(c *gin.Context) {
 	semanticMap, err := h.semanticService.CreateSemanticMap(c.Request.Context(), &req)
 	if err != nil {
 		logger.Error("failed to create semantic map", zap.Error(err))
-		InternalServerError(c, "创建语义地图失败: "+err.Error())
+		semanticElementError(c, err, "创建语义地图失败: ")
 		return
 	}
 
@@ -56,13 +57,14 @@ func (h *SemanticMapHandler) CreateSemanticMap(c *gin.Context) {
 
 // GetSemanticMap 获取语义地图
 // @Summary 获取语义地图
-// @Description 根据ID获取语义地图信息
+// @Description 根据ID获取语义地图信息，响应头 ETag 为当前修订号，更新时通过 If-Match 回传。指定 frame 时语义信息中的坐标换算到该坐标系
 // @Tags 语义地图
 // @Accept json
 // @Produce json
 // @Param id path int true "语义地图ID"
+// @Param frame query string false "返回坐标所用的坐标系，默认为底图坐标系"
 // @Success 200 {object} Response "成功"
-// @Failure 400 {object} Response "参数错误"
+// @Failure 400 {object} Response "参数错误或无法换算到指定坐标系"
 // @Failure 404 {object} Response "语义地图不存在"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /maps/semantic-maps/{id} [get]
@@ -76,12 +78,13 @@ func (h *SemanticMapHandler) GetSemanticMap(c *gin.Context) {
 		return
 	}
 
-	logger.Info("handling get semantic map request", zap.Uint("id", uint(id)))
+	frameName := c.Query("frame")
+	logger.Info("handling get semantic map request", zap.Uint("id", uint(id)), zap.String("frame", frameName))
 
-	semanticMap, err := h.semanticService.GetSemanticMapByID(c.Request.Context(), uint(id))
+	semanticMap, err := h.semanticService.GetSemanticMapInFrame(c.Request.Context(), uint(id), frameName)
 	if err != nil {
 		logger.Error("failed to get semantic map", zap.Error(err), zap.Uint("id", uint(id)))
-		InternalServerError(c, "获取语义地图失败: "+err.Error())
+		semanticContentError(c, err, "获取语义地图失败: ")
 		return
 	}
 
@@ -91,20 +94,25 @@ func (h *SemanticMapHandler) GetSemanticMap(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", semanticMapETag(semanticMap.Revision))
 	Success(c, semanticMap)
 }
 
 // UpdateSemanticMap 更新语义地图
 // @Summary 更新语义地图
-// @Description 更新语义地图信息
+// @Description 更新语义地图信息。必须携带 If-Match 请求头（获取语义地图时的 ETag），期间已被他人修改时返回 409 及当前修订号；编辑锁由他人持有时返回 423
 // @Tags 语义地图
 // @Accept json
 // @Produce json
 // @Param id path int true "语义地图ID"
+// @Param If-Match header string true "获取语义地图时响应头中的 ETag"
 // @Param request body dto.SemanticMapUpdateRequest true "更新信息"
 // @Success 200 {object} Response "成功"
 // @Failure 400 {object} Response "参数错误"
 // @Failure 404 {object} Response "语义地图不存在"
+// @Failure 409 {object} Response "修订号已过期"
+// @Failure 423 {object} Response "编辑锁由他人持有"
+// @Failure 428 {object} Response "缺少 If-Match 请求头"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /maps/semantic-maps/{id} [put]
 // @Security BearerAuth
@@ -124,15 +132,25 @@ func (h *SemanticMapHandler) UpdateSemanticMap(c *gin.Context) {
 		return
 	}
 
+	ifMatch, ok := parseIfMatch(c, true)
+	if !ok {
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
 	logger.Info("handling update semantic map request", zap.Uint("id", uint(id)))
 
-	if err := h.semanticService.UpdateSemanticMap(c.Request.Context(), uint(id), &req); err != nil {
+	semanticMap, err := h.semanticService.UpdateSemanticMap(c.Request.Context(), uint(id), &req, userName, ifMatch)
+	if err != nil {
 		logger.Error("failed to update semantic map", zap.Error(err), zap.Uint("id", uint(id)))
-		InternalServerError(c, "更新语义地图失败: "+err.Error())
+		h.semanticEditError(c, uint(id), err, "更新语义地图失败: ")
 		return
 	}
 
-	Success(c, gin.H{"message": "更新成功"})
+	c.Header("ETag", semanticMapETag(semanticMap.Revision))
+	Success(c, gin.H{"message": "更新成功", "revision": semanticMap.Revision})
 }
 
 // DeleteSemanticMap 删除语义地图
@@ -169,25 +187,29 @@ func (h *SemanticMapHandler) DeleteSemanticMap(c *gin.Context) {
 
 // ListSemanticMaps 查询语义地图列表
 // @Summary 查询语义地图列表
-// @Description 查询所有语义地图
+// @Description 查询所有语义地图，指定 locationId 时只返回挂载在该位置节点及其下级节点上的语义地图
 // @Tags 语义地图
 // @Accept json
 // @Produce json
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页大小"
+// @Param locationId query int false "位置节点ID"
 // @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /maps/semantic-maps [get]
 // @Security BearerAuth
 func (h *SemanticMapHandler) ListSemanticMaps(c *gin.Context) {
 	logger.Info("handling list semantic maps request")
 
-	var pageReq dto.PageRequest
-	if err := c.ShouldBindQuery(&pageReq); err != nil {
-		logger.Error("invalid pagination parameters", zap.Error(err))
-		BadRequest(c, "无效的分页参数: "+err.Error())
+	var req dto.SemanticMapListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid query parameters", zap.Error(err))
+		BadRequest(c, "无效的查询参数: "+err.Error())
 		return
 	}
 
-	semanticMaps, err := h.semanticService.ListSemanticMaps(c.Request.Context(), pageReq)
+	semanticMaps, err := h.semanticService.ListSemanticMaps(c.Request.Context(), req)
 	if err != nil {
 		logger.Error("failed to list semantic maps", zap.Error(err))
 		InternalServerError(c, "查询语义地图列表失败: "+err.Error())
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"strconv"
+
+	"robot_scheduler/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CheckSemanticMap 一致性检查
+// @Summary 一致性检查
+// @Description 立即检查语义地图：兴趣点、区域、路网节点是否超出关联点云的包围盒，路网是否存在不连通的子图，禁行区与电梯等互斥区域是否重叠，兴趣点是否位于禁行区或无法经路网往返；报告按 error/warning/info 分级并保存到语义地图
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "语义地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/check [post]
+// @Security BearerAuth
+func (h *SemanticMapHandler) CheckSemanticMap(c *gin.Context) {
+	id, ok := parseSemanticMapID(c)
+	if !ok {
+		return
+	}
+
+	logger.Info("handling check semantic map request", zap.Uint("id", id))
+
+	report, err := h.semanticService.CheckSemanticMap(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("failed to check semantic map", zap.Error(err), zap.Uint("id", id))
+		InternalServerError(c, "一致性检查失败: "+err.Error())
+		return
+	}
+	if report == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	Success(c, report)
+}
+
+// GetSemanticCheck 获取一致性检查报告
+// @Summary 获取一致性检查报告
+// @Description 返回最近一次保存的一致性检查报告，地图编辑后自动重新检查，关联点云更新后由后台任务重新检查；从未检查过时立即检查
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "语义地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/check [get]
+// @Security BearerAuth
+func (h *SemanticMapHandler) GetSemanticCheck(c *gin.Context) {
+	id, ok := parseSemanticMapID(c)
+	if !ok {
+		return
+	}
+
+	logger.Debug("handling get semantic check request", zap.Uint("id", id))
+
+	report, err := h.semanticService.GetSemanticCheck(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("failed to get semantic check", zap.Error(err), zap.Uint("id", id))
+		InternalServerError(c, "获取一致性检查报告失败: "+err.Error())
+		return
+	}
+	if report == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	Success(c, report)
+}
+
+// parseSemanticMapID 解析路径中的语义地图ID，解析失败时直接返回错误响应
+func parseSemanticMapID(c *gin.Context) (uint, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid semantic map id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的语义地图ID")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DiffSemanticMap 比较语义地图版本
+// @Summary 比较语义地图版本
+// @Description 返回两个版本之间新增、删除、修改的兴趣点、区域、路网节点与边，修改的元素给出字段级变更；不指定 to 时与当前内容比较
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param from query int true "起始版本号"
+// @Param to query int false "目标版本号，默认为当前内容"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或语义信息不符合结构定义"
+// @Failure 404 {object} Response "语义地图或版本不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/diff [get]
+// @Security BearerAuth
+func (h *SemanticMapHandler) DiffSemanticMap(c *gin.Context) {
+	id, ok := parseSemanticMapID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SemanticDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid diff parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	logger.Info("handling diff semantic map request", zap.Uint("id", id), zap.Int("from", req.From))
+
+	diff, err := h.semanticService.DiffSemanticMap(c.Request.Context(), id, req.From, req.To)
+	if err != nil {
+		logger.Error("failed to diff semantic map", zap.Error(err), zap.Uint("id", id))
+		semanticContentError(c, err, "比较语义地图版本失败: ")
+		return
+	}
+	if diff == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	Success(c, diff)
+}
+
+// MergeSemanticMap 三方合并语义地图
+// @Summary 三方合并语义地图
+// @Description 以 base 版本为共同祖先合并两个并发编辑分支：ours 默认为当前内容，theirs 为指定版本或请求携带的语义信息。只有一方修改的元素与字段自动合并，双方修改同一字段或一方删除一方修改时列为冲突且不覆盖；apply 为 true 且无冲突时保存为当前内容并生成新版本，有冲突时返回 409 及冲突列表
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param If-Match header string false "获取语义地图时响应头中的 ETag，apply 为 true 时必须携带"
+// @Param request body dto.SemanticMergeRequest true "合并参数"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或语义信息不符合结构定义"
+// @Failure 404 {object} Response "语义地图或版本不存在"
+// @Failure 409 {object} Response "合并存在冲突或修订号已过期"
+// @Failure 423 {object} Response "编辑锁由他人持有"
+// @Failure 428 {object} Response "应用合并结果时缺少 If-Match 请求头"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/merge [post]
+// @Security BearerAuth
+func (h *SemanticMapHandler) MergeSemanticMap(c *gin.Context) {
+	id, ok := parseSemanticMapID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SemanticMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+	if (req.Theirs == nil) == (req.TheirsInfo == nil) {
+		BadRequest(c, "theirs 与 theirsInfo 必须且只能指定一个")
+		return
+	}
+	if req.Apply && req.Ours != nil {
+		BadRequest(c, "只有本分支为当前内容时才能应用合并结果")
+		return
+	}
+
+	ifMatch, ok := parseIfMatch(c, req.Apply)
+	if !ok {
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling merge semantic map request", zap.Uint("id", id), zap.Int("base", req.Base), zap.Bool("apply", req.Apply))
+
+	result, err := h.semanticService.MergeSemanticMap(c.Request.Context(), id, &req, userName, ifMatch)
+	if err != nil {
+		logger.Error("failed to merge semantic map", zap.Error(err), zap.Uint("id", id))
+		h.semanticEditError(c, id, err, "合并语义地图失败: ")
+		return
+	}
+	if result == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	if req.Apply && !result.Applied {
+		c.JSON(http.StatusOK, Response{
+			Code:    http.StatusConflict,
+			Message: "合并存在冲突，未保存",
+			Data:    result,
+		})
+		return
+	}
+	if result.Applied {
+		c.Header("ETag", semanticMapETag(result.Revision))
+	}
+	Success(c, result)
+}
+
+// semanticContentError 版本不存在返回 404，其他错误按语义元素错误处理
+func semanticContentError(c *gin.Context, err error, prefix string) {
+	if errors.Is(err, service.ErrSemanticMapVersionNotFound) {
+		NotFound(c, prefix+err.Error())
+		return
+	}
+	semanticElementError(c, err, prefix)
+}
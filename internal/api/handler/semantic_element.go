@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/semantic"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ListSemanticElements 查询语义元素列表
+// @Summary 查询语义元素列表
+// @Description 返回语义地图中指定类型的全部元素：pois 兴趣点、regions 区域、waypoints 路网节点、edges 路网有向边
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param kind path string true "元素类型" Enums(pois, regions, waypoints, edges)
+// @Param frame query string false "返回坐标所用的坐标系，默认为底图坐标系"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或无法换算到指定坐标系"
+// @Failure 404 {object} Response "语义地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/{kind} [get]
+// @Security BearerAuth
+func (h *SemanticMapHandler) ListSemanticElements(c *gin.Context) {
+	id, kind, ok := parseSemanticElementParams(c)
+	if !ok {
+		return
+	}
+
+	frameName := c.Query("frame")
+	logger.Info("handling list semantic elements request", zap.Uint("id", id), zap.String("kind", string(kind)), zap.String("frame", frameName))
+
+	elements, err := h.semanticService.ListSemanticElements(c.Request.Context(), id, kind, frameName)
+	if err != nil {
+		logger.Error("failed to list semantic elements", zap.Error(err), zap.Uint("id", id))
+		semanticElementError(c, err, "查询语义元素失败: ")
+		return
+	}
+	if elements == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	Success(c, elements)
+}
+
+// GetSemanticElement 获取语义元素
+// @Summary 获取语义元素
+// @Description 根据元素ID获取语义地图中的单个元素
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param kind path string true "元素类型" Enums(pois, regions, waypoints, edges)
+// @Param elementId path string true "元素ID"
+// @Param frame query string false "返回坐标所用的坐标系，默认为底图坐标系"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或无法换算到指定坐标系"
+// @Failure 404 {object} Response "语义地图或元素不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/{kind}/{elementId} [get]
+// @Security BearerAuth
+func (h *SemanticMapHandler) GetSemanticElement(c *gin.Context) {
+	id, kind, ok := parseSemanticElementParams(c)
+	if !ok {
+		return
+	}
+	elementID := c.Param("elementId")
+	frameName := c.Query("frame")
+
+	logger.Info("handling get semantic element request", zap.Uint("id", id), zap.String("kind", string(kind)), zap.String("elementID", elementID), zap.String("frame", frameName))
+
+	element, err := h.semanticService.GetSemanticElement(c.Request.Context(), id, kind, elementID, frameName)
+	if err != nil {
+		logger.Error("failed to get semantic element", zap.Error(err), zap.Uint("id", id))
+		semanticElementError(c, err, "获取语义元素失败: ")
+		return
+	}
+	if element == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	Success(c, element)
+}
+
+// CreateSemanticElement 新增语义元素
+// @Summary 新增语义元素
+// @Description 向语义地图新增单个元素，元素ID由请求体指定且在同类元素中唯一；校验通过后生成新版本
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param kind path string true "元素类型" Enums(pois, regions, waypoints, edges)
+// @Param If-Match header string false "获取语义地图时响应头中的 ETag，携带时校验修订号"
+// @Param frame query string false "请求与响应中坐标所用的坐标系，默认为底图坐标系"
+// @Param request body object true "元素内容，结构见 semantic.POI / semantic.Region / semantic.Waypoint / semantic.Edge"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或校验失败"
+// @Failure 404 {object} Response "语义地图不存在"
+// @Failure 409 {object} Response "修订号已过期"
+// @Failure 423 {object} Response "编辑锁由他人持有"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/{kind} [post]
+// @Security BearerAuth
+func (h *SemanticMapHandler) CreateSemanticElement(c *gin.Context) {
+	h.saveSemanticElement(c, "")
+}
+
+// UpdateSemanticElement 替换语义元素
+// @Summary 替换语义元素
+// @Description 整体替换语义地图中的单个元素，元素ID以路径为准；校验通过后生成新版本
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param kind path string true "元素类型" Enums(pois, regions, waypoints, edges)
+// @Param elementId path string true "元素ID"
+// @Param If-Match header string false "获取语义地图时响应头中的 ETag，携带时校验修订号"
+// @Param frame query string false "请求与响应中坐标所用的坐标系，默认为底图坐标系"
+// @Param request body object true "元素内容，结构见 semantic.POI / semantic.Region / semantic.Waypoint / semantic.Edge"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或校验失败"
+// @Failure 404 {object} Response "语义地图或元素不存在"
+// @Failure 409 {object} Response "修订号已过期"
+// @Failure 423 {object} Response "编辑锁由他人持有"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/{kind}/{elementId} [put]
+// @Security BearerAuth
+func (h *SemanticMapHandler) UpdateSemanticElement(c *gin.Context) {
+	h.saveSemanticElement(c, c.Param("elementId"))
+}
+
+// saveSemanticElement 新增（elementID 为空）或替换语义元素
+func (h *SemanticMapHandler) saveSemanticElement(c *gin.Context, elementID string) {
+	id, kind, ok := parseSemanticElementParams(c)
+	if !ok {
+		return
+	}
+
+	data, err := c.GetRawData()
+	if err != nil || len(data) == 0 {
+		logger.Error("invalid request body", zap.Error(err))
+		BadRequest(c, "无效的请求参数")
+		return
+	}
+
+	ifMatch, ok := parseIfMatch(c, false)
+	if !ok {
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	frameName := c.Query("frame")
+	logger.Info("handling save semantic element request", zap.Uint("id", id), zap.String("kind", string(kind)), zap.String("elementID", elementID), zap.String("frame", frameName))
+
+	element, err := h.semanticService.SaveSemanticElement(c.Request.Context(), id, kind, elementID, data, userName, ifMatch, frameName)
+	if err != nil {
+		logger.Error("failed to save semantic element", zap.Error(err), zap.Uint("id", id))
+		h.semanticEditError(c, id, err, "保存语义元素失败: ")
+		return
+	}
+	if element == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	Success(c, element)
+}
+
+// DeleteSemanticElement 删除语义元素
+// @Summary 删除语义元素
+// @Description 删除语义地图中的单个元素并生成新版本，删除路网节点时一并删除与其相连的边
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param kind path string true "元素类型" Enums(pois, regions, waypoints, edges)
+// @Param elementId path string true "元素ID"
+// @Param If-Match header string false "获取语义地图时响应头中的 ETag，携带时校验修订号"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "语义地图或元素不存在"
+// @Failure 409 {object} Response "修订号已过期"
+// @Failure 423 {object} Response "编辑锁由他人持有"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/{kind}/{elementId} [delete]
+// @Security BearerAuth
+func (h *SemanticMapHandler) DeleteSemanticElement(c *gin.Context) {
+	id, kind, ok := parseSemanticElementParams(c)
+	if !ok {
+		return
+	}
+	elementID := c.Param("elementId")
+
+	ifMatch, ok := parseIfMatch(c, false)
+	if !ok {
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling delete semantic element request", zap.Uint("id", id), zap.String("kind", string(kind)), zap.String("elementID", elementID))
+
+	if err := h.semanticService.DeleteSemanticElement(c.Request.Context(), id, kind, elementID, userName, ifMatch); err != nil {
+		logger.Error("failed to delete semantic element", zap.Error(err), zap.Uint("id", id))
+		h.semanticEditError(c, id, err, "删除语义元素失败: ")
+		return
+	}
+
+	Success(c, gin.H{"message": "删除成功"})
+}
+
+// parseSemanticElementParams 解析路径中的语义地图ID与元素类型，解析失败时直接返回错误响应
+func parseSemanticElementParams(c *gin.Context) (uint, semantic.Kind, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid semantic map id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的语义地图ID")
+		return 0, "", false
+	}
+
+	kind, err := semantic.ParseKind(c.Param("kind"))
+	if err != nil {
+		logger.Warn("unknown semantic element kind", zap.String("kind", c.Param("kind")))
+		NotFound(c, "未知的语义元素类型")
+		return 0, "", false
+	}
+	return uint(id), kind, true
+}
+
+// semanticElementError 校验失败返回 400，元素不存在返回 404，其他错误返回 500
+func semanticElementError(c *gin.Context, err error, prefix string) {
+	switch {
+	case errors.Is(err, semantic.ErrInvalidMap), errors.Is(err, semantic.ErrElementExists), errors.Is(err, service.ErrSemanticBaseLayer),
+		errors.Is(err, frame.ErrNoTransform), errors.Is(err, service.ErrInvalidLocation):
+		BadRequest(c, prefix+err.Error())
+	case errors.Is(err, semantic.ErrElementNotFound):
+		NotFound(c, prefix+err.Error())
+	default:
+		InternalServerError(c, prefix+err.Error())
+	}
+}
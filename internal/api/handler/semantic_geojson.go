@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"robot_scheduler/internal/api/middleware"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ExportSemanticGeoJSON 导出语义地图为 GeoJSON
+// @Summary 导出语义地图为 GeoJSON
+// @Description 将兴趣点(Point)、区域(Polygon)、路网节点(Point)与路网边(LineString)导出为 GeoJSON FeatureCollection，要素 properties.kind 标明类型。crs=wgs84 时按配置的 geo_reference 换算为经纬度，朝向始终为地图坐标系下的弧度
+// @Tags 语义地图
+// @Produce application/geo+json
+// @Param id path int true "语义地图ID"
+// @Param crs query string false "坐标参考(local/wgs84)，默认 local"
+// @Success 200 {file} file "GeoJSON 文件"
+// @Failure 400 {object} Response "参数错误、语义信息不符合结构定义或未配置经纬度换算"
+// @Failure 404 {object} Response "语义地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/geojson [get]
+// @Security BearerAuth
+func (h *SemanticMapHandler) ExportSemanticGeoJSON(c *gin.Context) {
+	id, ok := parseSemanticMapID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SemanticGeoJSONExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid export parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	logger.Info("handling export semantic geojson request", zap.Uint("id", id), zap.String("crs", req.CRS))
+
+	fc, err := h.semanticService.ExportSemanticGeoJSON(c.Request.Context(), id, req.CRS)
+	if err != nil {
+		logger.Error("failed to export semantic geojson", zap.Error(err), zap.Uint("id", id))
+		semanticGeoJSONError(c, err, "导出 GeoJSON 失败: ")
+		return
+	}
+	if fc == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	data, err := json.Marshal(fc)
+	if err != nil {
+		InternalServerError(c, "导出 GeoJSON 失败: "+err.Error())
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="semantic_map_%d.geojson"`, id))
+	c.Data(http.StatusOK, "application/geo+json", data)
+}
+
+// ImportSemanticGeoJSON 由 GeoJSON 新建语义地图
+// @Summary 由 GeoJSON 新建语义地图
+// @Description 请求体为 GeoJSON FeatureCollection。要素按 properties.kind(poi/region/waypoint/edge)区分类型，缺省时 Point 视为兴趣点、Polygon 视为区域、LineString 视为路网边，边缺少 from/to 时按端点匹配路网节点。存在无法转换的要素时不保存并返回 422 及校验报告，否则报告中附带一致性检查结果
+// @Tags 语义地图
+// @Accept application/geo+json
+// @Produce json
+// @Param pcdFileId query int false "对应的pcd地图文件id，与 occupancyGridId 二选一"
+// @Param occupancyGridId query int false "对应的二维栅格地图id，与 pcdFileId 二选一"
+// @Param crs query string false "坐标参考(local/wgs84)，默认 local"
+// @Param message query string false "版本说明"
+// @Param dryRun query bool false "只校验不保存"
+// @Param request body semantic.FeatureCollection true "GeoJSON 要素集合"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误、文档不是要素集合或未配置经纬度换算"
+// @Failure 404 {object} Response "底图不存在"
+// @Failure 422 {object} Response "要素转换失败，未保存"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/geojson [post]
+// @Security BearerAuth
+func (h *SemanticMapHandler) ImportSemanticGeoJSON(c *gin.Context) {
+	var req dto.SemanticGeoJSONImportRequest
+	if err := c.ShouldBindQuery(&req); err != nil || (req.PCDFileID == 0) == (req.OccupancyGridID == 0) {
+		logger.Error("invalid import parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数，pcdFileId 与 occupancyGridId 必须且只能指定一个")
+		return
+	}
+	data, ok := readGeoJSONBody(c)
+	if !ok {
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling import semantic geojson request", zap.Uint("pcdFileID", req.PCDFileID), zap.Uint("occupancyGridID", req.OccupancyGridID), zap.Bool("dryRun", req.DryRun))
+
+	resp, err := h.semanticService.ImportSemanticGeoJSON(c.Request.Context(), data, &req, userName)
+	if err != nil {
+		logger.Error("failed to import semantic geojson", zap.Error(err))
+		semanticGeoJSONError(c, err, "导入 GeoJSON 失败: ")
+		return
+	}
+	if resp == nil {
+		NotFound(c, "底图不存在")
+		return
+	}
+
+	geoJSONImportResult(c, resp)
+}
+
+// ReplaceSemanticGeoJSON 以 GeoJSON 整体替换语义地图
+// @Summary 以 GeoJSON 整体替换语义地图
+// @Description 以 GeoJSON FeatureCollection 转换得到的内容替换语义地图并生成新版本，要素约定同导入接口。存在无法转换的要素时不保存并返回 422 及校验报告；dryRun 时只校验，不要求 If-Match
+// @Tags 语义地图
+// @Accept application/geo+json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param If-Match header string true "获取语义地图时响应头中的 ETag"
+// @Param crs query string false "坐标参考(local/wgs84)，默认 local"
+// @Param message query string false "版本说明"
+// @Param dryRun query bool false "只校验不保存"
+// @Param request body semantic.FeatureCollection true "GeoJSON 要素集合"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误、文档不是要素集合或未配置经纬度换算"
+// @Failure 404 {object} Response "语义地图不存在"
+// @Failure 409 {object} Response "修订号已过期"
+// @Failure 422 {object} Response "要素转换失败，未保存"
+// @Failure 423 {object} Response "编辑锁由他人持有"
+// @Failure 428 {object} Response "缺少 If-Match 请求头"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/geojson [put]
+// @Security BearerAuth
+func (h *SemanticMapHandler) ReplaceSemanticGeoJSON(c *gin.Context) {
+	id, ok := parseSemanticMapID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SemanticGeoJSONImportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid import parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+	ifMatch, ok := parseIfMatch(c, !req.DryRun)
+	if !ok {
+		return
+	}
+	data, ok := readGeoJSONBody(c)
+	if !ok {
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling replace semantic geojson request", zap.Uint("id", id), zap.Bool("dryRun", req.DryRun))
+
+	resp, err := h.semanticService.ReplaceSemanticGeoJSON(c.Request.Context(), id, data, &req, userName, ifMatch)
+	if err != nil {
+		logger.Error("failed to replace semantic geojson", zap.Error(err), zap.Uint("id", id))
+		if errors.Is(err, service.ErrGeoReferenceNotConfigured) {
+			semanticGeoJSONError(c, err, "导入 GeoJSON 失败: ")
+			return
+		}
+		h.semanticEditError(c, id, err, "导入 GeoJSON 失败: ")
+		return
+	}
+	if resp == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	if resp.Imported {
+		c.Header("ETag", semanticMapETag(resp.SemanticMap.Revision))
+	}
+	geoJSONImportResult(c, resp)
+}
+
+func readGeoJSONBody(c *gin.Context) ([]byte, bool) {
+	data, err := c.GetRawData()
+	if err != nil || len(data) == 0 {
+		logger.Error("invalid request body", zap.Error(err))
+		BadRequest(c, "请求体需要为 GeoJSON FeatureCollection")
+		return nil, false
+	}
+	return data, true
+}
+
+// geoJSONImportResult 要素转换出错时返回 422 并附带报告
+func geoJSONImportResult(c *gin.Context, resp *dto.SemanticGeoJSONImportResponse) {
+	if !resp.Valid {
+		c.JSON(http.StatusOK, Response{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "GeoJSON 校验未通过，未保存",
+			Data:    resp,
+		})
+		return
+	}
+	Success(c, resp)
+}
+
+// semanticGeoJSONError 未配置经纬度换算返回 400，其他错误按语义内容错误处理
+func semanticGeoJSONError(c *gin.Context, err error, prefix string) {
+	if errors.Is(err, service.ErrGeoReferenceNotConfigured) {
+		BadRequest(c, prefix+"未配置地图坐标系到 WGS84 的换算参数(geo_reference)")
+		return
+	}
+	semanticContentError(c, err, prefix)
+}
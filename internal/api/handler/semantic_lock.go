@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"robot_scheduler/internal/api/middleware"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// LockSemanticMap 获取语义地图编辑锁
+// @Summary 获取语义地图编辑锁
+// @Description 声明正在编辑语义地图，有效期内其他用户的修改会被拒绝(423)；本人重复获取即续期，过期后自动释放
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param request body dto.SemanticMapLockRequest false "锁有效期"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "语义地图不存在"
+// @Failure 423 {object} Response "编辑锁由他人持有"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/lock [post]
+// @Security BearerAuth
+func (h *SemanticMapHandler) LockSemanticMap(c *gin.Context) {
+	id, ok := parseSemanticMapID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SemanticMapLockRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error("invalid request parameters", zap.Error(err))
+			BadRequest(c, "无效的请求参数: "+err.Error())
+			return
+		}
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling lock semantic map request", zap.Uint("id", id), zap.String("userName", userName))
+
+	lock, err := h.semanticService.LockSemanticMap(c.Request.Context(), id, userName, time.Duration(req.TTL)*time.Second)
+	if err != nil {
+		logger.Error("failed to lock semantic map", zap.Error(err), zap.Uint("id", id))
+		h.semanticEditError(c, id, err, "获取编辑锁失败: ")
+		return
+	}
+	if lock == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	c.Header("ETag", semanticMapETag(lock.Revision))
+	Success(c, lock)
+}
+
+// UnlockSemanticMap 释放语义地图编辑锁
+// @Summary 释放语义地图编辑锁
+// @Description 释放本人持有的编辑锁，锁已过期或未加锁时直接成功
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 423 {object} Response "编辑锁由他人持有"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/lock [delete]
+// @Security BearerAuth
+func (h *SemanticMapHandler) UnlockSemanticMap(c *gin.Context) {
+	id, ok := parseSemanticMapID(c)
+	if !ok {
+		return
+	}
+
+	userNameValue, _ := c.Get(string(middleware.UserNameKey))
+	userName, _ := userNameValue.(string)
+
+	logger.Info("handling unlock semantic map request", zap.Uint("id", id), zap.String("userName", userName))
+
+	if err := h.semanticService.UnlockSemanticMap(c.Request.Context(), id, userName); err != nil {
+		logger.Error("failed to unlock semantic map", zap.Error(err), zap.Uint("id", id))
+		h.semanticEditError(c, id, err, "释放编辑锁失败: ")
+		return
+	}
+
+	Success(c, gin.H{"message": "释放成功"})
+}
+
+// GetSemanticMapLock 查询语义地图编辑锁
+// @Summary 查询语义地图编辑锁
+// @Description 返回编辑锁持有人与过期时间，已过期的锁视为未加锁
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 404 {object} Response "语义地图不存在"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/lock [get]
+// @Security BearerAuth
+func (h *SemanticMapHandler) GetSemanticMapLock(c *gin.Context) {
+	id, ok := parseSemanticMapID(c)
+	if !ok {
+		return
+	}
+
+	lock, err := h.semanticService.GetSemanticMapLock(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("failed to get semantic map lock", zap.Error(err), zap.Uint("id", id))
+		InternalServerError(c, "查询编辑锁失败: "+err.Error())
+		return
+	}
+	if lock == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	c.Header("ETag", semanticMapETag(lock.Revision))
+	Success(c, lock)
+}
+
+// semanticMapETag 由修订号生成强 ETag
+func semanticMapETag(revision int) string {
+	return `"` + strconv.Itoa(revision) + `"`
+}
+
+// parseIfMatch 解析 If-Match 请求头中的修订号，不接受 "*" 等不带修订号的取值。
+// required 为 true 时缺少该请求头返回 428，解析失败时直接返回错误响应
+func parseIfMatch(c *gin.Context, required bool) (*int, bool) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" {
+		if required {
+			Error(c, http.StatusPreconditionRequired, "缺少 If-Match 请求头，请先获取语义地图并回传响应头中的 ETag")
+			return nil, false
+		}
+		return nil, true
+	}
+	tag := strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	revision, err := strconv.Atoi(tag)
+	if err != nil {
+		logger.Warn("invalid If-Match header", zap.String("ifMatch", value))
+		BadRequest(c, "无效的 If-Match 请求头")
+		return nil, false
+	}
+	return &revision, true
+}
+
+// semanticEditError 修订号冲突返回 409 并附带当前修订号，编辑锁被他人持有返回 423，其他错误按语义内容错误处理
+func (h *SemanticMapHandler) semanticEditError(c *gin.Context, id uint, err error, prefix string) {
+	switch {
+	case errors.Is(err, dao.ErrSemanticMapConflict):
+		current, findErr := h.semanticService.GetSemanticMapByID(c.Request.Context(), id)
+		if findErr != nil || current == nil {
+			Error(c, http.StatusConflict, prefix+"语义地图已被他人修改，请刷新后重试")
+			return
+		}
+		c.Header("ETag", semanticMapETag(current.Revision))
+		c.JSON(http.StatusOK, Response{
+			Code:    http.StatusConflict,
+			Message: prefix + "语义地图已被他人修改，请刷新后重试",
+			Data:    gin.H{"revision": current.Revision, "semanticMap": current},
+		})
+	case errors.Is(err, dao.ErrSemanticMapLocked):
+		Error(c, http.StatusLocked, prefix+err.Error())
+	default:
+		semanticContentError(c, err, prefix)
+	}
+}
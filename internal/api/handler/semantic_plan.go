@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/semantic"
+	"robot_scheduler/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PlanSemanticPath 路径规划
+// @Summary 路径规划
+// @Description 在语义地图的路网有向图上规划从起点到终点的代价最小路径，避开禁行区、逆行单行区及设备类型受限的边与区域，返回路网节点序列、总代价与预计耗时
+// @Tags 语义地图
+// @Accept json
+// @Produce json
+// @Param id path int true "语义地图ID"
+// @Param request body dto.SemanticPlanRequest true "规划参数"
+// @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误、起终点不存在或无法换算到指定坐标系"
+// @Failure 404 {object} Response "语义地图或版本不存在"
+// @Failure 422 {object} Response "没有可通行的路径"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /maps/semantic-maps/{id}/plan [post]
+// @Security BearerAuth
+func (h *SemanticMapHandler) PlanSemanticPath(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Error("invalid semantic map id", zap.String("id", idStr), zap.Error(err))
+		BadRequest(c, "无效的语义地图ID")
+		return
+	}
+
+	var req dto.SemanticPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("invalid request parameters", zap.Error(err))
+		BadRequest(c, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	logger.Info("handling plan semantic path request", zap.Uint("id", uint(id)), zap.String("from", req.From), zap.String("to", req.To))
+
+	plan, err := h.semanticService.PlanPath(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		logger.Error("failed to plan path", zap.Error(err), zap.Uint("id", uint(id)))
+		switch {
+		case errors.Is(err, semantic.ErrUnknownNode), errors.Is(err, semantic.ErrInvalidMap), errors.Is(err, frame.ErrNoTransform):
+			BadRequest(c, "路径规划失败: "+err.Error())
+		case errors.Is(err, service.ErrSemanticMapVersionNotFound):
+			NotFound(c, "路径规划失败: "+err.Error())
+		case errors.Is(err, semantic.ErrNoPath):
+			Error(c, http.StatusUnprocessableEntity, "路径规划失败: "+err.Error())
+		default:
+			InternalServerError(c, "路径规划失败: "+err.Error())
+		}
+		return
+	}
+	if plan == nil {
+		NotFound(c, "语义地图不存在")
+		return
+	}
+
+	Success(c, plan)
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// StorageHandler 本地存储签名链接处理器，代替 MinIO 的预签名链接供客户端直接上传下载
+//
+// 客户端按 HTTP 状态码判断上传下载是否成功，因此错误时返回真实状态码而非统一的 200
+type StorageHandler struct {
+	local *storage.Local
+}
+
+func NewStorageHandler(local *storage.Local) *StorageHandler {
+	return &StorageHandler{
+		local: local,
+	}
+}
+
+// GetObject 通过签名链接下载对象
+// @Summary 通过签名链接下载对象
+// @Description 本地存储后端签发的下载链接，由签名授权，不需要登录。支持 Range 断点续传
+// @Tags 对象存储
+// @Produce application/octet-stream
+// @Param key path string true "对象Key"
+// @Param expires query int true "过期时间(Unix秒)"
+// @Param signature query string true "签名"
+// @Param response-content-disposition query string false "覆盖 Content-Disposition 响应头"
+// @Param response-content-type query string false "覆盖 Content-Type 响应头"
+// @Success 200 {file} file "对象内容"
+// @Success 206 {file} file "部分内容"
+// @Failure 403 {object} Response "签名无效或已过期"
+// @Failure 404 {object} Response "对象不存在"
+// @Router /storage/objects/{key} [get]
+func (h *StorageHandler) GetObject(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	query := c.Request.URL.Query()
+	if err := h.local.Verify(http.MethodGet, key, query); err != nil {
+		logger.Warn("rejected storage download", zap.Error(err), zap.String("key", key))
+		storageError(c, http.StatusForbidden, "签名无效或已过期")
+		return
+	}
+
+	object, info, err := h.local.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			storageError(c, http.StatusNotFound, "对象不存在")
+			return
+		}
+		logger.Error("failed to open storage object", zap.Error(err), zap.String("key", key))
+		storageError(c, http.StatusInternalServerError, "读取对象失败: "+err.Error())
+		return
+	}
+	defer object.Close()
+
+	contentType := info.ContentType
+	if v := query.Get("response-content-type"); v != "" {
+		contentType = v
+	}
+	c.Header("Content-Type", contentType)
+	if v := query.Get("response-content-disposition"); v != "" {
+		c.Header("Content-Disposition", v)
+	}
+	c.Header("ETag", `"`+info.ETag+`"`)
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.LastModified, object)
+}
+
+// PutObject 通过签名链接上传对象或分片
+// @Summary 通过签名链接上传对象或分片
+// @Description 本地存储后端签发的上传链接，由签名授权，不需要登录。请求体为文件内容，带 uploadId 与 partNumber 时为分片上传，响应头 ETag 为对象或分片的 ETag
+// @Tags 对象存储
+// @Accept application/octet-stream
+// @Param key path string true "对象Key"
+// @Param expires query int true "过期时间(Unix秒)"
+// @Param signature query string true "签名"
+// @Param uploadId query string false "分片上传ID"
+// @Param partNumber query int false "分片序号"
+// @Param maxSize query int true "允许上传的最大字节数"
+// @Success 200 "上传成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 403 {object} Response "签名无效或已过期"
+// @Failure 404 {object} Response "分片上传不存在"
+// @Failure 413 {object} Response "超过允许上传的大小"
+// @Failure 500 {object} Response "服务器错误"
+// @Router /storage/objects/{key} [put]
+func (h *StorageHandler) PutObject(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	query := c.Request.URL.Query()
+	if err := h.local.Verify(http.MethodPut, key, query); err != nil {
+		logger.Warn("rejected storage upload", zap.Error(err), zap.String("key", key))
+		storageError(c, http.StatusForbidden, "签名无效或已过期")
+		return
+	}
+
+	body, err := h.local.LimitBody(query, c.Request.Body, c.Request.ContentLength)
+	if err != nil {
+		h.putError(c, err, key, "写入对象失败: ")
+		return
+	}
+
+	ctx := c.Request.Context()
+	var etag string
+	if uploadID := query.Get("uploadId"); uploadID != "" {
+		partNumber, err := strconv.Atoi(query.Get("partNumber"))
+		if err != nil {
+			storageError(c, http.StatusBadRequest, "无效的分片序号")
+			return
+		}
+		etag, err = h.local.PutPart(ctx, key, uploadID, partNumber, body, c.Request.ContentLength)
+		if err != nil {
+			if errors.Is(err, storage.ErrNoSuchUpload) {
+				storageError(c, http.StatusNotFound, "分片上传不存在")
+				return
+			}
+			h.putError(c, err, key, "写入分片失败: ")
+			return
+		}
+	} else {
+		if err := h.local.Put(ctx, key, body, c.Request.ContentLength, c.ContentType()); err != nil {
+			h.putError(c, err, key, "写入对象失败: ")
+			return
+		}
+		info, err := h.local.Stat(ctx, key)
+		if err != nil {
+			storageError(c, http.StatusInternalServerError, "写入对象失败: "+err.Error())
+			return
+		}
+		etag = info.ETag
+	}
+
+	logger.Debug("storage object written", zap.String("key", key))
+	c.Header("ETag", `"`+etag+`"`)
+	c.Status(http.StatusOK)
+}
+
+// putError 签名缺少大小限制返回 403，超过大小限制返回 413，其他错误返回 500
+func (h *StorageHandler) putError(c *gin.Context, err error, key, prefix string) {
+	switch {
+	case errors.Is(err, storage.ErrInvalidSignature):
+		logger.Warn("rejected storage upload", zap.Error(err), zap.String("key", key))
+		storageError(c, http.StatusForbidden, "签名无效或已过期")
+	case errors.Is(err, storage.ErrTooLarge):
+		logger.Warn("storage upload too large", zap.Error(err), zap.String("key", key))
+		storageError(c, http.StatusRequestEntityTooLarge, prefix+"超过允许上传的大小")
+	default:
+		logger.Error("failed to write storage object", zap.Error(err), zap.String("key", key))
+		storageError(c, http.StatusInternalServerError, prefix+err.Error())
+	}
+}
+
+// storageError 以真实 HTTP 状态码返回错误
+func storageError(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, Response{
+		Code:    status,
+		Message: message,
+	})
+}
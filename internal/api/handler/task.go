@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
+	"robot_scheduler/internal/frame"
 	"robot_scheduler/internal/logger"
 	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/semantic"
 	"robot_scheduler/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -56,13 +59,14 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 
 // GetTask 获取任务
 // @Summary 获取任务
-// @Description 根据ID获取任务信息
+// @Description 根据ID获取任务信息，指定 frame 时返回任务固定版本的语义地图内容，坐标换算到该坐标系
 // @Tags 任务管理
 // @Accept json
 // @Produce json
 // @Param id path int true "任务ID"
+// @Param frame query string false "返回坐标所用的坐标系，默认为语义地图底图坐标系"
 // @Success 200 {object} Response "成功"
-// @Failure 400 {object} Response "参数错误"
+// @Failure 400 {object} Response "参数错误或无法换算到指定坐标系"
 // @Failure 404 {object} Response "任务不存在"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /tasks/{id} [get]
@@ -76,11 +80,16 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
-	logger.Info("handling get task request", zap.Uint("id", uint(id)))
+	frameName := c.Query("frame")
+	logger.Info("handling get task request", zap.Uint("id", uint(id)), zap.String("frame", frameName))
 
-	task, err := h.taskService.GetTaskByID(c.Request.Context(), uint(id))
+	task, err := h.taskService.GetTaskByID(c.Request.Context(), uint(id), frameName)
 	if err != nil {
 		logger.Error("failed to get task", zap.Error(err), zap.Uint("id", uint(id)))
+		if errors.Is(err, frame.ErrNoTransform) || errors.Is(err, semantic.ErrInvalidMap) {
+			BadRequest(c, "获取任务失败: "+err.Error())
+			return
+		}
 		InternalServerError(c, "获取任务失败: "+err.Error())
 		return
 	}
@@ -169,27 +178,37 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 
 // ListTasks 查询任务列表
 // @Summary 查询任务列表
-// @Description 查询所有任务
+// @Description 查询所有任务，指定 locationId 时只返回所用语义地图位于该位置节点及其下级节点的任务；
+// @Description 指定 frame 时返回各任务固定版本的语义地图内容，坐标换算到该坐标系
 // @Tags 任务管理
 // @Accept json
 // @Produce json
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页大小"
+// @Param locationId query int false "位置节点ID"
+// @Param frame query string false "返回坐标所用的坐标系，默认为各语义地图底图坐标系"
 // @Success 200 {object} Response "成功"
+// @Failure 400 {object} Response "参数错误或无法换算到指定坐标系"
 // @Failure 500 {object} Response "服务器错误"
 // @Router /tasks [get]
 // @Security BearerAuth
 func (h *TaskHandler) ListTasks(c *gin.Context) {
 	logger.Info("handling list tasks request")
 
-	var pageReq dto.PageRequest
-	if err := c.ShouldBindQuery(&pageReq); err != nil {
-		logger.Error("invalid pagination parameters", zap.Error(err))
-		BadRequest(c, "无效的分页参数: "+err.Error())
+	var req dto.TaskListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("invalid query parameters", zap.Error(err))
+		BadRequest(c, "无效的查询参数: "+err.Error())
 		return
 	}
 
-	tasks, err := h.taskService.ListTasks(c.Request.Context(), pageReq)
+	tasks, err := h.taskService.ListTasks(c.Request.Context(), req)
 	if err != nil {
 		logger.Error("failed to list tasks", zap.Error(err))
+		if errors.Is(err, frame.ErrNoTransform) || errors.Is(err, semantic.ErrInvalidMap) {
+			BadRequest(c, "查询任务列表失败: "+err.Error())
+			return
+		}
 		InternalServerError(c, "查询任务列表失败: "+err.Error())
 		return
 	}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -22,6 +23,8 @@ const (
 	UserNameKey ContextKey = "user_name"
 	// UserRoleKey 用户角色上下文键
 	UserRoleKey ContextKey = "user_role"
+	// DeviceIDKey 设备ID上下文键（设备凭证认证）
+	DeviceIDKey ContextKey = "device_id"
 )
 
 // Recovery 恢复中间件
@@ -69,7 +72,8 @@ func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -170,3 +174,37 @@ func RequirePermission(permissions ...string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// DeviceAuthenticator 设备凭证校验函数，返回凭证所属设备ID
+type DeviceAuthenticator func(ctx context.Context, token string) (uint, error)
+
+// DeviceAuth 设备凭证认证中间件
+// 设备使用注册时签发的凭证访问，格式：Authorization: Device <token>
+func DeviceAuth(authenticate DeviceAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Device" || parts[1] == "" {
+			logger.Warn("invalid device authorization header", zap.String("path", c.Request.URL.Path))
+			c.AbortWithStatusJSON(401, gin.H{
+				"code":    401,
+				"message": "缺少或无效的设备凭证",
+			})
+			return
+		}
+
+		deviceID, err := authenticate(c.Request.Context(), parts[1])
+		if err != nil {
+			logger.Warn("invalid device token", zap.Error(err), zap.String("path", c.Request.URL.Path))
+			c.AbortWithStatusJSON(401, gin.H{
+				"code":    401,
+				"message": "设备凭证无效或已吊销",
+			})
+			return
+		}
+
+		c.Set(string(DeviceIDKey), deviceID)
+
+		c.Next()
+	}
+}
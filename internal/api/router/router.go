@@ -1,12 +1,16 @@
 package router
 
 import (
+	"context"
 	"robot_scheduler/internal/api/handler"
 	"robot_scheduler/internal/api/middleware"
 	"robot_scheduler/internal/config"
 	impl "robot_scheduler/internal/dao"
+	dao "robot_scheduler/internal/dao/interfaces"
 	"robot_scheduler/internal/database"
+	"robot_scheduler/internal/discovery"
 	"robot_scheduler/internal/service"
+	"robot_scheduler/internal/storage"
 	"robot_scheduler/internal/utils"
 	"time"
 
@@ -16,7 +20,8 @@ import (
 )
 
 // SetupRouter 设置路由
-func SetupRouter(router *gin.Engine, cfg *config.Config) {
+// ctx 在服务关闭时取消，用于停止后台任务
+func SetupRouter(ctx context.Context, router *gin.Engine, cfg *config.Config) {
 	// 初始化DAO
 	db := database.DB
 
@@ -25,30 +30,87 @@ func SetupRouter(router *gin.Engine, cfg *config.Config) {
 	userService := service.NewUserService(userDAO)
 	userHandler := handler.NewUserHandler(userService, cfg)
 
+	// 操作记录相关
+	operationDAO := impl.NewUserOperationDAO(db)
+	operationService := service.NewUserOperationService(operationDAO)
+	operationHandler := handler.NewUserOperationHandler(operationService)
+
+	// 位置层级相关
+	locationDAO := impl.NewLocationDAO(db)
+	locationService := service.NewLocationService(locationDAO)
+	locationHandler := handler.NewLocationHandler(locationService)
+
 	// 点云地图相关
 	pcdDAO := impl.NewPCDFileDAO(db)
-	pcdService := service.NewPCDFileService(pcdDAO)
-	pcdHandler := handler.NewPCDFileHandler(pcdService)
+	pcdUploadDAO := impl.NewPCDUploadDAO(db)
+	pcdJobDAO := impl.NewPCDJobDAO(db)
+	pcdVersionDAO := impl.NewPCDFileVersionDAO(db)
+	pcdService := service.NewPCDFileService(pcdDAO, pcdUploadDAO, pcdJobDAO, pcdVersionDAO, locationDAO)
+	occupancyGridDAO := impl.NewOccupancyGridDAO(db)
+	pcdJobService := service.NewPCDJobService(pcdDAO, pcdJobDAO, occupancyGridDAO, pcdService)
+	pcdHandler := handler.NewPCDFileHandler(pcdService, pcdJobService, operationService)
+	occupancyGridService := service.NewOccupancyGridService(occupancyGridDAO)
+	occupancyGridHandler := handler.NewOccupancyGridHandler(occupancyGridService, operationService)
+	if storage.Backend() != nil && cfg.Minio != nil && cfg.Minio.UploadSweepInterval > 0 {
+		go pcdService.RunUploadSweeper(ctx, time.Duration(cfg.Minio.UploadSweepInterval)*time.Second)
+	}
+	if storage.Backend() != nil && cfg.Preview != nil && cfg.Preview.Enabled {
+		go pcdJobService.Run(ctx)
+	}
+
+	// 坐标系变换相关
+	frameTransformDAO := impl.NewFrameTransformDAO(db)
+	frameService := service.NewFrameService(frameTransformDAO)
+	frameHandler := handler.NewFrameHandler(frameService)
 
 	// 语义地图相关
 	semanticDAO := impl.NewSemanticMapDAO(db)
-	semanticService := service.NewSemanticMapService(semanticDAO)
+	semanticVersionDAO := impl.NewSemanticMapVersionDAO(db)
+	semanticService := service.NewSemanticMapService(semanticDAO, semanticVersionDAO, pcdDAO, occupancyGridDAO, locationDAO, frameService)
 	semanticHandler := handler.NewSemanticMapHandler(semanticService)
+	if cfg.SemanticCheck != nil && cfg.SemanticCheck.Interval > 0 {
+		go semanticService.RunConsistencyChecks(ctx, time.Duration(cfg.SemanticCheck.Interval)*time.Second)
+	}
 
 	// 任务相关
 	taskDAO := impl.NewTaskDAO(db)
-	taskService := service.NewTaskService(taskDAO)
+	taskService := service.NewTaskService(taskDAO, semanticDAO, semanticVersionDAO, pcdDAO, frameService)
 	taskHandler := handler.NewTaskHandler(taskService)
 
 	// 设备相关
 	deviceDAO := impl.NewDeviceDAO(db)
-	deviceService := service.NewDeviceService(deviceDAO)
+	deviceModelDAO := impl.NewDeviceModelDAO(db)
+	deviceService := service.NewDeviceService(deviceDAO, deviceModelDAO, locationDAO)
 	deviceHandler := handler.NewDeviceHandler(deviceService)
+	deviceModelService := service.NewDeviceModelService(deviceModelDAO, deviceDAO)
+	deviceModelHandler := handler.NewDeviceModelHandler(deviceModelService)
 
-	// 操作记录相关
-	operationDAO := impl.NewUserOperationDAO(db)
-	operationService := service.NewUserOperationService(operationDAO)
-	operationHandler := handler.NewUserOperationHandler(operationService)
+	// 设备告警相关
+	alarmDAO := impl.NewDeviceAlarmDAO(db)
+	alarmService := service.NewDeviceAlarmService(deviceDAO, alarmDAO)
+	alarmHandler := handler.NewDeviceAlarmHandler(alarmService)
+
+	// 设备注册与凭证相关
+	enrollmentDAO := impl.NewDeviceEnrollmentDAO(db)
+	credentialDAO := impl.NewDeviceCredentialDAO(db)
+	telemetryDAO := impl.NewDeviceTelemetryDAO(db)
+	provisionService := service.NewDeviceProvisionService(deviceDAO, enrollmentDAO, credentialDAO, telemetryDAO, alarmService, deviceModelDAO, frameService)
+	provisionHandler := handler.NewDeviceProvisionHandler(provisionService)
+
+	// 局域网设备发现相关
+	discoveryService := newDeviceDiscoveryService(deviceDAO, deviceModelDAO, cfg.Discovery)
+	discoveryHandler := handler.NewDeviceDiscoveryHandler(discoveryService)
+	if cfg.Discovery != nil && cfg.Discovery.Enabled {
+		go discoveryService.Run(ctx)
+	}
+
+	// 地图下发相关
+	mapDeploymentDAO := impl.NewMapDeploymentDAO(db)
+	mapDeploymentService := service.NewMapDeploymentService(mapDeploymentDAO, deviceDAO, deviceModelDAO, semanticDAO, semanticVersionDAO, pcdDAO, pcdVersionDAO, occupancyGridDAO)
+	mapDeploymentHandler := handler.NewMapDeploymentHandler(mapDeploymentService)
+	if storage.Backend() != nil {
+		go mapDeploymentService.Run(ctx)
+	}
 
 	// Swagger 文档
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -63,6 +125,14 @@ func SetupRouter(router *gin.Engine, cfg *config.Config) {
 		})
 	})
 
+	// 本地存储的签名链接（签名即授权，不使用JWT）
+	if local, ok := storage.Backend().(*storage.Local); ok {
+		storageHandler := handler.NewStorageHandler(local)
+		router.GET(storage.LocalRoutePath+"/*key", storageHandler.GetObject)
+		router.HEAD(storage.LocalRoutePath+"/*key", storageHandler.GetObject)
+		router.PUT(storage.LocalRoutePath+"/*key", storageHandler.PutObject)
+	}
+
 	// API路由组
 	api := router.Group("/api/v1")
 	{
@@ -78,6 +148,21 @@ func SetupRouter(router *gin.Engine, cfg *config.Config) {
 			auth.POST("/login", userHandler.Login)
 		}
 
+		// 设备接入路由（使用设备凭证，不使用用户JWT）
+		deviceAgent := api.Group("/device-agent")
+		{
+			// 注册接口仅凭一次性注册码，无需认证
+			deviceAgent.POST("/enroll", provisionHandler.Enroll)
+
+			deviceAuthed := deviceAgent.Group("")
+			deviceAuthed.Use(middleware.DeviceAuth(provisionService.AuthenticateDevice))
+			{
+				deviceAuthed.POST("/heartbeat", provisionHandler.Heartbeat)
+				deviceAuthed.POST("/telemetry", provisionHandler.ReportTelemetry)
+				deviceAuthed.POST("/alarms", alarmHandler.ReportAlarms)
+			}
+		}
+
 		// 需要JWT认证的路由组
 		authenticated := api.Group("")
 		authenticated.Use(middleware.JWTAuth(authConfig.JWTSecret))
@@ -108,27 +193,102 @@ func SetupRouter(router *gin.Engine, cfg *config.Config) {
 				{
 					// 上传凭证与创建/编辑/删除需要地图管理权限
 					pcds.POST("/upload-token", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.GetPCDUploadToken)
+					pcds.POST("/complete-upload", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.CompletePCDUpload)
+					pcds.GET("/reconcile", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.ReconcilePCDStorage)
+					pcds.POST("/multipart", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.InitPCDMultipartUpload)
+					pcds.POST("/multipart/part-urls", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.GetPCDPartUploadURLs)
+					pcds.GET("/multipart/parts", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.ListPCDUploadedParts)
+					pcds.POST("/multipart/complete", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.CompletePCDMultipartUpload)
+					pcds.POST("/multipart/abort", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.AbortPCDMultipartUpload)
 					pcds.POST("", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.CreatePCDFile)
 					pcds.PUT("/:id", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.UpdatePCDFile)
+					pcds.POST("/:id/analyze", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.AnalyzePCDFile)
+					pcds.POST("/:id/preview", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.GeneratePCDPreview)
+					pcds.POST("/:id/verify", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.VerifyPCDFile)
+					pcds.POST("/:id/occupancy-grids", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.GeneratePCDOccupancyGrid)
+					pcds.POST("/:id/versions/:version/rollback", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.RollbackPCDFile)
 					pcds.DELETE("/:id", middleware.RequirePermission(utils.PermissionMapManage), pcdHandler.DeletePCDFile)
 					// 查看需要地图查看权限
 					pcds.GET("/:id", middleware.RequirePermission(utils.PermissionMapView), pcdHandler.GetPCDFile)
+					pcds.GET("/:id/download-url", middleware.RequirePermission(utils.PermissionMapView), pcdHandler.GetPCDDownloadURL)
+					pcds.GET("/:id/download", middleware.RequirePermission(utils.PermissionMapView), pcdHandler.DownloadPCDFile)
+					pcds.GET("/:id/jobs", middleware.RequirePermission(utils.PermissionMapView), pcdHandler.ListPCDJobs)
+					pcds.GET("/:id/occupancy-grids", middleware.RequirePermission(utils.PermissionMapView), occupancyGridHandler.ListPCDOccupancyGrids)
+					pcds.GET("/:id/versions", middleware.RequirePermission(utils.PermissionMapView), pcdHandler.ListPCDFileVersions)
+					pcds.GET("/:id/versions/:version", middleware.RequirePermission(utils.PermissionMapView), pcdHandler.GetPCDFileVersion)
 					pcds.GET("", middleware.RequirePermission(utils.PermissionMapView), pcdHandler.ListPCDFiles)
 				}
 
+				// 二维占据栅格地图管理
+				grids := maps.Group("/occupancy-grids")
+				{
+					grids.POST("/import", middleware.RequirePermission(utils.PermissionMapManage), occupancyGridHandler.ImportOccupancyGrid)
+					grids.DELETE("/:id", middleware.RequirePermission(utils.PermissionMapManage), occupancyGridHandler.DeleteOccupancyGrid)
+					grids.GET("/:id", middleware.RequirePermission(utils.PermissionMapView), occupancyGridHandler.GetOccupancyGrid)
+					grids.GET("/:id/download", middleware.RequirePermission(utils.PermissionMapView), occupancyGridHandler.DownloadOccupancyGrid)
+					grids.GET("", middleware.RequirePermission(utils.PermissionMapView), occupancyGridHandler.ListOccupancyGrids)
+				}
+
+				// 坐标系与坐标系变换管理
+				frames := maps.Group("/frames")
+				{
+					frames.POST("/transforms", middleware.RequirePermission(utils.PermissionMapManage), frameHandler.CreateFrameTransform)
+					frames.PUT("/transforms/:id", middleware.RequirePermission(utils.PermissionMapManage), frameHandler.UpdateFrameTransform)
+					frames.DELETE("/transforms/:id", middleware.RequirePermission(utils.PermissionMapManage), frameHandler.DeleteFrameTransform)
+					// 位姿换算只读取变换，查看权限即可
+					frames.POST("/convert", middleware.RequirePermission(utils.PermissionMapView), frameHandler.ConvertPoses)
+					frames.GET("/transforms/:id", middleware.RequirePermission(utils.PermissionMapView), frameHandler.GetFrameTransform)
+					frames.GET("/transforms", middleware.RequirePermission(utils.PermissionMapView), frameHandler.ListFrameTransforms)
+					frames.GET("", middleware.RequirePermission(utils.PermissionMapView), frameHandler.ListFrames)
+				}
+
 				// 语义地图管理
 				semantics := maps.Group("/semantic-maps")
 				{
 					// 创建/编辑/删除需要地图管理权限
 					semantics.POST("", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.CreateSemanticMap)
+					semantics.POST("/geojson", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.ImportSemanticGeoJSON)
 					semantics.PUT("/:id", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.UpdateSemanticMap)
 					semantics.DELETE("/:id", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.DeleteSemanticMap)
+					semantics.PUT("/:id/geojson", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.ReplaceSemanticGeoJSON)
+					semantics.POST("/:id/versions/:version/rollback", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.RollbackSemanticMap)
+					// 路径规划只读取地图，查看权限即可
+					semantics.POST("/:id/plan", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.PlanSemanticPath)
+					semantics.POST("/:id/check", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.CheckSemanticMap)
+					semantics.POST("/:id/merge", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.MergeSemanticMap)
+					semantics.POST("/:id/lock", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.LockSemanticMap)
+					semantics.DELETE("/:id/lock", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.UnlockSemanticMap)
+					semantics.POST("/:id/:kind", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.CreateSemanticElement)
+					semantics.PUT("/:id/:kind/:elementId", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.UpdateSemanticElement)
+					semantics.DELETE("/:id/:kind/:elementId", middleware.RequirePermission(utils.PermissionMapManage), semanticHandler.DeleteSemanticElement)
 					// 查看需要地图查看权限
 					semantics.GET("/:id", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.GetSemanticMap)
+					semantics.GET("/:id/versions", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.ListSemanticMapVersions)
+					semantics.GET("/:id/versions/:version", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.GetSemanticMapVersion)
+					semantics.GET("/:id/check", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.GetSemanticCheck)
+					semantics.GET("/:id/geojson", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.ExportSemanticGeoJSON)
+					semantics.GET("/:id/diff", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.DiffSemanticMap)
+					semantics.GET("/:id/lock", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.GetSemanticMapLock)
+					// 按元素编辑语义信息，kind 为 pois / regions / waypoints / edges
+					semantics.GET("/:id/:kind", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.ListSemanticElements)
+					semantics.GET("/:id/:kind/:elementId", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.GetSemanticElement)
 					semantics.GET("", middleware.RequirePermission(utils.PermissionMapView), semanticHandler.ListSemanticMaps)
 				}
 			}
 
+			// 位置层级管理，园区 → 楼栋 → 楼层 → 区域
+			locations := authenticated.Group("/locations")
+			{
+				// 创建/编辑/删除需要地图管理权限
+				locations.POST("", middleware.RequirePermission(utils.PermissionMapManage), locationHandler.CreateLocation)
+				locations.PUT("/:id", middleware.RequirePermission(utils.PermissionMapManage), locationHandler.UpdateLocation)
+				locations.DELETE("/:id", middleware.RequirePermission(utils.PermissionMapManage), locationHandler.DeleteLocation)
+				// 查看需要地图查看权限
+				locations.GET("/tree", middleware.RequirePermission(utils.PermissionMapView), locationHandler.GetLocationTree)
+				locations.GET("/:id", middleware.RequirePermission(utils.PermissionMapView), locationHandler.GetLocation)
+				locations.GET("", middleware.RequirePermission(utils.PermissionMapView), locationHandler.ListLocations)
+			}
+
 			// 任务管理
 			tasks := authenticated.Group("/tasks")
 			{
@@ -148,9 +308,40 @@ func SetupRouter(router *gin.Engine, cfg *config.Config) {
 				devices.POST("", middleware.RequirePermission(utils.PermissionDeviceManage), deviceHandler.CreateDevice)
 				devices.PUT("/:id", middleware.RequirePermission(utils.PermissionDeviceManage), deviceHandler.UpdateDevice)
 				devices.DELETE("/:id", middleware.RequirePermission(utils.PermissionDeviceManage), deviceHandler.DeleteDevice)
+				// 设备注册码与凭证吊销
+				devices.POST("/import", middleware.RequirePermission(utils.PermissionDeviceManage), deviceHandler.ImportDevices)
+				devices.GET("/export", middleware.RequirePermission(utils.PermissionDeviceView), deviceHandler.ExportDevices)
+
+				devices.POST("/enrollment-codes", middleware.RequirePermission(utils.PermissionDeviceManage), provisionHandler.CreateEnrollmentCode)
+				devices.DELETE("/:id/credential", middleware.RequirePermission(utils.PermissionDeviceManage), provisionHandler.RevokeCredential)
+				// 局域网设备发现与认领
+				devices.POST("/discovery/scan", middleware.RequirePermission(utils.PermissionDeviceManage), discoveryHandler.ScanDevices)
+				devices.POST("/discovery/:key/adopt", middleware.RequirePermission(utils.PermissionDeviceManage), discoveryHandler.AdoptDevice)
+				devices.GET("/discovery", middleware.RequirePermission(utils.PermissionDeviceView), discoveryHandler.ListDiscoveredDevices)
+
+				devices.GET("/alarms", middleware.RequirePermission(utils.PermissionDeviceView), alarmHandler.ListAlarms)
+				devices.GET("/alarms/:alarmId", middleware.RequirePermission(utils.PermissionDeviceView), alarmHandler.GetAlarm)
+				devices.POST("/alarms/:alarmId/ack", middleware.RequirePermission(utils.PermissionDeviceManage), alarmHandler.AcknowledgeAlarm)
+				devices.POST("/alarms/:alarmId/clear", middleware.RequirePermission(utils.PermissionDeviceManage), alarmHandler.ClearAlarm)
 				// 查看需要设备查看权限（普通用户也可以）
 				devices.GET("/:id", middleware.RequirePermission(utils.PermissionDeviceView), deviceHandler.GetDevice)
 				devices.GET("", middleware.RequirePermission(utils.PermissionDeviceView), deviceHandler.ListDevices)
+				devices.GET("/:id/telemetry", middleware.RequirePermission(utils.PermissionDeviceView), provisionHandler.ListTelemetry)
+
+				devices.POST("/:id/maps", middleware.RequirePermission(utils.PermissionDeviceManage), mapDeploymentHandler.DeployMap)
+				devices.GET("/:id/maps", middleware.RequirePermission(utils.PermissionDeviceView), mapDeploymentHandler.ListDeployments)
+				devices.GET("/:id/maps/:deploymentId", middleware.RequirePermission(utils.PermissionDeviceView), mapDeploymentHandler.GetDeployment)
+			}
+
+			// 设备型号目录
+			deviceModels := authenticated.Group("/device-models")
+			{
+				deviceModels.POST("", middleware.RequirePermission(utils.PermissionDeviceManage), deviceModelHandler.CreateModel)
+				deviceModels.PUT("/:id", middleware.RequirePermission(utils.PermissionDeviceManage), deviceModelHandler.UpdateModel)
+				deviceModels.DELETE("/:id", middleware.RequirePermission(utils.PermissionDeviceManage), deviceModelHandler.DeleteModel)
+
+				deviceModels.GET("/:id", middleware.RequirePermission(utils.PermissionDeviceView), deviceModelHandler.GetModel)
+				deviceModels.GET("", middleware.RequirePermission(utils.PermissionDeviceView), deviceModelHandler.ListModels)
 			}
 
 			// 操作记录查询（操作员及以上）
@@ -163,3 +354,29 @@ func SetupRouter(router *gin.Engine, cfg *config.Config) {
 		}
 	}
 }
+
+// newDeviceDiscoveryService 根据配置创建设备发现服务，未配置时使用默认参数（仅可手动扫描）
+func newDeviceDiscoveryService(deviceDAO dao.DeviceDAO, modelDAO dao.DeviceModelDAO, cfg *config.DiscoveryConfig) *service.DeviceDiscoveryService {
+	if cfg == nil {
+		cfg = &config.DiscoveryConfig{}
+	}
+
+	scanner := discovery.NewScanner(discovery.Options{
+		Interfaces:     cfg.Interfaces,
+		ProbePort:      cfg.ProbePort,
+		ListenPort:     cfg.ListenPort,
+		MulticastGroup: cfg.MulticastGroup,
+		Targets:        cfg.Targets,
+		Timeout:        time.Duration(cfg.ScanTimeout) * time.Second,
+	})
+	registry := discovery.NewRegistry(time.Duration(cfg.EntryTTL) * time.Second)
+
+	return service.NewDeviceDiscoveryService(
+		deviceDAO,
+		modelDAO,
+		scanner,
+		registry,
+		time.Duration(cfg.ScanInterval)*time.Second,
+		cfg.ListenPort > 0,
+	)
+}
@@ -20,6 +20,7 @@ type Server struct {
 	config *config.Config
 	router *gin.Engine
 	server *http.Server
+	cancel context.CancelFunc
 }
 
 func NewServer(cfg *config.Config) *Server {
@@ -36,8 +37,11 @@ func NewServer(cfg *config.Config) *Server {
 	engine.Use(middleware.Logger())
 	engine.Use(middleware.CORS())
 
+	// 后台任务（设备发现等）随服务关闭而停止
+	ctx, cancel := context.WithCancel(context.Background())
+
 	// 注册路由
-	router.SetupRouter(engine, cfg)
+	router.SetupRouter(ctx, engine, cfg)
 
 	// 注册Swagger
 	if cfg.App.Mode != "release" {
@@ -57,6 +61,7 @@ func NewServer(cfg *config.Config) *Server {
 		config: cfg,
 		router: engine,
 		server: server,
+		cancel: cancel,
 	}
 }
 
@@ -66,5 +71,6 @@ func (s *Server) Start() error {
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.cancel()
 	return s.server.Shutdown(ctx)
 }
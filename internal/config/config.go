@@ -12,8 +12,17 @@ type Config struct {
 	Database *DatabaseConfig `mapstructure:"database"`
 	Log      *LogConfig      `mapstructure:"log"`
 	Minio    *MinioConfig    `mapstructure:"minio"`
+	Storage  *StorageConfig  `mapstructure:"storage"`
 	Platform *PlatformConfig `mapstructure:"platform"`
 	Auth     *AuthConfig     `mapstructure:"auth"`
+
+	Discovery *DiscoveryConfig `mapstructure:"discovery"`
+	Preview   *PreviewConfig   `mapstructure:"preview"`
+	Occupancy *OccupancyConfig `mapstructure:"occupancy"`
+
+	SemanticCheck *SemanticCheckConfig `mapstructure:"semantic_check"`
+	GeoReference  *GeoReferenceConfig  `mapstructure:"geo_reference"`
+	Deploy        *DeployConfig        `mapstructure:"deploy"`
 }
 
 type AppConfig struct {
@@ -68,6 +77,27 @@ type MinioConfig struct {
 	UseSSL     bool   `mapstructure:"use_ssl"`
 	BucketName string `mapstructure:"bucket_name"`
 	Region     string `mapstructure:"region"`
+
+	UploadExpire        int `mapstructure:"upload_expire"`         // 上传凭证(含分片预签名URL)有效期(秒)，默认 600
+	UploadSweepInterval int `mapstructure:"upload_sweep_interval"` // 过期上传与已删除地图对象的清理间隔(秒)，0 表示不清理
+	DeletedRetention    int `mapstructure:"deleted_retention"`     // 已删除地图对象的保留时长(小时)，默认 168
+	MultipartExpire     int `mapstructure:"multipart_expire"`      // 分片上传未完成时的保留时长(小时)，默认 24
+	DownloadExpire      int `mapstructure:"download_expire"`       // 下载链接有效期(秒)，默认 300
+	IntegrityInterval   int `mapstructure:"integrity_interval"`    // 对象完整性重新校验的周期(小时)，0 表示不定期校验
+	MaxDecompressed     int `mapstructure:"max_decompressed"`      // binary_compressed 点云解压后的数据上限(MB)，默认 4096
+}
+
+// StorageConfig 对象存储后端配置，上传、下载、清理等参数仍沿用 minio 配置段
+type StorageConfig struct {
+	Type  string              `mapstructure:"type"` // minio, local；为空时 platform.type 为 embedded 使用 local，否则 minio.enabled 时使用 minio
+	Local *LocalStorageConfig `mapstructure:"local"`
+}
+
+// LocalStorageConfig 本地磁盘存储配置，签名链接由本服务提供
+type LocalStorageConfig struct {
+	Root       string `mapstructure:"root"`        // 对象存放目录，默认 ./data/objects
+	SignKey    string `mapstructure:"sign_key"`    // 签名链接的 HMAC 密钥，必须单独配置，不能与其他密钥共用
+	BaseURL    string `mapstructure:"base_url"`    // 签名链接的外部访问地址，如 http://192.168.1.10:8080，为空时生成相对路径
 }
 
 type PlatformConfig struct {
@@ -80,6 +110,66 @@ type AuthConfig struct {
 	JWTExpireHours int   `mapstructure:"jwt_expire_hours"`
 }
 
+// DiscoveryConfig 局域网设备发现配置
+type DiscoveryConfig struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	Interfaces     []string `mapstructure:"interfaces"`      // 发送广播的网卡，为空时使用全部可广播网卡
+	ProbePort      int      `mapstructure:"probe_port"`      // 设备监听探测报文的端口
+	ListenPort     int      `mapstructure:"listen_port"`     // 监听设备主动广播的端口，0 表示不监听
+	MulticastGroup string   `mapstructure:"multicast_group"` // 组播地址（可选）
+	Targets        []string `mapstructure:"targets"`         // 额外单播探测地址(host:port)
+	ScanTimeout    int      `mapstructure:"scan_timeout"`    // 单次扫描等待时长(秒)
+	ScanInterval   int      `mapstructure:"scan_interval"`   // 周期扫描间隔(秒)，0 表示只手动扫描
+	EntryTTL       int      `mapstructure:"entry_ttl"`       // 发现记录保留时长(秒)
+}
+
+// PreviewConfig 点云预览生成配置
+type PreviewConfig struct {
+	Enabled       bool    `mapstructure:"enabled"`        // 启动点云后台任务协程，关闭时上传的点云在请求内同步解析，其他任务不执行
+	LeafSize      float64 `mapstructure:"leaf_size"`      // 初始体素边长(米)，默认 0.1
+	MaxPoints     int     `mapstructure:"max_points"`     // 预览最大点数，超出时自动放大体素，默认 500000
+	ThumbnailSize int     `mapstructure:"thumbnail_size"` // 缩略图长边像素，默认 512
+	PollInterval  int     `mapstructure:"poll_interval"`  // 后台任务轮询间隔(秒)，默认 5
+}
+
+// OccupancyConfig 点云生成二维占据栅格的默认参数，请求未指定时使用
+type OccupancyConfig struct {
+	Resolution float64 `mapstructure:"resolution"` // 栅格分辨率(米/像素)，默认 0.05
+	MinZ       float64 `mapstructure:"min_z"`      // 障碍物高度带下限(米)，低于下限的点视为地面，默认 0.1
+	MaxZ       float64 `mapstructure:"max_z"`      // 障碍物高度带上限(米)，默认 1.5
+	MinPoints  int     `mapstructure:"min_points"` // 栅格内至少多少个点才判为占据，默认 2
+	MaxPixels  int     `mapstructure:"max_pixels"` // 输出图像像素上限，默认 25000000
+	MaxRange   float64 `mapstructure:"max_range"`  // 点坐标 x、y 绝对值上限(米)，超出的离群点被忽略，默认 100000
+}
+
+// SemanticCheckConfig 语义地图一致性检查配置
+type SemanticCheckConfig struct {
+	Interval     int     `mapstructure:"interval"`      // 后台重新检查过期结果的间隔(秒)，0 表示只在编辑后和手动触发时检查
+	BoundsMargin float64 `mapstructure:"bounds_margin"` // 底图范围外允许的容差(米)，默认 0.5
+}
+
+// GeoReferenceConfig 地图坐标系到 WGS84 的换算参数，用于 GeoJSON 按经纬度导入导出
+type GeoReferenceConfig struct {
+	Enabled   bool    `mapstructure:"enabled"`
+	OriginLon float64 `mapstructure:"origin_lon"` // 地图原点经度(度)
+	OriginLat float64 `mapstructure:"origin_lat"` // 地图原点纬度(度)
+	Rotation  float64 `mapstructure:"rotation"`   // 地图 x 轴相对正东方向的逆时针旋转角(度)
+}
+
+// DeployConfig 地图下发到设备的配置
+type DeployConfig struct {
+	DefaultDriver string `mapstructure:"default_driver"` // 设备型号未指定驱动时使用的驱动，默认 http
+	PollInterval  int    `mapstructure:"poll_interval"`  // 后台任务轮询间隔(秒)，默认 5
+	Timeout       int    `mapstructure:"timeout"`        // 连接设备与等待响应的超时(秒)，默认 10
+	HTTPS         bool   `mapstructure:"https"`          // HTTP 驱动使用 https，关闭时设备密码以明文发送
+	CAFile        string `mapstructure:"ca_file"`        // https 校验设备证书的 CA 文件(PEM)，为空时使用系统根证书
+	SFTPPort      int    `mapstructure:"sftp_port"`      // SFTP 端口，默认 22
+	RemoteDir     string `mapstructure:"remote_dir"`     // SFTP 上传的根目录，默认 maps
+	KnownHosts    string `mapstructure:"known_hosts"`    // SSH known_hosts 文件
+	HostKey       string `mapstructure:"host_key"`       // SSH 主机公钥(authorized_keys 格式)，与 known_hosts 都未配置时 SFTP 拒绝连接
+}
+
+
 var cfg *Config
 
 func Init(configPath string) error {
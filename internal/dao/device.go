@@ -101,7 +101,7 @@ func (d *DeviceDAOImpl) FindAll(ctx context.Context) ([]*entity.Device, error) {
 }
 
 // FindPage 分页查询设备
-func (d *DeviceDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*entity.Device, int64, error) {
+func (d *DeviceDAOImpl) FindPage(ctx context.Context, filter dao.DeviceFilter, offset, limit int) ([]*entity.Device, int64, error) {
 	logger.Debug("finding devices with pagination", zap.Int("offset", offset), zap.Int("limit", limit))
 
 	var (
@@ -110,6 +110,13 @@ func (d *DeviceDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*ent
 	)
 
 	db := d.db.WithContext(ctx).Model(&entity.Device{})
+	db = whereLocation(db, "location_id", filter.LocationID)
+	if filter.LoadedSemanticMapID != nil {
+		db = db.Where("loaded_semantic_map_id = ?", *filter.LoadedSemanticMapID)
+		if filter.LoadedSemanticMapVersion != nil {
+			db = db.Where("loaded_semantic_map_version = ?", *filter.LoadedSemanticMapVersion)
+		}
+	}
 
 	if err := db.Count(&total).Error; err != nil {
 		logger.Error("failed to count devices for pagination", zap.Error(err))
@@ -127,4 +134,78 @@ func (d *DeviceDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*ent
 
 	logger.Debug("found devices with pagination", zap.Int("count", len(devices)), zap.Int64("total", total))
 	return devices, total, nil
-}
\ No newline at end of file
+}
+
+// FindBySerialNumber 根据序列号查询设备
+func (d *DeviceDAOImpl) FindBySerialNumber(ctx context.Context, serialNumber string) (*entity.Device, error) {
+	logger.Debug("finding device by serial number", zap.String("serialNumber", serialNumber))
+
+	var device entity.Device
+	err := d.db.WithContext(ctx).Where("serial_number = ?", serialNumber).First(&device).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Debug("device not found by serial number", zap.String("serialNumber", serialNumber))
+			return nil, nil
+		}
+		logger.Error("failed to find device by serial number", zap.Error(err), zap.String("serialNumber", serialNumber))
+		return nil, err
+	}
+
+	logger.Debug("device found by serial number", zap.String("serialNumber", serialNumber), zap.Uint("id", device.ID))
+	return &device, nil
+}
+
+// CountByModelID 统计使用指定型号的设备数量
+func (d *DeviceDAOImpl) CountByModelID(ctx context.Context, modelID uint) (int64, error) {
+	var count int64
+	err := d.db.WithContext(ctx).Model(&entity.Device{}).Where("model_id = ?", modelID).Count(&count).Error
+	if err != nil {
+		logger.Error("failed to count devices by model id", zap.Error(err), zap.Uint("modelID", modelID))
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateBatch 在同一事务中批量创建设备，任一失败则全部回滚
+func (d *DeviceDAOImpl) CreateBatch(ctx context.Context, devices []*entity.Device) error {
+	logger.Info("creating devices in batch", zap.Int("count", len(devices)))
+
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, device := range devices {
+			if err := tx.Create(device).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to create devices in batch", zap.Error(err))
+		return err
+	}
+
+	logger.Info("devices created in batch successfully", zap.Int("count", len(devices)))
+	return nil
+}
+
+// UpdateLoadedMap 记录设备当前加载的地图，只更新地图相关字段
+func (d *DeviceDAOImpl) UpdateLoadedMap(ctx context.Context, device *entity.Device) error {
+	logger.Info("updating device loaded map", zap.Uint("id", device.ID))
+
+	result := d.db.WithContext(ctx).Model(&entity.Device{}).
+		Where("id = ?", device.ID).
+		Updates(map[string]interface{}{
+			"loaded_semantic_map_id":      device.LoadedSemanticMapID,
+			"loaded_semantic_map_version": device.LoadedSemanticMapVersion,
+			"loaded_deployment_id":        device.LoadedDeploymentID,
+			"map_loaded_at":               device.MapLoadedAt,
+		})
+	if result.Error != nil {
+		logger.Error("failed to update device loaded map", zap.Error(result.Error), zap.Uint("id", device.ID))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		logger.Warn("device not found for loaded map update", zap.Uint("id", device.ID))
+		return errors.New("device not found")
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type DeviceAlarmDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewDeviceAlarmDAO(db *gorm.DB) dao.DeviceAlarmDAO {
+	return &DeviceAlarmDAOImpl{db: db}
+}
+
+func (d *DeviceAlarmDAOImpl) Create(ctx context.Context, alarm *entity.DeviceAlarm) error {
+	logger.Info("creating device alarm", zap.Uint("deviceID", alarm.DeviceID), zap.String("code", alarm.Code))
+
+	if err := d.db.WithContext(ctx).Create(alarm).Error; err != nil {
+		logger.Error("failed to create device alarm", zap.Error(err), zap.Uint("deviceID", alarm.DeviceID))
+		return err
+	}
+
+	logger.Info("device alarm created successfully", zap.Uint("id", alarm.ID))
+	return nil
+}
+
+func (d *DeviceAlarmDAOImpl) Update(ctx context.Context, alarm *entity.DeviceAlarm) error {
+	logger.Info("updating device alarm", zap.Uint("id", alarm.ID))
+
+	result := d.db.WithContext(ctx).Save(alarm)
+	if err := result.Error; err != nil {
+		logger.Error("failed to update device alarm", zap.Error(err), zap.Uint("id", alarm.ID))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Warn("device alarm not found for update", zap.Uint("id", alarm.ID))
+		return errors.New("device alarm not found")
+	}
+
+	logger.Info("device alarm updated successfully", zap.Uint("id", alarm.ID))
+	return nil
+}
+
+func (d *DeviceAlarmDAOImpl) FindByID(ctx context.Context, id uint) (*entity.DeviceAlarm, error) {
+	logger.Debug("finding device alarm by id", zap.Uint("id", id))
+
+	var alarm entity.DeviceAlarm
+	err := d.db.WithContext(ctx).First(&alarm, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Debug("device alarm not found", zap.Uint("id", id))
+			return nil, nil
+		}
+		logger.Error("failed to find device alarm by id", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	return &alarm, nil
+}
+
+// FindActiveByDeviceAndCode 查询设备指定错误码的未解除告警
+func (d *DeviceAlarmDAOImpl) FindActiveByDeviceAndCode(ctx context.Context, deviceID uint, code string) (*entity.DeviceAlarm, error) {
+	var alarm entity.DeviceAlarm
+	err := d.db.WithContext(ctx).
+		Where("device_id = ? AND code = ? AND cleared_at IS NULL", deviceID, code).
+		Order("id DESC").
+		First(&alarm).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to find active device alarm", zap.Error(err), zap.Uint("deviceID", deviceID), zap.String("code", code))
+		return nil, err
+	}
+
+	return &alarm, nil
+}
+
+// CountActiveBySeverity 统计设备指定级别的未解除告警数量
+func (d *DeviceAlarmDAOImpl) CountActiveBySeverity(ctx context.Context, deviceID uint, severity entity.AlarmSeverity) (int64, error) {
+	var count int64
+	err := d.db.WithContext(ctx).
+		Model(&entity.DeviceAlarm{}).
+		Where("device_id = ? AND severity = ? AND cleared_at IS NULL", deviceID, severity).
+		Count(&count).Error
+	if err != nil {
+		logger.Error("failed to count active device alarms", zap.Error(err), zap.Uint("deviceID", deviceID))
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindPage 按条件分页查询告警
+func (d *DeviceAlarmDAOImpl) FindPage(ctx context.Context, filter dao.DeviceAlarmFilter, offset, limit int) ([]*entity.DeviceAlarm, int64, error) {
+	logger.Debug("finding device alarms with pagination", zap.Int("offset", offset), zap.Int("limit", limit))
+
+	var (
+		alarms []*entity.DeviceAlarm
+		total  int64
+	)
+
+	db := d.db.WithContext(ctx).Model(&entity.DeviceAlarm{})
+	if filter.DeviceID != nil {
+		db = db.Where("device_id = ?", *filter.DeviceID)
+	}
+	if filter.Severity != nil {
+		db = db.Where("severity = ?", *filter.Severity)
+	}
+	if filter.Code != nil {
+		db = db.Where("code = ?", *filter.Code)
+	}
+	if filter.Active != nil {
+		if *filter.Active {
+			db = db.Where("cleared_at IS NULL")
+		} else {
+			db = db.Where("cleared_at IS NOT NULL")
+		}
+	}
+	if filter.Acknowledged != nil {
+		if *filter.Acknowledged {
+			db = db.Where("acknowledged_at IS NOT NULL")
+		} else {
+			db = db.Where("acknowledged_at IS NULL")
+		}
+	}
+	if filter.StartTime != nil {
+		db = db.Where("raised_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		db = db.Where("raised_at <= ?", *filter.EndTime)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		logger.Error("failed to count device alarms for pagination", zap.Error(err))
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []*entity.DeviceAlarm{}, 0, nil
+	}
+
+	if err := db.Order("raised_at DESC, id DESC").Offset(offset).Limit(limit).Find(&alarms).Error; err != nil {
+		logger.Error("failed to find device alarms with pagination", zap.Error(err))
+		return nil, 0, err
+	}
+
+	logger.Debug("found device alarms with pagination", zap.Int("count", len(alarms)), zap.Int64("total", total))
+	return alarms, total, nil
+}
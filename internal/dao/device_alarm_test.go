@@ -0,0 +1,112 @@
+package impl
+
+import (
+	"context"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+	"time"
+)
+
+func TestDeviceAlarmDAO_ActiveAndCount(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	alarmDAO := NewDeviceAlarmDAO(db)
+	ctx := context.Background()
+
+	alarm := &entity.DeviceAlarm{DeviceID: 1, Code: "E100", Severity: entity.AlarmSeverityCritical, RaisedAt: time.Now()}
+	if err := alarmDAO.Create(ctx, alarm); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	alarmDAO.Create(ctx, &entity.DeviceAlarm{DeviceID: 1, Code: "W200", Severity: entity.AlarmSeverityWarning, RaisedAt: time.Now()})
+
+	active, err := alarmDAO.FindActiveByDeviceAndCode(ctx, 1, "E100")
+	if err != nil || active == nil {
+		t.Fatalf("FindActiveByDeviceAndCode failed: %v", err)
+	}
+	if active.ID != alarm.ID {
+		t.Errorf("Expected alarm %d, got %d", alarm.ID, active.ID)
+	}
+
+	count, err := alarmDAO.CountActiveBySeverity(ctx, 1, entity.AlarmSeverityCritical)
+	if err != nil {
+		t.Fatalf("CountActiveBySeverity failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 active critical alarm, got %d", count)
+	}
+
+	now := time.Now()
+	alarm.ClearedAt = &now
+	if err := alarmDAO.Update(ctx, alarm); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	active, err = alarmDAO.FindActiveByDeviceAndCode(ctx, 1, "E100")
+	if err != nil {
+		t.Fatalf("FindActiveByDeviceAndCode failed: %v", err)
+	}
+	if active != nil {
+		t.Error("Expected no active alarm after clear")
+	}
+
+	count, _ = alarmDAO.CountActiveBySeverity(ctx, 1, entity.AlarmSeverityCritical)
+	if count != 0 {
+		t.Errorf("Expected 0 active critical alarms, got %d", count)
+	}
+}
+
+func TestDeviceAlarmDAO_FindPage(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	alarmDAO := NewDeviceAlarmDAO(db)
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 4; i++ {
+		alarmDAO.Create(ctx, &entity.DeviceAlarm{
+			DeviceID: 1,
+			Code:     "E100",
+			Severity: entity.AlarmSeverityCritical,
+			RaisedAt: base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	cleared := time.Now()
+	alarmDAO.Create(ctx, &entity.DeviceAlarm{DeviceID: 2, Code: "W200", Severity: entity.AlarmSeverityWarning, RaisedAt: base, ClearedAt: &cleared})
+
+	deviceID := uint(1)
+	alarms, total, err := alarmDAO.FindPage(ctx, dao.DeviceAlarmFilter{DeviceID: &deviceID}, 0, 2)
+	if err != nil {
+		t.Fatalf("FindPage failed: %v", err)
+	}
+	if total != 4 {
+		t.Errorf("Expected total 4, got %d", total)
+	}
+	if len(alarms) != 2 {
+		t.Errorf("Expected 2 alarms, got %d", len(alarms))
+	}
+	if !alarms[0].RaisedAt.After(alarms[1].RaisedAt) {
+		t.Error("Expected newest alarm first")
+	}
+
+	active := false
+	_, total, err = alarmDAO.FindPage(ctx, dao.DeviceAlarmFilter{Active: &active}, 0, 10)
+	if err != nil {
+		t.Fatalf("FindPage failed: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("Expected 1 cleared alarm, got %d", total)
+	}
+
+	severity := entity.AlarmSeverityInfo
+	alarms, total, err = alarmDAO.FindPage(ctx, dao.DeviceAlarmFilter{Severity: &severity}, 0, 10)
+	if err != nil {
+		t.Fatalf("FindPage failed: %v", err)
+	}
+	if total != 0 || len(alarms) != 0 {
+		t.Errorf("Expected no info alarms, got %d", total)
+	}
+}
@@ -0,0 +1,90 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type DeviceCredentialDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewDeviceCredentialDAO(db *gorm.DB) dao.DeviceCredentialDAO {
+	return &DeviceCredentialDAOImpl{db: db}
+}
+
+// FindByTokenHash 根据凭证哈希查询未吊销的凭证
+func (d *DeviceCredentialDAOImpl) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.DeviceCredential, error) {
+	logger.Debug("finding device credential by hash")
+
+	var credential entity.DeviceCredential
+	err := d.db.WithContext(ctx).
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		First(&credential).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Debug("device credential not found")
+			return nil, nil
+		}
+		logger.Error("failed to find device credential", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("device credential found", zap.Uint("id", credential.ID), zap.Uint("deviceID", credential.DeviceID))
+	return &credential, nil
+}
+
+// FindActiveByDeviceID 查询设备当前未吊销的凭证
+func (d *DeviceCredentialDAOImpl) FindActiveByDeviceID(ctx context.Context, deviceID uint) ([]*entity.DeviceCredential, error) {
+	logger.Debug("finding active device credentials", zap.Uint("deviceID", deviceID))
+
+	var credentials []*entity.DeviceCredential
+	err := d.db.WithContext(ctx).
+		Where("device_id = ? AND revoked_at IS NULL", deviceID).
+		Find(&credentials).Error
+	if err != nil {
+		logger.Error("failed to find active device credentials", zap.Error(err), zap.Uint("deviceID", deviceID))
+		return nil, err
+	}
+
+	logger.Debug("found active device credentials", zap.Uint("deviceID", deviceID), zap.Int("count", len(credentials)))
+	return credentials, nil
+}
+
+// TouchLastUsed 更新凭证最近使用时间
+func (d *DeviceCredentialDAOImpl) TouchLastUsed(ctx context.Context, id uint) error {
+	err := d.db.WithContext(ctx).
+		Model(&entity.DeviceCredential{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+	if err != nil {
+		logger.Error("failed to touch device credential", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+	return nil
+}
+
+// RevokeByDeviceID 吊销设备的全部凭证
+func (d *DeviceCredentialDAOImpl) RevokeByDeviceID(ctx context.Context, deviceID uint, revokedBy string) (int64, error) {
+	logger.Info("revoking device credentials", zap.Uint("deviceID", deviceID), zap.String("revokedBy", revokedBy))
+
+	result := d.db.WithContext(ctx).
+		Model(&entity.DeviceCredential{}).
+		Where("device_id = ? AND revoked_at IS NULL", deviceID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_by": revokedBy})
+	if err := result.Error; err != nil {
+		logger.Error("failed to revoke device credentials", zap.Error(err), zap.Uint("deviceID", deviceID))
+		return 0, err
+	}
+
+	logger.Info("device credentials revoked", zap.Uint("deviceID", deviceID), zap.Int64("count", result.RowsAffected))
+	return result.RowsAffected, nil
+}
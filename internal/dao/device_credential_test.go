@@ -0,0 +1,57 @@
+package impl
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+)
+
+func TestDeviceCredentialDAO_RevokeByDeviceID(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	credentialDAO := NewDeviceCredentialDAO(db)
+	ctx := context.Background()
+
+	device := testutil.CreateTestDevice(t, db, entity.DeviceTypeWheelRobot)
+	db.Create(&entity.DeviceCredential{DeviceID: device.ID, TokenHash: "token-1"})
+
+	found, err := credentialDAO.FindByTokenHash(ctx, "token-1")
+	if err != nil || found == nil {
+		t.Fatalf("Expected to find credential, err: %v", err)
+	}
+
+	count, err := credentialDAO.RevokeByDeviceID(ctx, device.ID, "admin")
+	if err != nil {
+		t.Fatalf("RevokeByDeviceID failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 revoked credential, got %d", count)
+	}
+
+	found, _ = credentialDAO.FindByTokenHash(ctx, "token-1")
+	if found != nil {
+		t.Error("Expected revoked credential to be rejected")
+	}
+}
+
+func TestDeviceCredentialDAO_TouchLastUsed(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	credentialDAO := NewDeviceCredentialDAO(db)
+	ctx := context.Background()
+
+	credential := &entity.DeviceCredential{DeviceID: 1, TokenHash: "token-1"}
+	db.Create(credential)
+
+	if err := credentialDAO.TouchLastUsed(ctx, credential.ID); err != nil {
+		t.Fatalf("TouchLastUsed failed: %v", err)
+	}
+
+	found, _ := credentialDAO.FindByTokenHash(ctx, "token-1")
+	if found.LastUsedAt == nil {
+		t.Error("Expected LastUsedAt to be set")
+	}
+}
@@ -0,0 +1,105 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type DeviceEnrollmentDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewDeviceEnrollmentDAO(db *gorm.DB) dao.DeviceEnrollmentDAO {
+	return &DeviceEnrollmentDAOImpl{db: db}
+}
+
+func (d *DeviceEnrollmentDAOImpl) Create(ctx context.Context, code *entity.DeviceEnrollmentCode) error {
+	logger.Info("creating device enrollment code", zap.String("createdBy", code.CreatedBy))
+
+	if err := d.db.WithContext(ctx).Create(code).Error; err != nil {
+		logger.Error("failed to create device enrollment code", zap.Error(err))
+		return err
+	}
+
+	logger.Info("device enrollment code created successfully", zap.Uint("id", code.ID))
+	return nil
+}
+
+// FindByCodeHash 根据注册码哈希查询
+func (d *DeviceEnrollmentDAOImpl) FindByCodeHash(ctx context.Context, codeHash string) (*entity.DeviceEnrollmentCode, error) {
+	logger.Debug("finding device enrollment code by hash")
+
+	var code entity.DeviceEnrollmentCode
+	err := d.db.WithContext(ctx).Where("code_hash = ?", codeHash).First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Debug("device enrollment code not found")
+			return nil, nil
+		}
+		logger.Error("failed to find device enrollment code", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("device enrollment code found", zap.Uint("id", code.ID))
+	return &code, nil
+}
+
+// Redeem 在同一事务中核销注册码、保存设备并签发设备凭证
+func (d *DeviceEnrollmentDAOImpl) Redeem(ctx context.Context, code *entity.DeviceEnrollmentCode, device *entity.Device, credential *entity.DeviceCredential) error {
+	logger.Info("redeeming device enrollment code", zap.Uint("codeID", code.ID), zap.Uint("deviceID", device.ID))
+
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// 条件更新保证注册码只能被核销一次
+		result := tx.Model(&entity.DeviceEnrollmentCode{}).
+			Where("id = ? AND used_at IS NULL", code.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return dao.ErrEnrollmentCodeUsed
+		}
+
+		if device.ID == 0 {
+			if err := tx.Create(device).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Save(device).Error; err != nil {
+				return err
+			}
+			// 重新注册的设备，旧凭证全部作废
+			if err := tx.Model(&entity.DeviceCredential{}).
+				Where("device_id = ? AND revoked_at IS NULL", device.ID).
+				Updates(map[string]interface{}{"revoked_at": now, "revoked_by": "re-enrollment"}).Error; err != nil {
+				return err
+			}
+		}
+
+		credential.DeviceID = device.ID
+		if err := tx.Create(credential).Error; err != nil {
+			return err
+		}
+
+		code.UsedAt = &now
+		code.DeviceID = &device.ID
+		return tx.Model(&entity.DeviceEnrollmentCode{}).Where("id = ?", code.ID).Update("device_id", device.ID).Error
+	})
+	if err != nil {
+		logger.Error("failed to redeem device enrollment code", zap.Error(err), zap.Uint("codeID", code.ID))
+		return err
+	}
+
+	logger.Info("device enrollment code redeemed successfully", zap.Uint("codeID", code.ID), zap.Uint("deviceID", device.ID))
+	return nil
+}
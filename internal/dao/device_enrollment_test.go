@@ -0,0 +1,138 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+	"time"
+)
+
+func createTestEnrollmentCode(t *testing.T, enrollmentDAO dao.DeviceEnrollmentDAO, hash string) *entity.DeviceEnrollmentCode {
+	t.Helper()
+
+	code := &entity.DeviceEnrollmentCode{
+		CodeHash:  hash,
+		CreatedBy: "test_user",
+		ExpireAt:  time.Now().Add(time.Hour),
+	}
+	if err := enrollmentDAO.Create(context.Background(), code); err != nil {
+		t.Fatalf("Create enrollment code failed: %v", err)
+	}
+	return code
+}
+
+func TestDeviceEnrollmentDAO_FindByCodeHash(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	enrollmentDAO := NewDeviceEnrollmentDAO(db)
+	ctx := context.Background()
+
+	code := createTestEnrollmentCode(t, enrollmentDAO, "hash-1")
+
+	found, err := enrollmentDAO.FindByCodeHash(ctx, "hash-1")
+	if err != nil {
+		t.Fatalf("FindByCodeHash failed: %v", err)
+	}
+	if found == nil || found.ID != code.ID {
+		t.Fatal("Expected to find enrollment code")
+	}
+
+	missing, err := enrollmentDAO.FindByCodeHash(ctx, "missing")
+	if err != nil {
+		t.Fatalf("FindByCodeHash failed: %v", err)
+	}
+	if missing != nil {
+		t.Error("Expected nil for unknown hash")
+	}
+}
+
+func TestDeviceEnrollmentDAO_Redeem_NewDevice(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	enrollmentDAO := NewDeviceEnrollmentDAO(db)
+	credentialDAO := NewDeviceCredentialDAO(db)
+	ctx := context.Background()
+
+	code := createTestEnrollmentCode(t, enrollmentDAO, "hash-1")
+	serial := "SN-001"
+	device := &entity.Device{Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg, SerialNumber: &serial}
+	credential := &entity.DeviceCredential{TokenHash: "token-1"}
+
+	if err := enrollmentDAO.Redeem(ctx, code, device, credential); err != nil {
+		t.Fatalf("Redeem failed: %v", err)
+	}
+
+	if device.ID == 0 {
+		t.Fatal("Expected device to be created")
+	}
+
+	found, _ := credentialDAO.FindByTokenHash(ctx, "token-1")
+	if found == nil || found.DeviceID != device.ID {
+		t.Fatal("Expected credential bound to the new device")
+	}
+
+	redeemed, _ := enrollmentDAO.FindByCodeHash(ctx, "hash-1")
+	if redeemed.UsedAt == nil || redeemed.DeviceID == nil || *redeemed.DeviceID != device.ID {
+		t.Error("Expected enrollment code to be marked used")
+	}
+}
+
+func TestDeviceEnrollmentDAO_Redeem_CodeUsedTwice(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	enrollmentDAO := NewDeviceEnrollmentDAO(db)
+	ctx := context.Background()
+
+	code := createTestEnrollmentCode(t, enrollmentDAO, "hash-1")
+
+	first := &entity.Device{Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg}
+	if err := enrollmentDAO.Redeem(ctx, code, first, &entity.DeviceCredential{TokenHash: "token-1"}); err != nil {
+		t.Fatalf("Redeem failed: %v", err)
+	}
+
+	second := &entity.Device{Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg}
+	err := enrollmentDAO.Redeem(ctx, code, second, &entity.DeviceCredential{TokenHash: "token-2"})
+	if !errors.Is(err, dao.ErrEnrollmentCodeUsed) {
+		t.Fatalf("Expected ErrEnrollmentCodeUsed, got %v", err)
+	}
+
+	var count int64
+	db.Model(&entity.Device{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected failed redeem to be rolled back, got %d devices", count)
+	}
+}
+
+func TestDeviceEnrollmentDAO_Redeem_ReEnrollRevokesOldCredential(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	enrollmentDAO := NewDeviceEnrollmentDAO(db)
+	credentialDAO := NewDeviceCredentialDAO(db)
+	ctx := context.Background()
+
+	device := &entity.Device{Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg}
+	if err := enrollmentDAO.Redeem(ctx, createTestEnrollmentCode(t, enrollmentDAO, "hash-1"), device, &entity.DeviceCredential{TokenHash: "token-1"}); err != nil {
+		t.Fatalf("Redeem failed: %v", err)
+	}
+
+	if err := enrollmentDAO.Redeem(ctx, createTestEnrollmentCode(t, enrollmentDAO, "hash-2"), device, &entity.DeviceCredential{TokenHash: "token-2"}); err != nil {
+		t.Fatalf("Re-enroll failed: %v", err)
+	}
+
+	old, _ := credentialDAO.FindByTokenHash(ctx, "token-1")
+	if old != nil {
+		t.Error("Expected old credential to be revoked")
+	}
+
+	active, _ := credentialDAO.FindActiveByDeviceID(ctx, device.ID)
+	if len(active) != 1 || active[0].TokenHash != "token-2" {
+		t.Errorf("Expected only the new credential to be active, got %d", len(active))
+	}
+}
@@ -0,0 +1,154 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type DeviceModelDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewDeviceModelDAO(db *gorm.DB) dao.DeviceModelDAO {
+	return &DeviceModelDAOImpl{db: db}
+}
+
+func (d *DeviceModelDAOImpl) Create(ctx context.Context, model *entity.DeviceModel) error {
+	logger.Info("creating device model", zap.String("company", string(model.Company)), zap.String("name", model.Name))
+
+	if err := d.db.WithContext(ctx).Create(model).Error; err != nil {
+		logger.Error("failed to create device model", zap.Error(err), zap.String("name", model.Name))
+		return err
+	}
+
+	logger.Info("device model created successfully", zap.Uint("id", model.ID))
+	return nil
+}
+
+func (d *DeviceModelDAOImpl) Update(ctx context.Context, model *entity.DeviceModel) error {
+	logger.Info("updating device model", zap.Uint("id", model.ID))
+
+	result := d.db.WithContext(ctx).Save(model)
+	if err := result.Error; err != nil {
+		logger.Error("failed to update device model", zap.Error(err), zap.Uint("id", model.ID))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Warn("device model not found for update", zap.Uint("id", model.ID))
+		return errors.New("device model not found")
+	}
+
+	logger.Info("device model updated successfully", zap.Uint("id", model.ID))
+	return nil
+}
+
+func (d *DeviceModelDAOImpl) Delete(ctx context.Context, id uint) error {
+	logger.Info("deleting device model", zap.Uint("id", id))
+
+	result := d.db.WithContext(ctx).Delete(&entity.DeviceModel{}, id)
+	if err := result.Error; err != nil {
+		logger.Error("failed to delete device model", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Warn("device model not found for deletion", zap.Uint("id", id))
+		return errors.New("device model not found")
+	}
+
+	logger.Info("device model deleted successfully", zap.Uint("id", id))
+	return nil
+}
+
+func (d *DeviceModelDAOImpl) FindByID(ctx context.Context, id uint) (*entity.DeviceModel, error) {
+	logger.Debug("finding device model by id", zap.Uint("id", id))
+
+	var model entity.DeviceModel
+	err := d.db.WithContext(ctx).First(&model, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Debug("device model not found", zap.Uint("id", id))
+			return nil, nil
+		}
+		logger.Error("failed to find device model by id", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	return &model, nil
+}
+
+// FindByCompanyAndName 根据厂商和型号名称查询
+func (d *DeviceModelDAOImpl) FindByCompanyAndName(ctx context.Context, company entity.CompanyType, name string) (*entity.DeviceModel, error) {
+	logger.Debug("finding device model by company and name", zap.String("company", string(company)), zap.String("name", name))
+
+	var model entity.DeviceModel
+	err := d.db.WithContext(ctx).Where("company = ? AND name = ?", company, name).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to find device model by company and name", zap.Error(err), zap.String("name", name))
+		return nil, err
+	}
+
+	return &model, nil
+}
+
+func (d *DeviceModelDAOImpl) FindAll(ctx context.Context) ([]*entity.DeviceModel, error) {
+	logger.Debug("finding all device models")
+
+	var models []*entity.DeviceModel
+	if err := d.db.WithContext(ctx).Order("company, name").Find(&models).Error; err != nil {
+		logger.Error("failed to find all device models", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("found device models", zap.Int("count", len(models)))
+	return models, nil
+}
+
+// FindPage 按条件分页查询设备型号
+func (d *DeviceModelDAOImpl) FindPage(ctx context.Context, filter dao.DeviceModelFilter, offset, limit int) ([]*entity.DeviceModel, int64, error) {
+	logger.Debug("finding device models with pagination", zap.Int("offset", offset), zap.Int("limit", limit))
+
+	var (
+		models []*entity.DeviceModel
+		total  int64
+	)
+
+	db := d.db.WithContext(ctx).Model(&entity.DeviceModel{})
+	if filter.Company != nil {
+		db = db.Where("company = ?", *filter.Company)
+	}
+	if filter.Type != nil {
+		db = db.Where("type = ?", *filter.Type)
+	}
+	if filter.Enabled != nil {
+		db = db.Where("enabled = ?", *filter.Enabled)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		logger.Error("failed to count device models for pagination", zap.Error(err))
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []*entity.DeviceModel{}, 0, nil
+	}
+
+	if err := db.Order("company, name").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		logger.Error("failed to find device models with pagination", zap.Error(err))
+		return nil, 0, err
+	}
+
+	logger.Debug("found device models with pagination", zap.Int("count", len(models)), zap.Int64("total", total))
+	return models, total, nil
+}
@@ -0,0 +1,77 @@
+package impl
+
+import (
+	"context"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+)
+
+func TestDeviceModelDAO_CreateAndFind(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	modelDAO := NewDeviceModelDAO(db)
+	ctx := context.Background()
+
+	model := &entity.DeviceModel{Company: entity.CompanyCyborg, Name: "cyborg-wheel", Type: entity.DeviceTypeWheelRobot, Enabled: true}
+	if err := modelDAO.Create(ctx, model); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// 同一厂商下型号名称唯一
+	duplicate := &entity.DeviceModel{Company: entity.CompanyCyborg, Name: "cyborg-wheel", Type: entity.DeviceTypeBipedRobot}
+	if err := modelDAO.Create(ctx, duplicate); err == nil {
+		t.Error("Expected duplicate company/name to fail")
+	}
+
+	found, err := modelDAO.FindByCompanyAndName(ctx, entity.CompanyCyborg, "cyborg-wheel")
+	if err != nil || found == nil {
+		t.Fatalf("FindByCompanyAndName failed: %v", err)
+	}
+	if found.ID != model.ID {
+		t.Errorf("Expected model %d, got %d", model.ID, found.ID)
+	}
+
+	missing, err := modelDAO.FindByCompanyAndName(ctx, entity.CompanyCyborg, "missing")
+	if err != nil {
+		t.Fatalf("FindByCompanyAndName failed: %v", err)
+	}
+	if missing != nil {
+		t.Error("Expected nil for missing model")
+	}
+}
+
+func TestDeviceModelDAO_FindPage(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	modelDAO := NewDeviceModelDAO(db)
+	ctx := context.Background()
+
+	modelDAO.Create(ctx, &entity.DeviceModel{Company: entity.CompanyCyborg, Name: "a", Type: entity.DeviceTypeWheelRobot, Enabled: true})
+	modelDAO.Create(ctx, &entity.DeviceModel{Company: entity.CompanyCyborg, Name: "b", Type: entity.DeviceTypeBipedRobot, Enabled: true})
+	disabled := &entity.DeviceModel{Company: "acme", Name: "c", Type: entity.DeviceTypeWheelRobot, Enabled: true}
+	modelDAO.Create(ctx, disabled)
+	disabled.Enabled = false
+	modelDAO.Update(ctx, disabled)
+
+	deviceType := entity.DeviceTypeWheelRobot
+	models, total, err := modelDAO.FindPage(ctx, dao.DeviceModelFilter{Type: &deviceType}, 0, 10)
+	if err != nil {
+		t.Fatalf("FindPage failed: %v", err)
+	}
+	if total != 2 || len(models) != 2 {
+		t.Errorf("Expected 2 wheel models, got %d", total)
+	}
+
+	enabled := true
+	_, total, err = modelDAO.FindPage(ctx, dao.DeviceModelFilter{Enabled: &enabled}, 0, 10)
+	if err != nil {
+		t.Fatalf("FindPage failed: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Expected 2 enabled models, got %d", total)
+	}
+}
@@ -0,0 +1,82 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type DeviceTelemetryDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewDeviceTelemetryDAO(db *gorm.DB) dao.DeviceTelemetryDAO {
+	return &DeviceTelemetryDAOImpl{db: db}
+}
+
+func (d *DeviceTelemetryDAOImpl) Create(ctx context.Context, telemetry *entity.DeviceTelemetry) error {
+	logger.Debug("creating device telemetry", zap.Uint("deviceID", telemetry.DeviceID))
+
+	if err := d.db.WithContext(ctx).Create(telemetry).Error; err != nil {
+		logger.Error("failed to create device telemetry", zap.Error(err), zap.Uint("deviceID", telemetry.DeviceID))
+		return err
+	}
+
+	return nil
+}
+
+// FindLatestByDeviceID 查询设备最近一条遥测记录
+func (d *DeviceTelemetryDAOImpl) FindLatestByDeviceID(ctx context.Context, deviceID uint) (*entity.DeviceTelemetry, error) {
+	logger.Debug("finding latest device telemetry", zap.Uint("deviceID", deviceID))
+
+	var telemetry entity.DeviceTelemetry
+	err := d.db.WithContext(ctx).
+		Where("device_id = ?", deviceID).
+		Order("id DESC").
+		First(&telemetry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to find latest device telemetry", zap.Error(err), zap.Uint("deviceID", deviceID))
+		return nil, err
+	}
+
+	return &telemetry, nil
+}
+
+// FindPageByDeviceID 分页查询设备遥测记录
+func (d *DeviceTelemetryDAOImpl) FindPageByDeviceID(ctx context.Context, deviceID uint, offset, limit int) ([]*entity.DeviceTelemetry, int64, error) {
+	logger.Debug("finding device telemetry with pagination",
+		zap.Uint("deviceID", deviceID), zap.Int("offset", offset), zap.Int("limit", limit))
+
+	var (
+		records []*entity.DeviceTelemetry
+		total   int64
+	)
+
+	db := d.db.WithContext(ctx).Model(&entity.DeviceTelemetry{}).Where("device_id = ?", deviceID)
+
+	if err := db.Count(&total).Error; err != nil {
+		logger.Error("failed to count device telemetry for pagination", zap.Error(err))
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []*entity.DeviceTelemetry{}, 0, nil
+	}
+
+	if err := db.Order("id DESC").Offset(offset).Limit(limit).Find(&records).Error; err != nil {
+		logger.Error("failed to find device telemetry with pagination", zap.Error(err))
+		return nil, 0, err
+	}
+
+	logger.Debug("found device telemetry with pagination", zap.Int("count", len(records)), zap.Int64("total", total))
+	return records, total, nil
+}
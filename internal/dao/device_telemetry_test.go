@@ -0,0 +1,47 @@
+package impl
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+)
+
+func TestDeviceTelemetryDAO_FindPageByDeviceID(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	telemetryDAO := NewDeviceTelemetryDAO(db)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		battery := float64(100 - i)
+		if err := telemetryDAO.Create(ctx, &entity.DeviceTelemetry{DeviceID: 1, Battery: &battery}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	telemetryDAO.Create(ctx, &entity.DeviceTelemetry{DeviceID: 2})
+
+	records, total, err := telemetryDAO.FindPageByDeviceID(ctx, 1, 0, 3)
+	if err != nil {
+		t.Fatalf("FindPageByDeviceID failed: %v", err)
+	}
+
+	if total != 5 {
+		t.Errorf("Expected total 5, got %d", total)
+	}
+	if len(records) != 3 {
+		t.Errorf("Expected 3 records, got %d", len(records))
+	}
+	if *records[0].Battery != 96 {
+		t.Errorf("Expected newest record first, got battery %v", *records[0].Battery)
+	}
+
+	latest, err := telemetryDAO.FindLatestByDeviceID(ctx, 1)
+	if err != nil || latest == nil {
+		t.Fatalf("FindLatestByDeviceID failed: %v", err)
+	}
+	if latest.ID != records[0].ID {
+		t.Errorf("Expected latest record %d, got %d", records[0].ID, latest.ID)
+	}
+}
@@ -2,9 +2,13 @@ package impl
 
 import (
 	"context"
+	dao "robot_scheduler/internal/dao/interfaces"
 	"robot_scheduler/internal/model/entity"
 	"robot_scheduler/internal/testutil"
 	"testing"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 func TestDeviceDAO_Create(t *testing.T) {
@@ -83,14 +87,14 @@ func TestDeviceDAO_FindPage(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer testutil.TeardownTestDB(db)
 
-	dao := NewDeviceDAO(db)
+	deviceDAO := NewDeviceDAO(db)
 	ctx := context.Background()
 
 	for i := 0; i < 5; i++ {
 		testutil.CreateTestDevice(t, db, entity.DeviceTypeWheelRobot)
 	}
 
-	devices, total, err := dao.FindPage(ctx, 0, 3)
+	devices, total, err := deviceDAO.FindPage(ctx, dao.DeviceFilter{}, 0, 3)
 	if err != nil {
 		t.Fatalf("FindPage failed: %v", err)
 	}
@@ -124,3 +128,88 @@ func TestDeviceDAO_FindAll(t *testing.T) {
 		t.Errorf("Expected 3 devices, got %d", len(devices))
 	}
 }
+
+func TestDeviceDAO_CreateBatch(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	dao := NewDeviceDAO(db)
+	ctx := context.Background()
+
+	devices := []*entity.Device{
+		{Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg, Port: 8080},
+		{Type: entity.DeviceTypeBipedRobot, Company: entity.CompanyCyborg, Port: 8081},
+	}
+	if err := dao.CreateBatch(ctx, devices); err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+	if devices[0].ID == 0 || devices[1].ID == 0 {
+		t.Error("Expected ids assigned after CreateBatch")
+	}
+
+	// 主键冲突时整批回滚
+	conflict := []*entity.Device{
+		{Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg, Port: 9000},
+		{Model: gorm.Model{ID: devices[0].ID}, Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg, Port: 9001},
+	}
+	if err := dao.CreateBatch(ctx, conflict); err == nil {
+		t.Fatal("Expected CreateBatch to fail on duplicate id")
+	}
+
+	all, err := dao.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 devices after rollback, got %d", len(all))
+	}
+}
+
+func TestDeviceDAO_UpdateLoadedMap(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	deviceDAO := NewDeviceDAO(db)
+	ctx := context.Background()
+
+	loaded := testutil.CreateTestDevice(t, db, entity.DeviceTypeWheelRobot)
+	testutil.CreateTestDevice(t, db, entity.DeviceTypeWheelRobot)
+
+	mapID, version, deploymentID := uint(7), 3, uint(11)
+	now := time.Now()
+	update := &entity.Device{
+		LoadedSemanticMapID:      &mapID,
+		LoadedSemanticMapVersion: &version,
+		LoadedDeploymentID:       &deploymentID,
+		MapLoadedAt:              &now,
+	}
+	update.ID = loaded.ID
+	if err := deviceDAO.UpdateLoadedMap(ctx, update); err != nil {
+		t.Fatalf("UpdateLoadedMap failed: %v", err)
+	}
+
+	found, _ := deviceDAO.FindByID(ctx, loaded.ID)
+	if found.LoadedSemanticMapID == nil || *found.LoadedSemanticMapID != mapID || *found.LoadedSemanticMapVersion != version || found.MapLoadedAt == nil {
+		t.Fatalf("Expected loaded map to be recorded, got %+v", found)
+	}
+	if found.Type != loaded.Type || found.Port != loaded.Port {
+		t.Errorf("Expected other fields to be kept, got %+v", found)
+	}
+
+	devices, total, err := deviceDAO.FindPage(ctx, dao.DeviceFilter{LoadedSemanticMapID: &mapID, LoadedSemanticMapVersion: &version}, 0, 10)
+	if err != nil {
+		t.Fatalf("FindPage failed: %v", err)
+	}
+	if total != 1 || devices[0].ID != loaded.ID {
+		t.Errorf("Expected only the device with the map loaded, got %d", total)
+	}
+	otherVersion := version + 1
+	if _, total, _ := deviceDAO.FindPage(ctx, dao.DeviceFilter{LoadedSemanticMapID: &mapID, LoadedSemanticMapVersion: &otherVersion}, 0, 10); total != 0 {
+		t.Errorf("Expected no device with version %d loaded, got %d", otherVersion, total)
+	}
+
+	update.ID = 9999
+	if err := deviceDAO.UpdateLoadedMap(ctx, update); err == nil {
+		t.Error("Expected UpdateLoadedMap to fail for missing device")
+	}
+}
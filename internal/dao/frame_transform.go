@@ -0,0 +1,104 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type FrameTransformDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewFrameTransformDAO(db *gorm.DB) dao.FrameTransformDAO {
+	return &FrameTransformDAOImpl{db: db}
+}
+
+func (d *FrameTransformDAOImpl) Create(ctx context.Context, transform *entity.FrameTransform) error {
+	logger.Info("creating frame transform", zap.String("parent", transform.ParentFrame), zap.String("child", transform.ChildFrame))
+
+	if err := d.db.WithContext(ctx).Create(transform).Error; err != nil {
+		logger.Error("failed to create frame transform", zap.Error(err))
+		return err
+	}
+
+	logger.Info("frame transform created successfully", zap.Uint("id", transform.ID))
+	return nil
+}
+
+func (d *FrameTransformDAOImpl) Update(ctx context.Context, transform *entity.FrameTransform) error {
+	logger.Info("updating frame transform", zap.Uint("id", transform.ID))
+
+	result := d.db.WithContext(ctx).Save(transform)
+	if err := result.Error; err != nil {
+		logger.Error("failed to update frame transform", zap.Error(err), zap.Uint("id", transform.ID))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Warn("frame transform not found for update", zap.Uint("id", transform.ID))
+		return errors.New("frame transform not found")
+	}
+
+	logger.Info("frame transform updated successfully", zap.Uint("id", transform.ID))
+	return nil
+}
+
+func (d *FrameTransformDAOImpl) Delete(ctx context.Context, id uint) error {
+	logger.Info("deleting frame transform", zap.Uint("id", id))
+
+	result := d.db.WithContext(ctx).Delete(&entity.FrameTransform{}, id)
+	if err := result.Error; err != nil {
+		logger.Error("failed to delete frame transform", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Warn("frame transform not found for deletion", zap.Uint("id", id))
+		return errors.New("frame transform not found")
+	}
+
+	logger.Info("frame transform deleted successfully", zap.Uint("id", id))
+	return nil
+}
+
+func (d *FrameTransformDAOImpl) FindByID(ctx context.Context, id uint) (*entity.FrameTransform, error) {
+	logger.Debug("finding frame transform by id", zap.Uint("id", id))
+
+	var transform entity.FrameTransform
+	err := d.db.WithContext(ctx).First(&transform, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Debug("frame transform not found", zap.Uint("id", id))
+			return nil, nil
+		}
+		logger.Error("failed to find frame transform by id", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	return &transform, nil
+}
+
+// FindAll 按ID升序查询全部坐标系变换，frame 不为空时只返回与其直接相连的变换
+func (d *FrameTransformDAOImpl) FindAll(ctx context.Context, frame string) ([]*entity.FrameTransform, error) {
+	logger.Debug("finding frame transforms", zap.String("frame", frame))
+
+	var transforms []*entity.FrameTransform
+	db := d.db.WithContext(ctx)
+	if frame != "" {
+		db = db.Where("parent_frame = ? OR child_frame = ?", frame, frame)
+	}
+	if err := db.Order("id").Find(&transforms).Error; err != nil {
+		logger.Error("failed to find frame transforms", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("found frame transforms", zap.Int("count", len(transforms)))
+	return transforms, nil
+}
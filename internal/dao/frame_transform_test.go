@@ -0,0 +1,42 @@
+package impl
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+)
+
+func TestFrameTransformDAO_FindAllByFrame(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	transformDAO := NewFrameTransformDAO(db)
+	ctx := context.Background()
+
+	for _, child := range []string{"pcd/1", "grid/2"} {
+		transform := &entity.FrameTransform{ParentFrame: "building/A", ChildFrame: child, X: 1, Yaw: 0.5, UserName: "test_user"}
+		if err := transformDAO.Create(ctx, transform); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	all, err := transformDAO.FindAll(ctx, "")
+	if err != nil || len(all) != 2 || all[0].ChildFrame != "pcd/1" {
+		t.Fatalf("Expected 2 transforms in id order, got %d (%v)", len(all), err)
+	}
+	linked, err := transformDAO.FindAll(ctx, "grid/2")
+	if err != nil || len(linked) != 1 || linked[0].ID != all[1].ID {
+		t.Fatalf("Expected only the grid transform, got %d (%v)", len(linked), err)
+	}
+
+	if err := transformDAO.Delete(ctx, all[0].ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if found, _ := transformDAO.FindByID(ctx, all[0].ID); found != nil {
+		t.Error("Expected deleted transform to be hidden")
+	}
+	if err := transformDAO.Delete(ctx, all[0].ID); err == nil {
+		t.Error("Expected error deleting missing transform")
+	}
+}
@@ -5,6 +5,14 @@ import (
 	"robot_scheduler/internal/model/entity"
 )
 
+// DeviceFilter 设备查询条件，字段为空表示不过滤
+type DeviceFilter struct {
+	LocationID *uint // 位置节点，包含其全部下级节点
+
+	LoadedSemanticMapID      *uint // 已加载的语义地图
+	LoadedSemanticMapVersion *int  // 已加载的语义地图版本，需同时指定 LoadedSemanticMapID
+}
+
 // DeviceDAO 设备数据访问接口
 type DeviceDAO interface {
 	// Create 创建设备
@@ -22,6 +30,18 @@ type DeviceDAO interface {
 	// FindAll 查询所有设备
 	FindAll(ctx context.Context) ([]*entity.Device, error)
 
-	// FindPage 分页查询设备
-	FindPage(ctx context.Context, offset, limit int) ([]*entity.Device, int64, error)
+	// FindPage 按条件分页查询设备
+	FindPage(ctx context.Context, filter DeviceFilter, offset, limit int) ([]*entity.Device, int64, error)
+
+	// FindBySerialNumber 根据序列号查询设备
+	FindBySerialNumber(ctx context.Context, serialNumber string) (*entity.Device, error)
+
+	// CountByModelID 统计使用指定型号的设备数量
+	CountByModelID(ctx context.Context, modelID uint) (int64, error)
+
+	// CreateBatch 在同一事务中批量创建设备，任一失败则全部回滚
+	CreateBatch(ctx context.Context, devices []*entity.Device) error
+
+	// UpdateLoadedMap 记录设备当前加载的地图，只更新地图相关字段
+	UpdateLoadedMap(ctx context.Context, device *entity.Device) error
 }
@@ -0,0 +1,39 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// DeviceAlarmFilter 设备告警查询条件，字段为空表示不过滤
+type DeviceAlarmFilter struct {
+	DeviceID     *uint
+	Severity     *entity.AlarmSeverity
+	Code         *string
+	Active       *bool // true: 未解除; false: 已解除
+	Acknowledged *bool
+	StartTime    *time.Time // 产生时间下限
+	EndTime      *time.Time // 产生时间上限
+}
+
+// DeviceAlarmDAO 设备告警数据访问接口
+type DeviceAlarmDAO interface {
+	// Create 创建告警
+	Create(ctx context.Context, alarm *entity.DeviceAlarm) error
+
+	// Update 更新告警
+	Update(ctx context.Context, alarm *entity.DeviceAlarm) error
+
+	// FindByID 根据ID查询告警
+	FindByID(ctx context.Context, id uint) (*entity.DeviceAlarm, error)
+
+	// FindActiveByDeviceAndCode 查询设备指定错误码的未解除告警
+	FindActiveByDeviceAndCode(ctx context.Context, deviceID uint, code string) (*entity.DeviceAlarm, error)
+
+	// CountActiveBySeverity 统计设备指定级别的未解除告警数量
+	CountActiveBySeverity(ctx context.Context, deviceID uint, severity entity.AlarmSeverity) (int64, error)
+
+	// FindPage 按条件分页查询告警（按产生时间倒序）
+	FindPage(ctx context.Context, filter DeviceAlarmFilter, offset, limit int) ([]*entity.DeviceAlarm, int64, error)
+}
@@ -0,0 +1,21 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+)
+
+// DeviceCredentialDAO 设备凭证数据访问接口
+type DeviceCredentialDAO interface {
+	// FindByTokenHash 根据凭证哈希查询未吊销的凭证
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.DeviceCredential, error)
+
+	// FindActiveByDeviceID 查询设备当前未吊销的凭证
+	FindActiveByDeviceID(ctx context.Context, deviceID uint) ([]*entity.DeviceCredential, error)
+
+	// TouchLastUsed 更新凭证最近使用时间
+	TouchLastUsed(ctx context.Context, id uint) error
+
+	// RevokeByDeviceID 吊销设备的全部凭证，返回吊销数量
+	RevokeByDeviceID(ctx context.Context, deviceID uint, revokedBy string) (int64, error)
+}
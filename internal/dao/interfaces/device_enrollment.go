@@ -0,0 +1,23 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/entity"
+)
+
+// ErrEnrollmentCodeUsed 注册码已被使用（并发核销时只有一方成功）
+var ErrEnrollmentCodeUsed = errors.New("enrollment code already used")
+
+// DeviceEnrollmentDAO 设备注册码数据访问接口
+type DeviceEnrollmentDAO interface {
+	// Create 创建注册码
+	Create(ctx context.Context, code *entity.DeviceEnrollmentCode) error
+
+	// FindByCodeHash 根据注册码哈希查询
+	FindByCodeHash(ctx context.Context, codeHash string) (*entity.DeviceEnrollmentCode, error)
+
+	// Redeem 在同一事务中核销注册码、保存设备并签发设备凭证
+	// device.ID 为 0 时新建设备，否则更新已有设备并吊销其旧凭证
+	Redeem(ctx context.Context, code *entity.DeviceEnrollmentCode, device *entity.Device, credential *entity.DeviceCredential) error
+}
@@ -0,0 +1,37 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+)
+
+// DeviceModelFilter 设备型号查询条件，字段为空表示不过滤
+type DeviceModelFilter struct {
+	Company *entity.CompanyType
+	Type    *entity.DeviceType
+	Enabled *bool
+}
+
+// DeviceModelDAO 设备型号目录数据访问接口
+type DeviceModelDAO interface {
+	// Create 创建设备型号
+	Create(ctx context.Context, model *entity.DeviceModel) error
+
+	// Update 更新设备型号
+	Update(ctx context.Context, model *entity.DeviceModel) error
+
+	// Delete 删除设备型号(软删除)
+	Delete(ctx context.Context, id uint) error
+
+	// FindByID 根据ID查询设备型号
+	FindByID(ctx context.Context, id uint) (*entity.DeviceModel, error)
+
+	// FindByCompanyAndName 根据厂商和型号名称查询
+	FindByCompanyAndName(ctx context.Context, company entity.CompanyType, name string) (*entity.DeviceModel, error)
+
+	// FindAll 查询全部设备型号
+	FindAll(ctx context.Context) ([]*entity.DeviceModel, error)
+
+	// FindPage 按条件分页查询设备型号
+	FindPage(ctx context.Context, filter DeviceModelFilter, offset, limit int) ([]*entity.DeviceModel, int64, error)
+}
@@ -0,0 +1,18 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+)
+
+// DeviceTelemetryDAO 设备遥测数据访问接口
+type DeviceTelemetryDAO interface {
+	// Create 创建遥测记录
+	Create(ctx context.Context, telemetry *entity.DeviceTelemetry) error
+
+	// FindLatestByDeviceID 查询设备最近一条遥测记录
+	FindLatestByDeviceID(ctx context.Context, deviceID uint) (*entity.DeviceTelemetry, error)
+
+	// FindPageByDeviceID 分页查询设备遥测记录（按时间倒序）
+	FindPageByDeviceID(ctx context.Context, deviceID uint, offset, limit int) ([]*entity.DeviceTelemetry, int64, error)
+}
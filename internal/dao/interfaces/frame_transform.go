@@ -0,0 +1,24 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+)
+
+// FrameTransformDAO 坐标系变换数据访问接口
+type FrameTransformDAO interface {
+	// Create 创建坐标系变换
+	Create(ctx context.Context, transform *entity.FrameTransform) error
+
+	// Update 更新坐标系变换
+	Update(ctx context.Context, transform *entity.FrameTransform) error
+
+	// Delete 删除坐标系变换(软删除)
+	Delete(ctx context.Context, id uint) error
+
+	// FindByID 根据ID查询坐标系变换，不存在时返回 nil
+	FindByID(ctx context.Context, id uint) (*entity.FrameTransform, error)
+
+	// FindAll 按ID升序查询全部坐标系变换，frame 不为空时只返回父或子坐标系为 frame 的变换
+	FindAll(ctx context.Context, frame string) ([]*entity.FrameTransform, error)
+}
@@ -0,0 +1,27 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+)
+
+// LocationDAO 位置层级数据访问接口
+type LocationDAO interface {
+	// Create 创建位置节点
+	Create(ctx context.Context, location *entity.Location) error
+
+	// Update 更新位置节点
+	Update(ctx context.Context, location *entity.Location) error
+
+	// Delete 删除位置节点(软删除)
+	Delete(ctx context.Context, id uint) error
+
+	// FindByID 根据ID查询位置节点，不存在时返回 nil
+	FindByID(ctx context.Context, id uint) (*entity.Location, error)
+
+	// FindAll 按ID升序查询全部位置节点
+	FindAll(ctx context.Context) ([]*entity.Location, error)
+
+	// CountAttached 统计直接挂载到该节点的点云地图、语义地图与设备总数
+	CountAttached(ctx context.Context, id uint) (int64, error)
+}
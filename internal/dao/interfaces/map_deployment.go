@@ -0,0 +1,33 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+)
+
+// MapDeploymentDAO 地图下发记录数据访问接口
+type MapDeploymentDAO interface {
+	// Create 创建下发记录
+	Create(ctx context.Context, deployment *entity.MapDeployment) error
+
+	// FindByID 根据ID查询下发记录，不存在时返回 nil
+	FindByID(ctx context.Context, id uint) (*entity.MapDeployment, error)
+
+	// FindActive 查询设备未结束（等待或上传中）的下发记录，不存在时返回 nil
+	FindActive(ctx context.Context, deviceID uint) (*entity.MapDeployment, error)
+
+	// ClaimNext 领取最早的等待下发记录并置为上传中，没有记录时返回 nil
+	ClaimNext(ctx context.Context) (*entity.MapDeployment, error)
+
+	// UpdateProgress 保存上传进度
+	UpdateProgress(ctx context.Context, id uint, sentBytes, totalBytes int64) error
+
+	// Finish 保存下发的结束状态与失败原因
+	Finish(ctx context.Context, deployment *entity.MapDeployment) error
+
+	// ListByDevice 按创建时间倒序查询设备的下发记录
+	ListByDevice(ctx context.Context, deviceID uint) ([]*entity.MapDeployment, error)
+
+	// ResetRunning 将上传中的记录重置为等待（服务重启后重新下发中断的地图），返回重置数量
+	ResetRunning(ctx context.Context) (int64, error)
+}
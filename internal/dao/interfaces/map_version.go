@@ -0,0 +1,36 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+)
+
+// PCDFileVersionDAO 点云地图版本数据访问接口
+type PCDFileVersionDAO interface {
+	// Create 以下一个版本号创建版本，并在同一事务中将点云地图的当前版本指向它
+	Create(ctx context.Context, version *entity.PCDFileVersion) error
+
+	// FindByFile 按版本号倒序查询点云地图的全部版本
+	FindByFile(ctx context.Context, pcdFileID uint) ([]*entity.PCDFileVersion, error)
+
+	// FindByVersion 查询点云地图的指定版本，不存在时返回 nil
+	FindByVersion(ctx context.Context, pcdFileID uint, version int) (*entity.PCDFileVersion, error)
+
+	// FindMinioPaths 查询对象尚未清理的点云地图各版本引用的 MinIO 对象
+	FindMinioPaths(ctx context.Context) ([]string, error)
+
+	// CountByMinioPath 统计其他未删除点云地图的版本中引用指定 MinIO 对象的数量
+	CountByMinioPath(ctx context.Context, minioPath string, excludeFileID uint) (int64, error)
+}
+
+// SemanticMapVersionDAO 语义地图版本数据访问接口
+type SemanticMapVersionDAO interface {
+	// Create 以下一个版本号创建版本，并在同一事务中将语义地图的当前版本指向它
+	Create(ctx context.Context, version *entity.SemanticMapVersion) error
+
+	// FindByMap 按版本号倒序查询语义地图的全部版本
+	FindByMap(ctx context.Context, semanticMapID uint) ([]*entity.SemanticMapVersion, error)
+
+	// FindByVersion 查询语义地图的指定版本，不存在时返回 nil
+	FindByVersion(ctx context.Context, semanticMapID uint, version int) (*entity.SemanticMapVersion, error)
+}
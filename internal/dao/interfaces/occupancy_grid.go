@@ -0,0 +1,27 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+)
+
+// OccupancyGridDAO 二维占据栅格地图数据访问接口
+type OccupancyGridDAO interface {
+	// Create 创建栅格地图
+	Create(ctx context.Context, grid *entity.OccupancyGrid) error
+
+	// Delete 删除栅格地图(软删除)
+	Delete(ctx context.Context, id uint) error
+
+	// FindByID 根据ID查询栅格地图，不存在时返回 nil
+	FindByID(ctx context.Context, id uint) (*entity.OccupancyGrid, error)
+
+	// FindPage 分页查询栅格地图
+	FindPage(ctx context.Context, offset, limit int) ([]*entity.OccupancyGrid, int64, error)
+
+	// FindByPCDFile 按创建时间倒序查询由点云地图生成的栅格地图
+	FindByPCDFile(ctx context.Context, pcdFileID uint) ([]*entity.OccupancyGrid, error)
+
+	// CountSemanticMaps 统计以该栅格地图为底图的语义地图数
+	CountSemanticMaps(ctx context.Context, id uint) (int64, error)
+}
@@ -2,9 +2,20 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"robot_scheduler/internal/model/entity"
+	"time"
 )
 
+// ErrPCDFileHasActiveTasks 引用该点云地图的任务仍在等待或执行中，级联删除被拒绝
+var ErrPCDFileHasActiveTasks = errors.New("pcd file has active tasks")
+
+// PCDFileFilter 点云地图查询条件，字段为空表示不过滤
+type PCDFileFilter struct {
+	LocationID      *uint                      // 位置节点，包含其全部下级节点
+	IntegrityStatus *entity.PCDIntegrityStatus // 完整性校验结果
+}
+
 // PCDFileDAO 点云地图数据访问接口
 type PCDFileDAO interface {
 	// Create 创建点云地图
@@ -25,6 +36,40 @@ type PCDFileDAO interface {
 	// FindByName 根据名称查询
 	FindByName(ctx context.Context, name string) (*entity.PCDFile, error)
 
-	// FindPage 分页查询点云地图
-	FindPage(ctx context.Context, offset, limit int) ([]*entity.PCDFile, int64, error)
+	// FindPage 按条件分页查询点云地图
+	FindPage(ctx context.Context, filter PCDFileFilter, offset, limit int) ([]*entity.PCDFile, int64, error)
+
+	// CountDependents 统计引用该点云地图的语义地图数与任务数
+	CountDependents(ctx context.Context, id uint) (semanticMaps int64, tasks int64, err error)
+
+	// DeleteCascade 在同一事务中软删除点云地图及引用它的语义地图、任务；
+	// 有待执行或执行中的任务且 force 为 false 时返回 ErrPCDFileHasActiveTasks
+	DeleteCascade(ctx context.Context, id uint, force bool) error
+
+	// FindDeletedBefore 查询软删除时间早于 before 且 MinIO 对象尚未清理的点云地图
+	FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*entity.PCDFile, error)
+
+	// MarkObjectPurged 记录点云地图的 MinIO 对象已清理
+	MarkObjectPurged(ctx context.Context, id uint) error
+
+	// FindAllWithObjects 查询仍持有 MinIO 对象的点云地图（含软删除但未清理的）
+	FindAllWithObjects(ctx context.Context) ([]*entity.PCDFile, error)
+
+	// CountByMinioPath 统计引用指定 MinIO 对象且未删除的点云地图数量
+	CountByMinioPath(ctx context.Context, minioPath string) (int64, error)
+
+	// UpdatePreview 只更新点云地图的预览字段，避免覆盖并发的其他修改
+	UpdatePreview(ctx context.Context, id uint, preview *entity.PCDPreview) error
+
+	// UpdateAnalysisStatus 只更新点云地图的解析状态
+	UpdateAnalysisStatus(ctx context.Context, id uint, status entity.PCDJobStatus) error
+
+	// FindBySHA256 查询内容 SHA-256 相同、对象可用且未删除的最早一个点云地图，用于上传去重
+	FindBySHA256(ctx context.Context, sha256 string) (*entity.PCDFile, error)
+
+	// FindIntegrityDue 查询持有对象且从未校验或上次校验早于 before 的点云地图，从未校验的排在前面
+	FindIntegrityDue(ctx context.Context, before time.Time, limit int) ([]*entity.PCDFile, error)
+
+	// UpdateIntegrity 只更新点云地图的 SHA-256 与完整性校验字段，避免覆盖并发的其他修改
+	UpdateIntegrity(ctx context.Context, file *entity.PCDFile) error
 }
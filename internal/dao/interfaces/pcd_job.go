@@ -0,0 +1,27 @@
+package dao
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+)
+
+// PCDJobDAO 点云后台任务数据访问接口
+type PCDJobDAO interface {
+	// Create 创建任务
+	Create(ctx context.Context, job *entity.PCDJob) error
+
+	// FindActive 查询点云地图指定类型未结束（等待或执行中）的任务，不存在时返回 nil
+	FindActive(ctx context.Context, pcdFileID uint, jobType entity.PCDJobType) (*entity.PCDJob, error)
+
+	// ClaimNext 领取最早的等待任务并置为执行中，没有任务时返回 nil
+	ClaimNext(ctx context.Context) (*entity.PCDJob, error)
+
+	// Finish 保存任务的结束状态、失败原因与结果
+	Finish(ctx context.Context, job *entity.PCDJob) error
+
+	// ListByFile 按创建时间倒序查询点云地图的任务
+	ListByFile(ctx context.Context, pcdFileID uint) ([]*entity.PCDJob, error)
+
+	// ResetRunning 将执行中的任务重置为等待（服务重启后恢复中断的任务），返回重置数量
+	ResetRunning(ctx context.Context) (int64, error)
+}
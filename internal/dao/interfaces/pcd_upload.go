@@ -0,0 +1,35 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// ErrPCDUploadNotPending 上传记录已完成或已过期（并发完成时只有一方成功）
+var ErrPCDUploadNotPending = errors.New("pcd upload is not pending")
+
+// PCDUploadDAO 点云上传记录数据访问接口
+type PCDUploadDAO interface {
+	// Create 创建上传记录
+	Create(ctx context.Context, upload *entity.PCDUpload) error
+
+	// FindByObjectKey 根据对象Key查询上传记录
+	FindByObjectKey(ctx context.Context, objectKey string) (*entity.PCDUpload, error)
+
+	// FindExpiredPending 查询截止时间早于 before 且仍未完成的上传记录
+	FindExpiredPending(ctx context.Context, before time.Time, limit int) ([]*entity.PCDUpload, error)
+
+	// Complete 在同一事务中将上传记录置为已完成并创建点云地图
+	Complete(ctx context.Context, upload *entity.PCDUpload, file *entity.PCDFile) error
+
+	// MarkExpired 将未完成的上传记录置为已过期
+	MarkExpired(ctx context.Context, id uint) error
+
+	// MarkAborted 将未完成的上传记录置为已放弃
+	MarkAborted(ctx context.Context, id uint) error
+
+	// ExtendExpire 延长未完成上传的截止时间
+	ExtendExpire(ctx context.Context, id uint, expireAt time.Time) error
+}
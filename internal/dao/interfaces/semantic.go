@@ -2,16 +2,35 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"robot_scheduler/internal/model/entity"
+	"time"
 )
 
+// ErrSemanticMapConflict 语义地图已被他人修改，修订号与预期不一致
+var ErrSemanticMapConflict = errors.New("semantic map revision conflict")
+
+// ErrSemanticMapLocked 语义地图编辑锁由他人持有
+var ErrSemanticMapLocked = errors.New("semantic map locked by another user")
+
+// SemanticMapFilter 语义地图查询条件，字段为空表示不过滤
+type SemanticMapFilter struct {
+	LocationID *uint // 位置节点，包含其全部下级节点
+}
+
 // SemanticMapDAO 语义地图数据访问接口
 type SemanticMapDAO interface {
 	// Create 创建语义地图
 	Create(ctx context.Context, semanticMap *entity.SemanticMap) error
 
-	// Update 更新语义地图
-	Update(ctx context.Context, semanticMap *entity.SemanticMap) error
+	// Update 更新语义地图，仅当数据库中的修订号仍等于 semanticMap.Revision 且编辑锁未被 editor 以外的人持有时
+	// 写入并递增修订号，修订号不一致返回 ErrSemanticMapConflict，锁被他人持有返回 ErrSemanticMapLocked；
+	// 不修改编辑锁与一致性检查结果
+	Update(ctx context.Context, semanticMap *entity.SemanticMap, editor string) error
+
+	// UpdateWithVersions 在同一事务中按 Update 的条件更新语义地图，并依次以下一个版本号写入 versions，
+	// 当前版本指向最后一个；条件不满足或任一写入失败时全部回滚
+	UpdateWithVersions(ctx context.Context, semanticMap *entity.SemanticMap, editor string, versions ...*entity.SemanticMapVersion) error
 
 	// Delete 删除语义地图(软删除)
 	Delete(ctx context.Context, id uint) error
@@ -22,6 +41,18 @@ type SemanticMapDAO interface {
 	// FindAll 查询所有语义地图
 	FindAll(ctx context.Context) ([]*entity.SemanticMap, error)
 
-	// FindPage 分页查询语义地图
-	FindPage(ctx context.Context, offset, limit int) ([]*entity.SemanticMap, int64, error)
+	// FindPage 按条件分页查询语义地图
+	FindPage(ctx context.Context, filter SemanticMapFilter, offset, limit int) ([]*entity.SemanticMap, int64, error)
+
+	// UpdateCheck 只更新一致性检查结果，不修改更新时间
+	UpdateCheck(ctx context.Context, id uint, check *entity.SemanticCheck) error
+
+	// FindCheckStale 查询尚未检查，或检查后语义地图、关联的点云或栅格底图又有更新的语义地图
+	FindCheckStale(ctx context.Context, limit int) ([]*entity.SemanticMap, error)
+
+	// AcquireLock 获取或续期编辑锁，锁由他人持有且未过期时返回 ErrSemanticMapLocked
+	AcquireLock(ctx context.Context, id uint, userName string, now, expiresAt time.Time) error
+
+	// ReleaseLock 释放编辑锁，锁由他人持有且未过期时返回 ErrSemanticMapLocked
+	ReleaseLock(ctx context.Context, id uint, userName string, now time.Time) error
 }
@@ -5,6 +5,11 @@ import (
 	"robot_scheduler/internal/model/entity"
 )
 
+// TaskFilter 任务查询条件，字段为空表示不过滤
+type TaskFilter struct {
+	LocationID *uint // 位置节点，按任务所用语义地图的位置匹配，包含其全部下级节点
+}
+
 // TaskDAO 任务数据访问接口
 type TaskDAO interface {
 	// Create 创建任务
@@ -22,6 +27,6 @@ type TaskDAO interface {
 	// FindAll 查询所有任务
 	FindAll(ctx context.Context) ([]*entity.Task, error)
 
-	// FindPage 分页查询任务
-	FindPage(ctx context.Context, offset, limit int) ([]*entity.Task, int64, error)
+	// FindPage 按条件分页查询任务
+	FindPage(ctx context.Context, filter TaskFilter, offset, limit int) ([]*entity.Task, int64, error)
 }
@@ -0,0 +1,130 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// locationSubtreeSQL 查询位置节点及其全部下级节点ID的递归子查询，SQLite 与 PostgreSQL 均支持
+const locationSubtreeSQL = `WITH RECURSIVE subtree(id) AS (
+	SELECT id FROM location WHERE id = ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT l.id FROM location l JOIN subtree s ON l.parent_id = s.id WHERE l.deleted_at IS NULL
+) SELECT id FROM subtree`
+
+// whereLocation 按位置节点过滤，column 取值为该节点或其任一下级节点时匹配，locationID 为空时不过滤
+func whereLocation(db *gorm.DB, column string, locationID *uint) *gorm.DB {
+	if locationID == nil {
+		return db
+	}
+	return db.Where(column+" IN ("+locationSubtreeSQL+")", *locationID)
+}
+
+type LocationDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewLocationDAO(db *gorm.DB) dao.LocationDAO {
+	return &LocationDAOImpl{db: db}
+}
+
+func (d *LocationDAOImpl) Create(ctx context.Context, location *entity.Location) error {
+	logger.Info("creating location", zap.String("name", location.Name), zap.String("level", string(location.Level)))
+
+	if err := d.db.WithContext(ctx).Create(location).Error; err != nil {
+		logger.Error("failed to create location", zap.Error(err))
+		return err
+	}
+
+	logger.Info("location created successfully", zap.Uint("id", location.ID))
+	return nil
+}
+
+func (d *LocationDAOImpl) Update(ctx context.Context, location *entity.Location) error {
+	logger.Info("updating location", zap.Uint("id", location.ID))
+
+	result := d.db.WithContext(ctx).Save(location)
+	if err := result.Error; err != nil {
+		logger.Error("failed to update location", zap.Error(err), zap.Uint("id", location.ID))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Warn("location not found for update", zap.Uint("id", location.ID))
+		return errors.New("location not found")
+	}
+
+	logger.Info("location updated successfully", zap.Uint("id", location.ID))
+	return nil
+}
+
+func (d *LocationDAOImpl) Delete(ctx context.Context, id uint) error {
+	logger.Info("deleting location", zap.Uint("id", id))
+
+	result := d.db.WithContext(ctx).Delete(&entity.Location{}, id)
+	if err := result.Error; err != nil {
+		logger.Error("failed to delete location", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Warn("location not found for deletion", zap.Uint("id", id))
+		return errors.New("location not found")
+	}
+
+	logger.Info("location deleted successfully", zap.Uint("id", id))
+	return nil
+}
+
+func (d *LocationDAOImpl) FindByID(ctx context.Context, id uint) (*entity.Location, error) {
+	logger.Debug("finding location by id", zap.Uint("id", id))
+
+	var location entity.Location
+	err := d.db.WithContext(ctx).First(&location, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Debug("location not found", zap.Uint("id", id))
+			return nil, nil
+		}
+		logger.Error("failed to find location by id", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+func (d *LocationDAOImpl) FindAll(ctx context.Context) ([]*entity.Location, error) {
+	logger.Debug("finding all locations")
+
+	var locations []*entity.Location
+	if err := d.db.WithContext(ctx).Order("id").Find(&locations).Error; err != nil {
+		logger.Error("failed to find all locations", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("found locations", zap.Int("count", len(locations)))
+	return locations, nil
+}
+
+// CountAttached 统计直接挂载到该节点的点云地图、语义地图与设备总数（不含已删除的记录）
+func (d *LocationDAOImpl) CountAttached(ctx context.Context, id uint) (int64, error) {
+	logger.Debug("counting location attachments", zap.Uint("id", id))
+
+	var total int64
+	for _, model := range []interface{}{&entity.PCDFile{}, &entity.SemanticMap{}, &entity.Device{}} {
+		var count int64
+		if err := d.db.WithContext(ctx).Model(model).Where("location_id = ?", id).Count(&count).Error; err != nil {
+			logger.Error("failed to count location attachments", zap.Error(err), zap.Uint("id", id))
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
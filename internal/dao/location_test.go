@@ -0,0 +1,78 @@
+package impl
+
+import (
+	"context"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+)
+
+func TestLocationDAO_SubtreeFilter(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	locationDAO := NewLocationDAO(db)
+	ctx := context.Background()
+
+	// 园区 A → 1 号楼 → 3 层，另建园区 B 作为对照
+	site := &entity.Location{Level: entity.LocationLevelSite, Name: "A", UserName: "test_user"}
+	other := &entity.Location{Level: entity.LocationLevelSite, Name: "B", UserName: "test_user"}
+	for _, l := range []*entity.Location{site, other} {
+		if err := locationDAO.Create(ctx, l); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	building := &entity.Location{ParentID: &site.ID, Level: entity.LocationLevelBuilding, Name: "1", UserName: "test_user"}
+	if err := locationDAO.Create(ctx, building); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	floor := &entity.Location{ParentID: &building.ID, Level: entity.LocationLevelFloor, Name: "3F", UserName: "test_user"}
+	if err := locationDAO.Create(ctx, floor); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "floor.pcd")
+	db.Model(pcdFile).Update("location_id", floor.ID)
+	testutil.CreateTestPCDFile(t, db, "unattached.pcd")
+	semanticMap := testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
+	db.Model(semanticMap).Update("location_id", building.ID)
+	testutil.CreateTestTask(t, db, semanticMap.ID)
+	device := testutil.CreateTestDevice(t, db, entity.DeviceTypeWheelRobot)
+	db.Model(device).Update("location_id", floor.ID)
+
+	// 按园区过滤时包含挂在下级节点上的记录
+	files, total, err := NewPCDFileDAO(db).FindPage(ctx, dao.PCDFileFilter{LocationID: &site.ID}, 0, 10)
+	if err != nil || total != 1 || files[0].ID != pcdFile.ID {
+		t.Fatalf("Expected only the floor pcd under the site, got %d (%v)", total, err)
+	}
+	_, total, _ = NewPCDFileDAO(db).FindPage(ctx, dao.PCDFileFilter{}, 0, 10)
+	if total != 2 {
+		t.Errorf("Expected 2 pcd files without filter, got %d", total)
+	}
+	// 按楼层过滤时不包含挂在上级节点上的语义地图
+	if _, total, _ = NewSemanticMapDAO(db).FindPage(ctx, dao.SemanticMapFilter{LocationID: &floor.ID}, 0, 10); total != 0 {
+		t.Errorf("Expected no semantic map on the floor, got %d", total)
+	}
+	if _, total, _ = NewSemanticMapDAO(db).FindPage(ctx, dao.SemanticMapFilter{LocationID: &site.ID}, 0, 10); total != 1 {
+		t.Errorf("Expected 1 semantic map under the site, got %d", total)
+	}
+	// 任务按所属语义地图的位置过滤
+	if _, total, _ = NewTaskDAO(db).FindPage(ctx, dao.TaskFilter{LocationID: &building.ID}, 0, 10); total != 1 {
+		t.Errorf("Expected 1 task under the building, got %d", total)
+	}
+	if _, total, _ = NewTaskDAO(db).FindPage(ctx, dao.TaskFilter{LocationID: &other.ID}, 0, 10); total != 0 {
+		t.Errorf("Expected no task under the other site, got %d", total)
+	}
+	if _, total, _ = NewDeviceDAO(db).FindPage(ctx, dao.DeviceFilter{LocationID: &site.ID}, 0, 10); total != 1 {
+		t.Errorf("Expected 1 device under the site, got %d", total)
+	}
+
+	// 直接挂载数只统计本节点
+	if count, err := locationDAO.CountAttached(ctx, floor.ID); err != nil || count != 2 {
+		t.Errorf("Expected 2 attachments on the floor, got %d (%v)", count, err)
+	}
+	if count, _ := locationDAO.CountAttached(ctx, site.ID); count != 0 {
+		t.Errorf("Expected no direct attachments on the site, got %d", count)
+	}
+}
@@ -0,0 +1,162 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MapDeploymentDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewMapDeploymentDAO(db *gorm.DB) dao.MapDeploymentDAO {
+	return &MapDeploymentDAOImpl{db: db}
+}
+
+func (d *MapDeploymentDAOImpl) Create(ctx context.Context, deployment *entity.MapDeployment) error {
+	logger.Info("creating map deployment", zap.Uint("deviceID", deployment.DeviceID),
+		zap.Uint("semanticMapID", deployment.SemanticMapID), zap.Int("version", deployment.SemanticMapVersion))
+
+	if err := d.db.WithContext(ctx).Create(deployment).Error; err != nil {
+		logger.Error("failed to create map deployment", zap.Error(err), zap.Uint("deviceID", deployment.DeviceID))
+		return err
+	}
+
+	logger.Info("map deployment created successfully", zap.Uint("id", deployment.ID))
+	return nil
+}
+
+// FindByID 根据ID查询下发记录，不存在时返回 nil
+func (d *MapDeploymentDAOImpl) FindByID(ctx context.Context, id uint) (*entity.MapDeployment, error) {
+	var deployment entity.MapDeployment
+	if err := d.db.WithContext(ctx).First(&deployment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to find map deployment", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// FindActive 查询设备未结束（等待或上传中）的下发记录，不存在时返回 nil
+func (d *MapDeploymentDAOImpl) FindActive(ctx context.Context, deviceID uint) (*entity.MapDeployment, error) {
+	var deployment entity.MapDeployment
+	err := d.db.WithContext(ctx).
+		Where("device_id = ? AND status IN ?", deviceID,
+			[]entity.MapDeploymentStatus{entity.MapDeploymentStatusPending, entity.MapDeploymentStatusRunning}).
+		Order("id DESC").
+		First(&deployment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to find active map deployment", zap.Error(err), zap.Uint("deviceID", deviceID))
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// ClaimNext 领取最早的等待下发记录并置为上传中，没有记录时返回 nil
+func (d *MapDeploymentDAOImpl) ClaimNext(ctx context.Context) (*entity.MapDeployment, error) {
+	db := d.db.WithContext(ctx)
+	for {
+		var deployment entity.MapDeployment
+		err := db.Where("status = ?", entity.MapDeploymentStatusPending).Order("id ASC").First(&deployment).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			logger.Error("failed to find pending map deployment", zap.Error(err))
+			return nil, err
+		}
+
+		// 条件更新保证同一记录只被领取一次
+		now := time.Now()
+		result := db.Model(&entity.MapDeployment{}).
+			Where("id = ? AND status = ?", deployment.ID, entity.MapDeploymentStatusPending).
+			Updates(map[string]interface{}{"status": entity.MapDeploymentStatusRunning, "started_at": now, "sent_bytes": 0})
+		if result.Error != nil {
+			logger.Error("failed to claim map deployment", zap.Error(result.Error), zap.Uint("id", deployment.ID))
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		deployment.Status = entity.MapDeploymentStatusRunning
+		deployment.StartedAt = &now
+		deployment.SentBytes = 0
+		logger.Debug("map deployment claimed", zap.Uint("id", deployment.ID))
+		return &deployment, nil
+	}
+}
+
+// UpdateProgress 保存上传进度
+func (d *MapDeploymentDAOImpl) UpdateProgress(ctx context.Context, id uint, sentBytes, totalBytes int64) error {
+	err := d.db.WithContext(ctx).Model(&entity.MapDeployment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"sent_bytes": sentBytes, "total_bytes": totalBytes}).Error
+	if err != nil {
+		logger.Error("failed to update map deployment progress", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+	return nil
+}
+
+// Finish 保存下发的结束状态与失败原因
+func (d *MapDeploymentDAOImpl) Finish(ctx context.Context, deployment *entity.MapDeployment) error {
+	logger.Info("finishing map deployment", zap.Uint("id", deployment.ID), zap.String("status", string(deployment.Status)))
+
+	now := time.Now()
+	deployment.FinishedAt = &now
+	err := d.db.WithContext(ctx).Model(&entity.MapDeployment{}).
+		Where("id = ?", deployment.ID).
+		Updates(map[string]interface{}{
+			"status":      deployment.Status,
+			"error":       deployment.Error,
+			"sent_bytes":  deployment.SentBytes,
+			"total_bytes": deployment.TotalBytes,
+			"finished_at": now,
+		}).Error
+	if err != nil {
+		logger.Error("failed to finish map deployment", zap.Error(err), zap.Uint("id", deployment.ID))
+		return err
+	}
+	return nil
+}
+
+// ListByDevice 按创建时间倒序查询设备的下发记录
+func (d *MapDeploymentDAOImpl) ListByDevice(ctx context.Context, deviceID uint) ([]*entity.MapDeployment, error) {
+	logger.Debug("listing map deployments by device", zap.Uint("deviceID", deviceID))
+
+	var deployments []*entity.MapDeployment
+	if err := d.db.WithContext(ctx).Where("device_id = ?", deviceID).Order("id DESC").Find(&deployments).Error; err != nil {
+		logger.Error("failed to list map deployments", zap.Error(err), zap.Uint("deviceID", deviceID))
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// ResetRunning 将上传中的记录重置为等待（服务重启后重新下发中断的地图），返回重置数量
+func (d *MapDeploymentDAOImpl) ResetRunning(ctx context.Context) (int64, error) {
+	result := d.db.WithContext(ctx).Model(&entity.MapDeployment{}).
+		Where("status = ?", entity.MapDeploymentStatusRunning).
+		Updates(map[string]interface{}{"status": entity.MapDeploymentStatusPending, "started_at": nil, "sent_bytes": 0})
+	if result.Error != nil {
+		logger.Error("failed to reset running map deployments", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	if result.RowsAffected > 0 {
+		logger.Info("interrupted map deployments reset to pending", zap.Int64("count", result.RowsAffected))
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,95 @@
+package impl
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+)
+
+func TestMapDeploymentDAO_ClaimProgressAndFinish(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	deploymentDAO := NewMapDeploymentDAO(db)
+	ctx := context.Background()
+
+	device := testutil.CreateTestDevice(t, db, entity.DeviceTypeWheelRobot)
+	if active, _ := deploymentDAO.FindActive(ctx, device.ID); active != nil {
+		t.Fatal("Expected no active deployment")
+	}
+
+	first := &entity.MapDeployment{DeviceID: device.ID, SemanticMapID: 1, SemanticMapVersion: 1, Driver: "http", UserName: "admin", Status: entity.MapDeploymentStatusPending}
+	second := &entity.MapDeployment{DeviceID: device.ID, SemanticMapID: 1, SemanticMapVersion: 2, Driver: "http", UserName: "admin", Status: entity.MapDeploymentStatusPending}
+	for _, d := range []*entity.MapDeployment{first, second} {
+		if err := deploymentDAO.Create(ctx, d); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if active, _ := deploymentDAO.FindActive(ctx, device.ID); active == nil || active.ID != second.ID {
+		t.Fatalf("Expected newest deployment to be active, got %+v", active)
+	}
+
+	claimed, err := deploymentDAO.ClaimNext(ctx)
+	if err != nil {
+		t.Fatalf("ClaimNext failed: %v", err)
+	}
+	if claimed == nil || claimed.ID != first.ID || claimed.Status != entity.MapDeploymentStatusRunning || claimed.StartedAt == nil {
+		t.Fatalf("Expected first deployment to be claimed, got %+v", claimed)
+	}
+
+	if err := deploymentDAO.UpdateProgress(ctx, claimed.ID, 40, 100); err != nil {
+		t.Fatalf("UpdateProgress failed: %v", err)
+	}
+	found, _ := deploymentDAO.FindByID(ctx, claimed.ID)
+	if found.SentBytes != 40 || found.TotalBytes != 100 {
+		t.Errorf("Expected progress 40/100, got %d/%d", found.SentBytes, found.TotalBytes)
+	}
+
+	claimed.Status = entity.MapDeploymentStatusSucceeded
+	claimed.SentBytes, claimed.TotalBytes = 100, 100
+	if err := deploymentDAO.Finish(ctx, claimed); err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+
+	list, err := deploymentDAO.ListByDevice(ctx, device.ID)
+	if err != nil {
+		t.Fatalf("ListByDevice failed: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != second.ID {
+		t.Fatalf("Expected 2 deployments newest first, got %d", len(list))
+	}
+	if list[1].Status != entity.MapDeploymentStatusSucceeded || list[1].SentBytes != 100 || list[1].FinishedAt == nil {
+		t.Errorf("Expected first deployment to be succeeded, got %+v", list[1])
+	}
+	if missing, err := deploymentDAO.FindByID(ctx, 9999); err != nil || missing != nil {
+		t.Errorf("Expected nil for missing deployment, got %+v (%v)", missing, err)
+	}
+}
+
+func TestMapDeploymentDAO_ResetRunning(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	deploymentDAO := NewMapDeploymentDAO(db)
+	ctx := context.Background()
+
+	device := testutil.CreateTestDevice(t, db, entity.DeviceTypeWheelRobot)
+	deployment := &entity.MapDeployment{DeviceID: device.ID, SemanticMapID: 1, SemanticMapVersion: 1, Driver: "sftp", UserName: "admin", Status: entity.MapDeploymentStatusPending}
+	if err := deploymentDAO.Create(ctx, deployment); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := deploymentDAO.ClaimNext(ctx); err != nil {
+		t.Fatalf("ClaimNext failed: %v", err)
+	}
+	deploymentDAO.UpdateProgress(ctx, deployment.ID, 10, 20)
+
+	count, err := deploymentDAO.ResetRunning(ctx)
+	if err != nil || count != 1 {
+		t.Fatalf("Expected 1 deployment reset, got %d (%v)", count, err)
+	}
+	found, _ := deploymentDAO.FindByID(ctx, deployment.ID)
+	if found.Status != entity.MapDeploymentStatusPending || found.StartedAt != nil || found.SentBytes != 0 {
+		t.Errorf("Expected deployment reset to pending, got %+v", found)
+	}
+}
@@ -0,0 +1,184 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PCDFileVersionDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewPCDFileVersionDAO(db *gorm.DB) dao.PCDFileVersionDAO {
+	return &PCDFileVersionDAOImpl{db: db}
+}
+
+// Create 以下一个版本号创建版本，并在同一事务中将点云地图的当前版本指向它
+// 并发创建时由 (pcd_file_id, version) 唯一索引保证版本号不重复
+func (d *PCDFileVersionDAOImpl) Create(ctx context.Context, version *entity.PCDFileVersion) error {
+	logger.Info("creating pcd file version", zap.Uint("pcdFileID", version.PCDFileID))
+
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先锁住点云地图行再取最大版本号，并发创建版本时依次分配
+		if err := tx.Model(&entity.PCDFile{}).
+			Where("id = ?", version.PCDFileID).
+			UpdateColumn("current_version", gorm.Expr("current_version")).Error; err != nil {
+			return err
+		}
+		var latest int
+		if err := tx.Model(&entity.PCDFileVersion{}).
+			Where("pcd_file_id = ?", version.PCDFileID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		version.Version = latest + 1
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.PCDFile{}).
+			Where("id = ?", version.PCDFileID).
+			UpdateColumn("current_version", version.Version).Error
+	})
+	if err != nil {
+		logger.Error("failed to create pcd file version", zap.Error(err), zap.Uint("pcdFileID", version.PCDFileID))
+		return err
+	}
+
+	logger.Info("pcd file version created successfully", zap.Uint("pcdFileID", version.PCDFileID), zap.Int("version", version.Version))
+	return nil
+}
+
+// FindByFile 按版本号倒序查询点云地图的全部版本
+func (d *PCDFileVersionDAOImpl) FindByFile(ctx context.Context, pcdFileID uint) ([]*entity.PCDFileVersion, error) {
+	var versions []*entity.PCDFileVersion
+	if err := d.db.WithContext(ctx).Where("pcd_file_id = ?", pcdFileID).Order("version DESC").Find(&versions).Error; err != nil {
+		logger.Error("failed to find pcd file versions", zap.Error(err), zap.Uint("pcdFileID", pcdFileID))
+		return nil, err
+	}
+	return versions, nil
+}
+
+// FindByVersion 查询点云地图的指定版本，不存在时返回 nil
+func (d *PCDFileVersionDAOImpl) FindByVersion(ctx context.Context, pcdFileID uint, version int) (*entity.PCDFileVersion, error) {
+	var v entity.PCDFileVersion
+	err := d.db.WithContext(ctx).Where("pcd_file_id = ? AND version = ?", pcdFileID, version).First(&v).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to find pcd file version", zap.Error(err), zap.Uint("pcdFileID", pcdFileID), zap.Int("version", version))
+		return nil, err
+	}
+	return &v, nil
+}
+
+// FindMinioPaths 查询对象尚未清理的点云地图各版本引用的 MinIO 对象
+func (d *PCDFileVersionDAOImpl) FindMinioPaths(ctx context.Context) ([]string, error) {
+	var paths []string
+	err := d.db.WithContext(ctx).Model(&entity.PCDFileVersion{}).
+		Joins("JOIN pcd_file ON pcd_file.id = pcd_file_version.pcd_file_id").
+		Where("pcd_file_version.minio_path IS NOT NULL AND pcd_file_version.minio_path <> ''").
+		Where("pcd_file.object_purged_at IS NULL").
+		Distinct().
+		Pluck("pcd_file_version.minio_path", &paths).Error
+	if err != nil {
+		logger.Error("failed to find pcd file version minio paths", zap.Error(err))
+		return nil, err
+	}
+	return paths, nil
+}
+
+// CountByMinioPath 统计其他未删除点云地图的版本中引用指定 MinIO 对象的数量
+func (d *PCDFileVersionDAOImpl) CountByMinioPath(ctx context.Context, minioPath string, excludeFileID uint) (int64, error) {
+	var count int64
+	err := d.db.WithContext(ctx).Model(&entity.PCDFileVersion{}).
+		Joins("JOIN pcd_file ON pcd_file.id = pcd_file_version.pcd_file_id").
+		Where("pcd_file_version.minio_path = ? AND pcd_file_version.pcd_file_id <> ?", minioPath, excludeFileID).
+		Where("pcd_file.deleted_at IS NULL").
+		Count(&count).Error
+	if err != nil {
+		logger.Error("failed to count pcd file versions by minio path", zap.Error(err), zap.String("minioPath", minioPath))
+		return 0, err
+	}
+	return count, nil
+}
+
+type SemanticMapVersionDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewSemanticMapVersionDAO(db *gorm.DB) dao.SemanticMapVersionDAO {
+	return &SemanticMapVersionDAOImpl{db: db}
+}
+
+// Create 以下一个版本号创建版本，并在同一事务中将语义地图的当前版本指向它
+func (d *SemanticMapVersionDAOImpl) Create(ctx context.Context, version *entity.SemanticMapVersion) error {
+	logger.Info("creating semantic map version", zap.Uint("semanticMapID", version.SemanticMapID))
+
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createSemanticMapVersion(tx, version)
+	})
+	if err != nil {
+		logger.Error("failed to create semantic map version", zap.Error(err), zap.Uint("semanticMapID", version.SemanticMapID))
+		return err
+	}
+
+	logger.Info("semantic map version created successfully", zap.Uint("semanticMapID", version.SemanticMapID), zap.Int("version", version.Version))
+	return nil
+}
+
+// createSemanticMapVersion 在事务中以下一个版本号写入版本并更新当前版本
+// 先锁住语义地图行再取最大版本号，并发写入同一地图的版本时依次分配版本号，不会撞唯一索引
+func createSemanticMapVersion(tx *gorm.DB, version *entity.SemanticMapVersion) error {
+	if err := tx.Model(&entity.SemanticMap{}).
+		Where("id = ?", version.SemanticMapID).
+		UpdateColumn("current_version", gorm.Expr("current_version")).Error; err != nil {
+		return err
+	}
+	var latest int
+	if err := tx.Model(&entity.SemanticMapVersion{}).
+		Where("semantic_map_id = ?", version.SemanticMapID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+	version.Version = latest + 1
+	if err := tx.Create(version).Error; err != nil {
+		return err
+	}
+	return tx.Model(&entity.SemanticMap{}).
+		Where("id = ?", version.SemanticMapID).
+		UpdateColumn("current_version", version.Version).Error
+}
+
+// FindByMap 按版本号倒序查询语义地图的全部版本
+func (d *SemanticMapVersionDAOImpl) FindByMap(ctx context.Context, semanticMapID uint) ([]*entity.SemanticMapVersion, error) {
+	var versions []*entity.SemanticMapVersion
+	if err := d.db.WithContext(ctx).Where("semantic_map_id = ?", semanticMapID).Order("version DESC").Find(&versions).Error; err != nil {
+		logger.Error("failed to find semantic map versions", zap.Error(err), zap.Uint("semanticMapID", semanticMapID))
+		return nil, err
+	}
+	return versions, nil
+}
+
+// FindByVersion 查询语义地图的指定版本，不存在时返回 nil
+func (d *SemanticMapVersionDAOImpl) FindByVersion(ctx context.Context, semanticMapID uint, version int) (*entity.SemanticMapVersion, error) {
+	var v entity.SemanticMapVersion
+	err := d.db.WithContext(ctx).Where("semantic_map_id = ? AND version = ?", semanticMapID, version).First(&v).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to find semantic map version", zap.Error(err), zap.Uint("semanticMapID", semanticMapID), zap.Int("version", version))
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,129 @@
+package impl
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+)
+
+func TestPCDFileVersionDAO_CreateAssignsNextVersion(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	versionDAO := NewPCDFileVersionDAO(db)
+	pcdDAO := NewPCDFileDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	for i, key := range []string{"pcd/a.pcd", "pcd/b.pcd"} {
+		minioPath := key
+		v := &entity.PCDFileVersion{PCDFileID: pcdFile.ID, Path: key, MinioPath: &minioPath, Author: "tester"}
+		if err := versionDAO.Create(ctx, v); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if v.Version != i+1 {
+			t.Fatalf("Expected version %d, got %d", i+1, v.Version)
+		}
+	}
+
+	file, err := pcdDAO.FindByID(ctx, pcdFile.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if file.CurrentVersion == nil || *file.CurrentVersion != 2 {
+		t.Fatalf("Expected current version 2, got %v", file.CurrentVersion)
+	}
+
+	versions, err := versionDAO.FindByFile(ctx, pcdFile.ID)
+	if err != nil {
+		t.Fatalf("FindByFile failed: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 2 {
+		t.Fatalf("Expected 2 versions in descending order, got %+v", versions)
+	}
+
+	missing, err := versionDAO.FindByVersion(ctx, pcdFile.ID, 3)
+	if err != nil || missing != nil {
+		t.Fatalf("Expected nil for missing version, got %+v, %v", missing, err)
+	}
+
+	paths, err := versionDAO.FindMinioPaths(ctx)
+	if err != nil {
+		t.Fatalf("FindMinioPaths failed: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 version paths, got %v", paths)
+	}
+}
+
+func TestPCDFileVersionDAO_CountByMinioPath(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	versionDAO := NewPCDFileVersionDAO(db)
+	pcdDAO := NewPCDFileDAO(db)
+	ctx := context.Background()
+
+	first := testutil.CreateTestPCDFile(t, db, "first.pcd")
+	second := testutil.CreateTestPCDFile(t, db, "second.pcd")
+	shared := "pcd/shared.pcd"
+	for _, id := range []uint{first.ID, second.ID} {
+		if err := versionDAO.Create(ctx, &entity.PCDFileVersion{PCDFileID: id, Path: shared, MinioPath: &shared, Author: "tester"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	count, err := versionDAO.CountByMinioPath(ctx, shared, first.ID)
+	if err != nil {
+		t.Fatalf("CountByMinioPath failed: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("Expected 1 reference from other files, got %d", count)
+	}
+
+	if err := pcdDAO.Delete(ctx, second.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	count, err = versionDAO.CountByMinioPath(ctx, shared, first.ID)
+	if err != nil {
+		t.Fatalf("CountByMinioPath failed: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("Expected versions of deleted files to be ignored, got %d", count)
+	}
+}
+
+func TestSemanticMapVersionDAO_CreateAndFind(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	versionDAO := NewSemanticMapVersionDAO(db)
+	semanticDAO := NewSemanticMapDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	semanticMap := testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
+	for _, info := range []string{`{"v":1}`, `{"v":2}`} {
+		v := &entity.SemanticMapVersion{SemanticMapID: semanticMap.ID, PCDFileID: &pcdFile.ID, SemanticInfo: info, Author: "tester"}
+		if err := versionDAO.Create(ctx, v); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	found, err := semanticDAO.FindByID(ctx, semanticMap.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if found.CurrentVersion == nil || *found.CurrentVersion != 2 {
+		t.Fatalf("Expected current version 2, got %v", found.CurrentVersion)
+	}
+
+	v, err := versionDAO.FindByVersion(ctx, semanticMap.ID, 1)
+	if err != nil {
+		t.Fatalf("FindByVersion failed: %v", err)
+	}
+	if v == nil || v.SemanticInfo != `{"v":1}` {
+		t.Fatalf("Expected first version content, got %+v", v)
+	}
+}
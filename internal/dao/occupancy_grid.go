@@ -0,0 +1,118 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type OccupancyGridDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewOccupancyGridDAO(db *gorm.DB) dao.OccupancyGridDAO {
+	return &OccupancyGridDAOImpl{db: db}
+}
+
+func (d *OccupancyGridDAOImpl) Create(ctx context.Context, grid *entity.OccupancyGrid) error {
+	logger.Info("creating occupancy grid", zap.String("name", grid.Name))
+
+	if err := d.db.WithContext(ctx).Create(grid).Error; err != nil {
+		logger.Error("failed to create occupancy grid", zap.Error(err), zap.String("name", grid.Name))
+		return err
+	}
+
+	logger.Info("occupancy grid created successfully", zap.Uint("id", grid.ID))
+	return nil
+}
+
+func (d *OccupancyGridDAOImpl) Delete(ctx context.Context, id uint) error {
+	logger.Info("deleting occupancy grid", zap.Uint("id", id))
+
+	result := d.db.WithContext(ctx).Delete(&entity.OccupancyGrid{}, id)
+	if err := result.Error; err != nil {
+		logger.Error("failed to delete occupancy grid", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Warn("occupancy grid not found for deletion", zap.Uint("id", id))
+		return errors.New("occupancy grid not found")
+	}
+
+	logger.Info("occupancy grid deleted successfully", zap.Uint("id", id))
+	return nil
+}
+
+func (d *OccupancyGridDAOImpl) FindByID(ctx context.Context, id uint) (*entity.OccupancyGrid, error) {
+	logger.Debug("finding occupancy grid by id", zap.Uint("id", id))
+
+	var grid entity.OccupancyGrid
+	err := d.db.WithContext(ctx).First(&grid, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Debug("occupancy grid not found", zap.Uint("id", id))
+			return nil, nil
+		}
+		logger.Error("failed to find occupancy grid by id", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+	return &grid, nil
+}
+
+func (d *OccupancyGridDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*entity.OccupancyGrid, int64, error) {
+	logger.Debug("finding occupancy grids with pagination", zap.Int("offset", offset), zap.Int("limit", limit))
+
+	var (
+		grids []*entity.OccupancyGrid
+		total int64
+	)
+
+	db := d.db.WithContext(ctx).Model(&entity.OccupancyGrid{})
+
+	if err := db.Count(&total).Error; err != nil {
+		logger.Error("failed to count occupancy grids for pagination", zap.Error(err))
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []*entity.OccupancyGrid{}, 0, nil
+	}
+
+	if err := db.Order("id DESC").Offset(offset).Limit(limit).Find(&grids).Error; err != nil {
+		logger.Error("failed to find occupancy grids with pagination", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return grids, total, nil
+}
+
+// FindByPCDFile 按创建时间倒序查询由点云地图生成的栅格地图
+func (d *OccupancyGridDAOImpl) FindByPCDFile(ctx context.Context, pcdFileID uint) ([]*entity.OccupancyGrid, error) {
+	logger.Debug("finding occupancy grids by pcd file", zap.Uint("pcdFileID", pcdFileID))
+
+	var grids []*entity.OccupancyGrid
+	if err := d.db.WithContext(ctx).Where("pcd_file_id = ?", pcdFileID).Order("id DESC").Find(&grids).Error; err != nil {
+		logger.Error("failed to find occupancy grids by pcd file", zap.Error(err), zap.Uint("pcdFileID", pcdFileID))
+		return nil, err
+	}
+	return grids, nil
+}
+
+// CountSemanticMaps 统计以该栅格地图为底图的语义地图数
+func (d *OccupancyGridDAOImpl) CountSemanticMaps(ctx context.Context, id uint) (int64, error) {
+	logger.Debug("counting semantic maps of occupancy grid", zap.Uint("id", id))
+
+	var count int64
+	if err := d.db.WithContext(ctx).Model(&entity.SemanticMap{}).Where("occupancy_grid_id = ?", id).Count(&count).Error; err != nil {
+		logger.Error("failed to count semantic maps of occupancy grid", zap.Error(err), zap.Uint("id", id))
+		return 0, err
+	}
+	return count, nil
+}
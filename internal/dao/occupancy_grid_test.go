@@ -0,0 +1,56 @@
+package impl
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+)
+
+func TestOccupancyGridDAO_FindByPCDFileAndDelete(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	gridDAO := NewOccupancyGridDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	for _, name := range []string{"a", "b"} {
+		grid := &entity.OccupancyGrid{
+			Name:       name,
+			Source:     entity.OccupancyGridSourcePCD,
+			PCDFileID:  &pcdFile.ID,
+			UserName:   "test_user",
+			ImagePath:  "grid/" + name + ".pgm",
+			YAMLPath:   "grid/" + name + ".yaml",
+			Resolution: 0.05,
+			Width:      10,
+			Height:     10,
+		}
+		if err := gridDAO.Create(ctx, grid); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	grids, err := gridDAO.FindByPCDFile(ctx, pcdFile.ID)
+	if err != nil {
+		t.Fatalf("FindByPCDFile failed: %v", err)
+	}
+	if len(grids) != 2 || grids[0].Name != "b" {
+		t.Fatalf("Expected 2 grids newest first, got %d", len(grids))
+	}
+
+	if err := gridDAO.Delete(ctx, grids[0].ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if found, _ := gridDAO.FindByID(ctx, grids[0].ID); found != nil {
+		t.Error("Expected deleted grid to be hidden")
+	}
+	_, total, err := gridDAO.FindPage(ctx, 0, 10)
+	if err != nil || total != 1 {
+		t.Errorf("Expected 1 remaining grid, got %d (%v)", total, err)
+	}
+	if err := gridDAO.Delete(ctx, grids[0].ID); err == nil {
+		t.Error("Expected error deleting missing grid")
+	}
+}
@@ -3,6 +3,7 @@ package impl
 import (
 	"context"
 	"errors"
+	"time"
 
 	dao "robot_scheduler/internal/dao/interfaces"
 	"robot_scheduler/internal/logger"
@@ -120,7 +121,7 @@ func (d *PCDFileDAOImpl) FindByName(ctx context.Context, name string) (*entity.P
 }
 
 // FindPage 分页查询点云地图
-func (d *PCDFileDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*entity.PCDFile, int64, error) {
+func (d *PCDFileDAOImpl) FindPage(ctx context.Context, filter dao.PCDFileFilter, offset, limit int) ([]*entity.PCDFile, int64, error) {
 	logger.Debug("finding pcd files with pagination", zap.Int("offset", offset), zap.Int("limit", limit))
 
 	var (
@@ -129,6 +130,10 @@ func (d *PCDFileDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*en
 	)
 
 	db := d.db.WithContext(ctx).Model(&entity.PCDFile{})
+	db = whereLocation(db, "location_id", filter.LocationID)
+	if filter.IntegrityStatus != nil {
+		db = db.Where("integrity_status = ?", *filter.IntegrityStatus)
+	}
 
 	if err := db.Count(&total).Error; err != nil {
 		logger.Error("failed to count pcd files for pagination", zap.Error(err))
@@ -147,3 +152,213 @@ func (d *PCDFileDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*en
 	logger.Debug("found pcd files with pagination", zap.Int("count", len(files)), zap.Int64("total", total))
 	return files, total, nil
 }
+
+// CountDependents 统计引用该点云地图的语义地图数与任务数
+func (d *PCDFileDAOImpl) CountDependents(ctx context.Context, id uint) (int64, int64, error) {
+	logger.Debug("counting pcd file dependents", zap.Uint("id", id))
+
+	var semanticMaps, tasks int64
+	db := d.db.WithContext(ctx)
+	if err := db.Model(&entity.SemanticMap{}).Where("pcd_file_id = ?", id).Count(&semanticMaps).Error; err != nil {
+		logger.Error("failed to count semantic maps of pcd file", zap.Error(err), zap.Uint("id", id))
+		return 0, 0, err
+	}
+	if semanticMaps == 0 {
+		return 0, 0, nil
+	}
+	err := db.Model(&entity.Task{}).
+		Where("semantic_map_id IN (?)", db.Model(&entity.SemanticMap{}).Select("id").Where("pcd_file_id = ?", id)).
+		Count(&tasks).Error
+	if err != nil {
+		logger.Error("failed to count tasks of pcd file", zap.Error(err), zap.Uint("id", id))
+		return 0, 0, err
+	}
+
+	return semanticMaps, tasks, nil
+}
+
+// DeleteCascade 在同一事务中软删除点云地图及引用它的语义地图、任务，
+// 未指定 force 时在同一事务内确认没有待执行或执行中的任务
+func (d *PCDFileDAOImpl) DeleteCascade(ctx context.Context, id uint, force bool) error {
+	logger.Info("deleting pcd file with dependents", zap.Uint("id", id), zap.Bool("force", force))
+
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		semanticIDs := tx.Model(&entity.SemanticMap{}).Select("id").Where("pcd_file_id = ?", id)
+		if !force {
+			var active int64
+			err := tx.Model(&entity.Task{}).
+				Where("semantic_map_id IN (?)", semanticIDs).
+				Where("(status IN ? OR status IS NULL)", []entity.TaskStatus{entity.TaskStatusPending, entity.TaskStatusRunning}).
+				Count(&active).Error
+			if err != nil {
+				return err
+			}
+			if active > 0 {
+				return dao.ErrPCDFileHasActiveTasks
+			}
+		}
+		if err := tx.Where("semantic_map_id IN (?)", semanticIDs).Delete(&entity.Task{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("pcd_file_id = ?", id).Delete(&entity.SemanticMap{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entity.PCDFile{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("pcd file not found")
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to delete pcd file with dependents", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+
+	logger.Info("pcd file deleted with dependents successfully", zap.Uint("id", id))
+	return nil
+}
+
+// FindDeletedBefore 查询软删除时间早于 before 且 MinIO 对象尚未清理的点云地图
+func (d *PCDFileDAOImpl) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*entity.PCDFile, error) {
+	logger.Debug("finding deleted pcd files to purge", zap.Time("before", before), zap.Int("limit", limit))
+
+	var files []*entity.PCDFile
+	err := d.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND object_purged_at IS NULL", before).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&files).Error
+	if err != nil {
+		logger.Error("failed to find deleted pcd files", zap.Error(err))
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// MarkObjectPurged 记录点云地图的 MinIO 对象已清理
+func (d *PCDFileDAOImpl) MarkObjectPurged(ctx context.Context, id uint) error {
+	logger.Info("marking pcd file object purged", zap.Uint("id", id))
+
+	err := d.db.WithContext(ctx).Unscoped().Model(&entity.PCDFile{}).
+		Where("id = ?", id).
+		Update("object_purged_at", time.Now()).Error
+	if err != nil {
+		logger.Error("failed to mark pcd file object purged", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+	return nil
+}
+
+// FindAllWithObjects 查询仍持有 MinIO 对象的点云地图（含软删除但未清理的）
+func (d *PCDFileDAOImpl) FindAllWithObjects(ctx context.Context) ([]*entity.PCDFile, error) {
+	logger.Debug("finding pcd files with minio objects")
+
+	var files []*entity.PCDFile
+	err := d.db.WithContext(ctx).Unscoped().
+		Where("minio_path IS NOT NULL AND minio_path <> '' AND object_purged_at IS NULL").
+		Find(&files).Error
+	if err != nil {
+		logger.Error("failed to find pcd files with minio objects", zap.Error(err))
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// CountByMinioPath 统计引用指定 MinIO 对象且未删除的点云地图数量
+func (d *PCDFileDAOImpl) CountByMinioPath(ctx context.Context, minioPath string) (int64, error) {
+	var count int64
+	if err := d.db.WithContext(ctx).Model(&entity.PCDFile{}).Where("minio_path = ?", minioPath).Count(&count).Error; err != nil {
+		logger.Error("failed to count pcd files by minio path", zap.Error(err), zap.String("minioPath", minioPath))
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdatePreview 只更新点云地图的预览字段，避免覆盖并发的其他修改
+func (d *PCDFileDAOImpl) UpdatePreview(ctx context.Context, id uint, preview *entity.PCDPreview) error {
+	logger.Debug("updating pcd file preview", zap.Uint("id", id))
+
+	err := d.db.WithContext(ctx).Model(&entity.PCDFile{}).
+		Where("id = ?", id).
+		Select("preview_status", "preview_path", "thumbnail_path", "preview_points", "preview_leaf").
+		Updates(&entity.PCDFile{PCDPreview: *preview}).Error
+	if err != nil {
+		logger.Error("failed to update pcd file preview", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+	return nil
+}
+
+// UpdateAnalysisStatus 只更新点云地图的解析状态
+func (d *PCDFileDAOImpl) UpdateAnalysisStatus(ctx context.Context, id uint, status entity.PCDJobStatus) error {
+	logger.Debug("updating pcd file analysis status", zap.Uint("id", id), zap.String("status", string(status)))
+
+	err := d.db.WithContext(ctx).Model(&entity.PCDFile{}).
+		Where("id = ?", id).
+		Update("analysis_status", status).Error
+	if err != nil {
+		logger.Error("failed to update pcd file analysis status", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+	return nil
+}
+
+// FindBySHA256 查询内容 SHA-256 相同、对象可用且未删除的最早一个点云地图，已校验为损坏或丢失的对象不参与去重
+func (d *PCDFileDAOImpl) FindBySHA256(ctx context.Context, sha256 string) (*entity.PCDFile, error) {
+	logger.Debug("finding pcd file by sha256", zap.String("sha256", sha256))
+
+	var file entity.PCDFile
+	err := d.db.WithContext(ctx).
+		Where("sha256 = ? AND minio_path IS NOT NULL AND minio_path <> ''", sha256).
+		Where("integrity_status IS NULL OR integrity_status = ?", entity.PCDIntegrityStatusOK).
+		Order("id").
+		First(&file).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to find pcd file by sha256", zap.Error(err), zap.String("sha256", sha256))
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// FindIntegrityDue 查询持有对象且从未校验或上次校验早于 before 的点云地图，从未校验的排在前面
+func (d *PCDFileDAOImpl) FindIntegrityDue(ctx context.Context, before time.Time, limit int) ([]*entity.PCDFile, error) {
+	logger.Debug("finding pcd files due for integrity check", zap.Time("before", before), zap.Int("limit", limit))
+
+	var files []*entity.PCDFile
+	err := d.db.WithContext(ctx).
+		Where("minio_path IS NOT NULL AND minio_path <> ''").
+		Where("integrity_checked_at IS NULL OR integrity_checked_at < ?", before).
+		Order("integrity_checked_at IS NOT NULL, integrity_checked_at, id").
+		Limit(limit).
+		Find(&files).Error
+	if err != nil {
+		logger.Error("failed to find pcd files due for integrity check", zap.Error(err))
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// UpdateIntegrity 只更新点云地图的 SHA-256 与完整性校验字段，避免覆盖并发的其他修改
+func (d *PCDFileDAOImpl) UpdateIntegrity(ctx context.Context, file *entity.PCDFile) error {
+	logger.Debug("updating pcd file integrity", zap.Uint("id", file.ID))
+
+	err := d.db.WithContext(ctx).Model(&entity.PCDFile{}).
+		Where("id = ?", file.ID).
+		Select("sha256", "integrity_status", "integrity_checked_at").
+		Updates(&entity.PCDFile{PCDMetadata: entity.PCDMetadata{SHA256: file.SHA256}, PCDIntegrity: file.PCDIntegrity}).Error
+	if err != nil {
+		logger.Error("failed to update pcd file integrity", zap.Error(err), zap.Uint("id", file.ID))
+		return err
+	}
+	return nil
+}
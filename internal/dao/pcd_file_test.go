@@ -2,8 +2,12 @@ package impl
 
 import (
 	"context"
+	"errors"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/model/entity"
 	"robot_scheduler/internal/testutil"
 	"testing"
+	"time"
 )
 
 func TestPCDFileDAO_Create(t *testing.T) {
@@ -104,14 +108,14 @@ func TestPCDFileDAO_FindPage(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer testutil.TeardownTestDB(db)
 
-	dao := NewPCDFileDAO(db)
+	pcdDAO := NewPCDFileDAO(db)
 	ctx := context.Background()
 
 	for i := 0; i < 5; i++ {
 		testutil.CreateTestPCDFile(t, db, "test"+string(rune('0'+i))+".pcd")
 	}
 
-	files, total, err := dao.FindPage(ctx, 0, 3)
+	files, total, err := pcdDAO.FindPage(ctx, dao.PCDFileFilter{}, 0, 3)
 	if err != nil {
 		t.Fatalf("FindPage failed: %v", err)
 	}
@@ -145,3 +149,197 @@ func TestPCDFileDAO_FindAll(t *testing.T) {
 		t.Errorf("Expected 3 files, got %d", len(files))
 	}
 }
+
+func TestPCDFileDAO_CountDependentsAndDeleteCascade(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	pcdDAO := NewPCDFileDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	other := testutil.CreateTestPCDFile(t, db, "other.pcd")
+	semanticMap := testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
+	testutil.CreateTestTask(t, db, semanticMap.ID)
+	testutil.CreateTestTask(t, db, semanticMap.ID)
+	otherMap := testutil.CreateTestSemanticMap(t, db, other.ID)
+	otherTask := testutil.CreateTestTask(t, db, otherMap.ID)
+
+	semanticMaps, tasks, err := pcdDAO.CountDependents(ctx, pcdFile.ID)
+	if err != nil {
+		t.Fatalf("CountDependents failed: %v", err)
+	}
+	if semanticMaps != 1 || tasks != 2 {
+		t.Errorf("Expected 1 semantic map and 2 tasks, got %d and %d", semanticMaps, tasks)
+	}
+
+	if err := pcdDAO.DeleteCascade(ctx, pcdFile.ID, false); !errors.Is(err, dao.ErrPCDFileHasActiveTasks) {
+		t.Fatalf("Expected ErrPCDFileHasActiveTasks with pending tasks, got %v", err)
+	}
+	if found, _ := pcdDAO.FindByID(ctx, pcdFile.ID); found == nil {
+		t.Fatal("Expected refused cascade deletion to keep the pcd file")
+	}
+
+	if err := pcdDAO.DeleteCascade(ctx, pcdFile.ID, true); err != nil {
+		t.Fatalf("DeleteCascade failed: %v", err)
+	}
+
+	if found, _ := pcdDAO.FindByID(ctx, pcdFile.ID); found != nil {
+		t.Error("Expected pcd file to be deleted")
+	}
+	var remainingTasks int64
+	db.Model(&entity.Task{}).Count(&remainingTasks)
+	if remainingTasks != 1 {
+		t.Errorf("Expected only the unrelated task to remain, got %d", remainingTasks)
+	}
+	var task entity.Task
+	if err := db.First(&task, otherTask.ID).Error; err != nil {
+		t.Errorf("Expected unrelated task to remain: %v", err)
+	}
+}
+
+func TestPCDFileDAO_FindDeletedBeforeAndMarkObjectPurged(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	dao := NewPCDFileDAO(db)
+	ctx := context.Background()
+
+	objectKey := "pcd/test_user/1_test.pcd"
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	pcdFile.MinioPath = &objectKey
+	if err := dao.Update(ctx, pcdFile); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	testutil.CreateTestPCDFile(t, db, "live.pcd")
+
+	if count, _ := dao.CountByMinioPath(ctx, objectKey); count != 1 {
+		t.Errorf("Expected 1 file referencing object, got %d", count)
+	}
+
+	if err := dao.Delete(ctx, pcdFile.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if count, _ := dao.CountByMinioPath(ctx, objectKey); count != 0 {
+		t.Errorf("Expected deleted file to be excluded, got %d", count)
+	}
+
+	files, err := dao.FindDeletedBefore(ctx, time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("FindDeletedBefore failed: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Expected no files past retention, got %d", len(files))
+	}
+
+	files, _ = dao.FindDeletedBefore(ctx, time.Now().Add(time.Hour), 10)
+	if len(files) != 1 || files[0].ID != pcdFile.ID {
+		t.Fatalf("Expected deleted file past retention, got %d", len(files))
+	}
+
+	withObjects, _ := dao.FindAllWithObjects(ctx)
+	if len(withObjects) != 1 {
+		t.Errorf("Expected deleted file to still own its object, got %d", len(withObjects))
+	}
+
+	if err := dao.MarkObjectPurged(ctx, pcdFile.ID); err != nil {
+		t.Fatalf("MarkObjectPurged failed: %v", err)
+	}
+
+	files, _ = dao.FindDeletedBefore(ctx, time.Now().Add(time.Hour), 10)
+	if len(files) != 0 {
+		t.Errorf("Expected purged file to be skipped, got %d", len(files))
+	}
+	withObjects, _ = dao.FindAllWithObjects(ctx)
+	if len(withObjects) != 0 {
+		t.Errorf("Expected purged file to no longer own an object, got %d", len(withObjects))
+	}
+}
+
+func TestPCDFileDAO_UpdatePreview(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	dao := NewPCDFileDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	status := entity.PCDJobStatusSucceeded
+	previewPath := "pcd/test_user/1_test_preview.pcd"
+	points := 100
+	preview := &entity.PCDPreview{PreviewStatus: &status, PreviewPath: &previewPath, PreviewPoints: &points}
+	if err := dao.UpdatePreview(ctx, pcdFile.ID, preview); err != nil {
+		t.Fatalf("UpdatePreview failed: %v", err)
+	}
+
+	found, _ := dao.FindByID(ctx, pcdFile.ID)
+	if found.PreviewStatus == nil || *found.PreviewStatus != entity.PCDJobStatusSucceeded {
+		t.Errorf("Expected preview status succeeded, got %v", found.PreviewStatus)
+	}
+	if found.PreviewPath == nil || *found.PreviewPath != previewPath || found.ThumbnailPath != nil {
+		t.Errorf("Unexpected preview paths %+v", found.PCDPreview)
+	}
+	if found.Name != pcdFile.Name || found.Size != pcdFile.Size {
+		t.Error("Expected other columns to be unchanged")
+	}
+}
+
+func TestPCDFileDAO_SHA256AndIntegrity(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	pcdDAO := NewPCDFileDAO(db)
+	ctx := context.Background()
+
+	sha := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	first := testutil.CreateTestPCDFile(t, db, "first.pcd")
+	second := testutil.CreateTestPCDFile(t, db, "second.pcd")
+	legacy := testutil.CreateTestPCDFile(t, db, "legacy.pcd")
+	for i, f := range []*entity.PCDFile{first, second, legacy} {
+		key := "pcd/test_user/" + f.Name
+		updates := map[string]interface{}{"minio_path": key}
+		if i < 2 {
+			updates["sha256"] = sha
+		}
+		db.Model(f).Updates(updates)
+	}
+
+	found, err := pcdDAO.FindBySHA256(ctx, sha)
+	if err != nil || found == nil || found.ID != first.ID {
+		t.Fatalf("Expected earliest file with the same sha256, got %+v (%v)", found, err)
+	}
+
+	// 校验为损坏的对象不再参与去重
+	corrupted := entity.PCDIntegrityStatusCorrupted
+	checkedAt := time.Now()
+	found.IntegrityStatus, found.IntegrityCheckedAt = &corrupted, &checkedAt
+	if err := pcdDAO.UpdateIntegrity(ctx, found); err != nil {
+		t.Fatalf("UpdateIntegrity failed: %v", err)
+	}
+	if found, _ = pcdDAO.FindBySHA256(ctx, sha); found == nil || found.ID != second.ID {
+		t.Errorf("Expected corrupted file to be skipped, got %+v", found)
+	}
+	if found, _ = pcdDAO.FindBySHA256(ctx, "0000"); found != nil {
+		t.Error("Expected no file for unknown sha256")
+	}
+
+	// 从未校验的排在前面，刚校验过的不在范围内
+	due, err := pcdDAO.FindIntegrityDue(ctx, checkedAt.Add(-time.Hour), 10)
+	if err != nil || len(due) != 2 || due[0].ID != second.ID || due[1].ID != legacy.ID {
+		t.Fatalf("Expected unchecked files due, got %d (%v)", len(due), err)
+	}
+	if due, _ = pcdDAO.FindIntegrityDue(ctx, checkedAt.Add(time.Hour), 10); len(due) != 3 || due[2].ID != first.ID {
+		t.Errorf("Expected checked file last once overdue, got %d", len(due))
+	}
+
+	status := entity.PCDIntegrityStatusCorrupted
+	files, total, err := pcdDAO.FindPage(ctx, dao.PCDFileFilter{IntegrityStatus: &status}, 0, 10)
+	if err != nil || total != 1 || files[0].ID != first.ID {
+		t.Errorf("Expected only the corrupted file, got %d (%v)", total, err)
+	}
+	reloaded, _ := pcdDAO.FindByID(ctx, first.ID)
+	if reloaded.SHA256 == nil || *reloaded.SHA256 != sha || reloaded.Name != first.Name {
+		t.Error("Expected other columns to be unchanged")
+	}
+}
@@ -0,0 +1,134 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PCDJobDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewPCDJobDAO(db *gorm.DB) dao.PCDJobDAO {
+	return &PCDJobDAOImpl{db: db}
+}
+
+func (d *PCDJobDAOImpl) Create(ctx context.Context, job *entity.PCDJob) error {
+	logger.Info("creating pcd job", zap.Uint("pcdFileID", job.PCDFileID), zap.String("type", string(job.Type)))
+
+	if err := d.db.WithContext(ctx).Create(job).Error; err != nil {
+		logger.Error("failed to create pcd job", zap.Error(err), zap.Uint("pcdFileID", job.PCDFileID))
+		return err
+	}
+
+	logger.Info("pcd job created successfully", zap.Uint("id", job.ID))
+	return nil
+}
+
+// FindActive 查询点云地图指定类型未结束（等待或执行中）的任务，不存在时返回 nil
+func (d *PCDJobDAOImpl) FindActive(ctx context.Context, pcdFileID uint, jobType entity.PCDJobType) (*entity.PCDJob, error) {
+	var job entity.PCDJob
+	err := d.db.WithContext(ctx).
+		Where("pcd_file_id = ? AND type = ? AND status IN ?", pcdFileID, jobType,
+			[]entity.PCDJobStatus{entity.PCDJobStatusPending, entity.PCDJobStatusRunning}).
+		Order("id DESC").
+		First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to find active pcd job", zap.Error(err), zap.Uint("pcdFileID", pcdFileID))
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ClaimNext 领取最早的等待任务并置为执行中，没有任务时返回 nil
+func (d *PCDJobDAOImpl) ClaimNext(ctx context.Context) (*entity.PCDJob, error) {
+	db := d.db.WithContext(ctx)
+	for {
+		var job entity.PCDJob
+		err := db.Where("status = ?", entity.PCDJobStatusPending).Order("id ASC").First(&job).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			logger.Error("failed to find pending pcd job", zap.Error(err))
+			return nil, err
+		}
+
+		// 条件更新保证同一任务只被领取一次
+		now := time.Now()
+		result := db.Model(&entity.PCDJob{}).
+			Where("id = ? AND status = ?", job.ID, entity.PCDJobStatusPending).
+			Updates(map[string]interface{}{"status": entity.PCDJobStatusRunning, "started_at": now})
+		if result.Error != nil {
+			logger.Error("failed to claim pcd job", zap.Error(result.Error), zap.Uint("id", job.ID))
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		job.Status = entity.PCDJobStatusRunning
+		job.StartedAt = &now
+		logger.Debug("pcd job claimed", zap.Uint("id", job.ID))
+		return &job, nil
+	}
+}
+
+// Finish 保存任务的结束状态、失败原因与结果
+func (d *PCDJobDAOImpl) Finish(ctx context.Context, job *entity.PCDJob) error {
+	logger.Info("finishing pcd job", zap.Uint("id", job.ID), zap.String("status", string(job.Status)))
+
+	now := time.Now()
+	job.FinishedAt = &now
+	err := d.db.WithContext(ctx).Model(&entity.PCDJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"status":      job.Status,
+			"error":       job.Error,
+			"result":      job.Result,
+			"finished_at": now,
+		}).Error
+	if err != nil {
+		logger.Error("failed to finish pcd job", zap.Error(err), zap.Uint("id", job.ID))
+		return err
+	}
+	return nil
+}
+
+// ListByFile 按创建时间倒序查询点云地图的任务
+func (d *PCDJobDAOImpl) ListByFile(ctx context.Context, pcdFileID uint) ([]*entity.PCDJob, error) {
+	logger.Debug("listing pcd jobs by file", zap.Uint("pcdFileID", pcdFileID))
+
+	var jobs []*entity.PCDJob
+	if err := d.db.WithContext(ctx).Where("pcd_file_id = ?", pcdFileID).Order("id DESC").Find(&jobs).Error; err != nil {
+		logger.Error("failed to list pcd jobs", zap.Error(err), zap.Uint("pcdFileID", pcdFileID))
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ResetRunning 将执行中的任务重置为等待（服务重启后恢复中断的任务），返回重置数量
+func (d *PCDJobDAOImpl) ResetRunning(ctx context.Context) (int64, error) {
+	result := d.db.WithContext(ctx).Model(&entity.PCDJob{}).
+		Where("status = ?", entity.PCDJobStatusRunning).
+		Updates(map[string]interface{}{"status": entity.PCDJobStatusPending, "started_at": nil})
+	if result.Error != nil {
+		logger.Error("failed to reset running pcd jobs", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	if result.RowsAffected > 0 {
+		logger.Info("interrupted pcd jobs reset to pending", zap.Int64("count", result.RowsAffected))
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,100 @@
+package impl
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+)
+
+func TestPCDJobDAO_ClaimNextAndFinish(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	jobDAO := NewPCDJobDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	first := &entity.PCDJob{PCDFileID: pcdFile.ID, Type: entity.PCDJobTypePreview, Status: entity.PCDJobStatusPending}
+	second := &entity.PCDJob{PCDFileID: pcdFile.ID, Type: entity.PCDJobTypePreview, Status: entity.PCDJobStatusPending}
+	for _, job := range []*entity.PCDJob{first, second} {
+		if err := jobDAO.Create(ctx, job); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	claimed, err := jobDAO.ClaimNext(ctx)
+	if err != nil {
+		t.Fatalf("ClaimNext failed: %v", err)
+	}
+	if claimed == nil || claimed.ID != first.ID || claimed.Status != entity.PCDJobStatusRunning || claimed.StartedAt == nil {
+		t.Fatalf("Expected first job to be claimed, got %+v", claimed)
+	}
+
+	msg := "boom"
+	claimed.Status = entity.PCDJobStatusFailed
+	claimed.Error = &msg
+	if err := jobDAO.Finish(ctx, claimed); err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+
+	claimed, _ = jobDAO.ClaimNext(ctx)
+	if claimed == nil || claimed.ID != second.ID {
+		t.Fatalf("Expected second job to be claimed, got %+v", claimed)
+	}
+	if next, _ := jobDAO.ClaimNext(ctx); next != nil {
+		t.Errorf("Expected no pending job, got %+v", next)
+	}
+
+	jobs, err := jobDAO.ListByFile(ctx, pcdFile.ID)
+	if err != nil {
+		t.Fatalf("ListByFile failed: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != second.ID {
+		t.Fatalf("Expected 2 jobs newest first, got %d", len(jobs))
+	}
+	if jobs[1].Status != entity.PCDJobStatusFailed || jobs[1].Error == nil || *jobs[1].Error != "boom" || jobs[1].FinishedAt == nil {
+		t.Errorf("Expected first job to be failed, got %+v", jobs[1])
+	}
+}
+
+func TestPCDJobDAO_FindActiveAndResetRunning(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	jobDAO := NewPCDJobDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	if active, _ := jobDAO.FindActive(ctx, pcdFile.ID, entity.PCDJobTypePreview); active != nil {
+		t.Fatal("Expected no active job")
+	}
+
+	job := &entity.PCDJob{PCDFileID: pcdFile.ID, Type: entity.PCDJobTypePreview, Status: entity.PCDJobStatusPending}
+	if err := jobDAO.Create(ctx, job); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := jobDAO.ClaimNext(ctx); err != nil {
+		t.Fatalf("ClaimNext failed: %v", err)
+	}
+
+	active, err := jobDAO.FindActive(ctx, pcdFile.ID, entity.PCDJobTypePreview)
+	if err != nil {
+		t.Fatalf("FindActive failed: %v", err)
+	}
+	if active == nil || active.ID != job.ID {
+		t.Fatal("Expected running job to be active")
+	}
+
+	count, err := jobDAO.ResetRunning(ctx)
+	if err != nil {
+		t.Fatalf("ResetRunning failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 job reset, got %d", count)
+	}
+	claimed, _ := jobDAO.ClaimNext(ctx)
+	if claimed == nil || claimed.ID != job.ID {
+		t.Error("Expected reset job to be claimable again")
+	}
+}
@@ -0,0 +1,155 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PCDUploadDAOImpl struct {
+	db *gorm.DB
+}
+
+func NewPCDUploadDAO(db *gorm.DB) dao.PCDUploadDAO {
+	return &PCDUploadDAOImpl{db: db}
+}
+
+func (d *PCDUploadDAOImpl) Create(ctx context.Context, upload *entity.PCDUpload) error {
+	logger.Info("creating pcd upload", zap.String("objectKey", upload.ObjectKey))
+
+	if err := d.db.WithContext(ctx).Create(upload).Error; err != nil {
+		logger.Error("failed to create pcd upload", zap.Error(err), zap.String("objectKey", upload.ObjectKey))
+		return err
+	}
+
+	logger.Info("pcd upload created successfully", zap.Uint("id", upload.ID))
+	return nil
+}
+
+// FindByObjectKey 根据对象Key查询上传记录
+func (d *PCDUploadDAOImpl) FindByObjectKey(ctx context.Context, objectKey string) (*entity.PCDUpload, error) {
+	logger.Debug("finding pcd upload by object key", zap.String("objectKey", objectKey))
+
+	var upload entity.PCDUpload
+	err := d.db.WithContext(ctx).Where("object_key = ?", objectKey).First(&upload).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Debug("pcd upload not found", zap.String("objectKey", objectKey))
+			return nil, nil
+		}
+		logger.Error("failed to find pcd upload", zap.Error(err), zap.String("objectKey", objectKey))
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// FindExpiredPending 查询截止时间早于 before 且仍未完成的上传记录
+func (d *PCDUploadDAOImpl) FindExpiredPending(ctx context.Context, before time.Time, limit int) ([]*entity.PCDUpload, error) {
+	logger.Debug("finding expired pending pcd uploads", zap.Time("before", before), zap.Int("limit", limit))
+
+	var uploads []*entity.PCDUpload
+	err := d.db.WithContext(ctx).
+		Where("status = ? AND expire_at < ?", entity.PCDUploadStatusPending, before).
+		Order("expire_at ASC").
+		Limit(limit).
+		Find(&uploads).Error
+	if err != nil {
+		logger.Error("failed to find expired pending pcd uploads", zap.Error(err))
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+// Complete 在同一事务中将上传记录置为已完成并创建点云地图
+func (d *PCDUploadDAOImpl) Complete(ctx context.Context, upload *entity.PCDUpload, file *entity.PCDFile) error {
+	logger.Info("completing pcd upload", zap.Uint("uploadID", upload.ID), zap.String("objectKey", upload.ObjectKey))
+
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(file).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		// 条件更新保证同一上传只能完成一次
+		result := tx.Model(&entity.PCDUpload{}).
+			Where("id = ? AND status = ?", upload.ID, entity.PCDUploadStatusPending).
+			Updates(map[string]interface{}{
+				"status":       entity.PCDUploadStatusCompleted,
+				"completed_at": now,
+				"pcd_file_id":  file.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return dao.ErrPCDUploadNotPending
+		}
+
+		upload.Status = entity.PCDUploadStatusCompleted
+		upload.CompletedAt = &now
+		upload.PCDFileID = &file.ID
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to complete pcd upload", zap.Error(err), zap.Uint("uploadID", upload.ID))
+		return err
+	}
+
+	logger.Info("pcd upload completed successfully", zap.Uint("uploadID", upload.ID), zap.Uint("pcdFileID", file.ID))
+	return nil
+}
+
+// MarkExpired 将未完成的上传记录置为已过期
+func (d *PCDUploadDAOImpl) MarkExpired(ctx context.Context, id uint) error {
+	logger.Info("marking pcd upload expired", zap.Uint("id", id))
+	return d.closePending(ctx, id, entity.PCDUploadStatusExpired)
+}
+
+// MarkAborted 将未完成的上传记录置为已放弃
+func (d *PCDUploadDAOImpl) MarkAborted(ctx context.Context, id uint) error {
+	logger.Info("marking pcd upload aborted", zap.Uint("id", id))
+	return d.closePending(ctx, id, entity.PCDUploadStatusAborted)
+}
+
+// closePending 条件更新未完成上传的状态，已不是 pending 时返回 ErrPCDUploadNotPending
+func (d *PCDUploadDAOImpl) closePending(ctx context.Context, id uint, status entity.PCDUploadStatus) error {
+	result := d.db.WithContext(ctx).Model(&entity.PCDUpload{}).
+		Where("id = ? AND status = ?", id, entity.PCDUploadStatusPending).
+		Update("status", status)
+	if result.Error != nil {
+		logger.Error("failed to update pcd upload status", zap.Error(result.Error), zap.Uint("id", id), zap.String("status", string(status)))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dao.ErrPCDUploadNotPending
+	}
+
+	return nil
+}
+
+// ExtendExpire 延长未完成上传的截止时间
+func (d *PCDUploadDAOImpl) ExtendExpire(ctx context.Context, id uint, expireAt time.Time) error {
+	logger.Debug("extending pcd upload expire time", zap.Uint("id", id), zap.Time("expireAt", expireAt))
+
+	result := d.db.WithContext(ctx).Model(&entity.PCDUpload{}).
+		Where("id = ? AND status = ?", id, entity.PCDUploadStatusPending).
+		Update("expire_at", expireAt)
+	if result.Error != nil {
+		logger.Error("failed to extend pcd upload expire time", zap.Error(result.Error), zap.Uint("id", id))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dao.ErrPCDUploadNotPending
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil"
+	"testing"
+	"time"
+)
+
+func createTestPCDUpload(t *testing.T, uploadDAO dao.PCDUploadDAO, key string, expireAt time.Time) *entity.PCDUpload {
+	t.Helper()
+
+	upload := &entity.PCDUpload{
+		ObjectKey:    key,
+		UserName:     "test_user",
+		FileName:     "test.pcd",
+		DeclaredSize: 1024,
+		Status:       entity.PCDUploadStatusPending,
+		ExpireAt:     expireAt,
+	}
+	if err := uploadDAO.Create(context.Background(), upload); err != nil {
+		t.Fatalf("Create pcd upload failed: %v", err)
+	}
+	return upload
+}
+
+func TestPCDUploadDAO_FindByObjectKey(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	uploadDAO := NewPCDUploadDAO(db)
+	ctx := context.Background()
+
+	upload := createTestPCDUpload(t, uploadDAO, "pcd/test_user/1_a.pcd", time.Now().Add(time.Hour))
+
+	found, err := uploadDAO.FindByObjectKey(ctx, "pcd/test_user/1_a.pcd")
+	if err != nil {
+		t.Fatalf("FindByObjectKey failed: %v", err)
+	}
+	if found == nil || found.ID != upload.ID {
+		t.Fatal("Expected to find pcd upload")
+	}
+
+	missing, err := uploadDAO.FindByObjectKey(ctx, "pcd/test_user/missing.pcd")
+	if err != nil {
+		t.Fatalf("FindByObjectKey failed: %v", err)
+	}
+	if missing != nil {
+		t.Error("Expected nil for unknown object key")
+	}
+}
+
+func TestPCDUploadDAO_Complete(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	uploadDAO := NewPCDUploadDAO(db)
+	pcdDAO := NewPCDFileDAO(db)
+	ctx := context.Background()
+
+	upload := createTestPCDUpload(t, uploadDAO, "pcd/test_user/1_a.pcd", time.Now().Add(time.Hour))
+
+	file := &entity.PCDFile{Name: "map-a", Area: "A", Path: upload.ObjectKey, UserName: "test_user", Size: 1024}
+	if err := uploadDAO.Complete(ctx, upload, file); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if file.ID == 0 || upload.PCDFileID == nil || *upload.PCDFileID != file.ID {
+		t.Fatal("Expected pcd file to be created and linked")
+	}
+
+	found, _ := uploadDAO.FindByObjectKey(ctx, upload.ObjectKey)
+	if found.Status != entity.PCDUploadStatusCompleted || found.CompletedAt == nil {
+		t.Errorf("Expected completed status, got %s", found.Status)
+	}
+
+	// 重复完成应失败且不创建新的点云地图
+	again := &entity.PCDFile{Name: "map-b", Area: "A", Path: upload.ObjectKey, UserName: "test_user"}
+	err := uploadDAO.Complete(ctx, found, again)
+	if !errors.Is(err, dao.ErrPCDUploadNotPending) {
+		t.Fatalf("Expected ErrPCDUploadNotPending, got %v", err)
+	}
+	if existing, _ := pcdDAO.FindByName(ctx, "map-b"); existing != nil {
+		t.Error("Expected pcd file creation to be rolled back")
+	}
+}
+
+func TestPCDUploadDAO_FindExpiredPendingAndMarkExpired(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	uploadDAO := NewPCDUploadDAO(db)
+	ctx := context.Background()
+
+	expired := createTestPCDUpload(t, uploadDAO, "pcd/test_user/1_old.pcd", time.Now().Add(-2*time.Hour))
+	createTestPCDUpload(t, uploadDAO, "pcd/test_user/2_new.pcd", time.Now().Add(time.Hour))
+
+	uploads, err := uploadDAO.FindExpiredPending(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("FindExpiredPending failed: %v", err)
+	}
+	if len(uploads) != 1 || uploads[0].ID != expired.ID {
+		t.Fatalf("Expected 1 expired upload, got %d", len(uploads))
+	}
+
+	if err := uploadDAO.MarkExpired(ctx, expired.ID); err != nil {
+		t.Fatalf("MarkExpired failed: %v", err)
+	}
+	if err := uploadDAO.MarkExpired(ctx, expired.ID); !errors.Is(err, dao.ErrPCDUploadNotPending) {
+		t.Errorf("Expected ErrPCDUploadNotPending on second mark, got %v", err)
+	}
+
+	uploads, _ = uploadDAO.FindExpiredPending(ctx, time.Now(), 10)
+	if len(uploads) != 0 {
+		t.Errorf("Expected no pending expired uploads, got %d", len(uploads))
+	}
+}
+
+func TestPCDUploadDAO_MarkAbortedAndExtendExpire(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	uploadDAO := NewPCDUploadDAO(db)
+	ctx := context.Background()
+
+	upload := createTestPCDUpload(t, uploadDAO, "pcd/test_user/1_big.pcd", time.Now().Add(-time.Hour))
+
+	if err := uploadDAO.ExtendExpire(ctx, upload.ID, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("ExtendExpire failed: %v", err)
+	}
+	uploads, _ := uploadDAO.FindExpiredPending(ctx, time.Now(), 10)
+	if len(uploads) != 0 {
+		t.Errorf("Expected extended upload not to be expired, got %d", len(uploads))
+	}
+
+	if err := uploadDAO.MarkAborted(ctx, upload.ID); err != nil {
+		t.Fatalf("MarkAborted failed: %v", err)
+	}
+	found, _ := uploadDAO.FindByObjectKey(ctx, upload.ObjectKey)
+	if found.Status != entity.PCDUploadStatusAborted {
+		t.Errorf("Expected aborted status, got %s", found.Status)
+	}
+
+	if err := uploadDAO.ExtendExpire(ctx, upload.ID, time.Now().Add(time.Hour)); !errors.Is(err, dao.ErrPCDUploadNotPending) {
+		t.Errorf("Expected ErrPCDUploadNotPending for aborted upload, got %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package impl
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 	dao "robot_scheduler/internal/dao/interfaces"
 
 	"robot_scheduler/internal/logger"
@@ -10,6 +12,7 @@ import (
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SemanticMapDAOImpl struct {
@@ -32,24 +35,94 @@ func (d *SemanticMapDAOImpl) Create(ctx context.Context, semanticMap *entity.Sem
 	return nil
 }
 
-func (d *SemanticMapDAOImpl) Update(ctx context.Context, semanticMap *entity.SemanticMap) error {
-	logger.Info("updating semantic map", zap.Uint("id", semanticMap.ID))
+func (d *SemanticMapDAOImpl) Update(ctx context.Context, semanticMap *entity.SemanticMap, editor string) error {
+	logger.Info("updating semantic map", zap.Uint("id", semanticMap.ID), zap.Int("revision", semanticMap.Revision), zap.String("editor", editor))
 
-	result := d.db.WithContext(ctx).Save(semanticMap)
+	if err := updateSemanticMap(d.db.WithContext(ctx), semanticMap, editor); err != nil {
+		return err
+	}
+
+	logger.Info("semantic map updated successfully", zap.Uint("id", semanticMap.ID), zap.Int("revision", semanticMap.Revision))
+	return nil
+}
+
+// UpdateWithVersions 条件更新语义地图并写入版本，版本号在锁住的地图行上分配
+func (d *SemanticMapDAOImpl) UpdateWithVersions(ctx context.Context, semanticMap *entity.SemanticMap, editor string, versions ...*entity.SemanticMapVersion) error {
+	logger.Info("updating semantic map with versions", zap.Uint("id", semanticMap.ID), zap.Int("revision", semanticMap.Revision), zap.Int("versions", len(versions)))
+
+	expected := semanticMap.Revision
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateSemanticMap(tx, semanticMap, editor); err != nil {
+			return err
+		}
+		for _, version := range versions {
+			version.SemanticMapID = semanticMap.ID
+			if err := createSemanticMapVersion(tx, version); err != nil {
+				logger.Error("failed to create semantic map version", zap.Error(err), zap.Uint("id", semanticMap.ID))
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		semanticMap.Revision = expected
+		return err
+	}
+
+	if len(versions) > 0 {
+		semanticMap.CurrentVersion = &versions[len(versions)-1].Version
+	}
+	logger.Info("semantic map updated with versions successfully", zap.Uint("id", semanticMap.ID), zap.Int("revision", semanticMap.Revision))
+	return nil
+}
+
+// updateSemanticMap 以修订号与编辑锁为条件更新语义地图，成功时递增 semanticMap.Revision
+// 两个条件放在同一条 UPDATE 中，并发编辑或其间他人加锁时只有满足条件的一方成功
+func updateSemanticMap(db *gorm.DB, semanticMap *entity.SemanticMap, editor string) error {
+	expected := semanticMap.Revision
+	semanticMap.Revision = expected + 1
+	now := time.Now()
+	result := db.Model(semanticMap).
+		Where("revision = ?", expected).
+		Where("locked_by IS NULL OR locked_by = ? OR lock_expires_at IS NULL OR lock_expires_at <= ?", editor, now).
+		Select("*").
+		Omit("created_at", "locked_by", "locked_at", "lock_expires_at", "check_severity", "check_report", "checked_at", clause.Associations).
+		Updates(semanticMap)
 	if err := result.Error; err != nil {
+		semanticMap.Revision = expected
 		logger.Error("failed to update semantic map", zap.Error(err), zap.Uint("id", semanticMap.ID))
 		return err
 	}
 
 	if result.RowsAffected == 0 {
-		logger.Warn("semantic map not found for update", zap.Uint("id", semanticMap.ID))
-		return errors.New("semantic map not found")
+		semanticMap.Revision = expected
+		return updateResult(db, semanticMap.ID, expected)
 	}
-
-	logger.Info("semantic map updated successfully", zap.Uint("id", semanticMap.ID))
 	return nil
 }
 
+// updateResult 条件更新未命中时区分地图不存在、修订号已变化与锁被他人持有
+func updateResult(db *gorm.DB, id uint, expected int) error {
+	var current entity.SemanticMap
+	err := db.Select("id", "revision", "locked_by", "lock_expires_at").First(&current, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Warn("semantic map not found for update", zap.Uint("id", id))
+		return errors.New("semantic map not found")
+	}
+	if err != nil {
+		return err
+	}
+	if current.Revision != expected {
+		logger.Warn("semantic map revision conflict", zap.Uint("id", id), zap.Int("revision", expected))
+		return dao.ErrSemanticMapConflict
+	}
+	logger.Warn("semantic map locked by another user", zap.Uint("id", id))
+	if current.LockedBy != nil && current.LockExpiresAt != nil {
+		return fmt.Errorf("%w: %s 持有至 %s", dao.ErrSemanticMapLocked, *current.LockedBy, current.LockExpiresAt.Format(time.RFC3339))
+	}
+	return dao.ErrSemanticMapLocked
+}
+
 func (d *SemanticMapDAOImpl) Delete(ctx context.Context, id uint) error {
 	logger.Info("deleting semantic map", zap.Uint("id", id))
 
@@ -72,7 +145,7 @@ func (d *SemanticMapDAOImpl) FindByID(ctx context.Context, id uint) (*entity.Sem
 	logger.Debug("finding semantic map by id", zap.Uint("id", id))
 
 	var semanticMap entity.SemanticMap
-	err := d.db.WithContext(ctx).Preload("PCDFile").First(&semanticMap, id).Error
+	err := d.db.WithContext(ctx).Preload("PCDFile").Preload("OccupancyGrid").First(&semanticMap, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Debug("semantic map not found", zap.Uint("id", id))
@@ -90,7 +163,7 @@ func (d *SemanticMapDAOImpl) FindAll(ctx context.Context) ([]*entity.SemanticMap
 	logger.Debug("finding all semantic maps")
 
 	var semanticMaps []*entity.SemanticMap
-	err := d.db.WithContext(ctx).Preload("PCDFile").Find(&semanticMaps).Error
+	err := d.db.WithContext(ctx).Preload("PCDFile").Preload("OccupancyGrid").Find(&semanticMaps).Error
 	if err != nil {
 		logger.Error("failed to find all semantic maps", zap.Error(err))
 		return nil, err
@@ -101,7 +174,7 @@ func (d *SemanticMapDAOImpl) FindAll(ctx context.Context) ([]*entity.SemanticMap
 }
 
 // FindPage 分页查询语义地图
-func (d *SemanticMapDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*entity.SemanticMap, int64, error) {
+func (d *SemanticMapDAOImpl) FindPage(ctx context.Context, filter dao.SemanticMapFilter, offset, limit int) ([]*entity.SemanticMap, int64, error) {
 	logger.Debug("finding semantic maps with pagination", zap.Int("offset", offset), zap.Int("limit", limit))
 
 	var (
@@ -109,7 +182,8 @@ func (d *SemanticMapDAOImpl) FindPage(ctx context.Context, offset, limit int) ([
 		total        int64
 	)
 
-	db := d.db.WithContext(ctx).Model(&entity.SemanticMap{}).Preload("PCDFile")
+	db := d.db.WithContext(ctx).Model(&entity.SemanticMap{}).Preload("PCDFile").Preload("OccupancyGrid")
+	db = whereLocation(db, "location_id", filter.LocationID)
 
 	if err := db.Count(&total).Error; err != nil {
 		logger.Error("failed to count semantic maps for pagination", zap.Error(err))
@@ -127,4 +201,88 @@ func (d *SemanticMapDAOImpl) FindPage(ctx context.Context, offset, limit int) ([
 
 	logger.Debug("found semantic maps with pagination", zap.Int("count", len(semanticMaps)), zap.Int64("total", total))
 	return semanticMaps, total, nil
-}
\ No newline at end of file
+}
+
+// UpdateCheck 只更新一致性检查结果，使用 UpdateColumns 避免更新时间晚于检查时间导致被视为过期
+func (d *SemanticMapDAOImpl) UpdateCheck(ctx context.Context, id uint, check *entity.SemanticCheck) error {
+	logger.Debug("updating semantic map check", zap.Uint("id", id))
+
+	err := d.db.WithContext(ctx).Model(&entity.SemanticMap{}).
+		Where("id = ?", id).
+		Select("check_severity", "check_report", "checked_at").
+		UpdateColumns(&entity.SemanticMap{SemanticCheck: *check}).Error
+	if err != nil {
+		logger.Error("failed to update semantic map check", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+	return nil
+}
+
+// FindCheckStale 查询需要重新做一致性检查的语义地图，按ID升序
+func (d *SemanticMapDAOImpl) FindCheckStale(ctx context.Context, limit int) ([]*entity.SemanticMap, error) {
+	logger.Debug("finding semantic maps with stale check", zap.Int("limit", limit))
+
+	var semanticMaps []*entity.SemanticMap
+	err := d.db.WithContext(ctx).
+		Joins("LEFT JOIN pcd_file ON pcd_file.id = semantic_map.pcd_file_id").
+		Joins("LEFT JOIN occupancy_grid ON occupancy_grid.id = semantic_map.occupancy_grid_id").
+		Where("semantic_map.checked_at IS NULL OR semantic_map.checked_at < semantic_map.updated_at OR " +
+			"semantic_map.checked_at < pcd_file.updated_at OR semantic_map.checked_at < occupancy_grid.updated_at").
+		Order("semantic_map.id").
+		Limit(limit).
+		Find(&semanticMaps).Error
+	if err != nil {
+		logger.Error("failed to find semantic maps with stale check", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("found semantic maps with stale check", zap.Int("count", len(semanticMaps)))
+	return semanticMaps, nil
+}
+
+// AcquireLock 条件更新编辑锁：锁为空、已过期或由本人持有时才写入
+func (d *SemanticMapDAOImpl) AcquireLock(ctx context.Context, id uint, userName string, now, expiresAt time.Time) error {
+	logger.Info("acquiring semantic map lock", zap.Uint("id", id), zap.String("userName", userName))
+
+	result := d.db.WithContext(ctx).Model(&entity.SemanticMap{}).
+		Where("id = ?", id).
+		Where("locked_by IS NULL OR locked_by = ? OR lock_expires_at IS NULL OR lock_expires_at <= ?", userName, now).
+		UpdateColumns(map[string]interface{}{"locked_by": userName, "locked_at": now, "lock_expires_at": expiresAt})
+	if err := result.Error; err != nil {
+		logger.Error("failed to acquire semantic map lock", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+	return d.lockResult(ctx, id, result.RowsAffected)
+}
+
+// ReleaseLock 条件清除编辑锁：锁已过期或由本人持有时才清除
+func (d *SemanticMapDAOImpl) ReleaseLock(ctx context.Context, id uint, userName string, now time.Time) error {
+	logger.Info("releasing semantic map lock", zap.Uint("id", id), zap.String("userName", userName))
+
+	result := d.db.WithContext(ctx).Model(&entity.SemanticMap{}).
+		Where("id = ?", id).
+		Where("locked_by IS NULL OR locked_by = ? OR lock_expires_at IS NULL OR lock_expires_at <= ?", userName, now).
+		UpdateColumns(map[string]interface{}{"locked_by": nil, "locked_at": nil, "lock_expires_at": nil})
+	if err := result.Error; err != nil {
+		logger.Error("failed to release semantic map lock", zap.Error(err), zap.Uint("id", id))
+		return err
+	}
+	return d.lockResult(ctx, id, result.RowsAffected)
+}
+
+// lockResult 条件更新未命中时区分地图不存在与锁被他人持有
+func (d *SemanticMapDAOImpl) lockResult(ctx context.Context, id uint, rowsAffected int64) error {
+	if rowsAffected > 0 {
+		return nil
+	}
+	var count int64
+	if err := d.db.WithContext(ctx).Model(&entity.SemanticMap{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		logger.Warn("semantic map not found for lock", zap.Uint("id", id))
+		return errors.New("semantic map not found")
+	}
+	logger.Warn("semantic map locked by another user", zap.Uint("id", id))
+	return dao.ErrSemanticMapLocked
+}
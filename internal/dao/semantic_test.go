@@ -2,8 +2,12 @@ package impl
 
 import (
 	"context"
+	"errors"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/model/entity"
 	"robot_scheduler/internal/testutil"
 	"testing"
+	"time"
 )
 
 func TestSemanticMapDAO_Create(t *testing.T) {
@@ -29,7 +33,7 @@ func TestSemanticMapDAO_Update(t *testing.T) {
 	semanticMap := testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
 	semanticMap.SemanticInfo = "updated_info"
 
-	err := dao.Update(ctx, semanticMap)
+	err := dao.Update(ctx, semanticMap, "test_user")
 	if err != nil {
 		t.Fatalf("Update failed: %v", err)
 	}
@@ -90,7 +94,7 @@ func TestSemanticMapDAO_FindPage(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer testutil.TeardownTestDB(db)
 
-	dao := NewSemanticMapDAO(db)
+	semanticDAO := NewSemanticMapDAO(db)
 	ctx := context.Background()
 
 	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
@@ -98,7 +102,7 @@ func TestSemanticMapDAO_FindPage(t *testing.T) {
 		testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
 	}
 
-	maps, total, err := dao.FindPage(ctx, 0, 3)
+	maps, total, err := semanticDAO.FindPage(ctx, dao.SemanticMapFilter{}, 0, 3)
 	if err != nil {
 		t.Fatalf("FindPage failed: %v", err)
 	}
@@ -133,3 +137,201 @@ func TestSemanticMapDAO_FindAll(t *testing.T) {
 		t.Errorf("Expected 3 maps, got %d", len(maps))
 	}
 }
+
+func TestSemanticMapDAO_CheckStale(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	dao := NewSemanticMapDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	semanticMap := testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
+
+	stale, err := dao.FindCheckStale(ctx, 10)
+	if err != nil {
+		t.Fatalf("FindCheckStale failed: %v", err)
+	}
+	if len(stale) != 1 || stale[0].ID != semanticMap.ID {
+		t.Fatalf("Expected unchecked semantic map to be stale, got %d", len(stale))
+	}
+
+	severity := "ok"
+	report := `{"severity":"ok","issues":[]}`
+	checkedAt := time.Now().Add(time.Second)
+	check := &entity.SemanticCheck{CheckSeverity: &severity, CheckReport: &report, CheckedAt: &checkedAt}
+	if err := dao.UpdateCheck(ctx, semanticMap.ID, check); err != nil {
+		t.Fatalf("UpdateCheck failed: %v", err)
+	}
+
+	found, _ := dao.FindByID(ctx, semanticMap.ID)
+	if found.CheckSeverity == nil || *found.CheckSeverity != "ok" || found.CheckReport == nil {
+		t.Fatalf("Expected check result to be saved, got %+v", found.SemanticCheck)
+	}
+	if !found.UpdatedAt.Equal(semanticMap.UpdatedAt) {
+		t.Error("Expected UpdateCheck not to change updated_at")
+	}
+
+	stale, _ = dao.FindCheckStale(ctx, 10)
+	if len(stale) != 0 {
+		t.Errorf("Expected no stale semantic maps after check, got %d", len(stale))
+	}
+
+	if err := db.Model(found).UpdateColumn("updated_at", checkedAt.Add(time.Second)).Error; err != nil {
+		t.Fatalf("UpdateColumn failed: %v", err)
+	}
+	stale, _ = dao.FindCheckStale(ctx, 10)
+	if len(stale) != 1 {
+		t.Errorf("Expected edited semantic map to be stale, got %d", len(stale))
+	}
+}
+
+func TestSemanticMapDAO_UpdateRevisionConflict(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	semanticDAO := NewSemanticMapDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	semanticMap := testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
+	if semanticMap.Revision != 1 {
+		t.Fatalf("Expected initial revision 1, got %d", semanticMap.Revision)
+	}
+
+	first, _ := semanticDAO.FindByID(ctx, semanticMap.ID)
+	second, _ := semanticDAO.FindByID(ctx, semanticMap.ID)
+
+	first.SemanticInfo = "first"
+	if err := semanticDAO.Update(ctx, first, "test_user"); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if first.Revision != 2 {
+		t.Errorf("Expected revision 2 after update, got %d", first.Revision)
+	}
+
+	second.SemanticInfo = "second"
+	if err := semanticDAO.Update(ctx, second, "test_user"); !errors.Is(err, dao.ErrSemanticMapConflict) {
+		t.Fatalf("Expected ErrSemanticMapConflict, got %v", err)
+	}
+	if second.Revision != 1 {
+		t.Errorf("Expected revision unchanged after conflict, got %d", second.Revision)
+	}
+
+	found, _ := semanticDAO.FindByID(ctx, semanticMap.ID)
+	if found.SemanticInfo != "first" || found.Revision != 2 {
+		t.Errorf("Expected first update to win, got %s at revision %d", found.SemanticInfo, found.Revision)
+	}
+}
+
+func TestSemanticMapDAO_UpdateLocked(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	semanticDAO := NewSemanticMapDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	semanticMap := testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
+	now := time.Now()
+
+	// 读取地图后他人加锁，条件更新仍要拒绝
+	edited, _ := semanticDAO.FindByID(ctx, semanticMap.ID)
+	if err := semanticDAO.AcquireLock(ctx, semanticMap.ID, "alice", now, now.Add(time.Minute)); err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	edited.SemanticInfo = "bob"
+	if err := semanticDAO.Update(ctx, edited, "bob"); !errors.Is(err, dao.ErrSemanticMapLocked) {
+		t.Fatalf("Expected ErrSemanticMapLocked, got %v", err)
+	}
+	if edited.Revision != 1 {
+		t.Errorf("Expected revision unchanged after locked update, got %d", edited.Revision)
+	}
+
+	edited.SemanticInfo = "alice"
+	if err := semanticDAO.Update(ctx, edited, "alice"); err != nil {
+		t.Fatalf("Update by lock holder failed: %v", err)
+	}
+	found, _ := semanticDAO.FindByID(ctx, semanticMap.ID)
+	if found.SemanticInfo != "alice" || found.LockedBy == nil || *found.LockedBy != "alice" {
+		t.Errorf("Expected lock holder's update with lock kept, got %s locked by %v", found.SemanticInfo, found.LockedBy)
+	}
+}
+
+func TestSemanticMapDAO_Lock(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	semanticDAO := NewSemanticMapDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	semanticMap := testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
+	now := time.Now()
+
+	if err := semanticDAO.AcquireLock(ctx, semanticMap.ID, "alice", now, now.Add(time.Minute)); err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	if err := semanticDAO.AcquireLock(ctx, semanticMap.ID, "bob", now, now.Add(time.Minute)); !errors.Is(err, dao.ErrSemanticMapLocked) {
+		t.Fatalf("Expected ErrSemanticMapLocked, got %v", err)
+	}
+	if err := semanticDAO.ReleaseLock(ctx, semanticMap.ID, "bob", now); !errors.Is(err, dao.ErrSemanticMapLocked) {
+		t.Fatalf("Expected ErrSemanticMapLocked on release by other user, got %v", err)
+	}
+
+	// 过期后他人可以接管
+	later := now.Add(2 * time.Minute)
+	if err := semanticDAO.AcquireLock(ctx, semanticMap.ID, "bob", later, later.Add(time.Minute)); err != nil {
+		t.Fatalf("AcquireLock after expiry failed: %v", err)
+	}
+	found, _ := semanticDAO.FindByID(ctx, semanticMap.ID)
+	if found.LockedBy == nil || *found.LockedBy != "bob" || found.Revision != 1 {
+		t.Fatalf("Expected lock held by bob without revision change, got %+v", found.SemanticEditLock)
+	}
+
+	if err := semanticDAO.ReleaseLock(ctx, semanticMap.ID, "bob", later); err != nil {
+		t.Fatalf("ReleaseLock failed: %v", err)
+	}
+	found, _ = semanticDAO.FindByID(ctx, semanticMap.ID)
+	if found.LockedBy != nil {
+		t.Error("Expected lock to be released")
+	}
+}
+
+func TestSemanticMapDAO_UpdateWithVersions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(db)
+
+	semanticDAO := NewSemanticMapDAO(db)
+	ctx := context.Background()
+
+	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
+	semanticMap := testutil.CreateTestSemanticMap(t, db, pcdFile.ID)
+	first, _ := semanticDAO.FindByID(ctx, semanticMap.ID)
+	second, _ := semanticDAO.FindByID(ctx, semanticMap.ID)
+
+	first.SemanticInfo = "first"
+	base := &entity.SemanticMapVersion{SemanticInfo: "test_semantic_info", Author: "test_user"}
+	edited := &entity.SemanticMapVersion{SemanticInfo: "first", Author: "alice"}
+	if err := semanticDAO.UpdateWithVersions(ctx, first, "alice", base, edited); err != nil {
+		t.Fatalf("UpdateWithVersions failed: %v", err)
+	}
+	if base.Version != 1 || edited.Version != 2 || first.CurrentVersion == nil || *first.CurrentVersion != 2 || first.Revision != 2 {
+		t.Fatalf("Expected versions 1 and 2 at revision 2, got %d, %d, %v at %d", base.Version, edited.Version, first.CurrentVersion, first.Revision)
+	}
+
+	// 修订号冲突时整个事务回滚，不留下版本
+	second.SemanticInfo = "second"
+	if err := semanticDAO.UpdateWithVersions(ctx, second, "bob", &entity.SemanticMapVersion{SemanticInfo: "second", Author: "bob"}); !errors.Is(err, dao.ErrSemanticMapConflict) {
+		t.Fatalf("Expected ErrSemanticMapConflict, got %v", err)
+	}
+	var count int64
+	db.Model(&entity.SemanticMapVersion{}).Where("semantic_map_id = ?", semanticMap.ID).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected 2 versions after conflict, got %d", count)
+	}
+	found, _ := semanticDAO.FindByID(ctx, semanticMap.ID)
+	if found.SemanticInfo != "first" || found.CurrentVersion == nil || *found.CurrentVersion != 2 {
+		t.Errorf("Expected first update to remain current, got %s at version %v", found.SemanticInfo, found.CurrentVersion)
+	}
+}
@@ -101,7 +101,7 @@ func (d *TaskDAOImpl) FindAll(ctx context.Context) ([]*entity.Task, error) {
 }
 
 // FindPage 分页查询任务
-func (d *TaskDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*entity.Task, int64, error) {
+func (d *TaskDAOImpl) FindPage(ctx context.Context, filter dao.TaskFilter, offset, limit int) ([]*entity.Task, int64, error) {
 	logger.Debug("finding tasks with pagination", zap.Int("offset", offset), zap.Int("limit", limit))
 
 	var (
@@ -110,6 +110,10 @@ func (d *TaskDAOImpl) FindPage(ctx context.Context, offset, limit int) ([]*entit
 	)
 
 	db := d.db.WithContext(ctx).Model(&entity.Task{}).Preload("SemanticMap.PCDFile")
+	if filter.LocationID != nil {
+		semanticMaps := whereLocation(d.db.Model(&entity.SemanticMap{}).Select("id"), "location_id", filter.LocationID)
+		db = db.Where("semantic_map_id IN (?)", semanticMaps)
+	}
 
 	if err := db.Count(&total).Error; err != nil {
 		logger.Error("failed to count tasks for pagination", zap.Error(err))
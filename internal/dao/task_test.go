@@ -2,6 +2,7 @@ package impl
 
 import (
 	"context"
+	dao "robot_scheduler/internal/dao/interfaces"
 	"robot_scheduler/internal/testutil"
 	"testing"
 )
@@ -95,7 +96,7 @@ func TestTaskDAO_FindPage(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer testutil.TeardownTestDB(db)
 
-	dao := NewTaskDAO(db)
+	taskDAO := NewTaskDAO(db)
 	ctx := context.Background()
 
 	pcdFile := testutil.CreateTestPCDFile(t, db, "test.pcd")
@@ -104,7 +105,7 @@ func TestTaskDAO_FindPage(t *testing.T) {
 		testutil.CreateTestTask(t, db, semanticMap.ID)
 	}
 
-	tasks, total, err := dao.FindPage(ctx, 0, 3)
+	tasks, total, err := taskDAO.FindPage(ctx, dao.TaskFilter{}, 0, 3)
 	if err != nil {
 		t.Fatalf("FindPage failed: %v", err)
 	}
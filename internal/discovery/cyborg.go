@@ -0,0 +1,62 @@
+package discovery
+
+import (
+	"encoding/json"
+	"net"
+
+	"robot_scheduler/internal/model/entity"
+)
+
+func init() {
+	Register(&cyborgProtocol{})
+}
+
+// cyborgProtocol 赛博格设备发现协议（JSON 报文）
+//
+// 探测: {"cmd":"discover","from":"robot-scheduler"}
+// 应答: {"cmd":"announce","vendor":"cyborg","type":"robot_wheel","sn":"...","model":"...","port":8080,"capabilities":[...]}
+type cyborgProtocol struct{}
+
+type cyborgMessage struct {
+	Cmd          string   `json:"cmd"`
+	From         string   `json:"from,omitempty"`
+	Vendor       string   `json:"vendor,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	SN           string   `json:"sn,omitempty"`
+	Model        string   `json:"model,omitempty"`
+	Port         int      `json:"port,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+func (p *cyborgProtocol) Company() entity.CompanyType {
+	return entity.CompanyCyborg
+}
+
+func (p *cyborgProtocol) Probe() []byte {
+	payload, _ := json.Marshal(cyborgMessage{Cmd: "discover", From: "robot-scheduler"})
+	return payload
+}
+
+func (p *cyborgProtocol) Parse(payload []byte, from *net.UDPAddr) (*Announcement, bool) {
+	var msg cyborgMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return nil, false
+	}
+	if msg.Cmd != "announce" || msg.Vendor != string(entity.CompanyCyborg) || msg.SN == "" {
+		return nil, false
+	}
+
+	a := &Announcement{
+		Company:      entity.CompanyCyborg,
+		Type:         entity.DeviceType(msg.Type),
+		SerialNumber: msg.SN,
+		Model:        msg.Model,
+		Port:         msg.Port,
+		Capabilities: msg.Capabilities,
+		Raw:          string(payload),
+	}
+	if from != nil {
+		a.IP = from.IP.String()
+	}
+	return a, true
+}
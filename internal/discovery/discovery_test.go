@@ -0,0 +1,109 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Logger = zap.NewNop()
+}
+
+func TestCyborgProtocol_Parse(t *testing.T) {
+	p := &cyborgProtocol{}
+	from := &net.UDPAddr{IP: net.ParseIP("192.168.1.20"), Port: 19090}
+
+	payload := []byte(`{"cmd":"announce","vendor":"cyborg","type":"robot_wheel","sn":"SN-1","port":8080,"capabilities":["lidar"]}`)
+	a, ok := p.Parse(payload, from)
+	if !ok {
+		t.Fatal("Expected announcement to be parsed")
+	}
+	if a.SerialNumber != "SN-1" || a.IP != "192.168.1.20" || a.Port != 8080 || a.Type != entity.DeviceTypeWheelRobot {
+		t.Errorf("Unexpected announcement: %+v", a)
+	}
+
+	if _, ok := p.Parse([]byte(`{"cmd":"announce","vendor":"other","sn":"SN-1"}`), from); ok {
+		t.Error("Expected foreign vendor to be ignored")
+	}
+	if _, ok := p.Parse([]byte("not json"), from); ok {
+		t.Error("Expected garbage to be ignored")
+	}
+}
+
+func TestScanner_ScanTargets(t *testing.T) {
+	// 模拟一台设备：收到探测报文后回复通告
+	device, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer device.Close()
+
+	go func() {
+		buf := make([]byte, 1024)
+		n, from, err := device.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		var probe cyborgMessage
+		if json.Unmarshal(buf[:n], &probe) != nil || probe.Cmd != "discover" {
+			return
+		}
+		reply, _ := json.Marshal(cyborgMessage{Cmd: "announce", Vendor: "cyborg", Type: "robot_wheel", SN: "SN-LOCAL", Port: 8080})
+		device.WriteToUDP(reply, from)
+	}()
+
+	target := "127.0.0.1:" + strconv.Itoa(device.LocalAddr().(*net.UDPAddr).Port)
+	scanner := NewScanner(Options{Targets: []string{target}, Timeout: 500 * time.Millisecond})
+
+	results, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 response, got %d", len(results))
+	}
+	if results[0].SerialNumber != "SN-LOCAL" || results[0].IP != "127.0.0.1" {
+		t.Errorf("Unexpected response: %+v", results[0])
+	}
+}
+
+func TestRegistry_UpsertAndEvict(t *testing.T) {
+	r := NewRegistry(50 * time.Millisecond)
+
+	a := &Announcement{Company: entity.CompanyCyborg, SerialNumber: "SN-1", IP: "10.0.0.1", Port: 8080}
+	first := r.Upsert(a)
+
+	a2 := *a
+	a2.IP = "10.0.0.2"
+	second := r.Upsert(&a2)
+	if first.Key != second.Key {
+		t.Error("Expected same key for same serial number")
+	}
+
+	list := r.List()
+	if len(list) != 1 || list[0].IP != "10.0.0.2" {
+		t.Fatalf("Expected one refreshed entry, got %+v", list)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if len(r.List()) != 0 {
+		t.Error("Expected entry to be evicted after TTL")
+	}
+}
+
+func TestBroadcastAddr(t *testing.T) {
+	_, ipNet, _ := net.ParseCIDR("192.168.10.37/24")
+	ipNet.IP = net.ParseIP("192.168.10.37")
+
+	if got := broadcastAddr(ipNet).String(); got != "192.168.10.255" {
+		t.Errorf("Expected 192.168.10.255, got %s", got)
+	}
+}
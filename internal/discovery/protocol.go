@@ -0,0 +1,72 @@
+package discovery
+
+import (
+	"net"
+	"sync"
+
+	"robot_scheduler/internal/model/entity"
+)
+
+// Announcement 设备发现应答（主动探测的应答或设备主动广播）
+type Announcement struct {
+	Company      entity.CompanyType `json:"company"`                // 设备厂商
+	Type         entity.DeviceType  `json:"type"`                   // 设备类型
+	SerialNumber string             `json:"serialNumber"`           // 设备序列号
+	Model        string             `json:"model,omitempty"`        // 设备型号
+	IP           string             `json:"ip"`                     // 设备IP
+	Port         int                `json:"port"`                   // 设备服务端口
+	Capabilities []string           `json:"capabilities,omitempty"` // 设备能力
+	Raw          string             `json:"raw,omitempty"`          // 原始报文
+}
+
+// Protocol 厂商发现协议，每个 entity.CompanyType 注册一个实现
+type Protocol interface {
+	// Company 协议对应的厂商
+	Company() entity.CompanyType
+
+	// Probe 构造主动探测报文
+	Probe() []byte
+
+	// Parse 解析应答报文，不属于该厂商的报文返回 false
+	Parse(payload []byte, from *net.UDPAddr) (*Announcement, bool)
+}
+
+var (
+	protocolsMu sync.RWMutex
+	protocols   = map[entity.CompanyType]Protocol{}
+)
+
+// Register 注册厂商发现协议，同一厂商重复注册时覆盖
+func Register(p Protocol) {
+	protocolsMu.Lock()
+	defer protocolsMu.Unlock()
+	protocols[p.Company()] = p
+}
+
+// Protocols 获取已注册的全部发现协议
+func Protocols() []Protocol {
+	protocolsMu.RLock()
+	defer protocolsMu.RUnlock()
+
+	list := make([]Protocol, 0, len(protocols))
+	for _, p := range protocols {
+		list = append(list, p)
+	}
+	return list
+}
+
+// parse 依次尝试各厂商协议解析报文
+func parse(payload []byte, from *net.UDPAddr) (*Announcement, bool) {
+	for _, p := range Protocols() {
+		if a, ok := p.Parse(payload, from); ok {
+			if a.Company == "" {
+				a.Company = p.Company()
+			}
+			if a.IP == "" && from != nil {
+				a.IP = from.IP.String()
+			}
+			return a, true
+		}
+	}
+	return nil, false
+}
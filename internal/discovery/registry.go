@@ -0,0 +1,110 @@
+package discovery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Discovered 已发现设备
+type Discovered struct {
+	Key string `json:"key"` // 发现记录标识，认领时使用
+	Announcement
+	FirstSeen time.Time `json:"firstSeen"` // 首次发现时间
+	LastSeen  time.Time `json:"lastSeen"`  // 最近发现时间
+}
+
+// Registry 已发现设备的内存登记表，超过 TTL 未再次出现的记录会被淘汰
+type Registry struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*Discovered
+}
+
+func NewRegistry(ttl time.Duration) *Registry {
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	return &Registry{
+		ttl:     ttl,
+		entries: make(map[string]*Discovered),
+	}
+}
+
+// Key 计算发现记录标识：有序列号时按厂商+序列号，否则按厂商+地址
+func Key(a *Announcement) string {
+	id := string(a.Company) + "|" + a.SerialNumber
+	if a.SerialNumber == "" {
+		id = string(a.Company) + "|" + a.IP + "|" + strconv.Itoa(a.Port)
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Upsert 登记一条通告
+func (r *Registry) Upsert(a *Announcement) *Discovered {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	key := Key(a)
+	if d, ok := r.entries[key]; ok {
+		d.Announcement = *a
+		d.LastSeen = now
+		return d
+	}
+
+	d := &Discovered{Key: key, Announcement: *a, FirstSeen: now, LastSeen: now}
+	r.entries[key] = d
+	return d
+}
+
+// Get 获取一条发现记录
+func (r *Registry) Get(key string) (*Discovered, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evictLocked()
+	d, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	cp := *d
+	return &cp, true
+}
+
+// Remove 删除一条发现记录（已认领）
+func (r *Registry) Remove(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, key)
+}
+
+// List 按最近发现时间倒序列出全部记录
+func (r *Registry) List() []*Discovered {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evictLocked()
+	list := make([]*Discovered, 0, len(r.entries))
+	for _, d := range r.entries {
+		cp := *d
+		list = append(list, &cp)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LastSeen.After(list[j].LastSeen)
+	})
+	return list
+}
+
+func (r *Registry) evictLocked() {
+	expire := time.Now().Add(-r.ttl)
+	for key, d := range r.entries {
+		if d.LastSeen.Before(expire) {
+			delete(r.entries, key)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"robot_scheduler/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// maxPacketSize UDP 报文最大长度
+const maxPacketSize = 8192
+
+// Options 扫描参数
+type Options struct {
+	Interfaces     []string      // 发送广播的网卡名，为空时使用全部可广播的网卡
+	ProbePort      int           // 设备监听探测报文的端口
+	ListenPort     int           // 被动监听设备主动广播的端口，0 表示不监听
+	MulticastGroup string        // 组播地址（可选），探测报文同时发往该组播地址
+	Targets        []string      // 额外的单播探测地址（host:port），用于广播不可达的网段
+	Timeout        time.Duration // 单次扫描等待应答的时长
+}
+
+// Scanner UDP 广播/组播设备扫描器
+type Scanner struct {
+	opts Options
+}
+
+func NewScanner(opts Options) *Scanner {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 3 * time.Second
+	}
+	return &Scanner{opts: opts}
+}
+
+// Scan 向配置的网卡发送探测报文，在超时前收集全部应答
+func (s *Scanner) Scan(ctx context.Context) ([]*Announcement, error) {
+	protocols := Protocols()
+	if len(protocols) == 0 {
+		return nil, errors.New("no discovery protocol registered")
+	}
+
+	conns, err := s.openProbeConns()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, pc := range conns {
+			pc.conn.Close()
+		}
+	}()
+
+	deadline := time.Now().Add(s.opts.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	var (
+		mu      sync.Mutex
+		results []*Announcement
+		wg      sync.WaitGroup
+	)
+
+	for _, pc := range conns {
+		for _, p := range protocols {
+			probe := p.Probe()
+			for _, dst := range pc.destinations {
+				if _, err := pc.conn.WriteToUDP(probe, dst); err != nil {
+					logger.Warn("failed to send discovery probe",
+						zap.Error(err), zap.String("dst", dst.String()), zap.String("company", string(p.Company())))
+				}
+			}
+		}
+
+		wg.Add(1)
+		go func(conn *net.UDPConn) {
+			defer wg.Done()
+			_ = conn.SetReadDeadline(deadline)
+			buf := make([]byte, maxPacketSize)
+			for {
+				n, from, err := conn.ReadFromUDP(buf)
+				if err != nil {
+					return
+				}
+				if a, ok := parse(append([]byte(nil), buf[:n]...), from); ok {
+					mu.Lock()
+					results = append(results, a)
+					mu.Unlock()
+				}
+			}
+		}(pc.conn)
+	}
+
+	// ctx 取消时提前结束读取
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			for _, pc := range conns {
+				_ = pc.conn.SetReadDeadline(time.Now())
+			}
+		case <-done:
+		}
+	}()
+	wg.Wait()
+	close(done)
+
+	return results, nil
+}
+
+// Listen 被动监听设备主动广播，每收到一条有效通告调用一次 handle，直到 ctx 取消
+func (s *Scanner) Listen(ctx context.Context, handle func(*Announcement)) error {
+	if s.opts.ListenPort <= 0 {
+		return errors.New("discovery listen port not configured")
+	}
+
+	var (
+		conn *net.UDPConn
+		err  error
+	)
+	if s.opts.MulticastGroup != "" {
+		group := net.ParseIP(s.opts.MulticastGroup)
+		if group == nil {
+			return fmt.Errorf("invalid multicast group: %s", s.opts.MulticastGroup)
+		}
+		var ifi *net.Interface
+		if len(s.opts.Interfaces) > 0 {
+			if ifi, err = net.InterfaceByName(s.opts.Interfaces[0]); err != nil {
+				return err
+			}
+		}
+		conn, err = net.ListenMulticastUDP("udp4", ifi, &net.UDPAddr{IP: group, Port: s.opts.ListenPort})
+	} else {
+		conn, err = net.ListenUDP("udp4", &net.UDPAddr{Port: s.opts.ListenPort})
+	}
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if a, ok := parse(append([]byte(nil), buf[:n]...), from); ok {
+			handle(a)
+		}
+	}
+}
+
+// probeConn 探测用的 UDP 连接及其目标地址
+type probeConn struct {
+	conn         *net.UDPConn
+	destinations []*net.UDPAddr
+}
+
+// openProbeConns 为每个网卡地址打开一个绑定到该地址的连接，保证广播从对应网卡发出
+func (s *Scanner) openProbeConns() ([]*probeConn, error) {
+	var conns []*probeConn
+
+	var group *net.UDPAddr
+	if s.opts.MulticastGroup != "" {
+		ip := net.ParseIP(s.opts.MulticastGroup)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid multicast group: %s", s.opts.MulticastGroup)
+		}
+		group = &net.UDPAddr{IP: ip, Port: s.opts.ProbePort}
+	}
+
+	if s.opts.ProbePort > 0 {
+		ifaces, err := s.interfaces()
+		if err != nil {
+			return nil, err
+		}
+		for _, ifi := range ifaces {
+			addrs, err := ifi.Addrs()
+			if err != nil {
+				logger.Warn("failed to read interface addresses", zap.Error(err), zap.String("interface", ifi.Name))
+				continue
+			}
+			for _, addr := range addrs {
+				ipNet, ok := addr.(*net.IPNet)
+				if !ok || ipNet.IP.To4() == nil {
+					continue
+				}
+				conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: ipNet.IP})
+				if err != nil {
+					logger.Warn("failed to open discovery socket", zap.Error(err), zap.String("interface", ifi.Name))
+					continue
+				}
+				pc := &probeConn{conn: conn}
+				pc.destinations = append(pc.destinations, &net.UDPAddr{IP: broadcastAddr(ipNet), Port: s.opts.ProbePort})
+				if group != nil {
+					pc.destinations = append(pc.destinations, group)
+				}
+				conns = append(conns, pc)
+			}
+		}
+	}
+
+	if len(s.opts.Targets) > 0 {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+		if err != nil {
+			return nil, err
+		}
+		pc := &probeConn{conn: conn}
+		for _, target := range s.opts.Targets {
+			dst, err := net.ResolveUDPAddr("udp4", target)
+			if err != nil {
+				logger.Warn("invalid discovery target", zap.Error(err), zap.String("target", target))
+				continue
+			}
+			pc.destinations = append(pc.destinations, dst)
+		}
+		conns = append(conns, pc)
+	}
+
+	if len(conns) == 0 {
+		return nil, errors.New("no usable network interface or target for discovery")
+	}
+	return conns, nil
+}
+
+// interfaces 获取参与广播的网卡
+func (s *Scanner) interfaces() ([]net.Interface, error) {
+	if len(s.opts.Interfaces) > 0 {
+		list := make([]net.Interface, 0, len(s.opts.Interfaces))
+		for _, name := range s.opts.Interfaces {
+			ifi, err := net.InterfaceByName(name)
+			if err != nil {
+				return nil, fmt.Errorf("discovery interface %s: %w", name, err)
+			}
+			list = append(list, *ifi)
+		}
+		return list, nil
+	}
+
+	all, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]net.Interface, 0, len(all))
+	for _, ifi := range all {
+		if ifi.Flags&net.FlagUp == 0 || ifi.Flags&net.FlagBroadcast == 0 || ifi.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		list = append(list, ifi)
+	}
+	return list, nil
+}
+
+// broadcastAddr 计算子网广播地址
+func broadcastAddr(ipNet *net.IPNet) net.IP {
+	ip := ipNet.IP.To4()
+	mask := ipNet.Mask
+	if len(mask) == net.IPv6len {
+		mask = mask[12:]
+	}
+	bc := make(net.IP, net.IPv4len)
+	for i := range bc {
+		bc[i] = ip[i] | ^mask[i]
+	}
+	return bc
+}
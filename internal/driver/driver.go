@@ -0,0 +1,112 @@
+package driver
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Target 部署目标设备的连接信息
+type Target struct {
+	Host     string // 设备IP或主机名
+	Port     int    // 设备服务端口，HTTP 驱动使用，为 0 时取 80
+	UserName string // 登录用户名
+	Password string // 登录密码
+	Options  Options
+}
+
+// Options 驱动通用参数
+type Options struct {
+	Timeout    time.Duration // 建立连接与等待响应的超时，不限制传输时长
+	HTTPS      bool          // HTTP 驱动使用 https，否则密码以明文 Basic 认证发送
+	CAFile     string        // https 校验设备证书的 CA 文件(PEM)，为空时使用系统根证书
+	SFTPPort   int           // SFTP 端口，为 0 时取 22
+	RemoteDir  string        // SFTP 上传的根目录，地图包放在其下以包名命名的目录中
+	KnownHosts string        // SSH known_hosts 文件
+	HostKey    string        // SSH 主机公钥(authorized_keys 格式)，与 KnownHosts 至少配置一项
+}
+
+// File 地图包中的一个文件，Open 在传输时才打开，调用方负责关闭
+type File struct {
+	Name string
+	Size int64
+	Open func(ctx context.Context) (io.ReadCloser, error)
+}
+
+// Package 下发到设备的地图包，Files 按顺序上传，最后一个文件写入完成即表示地图包完整
+type Package struct {
+	Name  string
+	Files []File
+}
+
+// Size 地图包总字节数
+func (p *Package) Size() int64 {
+	var total int64
+	for _, f := range p.Files {
+		total += f.Size
+	}
+	return total
+}
+
+// ProgressFunc 上传进度回调，参数为已发送的累计字节数
+type ProgressFunc func(sent int64)
+
+// Driver 厂商地图下发驱动，按设备型号的 driver_name 选择
+type Driver interface {
+	// Name 驱动名称，与 device_model.driver_name 对应
+	Name() string
+
+	// Deploy 把地图包上传到设备，progress 可能在传输过程中被频繁调用
+	Deploy(ctx context.Context, target Target, pkg *Package, progress ProgressFunc) error
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]Driver{}
+)
+
+// Register 注册驱动，同名驱动重复注册时覆盖
+func Register(d Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	drivers[d.Name()] = d
+}
+
+// Lookup 按名称查找驱动
+func Lookup(name string) (Driver, bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	d, ok := drivers[name]
+	return d, ok
+}
+
+// Names 已注册的驱动名称，按字母序
+func Names() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// progressReader 统计读取的字节数并回调累计进度
+type progressReader struct {
+	r        io.Reader
+	sent     *atomic.Int64
+	progress ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 && p.progress != nil {
+		p.progress(p.sent.Add(int64(n)))
+	}
+	return n, err
+}
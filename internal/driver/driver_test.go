@@ -0,0 +1,135 @@
+package driver
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testPackage(files map[string]string, order ...string) *Package {
+	pkg := &Package{Name: "semantic_1_v2"}
+	for _, name := range order {
+		data := files[name]
+		pkg.Files = append(pkg.Files, File{
+			Name: name,
+			Size: int64(len(data)),
+			Open: func(ctx context.Context) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(data)), nil
+			},
+		})
+	}
+	return pkg
+}
+
+func testTarget(t *testing.T, server *httptest.Server) Target {
+	t.Helper()
+	host, portStr, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	return Target{Host: host, Port: port}
+}
+
+func TestRegistry(t *testing.T) {
+	for _, name := range []string{"http", "cyborg", "sftp"} {
+		if d, ok := Lookup(name); !ok || d.Name() != name {
+			t.Errorf("Expected driver %q to be registered", name)
+		}
+	}
+	if _, ok := Lookup("unknown"); ok {
+		t.Error("Expected unknown driver to be missing")
+	}
+}
+
+func TestHTTPDriver_Deploy(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		io.Copy(io.Discard, r.Body)
+		if strings.HasSuffix(r.URL.Path, "bad.bin") {
+			http.Error(w, "disk full", http.StatusInsufficientStorage)
+		}
+	}))
+	defer server.Close()
+
+	d, _ := Lookup("cyborg")
+	files := map[string]string{"map.pcd": "0123456789", "manifest.json": "{}", "bad.bin": "x"}
+	var last int64
+	err := d.Deploy(context.Background(), testTarget(t, server), testPackage(files, "map.pcd", "manifest.json"), func(sent int64) { last = sent })
+	if err != nil {
+		t.Fatalf("Deploy failed: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/api/v1/maps/semantic_1_v2/map.pcd" || paths[1] != "/api/v1/maps/semantic_1_v2/manifest.json" {
+		t.Errorf("Unexpected upload paths %v", paths)
+	}
+	if last != 12 {
+		t.Errorf("Expected progress 12, got %d", last)
+	}
+
+	// 任一文件失败即停止，后续文件不再上传
+	paths = nil
+	err = d.Deploy(context.Background(), testTarget(t, server), testPackage(files, "bad.bin", "manifest.json"), nil)
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Expected device error to be reported, got %v", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("Expected upload to stop after failure, got %v", paths)
+	}
+}
+
+func TestHTTPDriver_HTTPS(t *testing.T) {
+	var user, pass string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ = r.BasicAuth()
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatalf("write ca file: %v", err)
+	}
+
+	d, _ := Lookup("http")
+	target := testTarget(t, server)
+	target.UserName, target.Password = "robot", "pw"
+	pkg := testPackage(map[string]string{"manifest.json": "{}"}, "manifest.json")
+
+	// 未信任设备证书时拒绝连接
+	target.Options.HTTPS = true
+	if err := d.Deploy(context.Background(), target, pkg, nil); err == nil {
+		t.Error("Expected untrusted certificate to be rejected")
+	}
+
+	target.Options.CAFile = caFile
+	if err := d.Deploy(context.Background(), target, pkg, nil); err != nil {
+		t.Fatalf("Deploy over https failed: %v", err)
+	}
+	if user != "robot" || pass != "pw" {
+		t.Errorf("Expected basic auth over https, got %q/%q", user, pass)
+	}
+}
+
+func TestSFTPDriver_RequiresHostKey(t *testing.T) {
+	d, _ := Lookup("sftp")
+	// 未配置主机密钥时在连接前就拒绝，不会把密码发给未经校验的主机
+	err := d.Deploy(context.Background(), Target{Host: "127.0.0.1", Port: 1, UserName: "robot", Password: "pw"}, &Package{Name: "p"}, nil)
+	if !errors.Is(err, ErrHostKeyNotConfigured) {
+		t.Errorf("Expected ErrHostKeyNotConfigured, got %v", err)
+	}
+
+	if _, err := hostKeyCallback(Options{HostKey: "not a key"}); err == nil {
+		t.Error("Expected invalid host key to be rejected")
+	}
+	if _, err := hostKeyCallback(Options{HostKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGM2jRDpvmKgNlXKVVNFX4ic5itMvrGzN7cE6hmqRTUS"}); err != nil {
+		t.Errorf("Expected pinned host key to be accepted, got %v", err)
+	}
+}
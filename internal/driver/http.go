@@ -0,0 +1,121 @@
+package driver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"robot_scheduler/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register(&httpDriver{name: "http", basePath: "/maps"})
+	// 赛博格设备的地图接口：PUT /api/v1/maps/{包名}/{文件名}，Basic 认证
+	Register(&httpDriver{name: "cyborg", basePath: "/api/v1/maps"})
+}
+
+// httpDriver 通过 HTTP PUT 逐个上传文件：PUT {scheme}://{host}:{port}{basePath}/{包名}/{文件名}，
+// 配置了用户名时使用 Basic 认证，任一文件返回非 2xx 即失败。未启用 https 时密码以明文发送
+type httpDriver struct {
+	name     string
+	basePath string
+}
+
+func (d *httpDriver) Name() string {
+	return d.name
+}
+
+func (d *httpDriver) Deploy(ctx context.Context, target Target, pkg *Package, progress ProgressFunc) error {
+	scheme, port := "http", target.Port
+	if target.Options.HTTPS {
+		scheme = "https"
+	}
+	if port == 0 {
+		port = 80
+		if target.Options.HTTPS {
+			port = 443
+		}
+	}
+	base := fmt.Sprintf("%s://%s%s/%s/", scheme, net.JoinHostPort(target.Host, strconv.Itoa(port)), d.basePath, url.PathEscape(pkg.Name))
+	if !target.Options.HTTPS && target.Password != "" {
+		logger.Warn("sending device credentials over plaintext http, enable deploy.https", zap.String("driver", d.name), zap.String("host", target.Host))
+	}
+
+	// 大文件传输时长不可预估，只限制连接与等待响应的时间
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if target.Options.HTTPS && target.Options.CAFile != "" {
+		pool, err := loadCAFile(target.Options.CAFile)
+		if err != nil {
+			return err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	if target.Options.Timeout > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: target.Options.Timeout}).DialContext
+		transport.ResponseHeaderTimeout = target.Options.Timeout
+	}
+	client := &http.Client{Transport: transport}
+	defer transport.CloseIdleConnections()
+
+	var sent atomic.Int64
+	for _, f := range pkg.Files {
+		if err := d.put(ctx, client, base+url.PathEscape(f.Name), target, f, &sent, progress); err != nil {
+			return fmt.Errorf("上传 %s 失败: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func (d *httpDriver) put(ctx context.Context, client *http.Client, u string, target Target, f File, sent *atomic.Int64, progress ProgressFunc) error {
+	body, err := f.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, &progressReader{r: body, sent: sent, progress: progress})
+	if err != nil {
+		return err
+	}
+	req.ContentLength = f.Size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if target.UserName != "" {
+		req.SetBasicAuth(target.UserName, target.Password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("设备返回 %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// loadCAFile 读取校验设备证书的 CA 文件
+func loadCAFile(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取 CA 文件失败: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("CA 文件中没有有效的 PEM 证书")
+	}
+	return pool, nil
+}
@@ -0,0 +1,134 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// ErrHostKeyNotConfigured 未配置 known_hosts 或主机公钥，无法确认设备身份
+var ErrHostKeyNotConfigured = errors.New("ssh host key not configured")
+
+func init() {
+	Register(&sftpDriver{})
+}
+
+// sftpDriver 通过 SFTP 上传到 {RemoteDir}/{包名}/，每个文件先写入 .part 临时文件再改名，
+// 设备侧看到的文件总是完整的。必须校验主机密钥，否则中间人可以截获设备密码
+type sftpDriver struct{}
+
+func (d *sftpDriver) Name() string {
+	return "sftp"
+}
+
+func (d *sftpDriver) Deploy(ctx context.Context, target Target, pkg *Package, progress ProgressFunc) error {
+	hostKey, err := hostKeyCallback(target.Options)
+	if err != nil {
+		return err
+	}
+	port := target.Options.SFTPPort
+	if port == 0 {
+		port = 22
+	}
+
+	conn, err := ssh.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(port)), &ssh.ClientConfig{
+		User:            target.UserName,
+		Auth:            []ssh.AuthMethod{ssh.Password(target.Password)},
+		HostKeyCallback: hostKey,
+		Timeout:         target.Options.Timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("连接设备失败: %w", err)
+	}
+	defer conn.Close()
+
+	// 取消时关闭连接，中断进行中的传输
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	dir := path.Join(target.Options.RemoteDir, pkg.Name)
+	if err := client.MkdirAll(dir); err != nil {
+		return fmt.Errorf("创建目录 %s 失败: %w", dir, err)
+	}
+
+	var sent atomic.Int64
+	for _, f := range pkg.Files {
+		if err := d.put(ctx, client, path.Join(dir, f.Name), f, &sent, progress); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("上传 %s 失败: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// hostKeyCallback 按配置的主机公钥或 known_hosts 校验设备，两者都未配置时拒绝连接
+func hostKeyCallback(opts Options) (ssh.HostKeyCallback, error) {
+	if opts.HostKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(opts.HostKey))
+		if err != nil {
+			return nil, fmt.Errorf("解析主机公钥失败: %w", err)
+		}
+		return ssh.FixedHostKey(key), nil
+	}
+	if opts.KnownHosts != "" {
+		callback, err := knownhosts.New(opts.KnownHosts)
+		if err != nil {
+			return nil, fmt.Errorf("读取 known_hosts 失败: %w", err)
+		}
+		return callback, nil
+	}
+	return nil, ErrHostKeyNotConfigured
+}
+
+func (d *sftpDriver) put(ctx context.Context, client *sftp.Client, remote string, f File, sent *atomic.Int64, progress ProgressFunc) error {
+	body, err := f.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	tmp := remote + ".part"
+	w, err := client.Create(tmp)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(w, &progressReader{r: body, sent: sent, progress: progress})
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written != f.Size {
+		err = fmt.Errorf("size mismatch: expected %d bytes, wrote %d", f.Size, written)
+	}
+	if err != nil {
+		client.Remove(tmp)
+		return err
+	}
+
+	// 优先使用可覆盖的 posix-rename 扩展，不支持时先删除旧文件
+	if err := client.PosixRename(tmp, remote); err != nil {
+		var status *sftp.StatusError
+		if !errors.As(err, &status) {
+			return err
+		}
+		client.Remove(remote)
+		return client.Rename(tmp, remote)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package frame
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrNoTransform 两个坐标系之间没有可用的变换链
+var ErrNoTransform = errors.New("no transform between frames")
+
+// PCDFrame 点云地图的坐标系名称
+func PCDFrame(id uint) string {
+	return fmt.Sprintf("pcd/%d", id)
+}
+
+// GridFrame 二维栅格地图的坐标系名称
+func GridFrame(id uint) string {
+	return fmt.Sprintf("grid/%d", id)
+}
+
+// MapFrame 以点云地图或二维栅格地图为底图的数据所在的坐标系，二者都为空时返回空字符串
+func MapFrame(pcdFileID, gridID *uint) string {
+	switch {
+	case gridID != nil:
+		return GridFrame(*gridID)
+	case pcdFileID != nil:
+		return PCDFrame(*pcdFileID)
+	default:
+		return ""
+	}
+}
+
+// Transform 刚体变换，把子坐标系中的坐标变换到父坐标系：p_parent = R(roll, pitch, yaw) * p_child + (X, Y, Z)。
+// 旋转按 Z-Y-X 顺序（先绕 X 轴 roll，再绕 Y 轴 pitch，最后绕 Z 轴 yaw），二维变换只需 X、Y、Yaw
+type Transform struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Z     float64 `json:"z"`
+	Roll  float64 `json:"roll"`
+	Pitch float64 `json:"pitch"`
+	Yaw   float64 `json:"yaw"`
+}
+
+// Pose 位姿，Yaw 为水平面内的朝向
+type Pose struct {
+	X   float64 `json:"x"`
+	Y   float64 `json:"y"`
+	Z   float64 `json:"z"`
+	Yaw float64 `json:"yaw"`
+}
+
+type matrix [3][3]float64
+
+func (t Transform) rotation() matrix {
+	sr, cr := math.Sincos(t.Roll)
+	sp, cp := math.Sincos(t.Pitch)
+	sy, cy := math.Sincos(t.Yaw)
+	return matrix{
+		{cy * cp, cy*sp*sr - sy*cr, cy*sp*cr + sy*sr},
+		{sy * cp, sy*sp*sr + cy*cr, sy*sp*cr - cy*sr},
+		{-sp, cp * sr, cp * cr},
+	}
+}
+
+func (m matrix) mul(o matrix) matrix {
+	var r matrix
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = m[i][0]*o[0][j] + m[i][1]*o[1][j] + m[i][2]*o[2][j]
+		}
+	}
+	return r
+}
+
+func (m matrix) apply(x, y, z float64) (float64, float64, float64) {
+	return m[0][0]*x + m[0][1]*y + m[0][2]*z,
+		m[1][0]*x + m[1][1]*y + m[1][2]*z,
+		m[2][0]*x + m[2][1]*y + m[2][2]*z
+}
+
+func (m matrix) transpose() matrix {
+	var r matrix
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = m[j][i]
+		}
+	}
+	return r
+}
+
+// fromMatrix 由旋转矩阵与平移构建变换，pitch 为 ±90° 时 roll 取 0
+func fromMatrix(m matrix, x, y, z float64) Transform {
+	t := Transform{X: x, Y: y, Z: z}
+	sp := math.Max(-1, math.Min(1, -m[2][0]))
+	t.Pitch = math.Asin(sp)
+	if math.Abs(sp) < 1-1e-12 {
+		t.Roll = math.Atan2(m[2][1], m[2][2])
+		t.Yaw = math.Atan2(m[1][0], m[0][0])
+	} else {
+		t.Yaw = math.Atan2(-m[0][1], m[1][1])
+	}
+	return t
+}
+
+// Compose 返回先做 u 再做 t 的变换
+func (t Transform) Compose(u Transform) Transform {
+	rt := t.rotation()
+	x, y, z := rt.apply(u.X, u.Y, u.Z)
+	return fromMatrix(rt.mul(u.rotation()), x+t.X, y+t.Y, z+t.Z)
+}
+
+// Inverse 返回逆变换，即把父坐标系中的坐标变换到子坐标系
+func (t Transform) Inverse() Transform {
+	rt := t.rotation().transpose()
+	x, y, z := rt.apply(-t.X, -t.Y, -t.Z)
+	return fromMatrix(rt, x, y, z)
+}
+
+// Is2D 是否为平面内的变换（无 Z 平移与倾斜）
+func (t Transform) Is2D() bool {
+	return t.Z == 0 && t.Roll == 0 && t.Pitch == 0
+}
+
+// Apply 变换位姿，朝向取变换后航向向量在水平面上的投影
+func (t Transform) Apply(p Pose) Pose {
+	r := t.rotation()
+	x, y, z := r.apply(p.X, p.Y, p.Z)
+	sin, cos := math.Sincos(p.Yaw)
+	hx, hy, _ := r.apply(cos, sin, 0)
+	return Pose{X: x + t.X, Y: y + t.Y, Z: z + t.Z, Yaw: math.Atan2(hy, hx)}
+}
+
+// Edge 父子坐标系之间的一条已知变换
+type Edge struct {
+	Parent    string
+	Child     string
+	Transform Transform
+}
+
+type link struct {
+	to        string
+	transform Transform // 当前坐标系到 to 的变换
+}
+
+// Graph 由已知变换组成的坐标系图，变换可沿正反两个方向使用
+type Graph struct {
+	links map[string][]link
+}
+
+// NewGraph 由已知变换构建坐标系图
+func NewGraph(edges []Edge) *Graph {
+	g := &Graph{links: make(map[string][]link)}
+	for _, e := range edges {
+		g.links[e.Child] = append(g.links[e.Child], link{to: e.Parent, transform: e.Transform})
+		g.links[e.Parent] = append(g.links[e.Parent], link{to: e.Child, transform: e.Transform.Inverse()})
+	}
+	return g
+}
+
+// Resolve 返回把 from 中的坐标变换到 to 的变换，按经过坐标系最少的链路组合，不连通时返回 ErrNoTransform
+func (g *Graph) Resolve(from, to string) (Transform, error) {
+	if from == to {
+		return Transform{}, nil
+	}
+	acc := map[string]Transform{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, l := range g.links[current] {
+			if _, seen := acc[l.to]; seen {
+				continue
+			}
+			acc[l.to] = l.transform.Compose(acc[current])
+			if l.to == to {
+				return acc[l.to], nil
+			}
+			queue = append(queue, l.to)
+		}
+	}
+	return Transform{}, fmt.Errorf("%w: %s -> %s", ErrNoTransform, from, to)
+}
+
+// Frames 按名称排序返回图中出现的全部坐标系
+func (g *Graph) Frames() []string {
+	frames := make([]string, 0, len(g.links))
+	for f := range g.links {
+		frames = append(frames, f)
+	}
+	sort.Strings(frames)
+	return frames
+}
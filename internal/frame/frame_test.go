@@ -0,0 +1,68 @@
+package frame
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func nearPose(a, b Pose) bool {
+	return near(a.X, b.X) && near(a.Y, b.Y) && near(a.Z, b.Z) && near(math.Remainder(a.Yaw-b.Yaw, 2*math.Pi), 0)
+}
+
+func TestTransform_ApplyComposeInverse(t *testing.T) {
+	// 二维：子坐标系原点位于父坐标系 (10, 5)，旋转 90°
+	t2 := Transform{X: 10, Y: 5, Yaw: math.Pi / 2}
+	got := t2.Apply(Pose{X: 1, Y: 0, Yaw: 0})
+	if want := (Pose{X: 10, Y: 6, Yaw: math.Pi / 2}); !nearPose(got, want) {
+		t.Fatalf("Apply = %+v, want %+v", got, want)
+	}
+	if back := t2.Inverse().Apply(got); !nearPose(back, Pose{X: 1}) {
+		t.Errorf("Inverse round trip = %+v", back)
+	}
+
+	t3 := Transform{X: 1, Y: -2, Z: 0.5, Roll: 0.1, Pitch: -0.2, Yaw: 0.7}
+	p := Pose{X: 3, Y: 4, Z: 1, Yaw: 0.3}
+	composed := t3.Compose(t2)
+	if got, want := composed.Apply(p), t3.Apply(t2.Apply(p)); !nearPose(got, want) {
+		t.Errorf("Compose = %+v, want %+v", got, want)
+	}
+	if id := t3.Compose(t3.Inverse()); !near(id.X, 0) || !near(id.Y, 0) || !near(id.Z, 0) || !near(id.Yaw, 0) || !near(id.Roll, 0) || !near(id.Pitch, 0) {
+		t.Errorf("t * t^-1 = %+v, want identity", id)
+	}
+	if t3.Is2D() || !t2.Is2D() {
+		t.Error("Unexpected Is2D result")
+	}
+}
+
+func TestGraph_Resolve(t *testing.T) {
+	// building <- pcd/1, building <- grid/2，两张地图经楼宇坐标系相互换算
+	g := NewGraph([]Edge{
+		{Parent: "building/A", Child: PCDFrame(1), Transform: Transform{X: 100, Y: 50}},
+		{Parent: "building/A", Child: GridFrame(2), Transform: Transform{X: 90, Y: 50, Yaw: math.Pi}},
+		{Parent: "building/B", Child: "pcd/9"},
+	})
+
+	tf, err := g.Resolve(PCDFrame(1), GridFrame(2))
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	// pcd/1 中的 (1, 0) 在楼宇中为 (101, 50)，在 grid/2 中为 (-11, 0) 且朝向反转
+	if got, want := tf.Apply(Pose{X: 1, Yaw: 0}), (Pose{X: -11, Yaw: math.Pi}); !nearPose(got, want) {
+		t.Errorf("Apply = %+v, want %+v", got, want)
+	}
+
+	if tf, err := g.Resolve("x", "x"); err != nil || tf != (Transform{}) {
+		t.Errorf("Expected identity for same frame, got %+v (%v)", tf, err)
+	}
+	if _, err := g.Resolve(PCDFrame(1), "pcd/9"); !errors.Is(err, ErrNoTransform) {
+		t.Errorf("Expected ErrNoTransform, got %v", err)
+	}
+	if frames := g.Frames(); len(frames) != 5 || frames[0] != "building/A" {
+		t.Errorf("Unexpected frames %v", frames)
+	}
+}
@@ -6,26 +6,40 @@ import (
 )
 
 // DeviceCreateRequest 创建设备请求
+// 类型/厂商须在设备型号目录中登记；指定 modelId 时类型、厂商可省略，端口默认取型号默认端口
 type DeviceCreateRequest struct {
-	Type      entity.DeviceType  `json:"type" binding:"required"`    // 设备类型
-	Company   entity.CompanyType `json:"company" binding:"required"` // 设备厂商
-	IP        *string            `json:"ip,omitempty"`               // 设备IP
-	Port      int                `json:"port" binding:"required"`    // 设备端口
-	UserName  *string            `json:"userName,omitempty"`         // 登录用户名
-	Password  *string            `json:"password,omitempty"`         // 登录密码
-	ExtraInfo *string            `json:"extraInfo,omitempty"`        // 扩展信息
+	ModelID    *uint              `json:"modelId,omitempty"`                        // 设备型号ID
+	Type       entity.DeviceType  `json:"type"`                                     // 设备类型
+	Company    entity.CompanyType `json:"company"`                                  // 设备厂商
+	IP         *string            `json:"ip,omitempty"`                             // 设备IP
+	Port       int                `json:"port" binding:"omitempty,min=1,max=65535"` // 设备端口
+	UserName   *string            `json:"userName,omitempty"`                       // 登录用户名
+	Password   *string            `json:"password,omitempty"`                       // 登录密码
+	ExtraInfo  *string            `json:"extraInfo,omitempty"`                      // 扩展信息
+	LocationID *uint              `json:"locationId,omitempty"`                     // 所在位置节点ID
 }
 
 // DeviceUpdateRequest 更新设备请求
 type DeviceUpdateRequest struct {
-	Type      *entity.DeviceType   `json:"type,omitempty"`      // 设备类型
-	Company   *entity.CompanyType  `json:"company,omitempty"`   // 设备厂商
-	IP        *string              `json:"ip,omitempty"`        // 设备IP
-	Port      *int                 `json:"port,omitempty"`      // 设备端口
-	UserName  *string              `json:"userName,omitempty"`  // 登录用户名
-	Password  *string              `json:"password,omitempty"`  // 登录密码
-	Status    *entity.DeviceStatus `json:"status,omitempty"`    // 设备状态
-	ExtraInfo *string              `json:"extraInfo,omitempty"` // 扩展信息
+	ModelID    *uint                `json:"modelId,omitempty"`    // 设备型号ID
+	Type       *entity.DeviceType   `json:"type,omitempty"`       // 设备类型
+	Company    *entity.CompanyType  `json:"company,omitempty"`    // 设备厂商
+	IP         *string              `json:"ip,omitempty"`         // 设备IP
+	Port       *int                 `json:"port,omitempty"`       // 设备端口
+	UserName   *string              `json:"userName,omitempty"`   // 登录用户名
+	Password   *string              `json:"password,omitempty"`   // 登录密码
+	Status     *entity.DeviceStatus `json:"status,omitempty"`     // 设备状态
+	ExtraInfo  *string              `json:"extraInfo,omitempty"`  // 扩展信息
+	LocationID *uint                `json:"locationId,omitempty"` // 所在位置节点ID，为 0 时解除挂载
+}
+
+// DeviceListRequest 设备查询请求
+type DeviceListRequest struct {
+	PageRequest
+	LocationID *uint `form:"locationId"` // 位置节点ID，包含其全部下级节点
+
+	LoadedSemanticMapID      *uint `form:"loadedSemanticMapId"`      // 已加载的语义地图ID
+	LoadedSemanticMapVersion *int  `form:"loadedSemanticMapVersion"` // 已加载的语义地图版本号，需同时指定 loadedSemanticMapId
 }
 
 // DeviceResponse 设备响应
@@ -40,6 +54,17 @@ type DeviceResponse struct {
 	CreateTime *time.Time           `json:"createTime"`          // 创建时间
 	UpdateTime *time.Time           `json:"updateTime"`          // 更新时间
 	ExtraInfo  *string              `json:"extraInfo,omitempty"` // 扩展信息
+
+	SerialNumber    *string    `json:"serialNumber,omitempty"`    // 设备序列号
+	Capabilities    *string    `json:"capabilities,omitempty"`    // 设备能力(JSON数组)
+	LastHeartbeatAt *time.Time `json:"lastHeartbeatAt,omitempty"` // 最近心跳时间
+	ModelID         *uint      `json:"modelId,omitempty"`         // 设备型号ID
+	LocationID      *uint      `json:"locationId,omitempty"`      // 所在位置节点ID
+
+	LoadedSemanticMapID      *uint      `json:"loadedSemanticMapId,omitempty"`      // 已加载的语义地图ID
+	LoadedSemanticMapVersion *int       `json:"loadedSemanticMapVersion,omitempty"` // 已加载的语义地图版本号
+	LoadedDeploymentID       *uint      `json:"loadedDeploymentId,omitempty"`       // 加载该地图的下发记录ID
+	MapLoadedAt              *time.Time `json:"mapLoadedAt,omitempty"`              // 地图加载时间
 }
 
 // DeviceListResponse 设备列表响应
@@ -64,6 +89,17 @@ func NewDeviceResponseFromEntity(d *entity.Device) *DeviceResponse {
 		CreateTime: &d.CreatedAt,
 		UpdateTime: &d.UpdatedAt,
 		ExtraInfo:  d.ExtraInfo,
+
+		SerialNumber:    d.SerialNumber,
+		Capabilities:    d.Capabilities,
+		LastHeartbeatAt: d.LastHeartbeatAt,
+		ModelID:         d.ModelID,
+		LocationID:      d.LocationID,
+
+		LoadedSemanticMapID:      d.LoadedSemanticMapID,
+		LoadedSemanticMapVersion: d.LoadedSemanticMapVersion,
+		LoadedDeploymentID:       d.LoadedDeploymentID,
+		MapLoadedAt:              d.MapLoadedAt,
 	}
 }
 
@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// DeviceAlarmReport 设备上报的单条告警
+type DeviceAlarmReport struct {
+	Code      string               `json:"code" binding:"required"`                                 // 厂商错误码
+	Severity  entity.AlarmSeverity `json:"severity" binding:"required,oneof=info warning critical"` // 告警级别
+	Message   string               `json:"message"`                                                 // 告警信息
+	Pose      *DevicePose          `json:"pose,omitempty"`                                          // 告警时位姿
+	Cleared   bool                 `json:"cleared"`                                                 // true 表示该错误码已恢复
+	ExtraInfo *string              `json:"extraInfo,omitempty"`                                     // 扩展信息(JSON)
+}
+
+// DeviceAlarmReportRequest 设备告警上报请求
+type DeviceAlarmReportRequest struct {
+	Alarms []DeviceAlarmReport `json:"alarms" binding:"required,min=1,dive"` // 告警列表
+}
+
+// DeviceAlarmListRequest 设备告警查询请求
+type DeviceAlarmListRequest struct {
+	PageRequest
+	DeviceID     *uint                 `form:"deviceId"`                                                 // 设备ID
+	Severity     *entity.AlarmSeverity `form:"severity" binding:"omitempty,oneof=info warning critical"` // 告警级别
+	Code         *string               `form:"code"`                                                     // 错误码
+	Active       *bool                 `form:"active"`                                                   // true: 未解除; false: 已解除
+	Acknowledged *bool                 `form:"acknowledged"`                                             // 是否已确认
+	StartTime    *time.Time            `form:"startTime" time_format:"2006-01-02T15:04:05Z07:00"`        // 产生时间下限
+	EndTime      *time.Time            `form:"endTime" time_format:"2006-01-02T15:04:05Z07:00"`          // 产生时间上限
+}
+
+// DeviceAlarmResponse 设备告警响应
+type DeviceAlarmResponse struct {
+	ID             uint                 `json:"id"`                       // 告警ID
+	DeviceID       uint                 `json:"deviceId"`                 // 设备ID
+	Code           string               `json:"code"`                     // 厂商错误码
+	Severity       entity.AlarmSeverity `json:"severity"`                 // 告警级别
+	Message        string               `json:"message"`                  // 告警信息
+	Pose           *DevicePose          `json:"pose,omitempty"`           // 告警时位姿
+	RaisedAt       time.Time            `json:"raisedAt"`                 // 产生时间
+	ClearedAt      *time.Time           `json:"clearedAt,omitempty"`      // 解除时间
+	Active         bool                 `json:"active"`                   // 是否未解除
+	AcknowledgedBy *string              `json:"acknowledgedBy,omitempty"` // 确认人员
+	AcknowledgedAt *time.Time           `json:"acknowledgedAt,omitempty"` // 确认时间
+	ExtraInfo      *string              `json:"extraInfo,omitempty"`      // 扩展信息
+}
+
+// DeviceAlarmListResponse 设备告警列表响应
+type DeviceAlarmListResponse struct {
+	PageResponse
+	List []*DeviceAlarmResponse `json:"list"` // 告警列表
+}
+
+// NewDeviceAlarmResponseFromEntity 从实体对象构建告警响应
+func NewDeviceAlarmResponseFromEntity(a *entity.DeviceAlarm) *DeviceAlarmResponse {
+	if a == nil {
+		return nil
+	}
+	resp := &DeviceAlarmResponse{
+		ID:             a.ID,
+		DeviceID:       a.DeviceID,
+		Code:           a.Code,
+		Severity:       a.Severity,
+		Message:        a.Message,
+		RaisedAt:       a.RaisedAt,
+		ClearedAt:      a.ClearedAt,
+		Active:         a.ClearedAt == nil,
+		AcknowledgedBy: a.AcknowledgedBy,
+		AcknowledgedAt: a.AcknowledgedAt,
+		ExtraInfo:      a.ExtraInfo,
+	}
+	if a.PoseX != nil && a.PoseY != nil {
+		resp.Pose = &DevicePose{X: *a.PoseX, Y: *a.PoseY}
+		if a.PoseYaw != nil {
+			resp.Pose.Yaw = *a.PoseYaw
+		}
+	}
+	return resp
+}
+
+// NewDeviceAlarmListResponseFromEntities 从实体列表构建告警列表响应
+func NewDeviceAlarmListResponseFromEntities(list []*entity.DeviceAlarm, page PageResponse) *DeviceAlarmListResponse {
+	resp := &DeviceAlarmListResponse{
+		PageResponse: page,
+		List:         make([]*DeviceAlarmResponse, 0, len(list)),
+	}
+	for _, a := range list {
+		resp.List = append(resp.List, NewDeviceAlarmResponseFromEntity(a))
+	}
+	return resp
+}
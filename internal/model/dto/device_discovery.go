@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// DiscoveredDeviceResponse 已发现但未注册的设备
+type DiscoveredDeviceResponse struct {
+	Key          string             `json:"key"`                    // 发现记录标识，认领时使用
+	Company      entity.CompanyType `json:"company"`                // 设备厂商
+	Type         entity.DeviceType  `json:"type"`                   // 设备类型
+	SerialNumber string             `json:"serialNumber,omitempty"` // 设备序列号
+	Model        string             `json:"model,omitempty"`        // 设备型号
+	IP           string             `json:"ip"`                     // 设备IP
+	Port         int                `json:"port"`                   // 设备端口
+	Capabilities []string           `json:"capabilities,omitempty"` // 设备能力
+	FirstSeen    time.Time          `json:"firstSeen"`              // 首次发现时间
+	LastSeen     time.Time          `json:"lastSeen"`               // 最近发现时间
+}
+
+// DeviceAdoptRequest 认领已发现设备请求，未填写的字段使用设备应答中的值
+type DeviceAdoptRequest struct {
+	ModelID   *uint              `json:"modelId,omitempty"`                                  // 设备型号ID，为空时按应答中的型号名称或类型匹配
+	Type      *entity.DeviceType `json:"type,omitempty"`                                     // 设备类型
+	Port      *int               `json:"port,omitempty" binding:"omitempty,min=1,max=65535"` // 设备端口
+	UserName  *string            `json:"userName,omitempty"`                                 // 登录用户名
+	Password  *string            `json:"password,omitempty"`                                 // 登录密码
+	ExtraInfo *string            `json:"extraInfo,omitempty"`                                // 扩展信息
+}
@@ -0,0 +1,55 @@
+package dto
+
+import "robot_scheduler/internal/model/entity"
+
+// DeviceImportRow 设备导入行（JSON 导入时为数组元素，CSV 导入时按表头映射）
+type DeviceImportRow struct {
+	Model        *string            `json:"model,omitempty"`        // 型号名称（可选，须在设备型号目录中登记）
+	Type         entity.DeviceType  `json:"type"`                   // 设备类型
+	Company      entity.CompanyType `json:"company"`                // 设备厂商
+	SerialNumber *string            `json:"serialNumber,omitempty"` // 设备序列号
+	IP           *string            `json:"ip,omitempty"`           // 设备IP
+	Port         int                `json:"port"`                   // 设备端口
+	UserName     *string            `json:"userName,omitempty"`     // 登录用户名
+	Password     *string            `json:"password,omitempty"`     // 登录密码
+	Capabilities []string           `json:"capabilities,omitempty"` // 设备能力（CSV 中以分号分隔）
+	ExtraInfo    *string            `json:"extraInfo,omitempty"`    // 扩展信息
+	InvalidPort  string             `json:"-"`                      // CSV 中无法解析为数字的端口原文，校验时报错
+}
+
+// DeviceImportRowResult 单行导入结果
+type DeviceImportRowResult struct {
+	Row          int                `json:"row"`                    // 行号（从1开始，不含表头）
+	Type         entity.DeviceType  `json:"type"`                   // 设备类型
+	Company      entity.CompanyType `json:"company"`                // 设备厂商
+	SerialNumber *string            `json:"serialNumber,omitempty"` // 设备序列号
+	IP           *string            `json:"ip,omitempty"`           // 设备IP
+	Port         int                `json:"port"`                   // 设备端口
+	Valid        bool               `json:"valid"`                  // 是否通过校验
+	Errors       []string           `json:"errors,omitempty"`       // 校验错误
+	DeviceID     uint               `json:"deviceId,omitempty"`     // 导入成功后的设备ID
+}
+
+// DeviceImportResponse 设备导入报告
+type DeviceImportResponse struct {
+	DryRun   bool                     `json:"dryRun"`   // 是否仅预览
+	Total    int                      `json:"total"`    // 总行数
+	Valid    int                      `json:"valid"`    // 校验通过行数
+	Invalid  int                      `json:"invalid"`  // 校验失败行数
+	Imported bool                     `json:"imported"` // 是否已写入（存在失败行时整批不写入）
+	Rows     []*DeviceImportRowResult `json:"rows"`     // 逐行结果
+}
+
+// DeviceExportItem 设备导出记录（不含登录用户名、密码及设备凭证）
+type DeviceExportItem struct {
+	ID           uint                 `json:"id"`                     // 设备ID
+	Model        *string              `json:"model,omitempty"`        // 型号名称
+	Type         entity.DeviceType    `json:"type"`                   // 设备类型
+	Company      entity.CompanyType   `json:"company"`                // 设备厂商
+	SerialNumber *string              `json:"serialNumber,omitempty"` // 设备序列号
+	IP           *string              `json:"ip,omitempty"`           // 设备IP
+	Port         int                  `json:"port"`                   // 设备端口
+	Status       *entity.DeviceStatus `json:"status,omitempty"`       // 设备状态
+	Capabilities []string             `json:"capabilities,omitempty"` // 设备能力
+	ExtraInfo    *string              `json:"extraInfo,omitempty"`    // 扩展信息
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// DeviceModelCreateRequest 创建设备型号请求
+type DeviceModelCreateRequest struct {
+	Company      entity.CompanyType `json:"company" binding:"required"`                      // 设备厂商
+	Name         string             `json:"name" binding:"required"`                         // 型号名称
+	Type         entity.DeviceType  `json:"type" binding:"required"`                         // 设备类型
+	Capabilities []string           `json:"capabilities,omitempty"`                          // 默认设备能力
+	DefaultPort  int                `json:"defaultPort" binding:"omitempty,min=1,max=65535"` // 默认端口
+	DriverName   string             `json:"driverName"`                                      // 驱动名称
+	Enabled      *bool              `json:"enabled,omitempty"`                               // 是否启用，默认启用
+	Description  *string            `json:"description,omitempty"`                           // 描述
+}
+
+// DeviceModelUpdateRequest 更新设备型号请求
+type DeviceModelUpdateRequest struct {
+	Name         *string            `json:"name,omitempty"`                                            // 型号名称
+	Type         *entity.DeviceType `json:"type,omitempty"`                                            // 设备类型
+	Capabilities []string           `json:"capabilities,omitempty"`                                    // 默认设备能力
+	DefaultPort  *int               `json:"defaultPort,omitempty" binding:"omitempty,min=0,max=65535"` // 默认端口，0 表示无默认值
+	DriverName   *string            `json:"driverName,omitempty"`                                      // 驱动名称
+	Enabled      *bool              `json:"enabled,omitempty"`                                         // 是否启用
+	Description  *string            `json:"description,omitempty"`                                     // 描述
+}
+
+// DeviceModelListRequest 设备型号查询请求
+type DeviceModelListRequest struct {
+	PageRequest
+	Company *entity.CompanyType `form:"company"` // 设备厂商
+	Type    *entity.DeviceType  `form:"type"`    // 设备类型
+	Enabled *bool               `form:"enabled"` // 是否启用
+}
+
+// DeviceModelResponse 设备型号响应
+type DeviceModelResponse struct {
+	ID           uint               `json:"id"`                     // 型号ID
+	Company      entity.CompanyType `json:"company"`                // 设备厂商
+	Name         string             `json:"name"`                   // 型号名称
+	Type         entity.DeviceType  `json:"type"`                   // 设备类型
+	Capabilities *string            `json:"capabilities,omitempty"` // 默认设备能力(JSON数组)
+	DefaultPort  int                `json:"defaultPort"`            // 默认端口
+	DriverName   string             `json:"driverName"`             // 驱动名称
+	Enabled      bool               `json:"enabled"`                // 是否启用
+	Description  *string            `json:"description,omitempty"`  // 描述
+	CreateTime   *time.Time         `json:"createTime"`             // 创建时间
+	UpdateTime   *time.Time         `json:"updateTime"`             // 更新时间
+}
+
+// DeviceModelListResponse 设备型号列表响应
+type DeviceModelListResponse struct {
+	PageResponse
+	List []*DeviceModelResponse `json:"list"` // 型号列表
+}
+
+// NewDeviceModelResponseFromEntity 从实体对象构建设备型号响应
+func NewDeviceModelResponseFromEntity(m *entity.DeviceModel) *DeviceModelResponse {
+	if m == nil {
+		return nil
+	}
+	return &DeviceModelResponse{
+		ID:           m.ID,
+		Company:      m.Company,
+		Name:         m.Name,
+		Type:         m.Type,
+		Capabilities: m.Capabilities,
+		DefaultPort:  m.DefaultPort,
+		DriverName:   m.DriverName,
+		Enabled:      m.Enabled,
+		Description:  m.Description,
+		CreateTime:   &m.CreatedAt,
+		UpdateTime:   &m.UpdatedAt,
+	}
+}
+
+// NewDeviceModelListResponseFromEntities 从实体列表构建设备型号列表响应
+func NewDeviceModelListResponseFromEntities(list []*entity.DeviceModel, page PageResponse) *DeviceModelListResponse {
+	resp := &DeviceModelListResponse{
+		PageResponse: page,
+		List:         make([]*DeviceModelResponse, 0, len(list)),
+	}
+	for _, m := range list {
+		resp.List = append(resp.List, NewDeviceModelResponseFromEntity(m))
+	}
+	return resp
+}
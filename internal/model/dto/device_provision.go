@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// DeviceEnrollmentCodeCreateRequest 创建设备注册码请求
+type DeviceEnrollmentCodeCreateRequest struct {
+	ExpireMinutes int     `json:"expireMinutes" binding:"omitempty,min=1,max=10080"` // 有效期(分钟)，默认60
+	Remark        *string `json:"remark,omitempty"`                                  // 备注
+}
+
+// DeviceEnrollmentCodeResponse 创建设备注册码响应（注册码明文只返回这一次）
+type DeviceEnrollmentCodeResponse struct {
+	ID       uint      `json:"id"`               // 注册码ID
+	Code     string    `json:"code"`             // 注册码明文
+	ExpireAt time.Time `json:"expireAt"`         // 过期时间
+	Remark   *string   `json:"remark,omitempty"` // 备注
+}
+
+// DeviceEnrollRequest 设备注册请求（由机器人调用）
+type DeviceEnrollRequest struct {
+	Code         string             `json:"code" binding:"required"`                  // 注册码
+	Type         entity.DeviceType  `json:"type" binding:"required"`                  // 设备类型
+	Company      entity.CompanyType `json:"company" binding:"required"`               // 设备厂商
+	SerialNumber string             `json:"serialNumber" binding:"required"`          // 设备序列号
+	Model        string             `json:"model,omitempty"`                          // 型号名称（可选，须在设备型号目录中登记）
+	Capabilities []string           `json:"capabilities,omitempty"`                   // 设备能力
+	IP           *string            `json:"ip,omitempty"`                             // 设备IP，为空时使用请求来源IP
+	Port         int                `json:"port" binding:"omitempty,min=1,max=65535"` // 设备端口
+	ExtraInfo    *string            `json:"extraInfo,omitempty"`                      // 扩展信息
+	Token        *string            `json:"token,omitempty"`                          // 重新注册已有序列号时携带该设备当前的凭证
+}
+
+// DeviceEnrollResponse 设备注册响应（凭证明文只返回这一次）
+type DeviceEnrollResponse struct {
+	DeviceID uint   `json:"deviceId"` // 设备ID
+	Token    string `json:"token"`    // 设备凭证，后续请求放在 Authorization: Device <token>
+}
+
+// DeviceHeartbeatRequest 设备心跳请求
+type DeviceHeartbeatRequest struct {
+	Status *entity.DeviceStatus `json:"status,omitempty" binding:"omitempty,oneof=offline online busy error"` // 设备自报状态，为空视为在线
+}
+
+// DevicePose 设备位姿
+type DevicePose struct {
+	X   float64  `json:"x"`           // X(米)
+	Y   float64  `json:"y"`           // Y(米)
+	Z   *float64 `json:"z,omitempty"` // Z(米)
+	Yaw float64  `json:"yaw"`         // 航向角(弧度)
+
+	Frame string `json:"frame,omitempty" binding:"omitempty,max=128"` // 位姿所在坐标系，如 pcd/1、grid/2
+}
+
+// DeviceTelemetryRequest 设备遥测上报请求
+type DeviceTelemetryRequest struct {
+	Status  *entity.DeviceStatus `json:"status,omitempty" binding:"omitempty,oneof=offline online busy error"` // 设备状态
+	Pose    *DevicePose          `json:"pose,omitempty"`                                                       // 当前位姿
+	Battery *float64             `json:"battery,omitempty" binding:"omitempty,min=0,max=100"`                  // 电量百分比
+	Payload *string              `json:"payload,omitempty"`                                                    // 原始遥测数据(JSON)
+	Alarms  []DeviceAlarmReport  `json:"alarms,omitempty" binding:"omitempty,dive"`                            // 随遥测上报的告警
+}
+
+// DeviceTelemetryResponse 设备遥测记录响应
+type DeviceTelemetryResponse struct {
+	ID         uint                 `json:"id"`                // 记录ID
+	DeviceID   uint                 `json:"deviceId"`          // 设备ID
+	Status     *entity.DeviceStatus `json:"status,omitempty"`  // 设备状态
+	Pose       *DevicePose          `json:"pose,omitempty"`    // 位姿
+	Battery    *float64             `json:"battery,omitempty"` // 电量百分比
+	Payload    *string              `json:"payload,omitempty"` // 原始遥测数据
+	CreateTime *time.Time           `json:"createTime"`        // 上报时间
+}
+
+// DeviceTelemetryListResponse 设备遥测记录列表响应
+type DeviceTelemetryListResponse struct {
+	PageResponse
+	List []*DeviceTelemetryResponse `json:"list"` // 遥测记录列表
+}
+
+// NewDeviceTelemetryResponseFromEntity 从实体对象构建遥测记录响应
+func NewDeviceTelemetryResponseFromEntity(t *entity.DeviceTelemetry) *DeviceTelemetryResponse {
+	if t == nil {
+		return nil
+	}
+	resp := &DeviceTelemetryResponse{
+		ID:         t.ID,
+		DeviceID:   t.DeviceID,
+		Status:     t.Status,
+		Battery:    t.Battery,
+		Payload:    t.Payload,
+		CreateTime: t.CreateTime,
+	}
+	if t.PoseX != nil && t.PoseY != nil {
+		resp.Pose = &DevicePose{X: *t.PoseX, Y: *t.PoseY, Z: t.PoseZ}
+		if t.PoseYaw != nil {
+			resp.Pose.Yaw = *t.PoseYaw
+		}
+		if t.PoseFrame != nil {
+			resp.Pose.Frame = *t.PoseFrame
+		}
+	}
+	return resp
+}
+
+// NewDeviceTelemetryListResponseFromEntities 从实体列表构建遥测记录列表响应
+func NewDeviceTelemetryListResponseFromEntities(list []*entity.DeviceTelemetry, page PageResponse) *DeviceTelemetryListResponse {
+	resp := &DeviceTelemetryListResponse{
+		PageResponse: page,
+		List:         make([]*DeviceTelemetryResponse, 0, len(list)),
+	}
+	for _, t := range list {
+		resp.List = append(resp.List, NewDeviceTelemetryResponseFromEntity(t))
+	}
+	return resp
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// FrameTransformRequest 创建或更新坐标系变换请求，变换把子坐标系中的坐标换算到父坐标系
+type FrameTransformRequest struct {
+	ParentFrame string  `json:"parentFrame" binding:"required,max=128"` // 父坐标系，如 building/A
+	ChildFrame  string  `json:"childFrame" binding:"required,max=128"`  // 子坐标系，如 pcd/1、grid/2
+	X           float64 `json:"x"`                                      // 平移X(米)
+	Y           float64 `json:"y"`                                      // 平移Y(米)
+	Z           float64 `json:"z"`                                      // 平移Z(米)
+	Roll        float64 `json:"roll"`                                   // 绕X轴旋转(弧度)
+	Pitch       float64 `json:"pitch"`                                  // 绕Y轴旋转(弧度)
+	Yaw         float64 `json:"yaw"`                                    // 绕Z轴旋转(弧度)
+	Description *string `json:"description,omitempty"`                  // 说明
+}
+
+// FrameTransformResponse 坐标系变换响应
+type FrameTransformResponse struct {
+	ID          uint            `json:"id"`                    // 变换ID
+	ParentFrame string          `json:"parentFrame"`           // 父坐标系
+	ChildFrame  string          `json:"childFrame"`            // 子坐标系
+	Transform   frame.Transform `json:"transform"`             // 子坐标系到父坐标系的变换
+	UserName    string          `json:"userName"`              // 创建人员
+	Description *string         `json:"description,omitempty"` // 说明
+	CreateTime  *time.Time      `json:"createTime"`            // 创建时间
+	UpdateTime  *time.Time      `json:"updateTime"`            // 更新时间
+}
+
+// FrameConvertRequest 位姿坐标换算请求
+type FrameConvertRequest struct {
+	From  string       `json:"from" binding:"required"`                  // 位姿当前所在坐标系
+	To    string       `json:"to" binding:"required"`                    // 目标坐标系
+	Poses []frame.Pose `json:"poses" binding:"required,min=1,max=10000"` // 待换算的位姿
+}
+
+// FrameConvertResponse 位姿坐标换算响应
+type FrameConvertResponse struct {
+	From      string          `json:"from"`      // 源坐标系
+	To        string          `json:"to"`        // 目标坐标系
+	Transform frame.Transform `json:"transform"` // 源坐标系到目标坐标系的组合变换
+	Poses     []frame.Pose    `json:"poses"`     // 换算后的位姿，顺序与请求一致
+}
+
+// NewFrameTransformResponseFromEntity 从实体对象构建坐标系变换响应
+func NewFrameTransformResponseFromEntity(t *entity.FrameTransform) *FrameTransformResponse {
+	if t == nil {
+		return nil
+	}
+	return &FrameTransformResponse{
+		ID:          t.ID,
+		ParentFrame: t.ParentFrame,
+		ChildFrame:  t.ChildFrame,
+		Transform:   frame.Transform{X: t.X, Y: t.Y, Z: t.Z, Roll: t.Roll, Pitch: t.Pitch, Yaw: t.Yaw},
+		UserName:    t.UserName,
+		Description: t.Description,
+		CreateTime:  &t.CreatedAt,
+		UpdateTime:  &t.UpdatedAt,
+	}
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// LocationCreateRequest 创建位置节点请求，园区没有上级节点，楼栋、楼层、区域的上级分别为园区、楼栋、楼层
+type LocationCreateRequest struct {
+	ParentID    *uint                `json:"parentId,omitempty"`                                      // 上级节点ID
+	Level       entity.LocationLevel `json:"level" binding:"required,oneof=site building floor area"` // 层级
+	Name        string               `json:"name" binding:"required,min=1,max=100"`                   // 名称，同一上级下不可重复
+	Description *string              `json:"description,omitempty"`                                   // 说明
+}
+
+// LocationUpdateRequest 更新位置节点请求，层级不可修改
+type LocationUpdateRequest struct {
+	ParentID    *uint   `json:"parentId,omitempty"`                               // 移动到新的上级节点，须为同一层级的上级
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"` // 名称
+	Description *string `json:"description,omitempty"`                            // 说明
+}
+
+// LocationListRequest 位置节点查询请求
+type LocationListRequest struct {
+	ParentID *uint                 `form:"parentId"`                                                 // 只返回该节点的直接下级
+	Level    *entity.LocationLevel `form:"level" binding:"omitempty,oneof=site building floor area"` // 层级
+}
+
+// LocationTreeRequest 位置树查询请求
+type LocationTreeRequest struct {
+	RootID *uint `form:"rootId"` // 只返回以该节点为根的子树，默认返回全部园区
+}
+
+// LocationBrief 位置节点摘要
+type LocationBrief struct {
+	ID    uint                 `json:"id"`    // 节点ID
+	Level entity.LocationLevel `json:"level"` // 层级
+	Name  string               `json:"name"`  // 名称
+}
+
+// LocationResponse 位置节点响应
+type LocationResponse struct {
+	ID          uint                 `json:"id"`                    // 节点ID
+	ParentID    *uint                `json:"parentId,omitempty"`    // 上级节点ID
+	Level       entity.LocationLevel `json:"level"`                 // 层级
+	Name        string               `json:"name"`                  // 名称
+	UserName    string               `json:"userName"`              // 创建人员
+	Description *string              `json:"description,omitempty"` // 说明
+	CreateTime  *time.Time           `json:"createTime"`            // 创建时间
+	UpdateTime  *time.Time           `json:"updateTime"`            // 更新时间
+
+	Path []*LocationBrief `json:"path,omitempty"` // 从园区到上级节点的路径，如 园区 / 楼栋 / 楼层
+}
+
+// LocationTreeNode 位置树节点
+type LocationTreeNode struct {
+	ID          uint                 `json:"id"`                    // 节点ID
+	Level       entity.LocationLevel `json:"level"`                 // 层级
+	Name        string               `json:"name"`                  // 名称
+	Description *string              `json:"description,omitempty"` // 说明
+	Children    []*LocationTreeNode  `json:"children"`              // 下级节点
+}
+
+// NewLocationResponseFromEntity 从实体对象构建位置节点响应
+func NewLocationResponseFromEntity(l *entity.Location) *LocationResponse {
+	if l == nil {
+		return nil
+	}
+	return &LocationResponse{
+		ID:          l.ID,
+		ParentID:    l.ParentID,
+		Level:       l.Level,
+		Name:        l.Name,
+		UserName:    l.UserName,
+		Description: l.Description,
+		CreateTime:  &l.CreatedAt,
+		UpdateTime:  &l.UpdatedAt,
+	}
+}
+
+// NewLocationBriefFromEntity 从实体对象构建位置节点摘要
+func NewLocationBriefFromEntity(l *entity.Location) *LocationBrief {
+	return &LocationBrief{ID: l.ID, Level: l.Level, Name: l.Name}
+}
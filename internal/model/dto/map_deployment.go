@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// MapDeployRequest 地图下发请求
+type MapDeployRequest struct {
+	SemanticMapID      uint `json:"semanticMapId" binding:"required"` // 语义地图ID
+	SemanticMapVersion *int `json:"semanticMapVersion,omitempty"`     // 语义地图版本号，为空时使用当前版本
+}
+
+// MapDeploymentResponse 地图下发记录响应
+type MapDeploymentResponse struct {
+	ID                 uint                       `json:"id"`                        // 下发记录ID
+	DeviceID           uint                       `json:"deviceId"`                  // 设备ID
+	SemanticMapID      uint                       `json:"semanticMapId"`             // 语义地图ID
+	SemanticMapVersion int                        `json:"semanticMapVersion"`        // 语义地图版本号
+	PCDFileID          *uint                      `json:"pcdFileId,omitempty"`       // 点云地图ID
+	PCDFileVersion     *int                       `json:"pcdFileVersion,omitempty"`  // 点云地图版本号
+	OccupancyGridID    *uint                      `json:"occupancyGridId,omitempty"` // 占据栅格ID
+	Driver             string                     `json:"driver"`                    // 下发驱动
+	UserName           string                     `json:"userName"`                  // 操作人
+	Status             entity.MapDeploymentStatus `json:"status"`                    // 下发状态
+	TotalBytes         int64                      `json:"totalBytes"`                // 地图包总字节数
+	SentBytes          int64                      `json:"sentBytes"`                 // 已发送字节数
+	Progress           float64                    `json:"progress"`                  // 进度(0-100)
+	Error              *string                    `json:"error,omitempty"`           // 失败原因
+	CreateTime         *time.Time                 `json:"createTime"`                // 创建时间
+	StartedAt          *time.Time                 `json:"startedAt,omitempty"`       // 开始时间
+	FinishedAt         *time.Time                 `json:"finishedAt,omitempty"`      // 结束时间
+}
+
+// NewMapDeploymentResponseFromEntity 从实体对象构建地图下发记录响应
+func NewMapDeploymentResponseFromEntity(d *entity.MapDeployment) *MapDeploymentResponse {
+	if d == nil {
+		return nil
+	}
+	resp := &MapDeploymentResponse{
+		ID:                 d.ID,
+		DeviceID:           d.DeviceID,
+		SemanticMapID:      d.SemanticMapID,
+		SemanticMapVersion: d.SemanticMapVersion,
+		PCDFileID:          d.PCDFileID,
+		PCDFileVersion:     d.PCDFileVersion,
+		OccupancyGridID:    d.OccupancyGridID,
+		Driver:             d.Driver,
+		UserName:           d.UserName,
+		Status:             d.Status,
+		TotalBytes:         d.TotalBytes,
+		SentBytes:          d.SentBytes,
+		Error:              d.Error,
+		CreateTime:         &d.CreatedAt,
+		StartedAt:          d.StartedAt,
+		FinishedAt:         d.FinishedAt,
+	}
+	switch {
+	case d.Status == entity.MapDeploymentStatusSucceeded:
+		resp.Progress = 100
+	case d.TotalBytes > 0:
+		resp.Progress = float64(d.SentBytes) * 100 / float64(d.TotalBytes)
+	}
+	return resp
+}
@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// PCDFileVersionResponse 点云地图版本响应
+type PCDFileVersionResponse struct {
+	PCDFileID  uint                 `json:"pcdFileId"`           // 点云地图ID
+	Version    int                  `json:"version"`             // 版本号
+	Current    bool                 `json:"current"`             // 是否为当前版本
+	Path       string               `json:"path"`                // 文件存储路径
+	Size       int                  `json:"size"`                // 文件大小
+	MinioPath  *string              `json:"minioPath,omitempty"` // MinIO存储路径
+	Author     string               `json:"author"`              // 版本作者
+	Message    *string              `json:"message,omitempty"`   // 版本说明
+	CreateTime *time.Time           `json:"createTime"`          // 创建时间
+	Metadata   *PCDMetadataResponse `json:"metadata,omitempty"`  // 点云元数据
+	SHA256     *string              `json:"sha256,omitempty"`    // 文件内容SHA-256(十六进制)
+}
+
+// SemanticMapVersionResponse 语义地图版本响应
+type SemanticMapVersionResponse struct {
+	SemanticMapID   uint       `json:"semanticMapId"`             // 语义地图ID
+	Version         int        `json:"version"`                   // 版本号
+	Current         bool       `json:"current"`                   // 是否为当前版本
+	PCDFileID       *uint      `json:"pcdFileId,omitempty"`       // 对应的pcd地图文件id
+	PCDFileVersion  *int       `json:"pcdFileVersion,omitempty"`  // 编辑时点云地图的版本号
+	OccupancyGridID *uint      `json:"occupancyGridId,omitempty"` // 对应的二维栅格地图id
+	SemanticInfo    string     `json:"semanticInfo"`              // 语义信息
+	ExtraInfo       *string    `json:"extraInfo,omitempty"`       // 扩展信息
+	Author          string     `json:"author"`                    // 版本作者
+	Message         *string    `json:"message,omitempty"`         // 版本说明
+	CreateTime      *time.Time `json:"createTime"`                // 创建时间
+}
+
+// NewPCDFileVersionResponseFromEntity 从实体对象构建点云地图版本响应
+func NewPCDFileVersionResponseFromEntity(v *entity.PCDFileVersion, current *int) *PCDFileVersionResponse {
+	if v == nil {
+		return nil
+	}
+	return &PCDFileVersionResponse{
+		PCDFileID:  v.PCDFileID,
+		Version:    v.Version,
+		Current:    current != nil && *current == v.Version,
+		Path:       v.Path,
+		Size:       v.Size,
+		MinioPath:  v.MinioPath,
+		Author:     v.Author,
+		Message:    v.Message,
+		CreateTime: &v.CreatedAt,
+		Metadata:   newPCDMetadataResponse(&v.PCDMetadata),
+		SHA256:     v.SHA256,
+	}
+}
+
+// NewPCDFileVersionResponsesFromEntities 从实体列表构建点云地图版本列表
+func NewPCDFileVersionResponsesFromEntities(list []*entity.PCDFileVersion, current *int) []*PCDFileVersionResponse {
+	resp := make([]*PCDFileVersionResponse, 0, len(list))
+	for _, v := range list {
+		resp = append(resp, NewPCDFileVersionResponseFromEntity(v, current))
+	}
+	return resp
+}
+
+// NewSemanticMapVersionResponseFromEntity 从实体对象构建语义地图版本响应
+func NewSemanticMapVersionResponseFromEntity(v *entity.SemanticMapVersion, current *int) *SemanticMapVersionResponse {
+	if v == nil {
+		return nil
+	}
+	return &SemanticMapVersionResponse{
+		SemanticMapID:   v.SemanticMapID,
+		Version:         v.Version,
+		Current:         current != nil && *current == v.Version,
+		PCDFileID:       v.PCDFileID,
+		PCDFileVersion:  v.PCDFileVersion,
+		OccupancyGridID: v.OccupancyGridID,
+		SemanticInfo:    v.SemanticInfo,
+		ExtraInfo:       v.ExtraInfo,
+		Author:          v.Author,
+		Message:         v.Message,
+		CreateTime:      &v.CreatedAt,
+	}
+}
+
+// NewSemanticMapVersionResponsesFromEntities 从实体列表构建语义地图版本列表
+func NewSemanticMapVersionResponsesFromEntities(list []*entity.SemanticMapVersion, current *int) []*SemanticMapVersionResponse {
+	resp := make([]*SemanticMapVersionResponse, 0, len(list))
+	for _, v := range list {
+		resp = append(resp, NewSemanticMapVersionResponseFromEntity(v, current))
+	}
+	return resp
+}
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// PCDOccupancyGridRequest 由点云地图生成二维占据栅格的请求，未指定的参数使用配置默认值
+type PCDOccupancyGridRequest struct {
+	Name       *string  `json:"name"`                                // 栅格地图名称，默认取点云地图名称
+	Resolution *float64 `json:"resolution" binding:"omitempty,gt=0"` // 分辨率(米/像素)
+	MinZ       *float64 `json:"minZ"`                                // 障碍物高度带下限(米)，低于下限的点视为地面
+	MaxZ       *float64 `json:"maxZ"`                                // 障碍物高度带上限(米)，高于上限的点被忽略
+	MinPoints  *int     `json:"minPoints" binding:"omitempty,min=1"` // 栅格内至少多少个点才判为占据
+}
+
+// OccupancyGridImportRequest 导入 ROS map_server 地图的表单参数，文件字段为 image(PGM) 与 yaml
+type OccupancyGridImportRequest struct {
+	Name     string `form:"name"` // 栅格地图名称，默认取 YAML 中 image 的文件名
+	UserName string `form:"-"`    // 创建人员，取当前登录用户
+}
+
+// OccupancyGridResponse 二维占据栅格地图响应
+type OccupancyGridResponse struct {
+	ID             uint                       `json:"id"`                      // 栅格地图ID
+	Name           string                     `json:"name"`                    // 栅格地图名称
+	Source         entity.OccupancyGridSource `json:"source"`                  // 来源
+	PCDFileID      *uint                      `json:"pcdFileId,omitempty"`     // 来源点云地图ID
+	JobID          *uint                      `json:"jobId,omitempty"`         // 生成任务ID
+	UserName       string                     `json:"userName"`                // 创建人员
+	Resolution     float64                    `json:"resolution"`              // 分辨率(米/像素)
+	OriginX        float64                    `json:"originX"`                 // 左下角像素在地图坐标系中的X
+	OriginY        float64                    `json:"originY"`                 // 左下角像素在地图坐标系中的Y
+	OriginYaw      float64                    `json:"originYaw"`               // 地图旋转角(弧度)
+	Width          int                        `json:"width"`                   // 宽度(像素)
+	Height         int                        `json:"height"`                  // 高度(像素)
+	Negate         bool                       `json:"negate"`                  // 是否反色
+	OccupiedThresh float64                    `json:"occupiedThresh"`          // 占据阈值
+	FreeThresh     float64                    `json:"freeThresh"`              // 空闲阈值
+	MinZ           *float64                   `json:"minZ,omitempty"`          // 障碍物高度带下限(米)
+	MaxZ           *float64                   `json:"maxZ,omitempty"`          // 障碍物高度带上限(米)
+	OccupiedCells  *int                       `json:"occupiedCells,omitempty"` // 占据栅格数
+	FreeCells      *int                       `json:"freeCells,omitempty"`     // 空闲栅格数
+	CreateTime     *time.Time                 `json:"createTime"`              // 创建时间
+}
+
+// OccupancyGridListResponse 二维占据栅格地图列表响应
+type OccupancyGridListResponse struct {
+	PageResponse
+	List []*OccupancyGridResponse `json:"list"` // 栅格地图列表
+}
+
+// NewOccupancyGridResponseFromEntity 从实体对象构建栅格地图响应
+func NewOccupancyGridResponseFromEntity(g *entity.OccupancyGrid) *OccupancyGridResponse {
+	if g == nil {
+		return nil
+	}
+	return &OccupancyGridResponse{
+		ID:             g.ID,
+		Name:           g.Name,
+		Source:         g.Source,
+		PCDFileID:      g.PCDFileID,
+		JobID:          g.JobID,
+		UserName:       g.UserName,
+		Resolution:     g.Resolution,
+		OriginX:        g.OriginX,
+		OriginY:        g.OriginY,
+		OriginYaw:      g.OriginYaw,
+		Width:          g.Width,
+		Height:         g.Height,
+		Negate:         g.Negate,
+		OccupiedThresh: g.OccupiedThresh,
+		FreeThresh:     g.FreeThresh,
+		MinZ:           g.MinZ,
+		MaxZ:           g.MaxZ,
+		OccupiedCells:  g.OccupiedCells,
+		FreeCells:      g.FreeCells,
+		CreateTime:     &g.CreatedAt,
+	}
+}
+
+// NewOccupancyGridListResponseFromEntities 从实体列表构建栅格地图列表响应
+func NewOccupancyGridListResponseFromEntities(list []*entity.OccupancyGrid, page PageResponse) *OccupancyGridListResponse {
+	resp := &OccupancyGridListResponse{
+		PageResponse: page,
+		List:         make([]*OccupancyGridResponse, 0, len(list)),
+	}
+	for _, g := range list {
+		resp.List = append(resp.List, NewOccupancyGridResponseFromEntity(g))
+	}
+	return resp
+}
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"robot_scheduler/internal/model/entity"
+	"strings"
 	"time"
 )
 
@@ -12,8 +13,11 @@ type PCDFileCreateRequest struct {
 	Path      string  `json:"path" binding:"required"`               // 文件存储路径
 	UserName  string  `json:"userName" binding:"required"`           // 上传人员
 	Size      int     `json:"size" binding:"required,min=0"`         // 文件大小
-	MinioPath *string `json:"minioPath,omitempty"`                   // MinIO存储路径
+	MinioPath *string `json:"minioPath,omitempty"`                   // MinIO存储路径，须位于当前用户的上传前缀 pcd/<用户名>/ 下
 	ExtraInfo *string `json:"extraInfo,omitempty"`                   // 扩展信息
+	Message   *string `json:"message,omitempty"`                     // 版本说明
+
+	LocationID *uint `json:"locationId,omitempty"` // 所属位置节点ID
 }
 
 // PCDFileUpdateRequest 更新点云地图请求
@@ -23,8 +27,18 @@ type PCDFileUpdateRequest struct {
 	Path      *string `json:"path,omitempty"`                                   // 文件存储路径
 	UserName  *string `json:"userName,omitempty"`                               // 上传人员
 	Size      *int    `json:"size,omitempty" binding:"omitempty,min=0"`         // 文件大小
-	MinioPath *string `json:"minioPath,omitempty"`                              // MinIO存储路径
+	MinioPath *string `json:"minioPath,omitempty"`                              // MinIO存储路径，须位于当前用户的上传前缀 pcd/<用户名>/ 下
 	ExtraInfo *string `json:"extraInfo,omitempty"`                              // 扩展信息
+	Message   *string `json:"message,omitempty"`                                // 版本说明，替换点云文件时记录到新版本
+
+	LocationID *uint `json:"locationId,omitempty"` // 所属位置节点ID，为 0 时解除挂载
+}
+
+// PCDFileListRequest 点云地图查询请求
+type PCDFileListRequest struct {
+	PageRequest
+	LocationID      *uint                      `form:"locationId"`                                                     // 位置节点ID，包含其全部下级节点
+	IntegrityStatus *entity.PCDIntegrityStatus `form:"integrityStatus" binding:"omitempty,oneof=ok corrupted missing"` // 完整性校验结果
 }
 
 // PCDFileResponse 点云地图响应
@@ -39,6 +53,47 @@ type PCDFileResponse struct {
 	CreateTime *time.Time `json:"createTime"`          // 创建时间
 	UpdateTime *time.Time `json:"updateTime"`          // 更新时间
 	ExtraInfo  *string    `json:"extraInfo,omitempty"` // 扩展信息
+
+	AnalysisStatus *entity.PCDJobStatus  `json:"analysisStatus,omitempty"` // 上传后的点云解析状态，解析失败的原因见后台任务列表
+	Metadata       *PCDMetadataResponse  `json:"metadata,omitempty"`       // 服务端解析的点云元数据
+	Preview        *PCDPreviewResponse   `json:"preview,omitempty"`        // 降采样预览与缩略图
+	CurrentVersion *int                  `json:"currentVersion,omitempty"` // 当前版本号
+	LocationID     *uint                 `json:"locationId,omitempty"`     // 所属位置节点ID
+	SHA256         *string               `json:"sha256,omitempty"`         // 文件内容SHA-256(十六进制)
+	Integrity      *PCDIntegrityResponse `json:"integrity,omitempty"`      // 最近一次完整性校验结果
+	DuplicateOf    *uint                 `json:"duplicateOf,omitempty"`    // 上传内容与该点云地图相同，已复用其对象，仅在直接创建时返回，上传完成后的去重结果见解析任务
+}
+
+// PCDIntegrityResponse 点云对象完整性校验结果
+type PCDIntegrityResponse struct {
+	Status    entity.PCDIntegrityStatus `json:"status"`              // ok / corrupted / missing
+	CheckedAt *time.Time                `json:"checkedAt,omitempty"` // 校验时间
+}
+
+// PCDPreviewResponse 点云预览，可通过下载接口的 variant=preview/thumbnail 获取
+type PCDPreviewResponse struct {
+	Status        entity.PCDJobStatus `json:"status"`                  // 生成状态
+	PreviewPath   *string             `json:"previewPath,omitempty"`   // 降采样预览PCD的MinIO路径
+	ThumbnailPath *string             `json:"thumbnailPath,omitempty"` // 俯视缩略图PNG的MinIO路径
+	PointCount    *int                `json:"pointCount,omitempty"`    // 预览点数
+	LeafSize      *float64            `json:"leafSize,omitempty"`      // 体素边长(米)
+}
+
+// PCDMetadataResponse 点云元数据
+type PCDMetadataResponse struct {
+	PointCount   int             `json:"pointCount"`       // 点数
+	ValidPoints  int             `json:"validPoints"`      // 有效点数(坐标非NaN)
+	Fields       []string        `json:"fields"`           // 字段列表
+	DataEncoding string          `json:"dataEncoding"`     // 点数据编码
+	Width        int             `json:"width"`            // 点云宽度
+	Height       int             `json:"height"`           // 点云高度
+	Bounds       *PCDBoundsRange `json:"bounds,omitempty"` // 包围盒
+}
+
+// PCDBoundsRange 点云包围盒
+type PCDBoundsRange struct {
+	Min [3]float64 `json:"min"` // 最小点 [x, y, z]
+	Max [3]float64 `json:"max"` // 最大点 [x, y, z]
 }
 
 // PCDFileListResponse 点云地图列表响应
@@ -63,7 +118,72 @@ func NewPCDFileResponseFromEntity(f *entity.PCDFile) *PCDFileResponse {
 		CreateTime: &f.CreatedAt,
 		UpdateTime: &f.UpdatedAt,
 		ExtraInfo:  f.ExtraInfo,
+
+		AnalysisStatus: f.AnalysisStatus,
+		Metadata:       newPCDMetadataResponse(&f.PCDMetadata),
+		Preview:        newPCDPreviewResponse(f),
+		CurrentVersion: f.CurrentVersion,
+		LocationID:     f.LocationID,
+		SHA256:         f.SHA256,
+		Integrity:      newPCDIntegrityResponse(&f.PCDIntegrity),
+	}
+}
+
+// newPCDIntegrityResponse 构建完整性校验结果，从未校验过时返回 nil
+func newPCDIntegrityResponse(i *entity.PCDIntegrity) *PCDIntegrityResponse {
+	if i.IntegrityStatus == nil {
+		return nil
+	}
+	return &PCDIntegrityResponse{
+		Status:    *i.IntegrityStatus,
+		CheckedAt: i.IntegrityCheckedAt,
+	}
+}
+
+// newPCDPreviewResponse 构建点云预览信息，从未生成过预览时返回 nil
+func newPCDPreviewResponse(f *entity.PCDFile) *PCDPreviewResponse {
+	if f.PreviewStatus == nil {
+		return nil
+	}
+	return &PCDPreviewResponse{
+		Status:        *f.PreviewStatus,
+		PreviewPath:   f.PreviewPath,
+		ThumbnailPath: f.ThumbnailPath,
+		PointCount:    f.PreviewPoints,
+		LeafSize:      f.PreviewLeaf,
+	}
+}
+
+// newPCDMetadataResponse 构建点云元数据，未解析过的文件返回 nil
+func newPCDMetadataResponse(f *entity.PCDMetadata) *PCDMetadataResponse {
+	if f.PointCount == nil {
+		return nil
+	}
+	meta := &PCDMetadataResponse{
+		PointCount: *f.PointCount,
+	}
+	if f.ValidPoints != nil {
+		meta.ValidPoints = *f.ValidPoints
+	}
+	if f.Fields != nil {
+		meta.Fields = strings.Fields(*f.Fields)
+	}
+	if f.DataEncoding != nil {
+		meta.DataEncoding = *f.DataEncoding
+	}
+	if f.Width != nil {
+		meta.Width = *f.Width
+	}
+	if f.Height != nil {
+		meta.Height = *f.Height
+	}
+	if f.MinX != nil && f.MinY != nil && f.MinZ != nil && f.MaxX != nil && f.MaxY != nil && f.MaxZ != nil {
+		meta.Bounds = &PCDBoundsRange{
+			Min: [3]float64{*f.MinX, *f.MinY, *f.MinZ},
+			Max: [3]float64{*f.MaxX, *f.MaxY, *f.MaxZ},
+		}
 	}
+	return meta
 }
 
 // NewPCDFileListResponseFromEntities 从实体列表构建点云地图列表响应
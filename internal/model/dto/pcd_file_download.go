@@ -0,0 +1,12 @@
+package dto
+
+// PCDFileDownloadURLResponse 点云地图下载链接响应
+type PCDFileDownloadURLResponse struct {
+	ID          uint   `json:"id"`          // 地图ID
+	Name        string `json:"name"`        // 地图名称
+	Variant     string `json:"variant"`     // 下载内容：空为原始点云，preview 预览点云，thumbnail 缩略图
+	FileName    string `json:"fileName"`    // 下载文件名
+	Size        int    `json:"size"`        // 文件大小(字节)，仅原始点云返回
+	DownloadURL string `json:"downloadUrl"` // 预签名 GET URL
+	ExpireAt    int64  `json:"expireAt"`    // 过期时间戳（秒）
+}
@@ -1,16 +1,96 @@
 package dto
 
+import "time"
+
 // PCDFileUploadTokenRequest 获取点云地图上传凭证请求
 type PCDFileUploadTokenRequest struct {
-	FileName string `json:"fileName" binding:"required"`   // 原始文件名
-	Size     int64  `json:"size" binding:"required,min=0"` // 文件大小(字节)
+	FileName string  `json:"fileName" binding:"required"`                               // 原始文件名
+	Size     int64   `json:"size" binding:"required,min=0"`                             // 文件大小(字节)
+	Checksum *string `json:"checksum,omitempty" binding:"omitempty,len=32,hexadecimal"` // 文件MD5(十六进制)，完成上传时校验
+	SHA256   *string `json:"sha256,omitempty" binding:"omitempty,len=64,hexadecimal"`   // 文件SHA-256(十六进制)，完成上传时校验
 }
 
 // PCDFileUploadTokenResponse 获取点云地图上传凭证响应
 type PCDFileUploadTokenResponse struct {
 	UploadURL string `json:"uploadUrl"` // 预签名 PUT URL
 	Bucket    string `json:"bucket"`    // MinIO Bucket 名
-	ObjectKey string `json:"objectKey"` // 对象 Key（前端后续要写入 MinioPath）
+	ObjectKey string `json:"objectKey"` // 对象 Key（上传完成后调用完成接口时回传）
 	ExpireAt  int64  `json:"expireAt"`  // 过期时间戳（秒）
 }
 
+// PCDFileCompleteUploadRequest 完成点云地图上传请求
+// 服务端校验对象存在、大小与校验和一致后创建点云地图，内容与已有点云地图相同时复用已有对象
+type PCDFileCompleteUploadRequest struct {
+	ObjectKey string  `json:"objectKey" binding:"required"`          // 上传凭证返回的对象 Key
+	Name      string  `json:"name" binding:"required,min=1,max=100"` // 地图名称
+	Area      string  `json:"area" binding:"required"`               // 区域描述
+	Path      *string `json:"path,omitempty"`                        // 文件存储路径，默认为对象 Key
+	ExtraInfo *string `json:"extraInfo,omitempty"`                   // 扩展信息
+	Message   *string `json:"message,omitempty"`                     // 版本说明
+
+	LocationID *uint `json:"locationId,omitempty"` // 所属位置节点ID
+}
+
+// PCDMultipartInitRequest 初始化分片上传请求
+type PCDMultipartInitRequest struct {
+	FileName string  `json:"fileName" binding:"required"`                               // 原始文件名
+	Size     int64   `json:"size" binding:"required,min=1"`                             // 文件大小(字节)
+	Checksum *string `json:"checksum,omitempty" binding:"omitempty,len=32,hexadecimal"` // 文件MD5(十六进制)，完成上传时校验
+	SHA256   *string `json:"sha256,omitempty" binding:"omitempty,len=64,hexadecimal"`   // 文件SHA-256(十六进制)，完成上传时校验
+	PartSize int64   `json:"partSize,omitempty" binding:"omitempty,min=5242880"`        // 分片大小(字节)，不小于 5MB，默认由服务端决定
+}
+
+// PCDMultipartInitResponse 初始化分片上传响应
+type PCDMultipartInitResponse struct {
+	ObjectKey string `json:"objectKey"` // 对象 Key，后续接口均以此标识本次上传
+	PartSize  int64  `json:"partSize"`  // 分片大小(字节)，最后一片可以更小
+	PartCount int    `json:"partCount"` // 分片数量
+	ExpireAt  int64  `json:"expireAt"`  // 未完成上传的保留截止时间戳（秒）
+}
+
+// PCDMultipartPartURLRequest 获取分片上传 URL 请求
+type PCDMultipartPartURLRequest struct {
+	ObjectKey   string `json:"objectKey" binding:"required"`                             // 对象 Key
+	PartNumbers []int  `json:"partNumbers" binding:"required,min=1,max=1000,dive,min=1"` // 分片序号(从 1 开始)
+}
+
+// PCDMultipartPartURL 分片预签名上传 URL
+type PCDMultipartPartURL struct {
+	PartNumber int    `json:"partNumber"` // 分片序号
+	UploadURL  string `json:"uploadUrl"`  // 预签名 PUT URL
+}
+
+// PCDMultipartPartURLResponse 获取分片上传 URL 响应
+type PCDMultipartPartURLResponse struct {
+	ObjectKey string                 `json:"objectKey"` // 对象 Key
+	Parts     []*PCDMultipartPartURL `json:"parts"`     // 分片上传 URL
+	ExpireAt  int64                  `json:"expireAt"`  // URL 过期时间戳（秒）
+}
+
+// PCDMultipartPartsRequest 查询已上传分片请求
+type PCDMultipartPartsRequest struct {
+	ObjectKey string `form:"objectKey" binding:"required"` // 对象 Key
+}
+
+// PCDUploadedPart 已上传的分片
+type PCDUploadedPart struct {
+	PartNumber   int       `json:"partNumber"`   // 分片序号
+	Size         int64     `json:"size"`         // 分片大小(字节)
+	ETag         string    `json:"etag"`         // 分片 ETag
+	LastModified time.Time `json:"lastModified"` // 上传时间
+}
+
+// PCDMultipartPartsResponse 查询已上传分片响应，客户端据此续传缺失的分片
+type PCDMultipartPartsResponse struct {
+	ObjectKey    string             `json:"objectKey"`    // 对象 Key
+	PartSize     int64              `json:"partSize"`     // 分片大小(字节)
+	PartCount    int                `json:"partCount"`    // 分片数量
+	Parts        []*PCDUploadedPart `json:"parts"`        // 已上传的分片
+	MissingParts []int              `json:"missingParts"` // 尚未上传的分片序号
+	ExpireAt     int64              `json:"expireAt"`     // 未完成上传的保留截止时间戳（秒）
+}
+
+// PCDMultipartAbortRequest 放弃分片上传请求
+type PCDMultipartAbortRequest struct {
+	ObjectKey string `json:"objectKey" binding:"required"` // 对象 Key
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"robot_scheduler/internal/model/entity"
+	"time"
+)
+
+// PCDJobResponse 点云后台任务响应
+type PCDJobResponse struct {
+	ID         uint                `json:"id"`                   // 任务ID
+	PCDFileID  uint                `json:"pcdFileId"`            // 点云地图ID
+	Type       entity.PCDJobType   `json:"type"`                 // 任务类型
+	Status     entity.PCDJobStatus `json:"status"`               // 任务状态
+	Params     *string             `json:"params,omitempty"`     // 任务参数(JSON)
+	Error      *string             `json:"error,omitempty"`      // 失败原因
+	Result     *string             `json:"result,omitempty"`     // 任务结果(JSON)
+	CreateTime *time.Time          `json:"createTime"`           // 创建时间
+	StartedAt  *time.Time          `json:"startedAt,omitempty"`  // 开始时间
+	FinishedAt *time.Time          `json:"finishedAt,omitempty"` // 结束时间
+}
+
+// NewPCDJobResponseFromEntity 从实体对象构建点云后台任务响应
+func NewPCDJobResponseFromEntity(j *entity.PCDJob) *PCDJobResponse {
+	if j == nil {
+		return nil
+	}
+	return &PCDJobResponse{
+		ID:         j.ID,
+		PCDFileID:  j.PCDFileID,
+		Type:       j.Type,
+		Status:     j.Status,
+		Params:     j.Params,
+		Error:      j.Error,
+		Result:     j.Result,
+		CreateTime: &j.CreatedAt,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+	}
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// PCDReconcileReport 点云地图存储对账报告
+type PCDReconcileReport struct {
+	ScannedObjects int                 `json:"scannedObjects"` // 扫描的 MinIO 对象数
+	CheckedFiles   int                 `json:"checkedFiles"`   // 检查的点云地图记录数
+	OrphanObjects  []*PCDOrphanObject  `json:"orphanObjects"`  // 没有对应记录的对象
+	MissingObjects []*PCDMissingObject `json:"missingObjects"` // 对象已丢失的记录
+}
+
+// PCDOrphanObject 没有任何点云地图记录或待完成上传引用的对象
+type PCDOrphanObject struct {
+	ObjectKey    string    `json:"objectKey"`    // 对象 Key
+	Size         int64     `json:"size"`         // 对象大小(字节)
+	LastModified time.Time `json:"lastModified"` // 最后修改时间
+}
+
+// PCDMissingObject MinIO 中找不到对象的点云地图记录
+type PCDMissingObject struct {
+	ID        uint   `json:"id"`        // 地图ID
+	Name      string `json:"name"`      // 地图名称
+	MinioPath string `json:"minioPath"` // MinIO存储路径
+	Deleted   bool   `json:"deleted"`   // 是否已软删除
+}
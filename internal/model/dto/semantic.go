@@ -1,36 +1,63 @@
 package dto
 
 import (
+	"robot_scheduler/internal/frame"
 	"robot_scheduler/internal/model/entity"
 	"time"
 )
 
 // SemanticMapCreateRequest 创建语义地图请求
 type SemanticMapCreateRequest struct {
-	PCDFileID    uint    `json:"pcdFileId" binding:"required"`    // 对应的pcd地图文件id
-	UserName     string  `json:"userName" binding:"required"`     // 编辑人员
-	SemanticInfo string  `json:"semanticInfo" binding:"required"` // 语义信息
-	ExtraInfo    *string `json:"extraInfo,omitempty"`             // 扩展信息
+	PCDFileID       *uint   `json:"pcdFileId,omitempty"`             // 对应的pcd地图文件id，与 occupancyGridId 二选一
+	OccupancyGridID *uint   `json:"occupancyGridId,omitempty"`       // 对应的二维栅格地图id，与 pcdFileId 二选一
+	UserName        string  `json:"userName" binding:"required"`     // 编辑人员
+	SemanticInfo    string  `json:"semanticInfo" binding:"required"` // 语义信息
+	ExtraInfo       *string `json:"extraInfo,omitempty"`             // 扩展信息
+	Message         *string `json:"message,omitempty"`               // 版本说明
+	LocationID      *uint   `json:"locationId,omitempty"`            // 所属位置节点ID，默认取点云底图的位置节点
 }
 
 // SemanticMapUpdateRequest 更新语义地图请求
 type SemanticMapUpdateRequest struct {
-	PCDFileID    *uint   `json:"pcdFileId,omitempty"`    // 对应的pcd地图文件id
-	UserName     *string `json:"userName,omitempty"`     // 编辑人员
-	SemanticInfo *string `json:"semanticInfo,omitempty"` // 语义信息
-	ExtraInfo    *string `json:"extraInfo,omitempty"`    // 扩展信息
+	PCDFileID       *uint   `json:"pcdFileId,omitempty"`       // 更换底图为指定的pcd地图文件，与 occupancyGridId 二选一
+	OccupancyGridID *uint   `json:"occupancyGridId,omitempty"` // 更换底图为指定的二维栅格地图，与 pcdFileId 二选一
+	UserName        *string `json:"userName,omitempty"`        // 编辑人员
+	SemanticInfo    *string `json:"semanticInfo,omitempty"`    // 语义信息
+	ExtraInfo       *string `json:"extraInfo,omitempty"`       // 扩展信息
+	Message         *string `json:"message,omitempty"`         // 版本说明
+	LocationID      *uint   `json:"locationId,omitempty"`      // 所属位置节点ID，为 0 时解除挂载
+}
+
+// SemanticMapListRequest 语义地图查询请求
+type SemanticMapListRequest struct {
+	PageRequest
+	LocationID *uint `form:"locationId"` // 位置节点ID，包含其全部下级节点
 }
 
 // SemanticMapResponse 语义地图响应
 type SemanticMapResponse struct {
 	ID           uint            `json:"id"`                  // 语义地图ID
-	PCDFileID    uint            `json:"pcdFileId"`           // 对应的pcd地图文件id
+	PCDFileID    *uint           `json:"pcdFileId,omitempty"` // 对应的pcd地图文件id
 	PCDFile      *entity.PCDFile `json:"pcdFile,omitempty"`   // 关联的点云地图
 	UserName     string          `json:"userName"`            // 编辑人员
 	SemanticInfo string          `json:"semanticInfo"`        // 语义信息
 	CreateTime   *time.Time      `json:"createTime"`          // 创建时间
 	UpdateTime   *time.Time      `json:"updateTime"`          // 更新时间
 	ExtraInfo    *string         `json:"extraInfo,omitempty"` // 扩展信息
+
+	OccupancyGridID *uint                  `json:"occupancyGridId,omitempty"` // 对应的二维栅格地图id
+	OccupancyGrid   *OccupancyGridResponse `json:"occupancyGrid,omitempty"`   // 关联的二维栅格地图
+	Frame           string                 `json:"frame"`                     // 语义信息中坐标所在的坐标系，默认为底图坐标系
+	LocationID      *uint                  `json:"locationId,omitempty"`      // 所属位置节点ID
+
+	CurrentVersion *int `json:"currentVersion,omitempty"` // 当前版本号
+	Revision       int  `json:"revision"`                 // 修订号，与响应头 ETag 一致，更新时通过 If-Match 回传
+
+	LockedBy      *string    `json:"lockedBy,omitempty"`      // 编辑锁持有人
+	LockExpiresAt *time.Time `json:"lockExpiresAt,omitempty"` // 编辑锁过期时间
+
+	CheckSeverity *string    `json:"checkSeverity,omitempty"` // 最近一次一致性检查的最高严重级别
+	CheckedAt     *time.Time `json:"checkedAt,omitempty"`     // 最近一次一致性检查时间
 }
 
 // SemanticMapListResponse 语义地图列表响应
@@ -47,12 +74,26 @@ func NewSemanticMapResponseFromEntity(m *entity.SemanticMap) *SemanticMapRespons
 	return &SemanticMapResponse{
 		ID:           m.ID,
 		PCDFileID:    m.PCDFileID,
-		PCDFile:      &m.PCDFile,
+		PCDFile:      m.PCDFile,
 		UserName:     m.UserName,
 		SemanticInfo: m.SemanticInfo,
 		CreateTime:   &m.CreatedAt,
 		UpdateTime:   &m.UpdatedAt,
 		ExtraInfo:    m.ExtraInfo,
+
+		OccupancyGridID: m.OccupancyGridID,
+		OccupancyGrid:   NewOccupancyGridResponseFromEntity(m.OccupancyGrid),
+		Frame:           frame.MapFrame(m.PCDFileID, m.OccupancyGridID),
+		LocationID:      m.LocationID,
+
+		CurrentVersion: m.CurrentVersion,
+		Revision:       m.Revision,
+
+		LockedBy:      m.LockedBy,
+		LockExpiresAt: m.LockExpiresAt,
+
+		CheckSeverity: m.CheckSeverity,
+		CheckedAt:     m.CheckedAt,
 	}
 }
 
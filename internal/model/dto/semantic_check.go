@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"robot_scheduler/internal/semantic"
+)
+
+// SemanticCheckResponse 语义地图一致性检查报告
+type SemanticCheckResponse struct {
+	SemanticMapID uint              `json:"semanticMapId"`     // 语义地图ID
+	Version       *int              `json:"version,omitempty"` // 检查时的语义地图版本
+	Severity      semantic.Severity `json:"severity"`          // 最高严重级别：ok/info/warning/error
+	Issues        []*semantic.Issue `json:"issues"`            // 问题列表
+	CheckedAt     time.Time         `json:"checkedAt"`         // 检查时间
+}
+
+// NewSemanticCheckResponse 从检查报告构建响应
+func NewSemanticCheckResponse(semanticMapID uint, version *int, report *semantic.Report, checkedAt time.Time) *SemanticCheckResponse {
+	return &SemanticCheckResponse{
+		SemanticMapID: semanticMapID,
+		Version:       version,
+		Severity:      report.Severity,
+		Issues:        report.Issues,
+		CheckedAt:     checkedAt,
+	}
+}
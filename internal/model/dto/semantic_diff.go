@@ -0,0 +1,55 @@
+package dto
+
+import "robot_scheduler/internal/semantic"
+
+// SemanticDiffRequest 语义地图版本差异查询参数
+type SemanticDiffRequest struct {
+	From int  `form:"from" binding:"required,gt=0"` // 起始版本号
+	To   *int `form:"to" binding:"omitempty,gt=0"`  // 目标版本号，默认为当前内容
+}
+
+// SemanticDiffResponse 语义地图版本差异
+type SemanticDiffResponse struct {
+	SemanticMapID uint               `json:"semanticMapId"` // 语义地图ID
+	From          int                `json:"from"`          // 起始版本号
+	To            *int               `json:"to,omitempty"`  // 目标版本号，为空表示当前内容
+	Added         int                `json:"added"`         // 新增元素数
+	Removed       int                `json:"removed"`       // 删除元素数
+	Modified      int                `json:"modified"`      // 修改元素数
+	Changes       []*semantic.Change `json:"changes"`       // 按类型、ID 排序的元素变更
+}
+
+// NewSemanticDiffResponse 从元素变更构建差异响应
+func NewSemanticDiffResponse(semanticMapID uint, from int, to *int, changes []*semantic.Change) *SemanticDiffResponse {
+	resp := &SemanticDiffResponse{SemanticMapID: semanticMapID, From: from, To: to, Changes: changes}
+	for _, c := range changes {
+		switch c.Op {
+		case semantic.ChangeAdded:
+			resp.Added++
+		case semantic.ChangeRemoved:
+			resp.Removed++
+		case semantic.ChangeModified:
+			resp.Modified++
+		}
+	}
+	return resp
+}
+
+// SemanticMergeRequest 语义地图三方合并请求
+type SemanticMergeRequest struct {
+	Base       int     `json:"base" binding:"required,gt=0"`              // 共同祖先版本号
+	Ours       *int    `json:"ours,omitempty" binding:"omitempty,gt=0"`   // 本分支版本号，默认为当前内容
+	Theirs     *int    `json:"theirs,omitempty" binding:"omitempty,gt=0"` // 对方分支版本号，与 theirsInfo 二选一
+	TheirsInfo *string `json:"theirsInfo,omitempty"`                      // 对方分支的语义信息，与 theirs 二选一
+	Apply      bool    `json:"apply,omitempty"`                           // 无冲突时将合并结果保存为当前内容并生成新版本，仅本分支为当前内容时可用
+	Message    *string `json:"message,omitempty"`                         // 应用合并结果时的版本说明
+}
+
+// SemanticMergeResponse 语义地图三方合并结果
+type SemanticMergeResponse struct {
+	SemanticMapID uint                 `json:"semanticMapId"`      // 语义地图ID
+	Merged        *semantic.Map        `json:"merged"`             // 合并结果，冲突处保留本分支内容
+	Conflicts     []*semantic.Conflict `json:"conflicts"`          // 冲突列表，为空表示可直接应用
+	Applied       bool                 `json:"applied"`            // 是否已保存为当前内容
+	Revision      int                  `json:"revision,omitempty"` // 应用后的修订号
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "robot_scheduler/internal/semantic"
+
+// GeoJSON 坐标参考
+const (
+	GeoJSONCRSLocal = "local" // 地图坐标系(米)
+	GeoJSONCRSWGS84 = "wgs84" // WGS84 经纬度，需要配置 geo_reference
+)
+
+// SemanticGeoJSONExportRequest 导出 GeoJSON 请求
+type SemanticGeoJSONExportRequest struct {
+	CRS string `form:"crs" binding:"omitempty,oneof=local wgs84"` // 坐标参考，默认 local
+}
+
+// SemanticGeoJSONImportRequest 导入 GeoJSON 的查询参数，请求体为 GeoJSON FeatureCollection
+type SemanticGeoJSONImportRequest struct {
+	PCDFileID       uint    `form:"pcdFileId"`                                 // 对应的pcd地图文件id，新建时与 occupancyGridId 二选一
+	OccupancyGridID uint    `form:"occupancyGridId"`                           // 对应的二维栅格地图id，新建时与 pcdFileId 二选一
+	CRS             string  `form:"crs" binding:"omitempty,oneof=local wgs84"` // 坐标参考，默认 local
+	Message         *string `form:"message"`                                   // 版本说明
+	DryRun          bool    `form:"dryRun"`                                    // 只校验不保存
+}
+
+// SemanticGeoJSONImportResponse 导入 GeoJSON 结果
+type SemanticGeoJSONImportResponse struct {
+	Valid       bool                  `json:"valid"`                 // 全部要素均可转换且结果符合结构定义
+	Imported    bool                  `json:"imported"`              // 是否已保存
+	SemanticMap *SemanticMapResponse  `json:"semanticMap,omitempty"` // 保存后的语义地图
+	Counts      map[semantic.Kind]int `json:"counts"`                // 转换得到的各类元素数量
+	Report      *semantic.Report      `json:"report"`                // 要素转换问题与一致性检查结果
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"robot_scheduler/internal/model/entity"
+)
+
+// SemanticMapLockRequest 获取语义地图编辑锁请求
+type SemanticMapLockRequest struct {
+	TTL int `json:"ttl,omitempty" binding:"omitempty,min=1,max=3600"` // 锁有效期(秒)，默认 300；本人重复获取即续期
+}
+
+// SemanticMapLockResponse 语义地图编辑锁状态
+type SemanticMapLockResponse struct {
+	SemanticMapID uint       `json:"semanticMapId"`       // 语义地图ID
+	Revision      int        `json:"revision"`            // 当前修订号
+	LockedBy      *string    `json:"lockedBy,omitempty"`  // 锁持有人，未加锁或已过期时为空
+	LockedAt      *time.Time `json:"lockedAt,omitempty"`  // 获取时间
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"` // 过期时间
+}
+
+// NewSemanticMapLockResponse 从实体构建编辑锁状态，已过期的锁视为未加锁
+func NewSemanticMapLockResponse(m *entity.SemanticMap, now time.Time) *SemanticMapLockResponse {
+	resp := &SemanticMapLockResponse{SemanticMapID: m.ID, Revision: m.Revision}
+	if m.LockedBy != nil && m.LockExpiresAt != nil && m.LockExpiresAt.After(now) {
+		resp.LockedBy = m.LockedBy
+		resp.LockedAt = m.LockedAt
+		resp.ExpiresAt = m.LockExpiresAt
+	}
+	return resp
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "robot_scheduler/internal/semantic"
+
+// SemanticPlanRequest 路径规划请求
+type SemanticPlanRequest struct {
+	From       string   `json:"from" binding:"required"`                    // 起点，路网节点ID或兴趣点ID
+	To         string   `json:"to" binding:"required"`                      // 终点，路网节点ID或兴趣点ID
+	DeviceType string   `json:"deviceType,omitempty"`                       // 设备类型，用于按设备类型过滤边与区域
+	Speed      *float64 `json:"speed,omitempty" binding:"omitempty,gt=0"`   // 行驶速度(米/秒)，默认 1.0
+	Version    *int     `json:"version,omitempty" binding:"omitempty,gt=0"` // 在指定历史版本上规划，默认为当前内容
+	Frame      string   `json:"frame,omitempty"`                            // 返回路网节点坐标所用的坐标系，默认为底图坐标系
+}
+
+// SemanticPlanResponse 路径规划响应
+type SemanticPlanResponse struct {
+	SemanticMapID uint                 `json:"semanticMapId"`     // 语义地图ID
+	Version       *int                 `json:"version,omitempty"` // 规划所用的语义地图版本
+	Waypoints     []*semantic.Waypoint `json:"waypoints"`         // 依次经过的路网节点，含起点与终点
+	Edges         []string             `json:"edges"`             // 依次经过的边ID
+	Cost          float64              `json:"cost"`              // 总代价
+	Distance      float64              `json:"distance"`          // 总里程(米)
+	EstimatedTime float64              `json:"estimatedTime"`     // 预计耗时(秒)
+	Frame         string               `json:"frame"`             // 路网节点坐标所在的坐标系
+}
+
+// NewSemanticPlanResponse 从规划结果构建路径规划响应
+func NewSemanticPlanResponse(semanticMapID uint, version *int, plan *semantic.Plan) *SemanticPlanResponse {
+	resp := &SemanticPlanResponse{
+		SemanticMapID: semanticMapID,
+		Version:       version,
+		Waypoints:     plan.Waypoints,
+		Edges:         make([]string, 0, len(plan.Edges)),
+		Cost:          plan.Cost,
+		Distance:      plan.Distance,
+		EstimatedTime: plan.Duration,
+	}
+	for _, e := range plan.Edges {
+		resp.Edges = append(resp.Edges, e.ID)
+	}
+	return resp
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"robot_scheduler/internal/frame"
 	"robot_scheduler/internal/model/entity"
 	"time"
 )
@@ -11,6 +12,8 @@ type TaskCreateRequest struct {
 	UserName      string  `json:"userName" binding:"required"`      // 编辑人员
 	TaskInfo      string  `json:"taskInfo" binding:"required"`      // 任务信息
 	ExtraInfo     *string `json:"extraInfo,omitempty"`              // 扩展信息
+
+	SemanticMapVersion *int `json:"semanticMapVersion,omitempty"` // 固定使用的语义地图版本，默认为当前版本
 }
 
 // TaskUpdateRequest 更新任务请求
@@ -20,6 +23,8 @@ type TaskUpdateRequest struct {
 	TaskInfo      *string            `json:"taskInfo,omitempty"`      // 任务信息
 	Status        *entity.TaskStatus `json:"status,omitempty"`        // 任务状态
 	ExtraInfo     *string            `json:"extraInfo,omitempty"`     // 扩展信息
+
+	SemanticMapVersion *int `json:"semanticMapVersion,omitempty"` // 固定使用的语义地图版本，更换语义地图时默认为其当前版本
 }
 
 // TaskResponse 任务响应
@@ -33,6 +38,16 @@ type TaskResponse struct {
 	CreateTime    *time.Time          `json:"createTime"`            // 创建时间
 	UpdateTime    *time.Time          `json:"updateTime"`            // 更新时间
 	ExtraInfo     *string             `json:"extraInfo,omitempty"`   // 扩展信息
+
+	SemanticMapVersion *int   `json:"semanticMapVersion,omitempty"` // 固定使用的语义地图版本
+	Frame              string `json:"frame,omitempty"`              // 关联语义地图中坐标所在的坐标系，指定 frame 查询时为固定版本换算后的坐标系
+}
+
+// TaskListRequest 任务查询请求
+type TaskListRequest struct {
+	PageRequest
+	LocationID *uint  `form:"locationId"` // 位置节点ID，按任务所用语义地图的位置匹配，包含其全部下级节点
+	Frame      string `form:"frame"`      // 返回坐标所用的坐标系，指定时返回各任务固定版本的语义地图内容
 }
 
 // TaskListResponse 任务列表响应
@@ -56,6 +71,9 @@ func NewTaskResponseFromEntity(t *entity.Task) *TaskResponse {
 		CreateTime:    &t.CreatedAt,
 		UpdateTime:    &t.UpdatedAt,
 		ExtraInfo:     t.ExtraInfo,
+
+		SemanticMapVersion: t.SemanticMapVersion,
+		Frame:              frame.MapFrame(t.SemanticMap.PCDFileID, t.SemanticMap.OccupancyGridID),
 	}
 }
 
@@ -1,8 +1,12 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
 
-// DeviceType 设备类型枚举
+	"gorm.io/gorm"
+)
+
+// DeviceType 设备类型，可用取值以设备型号目录(device_model)为准，以下为内置取值
 type DeviceType string
 
 const (
@@ -10,13 +14,31 @@ const (
 	DeviceTypeBipedRobot DeviceType = "robot_biped" // 双足机器人
 )
 
-// CompanyType 厂商类型枚举
+// IsValid 判断设备类型是否为内置取值，型号目录中另行登记的类型不在此列
+func (t DeviceType) IsValid() bool {
+	switch t {
+	case DeviceTypeWheelRobot, DeviceTypeBipedRobot:
+		return true
+	}
+	return false
+}
+
+// CompanyType 厂商类型，可用取值以设备型号目录(device_model)为准，以下为内置取值
 type CompanyType string
 
 const (
 	CompanyCyborg CompanyType = "cyborg" // 赛博格
 )
 
+// IsValid 判断厂商是否为内置取值，型号目录中另行登记的厂商不在此列
+func (c CompanyType) IsValid() bool {
+	switch c {
+	case CompanyCyborg:
+		return true
+	}
+	return false
+}
+
 // Device 设备表
 type Device struct {
 	gorm.Model
@@ -28,6 +50,18 @@ type Device struct {
 	Password  *string       `gorm:"type:text;comment:登录密码(RSA加密)"`
 	Status    *DeviceStatus `gorm:"type:text;default:'offline';comment:设备状态"`
 	ExtraInfo *string       `gorm:"type:text;comment:扩展信息(JSON)"`
+
+	SerialNumber    *string    `gorm:"type:text;index;comment:设备序列号"`
+	Capabilities    *string    `gorm:"type:text;comment:设备能力(JSON数组)"`
+	LastHeartbeatAt *time.Time `gorm:"comment:最近心跳时间"`
+	ModelID         *uint      `gorm:"index;comment:设备型号id"`
+	LocationID      *uint      `gorm:"index;comment:所在位置节点id"`
+
+	// 设备当前运行的地图，地图下发成功后更新，调度只在设备已加载的地图上分配任务
+	LoadedSemanticMapID      *uint      `gorm:"index;comment:已加载的语义地图id"`
+	LoadedSemanticMapVersion *int       `gorm:"comment:已加载的语义地图版本号"`
+	LoadedDeploymentID       *uint      `gorm:"comment:加载该地图的下发记录id"`
+	MapLoadedAt              *time.Time `gorm:"comment:地图加载时间"`
 }
 
 // DeviceStatus 设备状态枚举
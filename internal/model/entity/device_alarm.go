@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AlarmSeverity 告警级别枚举
+type AlarmSeverity string
+
+const (
+	AlarmSeverityInfo     AlarmSeverity = "info"     // 提示
+	AlarmSeverityWarning  AlarmSeverity = "warning"  // 警告
+	AlarmSeverityCritical AlarmSeverity = "critical" // 严重（存在未解除的严重告警时设备状态为 error）
+)
+
+// DeviceAlarm 设备告警表
+type DeviceAlarm struct {
+	gorm.Model
+	DeviceID       uint          `gorm:"not null;index;comment:设备id"`
+	Code           string        `gorm:"type:text;not null;comment:厂商错误码"`
+	Severity       AlarmSeverity `gorm:"type:text;not null;comment:告警级别"`
+	Message        string        `gorm:"type:text;comment:告警信息"`
+	PoseX          *float64      `gorm:"comment:告警时位姿X(米)"`
+	PoseY          *float64      `gorm:"comment:告警时位姿Y(米)"`
+	PoseYaw        *float64      `gorm:"comment:告警时位姿航向角(弧度)"`
+	RaisedAt       time.Time     `gorm:"not null;comment:产生时间"`
+	ClearedAt      *time.Time    `gorm:"index;comment:解除时间"`
+	AcknowledgedBy *string       `gorm:"type:text;comment:确认人员"`
+	AcknowledgedAt *time.Time    `gorm:"comment:确认时间"`
+	ExtraInfo      *string       `gorm:"type:text;comment:扩展信息(JSON)"`
+}
+
+func (DeviceAlarm) TableName() string {
+	return "device_alarm"
+}
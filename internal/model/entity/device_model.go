@@ -0,0 +1,20 @@
+package entity
+
+import "gorm.io/gorm"
+
+// DeviceModel 设备型号目录表，设备的类型/厂商组合须在目录中登记
+type DeviceModel struct {
+	gorm.Model
+	Company      CompanyType `gorm:"type:text;not null;uniqueIndex:idx_device_model_company_name,priority:1;comment:设备厂商"`
+	Name         string      `gorm:"type:text;not null;uniqueIndex:idx_device_model_company_name,priority:2;comment:型号名称"`
+	Type         DeviceType  `gorm:"type:text;not null;comment:设备类型"`
+	Capabilities *string     `gorm:"type:text;comment:默认设备能力(JSON数组)"`
+	DefaultPort  int         `gorm:"comment:默认端口"`
+	DriverName   string      `gorm:"type:text;comment:驱动名称"`
+	Enabled      bool        `gorm:"not null;comment:是否启用"`
+	Description  *string     `gorm:"type:text;comment:描述"`
+}
+
+func (DeviceModel) TableName() string {
+	return "device_model"
+}
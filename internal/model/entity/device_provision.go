@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeviceEnrollmentCode 设备注册码表（一次性）
+type DeviceEnrollmentCode struct {
+	gorm.Model
+	CodeHash  string     `gorm:"type:text;not null;uniqueIndex;comment:注册码哈希(SHA-256)"`
+	CreatedBy string     `gorm:"type:text;not null;comment:创建人员"`
+	ExpireAt  time.Time  `gorm:"not null;comment:过期时间"`
+	UsedAt    *time.Time `gorm:"comment:使用时间"`
+	DeviceID  *uint      `gorm:"comment:注册得到的设备id"`
+	Remark    *string    `gorm:"type:text;comment:备注"`
+}
+
+func (DeviceEnrollmentCode) TableName() string {
+	return "device_enrollment_code"
+}
+
+// DeviceCredential 设备凭证表（长期有效，可按设备吊销）
+type DeviceCredential struct {
+	gorm.Model
+	DeviceID   uint       `gorm:"not null;index;comment:设备id"`
+	TokenHash  string     `gorm:"type:text;not null;uniqueIndex;comment:凭证哈希(SHA-256)"`
+	LastUsedAt *time.Time `gorm:"comment:最近使用时间"`
+	RevokedAt  *time.Time `gorm:"comment:吊销时间"`
+	RevokedBy  *string    `gorm:"type:text;comment:吊销人员"`
+}
+
+func (DeviceCredential) TableName() string {
+	return "device_credential"
+}
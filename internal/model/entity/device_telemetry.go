@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// DeviceTelemetry 设备遥测记录表
+type DeviceTelemetry struct {
+	ID         uint          `gorm:"primarykey;comment:主键ID"`
+	DeviceID   uint          `gorm:"not null;index;comment:设备id"`
+	Status     *DeviceStatus `gorm:"type:text;comment:上报的设备状态"`
+	PoseX      *float64      `gorm:"comment:位姿X(米)"`
+	PoseY      *float64      `gorm:"comment:位姿Y(米)"`
+	PoseZ      *float64      `gorm:"comment:位姿Z(米)"`
+	PoseYaw    *float64      `gorm:"comment:位姿航向角(弧度)"`
+	PoseFrame  *string       `gorm:"type:text;comment:位姿所在坐标系"`
+	Battery    *float64      `gorm:"comment:电量百分比"`
+	Payload    *string       `gorm:"type:text;comment:原始遥测数据(JSON)"`
+	CreateTime *time.Time    `gorm:"autoCreateTime;index;comment:上报时间"`
+}
+
+func (DeviceTelemetry) TableName() string {
+	return "device_telemetry"
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "gorm.io/gorm"
+
+// FrameTransform 坐标系变换表，记录子坐标系到父坐标系的刚体变换。
+// 坐标系名称为 pcd/{id}、grid/{id}（对应地图）或自定义名称（如楼宇坐标系 building/A）
+type FrameTransform struct {
+	gorm.Model
+	ParentFrame string  `gorm:"type:text;not null;index;comment:父坐标系"`
+	ChildFrame  string  `gorm:"type:text;not null;index;comment:子坐标系"`
+	X           float64 `gorm:"not null;default:0;comment:平移X(米)"`
+	Y           float64 `gorm:"not null;default:0;comment:平移Y(米)"`
+	Z           float64 `gorm:"not null;default:0;comment:平移Z(米)"`
+	Roll        float64 `gorm:"not null;default:0;comment:绕X轴旋转(弧度)"`
+	Pitch       float64 `gorm:"not null;default:0;comment:绕Y轴旋转(弧度)"`
+	Yaw         float64 `gorm:"not null;default:0;comment:绕Z轴旋转(弧度)"`
+	UserName    string  `gorm:"type:text;not null;comment:创建人员"`
+	Description *string `gorm:"type:text;comment:说明"`
+}
+
+func (FrameTransform) TableName() string {
+	return "frame_transform"
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "gorm.io/gorm"
+
+// LocationLevel 位置层级
+type LocationLevel string
+
+const (
+	LocationLevelSite     LocationLevel = "site"     // 园区
+	LocationLevelBuilding LocationLevel = "building" // 楼栋
+	LocationLevelFloor    LocationLevel = "floor"    // 楼层
+	LocationLevelArea     LocationLevel = "area"     // 区域
+)
+
+// Location 位置层级表，按 园区 → 楼栋 → 楼层 → 区域 组成树，点云地图、语义地图与设备可挂载到任意节点
+type Location struct {
+	gorm.Model
+	ParentID    *uint         `gorm:"index;comment:上级节点id，园区为空"`
+	Level       LocationLevel `gorm:"type:text;not null;comment:层级(site/building/floor/area)"`
+	Name        string        `gorm:"type:text;not null;comment:名称"`
+	UserName    string        `gorm:"type:text;not null;comment:创建人员"`
+	Description *string       `gorm:"type:text;comment:说明"`
+}
+
+func (Location) TableName() string {
+	return "location"
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MapDeploymentStatus 地图下发状态
+type MapDeploymentStatus string
+
+const (
+	MapDeploymentStatusPending   MapDeploymentStatus = "pending"   // 等待下发
+	MapDeploymentStatusRunning   MapDeploymentStatus = "running"   // 上传中
+	MapDeploymentStatusSucceeded MapDeploymentStatus = "succeeded" // 成功，设备已加载
+	MapDeploymentStatusFailed    MapDeploymentStatus = "failed"    // 失败
+)
+
+// MapDeployment 地图下发记录表，记录把语义地图某个版本及其底图推送到设备的过程
+type MapDeployment struct {
+	gorm.Model
+	DeviceID           uint                `gorm:"not null;index;comment:设备id"`
+	SemanticMapID      uint                `gorm:"not null;index;comment:语义地图id"`
+	SemanticMapVersion int                 `gorm:"not null;comment:语义地图版本号"`
+	PCDFileID          *uint               `gorm:"comment:点云地图id"`
+	PCDFileVersion     *int                `gorm:"comment:点云地图版本号"`
+	OccupancyGridID    *uint               `gorm:"comment:占据栅格id"`
+	Driver             string              `gorm:"type:text;not null;comment:下发驱动"`
+	UserName           string              `gorm:"type:text;not null;comment:操作人"`
+	Status             MapDeploymentStatus `gorm:"type:text;not null;index;comment:下发状态"`
+	TotalBytes         int64               `gorm:"not null;default:0;comment:地图包总字节数"`
+	SentBytes          int64               `gorm:"not null;default:0;comment:已发送字节数"`
+	Error              *string             `gorm:"type:text;comment:失败原因"`
+	StartedAt          *time.Time          `gorm:"comment:开始时间"`
+	FinishedAt         *time.Time          `gorm:"comment:结束时间"`
+}
+
+func (MapDeployment) TableName() string {
+	return "map_deployment"
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "gorm.io/gorm"
+
+// PCDFileVersion 点云地图版本表，每次上传或替换点云文件生成一个不可变版本
+type PCDFileVersion struct {
+	gorm.Model
+	PCDFileID uint    `gorm:"not null;uniqueIndex:idx_pcd_file_version;comment:点云地图id"`
+	Version   int     `gorm:"not null;uniqueIndex:idx_pcd_file_version;comment:版本号"`
+	Path      string  `gorm:"type:text;not null;comment:文件存储路径"`
+	Size      int     `gorm:"comment:文件大小(字节)"`
+	MinioPath *string `gorm:"type:text;comment:MinIO存储路径"`
+	Author    string  `gorm:"type:text;not null;comment:版本作者"`
+	Message   *string `gorm:"type:text;comment:版本说明"`
+
+	PCDMetadata
+}
+
+func (PCDFileVersion) TableName() string {
+	return "pcd_file_version"
+}
+
+// SemanticMapVersion 语义地图版本表，每次编辑生成一个不可变版本
+type SemanticMapVersion struct {
+	gorm.Model
+	SemanticMapID   uint    `gorm:"not null;uniqueIndex:idx_semantic_map_version;comment:语义地图id"`
+	Version         int     `gorm:"not null;uniqueIndex:idx_semantic_map_version;comment:版本号"`
+	PCDFileID       *uint   `gorm:"comment:对应的pcd地图文件id"`
+	PCDFileVersion  *int    `gorm:"comment:编辑时点云地图的版本号"`
+	OccupancyGridID *uint   `gorm:"comment:对应的二维栅格地图id"`
+	SemanticInfo    string  `gorm:"type:text;comment:语义信息"`
+	ExtraInfo       *string `gorm:"type:text;comment:扩展信息(JSON)"`
+	Author          string  `gorm:"type:text;not null;comment:版本作者"`
+	Message         *string `gorm:"type:text;comment:版本说明"`
+}
+
+func (SemanticMapVersion) TableName() string {
+	return "semantic_map_version"
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+)
+
+// OccupancyGridSource 二维栅格地图来源
+type OccupancyGridSource string
+
+const (
+	OccupancyGridSourcePCD OccupancyGridSource = "pcd" // 由点云地图按高度带投影生成
+	OccupancyGridSourceROS OccupancyGridSource = "ros" // 导入的 ROS map_server 地图(PGM + YAML)
+)
+
+// OccupancyGrid 二维占据栅格地图表（ROS map_server 格式：PGM 图像 + YAML 描述）
+type OccupancyGrid struct {
+	gorm.Model
+	Name      string              `gorm:"type:text;not null;comment:栅格地图名称"`
+	Source    OccupancyGridSource `gorm:"type:text;not null;comment:来源"`
+	PCDFileID *uint               `gorm:"index;comment:来源点云地图id"`
+	JobID     *uint               `gorm:"comment:生成任务id"`
+	UserName  string              `gorm:"type:text;not null;comment:创建人员"`
+	ImagePath string              `gorm:"type:text;not null;comment:PGM图像的MinIO路径"`
+	YAMLPath  string              `gorm:"column:yaml_path;type:text;not null;comment:YAML描述的MinIO路径"`
+
+	Resolution     float64 `gorm:"not null;comment:分辨率(米/像素)"`
+	OriginX        float64 `gorm:"not null;comment:左下角像素在地图坐标系中的X"`
+	OriginY        float64 `gorm:"not null;comment:左下角像素在地图坐标系中的Y"`
+	OriginYaw      float64 `gorm:"not null;comment:地图旋转角(弧度)"`
+	Width          int     `gorm:"not null;comment:宽度(像素)"`
+	Height         int     `gorm:"not null;comment:高度(像素)"`
+	Negate         bool    `gorm:"not null;comment:是否反色"`
+	OccupiedThresh float64 `gorm:"not null;comment:占据阈值"`
+	FreeThresh     float64 `gorm:"not null;comment:空闲阈值"`
+
+	// 由点云生成时的投影参数与统计
+	MinZ          *float64 `gorm:"comment:障碍物高度带下限(米)"`
+	MaxZ          *float64 `gorm:"comment:障碍物高度带上限(米)"`
+	OccupiedCells *int     `gorm:"comment:占据栅格数"`
+	FreeCells     *int     `gorm:"comment:空闲栅格数"`
+}
+
+func (OccupancyGrid) TableName() string {
+	return "occupancy_grid"
+}
@@ -1,6 +1,10 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // PCDFile 点云地图表
 type PCDFile struct {
@@ -12,6 +16,64 @@ type PCDFile struct {
 	Size      int     `gorm:"comment:文件大小(字节)"`
 	MinioPath *string `gorm:"type:text;comment:MinIO存储路径"`
 	ExtraInfo *string `gorm:"type:text;comment:扩展信息(JSON)"`
+
+	PCDMetadata
+
+	// LocationID 所属位置节点，Area 仅作自由文本描述
+	LocationID *uint `gorm:"index;comment:所属位置节点id"`
+
+	// CurrentVersion 当前生效的版本号，上线版本管理前创建且未再修改过的地图为空
+	CurrentVersion *int `gorm:"comment:当前版本号"`
+
+	// ObjectPurgedAt 软删除超过保留期后 MinIO 对象被清理的时间，行本身保留以维持外键
+	ObjectPurgedAt *time.Time `gorm:"comment:MinIO对象清理时间"`
+
+	PCDPreview
+	PCDIntegrity
+}
+
+// PCDMetadata 服务端读取点云对象得到的元数据
+type PCDMetadata struct {
+	// AnalysisStatus 上传完成后由后台任务解析点云，解析成功前其余元数据为空；直接创建的地图同步解析，为空
+	AnalysisStatus *PCDJobStatus `gorm:"type:text;comment:点云解析状态"`
+
+	SHA256       *string  `gorm:"type:text;index;comment:文件内容SHA-256(十六进制)"`
+	PointCount   *int     `gorm:"comment:点数"`
+	ValidPoints  *int     `gorm:"comment:有效点数(坐标非NaN)"`
+	Fields       *string  `gorm:"type:text;comment:字段列表(空格分隔)"`
+	DataEncoding *string  `gorm:"type:text;comment:点数据编码(ascii/binary/binary_compressed)"`
+	Width        *int     `gorm:"comment:点云宽度"`
+	Height       *int     `gorm:"comment:点云高度"`
+	MinX         *float64 `gorm:"comment:包围盒最小X"`
+	MinY         *float64 `gorm:"comment:包围盒最小Y"`
+	MinZ         *float64 `gorm:"comment:包围盒最小Z"`
+	MaxX         *float64 `gorm:"comment:包围盒最大X"`
+	MaxY         *float64 `gorm:"comment:包围盒最大Y"`
+	MaxZ         *float64 `gorm:"comment:包围盒最大Z"`
+}
+
+// PCDPreview 点云预览，由后台任务生成并与原始文件存放在同一目录
+type PCDPreview struct {
+	PreviewStatus *PCDJobStatus `gorm:"type:text;comment:预览生成状态"`
+	PreviewPath   *string       `gorm:"type:text;comment:降采样预览PCD的MinIO路径"`
+	ThumbnailPath *string       `gorm:"type:text;comment:俯视缩略图PNG的MinIO路径"`
+	PreviewPoints *int          `gorm:"comment:预览点数"`
+	PreviewLeaf   *float64      `gorm:"comment:预览体素边长(米)"`
+}
+
+// PCDIntegrityStatus 点云对象完整性校验结果
+type PCDIntegrityStatus string
+
+const (
+	PCDIntegrityStatusOK        PCDIntegrityStatus = "ok"        // 内容与记录的 SHA-256 一致
+	PCDIntegrityStatusCorrupted PCDIntegrityStatus = "corrupted" // 内容与记录的 SHA-256 不一致
+	PCDIntegrityStatusMissing   PCDIntegrityStatus = "missing"   // 对象不存在
+)
+
+// PCDIntegrity 点云对象完整性校验结果，由后台任务重新计算对象的 SHA-256 得到
+type PCDIntegrity struct {
+	IntegrityStatus    *PCDIntegrityStatus `gorm:"type:text;index;comment:完整性校验结果"`
+	IntegrityCheckedAt *time.Time          `gorm:"comment:最近一次完整性校验时间"`
 }
 
 func (PCDFile) TableName() string {
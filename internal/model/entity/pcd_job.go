@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PCDJobType 点云后台任务类型
+type PCDJobType string
+
+const (
+	PCDJobTypePreview   PCDJobType = "preview"   // 降采样预览与缩略图
+	PCDJobTypeOccupancy PCDJobType = "occupancy" // 按高度带投影生成二维占据栅格
+	PCDJobTypeIntegrity PCDJobType = "integrity" // 重新计算对象 SHA-256 校验完整性
+	PCDJobTypeAnalyze   PCDJobType = "analyze"   // 上传完成后解析点云、校验校验和并提取元数据
+)
+
+// PCDJobStatus 点云后台任务状态
+type PCDJobStatus string
+
+const (
+	PCDJobStatusPending   PCDJobStatus = "pending"   // 等待执行
+	PCDJobStatusRunning   PCDJobStatus = "running"   // 执行中
+	PCDJobStatusSucceeded PCDJobStatus = "succeeded" // 成功
+	PCDJobStatusFailed    PCDJobStatus = "failed"    // 失败
+)
+
+// PCDJob 点云后台任务表
+type PCDJob struct {
+	gorm.Model
+	PCDFileID  uint         `gorm:"not null;index;comment:点云地图id"`
+	Type       PCDJobType   `gorm:"type:text;not null;comment:任务类型"`
+	Status     PCDJobStatus `gorm:"type:text;not null;index;comment:任务状态"`
+	Params     *string      `gorm:"type:text;comment:任务参数(JSON)"`
+	SourceKey  *string      `gorm:"type:text;comment:创建任务时点云地图的对象Key"`
+	Error      *string      `gorm:"type:text;comment:失败原因"`
+	StartedAt  *time.Time   `gorm:"comment:开始时间"`
+	FinishedAt *time.Time   `gorm:"comment:结束时间"`
+	Result     *string      `gorm:"type:text;comment:任务结果(JSON)"`
+}
+
+func (PCDJob) TableName() string {
+	return "pcd_job"
+}
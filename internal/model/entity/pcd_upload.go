@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PCDUploadStatus 点云上传状态
+type PCDUploadStatus string
+
+const (
+	PCDUploadStatusPending   PCDUploadStatus = "pending"   // 已签发上传凭证，等待完成
+	PCDUploadStatusCompleted PCDUploadStatus = "completed" // 已校验并生成点云地图
+	PCDUploadStatusExpired   PCDUploadStatus = "expired"   // 超时未完成，对象已清理
+	PCDUploadStatusAborted   PCDUploadStatus = "aborted"   // 客户端主动放弃
+)
+
+// PCDUpload 点云上传记录表，签发上传凭证时创建，用于完成校验与超时清理
+type PCDUpload struct {
+	gorm.Model
+	ObjectKey    string          `gorm:"type:text;not null;uniqueIndex;comment:MinIO对象Key"`
+	UserName     string          `gorm:"type:text;not null;index;comment:上传人员"`
+	FileName     string          `gorm:"type:text;not null;comment:原始文件名"`
+	DeclaredSize int64           `gorm:"not null;comment:声明的文件大小(字节)"`
+	Checksum     *string         `gorm:"type:text;comment:声明的文件MD5(十六进制)"`
+	SHA256       *string         `gorm:"type:text;comment:声明的文件SHA-256(十六进制)"`
+	Status       PCDUploadStatus `gorm:"type:text;not null;index;comment:上传状态"`
+	ExpireAt     time.Time       `gorm:"not null;index;comment:上传截止时间"`
+	CompletedAt  *time.Time      `gorm:"comment:完成时间"`
+	PCDFileID    *uint           `gorm:"comment:生成的点云地图id"`
+
+	// 分片上传时使用
+	MultipartUploadID *string `gorm:"type:text;comment:MinIO分片上传id"`
+	PartSize          *int64  `gorm:"comment:分片大小(字节)"`
+}
+
+func (PCDUpload) TableName() string {
+	return "pcd_upload"
+}
@@ -1,15 +1,47 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // SemanticMap 语义地图表
 type SemanticMap struct {
 	gorm.Model
-	PCDFileID    uint    `gorm:"not null;comment:对应的pcd地图文件id;index"`
-	PCDFile      PCDFile `gorm:"foreignKey:PCDFileID"`
-	UserName     string  `gorm:"type:text;not null;comment:编辑人员"`
-	SemanticInfo string  `gorm:"type:text;comment:语义信息"`
-	ExtraInfo    *string `gorm:"type:text;comment:扩展信息(JSON)"`
+	// 底图为三维点云地图或二维栅格地图，二者有且只有一个
+	PCDFileID       *uint          `gorm:"comment:对应的pcd地图文件id;index"`
+	PCDFile         *PCDFile       `gorm:"foreignKey:PCDFileID"`
+	OccupancyGridID *uint          `gorm:"comment:对应的二维栅格地图id;index"`
+	OccupancyGrid   *OccupancyGrid `gorm:"foreignKey:OccupancyGridID"`
+	LocationID      *uint          `gorm:"comment:所属位置节点id;index"`
+	UserName        string         `gorm:"type:text;not null;comment:编辑人员"`
+	SemanticInfo    string         `gorm:"type:text;comment:语义信息"`
+	ExtraInfo       *string        `gorm:"type:text;comment:扩展信息(JSON)"`
+
+	// CurrentVersion 当前生效的版本号，上线版本管理前创建且未再编辑过的地图为空
+	CurrentVersion *int `gorm:"comment:当前版本号"`
+
+	// Revision 修订号，每次修改递增，作为 ETag 用于并发编辑冲突检测
+	Revision int `gorm:"not null;default:1;comment:修订号"`
+
+	SemanticEditLock
+
+	SemanticCheck
+}
+
+// SemanticEditLock 语义地图编辑锁，过期后自动失效
+type SemanticEditLock struct {
+	LockedBy      *string    `gorm:"type:text;comment:编辑锁持有人"`
+	LockedAt      *time.Time `gorm:"comment:编辑锁获取时间"`
+	LockExpiresAt *time.Time `gorm:"comment:编辑锁过期时间"`
+}
+
+// SemanticCheck 语义地图一致性检查结果，由编辑后即时检查或后台任务写入
+type SemanticCheck struct {
+	CheckSeverity *string    `gorm:"type:text;comment:一致性检查最高严重级别(ok/info/warning/error)"`
+	CheckReport   *string    `gorm:"type:text;comment:一致性检查报告(JSON)"`
+	CheckedAt     *time.Time `gorm:"comment:一致性检查时间"`
 }
 
 func (SemanticMap) TableName() string {
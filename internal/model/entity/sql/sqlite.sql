@@ -39,14 +39,41 @@ CREATE TABLE IF NOT EXISTS pcd_file (
     deleted_at DATETIME,
     name TEXT NOT NULL,
     area TEXT NOT NULL,
+    location_id INTEGER,
     path TEXT NOT NULL,
     user_name TEXT NOT NULL,
     size INTEGER,
     minio_path TEXT,
-    extra_info TEXT
+    extra_info TEXT,
+    analysis_status TEXT,
+    sha256 TEXT,
+    point_count INTEGER,
+    valid_points INTEGER,
+    fields TEXT,
+    data_encoding TEXT,
+    width INTEGER,
+    height INTEGER,
+    min_x REAL,
+    min_y REAL,
+    min_z REAL,
+    max_x REAL,
+    max_y REAL,
+    max_z REAL,
+    object_purged_at DATETIME,
+    preview_status TEXT,
+    preview_path TEXT,
+    thumbnail_path TEXT,
+    preview_points INTEGER,
+    preview_leaf REAL,
+    current_version INTEGER,
+    integrity_status TEXT,
+    integrity_checked_at DATETIME
 );
 
 CREATE INDEX IF NOT EXISTS idx_pcd_file_deleted_at ON pcd_file(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_pcd_file_location_id ON pcd_file(location_id);
+CREATE INDEX IF NOT EXISTS idx_pcd_file_sha256 ON pcd_file(sha256);
+CREATE INDEX IF NOT EXISTS idx_pcd_file_integrity_status ON pcd_file(integrity_status);
 
 -- 4. 创建语义地图表
 CREATE TABLE IF NOT EXISTS semantic_map (
@@ -54,15 +81,27 @@ CREATE TABLE IF NOT EXISTS semantic_map (
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     deleted_at DATETIME,
-    pcd_file_id INTEGER NOT NULL,
+    pcd_file_id INTEGER,
+    occupancy_grid_id INTEGER,
+    location_id INTEGER,
     user_name TEXT NOT NULL,
     semantic_info TEXT,
     extra_info TEXT,
+    current_version INTEGER,
+    revision INTEGER NOT NULL DEFAULT 1,
+    locked_by TEXT,
+    locked_at DATETIME,
+    lock_expires_at DATETIME,
+    check_severity TEXT,
+    check_report TEXT,
+    checked_at DATETIME,
     FOREIGN KEY (pcd_file_id) REFERENCES pcd_file(id)
 );
 
 CREATE INDEX IF NOT EXISTS idx_semantic_map_deleted_at ON semantic_map(deleted_at);
 CREATE INDEX IF NOT EXISTS idx_semantic_map_pcd_file_id ON semantic_map(pcd_file_id);
+CREATE INDEX IF NOT EXISTS idx_semantic_map_occupancy_grid_id ON semantic_map(occupancy_grid_id);
+CREATE INDEX IF NOT EXISTS idx_semantic_map_location_id ON semantic_map(location_id);
 
 -- 5. 创建任务编排表
 CREATE TABLE IF NOT EXISTS task (
@@ -75,6 +114,7 @@ CREATE TABLE IF NOT EXISTS task (
     task_info TEXT,
     status TEXT DEFAULT 'pending',
     extra_info TEXT,
+    semantic_map_version INTEGER,
     FOREIGN KEY (semantic_map_id) REFERENCES semantic_map(id)
 );
 
@@ -94,10 +134,319 @@ CREATE TABLE IF NOT EXISTS device (
     user_name TEXT,
     password TEXT,
     status TEXT DEFAULT 'offline',
-    extra_info TEXT
+    extra_info TEXT,
+    serial_number TEXT,
+    capabilities TEXT,
+    last_heartbeat_at DATETIME,
+    model_id INTEGER,
+    location_id INTEGER,
+    loaded_semantic_map_id INTEGER,
+    loaded_semantic_map_version INTEGER,
+    loaded_deployment_id INTEGER,
+    map_loaded_at DATETIME
 );
 
 CREATE INDEX IF NOT EXISTS idx_device_deleted_at ON device(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_device_serial_number ON device(serial_number);
+CREATE INDEX IF NOT EXISTS idx_device_model_id ON device(model_id);
+CREATE INDEX IF NOT EXISTS idx_device_location_id ON device(location_id);
+CREATE INDEX IF NOT EXISTS idx_device_loaded_semantic_map_id ON device(loaded_semantic_map_id);
+
+-- 7. 创建设备注册码表
+CREATE TABLE IF NOT EXISTS device_enrollment_code (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    code_hash TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    expire_at DATETIME NOT NULL,
+    used_at DATETIME,
+    device_id INTEGER,
+    remark TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_enrollment_code_deleted_at ON device_enrollment_code(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_enrollment_code_code_hash ON device_enrollment_code(code_hash);
+
+-- 8. 创建设备凭证表
+CREATE TABLE IF NOT EXISTS device_credential (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    device_id INTEGER NOT NULL,
+    token_hash TEXT NOT NULL,
+    last_used_at DATETIME,
+    revoked_at DATETIME,
+    revoked_by TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_credential_deleted_at ON device_credential(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_device_credential_device_id ON device_credential(device_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_credential_token_hash ON device_credential(token_hash);
+
+-- 9. 创建设备遥测记录表
+CREATE TABLE IF NOT EXISTS device_telemetry (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    device_id INTEGER NOT NULL,
+    status TEXT,
+    pose_x REAL,
+    pose_y REAL,
+    pose_z REAL,
+    pose_yaw REAL,
+    pose_frame TEXT,
+    battery REAL,
+    payload TEXT,
+    create_time DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_telemetry_device_id ON device_telemetry(device_id);
+CREATE INDEX IF NOT EXISTS idx_device_telemetry_create_time ON device_telemetry(create_time);
+
+-- 10. 创建设备告警表
+CREATE TABLE IF NOT EXISTS device_alarm (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    device_id INTEGER NOT NULL,
+    code TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    message TEXT,
+    pose_x REAL,
+    pose_y REAL,
+    pose_yaw REAL,
+    raised_at DATETIME NOT NULL,
+    cleared_at DATETIME,
+    acknowledged_by TEXT,
+    acknowledged_at DATETIME,
+    extra_info TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_alarm_deleted_at ON device_alarm(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_device_alarm_device_id ON device_alarm(device_id);
+CREATE INDEX IF NOT EXISTS idx_device_alarm_cleared_at ON device_alarm(cleared_at);
+
+-- 11. 创建设备型号目录表
+CREATE TABLE IF NOT EXISTS device_model (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    company TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    capabilities TEXT,
+    default_port INTEGER,
+    driver_name TEXT,
+    enabled INTEGER DEFAULT 1,
+    description TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_model_deleted_at ON device_model(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_model_company_name ON device_model(company, name);
+
+-- 内置设备型号（与 entity 中的内置类型/厂商对应）
+INSERT OR IGNORE INTO device_model (company, name, type, driver_name)
+VALUES
+    ('cyborg', 'cyborg-wheel', 'robot_wheel', 'cyborg'),
+    ('cyborg', 'cyborg-biped', 'robot_biped', 'cyborg');
+
+-- 12. 创建点云上传记录表
+CREATE TABLE IF NOT EXISTS pcd_upload (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    object_key TEXT NOT NULL,
+    user_name TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    declared_size INTEGER NOT NULL,
+    checksum TEXT,
+    sha256 TEXT,
+    status TEXT NOT NULL,
+    expire_at DATETIME NOT NULL,
+    completed_at DATETIME,
+    pcd_file_id INTEGER,
+    multipart_upload_id TEXT,
+    part_size INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_pcd_upload_deleted_at ON pcd_upload(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pcd_upload_object_key ON pcd_upload(object_key);
+CREATE INDEX IF NOT EXISTS idx_pcd_upload_user_name ON pcd_upload(user_name);
+CREATE INDEX IF NOT EXISTS idx_pcd_upload_status ON pcd_upload(status);
+CREATE INDEX IF NOT EXISTS idx_pcd_upload_expire_at ON pcd_upload(expire_at);
+
+-- 13. 创建点云后台任务表
+CREATE TABLE IF NOT EXISTS pcd_job (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    pcd_file_id INTEGER NOT NULL,
+    type TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT,
+    started_at DATETIME,
+    finished_at DATETIME,
+    result TEXT,
+    params TEXT,
+    source_key TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_pcd_job_deleted_at ON pcd_job(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_pcd_job_pcd_file_id ON pcd_job(pcd_file_id);
+CREATE INDEX IF NOT EXISTS idx_pcd_job_status ON pcd_job(status);
+
+-- 14. 创建二维占据栅格地图表
+CREATE TABLE IF NOT EXISTS occupancy_grid (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    name TEXT NOT NULL,
+    source TEXT NOT NULL,
+    pcd_file_id INTEGER,
+    job_id INTEGER,
+    user_name TEXT NOT NULL,
+    image_path TEXT NOT NULL,
+    yaml_path TEXT NOT NULL,
+    resolution REAL NOT NULL,
+    origin_x REAL NOT NULL,
+    origin_y REAL NOT NULL,
+    origin_yaw REAL NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    negate INTEGER NOT NULL,
+    occupied_thresh REAL NOT NULL,
+    free_thresh REAL NOT NULL,
+    min_z REAL,
+    max_z REAL,
+    occupied_cells INTEGER,
+    free_cells INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_occupancy_grid_deleted_at ON occupancy_grid(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_occupancy_grid_pcd_file_id ON occupancy_grid(pcd_file_id);
+
+-- 15. 创建点云地图版本表
+CREATE TABLE IF NOT EXISTS pcd_file_version (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    pcd_file_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    path TEXT NOT NULL,
+    size INTEGER,
+    minio_path TEXT,
+    author TEXT NOT NULL,
+    message TEXT,
+    sha256 TEXT,
+    point_count INTEGER,
+    valid_points INTEGER,
+    fields TEXT,
+    data_encoding TEXT,
+    width INTEGER,
+    height INTEGER,
+    min_x REAL,
+    min_y REAL,
+    min_z REAL,
+    max_x REAL,
+    max_y REAL,
+    max_z REAL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pcd_file_version_deleted_at ON pcd_file_version(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pcd_file_version ON pcd_file_version(pcd_file_id, version);
+CREATE INDEX IF NOT EXISTS idx_pcd_file_version_sha256 ON pcd_file_version(sha256);
+
+-- 16. 创建语义地图版本表
+CREATE TABLE IF NOT EXISTS semantic_map_version (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    semantic_map_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    pcd_file_id INTEGER,
+    occupancy_grid_id INTEGER,
+    pcd_file_version INTEGER,
+    semantic_info TEXT,
+    extra_info TEXT,
+    author TEXT NOT NULL,
+    message TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_semantic_map_version_deleted_at ON semantic_map_version(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_semantic_map_version ON semantic_map_version(semantic_map_id, version);
+
+-- 17. 创建坐标变换表
+CREATE TABLE IF NOT EXISTS frame_transform (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    parent_frame TEXT NOT NULL,
+    child_frame TEXT NOT NULL,
+    x REAL NOT NULL DEFAULT 0,
+    y REAL NOT NULL DEFAULT 0,
+    z REAL NOT NULL DEFAULT 0,
+    roll REAL NOT NULL DEFAULT 0,
+    pitch REAL NOT NULL DEFAULT 0,
+    yaw REAL NOT NULL DEFAULT 0,
+    user_name TEXT NOT NULL,
+    description TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_frame_transform_deleted_at ON frame_transform(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_frame_transform_parent_frame ON frame_transform(parent_frame);
+CREATE INDEX IF NOT EXISTS idx_frame_transform_child_frame ON frame_transform(child_frame);
+
+-- 18. 创建位置层级表
+CREATE TABLE IF NOT EXISTS location (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    parent_id INTEGER,
+    level TEXT NOT NULL,
+    name TEXT NOT NULL,
+    user_name TEXT NOT NULL,
+    description TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_location_deleted_at ON location(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_location_parent_id ON location(parent_id);
+
+-- 19. 创建地图下发记录表
+CREATE TABLE IF NOT EXISTS map_deployment (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    device_id INTEGER NOT NULL,
+    semantic_map_id INTEGER NOT NULL,
+    semantic_map_version INTEGER NOT NULL,
+    pcd_file_id INTEGER,
+    pcd_file_version INTEGER,
+    occupancy_grid_id INTEGER,
+    driver TEXT NOT NULL,
+    user_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    total_bytes BIGINT NOT NULL DEFAULT 0,
+    sent_bytes BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at DATETIME,
+    finished_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_map_deployment_deleted_at ON map_deployment(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_map_deployment_device_id ON map_deployment(device_id);
+CREATE INDEX IF NOT EXISTS idx_map_deployment_semantic_map_id ON map_deployment(semantic_map_id);
+CREATE INDEX IF NOT EXISTS idx_map_deployment_status ON map_deployment(status);
 
 -- 插入默认角色数据（可选）
 INSERT OR IGNORE INTO user_info (user_name, password, role, is_locked) 
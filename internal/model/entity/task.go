@@ -11,6 +11,9 @@ type Task struct {
 	TaskInfo      string      `gorm:"type:text;comment:任务信息"`
 	Status        *TaskStatus `gorm:"type:text;default:'pending';comment:任务状态"`
 	ExtraInfo     *string     `gorm:"type:text;comment:扩展信息(JSON)"`
+
+	// SemanticMapVersion 任务固定使用的语义地图版本，保证历史任务可复现
+	SemanticMapVersion *int `gorm:"comment:语义地图版本号"`
 }
 
 // TaskStatus 任务状态枚举
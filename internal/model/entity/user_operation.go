@@ -6,12 +6,13 @@ import "time"
 type OperationType string
 
 const (
-	OperationCreate OperationType = "create" // 创建
-	OperationUpdate OperationType = "update" // 更新
-	OperationDelete OperationType = "delete" // 删除
-	OperationQuery  OperationType = "query"  // 查询
-	OperationLogin  OperationType = "login"  // 登录
-	OperationLogout OperationType = "logout" // 登出
+	OperationCreate   OperationType = "create"   // 创建
+	OperationUpdate   OperationType = "update"   // 更新
+	OperationDelete   OperationType = "delete"   // 删除
+	OperationQuery    OperationType = "query"    // 查询
+	OperationLogin    OperationType = "login"    // 登录
+	OperationLogout   OperationType = "logout"   // 登出
+	OperationDownload OperationType = "download" // 下载
 )
 
 // UserOperation 用户操作记录表
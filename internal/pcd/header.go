@@ -0,0 +1,258 @@
+// Package pcd 解析 PCL 点云文件（.pcd）的头部与点数据
+package pcd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 点数据编码方式
+const (
+	DataASCII            = "ascii"
+	DataBinary           = "binary"
+	DataBinaryCompressed = "binary_compressed"
+)
+
+// maxHeaderBytes 头部最大长度，超过视为非 PCD 文件
+const maxHeaderBytes = 64 * 1024
+
+// 单点布局上限，超出视为头部损坏，避免按异常头部分配内存
+const (
+	maxFieldCount = 4096      // 单个字段的 COUNT 上限（常见特征描述子如 ESF 为 640）
+	maxDims       = 4096      // 每个点的值个数上限
+	maxPointStep  = 16 * 1024 // 每个点的字节数上限
+)
+
+// ErrInvalidHeader 头部格式错误
+var ErrInvalidHeader = errors.New("invalid pcd header")
+
+// Header PCD 文件头
+type Header struct {
+	Version   string     // 版本，如 0.7
+	Fields    []string   // 字段名
+	Size      []int      // 每个字段单个值的字节数
+	Type      []byte     // 每个字段的类型：I 有符号整数、U 无符号整数、F 浮点
+	Count     []int      // 每个字段的值个数
+	Width     int        // 宽度（无序点云为点数）
+	Height    int        // 高度（无序点云为 1）
+	Viewpoint [7]float64 // 采集视点：tx ty tz qw qx qy qz
+	Points    int        // 点数
+	Data      string     // 点数据编码
+}
+
+// ReadHeader 从 r 读取并校验 PCD 头部，读取后 r 位于点数据起始位置
+func ReadHeader(r *bufio.Reader) (*Header, error) {
+	h := &Header{
+		Height:    1,
+		Viewpoint: [7]float64{0, 0, 0, 1, 0, 0, 0},
+		Points:    -1,
+	}
+
+	read := 0
+	for h.Data == "" {
+		// ReadSlice 限制单行长度，避免把非 PCD 的二进制文件整体读入内存
+		raw, err := r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			return nil, fmt.Errorf("%w: header line too long", ErrInvalidHeader)
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		line := string(raw)
+		if err == io.EOF && line == "" {
+			return nil, fmt.Errorf("%w: missing DATA line", ErrInvalidHeader)
+		}
+		read += len(line)
+		if read > maxHeaderBytes {
+			return nil, fmt.Errorf("%w: header too long", ErrInvalidHeader)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Fields(line)
+		key, values := strings.ToUpper(parts[0]), parts[1:]
+		if err := h.set(key, values); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := h.validate(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// set 解析一行头部
+func (h *Header) set(key string, values []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("%w: %s has no value", ErrInvalidHeader, key)
+	}
+
+	var err error
+	switch key {
+	case "VERSION":
+		h.Version = values[0]
+	case "FIELDS", "COLUMNS":
+		h.Fields = values
+	case "SIZE":
+		h.Size, err = parseInts(key, values)
+	case "TYPE":
+		h.Type = make([]byte, len(values))
+		for i, v := range values {
+			if len(v) != 1 || !strings.Contains("IUF", strings.ToUpper(v)) {
+				return fmt.Errorf("%w: unknown TYPE %q", ErrInvalidHeader, v)
+			}
+			h.Type[i] = strings.ToUpper(v)[0]
+		}
+	case "COUNT":
+		h.Count, err = parseInts(key, values)
+	case "WIDTH":
+		h.Width, err = parseInt(key, values[0])
+	case "HEIGHT":
+		h.Height, err = parseInt(key, values[0])
+	case "POINTS":
+		h.Points, err = parseInt(key, values[0])
+	case "VIEWPOINT":
+		if len(values) != 7 {
+			return fmt.Errorf("%w: VIEWPOINT needs 7 values", ErrInvalidHeader)
+		}
+		for i, v := range values {
+			if h.Viewpoint[i], err = strconv.ParseFloat(v, 64); err != nil {
+				return fmt.Errorf("%w: invalid VIEWPOINT %q", ErrInvalidHeader, v)
+			}
+		}
+	case "DATA":
+		data := strings.ToLower(values[0])
+		switch data {
+		case DataASCII, DataBinary, DataBinaryCompressed:
+			h.Data = data
+		default:
+			return fmt.Errorf("%w: unknown DATA %q", ErrInvalidHeader, values[0])
+		}
+	default:
+		return fmt.Errorf("%w: unknown header line %s", ErrInvalidHeader, key)
+	}
+	return err
+}
+
+// validate 校验头部各项的一致性
+func (h *Header) validate() error {
+	n := len(h.Fields)
+	if n == 0 {
+		return fmt.Errorf("%w: missing FIELDS", ErrInvalidHeader)
+	}
+	if len(h.Size) != n || len(h.Type) != n {
+		return fmt.Errorf("%w: FIELDS/SIZE/TYPE length mismatch", ErrInvalidHeader)
+	}
+	if h.Count == nil {
+		h.Count = make([]int, n)
+		for i := range h.Count {
+			h.Count[i] = 1
+		}
+	}
+	if len(h.Count) != n {
+		return fmt.Errorf("%w: FIELDS/COUNT length mismatch", ErrInvalidHeader)
+	}
+
+	for i := 0; i < n; i++ {
+		switch h.Size[i] {
+		case 1, 2, 4, 8:
+		default:
+			return fmt.Errorf("%w: field %s has invalid SIZE %d", ErrInvalidHeader, h.Fields[i], h.Size[i])
+		}
+		if h.Type[i] == 'F' && h.Size[i] != 4 && h.Size[i] != 8 {
+			return fmt.Errorf("%w: float field %s must have SIZE 4 or 8", ErrInvalidHeader, h.Fields[i])
+		}
+		if h.Count[i] < 1 || h.Count[i] > maxFieldCount {
+			return fmt.Errorf("%w: field %s has invalid COUNT %d", ErrInvalidHeader, h.Fields[i], h.Count[i])
+		}
+	}
+	if dims := h.Dims(); dims > maxDims {
+		return fmt.Errorf("%w: %d values per point exceeds %d", ErrInvalidHeader, dims, maxDims)
+	}
+	if step := h.PointStep(); step > maxPointStep {
+		return fmt.Errorf("%w: %d bytes per point exceeds %d", ErrInvalidHeader, step, maxPointStep)
+	}
+
+	if h.Width < 0 || h.Height < 1 {
+		return fmt.Errorf("%w: invalid WIDTH/HEIGHT", ErrInvalidHeader)
+	}
+	if h.Width > 0 && h.Height > math.MaxInt/h.Width {
+		return fmt.Errorf("%w: WIDTH*HEIGHT overflows", ErrInvalidHeader)
+	}
+	if h.Points < 0 {
+		h.Points = h.Width * h.Height
+	}
+	if h.Width*h.Height != h.Points {
+		return fmt.Errorf("%w: WIDTH*HEIGHT (%d) does not match POINTS (%d)", ErrInvalidHeader, h.Width*h.Height, h.Points)
+	}
+	return nil
+}
+
+// FieldIndex 返回字段下标，不存在时返回 -1
+func (h *Header) FieldIndex(name string) int {
+	for i, f := range h.Fields {
+		if f == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Dims 每个点的值个数（各字段 COUNT 之和）
+func (h *Header) Dims() int {
+	dims := 0
+	for _, c := range h.Count {
+		dims += c
+	}
+	return dims
+}
+
+// PointStep 每个点的字节数
+func (h *Header) PointStep() int {
+	step := 0
+	for i := range h.Fields {
+		step += h.Size[i] * h.Count[i]
+	}
+	return step
+}
+
+// ValueOffset 返回字段第一个值在点值数组（Reader.Next 的结果）中的下标，不存在时返回 -1
+func (h *Header) ValueOffset(name string) int {
+	offset := 0
+	for i, f := range h.Fields {
+		if f == name {
+			return offset
+		}
+		offset += h.Count[i]
+	}
+	return -1
+}
+
+func parseInt(key, v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s %q", ErrInvalidHeader, key, v)
+	}
+	return n, nil
+}
+
+func parseInts(key string, values []string) ([]int, error) {
+	list := make([]int, len(values))
+	for i, v := range values {
+		n, err := parseInt(key, v)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = n
+	}
+	return list, nil
+}
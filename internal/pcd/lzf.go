@@ -0,0 +1,55 @@
+package pcd
+
+import "errors"
+
+// errCorruptLZF LZF 压缩数据损坏
+var errCorruptLZF = errors.New("corrupt lzf data")
+
+// lzfDecompress 解压 LZF 数据（binary_compressed 使用的压缩算法），outLen 为解压后的长度
+func lzfDecompress(in []byte, outLen int) ([]byte, error) {
+	out := make([]byte, 0, outLen)
+	i := 0
+	for i < len(in) {
+		ctrl := int(in[i])
+		i++
+
+		if ctrl < 1<<5 {
+			// 字面量：后续 ctrl+1 个字节原样输出
+			n := ctrl + 1
+			if i+n > len(in) || len(out)+n > outLen {
+				return nil, errCorruptLZF
+			}
+			out = append(out, in[i:i+n]...)
+			i += n
+			continue
+		}
+
+		// 回溯引用：从已输出数据中复制
+		length := ctrl >> 5
+		if length == 7 {
+			if i >= len(in) {
+				return nil, errCorruptLZF
+			}
+			length += int(in[i])
+			i++
+		}
+		if i >= len(in) {
+			return nil, errCorruptLZF
+		}
+		ref := len(out) - ((ctrl & 0x1f) << 8) - int(in[i]) - 1
+		i++
+		length += 2
+		if ref < 0 || len(out)+length > outLen {
+			return nil, errCorruptLZF
+		}
+		// 引用区间可能与输出重叠，需逐字节复制
+		for k := 0; k < length; k++ {
+			out = append(out, out[ref+k])
+		}
+	}
+
+	if len(out) != outLen {
+		return nil, errCorruptLZF
+	}
+	return out, nil
+}
@@ -0,0 +1,203 @@
+package pcd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrInvalidMapServer PGM 图像或 YAML 描述不符合 ROS map_server 格式
+var ErrInvalidMapServer = errors.New("invalid map_server map")
+
+// MapServerYAML ROS map_server 地图描述文件
+type MapServerYAML struct {
+	Image          string    `yaml:"image"`
+	Resolution     float64   `yaml:"resolution"`
+	Origin         []float64 `yaml:"origin"` // [x, y, yaw]
+	Negate         int       `yaml:"negate"`
+	OccupiedThresh float64   `yaml:"occupied_thresh"`
+	FreeThresh     float64   `yaml:"free_thresh"`
+	Mode           string    `yaml:"mode,omitempty"` // trinary / scale / raw，缺省为 trinary
+}
+
+// ParseMapServerYAML 解析并校验地图描述文件
+func ParseMapServerYAML(data []byte) (*MapServerYAML, error) {
+	var m MapServerYAML
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMapServer, err)
+	}
+	if !(m.Resolution > 0) || !isFinite(m.Resolution) {
+		return nil, fmt.Errorf("%w: resolution must be positive", ErrInvalidMapServer)
+	}
+	if len(m.Origin) != 3 || !isFinite(m.Origin[0]) || !isFinite(m.Origin[1]) || !isFinite(m.Origin[2]) {
+		return nil, fmt.Errorf("%w: origin must be [x, y, yaw]", ErrInvalidMapServer)
+	}
+	if m.Negate != 0 && m.Negate != 1 {
+		return nil, fmt.Errorf("%w: negate must be 0 or 1", ErrInvalidMapServer)
+	}
+	if m.FreeThresh < 0 || m.OccupiedThresh > 1 || !(m.FreeThresh < m.OccupiedThresh) {
+		return nil, fmt.Errorf("%w: thresholds must satisfy 0 <= free_thresh < occupied_thresh <= 1", ErrInvalidMapServer)
+	}
+	switch m.Mode {
+	case "", "trinary", "scale", "raw":
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidMapServer, m.Mode)
+	}
+	return &m, nil
+}
+
+// Write 写出地图描述文件，image 为同目录下的 PGM 文件名
+func (m *MapServerYAML) Write(w io.Writer, image string) error {
+	_, err := fmt.Fprintf(w, "image: %q\n"+
+		"resolution: %g\n"+
+		"origin: [%g, %g, %g]\n"+
+		"negate: %d\n"+
+		"occupied_thresh: %g\n"+
+		"free_thresh: %g\n", image, m.Resolution, m.Origin[0], m.Origin[1], m.Origin[2], m.Negate, m.OccupiedThresh, m.FreeThresh)
+	if err == nil && m.Mode != "" {
+		_, err = fmt.Fprintf(w, "mode: %s\n", m.Mode)
+	}
+	return err
+}
+
+// PGMImage 8 位灰度 PGM 图像，Pixels 按行存放（第一行为地图 Y 最大处）
+type PGMImage struct {
+	Width  int
+	Height int
+	MaxVal int
+	Pixels []uint8
+}
+
+// ReadPGM 读取二进制(P5)或文本(P2)格式的 8 位 PGM 图像，maxPixels 为 0 表示不限制像素数
+func ReadPGM(r io.Reader, maxPixels int) (*PGMImage, error) {
+	br := bufio.NewReader(r)
+	magic, err := pgmToken(br)
+	if err != nil {
+		return nil, err
+	}
+	if magic != "P5" && magic != "P2" {
+		return nil, fmt.Errorf("%w: unsupported pgm magic %q", ErrInvalidMapServer, magic)
+	}
+
+	var header [3]int
+	for i := range header {
+		tok, err := pgmToken(br)
+		if err != nil {
+			return nil, err
+		}
+		if header[i], err = strconv.Atoi(tok); err != nil || header[i] <= 0 {
+			return nil, fmt.Errorf("%w: invalid pgm header value %q", ErrInvalidMapServer, tok)
+		}
+	}
+	img := &PGMImage{Width: header[0], Height: header[1], MaxVal: header[2]}
+	if img.MaxVal > 255 {
+		return nil, fmt.Errorf("%w: only 8-bit pgm is supported, maxval %d", ErrInvalidMapServer, img.MaxVal)
+	}
+	pixels := int64(img.Width) * int64(img.Height)
+	if maxPixels > 0 && pixels > int64(maxPixels) {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrGridTooLarge, img.Width, img.Height, maxPixels)
+	}
+
+	img.Pixels = make([]uint8, pixels)
+	if magic == "P5" {
+		// 头部最后一个数值后紧跟单个空白字符，之后为像素数据
+		if _, err := io.ReadFull(br, img.Pixels); err != nil {
+			return nil, fmt.Errorf("%w: truncated pgm data: %v", ErrInvalidMapServer, err)
+		}
+		return img, nil
+	}
+	for i := range img.Pixels {
+		tok, err := pgmToken(br)
+		if err != nil {
+			return nil, err
+		}
+		v, err := strconv.Atoi(tok)
+		if err != nil || v < 0 || v > img.MaxVal {
+			return nil, fmt.Errorf("%w: invalid pgm pixel %q", ErrInvalidMapServer, tok)
+		}
+		img.Pixels[i] = uint8(v)
+	}
+	return img, nil
+}
+
+// pgmToken 读取下一个以空白分隔的头部字段，跳过 # 开头的注释；P5 格式在字段后只消耗一个空白字符
+func pgmToken(br *bufio.Reader) (string, error) {
+	var tok []byte
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(tok) > 0 {
+				return string(tok), nil
+			}
+			return "", fmt.Errorf("%w: truncated pgm header", ErrInvalidMapServer)
+		}
+		switch {
+		case b == '#' && len(tok) == 0:
+			if _, err := br.ReadString('\n'); err != nil {
+				return "", fmt.Errorf("%w: truncated pgm header", ErrInvalidMapServer)
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if len(tok) > 0 {
+				return string(tok), nil
+			}
+		default:
+			tok = append(tok, b)
+		}
+	}
+}
+
+// CountCells 按 map_server 的规则统计占据与空闲栅格数，未知与 scale 模式下介于两个阈值之间的栅格不计入。
+// trinary 与 scale 按灰度换算占据概率后与阈值比较；raw 直接把像素值当作 0~100 的占据概率，大于 100 为未知，忽略 negate
+func (img *PGMImage) CountCells(meta *MapServerYAML) (occupied, free int) {
+	for _, v := range img.Pixels {
+		var p float64
+		switch {
+		case meta.Mode == "raw":
+			if v > 100 {
+				continue
+			}
+			p = float64(v) / 100
+		case meta.Negate == 1:
+			p = float64(v) / float64(img.MaxVal)
+		default:
+			p = float64(img.MaxVal-int(v)) / float64(img.MaxVal)
+		}
+		switch {
+		case p > meta.OccupiedThresh:
+			occupied++
+		case p < meta.FreeThresh:
+			free++
+		}
+	}
+	return occupied, free
+}
+
+// GridBounds 栅格在地图坐标系中的水平包围盒，origin 为左下角像素的位置，yaw 为地图旋转角
+func GridBounds(width, height int, resolution, originX, originY, yaw float64) (minX, minY, maxX, maxY float64) {
+	w, h := float64(width)*resolution, float64(height)*resolution
+	sin, cos := math.Sincos(yaw)
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, c := range [][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x := originX + c[0]*cos - c[1]*sin
+		y := originY + c[0]*sin + c[1]*cos
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// WritePGM 以二进制 PGM(P5) 格式写出图像，保留原最大灰度值
+func (img *PGMImage) WritePGM(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "P5\n%d %d\n%d\n", img.Width, img.Height, img.MaxVal)
+	if _, err := bw.Write(img.Pixels); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,71 @@
+package pcd
+
+import (
+	"errors"
+	"io"
+	"math"
+)
+
+// ErrMissingXYZ 点云缺少坐标字段
+var ErrMissingXYZ = errors.New("pcd has no x/y/z fields")
+
+// Bounds 坐标包围盒
+type Bounds struct {
+	MinX, MinY, MinZ float64
+	MaxX, MaxY, MaxZ float64
+}
+
+// Metadata 点云元数据
+type Metadata struct {
+	Header      *Header
+	ValidPoints int     // 坐标有限（非 NaN/Inf）的点数
+	Bounds      *Bounds // 有效点的包围盒，无有效点时为 nil
+}
+
+// Analyze 读取完整的 PCD 文件，校验头部与点数据，统计有效点数和包围盒
+func Analyze(r io.Reader) (*Metadata, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	h := reader.Header()
+
+	xi, yi, zi := h.ValueOffset("x"), h.ValueOffset("y"), h.ValueOffset("z")
+	if xi < 0 || yi < 0 || zi < 0 {
+		return nil, ErrMissingXYZ
+	}
+
+	meta := &Metadata{Header: h}
+	var b Bounds
+	for {
+		values, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		x, y, z := values[xi], values[yi], values[zi]
+		if !isFinite(x) || !isFinite(y) || !isFinite(z) {
+			continue
+		}
+		if meta.ValidPoints == 0 {
+			b = Bounds{MinX: x, MinY: y, MinZ: z, MaxX: x, MaxY: y, MaxZ: z}
+		} else {
+			b.MinX, b.MaxX = math.Min(b.MinX, x), math.Max(b.MaxX, x)
+			b.MinY, b.MaxY = math.Min(b.MinY, y), math.Max(b.MaxY, y)
+			b.MinZ, b.MaxZ = math.Min(b.MinZ, z), math.Max(b.MaxZ, z)
+		}
+		meta.ValidPoints++
+	}
+
+	if meta.ValidPoints > 0 {
+		meta.Bounds = &b
+	}
+	return meta, nil
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,221 @@
+package pcd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ROS map_server 约定的像素取值（negate=0）
+const (
+	PixelOccupied uint8 = 0
+	PixelFree     uint8 = 254
+	PixelUnknown  uint8 = 205
+
+	// OccupiedThresh / FreeThresh 写入 YAML 的默认阈值
+	OccupiedThresh = 0.65
+	FreeThresh     = 0.196
+)
+
+// ErrGridTooLarge 栅格像素数超过上限
+var ErrGridTooLarge = errors.New("occupancy grid too large")
+
+// ErrGridEmpty 高度带内外都没有点，无法生成栅格
+var ErrGridEmpty = errors.New("occupancy grid has no points")
+
+const (
+	// DefaultMaxRange 未指定坐标范围时点坐标绝对值的上限(米)
+	DefaultMaxRange = 100000.0
+	// maxCellIndex 栅格索引绝对值上限，保证宽高相减、相乘不会溢出 int64
+	maxCellIndex = 1 << 30
+)
+
+// OccupancyOptions 栅格化参数
+type OccupancyOptions struct {
+	Resolution float64 // 栅格边长(米/像素)
+	MinZ       float64 // 障碍物高度带下限，低于下限的点视为地面
+	MaxZ       float64 // 障碍物高度带上限，高于上限的点（天花板等）被忽略
+	MinPoints  int     // 栅格内高度带点数达到该值才判为占据，用于过滤噪点
+	MaxPixels  int     // 输出图像像素上限，0 表示不限制
+	MaxRange   float64 // 点坐标 x、y 绝对值上限(米)，超出的点视为离群点忽略，0 表示使用 DefaultMaxRange
+}
+
+type cellKey struct {
+	x, y int64
+}
+
+type cellCount struct {
+	obstacle int32
+	ground   bool
+}
+
+// OccupancyBuilder 按高度带把点云投影到二维栅格
+// 高度带内点数达到阈值的栅格为占据，只有地面点的栅格为空闲，其余为未知
+type OccupancyBuilder struct {
+	opts  OccupancyOptions
+	cells map[cellKey]*cellCount
+
+	// 已有栅格的索引范围，新增栅格时检查像素上限，超限后不再接收点
+	minX, minY, maxX, maxY int64
+	outliers               int
+	err                    error
+}
+
+// NewOccupancyBuilder 创建栅格构建器
+func NewOccupancyBuilder(opts OccupancyOptions) (*OccupancyBuilder, error) {
+	if !(opts.Resolution > 0) {
+		return nil, fmt.Errorf("invalid resolution %v", opts.Resolution)
+	}
+	if !(opts.MinZ < opts.MaxZ) {
+		return nil, fmt.Errorf("invalid height band [%v, %v]", opts.MinZ, opts.MaxZ)
+	}
+	if opts.MinPoints <= 0 {
+		opts.MinPoints = 1
+	}
+	if !(opts.MaxRange > 0) {
+		opts.MaxRange = DefaultMaxRange
+	}
+	return &OccupancyBuilder{opts: opts, cells: make(map[cellKey]*cellCount)}, nil
+}
+
+// Add 加入一个点，坐标非有限值或高于高度带的点被忽略，超出坐标范围的点计为离群点；
+// 栅格范围超过像素上限后不再接收点，Map 返回 ErrGridTooLarge
+func (b *OccupancyBuilder) Add(x, y, z float64) {
+	if b.err != nil || !isFinite(x) || !isFinite(y) || !isFinite(z) || z > b.opts.MaxZ {
+		return
+	}
+	cx, cy := math.Floor(x/b.opts.Resolution), math.Floor(y/b.opts.Resolution)
+	if math.Abs(x) > b.opts.MaxRange || math.Abs(y) > b.opts.MaxRange || math.Abs(cx) > maxCellIndex || math.Abs(cy) > maxCellIndex {
+		b.outliers++
+		return
+	}
+	key := cellKey{x: int64(cx), y: int64(cy)}
+	c, ok := b.cells[key]
+	if !ok {
+		if !b.extend(key) {
+			return
+		}
+		c = &cellCount{}
+		b.cells[key] = c
+	}
+	if z < b.opts.MinZ {
+		c.ground = true
+	} else if c.obstacle < math.MaxInt32 {
+		c.obstacle++
+	}
+}
+
+// extend 把新栅格并入索引范围，超过像素上限时记录错误并返回 false
+func (b *OccupancyBuilder) extend(key cellKey) bool {
+	minX, minY, maxX, maxY := key.x, key.y, key.x, key.y
+	if len(b.cells) > 0 {
+		minX, minY = min(b.minX, key.x), min(b.minY, key.y)
+		maxX, maxY = max(b.maxX, key.x), max(b.maxY, key.y)
+	}
+	width, height := maxX-minX+1, maxY-minY+1
+	if err := b.checkSize(width, height); err != nil {
+		b.err = err
+		return false
+	}
+	b.minX, b.minY, b.maxX, b.maxY = minX, minY, maxX, maxY
+	return true
+}
+
+// checkSize 检查栅格宽高的乘积不溢出且不超过像素上限
+func (b *OccupancyBuilder) checkSize(width, height int64) error {
+	limit := int64(math.MaxInt32)
+	if b.opts.MaxPixels > 0 {
+		limit = int64(b.opts.MaxPixels)
+	}
+	if width > limit/height {
+		return fmt.Errorf("%w: %dx%d exceeds %d pixels, increase resolution", ErrGridTooLarge, width, height, limit)
+	}
+	return nil
+}
+
+// Build 读取完整的 PCD 文件并生成占据栅格
+func (b *OccupancyBuilder) Build(r io.Reader) (*OccupancyMap, error) {
+	if _, err := scanXYZ(r, b.Add); err != nil {
+		return nil, err
+	}
+	return b.Map()
+}
+
+// OccupancyMap 占据栅格，Pixels 按 PGM 行序存放（第一行为 Y 最大处）
+type OccupancyMap struct {
+	Resolution    float64
+	OriginX       float64 // 左下角栅格在地图坐标系中的位置
+	OriginY       float64
+	Width         int
+	Height        int
+	Pixels        []uint8
+	OccupiedCells int
+	FreeCells     int
+	Outliers      int // 超出坐标范围被忽略的点数
+}
+
+// Map 生成占据栅格
+func (b *OccupancyBuilder) Map() (*OccupancyMap, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.cells) == 0 {
+		return nil, ErrGridEmpty
+	}
+
+	minX, minY, maxX, maxY := b.minX, b.minY, b.maxX, b.maxY
+	width, height := maxX-minX+1, maxY-minY+1
+	if err := b.checkSize(width, height); err != nil {
+		return nil, err
+	}
+
+	m := &OccupancyMap{
+		Resolution: b.opts.Resolution,
+		OriginX:    float64(minX) * b.opts.Resolution,
+		OriginY:    float64(minY) * b.opts.Resolution,
+		Width:      int(width),
+		Height:     int(height),
+		Pixels:     make([]uint8, width*height),
+		Outliers:   b.outliers,
+	}
+	for i := range m.Pixels {
+		m.Pixels[i] = PixelUnknown
+	}
+	for k, c := range b.cells {
+		row := maxY - k.y
+		col := k.x - minX
+		i := row*width + col
+		switch {
+		case int(c.obstacle) >= b.opts.MinPoints:
+			m.Pixels[i] = PixelOccupied
+			m.OccupiedCells++
+		case c.ground:
+			m.Pixels[i] = PixelFree
+			m.FreeCells++
+		}
+	}
+	return m, nil
+}
+
+// WritePGM 以二进制 PGM(P5) 格式写出栅格图像
+func (m *OccupancyMap) WritePGM(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "P5\n%d %d\n255\n", m.Width, m.Height)
+	if _, err := bw.Write(m.Pixels); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// WriteYAML 写出 ROS map_server 的地图描述文件，image 为同目录下的 PGM 文件名
+func (m *OccupancyMap) WriteYAML(w io.Writer, image string) error {
+	_, err := fmt.Fprintf(w, "image: %q\n"+
+		"resolution: %g\n"+
+		"origin: [%g, %g, 0.0]\n"+
+		"negate: 0\n"+
+		"occupied_thresh: %g\n"+
+		"free_thresh: %g\n", image, m.Resolution, m.OriginX, m.OriginY, OccupiedThresh, FreeThresh)
+	return err
+}
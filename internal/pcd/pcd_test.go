@@ -0,0 +1,441 @@
+package pcd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+const testHeader = `# .PCD v0.7 - Point Cloud Data file format
+VERSION 0.7
+FIELDS x y z intensity
+SIZE 4 4 4 1
+TYPE F F F U
+COUNT 1 1 1 1
+WIDTH 3
+HEIGHT 1
+VIEWPOINT 0 0 0 1 0 0 0
+POINTS 3
+DATA %s
+`
+
+var testPoints = [][4]float64{
+	{1, 2, 3, 10},
+	{-1, 5, 0.5, 20},
+	{math.NaN(), 0, 0, 30},
+}
+
+func header(data string) string {
+	return strings.Replace(testHeader, "%s", data, 1)
+}
+
+// encodePoint 按 x y z(F4) intensity(U1) 编码一个点
+func encodePoint(buf *bytes.Buffer, p [4]float64) {
+	for i := 0; i < 3; i++ {
+		binary.Write(buf, binary.LittleEndian, float32(p[i]))
+	}
+	buf.WriteByte(byte(p[3]))
+}
+
+// lzfLiterals 只使用字面量的 LZF 编码（合法但不压缩）
+func lzfLiterals(in []byte) []byte {
+	var out []byte
+	for len(in) > 0 {
+		n := len(in)
+		if n > 32 {
+			n = 32
+		}
+		out = append(out, byte(n-1))
+		out = append(out, in[:n]...)
+		in = in[n:]
+	}
+	return out
+}
+
+func checkMetadata(t *testing.T, meta *Metadata) {
+	t.Helper()
+	if meta.Header.Points != 3 {
+		t.Errorf("Expected 3 points, got %d", meta.Header.Points)
+	}
+	if meta.ValidPoints != 2 {
+		t.Errorf("Expected 2 valid points, got %d", meta.ValidPoints)
+	}
+	want := Bounds{MinX: -1, MinY: 2, MinZ: 0.5, MaxX: 1, MaxY: 5, MaxZ: 3}
+	if meta.Bounds == nil || *meta.Bounds != want {
+		t.Errorf("Expected bounds %+v, got %+v", want, meta.Bounds)
+	}
+}
+
+func TestAnalyze_ASCII(t *testing.T) {
+	input := header("ascii") + "1 2 3 10\n-1 5 0.5 20\nnan 0 0 30\n"
+
+	meta, err := Analyze(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	checkMetadata(t, meta)
+	if meta.Header.Data != DataASCII {
+		t.Errorf("Expected ascii, got %s", meta.Header.Data)
+	}
+	if strings.Join(meta.Header.Fields, " ") != "x y z intensity" {
+		t.Errorf("Unexpected fields %v", meta.Header.Fields)
+	}
+}
+
+func TestAnalyze_Binary(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString(header("binary"))
+	for _, p := range testPoints {
+		encodePoint(&buf, p)
+	}
+
+	meta, err := Analyze(&buf)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	checkMetadata(t, meta)
+}
+
+func TestAnalyze_BinaryCompressed(t *testing.T) {
+	// 列存：先全部 x，再全部 y、z、intensity
+	var columns bytes.Buffer
+	for field := 0; field < 3; field++ {
+		for _, p := range testPoints {
+			binary.Write(&columns, binary.LittleEndian, float32(p[field]))
+		}
+	}
+	for _, p := range testPoints {
+		columns.WriteByte(byte(p[3]))
+	}
+	compressed := lzfLiterals(columns.Bytes())
+
+	var buf bytes.Buffer
+	buf.WriteString(header("binary_compressed"))
+	binary.Write(&buf, binary.LittleEndian, uint32(len(compressed)))
+	binary.Write(&buf, binary.LittleEndian, uint32(columns.Len()))
+	buf.Write(compressed)
+
+	reader, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	values, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if values[0] != 1 || values[1] != 2 || values[2] != 3 || values[3] != 10 {
+		t.Errorf("Unexpected first point %v", values)
+	}
+
+	meta, err := Analyze(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	checkMetadata(t, meta)
+}
+
+func TestAnalyze_DecompressedLimit(t *testing.T) {
+	// 头部声明 10 亿个点，解压大小与头部一致，但超过上限时应在分配内存前拒绝
+	huge := "FIELDS x y z\nSIZE 4 4 4\nTYPE F F F\nWIDTH 1000000000\nHEIGHT 1\nPOINTS 1000000000\nDATA binary_compressed\n"
+	var buf bytes.Buffer
+	buf.WriteString(huge)
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint32(12000000000%(1<<32)))
+	if _, err := Analyze(bytes.NewReader(buf.Bytes())); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge, got %v", err)
+	}
+
+	SetMaxDecompressed(16)
+	defer SetMaxDecompressed(0)
+	var small bytes.Buffer
+	small.WriteString(header("binary_compressed"))
+	binary.Write(&small, binary.LittleEndian, uint32(4))
+	binary.Write(&small, binary.LittleEndian, uint32(39))
+	if _, err := Analyze(bytes.NewReader(small.Bytes())); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge below configured limit, got %v", err)
+	}
+}
+
+func TestAnalyze_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not pcd":        "hello world\n",
+		"missing data":   "VERSION 0.7\nFIELDS x y z\nSIZE 4 4 4\nTYPE F F F\nWIDTH 1\nPOINTS 1\n",
+		"size mismatch":  "FIELDS x y z\nSIZE 4 4\nTYPE F F F\nWIDTH 1\nPOINTS 1\nDATA ascii\n1 2 3\n",
+		"bad float size": "FIELDS x y z\nSIZE 4 4 2\nTYPE F F F\nWIDTH 1\nPOINTS 1\nDATA ascii\n1 2 3\n",
+		"points differ":  "FIELDS x y z\nSIZE 4 4 4\nTYPE F F F\nWIDTH 2\nHEIGHT 1\nPOINTS 3\nDATA ascii\n1 2 3\n",
+		"unknown data":   "FIELDS x y z\nSIZE 4 4 4\nTYPE F F F\nWIDTH 1\nPOINTS 1\nDATA zip\n",
+		"no xyz":         "FIELDS a b\nSIZE 4 4\nTYPE F F\nWIDTH 1\nPOINTS 1\nDATA ascii\n1 2\n",
+		"truncated":      header("ascii") + "1 2 3 10\n",
+		"wrong columns":  header("ascii") + "1 2 3\n1 2 3\n1 2 3\n",
+	}
+	for name, input := range tests {
+		if _, err := Analyze(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	// 异常的 COUNT、单点字节数与 WIDTH*HEIGHT 在头部校验时拒绝，不能按其分配内存
+	invalidHeaders := map[string]string{
+		"huge count":    "FIELDS x y z\nSIZE 4 4 4\nTYPE F F F\nCOUNT 1 1 100000000000000\nWIDTH 1\nPOINTS 1\nDATA binary\n",
+		"huge dims":     "FIELDS x y z a b\nSIZE 4 4 4 4 4\nTYPE F F F F F\nCOUNT 1 1 1 4000 4000\nWIDTH 1\nPOINTS 1\nDATA binary\n",
+		"huge step":     "FIELDS x y z a\nSIZE 8 8 8 8\nTYPE F F F F\nCOUNT 1 1 1 4090\nWIDTH 1\nPOINTS 1\nDATA binary\n",
+		"size overflow": "FIELDS x y z\nSIZE 4 4 4\nTYPE F F F\nWIDTH 4294967296\nHEIGHT 4294967296\nPOINTS 0\nDATA binary\n",
+	}
+	for name, input := range invalidHeaders {
+		if _, err := Analyze(strings.NewReader(input)); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("%s: expected ErrInvalidHeader, got %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header("binary"))
+	encodePoint(&buf, testPoints[0])
+	if _, err := Analyze(&buf); !errors.Is(err, ErrTruncated) {
+		t.Errorf("Expected ErrTruncated for short binary data, got %v", err)
+	}
+}
+
+func TestLZFDecompress_BackReference(t *testing.T) {
+	// "abc" 字面量 + 回溯引用复制 6 字节（与输出重叠）
+	in := []byte{2, 'a', 'b', 'c', 4 << 5, 2}
+	out, err := lzfDecompress(in, 9)
+	if err != nil {
+		t.Fatalf("lzfDecompress failed: %v", err)
+	}
+	if string(out) != "abcabcabc" {
+		t.Errorf("Expected abcabcabc, got %q", out)
+	}
+
+	if _, err := lzfDecompress([]byte{0x20, 5}, 3); err == nil {
+		t.Error("Expected error for reference before start")
+	}
+}
+
+func TestVoxelGrid_Centroids(t *testing.T) {
+	grid := NewVoxelGrid(1, 100)
+	grid.Add(0.1, 0.1, 0.1)
+	grid.Add(0.3, 0.5, 0.7)
+	grid.Add(2.5, 0.5, 0.5)
+	grid.Add(math.NaN(), 0, 0)
+
+	points := grid.Points()
+	if len(points) != 2 {
+		t.Fatalf("Expected 2 voxels, got %d", len(points))
+	}
+	if points[0] != (Point{X: 0.2, Y: 0.3, Z: 0.4}) {
+		t.Errorf("Expected centroid of first voxel, got %+v", points[0])
+	}
+}
+
+func TestVoxelGrid_CoarsensWhenFull(t *testing.T) {
+	grid := NewVoxelGrid(0.01, 50)
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 100; j++ {
+			grid.Add(float64(i)*0.1, float64(j)*0.1, 0)
+		}
+	}
+
+	if grid.Len() > 50 {
+		t.Errorf("Expected at most 50 voxels, got %d", grid.Len())
+	}
+	if grid.Leaf() <= 0.01 {
+		t.Errorf("Expected leaf size to grow, got %f", grid.Leaf())
+	}
+}
+
+func TestWriteBinary_RoundTrip(t *testing.T) {
+	grid := NewVoxelGrid(0.5, 100)
+	if _, err := Downsample(strings.NewReader(header("ascii")+"1 2 3 10\n-1 5 0.5 20\nnan 0 0 30\n"), grid); err != nil {
+		t.Fatalf("Downsample failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, grid.Points()); err != nil {
+		t.Fatalf("WriteBinary failed: %v", err)
+	}
+
+	meta, err := Analyze(&buf)
+	if err != nil {
+		t.Fatalf("Analyze of written pcd failed: %v", err)
+	}
+	if meta.Header.Points != 2 || meta.ValidPoints != 2 {
+		t.Errorf("Expected 2 points, got %d/%d", meta.Header.Points, meta.ValidPoints)
+	}
+	if meta.Bounds.MinX != -1 || meta.Bounds.MaxY != 5 {
+		t.Errorf("Unexpected bounds %+v", meta.Bounds)
+	}
+}
+
+func TestRenderTopDown(t *testing.T) {
+	img := RenderTopDown([]Point{{X: 0, Y: 0, Z: 0}, {X: 10, Y: 5, Z: 2}}, 101)
+
+	if w, h := img.Bounds().Dx(), img.Bounds().Dy(); w != 101 || h != 51 {
+		t.Fatalf("Expected 101x51 image, got %dx%d", w, h)
+	}
+	// 地图原点位于图像左下角，最高点为红色
+	if c := img.RGBAAt(0, 50); c.A == 0 || c.B != 255 {
+		t.Errorf("Expected blue pixel at origin, got %+v", c)
+	}
+	if c := img.RGBAAt(100, 0); c.R != 255 {
+		t.Errorf("Expected red pixel at highest point, got %+v", c)
+	}
+	if c := img.RGBAAt(50, 25); c.A != 0 {
+		t.Errorf("Expected transparent pixel without points, got %+v", c)
+	}
+}
+
+func TestOccupancyBuilder_Map(t *testing.T) {
+	builder, err := NewOccupancyBuilder(OccupancyOptions{Resolution: 1, MinZ: 0.2, MaxZ: 2, MinPoints: 2})
+	if err != nil {
+		t.Fatalf("NewOccupancyBuilder failed: %v", err)
+	}
+	// (0,0) 地面；(1,0) 两个障碍点；(2,1) 单个障碍点(噪点)；(0,1) 天花板点被忽略
+	builder.Add(0.5, 0.5, 0)
+	builder.Add(1.5, 0.5, 1)
+	builder.Add(1.2, 0.8, 1.5)
+	builder.Add(1.5, 0.5, 0)
+	builder.Add(2.5, 1.5, 1)
+	builder.Add(0.5, 1.5, 3)
+
+	m, err := builder.Map()
+	if err != nil {
+		t.Fatalf("Map failed: %v", err)
+	}
+	if m.Width != 3 || m.Height != 2 || m.OriginX != 0 || m.OriginY != 0 {
+		t.Fatalf("Unexpected grid %dx%d origin (%v,%v)", m.Width, m.Height, m.OriginX, m.OriginY)
+	}
+	// 第一行为 y=1，第二行为 y=0
+	want := []uint8{
+		PixelUnknown, PixelUnknown, PixelUnknown,
+		PixelFree, PixelOccupied, PixelUnknown,
+	}
+	if !bytes.Equal(m.Pixels, want) {
+		t.Errorf("Expected pixels %v, got %v", want, m.Pixels)
+	}
+	if m.OccupiedCells != 1 || m.FreeCells != 1 {
+		t.Errorf("Expected 1 occupied and 1 free cell, got %d/%d", m.OccupiedCells, m.FreeCells)
+	}
+
+	var pgm, yaml bytes.Buffer
+	if err := m.WritePGM(&pgm); err != nil {
+		t.Fatalf("WritePGM failed: %v", err)
+	}
+	if !strings.HasPrefix(pgm.String(), "P5\n3 2\n255\n") || pgm.Len() != len("P5\n3 2\n255\n")+6 {
+		t.Errorf("Unexpected pgm output %q", pgm.String())
+	}
+	if err := m.WriteYAML(&yaml, "floor.pgm"); err != nil {
+		t.Fatalf("WriteYAML failed: %v", err)
+	}
+	if !strings.Contains(yaml.String(), `image: "floor.pgm"`) || !strings.Contains(yaml.String(), "origin: [0, 0, 0.0]") {
+		t.Errorf("Unexpected yaml output %q", yaml.String())
+	}
+}
+
+func TestOccupancyBuilder_Limits(t *testing.T) {
+	if _, err := NewOccupancyBuilder(OccupancyOptions{Resolution: 0.1, MinZ: 1, MaxZ: 1}); err == nil {
+		t.Error("Expected error for empty height band")
+	}
+
+	builder, _ := NewOccupancyBuilder(OccupancyOptions{Resolution: 0.1, MinZ: 0, MaxZ: 1, MaxPixels: 100})
+	if _, err := builder.Map(); !errors.Is(err, ErrGridEmpty) {
+		t.Errorf("Expected ErrGridEmpty, got %v", err)
+	}
+	builder.Add(0, 0, 0.5)
+	builder.Add(10, 10, 0.5)
+	if _, err := builder.Map(); !errors.Is(err, ErrGridTooLarge) {
+		t.Errorf("Expected ErrGridTooLarge, got %v", err)
+	}
+}
+
+func TestOccupancyBuilder_Outliers(t *testing.T) {
+	// 单个极端坐标的离群点曾使宽高相乘溢出，通过像素上限检查后写越界
+	builder, _ := NewOccupancyBuilder(OccupancyOptions{Resolution: 0.05, MinZ: 0.1, MaxZ: 2, MaxPixels: 1000})
+	builder.Add(0, 0, 0.5)
+	builder.Add(1, 1, 0.5)
+	builder.Add(3e38, -3e38, 0.5)
+	builder.Add(1e6, 0, 0.5)
+	builder.Add(0, -math.MaxFloat64, 0)
+
+	m, err := builder.Map()
+	if err != nil {
+		t.Fatalf("Map failed: %v", err)
+	}
+	if m.Width != 21 || m.Height != 21 || m.Outliers != 3 {
+		t.Errorf("Expected 21x21 grid with 3 outliers, got %dx%d with %d", m.Width, m.Height, m.Outliers)
+	}
+
+	// 坐标范围内但超出像素上限时立即停止接收点，不再继续增长
+	builder, _ = NewOccupancyBuilder(OccupancyOptions{Resolution: 0.001, MinZ: 0.1, MaxZ: 2, MaxRange: 1e6})
+	builder.Add(-1e5, -1e5, 0.5)
+	builder.Add(1e5, 1e5, 0.5)
+	builder.Add(0, 0, 0.5)
+	if _, err := builder.Map(); !errors.Is(err, ErrGridTooLarge) {
+		t.Errorf("Expected ErrGridTooLarge, got %v", err)
+	}
+	if len(builder.cells) != 1 {
+		t.Errorf("Expected cells to stop growing after the limit, got %d", len(builder.cells))
+	}
+}
+
+func TestReadPGM_MapServer(t *testing.T) {
+	meta, err := ParseMapServerYAML([]byte("image: map.pgm\nresolution: 0.5\norigin: [-1.0, -2.0, 0.0]\nnegate: 0\noccupied_thresh: 0.65\nfree_thresh: 0.196\n"))
+	if err != nil {
+		t.Fatalf("ParseMapServerYAML failed: %v", err)
+	}
+
+	// P5 与 P2 两种编码、带注释的头部，像素依次为占据、未知、空闲
+	binary := append([]byte("P5\n# created by map_saver\n3 1\n255\n"), PixelOccupied, PixelUnknown, PixelFree)
+	ascii := []byte("P2\n3 1\n255\n0 205\n254\n")
+	for name, data := range map[string][]byte{"P5": binary, "P2": ascii} {
+		img, err := ReadPGM(bytes.NewReader(data), 0)
+		if err != nil {
+			t.Fatalf("%s: ReadPGM failed: %v", name, err)
+		}
+		if img.Width != 3 || img.Height != 1 || img.Pixels[1] != PixelUnknown {
+			t.Fatalf("%s: unexpected image %+v", name, img)
+		}
+		if occupied, free := img.CountCells(meta); occupied != 1 || free != 1 {
+			t.Errorf("%s: expected 1 occupied and 1 free, got %d/%d", name, occupied, free)
+		}
+		if minX, minY, maxX, maxY := GridBounds(img.Width, img.Height, meta.Resolution, meta.Origin[0], meta.Origin[1], meta.Origin[2]); minX != -1 || minY != -2 || maxX != 0.5 || maxY != -1.5 {
+			t.Errorf("%s: unexpected bounds %v %v %v %v", name, minX, minY, maxX, maxY)
+		}
+
+		var buf bytes.Buffer
+		if err := img.WritePGM(&buf); err != nil || !bytes.Equal(buf.Bytes(), []byte("P5\n3 1\n255\n\x00\xcd\xfe")) {
+			t.Errorf("%s: unexpected P5 output %q (%v)", name, buf.Bytes(), err)
+		}
+	}
+
+	if _, err := ReadPGM(bytes.NewReader(binary), 2); !errors.Is(err, ErrGridTooLarge) {
+		t.Errorf("Expected ErrGridTooLarge, got %v", err)
+	}
+	if _, err := ReadPGM(strings.NewReader("P5\n3 1\n255\n\x00"), 0); !errors.Is(err, ErrInvalidMapServer) {
+		t.Errorf("Expected ErrInvalidMapServer for truncated data, got %v", err)
+	}
+	if _, err := ParseMapServerYAML([]byte("resolution: 0.05\norigin: [0, 0]\noccupied_thresh: 0.65\nfree_thresh: 0.196\n")); !errors.Is(err, ErrInvalidMapServer) {
+		t.Errorf("Expected ErrInvalidMapServer for bad origin, got %v", err)
+	}
+}
+
+func TestPGMImage_CountCellsMode(t *testing.T) {
+	img := &PGMImage{Width: 4, Height: 1, MaxVal: 255, Pixels: []uint8{0, 50, 100, 255}}
+	tests := map[string]struct{ occupied, free int }{
+		"":        {2, 1}, // 0、50 为占据，255 为空闲，100 介于阈值之间
+		"trinary": {2, 1},
+		"scale":   {2, 1},
+		"raw":     {1, 1}, // 100 为占据，0 为空闲，50 介于阈值之间，255 为未知
+	}
+	for mode, want := range tests {
+		meta, err := ParseMapServerYAML([]byte("resolution: 0.05\norigin: [0, 0, 0]\noccupied_thresh: 0.65\nfree_thresh: 0.196\nmode: " + mode + "\n"))
+		if err != nil {
+			t.Fatalf("%q: ParseMapServerYAML failed: %v", mode, err)
+		}
+		if occupied, free := img.CountCells(meta); occupied != want.occupied || free != want.free {
+			t.Errorf("%q: expected %d occupied and %d free, got %d/%d", mode, want.occupied, want.free, occupied, free)
+		}
+	}
+}
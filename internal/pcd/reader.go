@@ -0,0 +1,244 @@
+package pcd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrTruncated 点数据少于头部声明的点数
+var ErrTruncated = errors.New("pcd point data truncated")
+
+// ErrTooLarge binary_compressed 数据块解压后超过上限
+var ErrTooLarge = errors.New("pcd decompressed data too large")
+
+// DefaultMaxDecompressed binary_compressed 数据块解压后的默认上限(字节)
+const DefaultMaxDecompressed int64 = 4 << 30
+
+var maxDecompressed atomic.Int64
+
+func init() {
+	maxDecompressed.Store(DefaultMaxDecompressed)
+}
+
+// SetMaxDecompressed 设置 binary_compressed 数据块解压后的上限(字节)，n <= 0 时恢复默认值。
+// 解压需要一次性分配整块内存，上限防止伪造的头部耗尽内存
+func SetMaxDecompressed(n int64) {
+	if n <= 0 {
+		n = DefaultMaxDecompressed
+	}
+	maxDecompressed.Store(n)
+}
+
+// Reader 逐点读取 PCD 点数据
+type Reader struct {
+	header *Header
+	r      *bufio.Reader
+	read   int
+
+	// binary 编码时的单点缓冲
+	buf []byte
+	// binary_compressed 编码解压后的列存数据
+	columns []byte
+}
+
+// NewReader 读取头部并返回点数据读取器
+func NewReader(r io.Reader) (*Reader, error) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReaderSize(r, 64*1024)
+	}
+	h, err := ReadHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{header: h, r: br}, nil
+}
+
+// Header 返回文件头
+func (r *Reader) Header() *Header {
+	return r.header
+}
+
+// Next 读取下一个点，返回按字段顺序展开的全部值（长度为 Header.Dims()）
+// 读完全部点后返回 io.EOF；数据不足时返回 ErrTruncated
+func (r *Reader) Next() ([]float64, error) {
+	if r.read >= r.header.Points {
+		return nil, io.EOF
+	}
+
+	var (
+		values []float64
+		err    error
+	)
+	switch r.header.Data {
+	case DataASCII:
+		values, err = r.nextASCII()
+	case DataBinary:
+		values, err = r.nextBinary()
+	case DataBinaryCompressed:
+		values, err = r.nextCompressed()
+	default:
+		err = fmt.Errorf("unsupported DATA %q", r.header.Data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.read++
+	return values, nil
+}
+
+func (r *Reader) nextASCII() ([]float64, error) {
+	h := r.header
+	for {
+		line, err := r.r.ReadString('\n')
+		if err != nil && (err != io.EOF || strings.TrimSpace(line) == "") {
+			if err == io.EOF {
+				return nil, fmt.Errorf("%w: got %d of %d points", ErrTruncated, r.read, h.Points)
+			}
+			return nil, err
+		}
+
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+		if len(parts) != h.Dims() {
+			return nil, fmt.Errorf("point %d: expected %d values, got %d", r.read, h.Dims(), len(parts))
+		}
+
+		values := make([]float64, len(parts))
+		for i, p := range parts {
+			v, err := strconv.ParseFloat(p, 64)
+			if err != nil {
+				return nil, fmt.Errorf("point %d: invalid value %q", r.read, p)
+			}
+			values[i] = v
+		}
+		return values, nil
+	}
+}
+
+func (r *Reader) nextBinary() ([]float64, error) {
+	h := r.header
+	if r.buf == nil {
+		r.buf = make([]byte, h.PointStep())
+	}
+	if _, err := io.ReadFull(r.r, r.buf); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: got %d of %d points", ErrTruncated, r.read, h.Points)
+		}
+		return nil, err
+	}
+
+	values := make([]float64, 0, h.Dims())
+	offset := 0
+	for i := range h.Fields {
+		for c := 0; c < h.Count[i]; c++ {
+			values = append(values, decodeValue(r.buf[offset:offset+h.Size[i]], h.Type[i]))
+			offset += h.Size[i]
+		}
+	}
+	return values, nil
+}
+
+func (r *Reader) nextCompressed() ([]float64, error) {
+	h := r.header
+	if r.columns == nil {
+		if err := r.decompress(); err != nil {
+			return nil, err
+		}
+	}
+
+	// 列存布局：每个字段的全部点连续存放
+	values := make([]float64, 0, h.Dims())
+	fieldStart := 0
+	for i := range h.Fields {
+		width := h.Size[i] * h.Count[i]
+		base := fieldStart + r.read*width
+		for c := 0; c < h.Count[i]; c++ {
+			offset := base + c*h.Size[i]
+			values = append(values, decodeValue(r.columns[offset:offset+h.Size[i]], h.Type[i]))
+		}
+		fieldStart += width * h.Points
+	}
+	return values, nil
+}
+
+// decompress 读取并解压 binary_compressed 数据块
+func (r *Reader) decompress() error {
+	h := r.header
+
+	var sizes [8]byte
+	if _, err := io.ReadFull(r.r, sizes[:]); err != nil {
+		return fmt.Errorf("%w: missing compressed block header", ErrTruncated)
+	}
+	compressed := binary.LittleEndian.Uint32(sizes[0:4])
+	uncompressed := binary.LittleEndian.Uint32(sizes[4:8])
+
+	// 解压大小必须等于 width*height*单点字节数，且不超过上限，校验通过后才分配内存
+	step, points, limit := int64(h.PointStep()), int64(h.Points), maxDecompressed.Load()
+	if step > 0 && points > limit/step {
+		return fmt.Errorf("%w: %d points x %d bytes exceeds %d", ErrTooLarge, points, step, limit)
+	}
+	expected := int(step * points)
+	if int64(uncompressed) != int64(expected) {
+		return fmt.Errorf("compressed block size %d does not match header (%d)", uncompressed, expected)
+	}
+	// LZF 最坏情况下每 32 字节多 1 字节控制字，超出则视为损坏，避免异常数据导致超大内存分配
+	if int(compressed) > expected+expected/32+16 {
+		return fmt.Errorf("compressed block too large: %d", compressed)
+	}
+
+	in := make([]byte, compressed)
+	if _, err := io.ReadFull(r.r, in); err != nil {
+		return fmt.Errorf("%w: compressed block", ErrTruncated)
+	}
+
+	out, err := lzfDecompress(in, expected)
+	if err != nil {
+		return err
+	}
+	r.columns = out
+	return nil
+}
+
+// decodeValue 按类型解码小端序数值
+func decodeValue(b []byte, typ byte) float64 {
+	switch typ {
+	case 'F':
+		if len(b) == 4 {
+			return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	case 'U':
+		switch len(b) {
+		case 1:
+			return float64(b[0])
+		case 2:
+			return float64(binary.LittleEndian.Uint16(b))
+		case 4:
+			return float64(binary.LittleEndian.Uint32(b))
+		default:
+			return float64(binary.LittleEndian.Uint64(b))
+		}
+	default:
+		switch len(b) {
+		case 1:
+			return float64(int8(b[0]))
+		case 2:
+			return float64(int16(binary.LittleEndian.Uint16(b)))
+		case 4:
+			return float64(int32(binary.LittleEndian.Uint32(b)))
+		default:
+			return float64(int64(binary.LittleEndian.Uint64(b)))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package pcd
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// RenderTopDown 渲染俯视缩略图，长边为 size 像素
+// 每个像素取落入其中的最高点，按高度由蓝到红着色，无点处透明
+func RenderTopDown(points []Point, size int) *image.RGBA {
+	if len(points) == 0 || size <= 0 {
+		return image.NewRGBA(image.Rect(0, 0, 1, 1))
+	}
+
+	minX, minY, minZ := math.Inf(1), math.Inf(1), math.Inf(1)
+	maxX, maxY, maxZ := math.Inf(-1), math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		x, y, z := float64(p.X), float64(p.Y), float64(p.Z)
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+		minZ, maxZ = math.Min(minZ, z), math.Max(maxZ, z)
+	}
+
+	extent := math.Max(maxX-minX, maxY-minY)
+	if extent <= 0 {
+		extent = 1
+	}
+	scale := float64(size-1) / extent
+	width := int((maxX-minX)*scale) + 1
+	height := int((maxY-minY)*scale) + 1
+
+	top := make([]float64, width*height)
+	for i := range top {
+		top[i] = math.Inf(-1)
+	}
+	for _, p := range points {
+		px := int((float64(p.X) - minX) * scale)
+		// 图像 y 轴向下，地图 y 轴向上
+		py := height - 1 - int((float64(p.Y)-minY)*scale)
+		i := py*width + px
+		top[i] = math.Max(top[i], float64(p.Z))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	zRange := maxZ - minZ
+	for py := 0; py < height; py++ {
+		for px := 0; px < width; px++ {
+			z := top[py*width+px]
+			if math.IsInf(z, -1) {
+				continue
+			}
+			t := 0.5
+			if zRange > 0 {
+				t = (z - minZ) / zRange
+			}
+			img.SetRGBA(px, py, heightColor(t))
+		}
+	}
+	return img
+}
+
+// heightColor 将 [0,1] 的相对高度映射为蓝-青-绿-黄-红
+func heightColor(t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t))
+	r := math.Max(0, math.Min(1, 4*t-2))
+	g := math.Max(0, math.Min(1, math.Min(4*t, 4-4*t)))
+	b := math.Max(0, math.Min(1, 2-4*t))
+	return color.RGBA{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+}
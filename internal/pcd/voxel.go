@@ -0,0 +1,143 @@
+package pcd
+
+import (
+	"io"
+	"math"
+	"sort"
+)
+
+// Point 三维点
+type Point struct {
+	X, Y, Z float32
+}
+
+type voxelKey struct {
+	x, y, z int64
+}
+
+type voxelSum struct {
+	x, y, z float64
+	n       int
+}
+
+// VoxelGrid 体素网格降采样，每个体素输出体素内点的质心
+// 体素数超过上限时体素边长翻倍并合并已有体素，保证内存占用有界
+type VoxelGrid struct {
+	leaf      float64
+	maxVoxels int
+	voxels    map[voxelKey]*voxelSum
+}
+
+// NewVoxelGrid 创建体素网格，leaf 为初始体素边长，maxVoxels 为输出点数上限
+func NewVoxelGrid(leaf float64, maxVoxels int) *VoxelGrid {
+	return &VoxelGrid{
+		leaf:      leaf,
+		maxVoxels: maxVoxels,
+		voxels:    make(map[voxelKey]*voxelSum),
+	}
+}
+
+// Add 加入一个点，坐标非有限值的点被忽略
+func (g *VoxelGrid) Add(x, y, z float64) {
+	if !isFinite(x) || !isFinite(y) || !isFinite(z) {
+		return
+	}
+	g.add(x, y, z, 1)
+	for len(g.voxels) > g.maxVoxels {
+		g.coarsen()
+	}
+}
+
+func (g *VoxelGrid) add(x, y, z float64, n int) {
+	key := voxelKey{
+		x: int64(math.Floor(x / g.leaf)),
+		y: int64(math.Floor(y / g.leaf)),
+		z: int64(math.Floor(z / g.leaf)),
+	}
+	v, ok := g.voxels[key]
+	if !ok {
+		v = &voxelSum{}
+		g.voxels[key] = v
+	}
+	v.x += x * float64(n)
+	v.y += y * float64(n)
+	v.z += z * float64(n)
+	v.n += n
+}
+
+// coarsen 体素边长翻倍，按各体素质心重新分箱
+func (g *VoxelGrid) coarsen() {
+	old := g.voxels
+	g.leaf *= 2
+	g.voxels = make(map[voxelKey]*voxelSum, len(old)/4)
+	for _, v := range old {
+		n := float64(v.n)
+		g.add(v.x/n, v.y/n, v.z/n, v.n)
+	}
+}
+
+// Leaf 当前体素边长
+func (g *VoxelGrid) Leaf() float64 {
+	return g.leaf
+}
+
+// Len 当前体素数
+func (g *VoxelGrid) Len() int {
+	return len(g.voxels)
+}
+
+// Points 返回各体素质心，按体素坐标排序以保证输出稳定
+func (g *VoxelGrid) Points() []Point {
+	keys := make([]voxelKey, 0, len(g.voxels))
+	for k := range g.voxels {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.z != b.z {
+			return a.z < b.z
+		}
+		if a.y != b.y {
+			return a.y < b.y
+		}
+		return a.x < b.x
+	})
+
+	points := make([]Point, 0, len(keys))
+	for _, k := range keys {
+		v := g.voxels[k]
+		n := float64(v.n)
+		points = append(points, Point{X: float32(v.x / n), Y: float32(v.y / n), Z: float32(v.z / n)})
+	}
+	return points
+}
+
+// Downsample 读取完整的 PCD 文件并做体素降采样
+func Downsample(r io.Reader, grid *VoxelGrid) (*Header, error) {
+	return scanXYZ(r, grid.Add)
+}
+
+// scanXYZ 读取完整的 PCD 文件，依次回调每个点的坐标
+func scanXYZ(r io.Reader, fn func(x, y, z float64)) (*Header, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	h := reader.Header()
+
+	xi, yi, zi := h.ValueOffset("x"), h.ValueOffset("y"), h.ValueOffset("z")
+	if xi < 0 || yi < 0 || zi < 0 {
+		return nil, ErrMissingXYZ
+	}
+
+	for {
+		values, err := reader.Next()
+		if err == io.EOF {
+			return h, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		fn(values[xi], values[yi], values[zi])
+	}
+}
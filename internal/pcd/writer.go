@@ -0,0 +1,36 @@
+package pcd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// WriteBinary 以 binary 编码写出只含 x y z 字段的 PCD 文件
+func WriteBinary(w io.Writer, points []Point) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# .PCD v0.7 - Point Cloud Data file format\n"+
+		"VERSION 0.7\n"+
+		"FIELDS x y z\n"+
+		"SIZE 4 4 4\n"+
+		"TYPE F F F\n"+
+		"COUNT 1 1 1\n"+
+		"WIDTH %d\n"+
+		"HEIGHT 1\n"+
+		"VIEWPOINT 0 0 0 1 0 0 0\n"+
+		"POINTS %d\n"+
+		"DATA %s\n", len(points), len(points), DataBinary)
+
+	var buf [12]byte
+	for _, p := range points {
+		binary.LittleEndian.PutUint32(buf[0:], math.Float32bits(p.X))
+		binary.LittleEndian.PutUint32(buf[4:], math.Float32bits(p.Y))
+		binary.LittleEndian.PutUint32(buf[8:], math.Float32bits(p.Z))
+		if _, err := bw.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,349 @@
+package semantic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Severity 检查问题的严重级别
+type Severity string
+
+const (
+	SeverityOK      Severity = "ok"      // 未发现问题（仅用于报告总体级别）
+	SeverityInfo    Severity = "info"    // 提示
+	SeverityWarning Severity = "warning" // 警告，地图可用但可能不符合预期
+	SeverityError   Severity = "error"   // 错误，机器人按此地图运行可能出错
+)
+
+var severityRank = map[Severity]int{SeverityOK: 0, SeverityInfo: 1, SeverityWarning: 2, SeverityError: 3}
+
+// 检查问题代码
+const (
+	IssueSchemaInvalid     = "schema_invalid"       // 语义信息不符合结构定义
+	IssuePCDMissing        = "pcd_missing"          // 关联的点云地图不存在
+	IssueGridMissing       = "grid_missing"         // 关联的二维栅格地图不存在
+	IssueBoundsUnknown     = "bounds_unknown"       // 点云地图没有包围盒元数据，跳过范围检查
+	IssueOutOfBounds       = "out_of_bounds"        // 元素完全位于底图范围之外
+	IssuePartlyOutOfBounds = "partly_out_of_bounds" // 区域部分顶点位于点云包围盒之外
+	IssueGraphDisconnected = "graph_disconnected"   // 路网存在与主体不连通的子图
+	IssueExclusiveOverlap  = "exclusive_overlap"    // 互斥区域（禁行区、电梯）相互重叠
+	IssueInNoGo            = "in_no_go"             // 兴趣点或路网节点位于禁行区内
+	IssuePOIUnreachable    = "poi_unreachable"      // 兴趣点无法经路网往返
+	IssueNoWaypoints       = "no_waypoints"         // 有兴趣点但没有路网
+)
+
+// Issue 检查发现的单个问题
+type Issue struct {
+	Severity   Severity `json:"severity"`
+	Code       string   `json:"code"`
+	Message    string   `json:"message"`
+	Kind       Kind     `json:"kind,omitempty"`       // 涉及的元素类型
+	ElementIDs []string `json:"elementIds,omitempty"` // 涉及的元素ID
+}
+
+// Report 一致性检查报告，Severity 为全部问题中的最高级别
+type Report struct {
+	Severity Severity `json:"severity"`
+	Issues   []*Issue `json:"issues"`
+}
+
+// Bounds 点云地图在水平面上的包围盒
+type Bounds struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+func (b *Bounds) contains(x, y, margin float64) bool {
+	return x >= b.MinX-margin && x <= b.MaxX+margin && y >= b.MinY-margin && y <= b.MaxY+margin
+}
+
+// NewReport 创建空报告
+func NewReport() *Report {
+	return &Report{Severity: SeverityOK, Issues: make([]*Issue, 0)}
+}
+
+// Add 追加问题并更新总体级别
+func (r *Report) Add(severity Severity, code string, kind Kind, ids []string, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, &Issue{
+		Severity:   severity,
+		Code:       code,
+		Message:    fmt.Sprintf(format, args...),
+		Kind:       kind,
+		ElementIDs: ids,
+	})
+	if severityRank[severity] > severityRank[r.Severity] {
+		r.Severity = severity
+	}
+}
+
+// Check 检查语义地图与点云包围盒及自身拓扑的一致性，bounds 为 nil 时跳过范围检查，
+// margin 为包围盒外允许的容差(米)
+func (m *Map) Check(bounds *Bounds, margin float64) *Report {
+	report := NewReport()
+	if bounds == nil {
+		report.Add(SeverityInfo, IssueBoundsUnknown, "", nil, "底图没有包围盒元数据，跳过范围检查")
+	} else {
+		m.checkBounds(report, bounds, margin)
+	}
+	m.checkNoGo(report)
+	m.checkExclusiveOverlap(report)
+	m.checkGraph(report)
+	return report
+}
+
+func (m *Map) checkBounds(report *Report, b *Bounds, margin float64) {
+	for _, p := range m.POIs {
+		if !b.contains(p.Pose.X, p.Pose.Y, margin) {
+			report.Add(SeverityError, IssueOutOfBounds, KindPOI, []string{p.ID}, "兴趣点 %s (%.2f, %.2f) 位于底图范围之外", p.ID, p.Pose.X, p.Pose.Y)
+		}
+	}
+	for _, w := range m.Waypoints {
+		if !b.contains(w.X, w.Y, margin) {
+			report.Add(SeverityError, IssueOutOfBounds, KindWaypoint, []string{w.ID}, "路网节点 %s (%.2f, %.2f) 位于底图范围之外", w.ID, w.X, w.Y)
+		}
+	}
+	for _, r := range m.Regions {
+		outside := 0
+		for _, pt := range r.Polygon {
+			if !b.contains(pt.X, pt.Y, margin) {
+				outside++
+			}
+		}
+		switch {
+		case outside == len(r.Polygon):
+			report.Add(SeverityError, IssueOutOfBounds, KindRegion, []string{r.ID}, "区域 %s 完全位于底图范围之外", r.ID)
+		case outside > 0:
+			report.Add(SeverityWarning, IssuePartlyOutOfBounds, KindRegion, []string{r.ID}, "区域 %s 有 %d 个顶点位于底图范围之外", r.ID, outside)
+		}
+	}
+}
+
+func (m *Map) checkNoGo(report *Report) {
+	for _, r := range m.Regions {
+		if !r.Properties.NoGo {
+			continue
+		}
+		for _, p := range m.POIs {
+			if r.Contains(Point{X: p.Pose.X, Y: p.Pose.Y}) {
+				report.Add(SeverityError, IssueInNoGo, KindPOI, []string{p.ID, r.ID}, "兴趣点 %s 位于禁行区 %s 内", p.ID, r.ID)
+			}
+		}
+		for _, w := range m.Waypoints {
+			if r.Contains(Point{X: w.X, Y: w.Y}) {
+				report.Add(SeverityWarning, IssueInNoGo, KindWaypoint, []string{w.ID, r.ID}, "路网节点 %s 位于禁行区 %s 内", w.ID, r.ID)
+			}
+		}
+	}
+}
+
+// checkExclusiveOverlap 禁行区与电梯区域互斥，两两之间不应有重叠面积（共用边界不算重叠）
+func (m *Map) checkExclusiveOverlap(report *Report) {
+	var exclusive []*Region
+	for _, r := range m.Regions {
+		if r.Properties.NoGo || r.Properties.Elevator {
+			exclusive = append(exclusive, r)
+		}
+	}
+	for i := 0; i < len(exclusive); i++ {
+		for j := i + 1; j < len(exclusive); j++ {
+			a, b := exclusive[i], exclusive[j]
+			if regionsOverlap(a, b) {
+				report.Add(SeverityWarning, IssueExclusiveOverlap, KindRegion, []string{a.ID, b.ID}, "互斥区域 %s 与 %s 相互重叠", a.ID, b.ID)
+			}
+		}
+	}
+}
+
+// checkGraph 检查路网连通性与兴趣点可达性
+func (m *Map) checkGraph(report *Report) {
+	if len(m.Waypoints) == 0 {
+		if len(m.POIs) > 0 {
+			report.Add(SeverityWarning, IssueNoWaypoints, KindPOI, nil, "地图有 %d 个兴趣点但没有路网，兴趣点均不可达", len(m.POIs))
+		}
+		return
+	}
+
+	// 弱连通分量：忽略边方向，主体以外的分量均报告
+	components := m.weakComponents()
+	for _, c := range components[1:] {
+		report.Add(SeverityWarning, IssueGraphDisconnected, KindWaypoint, c, "路网节点 %v 与路网主体不连通", c)
+	}
+
+	// 强连通主体：兴趣点最近的路网节点不在其中时，机器人无法往返该兴趣点
+	main := m.mainStrongComponent()
+	for _, p := range m.POIs {
+		nearest := m.nearestWaypoint(p.Pose.X, p.Pose.Y)
+		if !main[nearest.ID] {
+			report.Add(SeverityWarning, IssuePOIUnreachable, KindPOI, []string{p.ID, nearest.ID}, "兴趣点 %s 最近的路网节点 %s 无法与路网主体往返", p.ID, nearest.ID)
+		}
+	}
+}
+
+// weakComponents 按节点数从大到小返回弱连通分量
+func (m *Map) weakComponents() [][]string {
+	parent := make(map[string]string, len(m.Waypoints))
+	var find func(string) string
+	find = func(x string) string {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	for _, w := range m.Waypoints {
+		parent[w.ID] = w.ID
+	}
+	for _, e := range m.Edges {
+		parent[find(e.From)] = find(e.To)
+	}
+
+	groups := make(map[string][]string)
+	for _, w := range m.Waypoints {
+		root := find(w.ID)
+		groups[root] = append(groups[root], w.ID)
+	}
+	components := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		components = append(components, g)
+	}
+	sortComponents(components)
+	return components
+}
+
+// mainStrongComponent 返回可通行边构成的最大强连通分量（Kosaraju）
+func (m *Map) mainStrongComponent() map[string]bool {
+	nodes := make(map[string]*Waypoint, len(m.Waypoints))
+	for _, w := range m.Waypoints {
+		nodes[w.ID] = w
+	}
+	forward := make(map[string][]string)
+	backward := make(map[string][]string)
+	for _, e := range m.Edges {
+		if !m.passable(e, nodes[e.From], nodes[e.To], "") {
+			continue
+		}
+		forward[e.From] = append(forward[e.From], e.To)
+		backward[e.To] = append(backward[e.To], e.From)
+	}
+
+	visited := make(map[string]bool)
+	order := make([]string, 0, len(m.Waypoints))
+	var visit func(string)
+	visit = func(id string) {
+		visited[id] = true
+		for _, next := range forward[id] {
+			if !visited[next] {
+				visit(next)
+			}
+		}
+		order = append(order, id)
+	}
+	for _, w := range m.Waypoints {
+		if !visited[w.ID] {
+			visit(w.ID)
+		}
+	}
+
+	assigned := make(map[string]bool)
+	var collect func(string, *[]string)
+	collect = func(id string, component *[]string) {
+		assigned[id] = true
+		*component = append(*component, id)
+		for _, prev := range backward[id] {
+			if !assigned[prev] {
+				collect(prev, component)
+			}
+		}
+	}
+	var components [][]string
+	for i := len(order) - 1; i >= 0; i-- {
+		if assigned[order[i]] {
+			continue
+		}
+		var component []string
+		collect(order[i], &component)
+		components = append(components, component)
+	}
+	sortComponents(components)
+
+	main := make(map[string]bool)
+	for _, id := range components[0] {
+		main[id] = true
+	}
+	return main
+}
+
+func (m *Map) nearestWaypoint(x, y float64) *Waypoint {
+	var nearest *Waypoint
+	best := 0.0
+	for _, w := range m.Waypoints {
+		d := (w.X-x)*(w.X-x) + (w.Y-y)*(w.Y-y)
+		if nearest == nil || d < best {
+			nearest, best = w, d
+		}
+	}
+	return nearest
+}
+
+// sortComponents 分量内按ID排序，分量间按节点数从大到小、再按首个ID排序，保证报告稳定
+func sortComponents(components [][]string) {
+	for _, c := range components {
+		sort.Strings(c)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if len(components[i]) != len(components[j]) {
+			return len(components[i]) > len(components[j])
+		}
+		return components[i][0] < components[j][0]
+	})
+}
+
+// regionsOverlap 判断两个多边形是否有重叠面积：边严格相交，或一方的顶点/重心严格位于另一方内部
+func regionsOverlap(a, b *Region) bool {
+	n, k := len(a.Polygon), len(b.Polygon)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		for p, q := 0, k-1; p < k; q, p = p, p+1 {
+			if segmentsCross(a.Polygon[i], a.Polygon[j], b.Polygon[p], b.Polygon[q]) {
+				return true
+			}
+		}
+	}
+	return a.strictlyContainsAny(b) || b.strictlyContainsAny(a)
+}
+
+func (r *Region) strictlyContainsAny(other *Region) bool {
+	points := append([]Point{centroid(other.Polygon)}, other.Polygon...)
+	for _, p := range points {
+		if r.Contains(p) && !r.onBoundary(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Region) onBoundary(p Point) bool {
+	n := len(r.Polygon)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		if onSegment(p, r.Polygon[i], r.Polygon[j]) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentsCross 判断线段 ab 与 cd 是否严格交叉（不含端点接触与共线）
+func segmentsCross(a, b, c, d Point) bool {
+	d1, d2 := cross(c, d, a), cross(c, d, b)
+	d3, d4 := cross(a, b, c), cross(a, b, d)
+	return ((d1 > 1e-9 && d2 < -1e-9) || (d1 < -1e-9 && d2 > 1e-9)) &&
+		((d3 > 1e-9 && d4 < -1e-9) || (d3 < -1e-9 && d4 > 1e-9))
+}
+
+func centroid(polygon []Point) Point {
+	var c Point
+	for _, p := range polygon {
+		c.X += p.X
+		c.Y += p.Y
+	}
+	c.X /= float64(len(polygon))
+	c.Y /= float64(len(polygon))
+	return c
+}
@@ -0,0 +1,96 @@
+package semantic
+
+import "testing"
+
+// 双向连通的主路网 a <-> b，孤立节点 c，兴趣点 far 超出点云范围，
+// 兴趣点 trapped 最近节点为只能进不能出的 e，禁行区与电梯区域部分重叠
+const checkMap = `{
+	"pois": [
+		{"id": "home", "type": "dock", "pose": {"x": 1, "y": 0, "yaw": 0}},
+		{"id": "far", "type": "generic", "pose": {"x": 50, "y": 0, "yaw": 0}},
+		{"id": "trapped", "type": "generic", "pose": {"x": 0, "y": 9, "yaw": 0}}
+	],
+	"regions": [
+		{"id": "nogo", "polygon": [{"x": 4, "y": 4}, {"x": 6, "y": 4}, {"x": 6, "y": 6}, {"x": 4, "y": 6}], "properties": {"noGo": true}},
+		{"id": "lift", "polygon": [{"x": 5, "y": 5}, {"x": 7, "y": 5}, {"x": 7, "y": 7}, {"x": 5, "y": 7}], "properties": {"elevator": true}},
+		{"id": "edge", "polygon": [{"x": 8, "y": 8}, {"x": 12, "y": 8}, {"x": 12, "y": 9}, {"x": 8, "y": 9}]}
+	],
+	"waypoints": [
+		{"id": "a", "x": 0, "y": 0}, {"id": "b", "x": 5, "y": 0},
+		{"id": "c", "x": 9, "y": 2}, {"id": "e", "x": 0, "y": 8}
+	],
+	"edges": [
+		{"id": "a-b", "from": "a", "to": "b"}, {"id": "b-a", "from": "b", "to": "a"},
+		{"id": "a-e", "from": "a", "to": "e"}
+	]
+}`
+
+func issuesByCode(r *Report) map[string][]*Issue {
+	out := make(map[string][]*Issue)
+	for _, issue := range r.Issues {
+		out[issue.Code] = append(out[issue.Code], issue)
+	}
+	return out
+}
+
+func TestCheck_ReportsIssues(t *testing.T) {
+	m := mustParse(t, checkMap)
+	report := m.Check(&Bounds{MinX: -1, MinY: -1, MaxX: 10, MaxY: 10}, 0.5)
+
+	if report.Severity != SeverityError {
+		t.Fatalf("Expected error severity, got %s", report.Severity)
+	}
+	issues := issuesByCode(report)
+
+	if got := issues[IssueOutOfBounds]; len(got) != 1 || got[0].ElementIDs[0] != "far" {
+		t.Errorf("Expected only poi far out of bounds, got %+v", got)
+	}
+	if got := issues[IssuePartlyOutOfBounds]; len(got) != 1 || got[0].ElementIDs[0] != "edge" {
+		t.Errorf("Expected region edge partly out of bounds, got %+v", got)
+	}
+	if got := issues[IssueGraphDisconnected]; len(got) != 1 || got[0].ElementIDs[0] != "c" {
+		t.Errorf("Expected waypoint c disconnected, got %+v", got)
+	}
+	if got := issues[IssueExclusiveOverlap]; len(got) != 1 {
+		t.Errorf("Expected nogo and lift to overlap, got %+v", got)
+	}
+
+	unreachable := make(map[string]bool)
+	for _, issue := range issues[IssuePOIUnreachable] {
+		unreachable[issue.ElementIDs[0]] = true
+	}
+	if !unreachable["trapped"] || unreachable["home"] {
+		t.Errorf("Expected only trapped to be unreachable, got %v", unreachable)
+	}
+}
+
+func TestCheck_CleanMap(t *testing.T) {
+	m := mustParse(t, planMap)
+	report := m.Check(&Bounds{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}, 1)
+
+	if report.Severity != SeverityOK || len(report.Issues) != 0 {
+		t.Fatalf("Expected clean report, got %s %+v", report.Severity, report.Issues)
+	}
+}
+
+func TestCheck_UnknownBounds(t *testing.T) {
+	report := (&Map{}).Check(nil, 0)
+	if report.Severity != SeverityInfo || report.Issues[0].Code != IssueBoundsUnknown {
+		t.Fatalf("Expected bounds unknown info, got %s %+v", report.Severity, report.Issues)
+	}
+}
+
+func TestRegionsOverlap_SharedBorder(t *testing.T) {
+	a := &Region{Polygon: []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}}
+	b := &Region{Polygon: []Point{{2, 0}, {4, 0}, {4, 2}, {2, 2}}}
+	if regionsOverlap(a, b) {
+		t.Error("Expected regions sharing a border not to overlap")
+	}
+	if !regionsOverlap(a, a) {
+		t.Error("Expected identical regions to overlap")
+	}
+	inner := &Region{Polygon: []Point{{0.5, 0.5}, {1, 0.5}, {1, 1}}}
+	if !regionsOverlap(a, inner) {
+		t.Error("Expected contained region to overlap")
+	}
+}
@@ -0,0 +1,173 @@
+package semantic
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeOp 元素变更类型
+type ChangeOp string
+
+const (
+	ChangeAdded    ChangeOp = "added"
+	ChangeRemoved  ChangeOp = "removed"
+	ChangeModified ChangeOp = "modified"
+)
+
+// kinds 元素类型的固定顺序，保证差异与合并结果稳定
+var kinds = []Kind{KindPOI, KindRegion, KindWaypoint, KindEdge}
+
+// FieldChange 字段级变更，嵌套字段以点号连接（如 pose.x、properties.speedLimit），数组整体比较
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
+
+// Change 单个元素的变更
+type Change struct {
+	Kind   Kind           `json:"kind"`
+	ID     string         `json:"id"`
+	Op     ChangeOp       `json:"op"`
+	Fields []*FieldChange `json:"fields,omitempty"` // 仅 modified 时给出
+}
+
+// Diff 计算从 from 到 to 的元素变更，按类型、ID 排序
+func Diff(from, to *Map) []*Change {
+	changes := make([]*Change, 0)
+	for _, kind := range kinds {
+		before, after := from.flatElements(kind), to.flatElements(kind)
+		for _, id := range sortedKeys(before, after) {
+			b, inBefore := before[id]
+			a, inAfter := after[id]
+			switch {
+			case !inBefore:
+				changes = append(changes, &Change{Kind: kind, ID: id, Op: ChangeAdded})
+			case !inAfter:
+				changes = append(changes, &Change{Kind: kind, ID: id, Op: ChangeRemoved})
+			default:
+				if fields := diffFields(b, a); len(fields) > 0 {
+					changes = append(changes, &Change{Kind: kind, ID: id, Op: ChangeModified, Fields: fields})
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// flatFields 元素展开后的字段，键为以 "\x00" 连接的字段路径
+type flatFields map[string]interface{}
+
+// flatElements 将指定类型的元素按 ID 展开为字段表
+func (m *Map) flatElements(kind Kind) map[string]flatFields {
+	out := make(map[string]flatFields)
+	for _, item := range m.elementList(kind) {
+		out[item.ElementID()] = flatten(item)
+	}
+	return out
+}
+
+// elementList 按原顺序返回指定类型的元素
+func (m *Map) elementList(kind Kind) []Element {
+	switch kind {
+	case KindPOI:
+		return elementsOf(m.POIs)
+	case KindRegion:
+		return elementsOf(m.Regions)
+	case KindWaypoint:
+		return elementsOf(m.Waypoints)
+	case KindEdge:
+		return elementsOf(m.Edges)
+	default:
+		return nil
+	}
+}
+
+func elementsOf[T Element](items []T) []Element {
+	out := make([]Element, 0, len(items))
+	for _, item := range items {
+		out = append(out, item)
+	}
+	return out
+}
+
+// flatten 经 JSON 序列化后将嵌套对象展开为字段路径，数组保持整体
+func flatten(element Element) flatFields {
+	raw, _ := json.Marshal(element)
+	var tree map[string]interface{}
+	_ = json.Unmarshal(raw, &tree)
+
+	out := make(flatFields)
+	var walk func(prefix string, node map[string]interface{})
+	walk = func(prefix string, node map[string]interface{}) {
+		for key, value := range node {
+			path := prefix + key
+			if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+				walk(path+"\x00", child)
+				continue
+			}
+			out[path] = value
+		}
+	}
+	walk("", tree)
+	return out
+}
+
+// unflatten 将字段表还原为元素
+func unflatten[T any](fields flatFields) (T, error) {
+	tree := make(map[string]interface{})
+	for path, value := range fields {
+		parts := strings.Split(path, "\x00")
+		node := tree
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+
+	var item T
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return item, err
+	}
+	err = json.Unmarshal(raw, &item)
+	return item, err
+}
+
+func diffFields(before, after flatFields) []*FieldChange {
+	var fields []*FieldChange
+	for _, path := range sortedKeys(before, after) {
+		b, a := before[path], after[path]
+		if !reflect.DeepEqual(b, a) {
+			fields = append(fields, &FieldChange{Field: fieldName(path), From: b, To: a})
+		}
+	}
+	return fields
+}
+
+func fieldName(path string) string {
+	return strings.ReplaceAll(path, "\x00", ".")
+}
+
+// sortedKeys 返回两个表的全部键，升序
+func sortedKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	ids := make([]string, 0, len(a)+len(b))
+	for _, m := range []map[string]V{a, b} {
+		for id := range m {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,214 @@
+package semantic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownKind 未知的元素类型
+var ErrUnknownKind = errors.New("unknown semantic element kind")
+
+// ErrElementNotFound 元素不存在
+var ErrElementNotFound = errors.New("semantic element not found")
+
+// ErrElementExists 元素ID已存在
+var ErrElementExists = errors.New("semantic element already exists")
+
+// Kind 元素类型，取值与 API 路径一致
+type Kind string
+
+const (
+	KindPOI      Kind = "pois"
+	KindRegion   Kind = "regions"
+	KindWaypoint Kind = "waypoints"
+	KindEdge     Kind = "edges"
+)
+
+// Element 可按ID单独编辑的语义元素
+type Element interface {
+	ElementID() string
+}
+
+func (p *POI) ElementID() string      { return p.ID }
+func (r *Region) ElementID() string   { return r.ID }
+func (w *Waypoint) ElementID() string { return w.ID }
+func (e *Edge) ElementID() string     { return e.ID }
+
+// ParseKind 解析元素类型
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(s); k {
+	case KindPOI, KindRegion, KindWaypoint, KindEdge:
+		return k, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownKind, s)
+	}
+}
+
+// Elements 返回指定类型的全部元素
+func (m *Map) Elements(kind Kind) (interface{}, error) {
+	switch kind {
+	case KindPOI:
+		return nonNil(m.POIs), nil
+	case KindRegion:
+		return nonNil(m.Regions), nil
+	case KindWaypoint:
+		return nonNil(m.Waypoints), nil
+	case KindEdge:
+		return nonNil(m.Edges), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+}
+
+// Element 返回指定类型与ID的元素
+func (m *Map) Element(kind Kind, id string) (Element, error) {
+	var found Element
+	switch kind {
+	case KindPOI:
+		found = find(m.POIs, id)
+	case KindRegion:
+		found = find(m.Regions, id)
+	case KindWaypoint:
+		found = find(m.Waypoints, id)
+	case KindEdge:
+		found = find(m.Edges, id)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrElementNotFound, kind, id)
+	}
+	return found, nil
+}
+
+// Put 从 JSON 解码元素并写入地图：id 为空时新增（ID 取自请求体，已存在则报错），
+// 否则替换该ID的元素（请求体中的 ID 被忽略）。写入后整体校验，失败时地图保持不变
+func (m *Map) Put(kind Kind, id string, data []byte) (Element, error) {
+	next := *m
+	var (
+		element Element
+		err     error
+	)
+	switch kind {
+	case KindPOI:
+		var p *POI
+		p, next.POIs, err = put(m.POIs, id, data, func(p *POI, id string) { p.ID = id })
+		element = p
+	case KindRegion:
+		var r *Region
+		r, next.Regions, err = put(m.Regions, id, data, func(r *Region, id string) { r.ID = id })
+		element = r
+	case KindWaypoint:
+		var w *Waypoint
+		w, next.Waypoints, err = put(m.Waypoints, id, data, func(w *Waypoint, id string) { w.ID = id })
+		element = w
+	case KindEdge:
+		var e *Edge
+		e, next.Edges, err = put(m.Edges, id, data, func(e *Edge, id string) { e.ID = id })
+		element = e
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	*m = next
+	return element, nil
+}
+
+// Remove 删除元素，删除路网节点时一并删除与其相连的边
+func (m *Map) Remove(kind Kind, id string) error {
+	var ok bool
+	switch kind {
+	case KindPOI:
+		m.POIs, ok = remove(m.POIs, id)
+	case KindRegion:
+		m.Regions, ok = remove(m.Regions, id)
+	case KindWaypoint:
+		m.Waypoints, ok = remove(m.Waypoints, id)
+		if ok {
+			edges := make([]*Edge, 0, len(m.Edges))
+			for _, e := range m.Edges {
+				if e.From != id && e.To != id {
+					edges = append(edges, e)
+				}
+			}
+			m.Edges = edges
+		}
+	case KindEdge:
+		m.Edges, ok = remove(m.Edges, id)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s/%s", ErrElementNotFound, kind, id)
+	}
+	return nil
+}
+
+func find[T Element](items []T, id string) Element {
+	for _, item := range items {
+		if item.ElementID() == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// put 解码元素后追加或替换，返回新切片，不修改原切片
+func put[T Element](items []T, id string, data []byte, setID func(T, string)) (T, []T, error) {
+	var item, zero T
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&item); err != nil {
+		return item, nil, fmt.Errorf("%w: %v", ErrInvalidMap, err)
+	}
+	if any(item) == any(zero) {
+		return item, nil, fmt.Errorf("%w: empty element", ErrInvalidMap)
+	}
+
+	out := make([]T, 0, len(items)+1)
+	if id == "" {
+		if find(items, item.ElementID()) != nil {
+			return item, nil, fmt.Errorf("%w: %q", ErrElementExists, item.ElementID())
+		}
+		return item, append(append(out, items...), item), nil
+	}
+
+	setID(item, id)
+	replaced := false
+	for _, existing := range items {
+		if existing.ElementID() == id {
+			out = append(out, item)
+			replaced = true
+			continue
+		}
+		out = append(out, existing)
+	}
+	if !replaced {
+		return item, nil, fmt.Errorf("%w: %s", ErrElementNotFound, id)
+	}
+	return item, out, nil
+}
+
+func remove[T Element](items []T, id string) ([]T, bool) {
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		if item.ElementID() != id {
+			out = append(out, item)
+		}
+	}
+	return out, len(out) != len(items)
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
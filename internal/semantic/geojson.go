@@ -0,0 +1,408 @@
+package semantic
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// 导入 GeoJSON 的问题代码
+const (
+	IssueFeatureInvalid = "feature_invalid" // 要素无法转换为语义元素
+	IssueFeatureIgnored = "feature_ignored" // 要素类型不受支持，已忽略
+)
+
+// GeoJSON 要素的 kind 属性取值
+const (
+	FeatureKindPOI      = "poi"
+	FeatureKindRegion   = "region"
+	FeatureKindWaypoint = "waypoint"
+	FeatureKindEdge     = "edge"
+)
+
+// earthRadius WGS84 椭球长半轴(米)
+const earthRadius = 6378137.0
+
+// edgeSnapTolerance 边缺少 from/to 时按端点匹配路网节点的距离容差(米)
+const edgeSnapTolerance = 0.05
+
+// FeatureCollection GeoJSON 要素集合
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+// Feature GeoJSON 要素
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry GeoJSON 几何，坐标按类型延迟解析
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// GeoTransform 地图坐标系到 WGS84 的近似换算，以原点处的局部切平面展开，适用于园区尺度
+type GeoTransform struct {
+	OriginLon float64 // 地图原点经度(度)
+	OriginLat float64 // 地图原点纬度(度)
+	Rotation  float64 // 地图 x 轴相对正东方向的逆时针旋转角(弧度)
+}
+
+// ToWGS84 地图坐标转换为经纬度
+func (t *GeoTransform) ToWGS84(x, y float64) (lon, lat float64) {
+	sin, cos := math.Sincos(t.Rotation)
+	east, north := x*cos-y*sin, x*sin+y*cos
+	lat = t.OriginLat + north/earthRadius*180/math.Pi
+	lon = t.OriginLon + east/(earthRadius*math.Cos(t.OriginLat*math.Pi/180))*180/math.Pi
+	return lon, lat
+}
+
+// FromWGS84 经纬度转换为地图坐标
+func (t *GeoTransform) FromWGS84(lon, lat float64) (x, y float64) {
+	north := (lat - t.OriginLat) * math.Pi / 180 * earthRadius
+	east := (lon - t.OriginLon) * math.Pi / 180 * earthRadius * math.Cos(t.OriginLat*math.Pi/180)
+	sin, cos := math.Sincos(t.Rotation)
+	return east*cos + north*sin, -east*sin + north*cos
+}
+
+// ToGeoJSON 将兴趣点、区域与路网导出为 GeoJSON 要素集合，transform 为 nil 时坐标为地图坐标系(米)，
+// 否则为 WGS84 经纬度；朝向始终为地图坐标系下的弧度
+func (m *Map) ToGeoJSON(transform *GeoTransform) *FeatureCollection {
+	position := func(x, y float64) []float64 {
+		if transform == nil {
+			return []float64{x, y}
+		}
+		lon, lat := transform.ToWGS84(x, y)
+		return []float64{lon, lat}
+	}
+
+	fc := &FeatureCollection{Type: "FeatureCollection", Features: make([]*Feature, 0)}
+	for _, p := range m.POIs {
+		props := map[string]interface{}{"kind": FeatureKindPOI, "name": p.Name, "poiType": p.Type, "yaw": p.Pose.Yaw}
+		if len(p.Properties) > 0 {
+			props["properties"] = p.Properties
+		}
+		fc.Features = append(fc.Features, newFeature(p.ID, "Point", position(p.Pose.X, p.Pose.Y), props))
+	}
+	for _, r := range m.Regions {
+		ring := make([][]float64, 0, len(r.Polygon)+1)
+		for _, pt := range r.Polygon {
+			ring = append(ring, position(pt.X, pt.Y))
+		}
+		ring = append(ring, ring[0])
+		props := map[string]interface{}{"kind": FeatureKindRegion, "name": r.Name}
+		mergeStruct(props, r.Properties)
+		fc.Features = append(fc.Features, newFeature(r.ID, "Polygon", [][][]float64{ring}, props))
+	}
+
+	waypoints := make(map[string]*Waypoint, len(m.Waypoints))
+	for _, w := range m.Waypoints {
+		waypoints[w.ID] = w
+		props := map[string]interface{}{"kind": FeatureKindWaypoint}
+		if w.Name != "" {
+			props["name"] = w.Name
+		}
+		fc.Features = append(fc.Features, newFeature(w.ID, "Point", position(w.X, w.Y), props))
+	}
+	for _, e := range m.Edges {
+		from, to := waypoints[e.From], waypoints[e.To]
+		if from == nil || to == nil {
+			continue
+		}
+		props := map[string]interface{}{"kind": FeatureKindEdge, "from": e.From, "to": e.To}
+		if e.Cost != nil {
+			props["cost"] = *e.Cost
+		}
+		if len(e.DeviceTypes) > 0 {
+			props["deviceTypes"] = e.DeviceTypes
+		}
+		line := [][]float64{position(from.X, from.Y), position(to.X, to.Y)}
+		fc.Features = append(fc.Features, newFeature(e.ID, "LineString", line, props))
+	}
+	return fc
+}
+
+func newFeature(id, geometryType string, coordinates interface{}, props map[string]interface{}) *Feature {
+	raw, _ := json.Marshal(coordinates)
+	return &Feature{
+		Type:       "Feature",
+		ID:         id,
+		Geometry:   &Geometry{Type: geometryType, Coordinates: raw},
+		Properties: props,
+	}
+}
+
+// mergeStruct 将结构体经 JSON 序列化后的字段并入属性表
+func mergeStruct(props map[string]interface{}, v interface{}) {
+	raw, _ := json.Marshal(v)
+	var fields map[string]interface{}
+	_ = json.Unmarshal(raw, &fields)
+	for k, value := range fields {
+		props[k] = value
+	}
+}
+
+// FromGeoJSON 由 GeoJSON 要素集合构建语义地图，transform 为 nil 时坐标按地图坐标系解析。
+// 要素按 properties.kind 区分类型，缺省时 Point 视为兴趣点、Polygon 视为区域、LineString 视为路网边；
+// 边缺少 from/to 时按端点匹配已有路网节点。无法转换的要素记为错误、不支持的要素记为警告，
+// 转换结果仍需通过结构校验，否则同样记为错误。文档本身不是要素集合时返回 ErrInvalidMap
+func FromGeoJSON(data []byte, transform *GeoTransform) (*Map, *Report, error) {
+	var fc FeatureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidMap, err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, nil, fmt.Errorf("%w: geojson type must be FeatureCollection, got %q", ErrInvalidMap, fc.Type)
+	}
+
+	b := &geoBuilder{m: &Map{}, report: NewReport(), transform: transform}
+	var edges []int
+	for i, f := range fc.Features {
+		if f == nil || f.Geometry == nil {
+			b.report.Add(SeverityWarning, IssueFeatureIgnored, "", nil, "第 %d 个要素没有几何，已忽略", i+1)
+			continue
+		}
+		switch featureKind(f) {
+		case FeatureKindPOI:
+			b.addPOI(i, f)
+		case FeatureKindRegion:
+			b.addRegion(i, f)
+		case FeatureKindWaypoint:
+			b.addWaypoint(i, f)
+		case FeatureKindEdge:
+			// 边引用路网节点，待全部节点解析后再处理
+			edges = append(edges, i)
+		default:
+			b.report.Add(SeverityWarning, IssueFeatureIgnored, "", nil, "第 %d 个要素(%s)类型不受支持，已忽略", i+1, f.Geometry.Type)
+		}
+	}
+	for _, i := range edges {
+		b.addEdge(i, fc.Features[i])
+	}
+
+	if b.report.Severity != SeverityError {
+		if err := b.m.Validate(); err != nil {
+			b.report.Add(SeverityError, IssueSchemaInvalid, "", nil, "导入结果不符合结构定义: %v", err)
+		}
+	}
+	return b.m, b.report, nil
+}
+
+// featureKind 读取要素类型，未指定时按几何推断
+func featureKind(f *Feature) string {
+	if kind, ok := f.Properties["kind"].(string); ok && kind != "" {
+		return kind
+	}
+	switch f.Geometry.Type {
+	case "Point":
+		return FeatureKindPOI
+	case "Polygon":
+		return FeatureKindRegion
+	case "LineString":
+		return FeatureKindEdge
+	default:
+		return ""
+	}
+}
+
+type geoBuilder struct {
+	m         *Map
+	report    *Report
+	transform *GeoTransform
+}
+
+func (b *geoBuilder) point(c []float64) (float64, float64, bool) {
+	if len(c) < 2 || !finite(c[0], c[1]) {
+		return 0, 0, false
+	}
+	if b.transform == nil {
+		return c[0], c[1], true
+	}
+	x, y := b.transform.FromWGS84(c[0], c[1])
+	return x, y, true
+}
+
+// featureID 优先使用 properties.id，其次为要素 id，都没有时按类型与序号生成
+func (b *geoBuilder) featureID(i int, f *Feature, kind string) string {
+	if id, ok := f.Properties["id"].(string); ok && id != "" {
+		return id
+	}
+	switch id := f.ID.(type) {
+	case string:
+		if id != "" {
+			return id
+		}
+	case float64:
+		return fmt.Sprintf("%v", id)
+	}
+	return fmt.Sprintf("%s-%d", kind, i+1)
+}
+
+func (b *geoBuilder) invalid(i int, kind Kind, id, format string, args ...interface{}) {
+	b.report.Add(SeverityError, IssueFeatureInvalid, kind, []string{id}, "第 %d 个要素 %s: %s", i+1, id, fmt.Sprintf(format, args...))
+}
+
+func (b *geoBuilder) addPOI(i int, f *Feature) {
+	id := b.featureID(i, f, FeatureKindPOI)
+	var c []float64
+	if f.Geometry.Type != "Point" || json.Unmarshal(f.Geometry.Coordinates, &c) != nil {
+		b.invalid(i, KindPOI, id, "兴趣点几何必须为 Point")
+		return
+	}
+	x, y, ok := b.point(c)
+	if !ok {
+		b.invalid(i, KindPOI, id, "坐标无效")
+		return
+	}
+
+	p := &POI{ID: id, Type: POITypeGeneric, Pose: Pose{X: x, Y: y}}
+	p.Name, _ = f.Properties["name"].(string)
+	if t, ok := f.Properties["poiType"].(string); ok && t != "" {
+		p.Type = POIType(t)
+	}
+	if yaw, ok := f.Properties["yaw"].(float64); ok {
+		p.Pose.Yaw = yaw
+	}
+	if props, ok := f.Properties["properties"].(map[string]interface{}); ok {
+		p.Properties = props
+	}
+	if err := p.validate(); err != nil {
+		b.invalid(i, KindPOI, id, "%v", err)
+		return
+	}
+	b.m.POIs = append(b.m.POIs, p)
+}
+
+func (b *geoBuilder) addRegion(i int, f *Feature) {
+	id := b.featureID(i, f, FeatureKindRegion)
+	var rings [][][]float64
+	if f.Geometry.Type != "Polygon" || json.Unmarshal(f.Geometry.Coordinates, &rings) != nil || len(rings) == 0 {
+		b.invalid(i, KindRegion, id, "区域几何必须为 Polygon")
+		return
+	}
+	if len(rings) > 1 {
+		b.report.Add(SeverityWarning, IssueFeatureIgnored, KindRegion, []string{id}, "区域 %s 的内环(洞)不受支持，已忽略", id)
+	}
+
+	r := &Region{ID: id}
+	r.Name, _ = f.Properties["name"].(string)
+	ring := rings[0]
+	// GeoJSON 外环首尾重复，语义地图的多边形不重复首点
+	if n := len(ring); n > 1 && len(ring[0]) >= 2 && len(ring[n-1]) >= 2 && ring[0][0] == ring[n-1][0] && ring[0][1] == ring[n-1][1] {
+		ring = ring[:n-1]
+	}
+	for _, c := range ring {
+		x, y, ok := b.point(c)
+		if !ok {
+			b.invalid(i, KindRegion, id, "顶点坐标无效")
+			return
+		}
+		r.Polygon = append(r.Polygon, Point{X: x, Y: y})
+	}
+
+	raw, _ := json.Marshal(f.Properties)
+	if err := json.Unmarshal(raw, &r.Properties); err != nil {
+		b.invalid(i, KindRegion, id, "区域属性无效: %v", err)
+		return
+	}
+	if err := r.validate(); err != nil {
+		b.invalid(i, KindRegion, id, "%v", err)
+		return
+	}
+	b.m.Regions = append(b.m.Regions, r)
+}
+
+func (b *geoBuilder) addWaypoint(i int, f *Feature) {
+	id := b.featureID(i, f, FeatureKindWaypoint)
+	var c []float64
+	if f.Geometry.Type != "Point" || json.Unmarshal(f.Geometry.Coordinates, &c) != nil {
+		b.invalid(i, KindWaypoint, id, "路网节点几何必须为 Point")
+		return
+	}
+	x, y, ok := b.point(c)
+	if !ok {
+		b.invalid(i, KindWaypoint, id, "坐标无效")
+		return
+	}
+	w := &Waypoint{ID: id, X: x, Y: y}
+	w.Name, _ = f.Properties["name"].(string)
+	b.m.Waypoints = append(b.m.Waypoints, w)
+}
+
+func (b *geoBuilder) addEdge(i int, f *Feature) {
+	id := b.featureID(i, f, FeatureKindEdge)
+
+	var line [][]float64
+	if f.Geometry.Type != "LineString" || json.Unmarshal(f.Geometry.Coordinates, &line) != nil || len(line) != 2 {
+		b.invalid(i, KindEdge, id, "路网边几何必须为两个端点的 LineString")
+		return
+	}
+
+	e := &Edge{ID: id}
+	e.From, _ = f.Properties["from"].(string)
+	e.To, _ = f.Properties["to"].(string)
+	for end, ref := range []*string{&e.From, &e.To} {
+		if *ref != "" {
+			continue
+		}
+		x, y, ok := b.point(line[end])
+		if !ok {
+			b.invalid(i, KindEdge, id, "端点坐标无效")
+			return
+		}
+		w := b.m.nearestWaypointWithin(x, y, edgeSnapTolerance)
+		if w == nil {
+			b.invalid(i, KindEdge, id, "端点 (%.3f, %.3f) 附近没有路网节点，请通过 from/to 指定", x, y)
+			return
+		}
+		*ref = w.ID
+	}
+	if cost, ok := f.Properties["cost"].(float64); ok {
+		e.Cost = &cost
+	}
+	if types, ok := f.Properties["deviceTypes"].([]interface{}); ok {
+		for _, t := range types {
+			if s, ok := t.(string); ok {
+				e.DeviceTypes = append(e.DeviceTypes, s)
+			}
+		}
+	}
+	if err := e.validate(); err != nil {
+		b.invalid(i, KindEdge, id, "%v", err)
+		return
+	}
+	b.m.Edges = append(b.m.Edges, e)
+}
+
+// nearestWaypointWithin 返回距离不超过 tolerance 的最近路网节点
+func (m *Map) nearestWaypointWithin(x, y, tolerance float64) *Waypoint {
+	var best *Waypoint
+	bestDist := tolerance
+	for _, w := range m.Waypoints {
+		if d := math.Hypot(w.X-x, w.Y-y); d <= bestDist {
+			best, bestDist = w, d
+		}
+	}
+	return best
+}
+
+// Counts 各类元素数量，键为元素类型
+func (m *Map) Counts() map[Kind]int {
+	counts := make(map[Kind]int, len(kinds))
+	for _, kind := range kinds {
+		counts[kind] = len(m.elementList(kind))
+	}
+	return counts
+}
+
+// Merge 将另一份报告的问题追加到本报告
+func (r *Report) Merge(other *Report) {
+	for _, issue := range other.Issues {
+		r.Add(issue.Severity, issue.Code, issue.Kind, issue.ElementIDs, "%s", issue.Message)
+	}
+}
@@ -0,0 +1,83 @@
+package semantic
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+const geoMap = `{
+	"pois": [{"id": "p1", "name": "充电桩", "type": "charging", "pose": {"x": 1, "y": 2, "yaw": 0.5}, "properties": {"floor": 3}}],
+	"regions": [{"id": "r1", "name": "禁行区", "polygon": [{"x": 0, "y": 0}, {"x": 4, "y": 0}, {"x": 4, "y": 4}], "properties": {"noGo": true}}],
+	"waypoints": [{"id": "a", "x": 0, "y": 0}, {"id": "b", "x": 5, "y": 0}],
+	"edges": [{"id": "a-b", "from": "a", "to": "b", "cost": 6}]
+}`
+
+func TestGeoJSON_RoundTrip(t *testing.T) {
+	m := mustParse(t, geoMap)
+	transforms := map[string]*GeoTransform{
+		"local": nil,
+		"wgs84": {OriginLon: 121.47, OriginLat: 31.23, Rotation: 0.3},
+	}
+	for name, transform := range transforms {
+		raw, err := json.Marshal(m.ToGeoJSON(transform))
+		if err != nil {
+			t.Fatalf("%s: marshal failed: %v", name, err)
+		}
+		back, report, err := FromGeoJSON(raw, transform)
+		if err != nil {
+			t.Fatalf("%s: FromGeoJSON failed: %v", name, err)
+		}
+		if report.Severity != SeverityOK {
+			t.Fatalf("%s: expected clean report, got %+v", name, report.Issues)
+		}
+
+		// 经纬度换算存在舍入误差，逐字段比较坐标
+		if len(back.Regions) != 1 || len(back.Regions[0].Polygon) != 3 || !back.Regions[0].Properties.NoGo {
+			t.Errorf("%s: region not restored: %+v", name, back.Regions)
+		}
+		p := back.POIs[0]
+		if p.Type != POITypeCharging || p.Pose.Yaw != 0.5 || math.Abs(p.Pose.X-1) > 1e-6 || math.Abs(p.Pose.Y-2) > 1e-6 {
+			t.Errorf("%s: poi not restored: %+v", name, p)
+		}
+		if e := back.Edges[0]; e.From != "a" || e.To != "b" || e.Cost == nil || *e.Cost != 6 {
+			t.Errorf("%s: edge not restored: %+v", name, e)
+		}
+	}
+}
+
+func TestGeoJSON_ImportReport(t *testing.T) {
+	data := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "id": "a", "geometry": {"type": "Point", "coordinates": [0, 0]}, "properties": {"kind": "waypoint"}},
+		{"type": "Feature", "id": "b", "geometry": {"type": "Point", "coordinates": [3, 0]}, "properties": {"kind": "waypoint"}},
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0, 0], [3, 0.01]]}, "properties": {}},
+		{"type": "Feature", "geometry": {"type": "MultiPoint", "coordinates": [[0, 0]]}, "properties": {}}
+	]}`
+	m, report, err := FromGeoJSON([]byte(data), nil)
+	if err != nil {
+		t.Fatalf("FromGeoJSON failed: %v", err)
+	}
+	if report.Severity != SeverityWarning || len(report.Issues) != 1 || report.Issues[0].Code != IssueFeatureIgnored {
+		t.Fatalf("Expected one ignored-feature warning, got %+v", report.Issues)
+	}
+	// 未指定 from/to 的边按端点吸附到路网节点
+	if len(m.Edges) != 1 || m.Edges[0].From != "a" || m.Edges[0].To != "b" || m.Edges[0].ID != "edge-3" {
+		t.Errorf("Expected snapped edge edge-3 a->b, got %+v", m.Edges)
+	}
+
+	bad := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "id": "p", "geometry": {"type": "Point", "coordinates": [0, 0]}, "properties": {"poiType": "toilet"}},
+		{"type": "Feature", "id": "e", "geometry": {"type": "LineString", "coordinates": [[0, 0], [9, 9]]}, "properties": {}}
+	]}`
+	_, report, err = FromGeoJSON([]byte(bad), nil)
+	if err != nil {
+		t.Fatalf("FromGeoJSON failed: %v", err)
+	}
+	if report.Severity != SeverityError || len(report.Issues) != 2 {
+		t.Errorf("Expected two feature errors, got %+v", report.Issues)
+	}
+
+	if _, _, err := FromGeoJSON([]byte(`{"type": "Feature"}`), nil); err == nil {
+		t.Error("Expected error for non-collection document")
+	}
+}
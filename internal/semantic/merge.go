@@ -0,0 +1,166 @@
+package semantic
+
+import "reflect"
+
+// Conflict 三方合并冲突
+type Conflict struct {
+	Kind   Kind        `json:"kind,omitempty"`
+	ID     string      `json:"id,omitempty"`
+	Field  string      `json:"field,omitempty"` // 为空表示整个元素冲突
+	Base   interface{} `json:"base,omitempty"`
+	Ours   interface{} `json:"ours,omitempty"`
+	Theirs interface{} `json:"theirs,omitempty"`
+	Reason string      `json:"reason"`
+}
+
+// Merge 以 base 为共同祖先三方合并 ours 与 theirs：只有一方修改的元素或字段取该方内容，
+// 双方对同一字段做了不同修改、或一方删除而另一方修改时记为冲突，冲突处保留 ours 的内容。
+// 合并结果不满足结构校验时（如一方删除了另一方新边引用的路网节点）同样记为冲突
+func Merge(base, ours, theirs *Map) (*Map, []*Conflict) {
+	merged := &Map{}
+	conflicts := make([]*Conflict, 0)
+	for _, kind := range kinds {
+		b, o, t := base.flatElements(kind), ours.flatElements(kind), theirs.flatElements(kind)
+
+		// 结果顺序：ours 中的元素在前，theirs 新增的元素按其顺序追加
+		order := ours.elementIDs(kind)
+		for _, id := range theirs.elementIDs(kind) {
+			if _, ok := o[id]; !ok {
+				order = append(order, id)
+			}
+		}
+
+		for _, id := range order {
+			bf, inBase := b[id]
+			of, inOurs := o[id]
+			tf, inTheirs := t[id]
+
+			var (
+				result  flatFields
+				present bool
+			)
+			switch {
+			case sameElement(of, inOurs, tf, inTheirs), sameElement(bf, inBase, tf, inTheirs):
+				result, present = of, inOurs
+			case sameElement(bf, inBase, of, inOurs):
+				result, present = tf, inTheirs
+			case !inOurs || !inTheirs:
+				result, present = of, inOurs
+				reason := "ours 删除了该元素而 theirs 修改了它"
+				if !inTheirs {
+					reason = "theirs 删除了该元素而 ours 修改了它"
+				}
+				conflicts = append(conflicts, &Conflict{Kind: kind, ID: id, Reason: reason})
+			default:
+				var fieldConflicts []*Conflict
+				result, fieldConflicts = mergeFields(kind, id, bf, of, tf)
+				present = true
+				conflicts = append(conflicts, fieldConflicts...)
+			}
+			if !present {
+				continue
+			}
+			if err := merged.appendFlat(kind, result); err != nil {
+				conflicts = append(conflicts, &Conflict{Kind: kind, ID: id, Reason: "无法还原合并后的元素: " + err.Error()})
+			}
+		}
+	}
+
+	if err := merged.Validate(); err != nil {
+		conflicts = append(conflicts, &Conflict{Reason: "合并结果校验失败: " + err.Error()})
+	}
+	return merged, conflicts
+}
+
+// mergeFields 逐字段三方合并同一元素，双方修改了同一字段且取值不同时保留 ours 并记为冲突
+func mergeFields(kind Kind, id string, base, ours, theirs flatFields) (flatFields, []*Conflict) {
+	result := make(flatFields)
+	var conflicts []*Conflict
+	for _, path := range sortedKeys(unionFields(base, ours), theirs) {
+		bv, inBase := base[path]
+		ov, inOurs := ours[path]
+		tv, inTheirs := theirs[path]
+
+		var (
+			value   interface{}
+			present bool
+		)
+		switch {
+		case sameValue(ov, inOurs, tv, inTheirs), sameValue(bv, inBase, tv, inTheirs):
+			value, present = ov, inOurs
+		case sameValue(bv, inBase, ov, inOurs):
+			value, present = tv, inTheirs
+		default:
+			value, present = ov, inOurs
+			conflicts = append(conflicts, &Conflict{
+				Kind: kind, ID: id, Field: fieldName(path),
+				Base: bv, Ours: ov, Theirs: tv,
+				Reason: "双方修改了同一字段",
+			})
+		}
+		if present {
+			result[path] = value
+		}
+	}
+	return result, conflicts
+}
+
+func sameElement(a flatFields, aok bool, b flatFields, bok bool) bool {
+	return aok == bok && (!aok || reflect.DeepEqual(a, b))
+}
+
+func sameValue(a interface{}, aok bool, b interface{}, bok bool) bool {
+	return aok == bok && (!aok || reflect.DeepEqual(a, b))
+}
+
+func unionFields(a, b flatFields) flatFields {
+	out := make(flatFields, len(a)+len(b))
+	for k, v := range a {
+		out[k] = v
+	}
+	for k, v := range b {
+		out[k] = v
+	}
+	return out
+}
+
+// elementIDs 按原顺序返回指定类型的元素ID
+func (m *Map) elementIDs(kind Kind) []string {
+	items := m.elementList(kind)
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ElementID())
+	}
+	return ids
+}
+
+// appendFlat 将字段表还原为元素并追加到地图
+func (m *Map) appendFlat(kind Kind, fields flatFields) error {
+	switch kind {
+	case KindPOI:
+		p, err := unflatten[*POI](fields)
+		if err != nil {
+			return err
+		}
+		m.POIs = append(m.POIs, p)
+	case KindRegion:
+		r, err := unflatten[*Region](fields)
+		if err != nil {
+			return err
+		}
+		m.Regions = append(m.Regions, r)
+	case KindWaypoint:
+		w, err := unflatten[*Waypoint](fields)
+		if err != nil {
+			return err
+		}
+		m.Waypoints = append(m.Waypoints, w)
+	case KindEdge:
+		e, err := unflatten[*Edge](fields)
+		if err != nil {
+			return err
+		}
+		m.Edges = append(m.Edges, e)
+	}
+	return nil
+}
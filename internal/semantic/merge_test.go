@@ -0,0 +1,112 @@
+package semantic
+
+import "testing"
+
+const mergeBase = `{
+	"pois": [
+		{"id": "p1", "name": "工位1", "type": "workstation", "pose": {"x": 1, "y": 1, "yaw": 0}},
+		{"id": "p2", "name": "充电桩", "type": "charging", "pose": {"x": 2, "y": 2, "yaw": 0}}
+	],
+	"waypoints": [{"id": "a", "x": 0, "y": 0}, {"id": "b", "x": 5, "y": 0}],
+	"edges": [{"id": "a-b", "from": "a", "to": "b"}]
+}`
+
+func TestDiff_FieldLevel(t *testing.T) {
+	from := mustParse(t, mergeBase)
+	to := mustParse(t, `{
+		"pois": [
+			{"id": "p1", "name": "工位1", "type": "workstation", "pose": {"x": 1.5, "y": 1, "yaw": 0}},
+			{"id": "p3", "name": "停靠点", "type": "dock", "pose": {"x": 3, "y": 3, "yaw": 0}}
+		],
+		"waypoints": [{"id": "a", "x": 0, "y": 0}, {"id": "b", "x": 5, "y": 0}],
+		"edges": [{"id": "a-b", "from": "a", "to": "b", "cost": 7}]
+	}`)
+
+	changes := Diff(from, to)
+	if len(changes) != 4 {
+		t.Fatalf("Expected 4 changes, got %d: %+v", len(changes), changes)
+	}
+	want := []struct {
+		kind Kind
+		id   string
+		op   ChangeOp
+	}{
+		{KindPOI, "p1", ChangeModified}, {KindPOI, "p2", ChangeRemoved},
+		{KindPOI, "p3", ChangeAdded}, {KindEdge, "a-b", ChangeModified},
+	}
+	for i, w := range want {
+		if c := changes[i]; c.Kind != w.kind || c.ID != w.id || c.Op != w.op {
+			t.Errorf("Change %d: expected %s/%s %s, got %s/%s %s", i, w.kind, w.id, w.op, c.Kind, c.ID, c.Op)
+		}
+	}
+	if f := changes[0].Fields; len(f) != 1 || f[0].Field != "pose.x" || f[0].From != 1.0 || f[0].To != 1.5 {
+		t.Errorf("Expected pose.x 1 -> 1.5, got %+v", f)
+	}
+	if f := changes[3].Fields; len(f) != 1 || f[0].Field != "cost" || f[0].From != nil {
+		t.Errorf("Expected cost added, got %+v", f)
+	}
+}
+
+func TestMerge_NonOverlappingEdits(t *testing.T) {
+	base := mustParse(t, mergeBase)
+	ours := mustParse(t, mergeBase)
+	theirs := mustParse(t, mergeBase)
+
+	ours.POIs[0].Name = "一号工位"
+	theirs.POIs[0].Pose.X = 9
+	theirs.Waypoints = append(theirs.Waypoints, &Waypoint{ID: "c", X: 5, Y: 5})
+	ours.POIs = ours.POIs[:1]
+
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("Expected no conflicts, got %+v", conflicts[0])
+	}
+	if len(merged.POIs) != 1 || merged.POIs[0].Name != "一号工位" || merged.POIs[0].Pose.X != 9 {
+		t.Errorf("Expected both edits of p1 and removal of p2, got %+v", merged.POIs)
+	}
+	if len(merged.Waypoints) != 3 || merged.Waypoints[2].ID != "c" {
+		t.Errorf("Expected waypoint c appended, got %d waypoints", len(merged.Waypoints))
+	}
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	base := mustParse(t, mergeBase)
+	ours := mustParse(t, mergeBase)
+	theirs := mustParse(t, mergeBase)
+
+	ours.POIs[0].Name = "ours"
+	theirs.POIs[0].Name = "theirs"
+	ours.POIs = ours.POIs[:1]
+	theirs.POIs[1].Type = POITypeDock
+
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 2 {
+		t.Fatalf("Expected 2 conflicts, got %d: %+v", len(conflicts), conflicts)
+	}
+	if c := conflicts[0]; c.ID != "p1" || c.Field != "name" || c.Ours != "ours" || c.Theirs != "theirs" || c.Base != "工位1" {
+		t.Errorf("Expected name conflict on p1, got %+v", c)
+	}
+	if c := conflicts[1]; c.ID != "p2" || c.Field != "" {
+		t.Errorf("Expected delete/modify conflict on p2, got %+v", c)
+	}
+	if len(merged.POIs) != 1 || merged.POIs[0].Name != "ours" {
+		t.Errorf("Expected ours to be kept on conflict, got %+v", merged.POIs)
+	}
+}
+
+func TestMerge_InvalidResult(t *testing.T) {
+	base := mustParse(t, mergeBase)
+	ours := mustParse(t, mergeBase)
+	theirs := mustParse(t, mergeBase)
+
+	// ours 删除节点 b 及其边，theirs 新增了引用 b 的边
+	if err := ours.Remove(KindWaypoint, "b"); err != nil {
+		t.Fatal(err)
+	}
+	theirs.Edges = append(theirs.Edges, &Edge{ID: "b-a", From: "b", To: "a"})
+
+	_, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 1 || conflicts[0].Kind != "" {
+		t.Fatalf("Expected a validation conflict, got %+v", conflicts)
+	}
+}
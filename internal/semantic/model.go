@@ -0,0 +1,288 @@
+package semantic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrInvalidMap 语义信息不符合结构定义
+var ErrInvalidMap = errors.New("invalid semantic map")
+
+// POIType 兴趣点类型
+type POIType string
+
+const (
+	POITypeGeneric     POIType = "generic"     // 通用点位
+	POITypeCharging    POIType = "charging"    // 充电桩
+	POITypeDock        POIType = "dock"        // 停靠点
+	POITypeWorkstation POIType = "workstation" // 工位
+	POITypeElevator    POIType = "elevator"    // 电梯口
+)
+
+var poiTypes = map[POIType]bool{
+	POITypeGeneric:     true,
+	POITypeCharging:    true,
+	POITypeDock:        true,
+	POITypeWorkstation: true,
+	POITypeElevator:    true,
+}
+
+// Map 语义地图，序列化为 JSON 后保存在 SemanticMap.SemanticInfo 中
+// 坐标均位于关联点云地图的坐标系，单位为米，朝向为弧度
+type Map struct {
+	POIs      []*POI      `json:"pois"`      // 兴趣点
+	Regions   []*Region   `json:"regions"`   // 多边形区域（限速、禁行、单行、电梯等）
+	Waypoints []*Waypoint `json:"waypoints"` // 路网节点
+	Edges     []*Edge     `json:"edges"`     // 路网有向边
+}
+
+// Pose 平面位姿
+type Pose struct {
+	X   float64 `json:"x"`
+	Y   float64 `json:"y"`
+	Yaw float64 `json:"yaw"`
+}
+
+// Point 平面坐标
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// POI 兴趣点
+type POI struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Type       POIType                `json:"type"`
+	Pose       Pose                   `json:"pose"`
+	Properties map[string]interface{} `json:"properties,omitempty"` // 业务自定义属性
+}
+
+// Region 多边形区域，顶点按顺序首尾相连
+type Region struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Polygon    []Point          `json:"polygon"`
+	Properties RegionProperties `json:"properties"`
+}
+
+// RegionProperties 区域通行属性
+type RegionProperties struct {
+	SpeedLimit *float64 `json:"speedLimit,omitempty"` // 限速(米/秒)
+	NoGo       bool     `json:"noGo,omitempty"`       // 禁行区
+	OneWay     *float64 `json:"oneWay,omitempty"`     // 单行区允许的通行朝向(弧度)
+	Elevator   bool     `json:"elevator,omitempty"`   // 电梯轿厢
+
+	DeniedDeviceTypes []string `json:"deniedDeviceTypes,omitempty"` // 禁止进入的设备类型
+}
+
+// Waypoint 路网节点
+type Waypoint struct {
+	ID   string  `json:"id"`
+	Name string  `json:"name,omitempty"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// Edge 路网有向边，双向通行需要两条边
+type Edge struct {
+	ID   string   `json:"id"`
+	From string   `json:"from"`           // 起点路网节点ID
+	To   string   `json:"to"`             // 终点路网节点ID
+	Cost *float64 `json:"cost,omitempty"` // 通行代价，缺省为两节点的欧氏距离
+
+	DeviceTypes []string `json:"deviceTypes,omitempty"` // 允许通行的设备类型，为空时不限制
+}
+
+// Parse 解析并校验语义信息，空字符串视为空地图
+func Parse(info string) (*Map, error) {
+	m := &Map{}
+	if strings.TrimSpace(info) == "" {
+		return m, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(info))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMap, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidMap)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// String 序列化为保存到数据库的 JSON，空集合输出为 []
+func (m *Map) String() string {
+	out := *m
+	if out.POIs == nil {
+		out.POIs = []*POI{}
+	}
+	if out.Regions == nil {
+		out.Regions = []*Region{}
+	}
+	if out.Waypoints == nil {
+		out.Waypoints = []*Waypoint{}
+	}
+	if out.Edges == nil {
+		out.Edges = []*Edge{}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(&out)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Validate 校验元素ID唯一、取值合法，以及路网边引用的节点存在
+func (m *Map) Validate() error {
+	ids := make(map[string]bool)
+	for _, p := range m.POIs {
+		if p == nil {
+			return fmt.Errorf("%w: null element in %s", ErrInvalidMap, KindPOI)
+		}
+		if err := checkID(ids, KindPOI, p.ID); err != nil {
+			return err
+		}
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+
+	ids = make(map[string]bool)
+	for _, r := range m.Regions {
+		if r == nil {
+			return fmt.Errorf("%w: null element in %s", ErrInvalidMap, KindRegion)
+		}
+		if err := checkID(ids, KindRegion, r.ID); err != nil {
+			return err
+		}
+		if err := r.validate(); err != nil {
+			return err
+		}
+	}
+
+	waypoints := make(map[string]bool)
+	for _, w := range m.Waypoints {
+		if w == nil {
+			return fmt.Errorf("%w: null element in %s", ErrInvalidMap, KindWaypoint)
+		}
+		if err := checkID(waypoints, KindWaypoint, w.ID); err != nil {
+			return err
+		}
+		if err := w.validate(); err != nil {
+			return err
+		}
+	}
+
+	ids = make(map[string]bool)
+	pairs := make(map[[2]string]bool)
+	for _, e := range m.Edges {
+		if e == nil {
+			return fmt.Errorf("%w: null element in %s", ErrInvalidMap, KindEdge)
+		}
+		if err := checkID(ids, KindEdge, e.ID); err != nil {
+			return err
+		}
+		if err := e.validate(); err != nil {
+			return err
+		}
+		if !waypoints[e.From] || !waypoints[e.To] {
+			return fmt.Errorf("%w: edge %q references unknown waypoint", ErrInvalidMap, e.ID)
+		}
+		pair := [2]string{e.From, e.To}
+		if pairs[pair] {
+			return fmt.Errorf("%w: duplicate edge %s -> %s", ErrInvalidMap, e.From, e.To)
+		}
+		pairs[pair] = true
+	}
+	return nil
+}
+
+// checkID 元素ID不能为空且在同类元素中唯一
+func checkID(seen map[string]bool, kind Kind, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("%w: element in %s has empty id", ErrInvalidMap, kind)
+	}
+	if seen[id] {
+		return fmt.Errorf("%w: duplicate id %q in %s", ErrInvalidMap, id, kind)
+	}
+	seen[id] = true
+	return nil
+}
+
+func (p *POI) validate() error {
+	if !poiTypes[p.Type] {
+		return fmt.Errorf("%w: poi %q has unknown type %q", ErrInvalidMap, p.ID, p.Type)
+	}
+	if !finite(p.Pose.X, p.Pose.Y, p.Pose.Yaw) {
+		return fmt.Errorf("%w: poi %q has invalid pose", ErrInvalidMap, p.ID)
+	}
+	return nil
+}
+
+func (r *Region) validate() error {
+	if len(r.Polygon) < 3 {
+		return fmt.Errorf("%w: region %q needs at least 3 vertices", ErrInvalidMap, r.ID)
+	}
+	for _, pt := range r.Polygon {
+		if !finite(pt.X, pt.Y) {
+			return fmt.Errorf("%w: region %q has invalid vertex", ErrInvalidMap, r.ID)
+		}
+	}
+	if polygonArea(r.Polygon) == 0 {
+		return fmt.Errorf("%w: region %q has zero area", ErrInvalidMap, r.ID)
+	}
+	props := r.Properties
+	if props.SpeedLimit != nil && (!finite(*props.SpeedLimit) || *props.SpeedLimit <= 0) {
+		return fmt.Errorf("%w: region %q speed limit must be positive", ErrInvalidMap, r.ID)
+	}
+	if props.OneWay != nil && !finite(*props.OneWay) {
+		return fmt.Errorf("%w: region %q has invalid one-way heading", ErrInvalidMap, r.ID)
+	}
+	return nil
+}
+
+func (w *Waypoint) validate() error {
+	if !finite(w.X, w.Y) {
+		return fmt.Errorf("%w: waypoint %q has invalid position", ErrInvalidMap, w.ID)
+	}
+	return nil
+}
+
+func (e *Edge) validate() error {
+	if e.From == e.To {
+		return fmt.Errorf("%w: edge %q is a self loop", ErrInvalidMap, e.ID)
+	}
+	if e.Cost != nil && (!finite(*e.Cost) || *e.Cost < 0) {
+		return fmt.Errorf("%w: edge %q cost must be non-negative", ErrInvalidMap, e.ID)
+	}
+	return nil
+}
+
+// polygonArea 多边形面积（鞋带公式）
+func polygonArea(polygon []Point) float64 {
+	area := 0.0
+	for i := range polygon {
+		j := (i + 1) % len(polygon)
+		area += polygon[i].X*polygon[j].Y - polygon[j].X*polygon[i].Y
+	}
+	return math.Abs(area) / 2
+}
+
+func finite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
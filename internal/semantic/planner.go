@@ -0,0 +1,278 @@
+package semantic
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnknownNode 规划起点或终点不是路网节点或兴趣点
+var ErrUnknownNode = errors.New("unknown waypoint or poi")
+
+// ErrNoPath 起点与终点之间没有可通行的路径
+var ErrNoPath = errors.New("no path found")
+
+// DefaultSpeed 未指定速度时估算耗时使用的行驶速度(米/秒)
+const DefaultSpeed = 1.0
+
+// PlanOptions 路径规划参数
+type PlanOptions struct {
+	From       string  // 起点，路网节点ID或兴趣点ID（取最近的路网节点）
+	To         string  // 终点，路网节点ID或兴趣点ID（取最近的路网节点）
+	DeviceType string  // 设备类型，为空时不按设备类型过滤边与区域
+	Speed      float64 // 行驶速度(米/秒)，不大于 0 时取 DefaultSpeed，途经限速区时取较小值
+}
+
+// Plan 路径规划结果
+type Plan struct {
+	Waypoints []*Waypoint // 依次经过的路网节点，含起点与终点
+	Edges     []*Edge     // 依次经过的边
+	Cost      float64     // 总代价
+	Distance  float64     // 总里程(米)
+	Duration  float64     // 预计耗时(秒)
+}
+
+// Plan 在路网上规划从起点到终点的代价最小路径
+// 边代价全部不小于欧氏距离时使用 A*（欧氏距离启发），否则退化为 Dijkstra；
+// 经过禁行区、该设备类型禁止进入的区域，或与单行区通行朝向相差超过 90° 的边不可通行
+func (m *Map) Plan(opts PlanOptions) (*Plan, error) {
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = DefaultSpeed
+	}
+
+	nodes := make(map[string]*Waypoint, len(m.Waypoints))
+	for _, w := range m.Waypoints {
+		nodes[w.ID] = w
+	}
+	start, err := m.resolveNode(nodes, opts.From)
+	if err != nil {
+		return nil, err
+	}
+	goal, err := m.resolveNode(nodes, opts.To)
+	if err != nil {
+		return nil, err
+	}
+
+	// 过滤出可通行的边，同时判断欧氏距离启发是否可采纳
+	adjacency := make(map[string][]*Edge)
+	admissible := true
+	for _, e := range m.Edges {
+		from, to := nodes[e.From], nodes[e.To]
+		if !m.passable(e, from, to, opts.DeviceType) {
+			continue
+		}
+		adjacency[e.From] = append(adjacency[e.From], e)
+		if e.Cost != nil && *e.Cost < distance(from, to) {
+			admissible = false
+		}
+	}
+	heuristic := func(w *Waypoint) float64 {
+		if !admissible {
+			return 0
+		}
+		return distance(w, goal)
+	}
+
+	cost := map[string]float64{start.ID: 0}
+	via := make(map[string]*Edge)
+	closed := make(map[string]bool)
+	open := &planQueue{{node: start.ID, priority: heuristic(start)}}
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*planItem).node
+		if current == goal.ID {
+			return m.buildPlan(nodes, via, start, goal, cost[goal.ID], speed), nil
+		}
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+
+		for _, e := range adjacency[current] {
+			if closed[e.To] {
+				continue
+			}
+			next := cost[current] + edgeCost(e, nodes[e.From], nodes[e.To])
+			if known, ok := cost[e.To]; ok && known <= next {
+				continue
+			}
+			cost[e.To] = next
+			via[e.To] = e
+			heap.Push(open, &planItem{node: e.To, priority: next + heuristic(nodes[e.To])})
+		}
+	}
+	return nil, fmt.Errorf("%w: %s -> %s", ErrNoPath, opts.From, opts.To)
+}
+
+// resolveNode 路网节点ID直接使用，兴趣点ID取距离最近的路网节点
+func (m *Map) resolveNode(nodes map[string]*Waypoint, id string) (*Waypoint, error) {
+	if w, ok := nodes[id]; ok {
+		return w, nil
+	}
+	for _, p := range m.POIs {
+		if p.ID != id {
+			continue
+		}
+		var nearest *Waypoint
+		for _, w := range m.Waypoints {
+			if nearest == nil || math.Hypot(w.X-p.Pose.X, w.Y-p.Pose.Y) < math.Hypot(nearest.X-p.Pose.X, nearest.Y-p.Pose.Y) {
+				nearest = w
+			}
+		}
+		if nearest != nil {
+			return nearest, nil
+		}
+		break
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownNode, id)
+}
+
+// passable 判断边对指定设备类型是否可通行
+func (m *Map) passable(e *Edge, from, to *Waypoint, deviceType string) bool {
+	if deviceType != "" && len(e.DeviceTypes) > 0 && !contains(e.DeviceTypes, deviceType) {
+		return false
+	}
+
+	a, b := Point{X: from.X, Y: from.Y}, Point{X: to.X, Y: to.Y}
+	for _, r := range m.Regions {
+		props := r.Properties
+		denied := deviceType != "" && contains(props.DeniedDeviceTypes, deviceType)
+		if !props.NoGo && !denied && props.OneWay == nil {
+			continue
+		}
+		if !r.Touches(a, b) {
+			continue
+		}
+		if props.NoGo || denied {
+			return false
+		}
+		heading := math.Atan2(b.Y-a.Y, b.X-a.X)
+		if math.Cos(heading-*props.OneWay) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// buildPlan 从终点沿前驱边回溯出完整路径，并按途经限速区估算耗时
+func (m *Map) buildPlan(nodes map[string]*Waypoint, via map[string]*Edge, start, goal *Waypoint, cost, speed float64) *Plan {
+	var edges []*Edge
+	for id := goal.ID; id != start.ID; id = via[id].From {
+		edges = append([]*Edge{via[id]}, edges...)
+	}
+
+	plan := &Plan{Waypoints: []*Waypoint{start}, Edges: edges, Cost: cost}
+	for _, e := range edges {
+		from, to := nodes[e.From], nodes[e.To]
+		length := distance(from, to)
+		plan.Waypoints = append(plan.Waypoints, to)
+		plan.Distance += length
+		plan.Duration += length / m.edgeSpeed(from, to, speed)
+	}
+	return plan
+}
+
+// edgeSpeed 边上的行驶速度，途经多个限速区时取最小值
+func (m *Map) edgeSpeed(from, to *Waypoint, speed float64) float64 {
+	a, b := Point{X: from.X, Y: from.Y}, Point{X: to.X, Y: to.Y}
+	for _, r := range m.Regions {
+		if limit := r.Properties.SpeedLimit; limit != nil && *limit < speed && r.Touches(a, b) {
+			speed = *limit
+		}
+	}
+	return speed
+}
+
+// Contains 判断点是否位于区域内（射线法，边界上的点视为在内）
+func (r *Region) Contains(p Point) bool {
+	inside := false
+	n := len(r.Polygon)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := r.Polygon[i], r.Polygon[j]
+		if onSegment(p, a, b) {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Touches 判断线段是否进入或穿过区域
+func (r *Region) Touches(a, b Point) bool {
+	if r.Contains(a) || r.Contains(b) {
+		return true
+	}
+	n := len(r.Polygon)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		if segmentsIntersect(a, b, r.Polygon[i], r.Polygon[j]) {
+			return true
+		}
+	}
+	return false
+}
+
+// onSegment 判断点是否落在线段上
+func onSegment(p, a, b Point) bool {
+	if math.Abs(cross(a, b, p)) > 1e-9 {
+		return false
+	}
+	return p.X >= math.Min(a.X, b.X)-1e-9 && p.X <= math.Max(a.X, b.X)+1e-9 &&
+		p.Y >= math.Min(a.Y, b.Y)-1e-9 && p.Y <= math.Max(a.Y, b.Y)+1e-9
+}
+
+// segmentsIntersect 判断线段 ab 与 cd 是否相交（含端点接触与共线重叠）
+func segmentsIntersect(a, b, c, d Point) bool {
+	d1, d2 := cross(c, d, a), cross(c, d, b)
+	d3, d4 := cross(a, b, c), cross(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return onSegment(a, c, d) || onSegment(b, c, d) || onSegment(c, a, b) || onSegment(d, a, b)
+}
+
+// cross 向量 ab 与 ap 的叉积
+func cross(a, b, p Point) float64 {
+	return (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+}
+
+func distance(a, b *Waypoint) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+func edgeCost(e *Edge, from, to *Waypoint) float64 {
+	if e.Cost != nil {
+		return *e.Cost
+	}
+	return distance(from, to)
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// planItem / planQueue 按优先级出队的开放列表
+type planItem struct {
+	node     string
+	priority float64
+}
+
+type planQueue []*planItem
+
+func (q planQueue) Len() int            { return len(q) }
+func (q planQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q planQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *planQueue) Push(x interface{}) { *q = append(*q, x.(*planItem)) }
+func (q *planQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
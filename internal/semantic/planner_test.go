@@ -0,0 +1,94 @@
+package semantic
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// 网格路网：a(0,0) -> b(10,0) -> d(10,10)，以及绕行 a -> c(0,10) -> d
+const planMap = `{
+	"pois": [{"id": "dock", "type": "dock", "pose": {"x": 9, "y": 11, "yaw": 0}}],
+	"waypoints": [
+		{"id": "a", "x": 0, "y": 0}, {"id": "b", "x": 10, "y": 0},
+		{"id": "c", "x": 0, "y": 10}, {"id": "d", "x": 10, "y": 10}
+	],
+	"edges": [
+		{"id": "a-b", "from": "a", "to": "b"}, {"id": "b-d", "from": "b", "to": "d"},
+		{"id": "a-c", "from": "a", "to": "c", "cost": 11}, {"id": "c-d", "from": "c", "to": "d", "cost": 11},
+		{"id": "d-a", "from": "d", "to": "a", "deviceTypes": ["robot_wheel"]}
+	]
+}`
+
+func mustParse(t *testing.T, info string) *Map {
+	t.Helper()
+	m, err := Parse(info)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return m
+}
+
+func planIDs(p *Plan) []string {
+	ids := make([]string, 0, len(p.Waypoints))
+	for _, w := range p.Waypoints {
+		ids = append(ids, w.ID)
+	}
+	return ids
+}
+
+func TestPlan_ShortestPath(t *testing.T) {
+	m := mustParse(t, planMap)
+
+	plan, err := m.Plan(PlanOptions{From: "a", To: "dock", Speed: 2})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if got := planIDs(plan); len(got) != 3 || got[1] != "b" || got[2] != "d" {
+		t.Fatalf("Expected a -> b -> d, got %v", got)
+	}
+	if plan.Cost != 20 || plan.Distance != 20 || plan.Duration != 10 {
+		t.Fatalf("Unexpected totals: cost %v distance %v duration %v", plan.Cost, plan.Distance, plan.Duration)
+	}
+
+	// 边是有向的，b 无法回到 a
+	if _, err := m.Plan(PlanOptions{From: "b", To: "a", DeviceType: "robot_biped"}); !errors.Is(err, ErrNoPath) {
+		t.Fatalf("Expected ErrNoPath, got %v", err)
+	}
+	// 仅轮式机器人可走 d -> a
+	if _, err := m.Plan(PlanOptions{From: "b", To: "a", DeviceType: "robot_wheel"}); err != nil {
+		t.Fatalf("Expected wheel robot to use restricted edge, got %v", err)
+	}
+	if _, err := m.Plan(PlanOptions{From: "a", To: "nowhere"}); !errors.Is(err, ErrUnknownNode) {
+		t.Fatalf("Expected ErrUnknownNode, got %v", err)
+	}
+}
+
+func TestPlan_RegionRestrictions(t *testing.T) {
+	m := mustParse(t, planMap)
+	heading := math.Pi // 只允许向 -x 方向通行
+	if _, err := m.Put(KindRegion, "", []byte(`{"id": "slow", "polygon": [{"x": -1, "y": 9}, {"x": 11, "y": 9}, {"x": 11, "y": 11}, {"x": -1, "y": 11}], "properties": {"speedLimit": 0.5}}`)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	// 禁行区挡住 b-d 后改走 a -> c -> d，c-d 位于限速区
+	if _, err := m.Put(KindRegion, "", []byte(`{"id": "blocked", "polygon": [{"x": 9, "y": 4}, {"x": 11, "y": 4}, {"x": 11, "y": 6}, {"x": 9, "y": 6}], "properties": {"noGo": true}}`)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	plan, err := m.Plan(PlanOptions{From: "a", To: "d"})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if got := planIDs(plan); len(got) != 3 || got[1] != "c" {
+		t.Fatalf("Expected detour via c, got %v", got)
+	}
+	if plan.Cost != 22 || plan.Duration != 10/0.5+10/0.5 {
+		t.Fatalf("Unexpected totals: cost %v duration %v", plan.Cost, plan.Duration)
+	}
+
+	// 单行区只允许向 -x 通行，c -> d 为逆行
+	m.Regions[0].Properties.OneWay = &heading
+	if _, err := m.Plan(PlanOptions{From: "a", To: "d"}); !errors.Is(err, ErrNoPath) {
+		t.Fatalf("Expected ErrNoPath against one-way region, got %v", err)
+	}
+}
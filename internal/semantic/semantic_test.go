@@ -0,0 +1,119 @@
+package semantic
+
+import (
+	"errors"
+	"testing"
+)
+
+const testMap = `{
+	"pois": [{"id": "charger-1", "name": "充电桩", "type": "charging", "pose": {"x": 1, "y": 2, "yaw": 0}}],
+	"regions": [{"id": "lobby", "name": "大厅", "polygon": [{"x": 0, "y": 0}, {"x": 4, "y": 0}, {"x": 4, "y": 4}], "properties": {"speedLimit": 0.5}}],
+	"waypoints": [{"id": "a", "x": 0, "y": 0}, {"id": "b", "x": 3, "y": 4}],
+	"edges": [{"id": "a-b", "from": "a", "to": "b"}]
+}`
+
+func TestParse_Valid(t *testing.T) {
+	m, err := Parse(testMap)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(m.POIs) != 1 || len(m.Regions) != 1 || len(m.Waypoints) != 2 || len(m.Edges) != 1 {
+		t.Fatalf("Unexpected map: %+v", m)
+	}
+
+	again, err := Parse(m.String())
+	if err != nil {
+		t.Fatalf("Parse of serialized map failed: %v", err)
+	}
+	if again.String() != m.String() {
+		t.Fatalf("Expected stable serialization, got %s", again.String())
+	}
+
+	empty, err := Parse("")
+	if err != nil || empty.String() != `{"pois":[],"regions":[],"waypoints":[],"edges":[]}` {
+		t.Fatalf("Expected empty map, got %v, %v", empty, err)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"free text":         "一楼大厅",
+		"unknown field":     `{"pois": [], "extra": 1}`,
+		"unknown poi type":  `{"pois": [{"id": "p", "type": "sofa", "pose": {"x": 0, "y": 0, "yaw": 0}}]}`,
+		"duplicate id":      `{"waypoints": [{"id": "a", "x": 0, "y": 0}, {"id": "a", "x": 1, "y": 1}]}`,
+		"degenerate region": `{"regions": [{"id": "r", "polygon": [{"x": 0, "y": 0}, {"x": 1, "y": 1}, {"x": 2, "y": 2}]}]}`,
+		"bad speed limit":   `{"regions": [{"id": "r", "polygon": [{"x": 0, "y": 0}, {"x": 1, "y": 0}, {"x": 0, "y": 1}], "properties": {"speedLimit": 0}}]}`,
+		"dangling edge":     `{"waypoints": [{"id": "a", "x": 0, "y": 0}], "edges": [{"id": "e", "from": "a", "to": "b"}]}`,
+		"negative cost":     `{"waypoints": [{"id": "a", "x": 0, "y": 0}, {"id": "b", "x": 1, "y": 0}], "edges": [{"id": "e", "from": "a", "to": "b", "cost": -1}]}`,
+		"null element":      `{"pois": [null]}`,
+	}
+	for name, info := range cases {
+		if _, err := Parse(info); !errors.Is(err, ErrInvalidMap) {
+			t.Errorf("%s: expected ErrInvalidMap, got %v", name, err)
+		}
+	}
+}
+
+func TestMap_PutAndRemove(t *testing.T) {
+	m, err := Parse(testMap)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if _, err := m.Put(KindPOI, "", []byte(`{"id": "charger-1", "type": "dock", "pose": {"x": 0, "y": 0, "yaw": 0}}`)); !errors.Is(err, ErrElementExists) {
+		t.Fatalf("Expected ErrElementExists, got %v", err)
+	}
+	if _, err := m.Put(KindEdge, "", []byte(`{"id": "b-c", "from": "b", "to": "c"}`)); !errors.Is(err, ErrInvalidMap) {
+		t.Fatalf("Expected dangling edge to be rejected, got %v", err)
+	}
+	if len(m.Edges) != 1 {
+		t.Fatalf("Expected rejected put to leave map unchanged, got %d edges", len(m.Edges))
+	}
+
+	element, err := m.Put(KindPOI, "charger-1", []byte(`{"id": "ignored", "name": "新充电桩", "type": "charging", "pose": {"x": 5, "y": 5, "yaw": 1.57}}`))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if element.ElementID() != "charger-1" || m.POIs[0].Pose.X != 5 {
+		t.Fatalf("Expected poi to be replaced in place, got %+v", m.POIs[0])
+	}
+	if _, err := m.Put(KindPOI, "missing", []byte(`{"type": "dock", "pose": {"x": 0, "y": 0, "yaw": 0}}`)); !errors.Is(err, ErrElementNotFound) {
+		t.Fatalf("Expected ErrElementNotFound, got %v", err)
+	}
+
+	if err := m.Remove(KindWaypoint, "a"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if len(m.Waypoints) != 1 || len(m.Edges) != 0 {
+		t.Fatalf("Expected edges of removed waypoint to be removed, got %+v", m.Edges)
+	}
+	if err := m.Remove(KindRegion, "missing"); !errors.Is(err, ErrElementNotFound) {
+		t.Fatalf("Expected ErrElementNotFound, got %v", err)
+	}
+	if _, err := ParseKind("sofas"); !errors.Is(err, ErrUnknownKind) {
+		t.Fatalf("Expected ErrUnknownKind, got %v", err)
+	}
+}
+
+func TestMap_Transform(t *testing.T) {
+	m, err := Parse(testMap)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	m.Transform(func(p Pose) Pose {
+		return Pose{X: p.X + 10, Y: p.Y - 1, Yaw: p.Yaw + 1}
+	})
+
+	if p := m.POIs[0].Pose; p.X != 11 || p.Y != 1 || p.Yaw != 1 {
+		t.Errorf("Unexpected poi pose %+v", p)
+	}
+	if r := m.Regions[0].Polygon[2]; r.X != 14 || r.Y != 3 {
+		t.Errorf("Unexpected region vertex %+v", r)
+	}
+	if w := m.Waypoints[1]; w.X != 13 || w.Y != 3 {
+		t.Errorf("Unexpected waypoint %+v", w)
+	}
+	if err := m.Validate(); err != nil {
+		t.Errorf("Transformed map invalid: %v", err)
+	}
+}
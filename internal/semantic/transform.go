@@ -0,0 +1,35 @@
+package semantic
+
+// Transform 用 f 变换全部元素的坐标，用于在不同地图坐标系之间换算
+func (m *Map) Transform(f func(Pose) Pose) {
+	for _, p := range m.POIs {
+		TransformElement(p, f)
+	}
+	for _, r := range m.Regions {
+		TransformElement(r, f)
+	}
+	for _, w := range m.Waypoints {
+		TransformElement(w, f)
+	}
+}
+
+// TransformElement 用 f 变换单个元素的坐标，路网边没有坐标不做处理。
+// 区域的单行朝向按首个顶点处的朝向变换
+func TransformElement(e Element, f func(Pose) Pose) {
+	switch v := e.(type) {
+	case *POI:
+		v.Pose = f(v.Pose)
+	case *Waypoint:
+		p := f(Pose{X: v.X, Y: v.Y})
+		v.X, v.Y = p.X, p.Y
+	case *Region:
+		if v.Properties.OneWay != nil && len(v.Polygon) > 0 {
+			heading := f(Pose{X: v.Polygon[0].X, Y: v.Polygon[0].Y, Yaw: *v.Properties.OneWay}).Yaw
+			v.Properties.OneWay = &heading
+		}
+		for i, pt := range v.Polygon {
+			p := f(Pose{X: pt.X, Y: pt.Y})
+			v.Polygon[i] = Point{X: p.X, Y: p.Y}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	dao "robot_scheduler/internal/dao/interfaces"
 	"robot_scheduler/internal/logger"
 	"robot_scheduler/internal/model/dto"
@@ -13,12 +14,16 @@ import (
 
 // DeviceService 设备服务
 type DeviceService struct {
-	deviceDAO dao.DeviceDAO
+	deviceDAO   dao.DeviceDAO
+	modelDAO    dao.DeviceModelDAO
+	locationDAO dao.LocationDAO
 }
 
-func NewDeviceService(deviceDAO dao.DeviceDAO) *DeviceService {
+func NewDeviceService(deviceDAO dao.DeviceDAO, modelDAO dao.DeviceModelDAO, locationDAO dao.LocationDAO) *DeviceService {
 	return &DeviceService{
-		deviceDAO: deviceDAO,
+		deviceDAO:   deviceDAO,
+		modelDAO:    modelDAO,
+		locationDAO: locationDAO,
 	}
 }
 
@@ -26,6 +31,17 @@ func NewDeviceService(deviceDAO dao.DeviceDAO) *DeviceService {
 func (s *DeviceService) CreateDevice(ctx context.Context, req *dto.DeviceCreateRequest) (*dto.DeviceResponse, error) {
 	logger.Info("creating device in service", zap.String("type", string(req.Type)))
 
+	// 按设备型号目录校验类型/厂商
+	catalog, err := loadDeviceCatalog(ctx, s.modelDAO)
+	if err != nil {
+		return nil, err
+	}
+	model, err := catalog.resolve(req.Company, req.Type, req.ModelID, "")
+	if err != nil {
+		logger.Warn("device rejected by model catalog", zap.Error(err))
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeviceModel, err)
+	}
+
 	// 创建设备实体
 	device := &entity.Device{
 		Type:      req.Type,
@@ -37,6 +53,13 @@ func (s *DeviceService) CreateDevice(ctx context.Context, req *dto.DeviceCreateR
 		Status:    &[]entity.DeviceStatus{entity.DeviceStatusOffline}[0],
 		ExtraInfo: req.ExtraInfo,
 	}
+	applyDeviceModel(device, model)
+	if device.Port == 0 {
+		return nil, errors.New("设备端口不能为空")
+	}
+	if device.LocationID, err = resolveLocationID(ctx, s.locationDAO, req.LocationID); err != nil {
+		return nil, err
+	}
 
 	// 保存到数据库
 	if err := s.deviceDAO.Create(ctx, device); err != nil {
@@ -89,6 +112,41 @@ func (s *DeviceService) UpdateDevice(ctx context.Context, id uint, req *dto.Devi
 	if req.ExtraInfo != nil {
 		device.ExtraInfo = req.ExtraInfo
 	}
+	if req.LocationID != nil {
+		if device.LocationID, err = resolveLocationID(ctx, s.locationDAO, req.LocationID); err != nil {
+			return err
+		}
+	}
+
+	// 型号、类型或厂商变化时重新按目录校验
+	if req.ModelID != nil || req.Type != nil || req.Company != nil {
+		catalog, err := loadDeviceCatalog(ctx, s.modelDAO)
+		if err != nil {
+			return err
+		}
+		company, deviceType := device.Company, device.Type
+		if req.ModelID != nil {
+			// 指定新型号时，只校验请求中显式给出的类型/厂商
+			company, deviceType = "", ""
+			if req.Company != nil {
+				company = *req.Company
+			}
+			if req.Type != nil {
+				deviceType = *req.Type
+			}
+		}
+		model, err := catalog.resolve(company, deviceType, req.ModelID, "")
+		if err != nil {
+			logger.Warn("device update rejected by model catalog", zap.Error(err), zap.Uint("id", id))
+			return fmt.Errorf("%w: %v", ErrInvalidDeviceModel, err)
+		}
+		if model == nil {
+			// 类型/厂商对应多个型号时无法确定新型号，不能静默解除原有的型号绑定
+			logger.Warn("device update matches multiple models", zap.Uint("id", id))
+			return fmt.Errorf("%w: 厂商 %s 类型 %s 对应多个已启用型号，请指定型号", ErrInvalidDeviceModel, company, deviceType)
+		}
+		applyDeviceModel(device, model)
+	}
 
 	// 保存更新
 	if err := s.deviceDAO.Update(ctx, device); err != nil {
@@ -119,8 +177,8 @@ func (s *DeviceService) GetDeviceByID(ctx context.Context, id uint) (*dto.Device
 	return dto.NewDeviceResponseFromEntity(device), nil
 }
 
-// ListDevices 分页获取设备列表
-func (s *DeviceService) ListDevices(ctx context.Context, req dto.PageRequest) (*dto.DeviceListResponse, error) {
+// ListDevices 分页获取设备列表，可按位置节点过滤
+func (s *DeviceService) ListDevices(ctx context.Context, req dto.DeviceListRequest) (*dto.DeviceListResponse, error) {
 	logger.Debug("listing devices in service with pagination", zap.Int("page", req.Page), zap.Int("pageSize", req.PageSize))
 
 	if req.Page <= 0 {
@@ -132,7 +190,11 @@ func (s *DeviceService) ListDevices(ctx context.Context, req dto.PageRequest) (*
 
 	offset := (req.Page - 1) * req.PageSize
 
-	devices, total, err := s.deviceDAO.FindPage(ctx, offset, req.PageSize)
+	devices, total, err := s.deviceDAO.FindPage(ctx, dao.DeviceFilter{
+		LocationID:               req.LocationID,
+		LoadedSemanticMapID:      req.LoadedSemanticMapID,
+		LoadedSemanticMapVersion: req.LoadedSemanticMapVersion,
+	}, offset, req.PageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+)
+
+// DeviceAlarmService 设备告警服务
+type DeviceAlarmService struct {
+	deviceDAO dao.DeviceDAO
+	alarmDAO  dao.DeviceAlarmDAO
+}
+
+func NewDeviceAlarmService(deviceDAO dao.DeviceDAO, alarmDAO dao.DeviceAlarmDAO) *DeviceAlarmService {
+	return &DeviceAlarmService{
+		deviceDAO: deviceDAO,
+		alarmDAO:  alarmDAO,
+	}
+}
+
+// ReportAlarms 处理设备上报的告警并同步设备状态
+func (s *DeviceAlarmService) ReportAlarms(ctx context.Context, deviceID uint, reports []dto.DeviceAlarmReport) error {
+	logger.Info("handling device alarm report in service", zap.Uint("deviceID", deviceID), zap.Int("count", len(reports)))
+
+	device, err := s.deviceDAO.FindByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		return errors.New("device not found")
+	}
+
+	if err := s.SaveReports(ctx, deviceID, reports); err != nil {
+		return err
+	}
+
+	return s.syncDeviceStatus(ctx, device)
+}
+
+// SaveReports 保存告警记录：同一错误码未解除时只刷新原告警，cleared 为 true 时解除该错误码的告警
+// 不修改设备状态，调用方需自行调用 ApplyDeviceStatus
+func (s *DeviceAlarmService) SaveReports(ctx context.Context, deviceID uint, reports []dto.DeviceAlarmReport) error {
+	now := time.Now()
+	for i := range reports {
+		report := &reports[i]
+
+		active, err := s.alarmDAO.FindActiveByDeviceAndCode(ctx, deviceID, report.Code)
+		if err != nil {
+			return err
+		}
+
+		if report.Cleared {
+			if active == nil {
+				continue
+			}
+			active.ClearedAt = &now
+			if err := s.alarmDAO.Update(ctx, active); err != nil {
+				logger.Error("failed to clear device alarm in service", zap.Error(err), zap.Uint("id", active.ID))
+				return err
+			}
+			continue
+		}
+
+		alarm := active
+		if alarm == nil {
+			alarm = &entity.DeviceAlarm{
+				DeviceID: deviceID,
+				Code:     report.Code,
+				RaisedAt: now,
+			}
+		}
+		alarm.Severity = report.Severity
+		alarm.Message = report.Message
+		if report.Pose != nil {
+			alarm.PoseX = &report.Pose.X
+			alarm.PoseY = &report.Pose.Y
+			alarm.PoseYaw = &report.Pose.Yaw
+		}
+		if report.ExtraInfo != nil {
+			alarm.ExtraInfo = report.ExtraInfo
+		}
+
+		if alarm.ID == 0 {
+			err = s.alarmDAO.Create(ctx, alarm)
+		} else {
+			err = s.alarmDAO.Update(ctx, alarm)
+		}
+		if err != nil {
+			logger.Error("failed to save device alarm in service", zap.Error(err), zap.Uint("deviceID", deviceID), zap.String("code", report.Code))
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyDeviceStatus 存在未解除的严重告警时将设备状态置为 error（只修改内存对象）
+func (s *DeviceAlarmService) ApplyDeviceStatus(ctx context.Context, device *entity.Device) error {
+	count, err := s.alarmDAO.CountActiveBySeverity(ctx, device.ID, entity.AlarmSeverityCritical)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		device.Status = &[]entity.DeviceStatus{entity.DeviceStatusError}[0]
+	}
+	return nil
+}
+
+// GetAlarm 获取告警详情
+func (s *DeviceAlarmService) GetAlarm(ctx context.Context, id uint) (*dto.DeviceAlarmResponse, error) {
+	alarm, err := s.alarmDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alarm == nil {
+		return nil, errors.New("device alarm not found")
+	}
+	return dto.NewDeviceAlarmResponseFromEntity(alarm), nil
+}
+
+// ListAlarms 按条件分页查询告警
+func (s *DeviceAlarmService) ListAlarms(ctx context.Context, req dto.DeviceAlarmListRequest) (*dto.DeviceAlarmListResponse, error) {
+	logger.Debug("listing device alarms in service")
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+
+	filter := dao.DeviceAlarmFilter{
+		DeviceID:     req.DeviceID,
+		Severity:     req.Severity,
+		Code:         req.Code,
+		Active:       req.Active,
+		Acknowledged: req.Acknowledged,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+	}
+
+	alarms, total, err := s.alarmDAO.FindPage(ctx, filter, offset, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := 0
+	if req.PageSize > 0 {
+		pages = int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	}
+
+	page := dto.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Pages:    pages,
+	}
+
+	return dto.NewDeviceAlarmListResponseFromEntities(alarms, page), nil
+}
+
+// AcknowledgeAlarm 确认告警
+func (s *DeviceAlarmService) AcknowledgeAlarm(ctx context.Context, id uint, userName string) (*dto.DeviceAlarmResponse, error) {
+	logger.Info("acknowledging device alarm in service", zap.Uint("id", id), zap.String("userName", userName))
+
+	alarm, err := s.alarmDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alarm == nil {
+		return nil, errors.New("device alarm not found")
+	}
+	if alarm.AcknowledgedAt != nil {
+		return nil, errors.New("告警已确认")
+	}
+
+	now := time.Now()
+	alarm.AcknowledgedBy = &userName
+	alarm.AcknowledgedAt = &now
+	if err := s.alarmDAO.Update(ctx, alarm); err != nil {
+		logger.Error("failed to acknowledge device alarm in service", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	return dto.NewDeviceAlarmResponseFromEntity(alarm), nil
+}
+
+// ClearAlarm 手动解除告警，解除后若设备不再有严重告警则恢复在线状态
+func (s *DeviceAlarmService) ClearAlarm(ctx context.Context, id uint, userName string) (*dto.DeviceAlarmResponse, error) {
+	logger.Info("clearing device alarm in service", zap.Uint("id", id), zap.String("userName", userName))
+
+	alarm, err := s.alarmDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alarm == nil {
+		return nil, errors.New("device alarm not found")
+	}
+	if alarm.ClearedAt != nil {
+		return nil, errors.New("告警已解除")
+	}
+
+	now := time.Now()
+	alarm.ClearedAt = &now
+	if alarm.AcknowledgedAt == nil {
+		alarm.AcknowledgedBy = &userName
+		alarm.AcknowledgedAt = &now
+	}
+	if err := s.alarmDAO.Update(ctx, alarm); err != nil {
+		logger.Error("failed to clear device alarm in service", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	device, err := s.deviceDAO.FindByID(ctx, alarm.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device != nil {
+		if err := s.syncDeviceStatus(ctx, device); err != nil {
+			return nil, err
+		}
+	}
+
+	return dto.NewDeviceAlarmResponseFromEntity(alarm), nil
+}
+
+// syncDeviceStatus 根据未解除的严重告警更新设备状态：有则置为 error，全部解除后由 error 恢复为 online
+func (s *DeviceAlarmService) syncDeviceStatus(ctx context.Context, device *entity.Device) error {
+	count, err := s.alarmDAO.CountActiveBySeverity(ctx, device.ID, entity.AlarmSeverityCritical)
+	if err != nil {
+		return err
+	}
+
+	isError := device.Status != nil && *device.Status == entity.DeviceStatusError
+	switch {
+	case count > 0 && !isError:
+		logger.Info("device switched to error by critical alarm", zap.Uint("deviceID", device.ID))
+		device.Status = &[]entity.DeviceStatus{entity.DeviceStatusError}[0]
+	case count == 0 && isError:
+		logger.Info("device recovered from error, no critical alarm active", zap.Uint("deviceID", device.ID))
+		device.Status = &[]entity.DeviceStatus{entity.DeviceStatusOnline}[0]
+	default:
+		return nil
+	}
+
+	return s.deviceDAO.Update(ctx, device)
+}
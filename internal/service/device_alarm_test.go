@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestDeviceAlarmService_ReportAlarms(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceDAO := mocks.NewMockDeviceDAO(ctrl)
+	mockAlarmDAO := mocks.NewMockDeviceAlarmDAO(ctrl)
+	service := NewDeviceAlarmService(mockDeviceDAO, mockAlarmDAO)
+	ctx := context.Background()
+
+	online := entity.DeviceStatusOnline
+	device := &entity.Device{Model: gorm.Model{ID: 1}, Status: &online}
+	existing := &entity.DeviceAlarm{Model: gorm.Model{ID: 10}, DeviceID: 1, Code: "E02", Severity: entity.AlarmSeverityWarning, RaisedAt: time.Now().Add(-time.Minute)}
+	raisedAt := existing.RaisedAt
+
+	mockDeviceDAO.EXPECT().FindByID(ctx, uint(1)).Return(device, nil)
+	// 新错误码创建告警
+	mockAlarmDAO.EXPECT().FindActiveByDeviceAndCode(ctx, uint(1), "E01").Return(nil, nil)
+	mockAlarmDAO.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, alarm *entity.DeviceAlarm) error {
+		if alarm.Severity != entity.AlarmSeverityCritical || alarm.PoseX == nil || *alarm.PoseX != 1.5 {
+			t.Errorf("Unexpected new alarm: %+v", alarm)
+		}
+		return nil
+	})
+	// 未解除的同一错误码只刷新原告警
+	mockAlarmDAO.EXPECT().FindActiveByDeviceAndCode(ctx, uint(1), "E02").Return(existing, nil)
+	mockAlarmDAO.EXPECT().Update(ctx, existing).DoAndReturn(func(_ context.Context, alarm *entity.DeviceAlarm) error {
+		if alarm.Message != "refreshed" || !alarm.RaisedAt.Equal(raisedAt) || alarm.ClearedAt != nil {
+			t.Errorf("Expected existing alarm to be refreshed, got %+v", alarm)
+		}
+		return nil
+	})
+	// 没有未解除告警的恢复上报直接忽略
+	mockAlarmDAO.EXPECT().FindActiveByDeviceAndCode(ctx, uint(1), "E03").Return(nil, nil)
+	// 存在严重告警时设备切换为 error
+	mockAlarmDAO.EXPECT().CountActiveBySeverity(ctx, uint(1), entity.AlarmSeverityCritical).Return(int64(1), nil)
+	mockDeviceDAO.EXPECT().Update(ctx, device).Return(nil)
+
+	err := service.ReportAlarms(ctx, 1, []dto.DeviceAlarmReport{
+		{Code: "E01", Severity: entity.AlarmSeverityCritical, Message: "motor fault", Pose: &dto.DevicePose{X: 1.5, Y: 2, Yaw: 0.1}},
+		{Code: "E02", Severity: entity.AlarmSeverityWarning, Message: "refreshed"},
+		{Code: "E03", Severity: entity.AlarmSeverityInfo, Cleared: true},
+	})
+	if err != nil {
+		t.Fatalf("ReportAlarms failed: %v", err)
+	}
+	if device.Status == nil || *device.Status != entity.DeviceStatusError {
+		t.Errorf("Expected device status error, got %v", device.Status)
+	}
+}
+
+func TestDeviceAlarmService_AcknowledgeAndClear(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceDAO := mocks.NewMockDeviceDAO(ctrl)
+	mockAlarmDAO := mocks.NewMockDeviceAlarmDAO(ctrl)
+	service := NewDeviceAlarmService(mockDeviceDAO, mockAlarmDAO)
+	ctx := context.Background()
+
+	alarm := &entity.DeviceAlarm{Model: gorm.Model{ID: 10}, DeviceID: 1, Code: "E01", Severity: entity.AlarmSeverityCritical, RaisedAt: time.Now()}
+
+	mockAlarmDAO.EXPECT().FindByID(ctx, uint(10)).Return(alarm, nil).Times(2)
+	mockAlarmDAO.EXPECT().Update(ctx, alarm).Return(nil)
+	resp, err := service.AcknowledgeAlarm(ctx, 10, "alice")
+	if err != nil {
+		t.Fatalf("AcknowledgeAlarm failed: %v", err)
+	}
+	if resp.AcknowledgedBy == nil || *resp.AcknowledgedBy != "alice" || resp.AcknowledgedAt == nil {
+		t.Errorf("Expected alarm acknowledged by alice, got %+v", resp)
+	}
+	// 重复确认拒绝
+	if _, err := service.AcknowledgeAlarm(ctx, 10, "bob"); err == nil {
+		t.Error("Expected error acknowledging twice")
+	}
+
+	// 解除最后一个严重告警后设备由 error 恢复为 online
+	errorStatus := entity.DeviceStatusError
+	device := &entity.Device{Model: gorm.Model{ID: 1}, Status: &errorStatus}
+	mockAlarmDAO.EXPECT().FindByID(ctx, uint(10)).Return(alarm, nil).Times(2)
+	mockAlarmDAO.EXPECT().Update(ctx, alarm).Return(nil)
+	mockDeviceDAO.EXPECT().FindByID(ctx, uint(1)).Return(device, nil)
+	mockAlarmDAO.EXPECT().CountActiveBySeverity(ctx, uint(1), entity.AlarmSeverityCritical).Return(int64(0), nil)
+	mockDeviceDAO.EXPECT().Update(ctx, device).Return(nil)
+	resp, err = service.ClearAlarm(ctx, 10, "bob")
+	if err != nil {
+		t.Fatalf("ClearAlarm failed: %v", err)
+	}
+	if resp.ClearedAt == nil || *resp.AcknowledgedBy != "alice" {
+		t.Errorf("Expected cleared alarm keeping the first acknowledgement, got %+v", resp)
+	}
+	if *device.Status != entity.DeviceStatusOnline {
+		t.Errorf("Expected device status online, got %s", *device.Status)
+	}
+	// 重复解除拒绝
+	if _, err := service.ClearAlarm(ctx, 10, "bob"); err == nil {
+		t.Error("Expected error clearing twice")
+	}
+}
+
+func TestDeviceProvisionService_TelemetryKeepsErrorWithCriticalAlarm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceDAO := mocks.NewMockDeviceDAO(ctrl)
+	mockAlarmDAO := mocks.NewMockDeviceAlarmDAO(ctrl)
+	mockTelemetryDAO := mocks.NewMockDeviceTelemetryDAO(ctrl)
+	alarmService := NewDeviceAlarmService(mockDeviceDAO, mockAlarmDAO)
+	service := NewDeviceProvisionService(mockDeviceDAO, nil, nil, mockTelemetryDAO, alarmService, nil, nil)
+	ctx := context.Background()
+
+	device := &entity.Device{Model: gorm.Model{ID: 1}}
+	online := entity.DeviceStatusOnline
+
+	// 遥测携带的严重告警使设备保持 error，即使设备自报在线
+	mockDeviceDAO.EXPECT().FindByID(ctx, uint(1)).Return(device, nil)
+	mockTelemetryDAO.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	mockAlarmDAO.EXPECT().FindActiveByDeviceAndCode(ctx, uint(1), "E01").Return(nil, nil)
+	mockAlarmDAO.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	mockAlarmDAO.EXPECT().CountActiveBySeverity(ctx, uint(1), entity.AlarmSeverityCritical).Return(int64(1), nil)
+	mockDeviceDAO.EXPECT().Update(ctx, device).Return(nil)
+	err := service.ReportTelemetry(ctx, 1, &dto.DeviceTelemetryRequest{
+		Status: &online,
+		Alarms: []dto.DeviceAlarmReport{{Code: "E01", Severity: entity.AlarmSeverityCritical}},
+	})
+	if err != nil {
+		t.Fatalf("ReportTelemetry failed: %v", err)
+	}
+	if *device.Status != entity.DeviceStatusError || device.LastHeartbeatAt == nil {
+		t.Errorf("Expected error status with heartbeat, got %s, %v", *device.Status, device.LastHeartbeatAt)
+	}
+
+	// 严重告警解除后心跳恢复设备自报的状态
+	mockDeviceDAO.EXPECT().FindByID(ctx, uint(1)).Return(device, nil)
+	mockAlarmDAO.EXPECT().CountActiveBySeverity(ctx, uint(1), entity.AlarmSeverityCritical).Return(int64(0), nil)
+	mockDeviceDAO.EXPECT().Update(ctx, device).Return(nil)
+	if err := service.Heartbeat(ctx, 1, &dto.DeviceHeartbeatRequest{}); err != nil {
+		t.Fatalf("Heartbeat failed: %v", err)
+	}
+	if *device.Status != entity.DeviceStatusOnline {
+		t.Errorf("Expected online status after heartbeat, got %s", *device.Status)
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/discovery"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+)
+
+// DeviceDiscoveryService 局域网设备发现服务
+type DeviceDiscoveryService struct {
+	deviceDAO    dao.DeviceDAO
+	modelDAO     dao.DeviceModelDAO
+	scanner      *discovery.Scanner
+	registry     *discovery.Registry
+	scanInterval time.Duration
+	listen       bool
+}
+
+func NewDeviceDiscoveryService(deviceDAO dao.DeviceDAO, modelDAO dao.DeviceModelDAO, scanner *discovery.Scanner, registry *discovery.Registry, scanInterval time.Duration, listen bool) *DeviceDiscoveryService {
+	return &DeviceDiscoveryService{
+		deviceDAO:    deviceDAO,
+		modelDAO:     modelDAO,
+		scanner:      scanner,
+		registry:     registry,
+		scanInterval: scanInterval,
+		listen:       listen,
+	}
+}
+
+// Run 启动被动监听与周期扫描，直到 ctx 取消
+func (s *DeviceDiscoveryService) Run(ctx context.Context) {
+	logger.Info("starting device discovery", zap.Duration("scanInterval", s.scanInterval), zap.Bool("listen", s.listen))
+
+	if s.listen {
+		go func() {
+			err := s.scanner.Listen(ctx, func(a *discovery.Announcement) {
+				s.registry.Upsert(a)
+			})
+			if err != nil {
+				logger.Error("device discovery listener stopped", zap.Error(err))
+			}
+		}()
+	}
+
+	if s.scanInterval <= 0 {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(s.scanInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Scan(ctx); err != nil {
+			logger.Warn("periodic device discovery scan failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan 立即执行一次扫描，返回未注册的设备列表
+func (s *DeviceDiscoveryService) Scan(ctx context.Context) ([]*dto.DiscoveredDeviceResponse, error) {
+	logger.Info("scanning lan for devices in service")
+
+	announcements, err := s.scanner.Scan(ctx)
+	if err != nil {
+		logger.Error("failed to scan lan for devices", zap.Error(err))
+		return nil, err
+	}
+	for _, a := range announcements {
+		s.registry.Upsert(a)
+	}
+
+	logger.Info("lan device scan finished", zap.Int("responses", len(announcements)))
+	return s.ListDiscovered(ctx)
+}
+
+// ListDiscovered 列出已发现但尚未注册的设备
+func (s *DeviceDiscoveryService) ListDiscovered(ctx context.Context) ([]*dto.DiscoveredDeviceResponse, error) {
+	devices, err := s.deviceDAO.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	serials := make(map[string]bool, len(devices))
+	endpoints := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		if d.SerialNumber != nil && *d.SerialNumber != "" {
+			serials[string(d.Company)+"|"+*d.SerialNumber] = true
+		}
+		if d.IP != nil {
+			endpoints[fmt.Sprintf("%s:%d", *d.IP, d.Port)] = true
+		}
+	}
+
+	list := make([]*dto.DiscoveredDeviceResponse, 0)
+	for _, d := range s.registry.List() {
+		if d.SerialNumber != "" && serials[string(d.Company)+"|"+d.SerialNumber] {
+			continue
+		}
+		if endpoints[fmt.Sprintf("%s:%d", d.IP, d.Port)] {
+			continue
+		}
+		list = append(list, newDiscoveredDeviceResponse(d))
+	}
+	return list, nil
+}
+
+// Adopt 将已发现设备登记为正式设备
+func (s *DeviceDiscoveryService) Adopt(ctx context.Context, key string, req *dto.DeviceAdoptRequest) (*dto.DeviceResponse, error) {
+	logger.Info("adopting discovered device in service", zap.String("key", key))
+
+	found, ok := s.registry.Get(key)
+	if !ok {
+		logger.Warn("discovered device not found", zap.String("key", key))
+		return nil, errors.New("discovered device not found or expired")
+	}
+
+	if found.SerialNumber != "" {
+		existing, err := s.deviceDAO.FindBySerialNumber(ctx, found.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && existing.Company == found.Company {
+			logger.Warn("discovered device already registered", zap.String("key", key), zap.Uint("deviceID", existing.ID))
+			return nil, errors.New("device already registered")
+		}
+	}
+
+	ip := found.IP
+	device := &entity.Device{
+		Type:      found.Type,
+		Company:   found.Company,
+		IP:        &ip,
+		Port:      found.Port,
+		UserName:  req.UserName,
+		Password:  req.Password,
+		Status:    &[]entity.DeviceStatus{entity.DeviceStatusOffline}[0],
+		ExtraInfo: req.ExtraInfo,
+	}
+	if found.SerialNumber != "" {
+		serial := found.SerialNumber
+		device.SerialNumber = &serial
+	}
+	if len(found.Capabilities) > 0 {
+		raw, err := json.Marshal(found.Capabilities)
+		if err != nil {
+			return nil, err
+		}
+		capabilities := string(raw)
+		device.Capabilities = &capabilities
+	}
+	if req.Type != nil {
+		device.Type = *req.Type
+	}
+	if req.Port != nil {
+		device.Port = *req.Port
+	}
+	catalog, err := loadDeviceCatalog(ctx, s.modelDAO)
+	if err != nil {
+		return nil, err
+	}
+	model, err := catalog.resolve(device.Company, device.Type, req.ModelID, found.Model)
+	if err != nil {
+		logger.Warn("discovered device rejected by model catalog", zap.Error(err), zap.String("key", key))
+		return nil, err
+	}
+	applyDeviceModel(device, model)
+	if device.Type == "" {
+		return nil, errors.New("device type unknown, please specify type")
+	}
+
+	if err := s.deviceDAO.Create(ctx, device); err != nil {
+		logger.Error("failed to create adopted device", zap.Error(err), zap.String("key", key))
+		return nil, err
+	}
+
+	s.registry.Remove(key)
+
+	logger.Info("discovered device adopted successfully in service", zap.String("key", key), zap.Uint("deviceID", device.ID))
+	return dto.NewDeviceResponseFromEntity(device), nil
+}
+
+func newDiscoveredDeviceResponse(d *discovery.Discovered) *dto.DiscoveredDeviceResponse {
+	return &dto.DiscoveredDeviceResponse{
+		Key:          d.Key,
+		Company:      d.Company,
+		Type:         d.Type,
+		SerialNumber: d.SerialNumber,
+		Model:        d.Model,
+		IP:           d.IP,
+		Port:         d.Port,
+		Capabilities: d.Capabilities,
+		FirstSeen:    d.FirstSeen,
+		LastSeen:     d.LastSeen,
+	}
+}
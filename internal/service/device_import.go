@@ -0,0 +1,409 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DeviceFileFormatCSV CSV 格式
+	DeviceFileFormatCSV = "csv"
+	// DeviceFileFormatJSON JSON 格式
+	DeviceFileFormatJSON = "json"
+
+	// maxDeviceImportRows 单次导入的最大行数
+	maxDeviceImportRows = 1000
+	// capabilitySeparator CSV 中设备能力的分隔符
+	capabilitySeparator = ";"
+)
+
+// deviceExportColumns 导出 CSV 的列，导出文件可直接再次导入（id、status 列导入时忽略）
+var deviceExportColumns = []string{"id", "model", "type", "company", "serialNumber", "ip", "port", "status", "capabilities", "extraInfo"}
+
+// deviceImportIgnoredColumns 导入时忽略的列
+var deviceImportIgnoredColumns = map[string]bool{"id": true, "status": true}
+
+// ImportDevices 批量导入设备：先逐行校验，全部通过且非预览时在同一事务中写入
+func (s *DeviceService) ImportDevices(ctx context.Context, format string, r io.Reader, dryRun bool) (*dto.DeviceImportResponse, error) {
+	logger.Info("importing devices in service", zap.String("format", format), zap.Bool("dryRun", dryRun))
+
+	var (
+		rows []*dto.DeviceImportRow
+		err  error
+	)
+	switch format {
+	case DeviceFileFormatCSV:
+		rows, err = parseDeviceCSV(r)
+	case DeviceFileFormatJSON:
+		rows, err = parseDeviceJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+	if err != nil {
+		logger.Warn("failed to parse device import file", zap.Error(err))
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("导入文件中没有设备数据")
+	}
+	if len(rows) > maxDeviceImportRows {
+		return nil, fmt.Errorf("单次最多导入 %d 台设备", maxDeviceImportRows)
+	}
+
+	existing, err := s.deviceDAO.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := loadDeviceCatalog(ctx, s.modelDAO)
+	if err != nil {
+		return nil, err
+	}
+
+	results, devices := s.validateImportRows(rows, existing, catalog)
+
+	resp := &dto.DeviceImportResponse{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   results,
+	}
+	for _, result := range results {
+		if result.Valid {
+			resp.Valid++
+		} else {
+			resp.Invalid++
+		}
+	}
+
+	if dryRun || resp.Invalid > 0 {
+		logger.Info("device import not written",
+			zap.Bool("dryRun", dryRun), zap.Int("valid", resp.Valid), zap.Int("invalid", resp.Invalid))
+		return resp, nil
+	}
+
+	if err := s.deviceDAO.CreateBatch(ctx, devices); err != nil {
+		logger.Error("failed to import devices in service", zap.Error(err))
+		return nil, err
+	}
+
+	for i, device := range devices {
+		results[i].DeviceID = device.ID
+	}
+	resp.Imported = true
+
+	logger.Info("devices imported successfully in service", zap.Int("count", len(devices)))
+	return resp, nil
+}
+
+// ExportDevices 导出全部设备，不包含登录用户名、密码及设备凭证
+func (s *DeviceService) ExportDevices(ctx context.Context, format string, w io.Writer) error {
+	logger.Info("exporting devices in service", zap.String("format", format))
+
+	devices, err := s.deviceDAO.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	catalog, err := loadDeviceCatalog(ctx, s.modelDAO)
+	if err != nil {
+		return err
+	}
+	modelNames := make(map[uint]string, len(catalog.models))
+	for _, m := range catalog.models {
+		modelNames[m.ID] = m.Name
+	}
+
+	items := make([]*dto.DeviceExportItem, 0, len(devices))
+	for _, d := range devices {
+		item := &dto.DeviceExportItem{
+			ID:           d.ID,
+			Type:         d.Type,
+			Company:      d.Company,
+			SerialNumber: d.SerialNumber,
+			IP:           d.IP,
+			Port:         d.Port,
+			Status:       d.Status,
+			ExtraInfo:    d.ExtraInfo,
+		}
+		if d.ModelID != nil {
+			if name, ok := modelNames[*d.ModelID]; ok {
+				item.Model = &name
+			}
+		}
+		if d.Capabilities != nil && *d.Capabilities != "" {
+			if err := json.Unmarshal([]byte(*d.Capabilities), &item.Capabilities); err != nil {
+				logger.Warn("invalid device capabilities, skipped in export", zap.Error(err), zap.Uint("id", d.ID))
+			}
+		}
+		items = append(items, item)
+	}
+
+	switch format {
+	case DeviceFileFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+	case DeviceFileFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(deviceExportColumns); err != nil {
+			return err
+		}
+		for _, item := range items {
+			status := ""
+			if item.Status != nil {
+				status = string(*item.Status)
+			}
+			record := []string{
+				strconv.FormatUint(uint64(item.ID), 10),
+				derefString(item.Model),
+				string(item.Type),
+				string(item.Company),
+				derefString(item.SerialNumber),
+				derefString(item.IP),
+				strconv.Itoa(item.Port),
+				status,
+				strings.Join(item.Capabilities, capabilitySeparator),
+				derefString(item.ExtraInfo),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// validateImportRows 逐行校验导入数据，返回逐行结果及校验通过的设备实体
+func (s *DeviceService) validateImportRows(rows []*dto.DeviceImportRow, existing []*entity.Device, catalog *deviceCatalog) ([]*dto.DeviceImportRowResult, []*entity.Device) {
+	existingSerials := make(map[string]bool, len(existing))
+	existingEndpoints := make(map[string]bool, len(existing))
+	for _, d := range existing {
+		if d.SerialNumber != nil && *d.SerialNumber != "" {
+			existingSerials[*d.SerialNumber] = true
+		}
+		if d.IP != nil && *d.IP != "" {
+			existingEndpoints[net.JoinHostPort(*d.IP, strconv.Itoa(d.Port))] = true
+		}
+	}
+
+	fileSerials := make(map[string]int)
+	fileEndpoints := make(map[string]int)
+
+	results := make([]*dto.DeviceImportRowResult, 0, len(rows))
+	devices := make([]*entity.Device, 0, len(rows))
+	for i, row := range rows {
+		result := &dto.DeviceImportRowResult{
+			Row:          i + 1,
+			Type:         row.Type,
+			Company:      row.Company,
+			SerialNumber: row.SerialNumber,
+			IP:           row.IP,
+			Port:         row.Port,
+		}
+
+		model, err := catalog.resolve(row.Company, row.Type, nil, derefString(row.Model))
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		port := row.Port
+		if row.InvalidPort != "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("无效的端口: %q", row.InvalidPort))
+		} else {
+			// 只有未填写端口时才使用型号默认端口
+			if port == 0 && model != nil {
+				port = model.DefaultPort
+				result.Port = port
+			}
+			if port < 1 || port > 65535 {
+				result.Errors = append(result.Errors, fmt.Sprintf("端口超出范围(1-65535): %d", port))
+			}
+		}
+		if row.IP != nil && *row.IP != "" {
+			if net.ParseIP(*row.IP) == nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("无效的IP地址: %q", *row.IP))
+			} else {
+				endpoint := net.JoinHostPort(*row.IP, strconv.Itoa(port))
+				if existingEndpoints[endpoint] {
+					result.Errors = append(result.Errors, fmt.Sprintf("地址 %s 已被已有设备使用", endpoint))
+				} else if first, ok := fileEndpoints[endpoint]; ok {
+					result.Errors = append(result.Errors, fmt.Sprintf("地址 %s 与第 %d 行重复", endpoint, first))
+				} else {
+					fileEndpoints[endpoint] = result.Row
+				}
+			}
+		}
+		if row.SerialNumber != nil && *row.SerialNumber != "" {
+			serial := *row.SerialNumber
+			if existingSerials[serial] {
+				result.Errors = append(result.Errors, fmt.Sprintf("序列号 %s 已存在", serial))
+			} else if first, ok := fileSerials[serial]; ok {
+				result.Errors = append(result.Errors, fmt.Sprintf("序列号 %s 与第 %d 行重复", serial, first))
+			} else {
+				fileSerials[serial] = result.Row
+			}
+		}
+
+		result.Valid = len(result.Errors) == 0
+		results = append(results, result)
+		if !result.Valid {
+			continue
+		}
+
+		device := &entity.Device{
+			Type:      row.Type,
+			Company:   row.Company,
+			IP:        emptyToNil(row.IP),
+			Port:      port,
+			UserName:  emptyToNil(row.UserName),
+			Password:  emptyToNil(row.Password),
+			Status:    &[]entity.DeviceStatus{entity.DeviceStatusOffline}[0],
+			ExtraInfo: emptyToNil(row.ExtraInfo),
+
+			SerialNumber: emptyToNil(row.SerialNumber),
+		}
+		if len(row.Capabilities) > 0 {
+			raw, _ := json.Marshal(row.Capabilities)
+			capabilities := string(raw)
+			device.Capabilities = &capabilities
+		}
+		applyDeviceModel(device, model)
+		devices = append(devices, device)
+	}
+
+	return results, devices
+}
+
+// parseDeviceJSON 解析 JSON 数组格式的导入文件
+func parseDeviceJSON(r io.Reader) ([]*dto.DeviceImportRow, error) {
+	var rows []*dto.DeviceImportRow
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("JSON 解析失败: %w", err)
+	}
+	for i, row := range rows {
+		if row == nil {
+			return nil, fmt.Errorf("第 %d 行为空", i+1)
+		}
+	}
+	return rows, nil
+}
+
+// parseDeviceCSV 解析带表头的 CSV 导入文件，列名与 JSON 字段名一致（不区分大小写）
+func parseDeviceCSV(r io.Reader) ([]*dto.DeviceImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("CSV 表头读取失败: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// 兼容 Excel 导出的 UTF-8 BOM
+			name = string(bytes.TrimPrefix([]byte(name), []byte("\xef\xbb\xbf")))
+		}
+		key := strings.ToLower(strings.TrimSpace(name))
+		if deviceImportIgnoredColumns[key] {
+			continue
+		}
+		switch key {
+		case "model", "type", "company", "serialnumber", "ip", "port", "username", "password", "capabilities", "extrainfo":
+			columns[key] = i
+		default:
+			return nil, fmt.Errorf("未知的 CSV 列: %s", name)
+		}
+	}
+	for _, required := range []string{"type", "company"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV 缺少必需列: %s", required)
+		}
+	}
+
+	var rows []*dto.DeviceImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSV 解析失败: %w", err)
+		}
+
+		value := func(key string) string {
+			if i, ok := columns[key]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		optional := func(key string) *string {
+			if v := value(key); v != "" {
+				return &v
+			}
+			return nil
+		}
+
+		row := &dto.DeviceImportRow{
+			Model:        optional("model"),
+			Type:         entity.DeviceType(value("type")),
+			Company:      entity.CompanyType(value("company")),
+			SerialNumber: optional("serialnumber"),
+			IP:           optional("ip"),
+			UserName:     optional("username"),
+			Password:     optional("password"),
+			ExtraInfo:    optional("extrainfo"),
+		}
+		// 端口为空时保留 0，由校验环节取型号默认端口；无法解析时记下原文，由校验环节给出错误
+		if text := value("port"); text != "" {
+			port, err := strconv.Atoi(text)
+			if err != nil {
+				row.InvalidPort = text
+			}
+			row.Port = port
+		}
+		if capabilities := value("capabilities"); capabilities != "" {
+			for _, c := range strings.Split(capabilities, capabilitySeparator) {
+				if c = strings.TrimSpace(c); c != "" {
+					row.Capabilities = append(row.Capabilities, c)
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// emptyToNil 空字符串视为未填写
+func emptyToNil(s *string) *string {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	return &v
+}
+
+// derefString 取字符串指针的值，nil 返回空串
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestDeviceService_ImportDevices_CSVReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceDAO := mocks.NewMockDeviceDAO(ctrl)
+	mockModelDAO := mocks.NewMockDeviceModelDAO(ctrl)
+	service := NewDeviceService(mockDeviceDAO, mockModelDAO, mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	existingIP := "192.168.1.10"
+	existingSerial := "SN-EXIST"
+	mockDeviceDAO.EXPECT().FindAll(ctx).Return([]*entity.Device{
+		{IP: &existingIP, Port: 8080, SerialNumber: &existingSerial},
+	}, nil)
+	mockModelDAO.EXPECT().FindAll(ctx).Return(testDeviceModels(), nil)
+
+	input := "\xef\xbb\xbftype,company,serialNumber,ip,port,capabilities\n" +
+		"robot_wheel,cyborg,SN-1,192.168.1.11,8080,nav;lift\n" +
+		"robot_car,cyborg,SN-2,192.168.1.12,8080,\n" +
+		"robot_wheel,cyborg,SN-1,192.168.1.13,8080,\n" +
+		"robot_wheel,cyborg,SN-EXIST,192.168.1.10,8080,\n" +
+		"robot_wheel,cyborg,,not-an-ip,70000,\n" +
+		"robot_wheel,cyborg,,192.168.1.14,abc,\n" +
+		"robot_wheel,cyborg,,192.168.1.15,,\n"
+
+	resp, err := service.ImportDevices(ctx, DeviceFileFormatCSV, strings.NewReader(input), false)
+	if err != nil {
+		t.Fatalf("ImportDevices failed: %v", err)
+	}
+
+	if resp.Total != 7 || resp.Valid != 2 || resp.Invalid != 5 {
+		t.Errorf("Expected 7 total / 2 valid / 5 invalid, got %d / %d / %d", resp.Total, resp.Valid, resp.Invalid)
+	}
+	if resp.Imported {
+		t.Error("Expected nothing imported when any row is invalid")
+	}
+	if !resp.Rows[0].Valid {
+		t.Errorf("Expected row 1 valid, got errors %v", resp.Rows[0].Errors)
+	}
+	if len(resp.Rows[3].Errors) != 2 {
+		t.Errorf("Expected duplicate serial and endpoint errors on row 4, got %v", resp.Rows[3].Errors)
+	}
+	if len(resp.Rows[4].Errors) != 2 {
+		t.Errorf("Expected ip and port errors on row 5, got %v", resp.Rows[4].Errors)
+	}
+	// 无法解析的端口报错，不能被型号默认端口替换；未填写端口时才使用默认端口
+	if resp.Rows[5].Valid || len(resp.Rows[5].Errors) != 1 || !strings.Contains(resp.Rows[5].Errors[0], "abc") {
+		t.Errorf("Expected invalid port error on row 6, got %v", resp.Rows[5].Errors)
+	}
+	if !resp.Rows[6].Valid || resp.Rows[6].Port != 9000 {
+		t.Errorf("Expected row 7 valid with default port 9000, got %d %v", resp.Rows[6].Port, resp.Rows[6].Errors)
+	}
+}
+
+func TestDeviceService_ImportDevices_JSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceDAO := mocks.NewMockDeviceDAO(ctrl)
+	mockModelDAO := mocks.NewMockDeviceModelDAO(ctrl)
+	service := NewDeviceService(mockDeviceDAO, mockModelDAO, mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	input := `[{"type":"robot_wheel","company":"cyborg","ip":"10.0.0.1","port":8080,"capabilities":["nav"]},
+		{"type":"robot_biped","company":"cyborg","ip":"10.0.0.2","port":8080}]`
+
+	// 预览不写入
+	mockDeviceDAO.EXPECT().FindAll(ctx).Return(nil, nil)
+	mockModelDAO.EXPECT().FindAll(ctx).Return(testDeviceModels(), nil)
+	resp, err := service.ImportDevices(ctx, DeviceFileFormatJSON, strings.NewReader(input), true)
+	if err != nil {
+		t.Fatalf("ImportDevices dry run failed: %v", err)
+	}
+	if !resp.DryRun || resp.Imported || resp.Valid != 2 {
+		t.Errorf("Unexpected dry run report: %+v", resp)
+	}
+
+	mockDeviceDAO.EXPECT().FindAll(ctx).Return(nil, nil)
+	mockModelDAO.EXPECT().FindAll(ctx).Return(testDeviceModels(), nil)
+	mockDeviceDAO.EXPECT().
+		CreateBatch(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, devices []*entity.Device) error {
+			for i, d := range devices {
+				d.ID = uint(i + 1)
+			}
+			if devices[0].Capabilities == nil || *devices[0].Capabilities != `["nav"]` {
+				t.Errorf("Expected capabilities stored as JSON, got %v", devices[0].Capabilities)
+			}
+			return nil
+		})
+
+	resp, err = service.ImportDevices(ctx, DeviceFileFormatJSON, strings.NewReader(input), false)
+	if err != nil {
+		t.Fatalf("ImportDevices failed: %v", err)
+	}
+	if !resp.Imported || resp.Rows[1].DeviceID != 2 {
+		t.Errorf("Unexpected import report: %+v", resp)
+	}
+}
+
+func TestDeviceService_ExportDevices_ExcludesCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceDAO := mocks.NewMockDeviceDAO(ctrl)
+	mockModelDAO := mocks.NewMockDeviceModelDAO(ctrl)
+	service := NewDeviceService(mockDeviceDAO, mockModelDAO, mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	ip := "10.0.0.1"
+	userName := "admin"
+	password := "secret"
+	capabilities := `["nav","lift"]`
+	mockDeviceDAO.EXPECT().FindAll(ctx).Return([]*entity.Device{
+		{Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg, IP: &ip, Port: 8080,
+			UserName: &userName, Password: &password, Capabilities: &capabilities},
+	}, nil)
+	mockModelDAO.EXPECT().FindAll(ctx).Return(testDeviceModels(), nil)
+
+	var buf bytes.Buffer
+	if err := service.ExportDevices(ctx, DeviceFileFormatCSV, &buf); err != nil {
+		t.Fatalf("ExportDevices failed: %v", err)
+	}
+
+	if strings.Contains(buf.String(), userName) || strings.Contains(buf.String(), password) {
+		t.Errorf("Export must not contain credentials: %s", buf.String())
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read exported csv: %v", err)
+	}
+	if len(records) != 2 || records[1][8] != "nav;lift" {
+		t.Errorf("Unexpected export records: %v", records)
+	}
+}
+
+func testDeviceModels() []*entity.DeviceModel {
+	wheelCapabilities := `["nav"]`
+	return []*entity.DeviceModel{
+		{Model: gorm.Model{ID: 1}, Company: entity.CompanyCyborg, Name: "cyborg-wheel", Type: entity.DeviceTypeWheelRobot,
+			DefaultPort: 9000, Capabilities: &wheelCapabilities, Enabled: true},
+		{Model: gorm.Model{ID: 2}, Company: entity.CompanyCyborg, Name: "cyborg-biped", Type: entity.DeviceTypeBipedRobot, Enabled: true},
+		{Model: gorm.Model{ID: 3}, Company: entity.CompanyCyborg, Name: "cyborg-biped-v1", Type: entity.DeviceTypeBipedRobot, Enabled: false},
+	}
+}
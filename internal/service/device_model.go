@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+)
+
+// ErrInvalidDeviceModel 设备的型号、类型或厂商与型号目录不符
+var ErrInvalidDeviceModel = errors.New("invalid device model")
+
+// DeviceModelService 设备型号目录服务
+type DeviceModelService struct {
+	modelDAO  dao.DeviceModelDAO
+	deviceDAO dao.DeviceDAO
+}
+
+func NewDeviceModelService(modelDAO dao.DeviceModelDAO, deviceDAO dao.DeviceDAO) *DeviceModelService {
+	return &DeviceModelService{
+		modelDAO:  modelDAO,
+		deviceDAO: deviceDAO,
+	}
+}
+
+// CreateModel 创建设备型号
+func (s *DeviceModelService) CreateModel(ctx context.Context, req *dto.DeviceModelCreateRequest) (*dto.DeviceModelResponse, error) {
+	logger.Info("creating device model in service", zap.String("company", string(req.Company)), zap.String("name", req.Name))
+
+	existing, err := s.modelDAO.FindByCompanyAndName(ctx, req.Company, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("该厂商下已存在同名型号")
+	}
+
+	model := &entity.DeviceModel{
+		Company:     req.Company,
+		Name:        req.Name,
+		Type:        req.Type,
+		DefaultPort: req.DefaultPort,
+		DriverName:  req.DriverName,
+		Enabled:     true,
+		Description: req.Description,
+	}
+	if req.Enabled != nil {
+		model.Enabled = *req.Enabled
+	}
+	if len(req.Capabilities) > 0 {
+		raw, err := json.Marshal(req.Capabilities)
+		if err != nil {
+			return nil, err
+		}
+		capabilities := string(raw)
+		model.Capabilities = &capabilities
+	}
+
+	if err := s.modelDAO.Create(ctx, model); err != nil {
+		logger.Error("failed to create device model in service", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("device model created successfully in service", zap.Uint("id", model.ID))
+	return dto.NewDeviceModelResponseFromEntity(model), nil
+}
+
+// UpdateModel 更新设备型号，厂商不可修改
+func (s *DeviceModelService) UpdateModel(ctx context.Context, id uint, req *dto.DeviceModelUpdateRequest) (*dto.DeviceModelResponse, error) {
+	logger.Info("updating device model in service", zap.Uint("id", id))
+
+	model, err := s.modelDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if model == nil {
+		return nil, errors.New("device model not found")
+	}
+
+	if req.Name != nil && *req.Name != model.Name {
+		existing, err := s.modelDAO.FindByCompanyAndName(ctx, model.Company, *req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, errors.New("该厂商下已存在同名型号")
+		}
+		model.Name = *req.Name
+	}
+	if req.Type != nil {
+		model.Type = *req.Type
+	}
+	if req.Capabilities != nil {
+		raw, err := json.Marshal(req.Capabilities)
+		if err != nil {
+			return nil, err
+		}
+		capabilities := string(raw)
+		model.Capabilities = &capabilities
+	}
+	if req.DefaultPort != nil {
+		model.DefaultPort = *req.DefaultPort
+	}
+	if req.DriverName != nil {
+		model.DriverName = *req.DriverName
+	}
+	if req.Enabled != nil {
+		model.Enabled = *req.Enabled
+	}
+	if req.Description != nil {
+		model.Description = req.Description
+	}
+
+	if err := s.modelDAO.Update(ctx, model); err != nil {
+		logger.Error("failed to update device model in service", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	logger.Info("device model updated successfully in service", zap.Uint("id", id))
+	return dto.NewDeviceModelResponseFromEntity(model), nil
+}
+
+// DeleteModel 删除设备型号，仍有设备使用时不允许删除（可改为停用）
+func (s *DeviceModelService) DeleteModel(ctx context.Context, id uint) error {
+	logger.Info("deleting device model in service", zap.Uint("id", id))
+
+	count, err := s.deviceDAO.CountByModelID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("仍有 %d 台设备使用该型号，请先停用", count)
+	}
+
+	return s.modelDAO.Delete(ctx, id)
+}
+
+// GetModel 获取设备型号
+func (s *DeviceModelService) GetModel(ctx context.Context, id uint) (*dto.DeviceModelResponse, error) {
+	model, err := s.modelDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if model == nil {
+		return nil, nil
+	}
+	return dto.NewDeviceModelResponseFromEntity(model), nil
+}
+
+// ListModels 按条件分页查询设备型号
+func (s *DeviceModelService) ListModels(ctx context.Context, req dto.DeviceModelListRequest) (*dto.DeviceModelListResponse, error) {
+	logger.Debug("listing device models in service")
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+
+	filter := dao.DeviceModelFilter{
+		Company: req.Company,
+		Type:    req.Type,
+		Enabled: req.Enabled,
+	}
+
+	models, total, err := s.modelDAO.FindPage(ctx, filter, offset, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := 0
+	if req.PageSize > 0 {
+		pages = int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	}
+
+	page := dto.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Pages:    pages,
+	}
+
+	return dto.NewDeviceModelListResponseFromEntities(models, page), nil
+}
+
+// deviceCatalog 已启用设备型号的内存索引，用于校验设备的类型/厂商
+type deviceCatalog struct {
+	models []*entity.DeviceModel
+}
+
+// loadDeviceCatalog 加载设备型号目录
+func loadDeviceCatalog(ctx context.Context, modelDAO dao.DeviceModelDAO) (*deviceCatalog, error) {
+	models, err := modelDAO.FindAll(ctx)
+	if err != nil {
+		logger.Error("failed to load device model catalog", zap.Error(err))
+		return nil, err
+	}
+	return &deviceCatalog{models: models}, nil
+}
+
+// resolve 按型号ID、型号名称或类型/厂商组合在目录中查找型号
+// 指定型号ID或名称时必须命中已启用的型号，且与给出的类型/厂商一致；
+// 只给出类型/厂商时，目录中须有对应的已启用型号，恰好一个时返回该型号，多个时返回 nil
+func (c *deviceCatalog) resolve(company entity.CompanyType, deviceType entity.DeviceType, modelID *uint, modelName string) (*entity.DeviceModel, error) {
+	var model *entity.DeviceModel
+	switch {
+	case modelID != nil:
+		for _, m := range c.models {
+			if m.ID == *modelID {
+				model = m
+				break
+			}
+		}
+		if model == nil || !model.Enabled {
+			return nil, fmt.Errorf("设备型号不存在或已停用: %d", *modelID)
+		}
+	case modelName != "":
+		for _, m := range c.models {
+			if m.Company == company && m.Name == modelName {
+				model = m
+				break
+			}
+		}
+		if model == nil || !model.Enabled {
+			return nil, fmt.Errorf("厂商 %s 没有已启用的型号 %s", company, modelName)
+		}
+	}
+
+	if model != nil {
+		if company != "" && company != model.Company {
+			return nil, fmt.Errorf("设备厂商 %s 与型号厂商 %s 不一致", company, model.Company)
+		}
+		if deviceType != "" && deviceType != model.Type {
+			return nil, fmt.Errorf("设备类型 %s 与型号类型 %s 不一致", deviceType, model.Type)
+		}
+		return model, nil
+	}
+
+	if company == "" || deviceType == "" {
+		return nil, errors.New("未指定设备型号时，设备类型和厂商不能为空")
+	}
+
+	var (
+		matches      []*entity.DeviceModel
+		knownCompany bool
+	)
+	for _, m := range c.models {
+		if !m.Enabled || m.Company != company {
+			continue
+		}
+		knownCompany = true
+		if m.Type == deviceType {
+			matches = append(matches, m)
+		}
+	}
+	if !knownCompany {
+		return nil, fmt.Errorf("未登记的设备厂商: %q", company)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("厂商 %s 没有类型为 %q 的已启用型号", company, deviceType)
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	return nil, nil
+}
+
+// applyDeviceModel 将型号信息写入设备：绑定型号并补全类型、厂商、默认端口和默认能力
+func applyDeviceModel(device *entity.Device, model *entity.DeviceModel) {
+	if model == nil {
+		return
+	}
+	device.ModelID = &model.ID
+	device.Type = model.Type
+	device.Company = model.Company
+	if device.Port == 0 {
+		device.Port = model.DefaultPort
+	}
+	if device.Capabilities == nil && model.Capabilities != nil {
+		capabilities := *model.Capabilities
+		device.Capabilities = &capabilities
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestDeviceCatalog_Resolve(t *testing.T) {
+	catalog := &deviceCatalog{models: testDeviceModels()}
+
+	// 类型/厂商唯一匹配时绑定型号
+	model, err := catalog.resolve(entity.CompanyCyborg, entity.DeviceTypeWheelRobot, nil, "")
+	if err != nil || model == nil || model.ID != 1 {
+		t.Fatalf("Expected wheel model, got %v, %v", model, err)
+	}
+
+	// 停用的型号不参与匹配
+	model, err = catalog.resolve(entity.CompanyCyborg, entity.DeviceTypeBipedRobot, nil, "")
+	if err != nil || model == nil || model.ID != 2 {
+		t.Fatalf("Expected enabled biped model, got %v, %v", model, err)
+	}
+
+	if _, err := catalog.resolve("unknown", entity.DeviceTypeWheelRobot, nil, ""); err == nil {
+		t.Error("Expected error for unknown company")
+	}
+	if _, err := catalog.resolve(entity.CompanyCyborg, "robot_car", nil, ""); err == nil {
+		t.Error("Expected error for unknown type")
+	}
+
+	disabled := uint(3)
+	if _, err := catalog.resolve("", "", &disabled, ""); err == nil {
+		t.Error("Expected error for disabled model")
+	}
+
+	wheel := uint(1)
+	if _, err := catalog.resolve("", entity.DeviceTypeBipedRobot, &wheel, ""); err == nil {
+		t.Error("Expected error when type conflicts with model")
+	}
+
+	model, err = catalog.resolve(entity.CompanyCyborg, "", nil, "cyborg-biped")
+	if err != nil || model == nil || model.ID != 2 {
+		t.Fatalf("Expected model resolved by name, got %v, %v", model, err)
+	}
+}
+
+func TestDeviceService_CreateDevice_UsesModelDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceDAO := mocks.NewMockDeviceDAO(ctrl)
+	mockModelDAO := mocks.NewMockDeviceModelDAO(ctrl)
+	service := NewDeviceService(mockDeviceDAO, mockModelDAO, mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	modelID := uint(1)
+	mockModelDAO.EXPECT().FindAll(ctx).Return(testDeviceModels(), nil)
+	mockDeviceDAO.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	resp, err := service.CreateDevice(ctx, &dto.DeviceCreateRequest{ModelID: &modelID})
+	if err != nil {
+		t.Fatalf("CreateDevice failed: %v", err)
+	}
+	if resp.Type != entity.DeviceTypeWheelRobot || resp.Company != entity.CompanyCyborg {
+		t.Errorf("Expected type/company from model, got %s/%s", resp.Type, resp.Company)
+	}
+	if resp.Port != 9000 {
+		t.Errorf("Expected default port 9000, got %d", resp.Port)
+	}
+	if resp.ModelID == nil || *resp.ModelID != modelID {
+		t.Errorf("Expected model id %d, got %v", modelID, resp.ModelID)
+	}
+
+	mockModelDAO.EXPECT().FindAll(ctx).Return(testDeviceModels(), nil)
+	_, err = service.CreateDevice(ctx, &dto.DeviceCreateRequest{Type: "robot_car", Company: entity.CompanyCyborg, Port: 80})
+	if !errors.Is(err, ErrInvalidDeviceModel) {
+		t.Errorf("Expected unregistered type to be rejected, got %v", err)
+	}
+}
+
+func TestDeviceService_UpdateDevice_AmbiguousModel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceDAO := mocks.NewMockDeviceDAO(ctrl)
+	mockModelDAO := mocks.NewMockDeviceModelDAO(ctrl)
+	service := NewDeviceService(mockDeviceDAO, mockModelDAO, mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	models := append(testDeviceModels(), &entity.DeviceModel{Model: gorm.Model{ID: 4}, Company: entity.CompanyCyborg,
+		Name: "cyborg-biped-v2", Type: entity.DeviceTypeBipedRobot, Enabled: true})
+	modelID := uint(1)
+	device := &entity.Device{Model: gorm.Model{ID: 5}, Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg, Port: 9000, ModelID: &modelID}
+	mockDeviceDAO.EXPECT().FindByID(ctx, uint(5)).Return(device, nil)
+	mockModelDAO.EXPECT().FindAll(ctx).Return(models, nil)
+
+	// 新类型对应多个型号时拒绝更新，而不是清空原有的型号绑定
+	biped := entity.DeviceTypeBipedRobot
+	err := service.UpdateDevice(ctx, 5, &dto.DeviceUpdateRequest{Type: &biped})
+	if !errors.Is(err, ErrInvalidDeviceModel) {
+		t.Fatalf("Expected ErrInvalidDeviceModel for ambiguous model, got %v", err)
+	}
+}
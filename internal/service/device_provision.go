@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// enrollmentCodePrefix 注册码前缀
+	enrollmentCodePrefix = "enr_"
+	// deviceTokenPrefix 设备凭证前缀
+	deviceTokenPrefix = "dev_"
+	// defaultEnrollmentExpireMinutes 注册码默认有效期(分钟)
+	defaultEnrollmentExpireMinutes = 60
+)
+
+// ErrDeviceAlreadyEnrolled 序列号已被其他设备注册，重新注册须携带该设备当前的凭证
+var ErrDeviceAlreadyEnrolled = errors.New("device already enrolled")
+
+// DeviceProvisionService 设备注册与凭证服务
+type DeviceProvisionService struct {
+	deviceDAO     dao.DeviceDAO
+	enrollmentDAO dao.DeviceEnrollmentDAO
+	credentialDAO dao.DeviceCredentialDAO
+	telemetryDAO  dao.DeviceTelemetryDAO
+	alarmService  *DeviceAlarmService
+	modelDAO      dao.DeviceModelDAO
+	frameService  *FrameService
+}
+
+func NewDeviceProvisionService(
+	deviceDAO dao.DeviceDAO,
+	enrollmentDAO dao.DeviceEnrollmentDAO,
+	credentialDAO dao.DeviceCredentialDAO,
+	telemetryDAO dao.DeviceTelemetryDAO,
+	alarmService *DeviceAlarmService,
+	modelDAO dao.DeviceModelDAO,
+	frameService *FrameService,
+) *DeviceProvisionService {
+	return &DeviceProvisionService{
+		deviceDAO:     deviceDAO,
+		enrollmentDAO: enrollmentDAO,
+		credentialDAO: credentialDAO,
+		telemetryDAO:  telemetryDAO,
+		alarmService:  alarmService,
+		modelDAO:      modelDAO,
+		frameService:  frameService,
+	}
+}
+
+// CreateEnrollmentCode 创建一次性设备注册码
+func (s *DeviceProvisionService) CreateEnrollmentCode(ctx context.Context, userName string, req *dto.DeviceEnrollmentCodeCreateRequest) (*dto.DeviceEnrollmentCodeResponse, error) {
+	logger.Info("creating device enrollment code in service", zap.String("userName", userName))
+
+	expireMinutes := req.ExpireMinutes
+	if expireMinutes <= 0 {
+		expireMinutes = defaultEnrollmentExpireMinutes
+	}
+
+	code, err := utils.GenerateRandomToken(enrollmentCodePrefix, 16)
+	if err != nil {
+		logger.Error("failed to generate enrollment code", zap.Error(err))
+		return nil, err
+	}
+
+	record := &entity.DeviceEnrollmentCode{
+		CodeHash:  utils.HashToken(code),
+		CreatedBy: userName,
+		ExpireAt:  time.Now().Add(time.Duration(expireMinutes) * time.Minute),
+		Remark:    req.Remark,
+	}
+
+	if err := s.enrollmentDAO.Create(ctx, record); err != nil {
+		logger.Error("failed to create enrollment code in service", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("device enrollment code created successfully in service", zap.Uint("id", record.ID))
+	return &dto.DeviceEnrollmentCodeResponse{
+		ID:       record.ID,
+		Code:     code,
+		ExpireAt: record.ExpireAt,
+		Remark:   record.Remark,
+	}, nil
+}
+
+// Enroll 设备使用注册码完成注册，返回长期设备凭证
+// 已存在相同序列号的设备时视为重新注册，须携带该设备当前的凭证，复用原设备记录并吊销旧凭证；
+// 凭证已被管理员全部吊销的设备可直接重新注册
+func (s *DeviceProvisionService) Enroll(ctx context.Context, remoteIP string, req *dto.DeviceEnrollRequest) (*dto.DeviceEnrollResponse, error) {
+	logger.Info("enrolling device in service",
+		zap.String("serialNumber", req.SerialNumber),
+		zap.String("type", string(req.Type)),
+		zap.String("company", string(req.Company)),
+	)
+
+	code, err := s.enrollmentDAO.FindByCodeHash(ctx, utils.HashToken(req.Code))
+	if err != nil {
+		logger.Error("failed to find enrollment code", zap.Error(err))
+		return nil, err
+	}
+	if code == nil {
+		logger.Warn("invalid enrollment code", zap.String("serialNumber", req.SerialNumber))
+		return nil, errors.New("注册码无效")
+	}
+	if code.UsedAt != nil {
+		logger.Warn("enrollment code already used", zap.Uint("codeID", code.ID))
+		return nil, errors.New("注册码已被使用")
+	}
+	if time.Now().After(code.ExpireAt) {
+		logger.Warn("enrollment code expired", zap.Uint("codeID", code.ID))
+		return nil, errors.New("注册码已过期")
+	}
+
+	catalog, err := loadDeviceCatalog(ctx, s.modelDAO)
+	if err != nil {
+		return nil, err
+	}
+	model, err := catalog.resolve(req.Company, req.Type, nil, req.Model)
+	if err != nil {
+		logger.Warn("device enroll rejected by model catalog", zap.Error(err), zap.String("serialNumber", req.SerialNumber))
+		return nil, err
+	}
+
+	device, err := s.deviceDAO.FindBySerialNumber(ctx, req.SerialNumber)
+	if err != nil {
+		logger.Error("failed to find device by serial number", zap.Error(err))
+		return nil, err
+	}
+	if device == nil {
+		device = &entity.Device{
+			Status: &[]entity.DeviceStatus{entity.DeviceStatusOffline}[0],
+		}
+	} else if err := s.checkReenroll(ctx, device, req.Token); err != nil {
+		logger.Warn("device re-enroll rejected", zap.Error(err), zap.Uint("deviceID", device.ID), zap.String("serialNumber", req.SerialNumber))
+		return nil, err
+	}
+
+	ip := req.IP
+	if ip == nil && remoteIP != "" {
+		ip = &remoteIP
+	}
+
+	device.Type = req.Type
+	device.Company = req.Company
+	device.SerialNumber = &req.SerialNumber
+	device.IP = ip
+	if req.Port > 0 {
+		device.Port = req.Port
+	}
+	if req.Capabilities != nil {
+		raw, err := json.Marshal(req.Capabilities)
+		if err != nil {
+			return nil, err
+		}
+		capabilities := string(raw)
+		device.Capabilities = &capabilities
+	}
+	if req.ExtraInfo != nil {
+		device.ExtraInfo = req.ExtraInfo
+	}
+	applyDeviceModel(device, model)
+
+	token, err := utils.GenerateRandomToken(deviceTokenPrefix, 32)
+	if err != nil {
+		logger.Error("failed to generate device token", zap.Error(err))
+		return nil, err
+	}
+	credential := &entity.DeviceCredential{
+		TokenHash: utils.HashToken(token),
+	}
+
+	if err := s.enrollmentDAO.Redeem(ctx, code, device, credential); err != nil {
+		if errors.Is(err, dao.ErrEnrollmentCodeUsed) {
+			return nil, errors.New("注册码已被使用")
+		}
+		logger.Error("failed to redeem enrollment code in service", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("device enrolled successfully in service",
+		zap.Uint("deviceID", device.ID),
+		zap.String("serialNumber", req.SerialNumber),
+	)
+	return &dto.DeviceEnrollResponse{
+		DeviceID: device.ID,
+		Token:    token,
+	}, nil
+}
+
+// checkReenroll 校验重新注册的请求持有设备当前的凭证，设备没有未吊销的凭证时放行
+func (s *DeviceProvisionService) checkReenroll(ctx context.Context, device *entity.Device, token *string) error {
+	active, err := s.credentialDAO.FindActiveByDeviceID(ctx, device.ID)
+	if err != nil {
+		return err
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	if token == nil || *token == "" {
+		return fmt.Errorf("%w: 序列号 %s 已注册，重新注册须携带设备当前凭证", ErrDeviceAlreadyEnrolled, *device.SerialNumber)
+	}
+	credential, err := s.credentialDAO.FindByTokenHash(ctx, utils.HashToken(*token))
+	if err != nil {
+		return err
+	}
+	if credential == nil || credential.DeviceID != device.ID {
+		return fmt.Errorf("%w: 设备凭证与序列号 %s 不匹配", ErrDeviceAlreadyEnrolled, *device.SerialNumber)
+	}
+	return nil
+}
+
+// AuthenticateDevice 校验设备凭证，返回设备ID
+func (s *DeviceProvisionService) AuthenticateDevice(ctx context.Context, token string) (uint, error) {
+	credential, err := s.credentialDAO.FindByTokenHash(ctx, utils.HashToken(token))
+	if err != nil {
+		return 0, err
+	}
+	if credential == nil {
+		return 0, errors.New("设备凭证无效或已吊销")
+	}
+
+	if err := s.credentialDAO.TouchLastUsed(ctx, credential.ID); err != nil {
+		// 更新使用时间失败不影响认证结果
+		logger.Warn("failed to touch device credential", zap.Error(err), zap.Uint("id", credential.ID))
+	}
+
+	return credential.DeviceID, nil
+}
+
+// RevokeCredential 吊销设备的全部凭证，设备需使用新的注册码重新注册
+func (s *DeviceProvisionService) RevokeCredential(ctx context.Context, deviceID uint, userName string) error {
+	logger.Info("revoking device credential in service", zap.Uint("deviceID", deviceID), zap.String("userName", userName))
+
+	device, err := s.deviceDAO.FindByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		return errors.New("device not found")
+	}
+
+	count, err := s.credentialDAO.RevokeByDeviceID(ctx, deviceID, userName)
+	if err != nil {
+		logger.Error("failed to revoke device credential in service", zap.Error(err), zap.Uint("deviceID", deviceID))
+		return err
+	}
+	if count == 0 {
+		return errors.New("设备没有有效凭证")
+	}
+
+	// 吊销后设备无法再上报，标记为离线
+	device.Status = &[]entity.DeviceStatus{entity.DeviceStatusOffline}[0]
+	if err := s.deviceDAO.Update(ctx, device); err != nil {
+		logger.Warn("failed to mark revoked device offline", zap.Error(err), zap.Uint("deviceID", deviceID))
+	}
+
+	logger.Info("device credential revoked successfully in service", zap.Uint("deviceID", deviceID), zap.Int64("count", count))
+	return nil
+}
+
+// Heartbeat 处理设备心跳
+func (s *DeviceProvisionService) Heartbeat(ctx context.Context, deviceID uint, req *dto.DeviceHeartbeatRequest) error {
+	logger.Debug("handling device heartbeat in service", zap.Uint("deviceID", deviceID))
+
+	device, err := s.deviceDAO.FindByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		return errors.New("device not found")
+	}
+
+	if err := s.applyHeartbeat(ctx, device, req.Status); err != nil {
+		return err
+	}
+	return s.deviceDAO.Update(ctx, device)
+}
+
+// ReportTelemetry 保存设备遥测数据，同时视为一次心跳
+func (s *DeviceProvisionService) ReportTelemetry(ctx context.Context, deviceID uint, req *dto.DeviceTelemetryRequest) error {
+	logger.Debug("handling device telemetry in service", zap.Uint("deviceID", deviceID))
+
+	device, err := s.deviceDAO.FindByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		return errors.New("device not found")
+	}
+
+	telemetry := &entity.DeviceTelemetry{
+		DeviceID: deviceID,
+		Status:   req.Status,
+		Battery:  req.Battery,
+		Payload:  req.Payload,
+	}
+	if req.Pose != nil {
+		telemetry.PoseX = &req.Pose.X
+		telemetry.PoseY = &req.Pose.Y
+		telemetry.PoseZ = req.Pose.Z
+		telemetry.PoseYaw = &req.Pose.Yaw
+		if req.Pose.Frame != "" {
+			telemetry.PoseFrame = &req.Pose.Frame
+		}
+	}
+
+	if err := s.telemetryDAO.Create(ctx, telemetry); err != nil {
+		logger.Error("failed to save device telemetry in service", zap.Error(err), zap.Uint("deviceID", deviceID))
+		return err
+	}
+
+	if len(req.Alarms) > 0 {
+		if err := s.alarmService.SaveReports(ctx, deviceID, req.Alarms); err != nil {
+			logger.Error("failed to save device alarms with telemetry", zap.Error(err), zap.Uint("deviceID", deviceID))
+			return err
+		}
+	}
+
+	if err := s.applyHeartbeat(ctx, device, req.Status); err != nil {
+		return err
+	}
+	return s.deviceDAO.Update(ctx, device)
+}
+
+// ListTelemetry 分页查询设备遥测记录，frameName 不为空时把带坐标系的位姿换算到该坐标系，未标明坐标系的位姿原样返回
+func (s *DeviceProvisionService) ListTelemetry(ctx context.Context, deviceID uint, req dto.PageRequest, frameName string) (*dto.DeviceTelemetryListResponse, error) {
+	logger.Debug("listing device telemetry in service", zap.Uint("deviceID", deviceID), zap.String("frame", frameName))
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+
+	records, total, err := s.telemetryDAO.FindPageByDeviceID(ctx, deviceID, offset, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := 0
+	if req.PageSize > 0 {
+		pages = int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	}
+
+	page := dto.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Pages:    pages,
+	}
+
+	resp := dto.NewDeviceTelemetryListResponseFromEntities(records, page)
+	if frameName != "" {
+		if err := s.convertTelemetryPoses(ctx, resp.List, frameName); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// convertTelemetryPoses 把遥测位姿换算到 target 坐标系，同一坐标系的变换只求一次
+func (s *DeviceProvisionService) convertTelemetryPoses(ctx context.Context, list []*dto.DeviceTelemetryResponse, target string) error {
+	var graph *frame.Graph
+	transforms := make(map[string]frame.Transform)
+	for _, t := range list {
+		if t.Pose == nil || t.Pose.Frame == "" || t.Pose.Frame == target {
+			continue
+		}
+		transform, ok := transforms[t.Pose.Frame]
+		if !ok {
+			if graph == nil {
+				g, err := s.frameService.Graph(ctx)
+				if err != nil {
+					return err
+				}
+				graph = g
+			}
+			resolved, err := graph.Resolve(t.Pose.Frame, target)
+			if err != nil {
+				return err
+			}
+			transform, transforms[t.Pose.Frame] = resolved, resolved
+		}
+
+		p := frame.Pose{X: t.Pose.X, Y: t.Pose.Y, Yaw: t.Pose.Yaw}
+		if t.Pose.Z != nil {
+			p.Z = *t.Pose.Z
+		}
+		p = transform.Apply(p)
+		t.Pose.X, t.Pose.Y, t.Pose.Yaw, t.Pose.Frame = p.X, p.Y, p.Yaw, target
+		if t.Pose.Z != nil || p.Z != 0 {
+			t.Pose.Z = &p.Z
+		}
+	}
+	return nil
+}
+
+// applyHeartbeat 刷新心跳时间与状态，存在未解除的严重告警时状态保持为 error
+func (s *DeviceProvisionService) applyHeartbeat(ctx context.Context, device *entity.Device, status *entity.DeviceStatus) error {
+	now := time.Now()
+	device.LastHeartbeatAt = &now
+	if status != nil {
+		device.Status = status
+	} else {
+		device.Status = &[]entity.DeviceStatus{entity.DeviceStatusOnline}[0]
+	}
+	return s.alarmService.ApplyDeviceStatus(ctx, device)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"robot_scheduler/internal/utils"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestDeviceProvisionService_EnrollExistingSerial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceDAO := mocks.NewMockDeviceDAO(ctrl)
+	mockEnrollmentDAO := mocks.NewMockDeviceEnrollmentDAO(ctrl)
+	mockCredentialDAO := mocks.NewMockDeviceCredentialDAO(ctrl)
+	mockModelDAO := mocks.NewMockDeviceModelDAO(ctrl)
+	service := NewDeviceProvisionService(mockDeviceDAO, mockEnrollmentDAO, mockCredentialDAO, nil, nil, mockModelDAO, nil)
+	ctx := context.Background()
+
+	serial := "SN-001"
+	existing := func() *entity.Device {
+		return &entity.Device{Model: gorm.Model{ID: 7}, Type: entity.DeviceTypeWheelRobot, Company: entity.CompanyCyborg, SerialNumber: &serial}
+	}
+	request := func(token *string) *dto.DeviceEnrollRequest {
+		return &dto.DeviceEnrollRequest{
+			Code:         "code",
+			Type:         entity.DeviceTypeWheelRobot,
+			Company:      entity.CompanyCyborg,
+			SerialNumber: serial,
+			Token:        token,
+		}
+	}
+	expectLookup := func() {
+		mockEnrollmentDAO.EXPECT().FindByCodeHash(ctx, utils.HashToken("code")).
+			Return(&entity.DeviceEnrollmentCode{Model: gorm.Model{ID: 1}, ExpireAt: time.Now().Add(time.Hour)}, nil)
+		mockModelDAO.EXPECT().FindAll(ctx).Return(testDeviceModels(), nil)
+		mockDeviceDAO.EXPECT().FindBySerialNumber(ctx, serial).Return(existing(), nil)
+	}
+	active := []*entity.DeviceCredential{{Model: gorm.Model{ID: 3}, DeviceID: 7}}
+
+	// 设备仍有有效凭证时，未携带凭证不能覆盖已注册的设备
+	expectLookup()
+	mockCredentialDAO.EXPECT().FindActiveByDeviceID(ctx, uint(7)).Return(active, nil)
+	if _, err := service.Enroll(ctx, "", request(nil)); !errors.Is(err, ErrDeviceAlreadyEnrolled) {
+		t.Fatalf("Expected ErrDeviceAlreadyEnrolled without token, got %v", err)
+	}
+
+	// 携带其他设备的凭证同样拒绝
+	other := "dev_other"
+	expectLookup()
+	mockCredentialDAO.EXPECT().FindActiveByDeviceID(ctx, uint(7)).Return(active, nil)
+	mockCredentialDAO.EXPECT().FindByTokenHash(ctx, utils.HashToken(other)).
+		Return(&entity.DeviceCredential{Model: gorm.Model{ID: 4}, DeviceID: 8}, nil)
+	if _, err := service.Enroll(ctx, "", request(&other)); !errors.Is(err, ErrDeviceAlreadyEnrolled) {
+		t.Fatalf("Expected ErrDeviceAlreadyEnrolled with foreign token, got %v", err)
+	}
+
+	// 携带设备当前凭证时复用原设备记录
+	current := "dev_current"
+	expectLookup()
+	mockCredentialDAO.EXPECT().FindActiveByDeviceID(ctx, uint(7)).Return(active, nil)
+	mockCredentialDAO.EXPECT().FindByTokenHash(ctx, utils.HashToken(current)).Return(active[0], nil)
+	mockEnrollmentDAO.EXPECT().Redeem(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *entity.DeviceEnrollmentCode, device *entity.Device, _ *entity.DeviceCredential) error {
+			if device.ID != 7 {
+				t.Errorf("Expected existing device to be reused, got %d", device.ID)
+			}
+			return nil
+		})
+	resp, err := service.Enroll(ctx, "", request(&current))
+	if err != nil || resp.DeviceID != 7 {
+		t.Fatalf("Expected re-enroll with current token, got %v, %v", resp, err)
+	}
+
+	// 凭证已被全部吊销的设备可直接重新注册
+	expectLookup()
+	mockCredentialDAO.EXPECT().FindActiveByDeviceID(ctx, uint(7)).Return(nil, nil)
+	mockEnrollmentDAO.EXPECT().Redeem(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	if _, err := service.Enroll(ctx, "", request(nil)); err != nil {
+		t.Fatalf("Expected re-enroll of revoked device, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
+
+	"go.uber.org/zap"
+)
+
+// ErrInvalidFrameTransform 坐标系变换无效：坐标系名称不合法、父子相同或与已有变换构成环路
+var ErrInvalidFrameTransform = errors.New("invalid frame transform")
+
+// FrameService 坐标系变换与位姿换算服务
+type FrameService struct {
+	transformDAO dao.FrameTransformDAO
+}
+
+func NewFrameService(transformDAO dao.FrameTransformDAO) *FrameService {
+	return &FrameService{
+		transformDAO: transformDAO,
+	}
+}
+
+// CreateTransform 创建坐标系变换，两个坐标系已可经其他变换换算时拒绝创建，保证任意两坐标系之间的换算唯一
+func (s *FrameService) CreateTransform(ctx context.Context, req *dto.FrameTransformRequest, userName string) (*dto.FrameTransformResponse, error) {
+	logger.Info("creating frame transform in service", zap.String("parent", req.ParentFrame), zap.String("child", req.ChildFrame))
+
+	if err := s.checkTransform(ctx, 0, req); err != nil {
+		return nil, err
+	}
+
+	transform := &entity.FrameTransform{UserName: userName}
+	applyFrameTransformRequest(transform, req)
+	if err := s.transformDAO.Create(ctx, transform); err != nil {
+		logger.Error("failed to create frame transform in service", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("frame transform created successfully in service", zap.Uint("id", transform.ID))
+	return dto.NewFrameTransformResponseFromEntity(transform), nil
+}
+
+// UpdateTransform 更新坐标系变换，变换不存在时返回 nil
+func (s *FrameService) UpdateTransform(ctx context.Context, id uint, req *dto.FrameTransformRequest) (*dto.FrameTransformResponse, error) {
+	logger.Info("updating frame transform in service", zap.Uint("id", id))
+
+	transform, err := s.transformDAO.FindByID(ctx, id)
+	if err != nil || transform == nil {
+		return nil, err
+	}
+	if err := s.checkTransform(ctx, id, req); err != nil {
+		return nil, err
+	}
+
+	applyFrameTransformRequest(transform, req)
+	if err := s.transformDAO.Update(ctx, transform); err != nil {
+		logger.Error("failed to update frame transform in service", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	logger.Info("frame transform updated successfully in service", zap.Uint("id", id))
+	return dto.NewFrameTransformResponseFromEntity(transform), nil
+}
+
+// DeleteTransform 删除坐标系变换
+func (s *FrameService) DeleteTransform(ctx context.Context, id uint) error {
+	logger.Info("deleting frame transform in service", zap.Uint("id", id))
+	return s.transformDAO.Delete(ctx, id)
+}
+
+// GetTransform 根据ID获取坐标系变换，不存在时返回 nil
+func (s *FrameService) GetTransform(ctx context.Context, id uint) (*dto.FrameTransformResponse, error) {
+	logger.Debug("getting frame transform in service", zap.Uint("id", id))
+
+	transform, err := s.transformDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewFrameTransformResponseFromEntity(transform), nil
+}
+
+// ListTransforms 获取坐标系变换列表，frameName 不为空时只返回与该坐标系直接相连的变换
+func (s *FrameService) ListTransforms(ctx context.Context, frameName string) ([]*dto.FrameTransformResponse, error) {
+	logger.Debug("listing frame transforms in service", zap.String("frame", frameName))
+
+	transforms, err := s.transformDAO.FindAll(ctx, frameName)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*dto.FrameTransformResponse, 0, len(transforms))
+	for _, t := range transforms {
+		list = append(list, dto.NewFrameTransformResponseFromEntity(t))
+	}
+	return list, nil
+}
+
+// ListFrames 获取已登记变换涉及的全部坐标系
+func (s *FrameService) ListFrames(ctx context.Context) ([]string, error) {
+	graph, err := s.Graph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graph.Frames(), nil
+}
+
+// ConvertPoses 把位姿从一个坐标系换算到另一个坐标系，无法换算时返回 frame.ErrNoTransform
+func (s *FrameService) ConvertPoses(ctx context.Context, req *dto.FrameConvertRequest) (*dto.FrameConvertResponse, error) {
+	logger.Debug("converting poses in service", zap.String("from", req.From), zap.String("to", req.To), zap.Int("count", len(req.Poses)))
+
+	transform, err := s.Resolve(ctx, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	poses := make([]frame.Pose, len(req.Poses))
+	for i, p := range req.Poses {
+		poses[i] = transform.Apply(p)
+	}
+	return &dto.FrameConvertResponse{From: req.From, To: req.To, Transform: transform, Poses: poses}, nil
+}
+
+// Resolve 返回把 from 中的坐标换算到 to 的变换，无法换算时返回 frame.ErrNoTransform
+func (s *FrameService) Resolve(ctx context.Context, from, to string) (frame.Transform, error) {
+	if from == to {
+		return frame.Transform{}, nil
+	}
+	graph, err := s.Graph(ctx)
+	if err != nil {
+		return frame.Transform{}, err
+	}
+	return graph.Resolve(from, to)
+}
+
+// Graph 由全部已登记的变换构建坐标系图，需要多次换算时避免重复查询
+func (s *FrameService) Graph(ctx context.Context) (*frame.Graph, error) {
+	transforms, err := s.transformDAO.FindAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return frameGraph(transforms, 0), nil
+}
+
+// checkTransform 校验坐标系名称，并确认除 excludeID 外的已有变换不能在两坐标系之间换算
+func (s *FrameService) checkTransform(ctx context.Context, excludeID uint, req *dto.FrameTransformRequest) error {
+	for _, name := range []string{req.ParentFrame, req.ChildFrame} {
+		if strings.TrimSpace(name) != name || strings.ContainsAny(name, " \t\r\n") {
+			return fmt.Errorf("%w: 坐标系名称 %q 不能包含空白字符", ErrInvalidFrameTransform, name)
+		}
+	}
+	if req.ParentFrame == req.ChildFrame {
+		return fmt.Errorf("%w: 父子坐标系不能相同", ErrInvalidFrameTransform)
+	}
+
+	transforms, err := s.transformDAO.FindAll(ctx, "")
+	if err != nil {
+		return err
+	}
+	if _, err := frameGraph(transforms, excludeID).Resolve(req.ChildFrame, req.ParentFrame); err == nil {
+		logger.Warn("frame transform would create a cycle", zap.String("parent", req.ParentFrame), zap.String("child", req.ChildFrame))
+		return fmt.Errorf("%w: %s 与 %s 之间已可经其他变换换算", ErrInvalidFrameTransform, req.ChildFrame, req.ParentFrame)
+	}
+	return nil
+}
+
+// frameGraph 由变换记录构建坐标系图，跳过 excludeID 对应的记录
+func frameGraph(transforms []*entity.FrameTransform, excludeID uint) *frame.Graph {
+	edges := make([]frame.Edge, 0, len(transforms))
+	for _, t := range transforms {
+		if t.ID == excludeID {
+			continue
+		}
+		edges = append(edges, frame.Edge{
+			Parent:    t.ParentFrame,
+			Child:     t.ChildFrame,
+			Transform: frame.Transform{X: t.X, Y: t.Y, Z: t.Z, Roll: t.Roll, Pitch: t.Pitch, Yaw: t.Yaw},
+		})
+	}
+	return frame.NewGraph(edges)
+}
+
+func applyFrameTransformRequest(t *entity.FrameTransform, req *dto.FrameTransformRequest) {
+	t.ParentFrame, t.ChildFrame = req.ParentFrame, req.ChildFrame
+	t.X, t.Y, t.Z = req.X, req.Y, req.Z
+	t.Roll, t.Pitch, t.Yaw = req.Roll, req.Pitch, req.Yaw
+	t.Description = req.Description
+}
+
+// semanticPoseFunc 把坐标系变换包装为语义元素的平面位姿变换，语义元素位于地图坐标系的 z=0 平面
+func semanticPoseFunc(t frame.Transform) func(semantic.Pose) semantic.Pose {
+	return func(p semantic.Pose) semantic.Pose {
+		q := t.Apply(frame.Pose{X: p.X, Y: p.Y, Yaw: p.Yaw})
+		return semantic.Pose{X: q.X, Y: q.Y, Yaw: q.Yaw}
+	}
+}
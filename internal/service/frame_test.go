@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestFrameService_CreateTransformRejectsCycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransformDAO := mocks.NewMockFrameTransformDAO(ctrl)
+	service := NewFrameService(mockTransformDAO)
+	ctx := context.Background()
+
+	existing := []*entity.FrameTransform{
+		{Model: gorm.Model{ID: 1}, ParentFrame: "building/A", ChildFrame: "pcd/1"},
+		{Model: gorm.Model{ID: 2}, ParentFrame: "building/A", ChildFrame: "grid/2"},
+	}
+	mockTransformDAO.EXPECT().FindAll(ctx, "").Return(existing, nil).AnyTimes()
+
+	_, err := service.CreateTransform(ctx, &dto.FrameTransformRequest{ParentFrame: "pcd/1", ChildFrame: "grid/2"}, "tester")
+	if !errors.Is(err, ErrInvalidFrameTransform) {
+		t.Fatalf("Expected cycle to be rejected, got %v", err)
+	}
+	_, err = service.CreateTransform(ctx, &dto.FrameTransformRequest{ParentFrame: "pcd/1", ChildFrame: "pcd/1"}, "tester")
+	if !errors.Is(err, ErrInvalidFrameTransform) {
+		t.Fatalf("Expected identical frames to be rejected, got %v", err)
+	}
+
+	// 更新已有变换时不与自身比较
+	mockTransformDAO.EXPECT().FindByID(ctx, uint(2)).Return(existing[1], nil)
+	mockTransformDAO.EXPECT().Update(ctx, existing[1]).Return(nil)
+	resp, err := service.UpdateTransform(ctx, 2, &dto.FrameTransformRequest{ParentFrame: "building/A", ChildFrame: "grid/2", X: 3})
+	if err != nil || resp.Transform.X != 3 {
+		t.Fatalf("Expected update to succeed, got %+v (%v)", resp, err)
+	}
+}
+
+func TestFrameService_ConvertPoses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransformDAO := mocks.NewMockFrameTransformDAO(ctrl)
+	service := NewFrameService(mockTransformDAO)
+	ctx := context.Background()
+
+	// pcd/1 相对楼层平移 (10, 0)，grid/2 相对楼层旋转 90°
+	mockTransformDAO.EXPECT().FindAll(ctx, "").Return([]*entity.FrameTransform{
+		{Model: gorm.Model{ID: 1}, ParentFrame: "building/A", ChildFrame: "pcd/1", X: 10},
+		{Model: gorm.Model{ID: 2}, ParentFrame: "building/A", ChildFrame: "grid/2", Yaw: math.Pi / 2},
+	}, nil).Times(2)
+
+	resp, err := service.ConvertPoses(ctx, &dto.FrameConvertRequest{From: "pcd/1", To: "grid/2", Poses: []frame.Pose{{X: 1, Y: 2}}})
+	if err != nil {
+		t.Fatalf("ConvertPoses failed: %v", err)
+	}
+	// 楼层坐标 (11, 2)，旋转回 grid/2 为 (2, -11)，朝向 -90°
+	p := resp.Poses[0]
+	if math.Abs(p.X-2) > 1e-9 || math.Abs(p.Y+11) > 1e-9 || math.Abs(p.Yaw+math.Pi/2) > 1e-9 {
+		t.Errorf("Unexpected converted pose %+v", p)
+	}
+
+	_, err = service.ConvertPoses(ctx, &dto.FrameConvertRequest{From: "pcd/1", To: "pcd/9", Poses: []frame.Pose{{}}})
+	if !errors.Is(err, frame.ErrNoTransform) {
+		t.Errorf("Expected ErrNoTransform, got %v", err)
+	}
+}
+
+func TestTaskService_GetTaskByIDUsesPinnedVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTaskDAO := mocks.NewMockTaskDAO(ctrl)
+	mockVersionDAO := mocks.NewMockSemanticMapVersionDAO(ctrl)
+	mockTransformDAO := mocks.NewMockFrameTransformDAO(ctrl)
+	service := NewTaskService(mockTaskDAO, mocks.NewMockSemanticMapDAO(ctrl), mockVersionDAO, mocks.NewMockPCDFileDAO(ctrl), NewFrameService(mockTransformDAO))
+	ctx := context.Background()
+
+	// 任务固定在以点云 1 为底图的版本 1，语义地图当前已改用栅格 2 且内容不同
+	pcdFileID, gridID, pinned := uint(1), uint(2), 1
+	task := &entity.Task{Model: gorm.Model{ID: 5}, SemanticMapID: 9, SemanticMapVersion: &pinned}
+	task.SemanticMap = entity.SemanticMap{Model: gorm.Model{ID: 9}, OccupancyGridID: &gridID,
+		SemanticInfo: `{"pois":[{"id":"new","type":"generic","pose":{"x":0,"y":0,"yaw":0}}]}`}
+	mockTaskDAO.EXPECT().FindByID(ctx, uint(5)).Return(task, nil)
+	mockVersionDAO.EXPECT().FindByVersion(ctx, uint(9), 1).Return(&entity.SemanticMapVersion{SemanticMapID: 9, Version: 1, PCDFileID: &pcdFileID,
+		SemanticInfo: `{"pois":[{"id":"old","type":"generic","pose":{"x":1,"y":2,"yaw":0}}]}`}, nil)
+	mockTransformDAO.EXPECT().FindAll(ctx, "").Return([]*entity.FrameTransform{
+		{Model: gorm.Model{ID: 1}, ParentFrame: "building/A", ChildFrame: frame.PCDFrame(1), X: 10},
+	}, nil)
+
+	resp, err := service.GetTaskByID(ctx, 5, "building/A")
+	if err != nil {
+		t.Fatalf("GetTaskByID failed: %v", err)
+	}
+	if resp.Frame != "building/A" || resp.SemanticMap.PCDFileID == nil || resp.SemanticMap.OccupancyGridID != nil {
+		t.Fatalf("Expected pinned base in building/A, got frame %s map %+v", resp.Frame, resp.SemanticMap)
+	}
+	info, err := semantic.Parse(resp.SemanticMap.SemanticInfo)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(info.POIs) != 1 || info.POIs[0].ID != "old" || info.POIs[0].Pose.X != 11 || info.POIs[0].Pose.Y != 2 {
+		t.Errorf("Expected pinned content shifted to building/A, got %s", resp.SemanticMap.SemanticInfo)
+	}
+}
+
+func TestSemanticMapService_PlanPathUsesVersionBase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	mockVersionDAO := mocks.NewMockSemanticMapVersionDAO(ctrl)
+	mockTransformDAO := mocks.NewMockFrameTransformDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mockVersionDAO, mocks.NewMockPCDFileDAO(ctrl), mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mockTransformDAO))
+	ctx := context.Background()
+
+	// 版本 1 以点云 1 为底图，语义地图当前已改用栅格 2
+	pcdFileID, gridID, version := uint(1), uint(2), 1
+	route := `{"waypoints":[{"id":"a","x":0,"y":0},{"id":"b","x":5,"y":0}],"edges":[{"id":"a-b","from":"a","to":"b"}]}`
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(9)).Return(&entity.SemanticMap{Model: gorm.Model{ID: 9}, OccupancyGridID: &gridID, SemanticInfo: route}, nil)
+	mockVersionDAO.EXPECT().FindByVersion(ctx, uint(9), 1).Return(&entity.SemanticMapVersion{SemanticMapID: 9, Version: 1, PCDFileID: &pcdFileID, SemanticInfo: route}, nil)
+	mockTransformDAO.EXPECT().FindAll(ctx, "").Return([]*entity.FrameTransform{
+		{Model: gorm.Model{ID: 1}, ParentFrame: "building/A", ChildFrame: frame.PCDFrame(1), X: 10},
+	}, nil)
+
+	resp, err := service.PlanPath(ctx, 9, &dto.SemanticPlanRequest{From: "a", To: "b", Version: &version, Frame: "building/A"})
+	if err != nil {
+		t.Fatalf("PlanPath failed: %v", err)
+	}
+	if resp.Frame != "building/A" || len(resp.Waypoints) != 2 || resp.Waypoints[0].X != 10 || resp.Waypoints[1].X != 15 {
+		t.Errorf("Expected waypoints converted from the version's base, got frame %s %+v", resp.Frame, resp.Waypoints)
+	}
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidLocation 位置节点无效：节点不存在、上级层级不符或同级重名
+	ErrInvalidLocation = errors.New("invalid location")
+	// ErrLocationInUse 位置节点仍有下级节点或挂载的地图、设备，不能删除
+	ErrLocationInUse = errors.New("location in use")
+)
+
+// locationLevels 位置层级由上至下的顺序
+var locationLevels = []entity.LocationLevel{
+	entity.LocationLevelSite,
+	entity.LocationLevelBuilding,
+	entity.LocationLevelFloor,
+	entity.LocationLevelArea,
+}
+
+// parentLevel 返回层级对应的上级层级，园区没有上级
+func parentLevel(level entity.LocationLevel) (entity.LocationLevel, bool) {
+	for i, l := range locationLevels {
+		if l == level && i > 0 {
+			return locationLevels[i-1], true
+		}
+	}
+	return "", false
+}
+
+// LocationService 位置层级服务
+type LocationService struct {
+	locationDAO dao.LocationDAO
+}
+
+func NewLocationService(locationDAO dao.LocationDAO) *LocationService {
+	return &LocationService{
+		locationDAO: locationDAO,
+	}
+}
+
+// CreateLocation 创建位置节点
+func (s *LocationService) CreateLocation(ctx context.Context, req *dto.LocationCreateRequest, userName string) (*dto.LocationResponse, error) {
+	logger.Info("creating location in service", zap.String("name", req.Name), zap.String("level", string(req.Level)))
+
+	location := &entity.Location{
+		ParentID:    req.ParentID,
+		Level:       req.Level,
+		Name:        req.Name,
+		UserName:    userName,
+		Description: req.Description,
+	}
+	tree, err := s.loadTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tree.check(location); err != nil {
+		return nil, err
+	}
+
+	if err := s.locationDAO.Create(ctx, location); err != nil {
+		logger.Error("failed to create location in service", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("location created successfully in service", zap.Uint("id", location.ID))
+	return tree.response(location), nil
+}
+
+// UpdateLocation 更新位置节点，节点不存在时返回 nil
+func (s *LocationService) UpdateLocation(ctx context.Context, id uint, req *dto.LocationUpdateRequest) (*dto.LocationResponse, error) {
+	logger.Info("updating location in service", zap.Uint("id", id))
+
+	tree, err := s.loadTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	location, ok := tree.nodes[id]
+	if !ok {
+		return nil, nil
+	}
+
+	if req.ParentID != nil {
+		location.ParentID = req.ParentID
+	}
+	if req.Name != nil {
+		location.Name = *req.Name
+	}
+	if req.Description != nil {
+		location.Description = req.Description
+	}
+	if err := tree.check(location); err != nil {
+		return nil, err
+	}
+
+	if err := s.locationDAO.Update(ctx, location); err != nil {
+		logger.Error("failed to update location in service", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	logger.Info("location updated successfully in service", zap.Uint("id", id))
+	return tree.response(location), nil
+}
+
+// DeleteLocation 删除位置节点，仍有下级节点或挂载的地图、设备时返回 ErrLocationInUse
+func (s *LocationService) DeleteLocation(ctx context.Context, id uint) error {
+	logger.Info("deleting location in service", zap.Uint("id", id))
+
+	tree, err := s.loadTree(ctx)
+	if err != nil {
+		return err
+	}
+	if children := len(tree.children[id]); children > 0 {
+		return fmt.Errorf("%w: 仍有 %d 个下级节点", ErrLocationInUse, children)
+	}
+	attached, err := s.locationDAO.CountAttached(ctx, id)
+	if err != nil {
+		return err
+	}
+	if attached > 0 {
+		return fmt.Errorf("%w: 仍有 %d 个地图或设备挂载在该节点", ErrLocationInUse, attached)
+	}
+
+	return s.locationDAO.Delete(ctx, id)
+}
+
+// GetLocation 根据ID获取位置节点及其上级路径，不存在时返回 nil
+func (s *LocationService) GetLocation(ctx context.Context, id uint) (*dto.LocationResponse, error) {
+	logger.Debug("getting location in service", zap.Uint("id", id))
+
+	tree, err := s.loadTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	location, ok := tree.nodes[id]
+	if !ok {
+		return nil, nil
+	}
+	return tree.response(location), nil
+}
+
+// ListLocations 按上级节点与层级查询位置节点
+func (s *LocationService) ListLocations(ctx context.Context, req dto.LocationListRequest) ([]*dto.LocationResponse, error) {
+	logger.Debug("listing locations in service")
+
+	locations, err := s.locationDAO.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*dto.LocationResponse, 0, len(locations))
+	for _, l := range locations {
+		if req.ParentID != nil && (l.ParentID == nil || *l.ParentID != *req.ParentID) {
+			continue
+		}
+		if req.Level != nil && l.Level != *req.Level {
+			continue
+		}
+		list = append(list, dto.NewLocationResponseFromEntity(l))
+	}
+	return list, nil
+}
+
+// GetTree 获取位置树，rootID 为空时返回全部园区，rootID 对应的节点不存在时返回 nil
+func (s *LocationService) GetTree(ctx context.Context, rootID *uint) ([]*dto.LocationTreeNode, error) {
+	logger.Debug("getting location tree in service")
+
+	tree, err := s.loadTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rootID == nil {
+		roots := make([]*dto.LocationTreeNode, 0, len(tree.roots))
+		for _, r := range tree.roots {
+			roots = append(roots, tree.node(r))
+		}
+		return roots, nil
+	}
+	root, ok := tree.nodes[*rootID]
+	if !ok {
+		return nil, nil
+	}
+	return []*dto.LocationTreeNode{tree.node(root)}, nil
+}
+
+// locationTree 内存中的位置树，位置节点数量有限，按需整体加载
+type locationTree struct {
+	nodes    map[uint]*entity.Location
+	children map[uint][]*entity.Location
+	roots    []*entity.Location
+}
+
+func (s *LocationService) loadTree(ctx context.Context) (*locationTree, error) {
+	locations, err := s.locationDAO.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tree := &locationTree{
+		nodes:    make(map[uint]*entity.Location, len(locations)),
+		children: make(map[uint][]*entity.Location),
+	}
+	for _, l := range locations {
+		tree.nodes[l.ID] = l
+		if l.ParentID == nil {
+			tree.roots = append(tree.roots, l)
+		} else {
+			tree.children[*l.ParentID] = append(tree.children[*l.ParentID], l)
+		}
+	}
+	return tree, nil
+}
+
+// check 校验节点的上级层级与同级名称，location.ID 为 0 表示新建
+func (t *locationTree) check(location *entity.Location) error {
+	want, hasParent := parentLevel(location.Level)
+	siblings := t.roots
+	switch {
+	case !hasParent && location.ParentID != nil:
+		return fmt.Errorf("%w: 园区不能有上级节点", ErrInvalidLocation)
+	case hasParent && location.ParentID == nil:
+		return fmt.Errorf("%w: %s 必须指定 %s 作为上级节点", ErrInvalidLocation, location.Level, want)
+	case hasParent:
+		parent, ok := t.nodes[*location.ParentID]
+		if !ok {
+			return fmt.Errorf("%w: 上级节点 %d 不存在", ErrInvalidLocation, *location.ParentID)
+		}
+		if parent.Level != want {
+			return fmt.Errorf("%w: %s 的上级节点必须是 %s，而节点 %d 是 %s", ErrInvalidLocation, location.Level, want, parent.ID, parent.Level)
+		}
+		siblings = t.children[parent.ID]
+	}
+
+	for _, sibling := range siblings {
+		if sibling.ID != location.ID && sibling.Name == location.Name {
+			return fmt.Errorf("%w: 同一上级下已存在名为 %q 的节点", ErrInvalidLocation, location.Name)
+		}
+	}
+	return nil
+}
+
+// response 构建节点响应并附带从园区到上级节点的路径
+func (t *locationTree) response(location *entity.Location) *dto.LocationResponse {
+	resp := dto.NewLocationResponseFromEntity(location)
+	for id := location.ParentID; id != nil; {
+		parent, ok := t.nodes[*id]
+		if !ok {
+			break
+		}
+		resp.Path = append([]*dto.LocationBrief{dto.NewLocationBriefFromEntity(parent)}, resp.Path...)
+		id = parent.ParentID
+	}
+	return resp
+}
+
+func (t *locationTree) node(location *entity.Location) *dto.LocationTreeNode {
+	node := &dto.LocationTreeNode{
+		ID:          location.ID,
+		Level:       location.Level,
+		Name:        location.Name,
+		Description: location.Description,
+		Children:    make([]*dto.LocationTreeNode, 0, len(t.children[location.ID])),
+	}
+	for _, child := range t.children[location.ID] {
+		node.Children = append(node.Children, t.node(child))
+	}
+	return node
+}
+
+// resolveLocationID 校验请求中的位置节点ID：nil 表示不修改，0 表示解除挂载，其余须为已存在的节点
+func resolveLocationID(ctx context.Context, locationDAO dao.LocationDAO, id *uint) (*uint, error) {
+	if id == nil || *id == 0 {
+		return nil, nil
+	}
+	location, err := locationDAO.FindByID(ctx, *id)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, fmt.Errorf("%w: 位置节点 %d 不存在", ErrInvalidLocation, *id)
+	}
+	return &location.ID, nil
+}
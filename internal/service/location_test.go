@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func testLocations() []*entity.Location {
+	site, building, floor := uint(1), uint(2), uint(3)
+	return []*entity.Location{
+		{Model: gorm.Model{ID: site}, Level: entity.LocationLevelSite, Name: "A"},
+		{Model: gorm.Model{ID: building}, ParentID: &site, Level: entity.LocationLevelBuilding, Name: "1"},
+		{Model: gorm.Model{ID: floor}, ParentID: &building, Level: entity.LocationLevelFloor, Name: "3F"},
+		{Model: gorm.Model{ID: 4}, ParentID: &floor, Level: entity.LocationLevelArea, Name: "仓储区"},
+	}
+}
+
+func TestLocationService_CreateLocationChecksHierarchy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLocationDAO := mocks.NewMockLocationDAO(ctrl)
+	service := NewLocationService(mockLocationDAO)
+	ctx := context.Background()
+
+	mockLocationDAO.EXPECT().FindAll(ctx).Return(testLocations(), nil).AnyTimes()
+
+	site, building, floor := uint(1), uint(2), uint(3)
+	cases := []dto.LocationCreateRequest{
+		{Level: entity.LocationLevelSite, Name: "B", ParentID: &site},       // 园区不能有上级
+		{Level: entity.LocationLevelBuilding, Name: "2"},                    // 楼栋必须有上级
+		{Level: entity.LocationLevelFloor, Name: "1F", ParentID: &site},     // 楼层的上级必须是楼栋
+		{Level: entity.LocationLevelArea, Name: "仓储区", ParentID: &floor},    // 同级重名
+		{Level: entity.LocationLevelArea, Name: "装卸区", ParentID: new(uint)}, // 上级不存在
+	}
+	for i := range cases {
+		if _, err := service.CreateLocation(ctx, &cases[i], "tester"); !errors.Is(err, ErrInvalidLocation) {
+			t.Errorf("case %d: expected ErrInvalidLocation, got %v", i, err)
+		}
+	}
+
+	mockLocationDAO.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, l *entity.Location) error {
+		l.ID = 5
+		return nil
+	})
+	resp, err := service.CreateLocation(ctx, &dto.LocationCreateRequest{Level: entity.LocationLevelFloor, Name: "4F", ParentID: &building}, "tester")
+	if err != nil {
+		t.Fatalf("CreateLocation failed: %v", err)
+	}
+	if len(resp.Path) != 2 || resp.Path[0].Name != "A" || resp.Path[1].Name != "1" {
+		t.Errorf("Expected path A / 1, got %+v", resp.Path)
+	}
+}
+
+func TestLocationService_DeleteLocationInUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLocationDAO := mocks.NewMockLocationDAO(ctrl)
+	service := NewLocationService(mockLocationDAO)
+	ctx := context.Background()
+
+	mockLocationDAO.EXPECT().FindAll(ctx).Return(testLocations(), nil).AnyTimes()
+
+	// 仍有下级节点
+	if err := service.DeleteLocation(ctx, 3); !errors.Is(err, ErrLocationInUse) {
+		t.Errorf("Expected ErrLocationInUse for node with children, got %v", err)
+	}
+	// 仍有挂载
+	mockLocationDAO.EXPECT().CountAttached(ctx, uint(4)).Return(int64(1), nil)
+	if err := service.DeleteLocation(ctx, 4); !errors.Is(err, ErrLocationInUse) {
+		t.Errorf("Expected ErrLocationInUse for node with attachments, got %v", err)
+	}
+
+	mockLocationDAO.EXPECT().CountAttached(ctx, uint(4)).Return(int64(0), nil)
+	mockLocationDAO.EXPECT().Delete(ctx, uint(4)).Return(nil)
+	if err := service.DeleteLocation(ctx, 4); err != nil {
+		t.Errorf("DeleteLocation failed: %v", err)
+	}
+}
+
+func TestLocationService_GetTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLocationDAO := mocks.NewMockLocationDAO(ctrl)
+	service := NewLocationService(mockLocationDAO)
+	ctx := context.Background()
+
+	mockLocationDAO.EXPECT().FindAll(ctx).Return(testLocations(), nil).AnyTimes()
+
+	tree, err := service.GetTree(ctx, nil)
+	if err != nil || len(tree) != 1 {
+		t.Fatalf("Expected one site, got %d (%v)", len(tree), err)
+	}
+	area := tree[0].Children[0].Children[0].Children[0]
+	if area.Name != "仓储区" || len(area.Children) != 0 {
+		t.Errorf("Expected area leaf at depth 3, got %+v", area)
+	}
+
+	floor := uint(3)
+	sub, _ := service.GetTree(ctx, &floor)
+	if len(sub) != 1 || sub[0].Level != entity.LocationLevelFloor || len(sub[0].Children) != 1 {
+		t.Errorf("Expected subtree rooted at the floor, got %+v", sub)
+	}
+	missing := uint(99)
+	if sub, err := service.GetTree(ctx, &missing); err != nil || sub != nil {
+		t.Errorf("Expected nil for missing root, got %+v (%v)", sub, err)
+	}
+}
@@ -0,0 +1,522 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync/atomic"
+	"time"
+
+	"robot_scheduler/internal/config"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/driver"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/storage"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidMapDeployment 地图下发参数无效：地图或版本不存在、没有底图、设备未配置地址或驱动未注册
+	ErrInvalidMapDeployment = errors.New("invalid map deployment")
+	// ErrMapDeploymentInProgress 设备已有未结束的地图下发
+	ErrMapDeploymentInProgress = errors.New("map deployment in progress")
+)
+
+const (
+	// mapDeployProgressInterval 上传进度写库的间隔
+	mapDeployProgressInterval = time.Second
+	// mapManifestName 地图包清单文件名，最后上传，设备据此判断地图包完整
+	mapManifestName = "manifest.json"
+)
+
+// MapDeploymentService 地图下发服务，把语义地图某个版本及其底图通过厂商驱动推送到设备，
+// 成功后记录设备当前加载的地图版本
+type MapDeploymentService struct {
+	deploymentDAO      dao.MapDeploymentDAO
+	deviceDAO          dao.DeviceDAO
+	deviceModelDAO     dao.DeviceModelDAO
+	semanticDAO        dao.SemanticMapDAO
+	semanticVersionDAO dao.SemanticMapVersionDAO
+	pcdDAO             dao.PCDFileDAO
+	pcdVersionDAO      dao.PCDFileVersionDAO
+	gridDAO            dao.OccupancyGridDAO
+}
+
+func NewMapDeploymentService(deploymentDAO dao.MapDeploymentDAO, deviceDAO dao.DeviceDAO, deviceModelDAO dao.DeviceModelDAO,
+	semanticDAO dao.SemanticMapDAO, semanticVersionDAO dao.SemanticMapVersionDAO, pcdDAO dao.PCDFileDAO,
+	pcdVersionDAO dao.PCDFileVersionDAO, gridDAO dao.OccupancyGridDAO) *MapDeploymentService {
+	return &MapDeploymentService{
+		deploymentDAO:      deploymentDAO,
+		deviceDAO:          deviceDAO,
+		deviceModelDAO:     deviceModelDAO,
+		semanticDAO:        semanticDAO,
+		semanticVersionDAO: semanticVersionDAO,
+		pcdDAO:             pcdDAO,
+		pcdVersionDAO:      pcdVersionDAO,
+		gridDAO:            gridDAO,
+	}
+}
+
+// DeployMap 创建地图下发记录，由后台任务上传到设备；设备不存在时返回 nil
+func (s *MapDeploymentService) DeployMap(ctx context.Context, deviceID uint, userName string, req *dto.MapDeployRequest) (*dto.MapDeploymentResponse, error) {
+	logger.Info("deploying map to device in service", zap.Uint("deviceID", deviceID), zap.Uint("semanticMapID", req.SemanticMapID))
+
+	device, err := s.deviceDAO.FindByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device == nil {
+		return nil, nil
+	}
+	if device.IP == nil || *device.IP == "" {
+		return nil, fmt.Errorf("%w: 设备未配置IP", ErrInvalidMapDeployment)
+	}
+
+	driverName, err := s.driverName(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := driver.Lookup(driverName); !ok {
+		return nil, fmt.Errorf("%w: 未注册的下发驱动 %q，可用驱动 %v", ErrInvalidMapDeployment, driverName, driver.Names())
+	}
+
+	version, err := s.resolveVersion(ctx, req.SemanticMapID, req.SemanticMapVersion)
+	if err != nil {
+		return nil, err
+	}
+	if version.PCDFileID == nil && version.OccupancyGridID == nil {
+		return nil, fmt.Errorf("%w: 语义地图版本 %d 没有底图", ErrInvalidMapDeployment, version.Version)
+	}
+
+	active, err := s.deploymentDAO.FindActive(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		logger.Warn("map deployment already in progress", zap.Uint("deviceID", deviceID), zap.Uint("deploymentID", active.ID))
+		return nil, fmt.Errorf("%w: 下发记录 %d 尚未结束", ErrMapDeploymentInProgress, active.ID)
+	}
+
+	deployment := &entity.MapDeployment{
+		DeviceID:           deviceID,
+		SemanticMapID:      version.SemanticMapID,
+		SemanticMapVersion: version.Version,
+		PCDFileID:          version.PCDFileID,
+		PCDFileVersion:     version.PCDFileVersion,
+		OccupancyGridID:    version.OccupancyGridID,
+		Driver:             driverName,
+		UserName:           userName,
+		Status:             entity.MapDeploymentStatusPending,
+	}
+	if err := s.deploymentDAO.Create(ctx, deployment); err != nil {
+		return nil, err
+	}
+
+	logger.Info("map deployment queued in service", zap.Uint("id", deployment.ID), zap.String("driver", driverName))
+	return dto.NewMapDeploymentResponseFromEntity(deployment), nil
+}
+
+// GetDeployment 获取设备的下发记录，记录不存在或不属于该设备时返回 nil
+func (s *MapDeploymentService) GetDeployment(ctx context.Context, deviceID, id uint) (*dto.MapDeploymentResponse, error) {
+	deployment, err := s.deploymentDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if deployment == nil || deployment.DeviceID != deviceID {
+		return nil, nil
+	}
+	return dto.NewMapDeploymentResponseFromEntity(deployment), nil
+}
+
+// ListDeployments 按创建时间倒序获取设备的下发记录，设备不存在时返回 nil
+func (s *MapDeploymentService) ListDeployments(ctx context.Context, deviceID uint) ([]*dto.MapDeploymentResponse, error) {
+	device, err := s.deviceDAO.FindByID(ctx, deviceID)
+	if err != nil || device == nil {
+		return nil, err
+	}
+	deployments, err := s.deploymentDAO.ListByDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*dto.MapDeploymentResponse, 0, len(deployments))
+	for _, d := range deployments {
+		list = append(list, dto.NewMapDeploymentResponseFromEntity(d))
+	}
+	return list, nil
+}
+
+// Run 轮询并依次执行等待中的下发，直到 ctx 取消
+func (s *MapDeploymentService) Run(ctx context.Context) {
+	settings := deploySettings()
+	logger.Info("starting map deployment worker", zap.Duration("pollInterval", settings.pollInterval))
+
+	if _, err := s.deploymentDAO.ResetRunning(ctx); err != nil {
+		logger.Warn("failed to reset interrupted map deployments", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(settings.pollInterval)
+	defer ticker.Stop()
+	for {
+		// 有等待的下发时连续执行，队列空了再等待
+		for {
+			ran, err := s.RunOnce(ctx)
+			if err != nil {
+				logger.Warn("map deployment worker error", zap.Error(err))
+			}
+			if !ran || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 领取并执行一个下发，没有等待中的下发时返回 false
+func (s *MapDeploymentService) RunOnce(ctx context.Context) (bool, error) {
+	deployment, err := s.deploymentDAO.ClaimNext(ctx)
+	if err != nil || deployment == nil {
+		return false, err
+	}
+
+	logger.Info("running map deployment", zap.Uint("id", deployment.ID), zap.Uint("deviceID", deployment.DeviceID),
+		zap.Uint("semanticMapID", deployment.SemanticMapID), zap.Int("version", deployment.SemanticMapVersion))
+
+	if err := s.deploy(ctx, deployment); err != nil {
+		// 服务停止导致的中断保持上传中，重启后重新下发
+		if ctx.Err() != nil {
+			return true, nil
+		}
+		logger.Warn("map deployment failed", zap.Error(err), zap.Uint("id", deployment.ID))
+		msg := err.Error()
+		deployment.Status = entity.MapDeploymentStatusFailed
+		deployment.Error = &msg
+		return true, s.deploymentDAO.Finish(ctx, deployment)
+	}
+
+	now := time.Now()
+	device := &entity.Device{
+		LoadedSemanticMapID:      &deployment.SemanticMapID,
+		LoadedSemanticMapVersion: &deployment.SemanticMapVersion,
+		LoadedDeploymentID:       &deployment.ID,
+		MapLoadedAt:              &now,
+	}
+	device.ID = deployment.DeviceID
+	if err := s.deviceDAO.UpdateLoadedMap(ctx, device); err != nil {
+		msg := "地图已上传，记录设备加载的地图失败: " + err.Error()
+		deployment.Status = entity.MapDeploymentStatusFailed
+		deployment.Error = &msg
+		return true, s.deploymentDAO.Finish(ctx, deployment)
+	}
+
+	deployment.Status = entity.MapDeploymentStatusSucceeded
+	logger.Info("map deployment succeeded", zap.Uint("id", deployment.ID), zap.Uint("deviceID", deployment.DeviceID))
+	return true, s.deploymentDAO.Finish(ctx, deployment)
+}
+
+// deploy 组装地图包并通过驱动上传，上传期间按固定间隔保存进度
+func (s *MapDeploymentService) deploy(ctx context.Context, deployment *entity.MapDeployment) error {
+	device, err := s.deviceDAO.FindByID(ctx, deployment.DeviceID)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		return errors.New("device not found")
+	}
+	if device.IP == nil || *device.IP == "" {
+		return errors.New("设备未配置IP")
+	}
+	d, ok := driver.Lookup(deployment.Driver)
+	if !ok {
+		return fmt.Errorf("未注册的下发驱动 %q", deployment.Driver)
+	}
+
+	pkg, err := s.buildPackage(ctx, deployment)
+	if err != nil {
+		return err
+	}
+	deployment.TotalBytes = pkg.Size()
+	if err := s.deploymentDAO.UpdateProgress(ctx, deployment.ID, 0, deployment.TotalBytes); err != nil {
+		return err
+	}
+
+	var sent atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(mapDeployProgressInterval)
+		defer ticker.Stop()
+		var saved int64
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if n := sent.Load(); n != saved {
+					if err := s.deploymentDAO.UpdateProgress(ctx, deployment.ID, n, deployment.TotalBytes); err == nil {
+						saved = n
+					}
+				}
+			}
+		}
+	}()
+
+	settings := deploySettings()
+	target := driver.Target{
+		Host:    *device.IP,
+		Port:    device.Port,
+		Options: settings.options,
+	}
+	if device.UserName != nil {
+		target.UserName = *device.UserName
+	}
+	if device.Password != nil {
+		target.Password = *device.Password
+	}
+	err = d.Deploy(ctx, target, pkg, func(n int64) { sent.Store(n) })
+	close(done)
+	deployment.SentBytes = sent.Load()
+	return err
+}
+
+// buildPackage 组装地图包：点云、栅格、语义信息，最后是清单文件
+func (s *MapDeploymentService) buildPackage(ctx context.Context, deployment *entity.MapDeployment) (*driver.Package, error) {
+	version, err := s.semanticVersionDAO.FindByVersion(ctx, deployment.SemanticMapID, deployment.SemanticMapVersion)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		return nil, fmt.Errorf("语义地图 %d 不存在版本 %d", deployment.SemanticMapID, deployment.SemanticMapVersion)
+	}
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := mapManifest{
+		DeploymentID:       deployment.ID,
+		SemanticMapID:      deployment.SemanticMapID,
+		SemanticMapVersion: deployment.SemanticMapVersion,
+		PCDFileID:          deployment.PCDFileID,
+		PCDFileVersion:     deployment.PCDFileVersion,
+		OccupancyGridID:    deployment.OccupancyGridID,
+		CreatedAt:          time.Now(),
+	}
+	pkg := &driver.Package{Name: fmt.Sprintf("semantic_%d_v%d", deployment.SemanticMapID, deployment.SemanticMapVersion)}
+	addObject := func(name, key string) error {
+		info, err := store.Stat(ctx, key)
+		if err != nil {
+			return fmt.Errorf("读取地图对象 %s 失败: %w", key, err)
+		}
+		pkg.Files = append(pkg.Files, objectFile(store, name, key, info.Size))
+		manifest.Files = append(manifest.Files, mapManifestFile{Name: name, Size: info.Size})
+		return nil
+	}
+
+	if deployment.PCDFileID != nil {
+		key, sha256, err := s.pcdObject(ctx, *deployment.PCDFileID, deployment.PCDFileVersion)
+		if err != nil {
+			return nil, err
+		}
+		if err := addObject("map.pcd", key); err != nil {
+			return nil, err
+		}
+		manifest.Files[len(manifest.Files)-1].SHA256 = sha256
+	}
+	if deployment.OccupancyGridID != nil {
+		grid, err := s.gridDAO.FindByID(ctx, *deployment.OccupancyGridID)
+		if err != nil {
+			return nil, err
+		}
+		if grid == nil {
+			return nil, fmt.Errorf("占据栅格 %d 不存在", *deployment.OccupancyGridID)
+		}
+		// YAML 中的 image 字段引用图像的文件名，保持原文件名
+		if err := addObject(path.Base(grid.ImagePath), grid.ImagePath); err != nil {
+			return nil, err
+		}
+		if err := addObject(path.Base(grid.YAMLPath), grid.YAMLPath); err != nil {
+			return nil, err
+		}
+	}
+
+	semanticInfo := []byte(version.SemanticInfo)
+	pkg.Files = append(pkg.Files, bytesFile("semantic.json", semanticInfo))
+	manifest.Files = append(manifest.Files, mapManifestFile{Name: "semantic.json", Size: int64(len(semanticInfo))})
+
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	pkg.Files = append(pkg.Files, bytesFile(mapManifestName, raw))
+	return pkg, nil
+}
+
+// pcdObject 查询点云地图指定版本的对象 Key 与 SHA-256，未记录版本时使用当前文件
+func (s *MapDeploymentService) pcdObject(ctx context.Context, pcdFileID uint, version *int) (string, *string, error) {
+	if version != nil {
+		v, err := s.pcdVersionDAO.FindByVersion(ctx, pcdFileID, *version)
+		if err != nil {
+			return "", nil, err
+		}
+		if v == nil {
+			return "", nil, fmt.Errorf("点云地图 %d 不存在版本 %d", pcdFileID, *version)
+		}
+		if v.MinioPath == nil || *v.MinioPath == "" {
+			return "", nil, fmt.Errorf("点云地图 %d 版本 %d 没有存储对象", pcdFileID, *version)
+		}
+		return *v.MinioPath, v.SHA256, nil
+	}
+
+	file, err := s.pcdDAO.FindByID(ctx, pcdFileID)
+	if err != nil {
+		return "", nil, err
+	}
+	if file == nil {
+		return "", nil, fmt.Errorf("点云地图 %d 不存在", pcdFileID)
+	}
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		return "", nil, fmt.Errorf("点云地图 %d 没有存储对象", pcdFileID)
+	}
+	return *file.MinioPath, file.SHA256, nil
+}
+
+// resolveVersion 确定下发的语义地图版本：指定版本时校验其存在，否则取地图的当前版本
+// 版本管理上线前创建的地图没有版本，此时先把其内容保存为第一个版本
+func (s *MapDeploymentService) resolveVersion(ctx context.Context, semanticMapID uint, requested *int) (*entity.SemanticMapVersion, error) {
+	if requested == nil {
+		semanticMap, err := s.semanticDAO.FindByID(ctx, semanticMapID)
+		if err != nil {
+			return nil, err
+		}
+		if semanticMap == nil {
+			return nil, fmt.Errorf("%w: 语义地图 %d 不存在", ErrInvalidMapDeployment, semanticMapID)
+		}
+		if semanticMap.CurrentVersion == nil {
+			if err := recordSemanticMapVersion(ctx, s.semanticVersionDAO, s.pcdDAO, semanticMap, semanticMap.UserName, nil); err != nil {
+				logger.Error("failed to save base semantic map version", zap.Error(err), zap.Uint("semanticMapID", semanticMapID))
+				return nil, err
+			}
+		}
+		requested = semanticMap.CurrentVersion
+	}
+
+	version, err := s.semanticVersionDAO.FindByVersion(ctx, semanticMapID, *requested)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		return nil, fmt.Errorf("%w: 语义地图 %d 不存在版本 %d", ErrInvalidMapDeployment, semanticMapID, *requested)
+	}
+	return version, nil
+}
+
+// driverName 设备使用的下发驱动：型号登记的驱动优先，否则使用配置的默认驱动
+func (s *MapDeploymentService) driverName(ctx context.Context, device *entity.Device) (string, error) {
+	if device.ModelID != nil {
+		model, err := s.deviceModelDAO.FindByID(ctx, *device.ModelID)
+		if err != nil {
+			return "", err
+		}
+		if model != nil && model.DriverName != "" {
+			return model.DriverName, nil
+		}
+	}
+	return deploySettings().defaultDriver, nil
+}
+
+// mapManifest 地图包清单
+type mapManifest struct {
+	DeploymentID       uint              `json:"deploymentId"`
+	SemanticMapID      uint              `json:"semanticMapId"`
+	SemanticMapVersion int               `json:"semanticMapVersion"`
+	PCDFileID          *uint             `json:"pcdFileId,omitempty"`
+	PCDFileVersion     *int              `json:"pcdFileVersion,omitempty"`
+	OccupancyGridID    *uint             `json:"occupancyGridId,omitempty"`
+	Files              []mapManifestFile `json:"files"`
+	CreatedAt          time.Time         `json:"createdAt"`
+}
+
+// mapManifestFile 地图包清单中的文件
+type mapManifestFile struct {
+	Name   string  `json:"name"`
+	Size   int64   `json:"size"`
+	SHA256 *string `json:"sha256,omitempty"`
+}
+
+// objectFile 以对象存储中的对象作为地图包文件
+func objectFile(store storage.Storage, name, key string, size int64) driver.File {
+	return driver.File{
+		Name: name,
+		Size: size,
+		Open: func(ctx context.Context) (io.ReadCloser, error) {
+			object, _, err := store.Get(ctx, key)
+			return object, err
+		},
+	}
+}
+
+// bytesFile 以内存数据作为地图包文件
+func bytesFile(name string, data []byte) driver.File {
+	return driver.File{
+		Name: name,
+		Size: int64(len(data)),
+		Open: func(ctx context.Context) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+	}
+}
+
+// mapDeploySettings 地图下发参数
+type mapDeploySettings struct {
+	defaultDriver string
+	pollInterval  time.Duration
+	options       driver.Options
+}
+
+// deploySettings 读取地图下发配置，未配置的项使用默认值
+func deploySettings() mapDeploySettings {
+	settings := mapDeploySettings{
+		defaultDriver: "http",
+		pollInterval:  5 * time.Second,
+		options: driver.Options{
+			Timeout:   10 * time.Second,
+			SFTPPort:  22,
+			RemoteDir: "maps",
+		},
+	}
+	cfg := config.Get()
+	if cfg == nil || cfg.Deploy == nil {
+		return settings
+	}
+	d := cfg.Deploy
+	if d.DefaultDriver != "" {
+		settings.defaultDriver = d.DefaultDriver
+	}
+	if d.PollInterval > 0 {
+		settings.pollInterval = time.Duration(d.PollInterval) * time.Second
+	}
+	if d.Timeout > 0 {
+		settings.options.Timeout = time.Duration(d.Timeout) * time.Second
+	}
+	if d.SFTPPort > 0 {
+		settings.options.SFTPPort = d.SFTPPort
+	}
+	if d.RemoteDir != "" {
+		settings.options.RemoteDir = d.RemoteDir
+	}
+	settings.options.HTTPS = d.HTTPS
+	settings.options.CAFile = d.CAFile
+	settings.options.KnownHosts = d.KnownHosts
+	settings.options.HostKey = d.HostKey
+	return settings
+}
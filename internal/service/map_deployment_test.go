@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/storage"
+	"robot_scheduler/internal/testutil/mocks"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type mapDeploymentMocks struct {
+	deploymentDAO      *mocks.MockMapDeploymentDAO
+	deviceDAO          *mocks.MockDeviceDAO
+	semanticDAO        *mocks.MockSemanticMapDAO
+	semanticVersionDAO *mocks.MockSemanticMapVersionDAO
+	pcdVersionDAO      *mocks.MockPCDFileVersionDAO
+}
+
+func newTestMapDeploymentService(ctrl *gomock.Controller) (*MapDeploymentService, mapDeploymentMocks) {
+	m := mapDeploymentMocks{
+		deploymentDAO:      mocks.NewMockMapDeploymentDAO(ctrl),
+		deviceDAO:          mocks.NewMockDeviceDAO(ctrl),
+		semanticDAO:        mocks.NewMockSemanticMapDAO(ctrl),
+		semanticVersionDAO: mocks.NewMockSemanticMapVersionDAO(ctrl),
+		pcdVersionDAO:      mocks.NewMockPCDFileVersionDAO(ctrl),
+	}
+	s := NewMapDeploymentService(m.deploymentDAO, m.deviceDAO, mocks.NewMockDeviceModelDAO(ctrl), m.semanticDAO,
+		m.semanticVersionDAO, mocks.NewMockPCDFileDAO(ctrl), m.pcdVersionDAO, mocks.NewMockOccupancyGridDAO(ctrl))
+	return s, m
+}
+
+func TestMapDeploymentService_DeployMap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestMapDeploymentService(ctrl)
+	ctx := context.Background()
+
+	ip := "10.0.0.8"
+	device := &entity.Device{Model: gorm.Model{ID: 1}, IP: &ip, Port: 8080}
+	pcdFileID, pcdVersion, current := uint(5), 2, 3
+	semanticMap := &entity.SemanticMap{Model: gorm.Model{ID: 9}, CurrentVersion: &current}
+	version := &entity.SemanticMapVersion{SemanticMapID: 9, Version: 3, PCDFileID: &pcdFileID, PCDFileVersion: &pcdVersion}
+
+	m.deviceDAO.EXPECT().FindByID(ctx, uint(2)).Return(nil, nil)
+	if resp, err := service.DeployMap(ctx, 2, "admin", &dto.MapDeployRequest{SemanticMapID: 9}); err != nil || resp != nil {
+		t.Fatalf("Expected nil for missing device, got %+v (%v)", resp, err)
+	}
+
+	m.deviceDAO.EXPECT().FindByID(ctx, uint(3)).Return(&entity.Device{Model: gorm.Model{ID: 3}}, nil)
+	if _, err := service.DeployMap(ctx, 3, "admin", &dto.MapDeployRequest{SemanticMapID: 9}); !errors.Is(err, ErrInvalidMapDeployment) {
+		t.Errorf("Expected device without ip to be rejected, got %v", err)
+	}
+
+	m.deviceDAO.EXPECT().FindByID(ctx, uint(1)).Return(device, nil).AnyTimes()
+	missing := 7
+	m.semanticVersionDAO.EXPECT().FindByVersion(ctx, uint(9), 7).Return(nil, nil)
+	if _, err := service.DeployMap(ctx, 1, "admin", &dto.MapDeployRequest{SemanticMapID: 9, SemanticMapVersion: &missing}); !errors.Is(err, ErrInvalidMapDeployment) {
+		t.Errorf("Expected missing version to be rejected, got %v", err)
+	}
+
+	m.semanticDAO.EXPECT().FindByID(ctx, uint(9)).Return(semanticMap, nil).Times(2)
+	m.semanticVersionDAO.EXPECT().FindByVersion(ctx, uint(9), 3).Return(version, nil).Times(2)
+	m.deploymentDAO.EXPECT().FindActive(ctx, uint(1)).Return(&entity.MapDeployment{Model: gorm.Model{ID: 4}}, nil)
+	if _, err := service.DeployMap(ctx, 1, "admin", &dto.MapDeployRequest{SemanticMapID: 9}); !errors.Is(err, ErrMapDeploymentInProgress) {
+		t.Errorf("Expected ErrMapDeploymentInProgress, got %v", err)
+	}
+
+	m.deploymentDAO.EXPECT().FindActive(ctx, uint(1)).Return(nil, nil)
+	m.deploymentDAO.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d *entity.MapDeployment) error {
+		d.ID = 10
+		return nil
+	})
+	resp, err := service.DeployMap(ctx, 1, "admin", &dto.MapDeployRequest{SemanticMapID: 9})
+	if err != nil {
+		t.Fatalf("DeployMap failed: %v", err)
+	}
+	if resp.ID != 10 || resp.Status != entity.MapDeploymentStatusPending || resp.Driver != "http" || resp.SemanticMapVersion != 3 ||
+		resp.PCDFileVersion == nil || *resp.PCDFileVersion != pcdVersion {
+		t.Errorf("Unexpected deployment %+v", resp)
+	}
+}
+
+func TestMapDeploymentService_RunOnceUploadsPackage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local, err := storage.NewLocal(storage.LocalConfig{Root: t.TempDir(), Secret: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	storage.SetBackend(local)
+	defer storage.SetBackend(nil)
+
+	ctx := context.Background()
+	pcdKey := "pcd/admin/1_floor.pcd"
+	if err := local.Put(ctx, pcdKey, strings.NewReader("pcd-data"), 8, ""); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	var (
+		mu       sync.Mutex
+		received = map[string]string{}
+		order    []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "robot" || pass != "pw" || r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received[r.URL.Path] = string(body)
+		order = append(order, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer server.Close()
+	host, portStr, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	service, m := newTestMapDeploymentService(ctrl)
+	userName, password := "robot", "pw"
+	device := &entity.Device{Model: gorm.Model{ID: 1}, IP: &host, Port: port, UserName: &userName, Password: &password}
+	pcdFileID, pcdVersion := uint(5), 2
+	deployment := &entity.MapDeployment{Model: gorm.Model{ID: 10}, DeviceID: 1, SemanticMapID: 9, SemanticMapVersion: 3,
+		PCDFileID: &pcdFileID, PCDFileVersion: &pcdVersion, Driver: "http", Status: entity.MapDeploymentStatusRunning}
+
+	m.deploymentDAO.EXPECT().ClaimNext(ctx).Return(deployment, nil)
+	m.deviceDAO.EXPECT().FindByID(ctx, uint(1)).Return(device, nil)
+	m.semanticVersionDAO.EXPECT().FindByVersion(ctx, uint(9), 3).Return(&entity.SemanticMapVersion{SemanticMapID: 9, Version: 3, SemanticInfo: `{"poi":[]}`}, nil)
+	m.pcdVersionDAO.EXPECT().FindByVersion(ctx, pcdFileID, pcdVersion).Return(&entity.PCDFileVersion{MinioPath: &pcdKey}, nil)
+	m.deploymentDAO.EXPECT().UpdateProgress(gomock.Any(), uint(10), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	m.deviceDAO.EXPECT().UpdateLoadedMap(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d *entity.Device) error {
+		if d.ID != 1 || *d.LoadedSemanticMapID != 9 || *d.LoadedSemanticMapVersion != 3 || *d.LoadedDeploymentID != 10 {
+			t.Errorf("Unexpected loaded map %+v", d)
+		}
+		return nil
+	})
+	m.deploymentDAO.EXPECT().Finish(ctx, deployment).Return(nil)
+
+	ran, err := service.RunOnce(ctx)
+	if !ran || err != nil {
+		t.Fatalf("RunOnce failed: %v, %v", ran, err)
+	}
+	if deployment.Status != entity.MapDeploymentStatusSucceeded || deployment.Error != nil {
+		t.Fatalf("Expected deployment to succeed, got %s %v", deployment.Status, deployment.Error)
+	}
+	if deployment.SentBytes != deployment.TotalBytes || deployment.TotalBytes == 0 {
+		t.Errorf("Expected all bytes sent, got %d/%d", deployment.SentBytes, deployment.TotalBytes)
+	}
+
+	base := "/maps/semantic_9_v3/"
+	if received[base+"map.pcd"] != "pcd-data" || received[base+"semantic.json"] != `{"poi":[]}` {
+		t.Errorf("Unexpected uploaded files %v", received)
+	}
+	if len(order) != 3 || order[2] != base+mapManifestName {
+		t.Fatalf("Expected manifest to be uploaded last, got %v", order)
+	}
+	var manifest mapManifest
+	if err := json.Unmarshal([]byte(received[base+mapManifestName]), &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if manifest.DeploymentID != 10 || manifest.SemanticMapVersion != 3 || len(manifest.Files) != 2 || manifest.Files[0].Size != 8 {
+		t.Errorf("Unexpected manifest %+v", manifest)
+	}
+}
+
+func TestMapDeploymentService_RunOnceRecordsFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestMapDeploymentService(ctrl)
+	ctx := context.Background()
+
+	deployment := &entity.MapDeployment{Model: gorm.Model{ID: 10}, DeviceID: 1, SemanticMapID: 9, SemanticMapVersion: 3, Driver: "unknown"}
+	ip := "10.0.0.8"
+	m.deploymentDAO.EXPECT().ClaimNext(ctx).Return(deployment, nil)
+	m.deviceDAO.EXPECT().FindByID(ctx, uint(1)).Return(&entity.Device{Model: gorm.Model{ID: 1}, IP: &ip}, nil)
+	m.deploymentDAO.EXPECT().Finish(ctx, deployment).Return(nil)
+
+	if ran, err := service.RunOnce(ctx); !ran || err != nil {
+		t.Fatalf("RunOnce failed: %v, %v", ran, err)
+	}
+	if deployment.Status != entity.MapDeploymentStatusFailed || deployment.Error == nil {
+		t.Errorf("Expected deployment to fail, got %s", deployment.Status)
+	}
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+
+	"go.uber.org/zap"
+)
+
+// recordVersion 将点云地图当前内容保存为新版本，并把当前版本指向它
+func (s *PCDFileService) recordVersion(ctx context.Context, file *entity.PCDFile, author string, message *string) error {
+	if author == "" {
+		author = file.UserName
+	}
+	version := &entity.PCDFileVersion{
+		PCDFileID:   file.ID,
+		Path:        file.Path,
+		Size:        file.Size,
+		MinioPath:   file.MinioPath,
+		Author:      author,
+		Message:     message,
+		PCDMetadata: file.PCDMetadata,
+	}
+	if err := s.versionDAO.Create(ctx, version); err != nil {
+		return err
+	}
+	file.CurrentVersion = &version.Version
+	return nil
+}
+
+// ensureBaseVersion 版本管理上线前创建的地图没有版本，替换内容前先把原内容保存为第一个版本
+func (s *PCDFileService) ensureBaseVersion(ctx context.Context, file *entity.PCDFile) error {
+	if file.CurrentVersion != nil {
+		return nil
+	}
+	return s.recordVersion(ctx, file, file.UserName, nil)
+}
+
+// ListPCDFileVersions 按版本号倒序获取点云地图的全部版本，地图不存在时返回 nil
+func (s *PCDFileService) ListPCDFileVersions(ctx context.Context, id uint) ([]*dto.PCDFileVersionResponse, error) {
+	logger.Debug("listing pcd file versions in service", zap.Uint("id", id))
+
+	file, err := s.pcdDAO.FindByID(ctx, id)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	versions, err := s.versionDAO.FindByFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewPCDFileVersionResponsesFromEntities(versions, file.CurrentVersion), nil
+}
+
+// GetPCDFileVersion 获取点云地图的指定版本，地图或版本不存在时返回 nil
+func (s *PCDFileService) GetPCDFileVersion(ctx context.Context, id uint, version int) (*dto.PCDFileVersionResponse, error) {
+	logger.Debug("getting pcd file version in service", zap.Uint("id", id), zap.Int("version", version))
+
+	file, err := s.pcdDAO.FindByID(ctx, id)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	v, err := s.versionDAO.FindByVersion(ctx, id, version)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return dto.NewPCDFileVersionResponseFromEntity(v, file.CurrentVersion), nil
+}
+
+// RollbackPCDFile 将点云地图回滚到指定版本：恢复该版本的文件与元数据并把当前版本指向它，
+// 不产生新版本；地图或版本不存在时返回 nil
+func (s *PCDFileService) RollbackPCDFile(ctx context.Context, id uint, version int) (*dto.PCDFileResponse, error) {
+	logger.Info("rolling back pcd file in service", zap.Uint("id", id), zap.Int("version", version))
+
+	file, err := s.pcdDAO.FindByID(ctx, id)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	v, err := s.versionDAO.FindByVersion(ctx, id, version)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	objectChanged := !sameObject(file.MinioPath, v.MinioPath)
+	file.Path = v.Path
+	file.Size = v.Size
+	file.MinioPath = v.MinioPath
+	file.PCDMetadata = v.PCDMetadata
+	file.CurrentVersion = &v.Version
+	if objectChanged {
+		// 换回的对象尚未校验，等待下一轮完整性校验
+		file.PCDIntegrity = entity.PCDIntegrity{}
+	}
+
+	if err := s.pcdDAO.Update(ctx, file); err != nil {
+		logger.Error("failed to rollback pcd file in service", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+	if objectChanged && file.MinioPath != nil && *file.MinioPath != "" {
+		s.schedulePreview(ctx, file)
+	}
+
+	logger.Info("pcd file rolled back successfully in service", zap.Uint("id", id), zap.Int("version", version))
+	return dto.NewPCDFileResponseFromEntity(file), nil
+}
+
+// sameObject 判断两个 MinIO 路径是否指向同一对象
+func sameObject(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// recordSemanticMapVersion 将语义地图当前内容保存为新版本，并把当前版本指向它
+func recordSemanticMapVersion(ctx context.Context, versionDAO dao.SemanticMapVersionDAO, pcdDAO dao.PCDFileDAO, semanticMap *entity.SemanticMap, author string, message *string) error {
+	version, err := newSemanticMapVersion(ctx, pcdDAO, semanticMap, author, message)
+	if err != nil {
+		return err
+	}
+	if err := versionDAO.Create(ctx, version); err != nil {
+		return err
+	}
+	semanticMap.CurrentVersion = &version.Version
+	return nil
+}
+
+// newSemanticMapVersion 以语义地图当前内容构造待写入的版本，版本号由 DAO 写入时分配
+// 底图为点云地图时版本同时记录点云此刻的当前版本，保证历史语义与点云可以一并还原
+func newSemanticMapVersion(ctx context.Context, pcdDAO dao.PCDFileDAO, semanticMap *entity.SemanticMap, author string, message *string) (*entity.SemanticMapVersion, error) {
+	if author == "" {
+		author = semanticMap.UserName
+	}
+	version := &entity.SemanticMapVersion{
+		SemanticMapID:   semanticMap.ID,
+		PCDFileID:       semanticMap.PCDFileID,
+		OccupancyGridID: semanticMap.OccupancyGridID,
+		SemanticInfo:    semanticMap.SemanticInfo,
+		ExtraInfo:       semanticMap.ExtraInfo,
+		Author:          author,
+		Message:         message,
+	}
+
+	if semanticMap.PCDFileID != nil {
+		pcdFile, err := pcdDAO.FindByID(ctx, *semanticMap.PCDFileID)
+		if err != nil {
+			return nil, err
+		}
+		if pcdFile != nil {
+			version.PCDFileVersion = pcdFile.CurrentVersion
+		}
+	}
+	return version, nil
+}
+
+// baseVersion 版本管理上线前创建的语义地图没有版本，编辑前先以原内容构造基础版本，
+// 与编辑后的新版本在同一事务中写入；已有版本时返回 nil
+func (s *SemanticMapService) baseVersion(ctx context.Context, semanticMap *entity.SemanticMap) (*entity.SemanticMapVersion, error) {
+	if semanticMap.CurrentVersion != nil {
+		return nil, nil
+	}
+	version, err := newSemanticMapVersion(ctx, s.pcdDAO, semanticMap, semanticMap.UserName, nil)
+	if err != nil {
+		logger.Error("failed to build base semantic map version", zap.Error(err), zap.Uint("id", semanticMap.ID))
+		return nil, err
+	}
+	return version, nil
+}
+
+// ListSemanticMapVersions 按版本号倒序获取语义地图的全部版本，地图不存在时返回 nil
+func (s *SemanticMapService) ListSemanticMapVersions(ctx context.Context, id uint) ([]*dto.SemanticMapVersionResponse, error) {
+	logger.Debug("listing semantic map versions in service", zap.Uint("id", id))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	versions, err := s.versionDAO.FindByMap(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewSemanticMapVersionResponsesFromEntities(versions, semanticMap.CurrentVersion), nil
+}
+
+// GetSemanticMapVersion 获取语义地图的指定版本，地图或版本不存在时返回 nil
+func (s *SemanticMapService) GetSemanticMapVersion(ctx context.Context, id uint, version int) (*dto.SemanticMapVersionResponse, error) {
+	logger.Debug("getting semantic map version in service", zap.Uint("id", id), zap.Int("version", version))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	v, err := s.versionDAO.FindByVersion(ctx, id, version)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return dto.NewSemanticMapVersionResponseFromEntity(v, semanticMap.CurrentVersion), nil
+}
+
+// RollbackSemanticMap 将语义地图回滚到指定版本，不产生新版本；地图或版本不存在时返回 nil。
+// 编辑锁由他人持有或 ifMatch 与当前修订号不一致时拒绝回滚
+func (s *SemanticMapService) RollbackSemanticMap(ctx context.Context, id uint, version int, editor string, ifMatch *int) (*dto.SemanticMapResponse, error) {
+	logger.Info("rolling back semantic map in service", zap.Uint("id", id), zap.Int("version", version))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	if err := checkEditable(semanticMap, editor, ifMatch); err != nil {
+		return nil, err
+	}
+	v, err := s.versionDAO.FindByVersion(ctx, id, version)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	if err := s.setBaseLayer(ctx, semanticMap, v.PCDFileID, v.OccupancyGridID); err != nil {
+		logger.Warn("base layer of semantic map version deleted", zap.Error(err), zap.Uint("id", id), zap.Int("version", version))
+		return nil, fmt.Errorf("该版本关联的底图已删除，无法回滚: %w", err)
+	}
+
+	semanticMap.SemanticInfo = v.SemanticInfo
+	semanticMap.ExtraInfo = v.ExtraInfo
+	semanticMap.CurrentVersion = &v.Version
+
+	if err := s.semanticDAO.Update(ctx, semanticMap, editor); err != nil {
+		logger.Error("failed to rollback semantic map in service", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+	s.refreshCheck(ctx, semanticMap)
+
+	logger.Info("semantic map rolled back successfully in service", zap.Uint("id", id), zap.Int("version", version))
+	return dto.NewSemanticMapResponseFromEntity(semanticMap), nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestTaskService_CreateTask_PinsCurrentSemanticMapVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTaskDAO := mocks.NewMockTaskDAO(ctrl)
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	mockVersionDAO := mocks.NewMockSemanticMapVersionDAO(ctrl)
+	service := NewTaskService(mockTaskDAO, mockSemanticDAO, mockVersionDAO, mocks.NewMockPCDFileDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	current := 3
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(1)).Return(&entity.SemanticMap{Model: gorm.Model{ID: 1}, CurrentVersion: &current}, nil)
+	mockTaskDAO.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, task *entity.Task) error {
+		if task.SemanticMapVersion == nil || *task.SemanticMapVersion != 3 {
+			t.Fatalf("Expected task pinned to version 3, got %v", task.SemanticMapVersion)
+		}
+		return nil
+	})
+
+	resp, err := service.CreateTask(ctx, &dto.TaskCreateRequest{SemanticMapID: 1, UserName: "tester", TaskInfo: "{}"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if resp.SemanticMapVersion == nil || *resp.SemanticMapVersion != 3 {
+		t.Fatalf("Expected response version 3, got %v", resp.SemanticMapVersion)
+	}
+}
+
+func TestTaskService_CreateTask_RejectsUnknownVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockVersionDAO := mocks.NewMockSemanticMapVersionDAO(ctrl)
+	service := NewTaskService(mocks.NewMockTaskDAO(ctrl), mocks.NewMockSemanticMapDAO(ctrl), mockVersionDAO, mocks.NewMockPCDFileDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	requested := 7
+	mockVersionDAO.EXPECT().FindByVersion(ctx, uint(1), 7).Return(nil, nil)
+
+	if _, err := service.CreateTask(ctx, &dto.TaskCreateRequest{SemanticMapID: 1, UserName: "tester", TaskInfo: "{}", SemanticMapVersion: &requested}); err == nil {
+		t.Fatal("Expected task creation with unknown version to fail")
+	}
+}
+
+func TestPCDFileService_RollbackPCDFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockVersionDAO := mocks.NewMockPCDFileVersionDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mocks.NewMockPCDUploadDAO(ctrl), mocks.NewMockPCDJobDAO(ctrl), mockVersionDAO, mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	current, oldPath := 2, "pcd/old.pcd"
+	newPath := "pcd/new.pcd"
+	file := &entity.PCDFile{Model: gorm.Model{ID: 1}, Path: newPath, MinioPath: &newPath, CurrentVersion: &current}
+	mockPCDDAO.EXPECT().FindByID(ctx, uint(1)).Return(file, nil)
+	mockVersionDAO.EXPECT().FindByVersion(ctx, uint(1), 1).Return(&entity.PCDFileVersion{PCDFileID: 1, Version: 1, Path: oldPath, Size: 10}, nil)
+	mockPCDDAO.EXPECT().Update(ctx, file).Return(nil)
+
+	resp, err := service.RollbackPCDFile(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("RollbackPCDFile failed: %v", err)
+	}
+	if resp.Path != oldPath || resp.MinioPath != nil || resp.CurrentVersion == nil || *resp.CurrentVersion != 1 {
+		t.Fatalf("Expected file restored to version 1, got %+v", resp)
+	}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/pcd"
+	"robot_scheduler/internal/storage"
+
+	"go.uber.org/zap"
+)
+
+// 栅格地图下载内容
+const (
+	OccupancyGridFileImage = "image"
+	OccupancyGridFileYAML  = "yaml"
+
+	occupancyImageContentType = "image/x-portable-graymap"
+	occupancyYAMLContentType  = "application/x-yaml"
+)
+
+// OccupancyGridService 二维占据栅格地图服务
+type OccupancyGridService struct {
+	gridDAO dao.OccupancyGridDAO
+}
+
+func NewOccupancyGridService(gridDAO dao.OccupancyGridDAO) *OccupancyGridService {
+	return &OccupancyGridService{
+		gridDAO: gridDAO,
+	}
+}
+
+// GetOccupancyGridByID 根据ID获取栅格地图，不存在时返回 nil
+func (s *OccupancyGridService) GetOccupancyGridByID(ctx context.Context, id uint) (*dto.OccupancyGridResponse, error) {
+	logger.Debug("getting occupancy grid by id in service", zap.Uint("id", id))
+	grid, err := s.gridDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewOccupancyGridResponseFromEntity(grid), nil
+}
+
+// ListOccupancyGrids 分页获取栅格地图列表
+func (s *OccupancyGridService) ListOccupancyGrids(ctx context.Context, req dto.PageRequest) (*dto.OccupancyGridListResponse, error) {
+	logger.Debug("listing occupancy grids in service", zap.Int("page", req.Page), zap.Int("pageSize", req.PageSize))
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+
+	grids, total, err := s.gridDAO.FindPage(ctx, (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	page := dto.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Pages:    int((total + int64(req.PageSize) - 1) / int64(req.PageSize)),
+	}
+	return dto.NewOccupancyGridListResponseFromEntities(grids, page), nil
+}
+
+// ListByPCDFile 查询由点云地图生成的栅格地图
+func (s *OccupancyGridService) ListByPCDFile(ctx context.Context, pcdFileID uint) ([]*dto.OccupancyGridResponse, error) {
+	grids, err := s.gridDAO.FindByPCDFile(ctx, pcdFileID)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*dto.OccupancyGridResponse, 0, len(grids))
+	for _, g := range grids {
+		list = append(list, dto.NewOccupancyGridResponseFromEntity(g))
+	}
+	return list, nil
+}
+
+// ImportROSMap 导入 ROS map_server 地图：校验 YAML 描述与 PGM 图像后存入 MinIO 并创建栅格地图。
+// 图像统一转存为二进制 PGM，YAML 中的 image 改写为存储后的文件名，格式不符时返回 pcd.ErrInvalidMapServer
+func (s *OccupancyGridService) ImportROSMap(ctx context.Context, req *dto.OccupancyGridImportRequest, yamlData []byte, image io.Reader) (*dto.OccupancyGridResponse, error) {
+	logger.Info("importing ros map in service", zap.String("name", req.Name))
+
+	meta, err := pcd.ParseMapServerYAML(yamlData)
+	if err != nil {
+		logger.Warn("map_server yaml rejected", zap.Error(err))
+		return nil, err
+	}
+	img, err := pcd.ReadPGM(image, occupancyMaxPixels())
+	if err != nil {
+		logger.Warn("map_server image rejected", zap.Error(err))
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = strings.TrimSuffix(path.Base(meta.Image), path.Ext(meta.Image))
+	}
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	base := fmt.Sprintf("%sros/%d/%s", occupancyObjectPrefix, time.Now().UnixNano(), safeObjectName(name))
+	imageKey, yamlKey := base+".pgm", base+".yaml"
+	var pgmBuf, yamlBuf bytes.Buffer
+	if err := img.WritePGM(&pgmBuf); err != nil {
+		return nil, err
+	}
+	if err := meta.Write(&yamlBuf, path.Base(imageKey)); err != nil {
+		return nil, err
+	}
+	if err := store.Put(ctx, imageKey, &pgmBuf, int64(pgmBuf.Len()), occupancyImageContentType); err != nil {
+		logger.Error("failed to upload ros map image", zap.Error(err), zap.String("objectKey", imageKey))
+		return nil, err
+	}
+	if err := store.Put(ctx, yamlKey, &yamlBuf, int64(yamlBuf.Len()), occupancyYAMLContentType); err != nil {
+		logger.Error("failed to upload ros map yaml", zap.Error(err), zap.String("objectKey", yamlKey))
+		removeGridObjects(ctx, store, imageKey)
+		return nil, err
+	}
+
+	occupied, free := img.CountCells(meta)
+	grid := &entity.OccupancyGrid{
+		Name:           name,
+		Source:         entity.OccupancyGridSourceROS,
+		UserName:       req.UserName,
+		ImagePath:      imageKey,
+		YAMLPath:       yamlKey,
+		Resolution:     meta.Resolution,
+		OriginX:        meta.Origin[0],
+		OriginY:        meta.Origin[1],
+		OriginYaw:      meta.Origin[2],
+		Width:          img.Width,
+		Height:         img.Height,
+		Negate:         meta.Negate == 1,
+		OccupiedThresh: meta.OccupiedThresh,
+		FreeThresh:     meta.FreeThresh,
+		OccupiedCells:  &occupied,
+		FreeCells:      &free,
+	}
+	if err := s.gridDAO.Create(ctx, grid); err != nil {
+		logger.Error("failed to create imported occupancy grid", zap.Error(err))
+		removeGridObjects(ctx, store, imageKey, yamlKey)
+		return nil, err
+	}
+
+	logger.Info("ros map imported successfully in service", zap.Uint("id", grid.ID), zap.Int("width", grid.Width), zap.Int("height", grid.Height))
+	return dto.NewOccupancyGridResponseFromEntity(grid), nil
+}
+
+// OpenDownload 打开栅格地图的 PGM 图像或 YAML 描述用于代理下载，栅格地图不存在时返回 nil
+// 下载文件名与 YAML 中 image 字段一致，两个文件放在同一目录即可被 map_server 加载
+func (s *OccupancyGridService) OpenDownload(ctx context.Context, id uint, file string) (*PCDDownload, error) {
+	logger.Info("opening occupancy grid download in service", zap.Uint("id", id), zap.String("file", file))
+
+	var objectKey, contentType string
+	grid, err := s.gridDAO.FindByID(ctx, id)
+	if err != nil || grid == nil {
+		return nil, err
+	}
+	switch file {
+	case "", OccupancyGridFileImage:
+		objectKey, contentType = grid.ImagePath, occupancyImageContentType
+	case OccupancyGridFileYAML:
+		objectKey, contentType = grid.YAMLPath, occupancyYAMLContentType
+	default:
+		return nil, fmt.Errorf("unknown grid file %q", file)
+	}
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+	object, info, err := store.Get(ctx, objectKey)
+	if err != nil {
+		logger.Error("failed to open occupancy grid object", zap.Error(err), zap.Uint("id", id))
+		return nil, fmt.Errorf("对象 %s 不存在: %w", objectKey, err)
+	}
+
+	return &PCDDownload{
+		FileID:      grid.ID,
+		Name:        grid.Name,
+		FileName:    path.Base(objectKey),
+		ContentType: contentType,
+		Object:      object,
+		Info:        info,
+	}, nil
+}
+
+// DeleteOccupancyGrid 删除栅格地图并清理其 MinIO 对象，对象清理失败只记录日志；仍被语义地图用作底图时拒绝删除
+func (s *OccupancyGridService) DeleteOccupancyGrid(ctx context.Context, id uint) error {
+	logger.Info("deleting occupancy grid in service", zap.Uint("id", id))
+
+	grid, err := s.gridDAO.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if grid == nil {
+		return errors.New("occupancy grid not found")
+	}
+	semanticMaps, err := s.gridDAO.CountSemanticMaps(ctx, id)
+	if err != nil {
+		return err
+	}
+	if semanticMaps > 0 {
+		logger.Warn("occupancy grid still referenced", zap.Uint("id", id), zap.Int64("semanticMaps", semanticMaps))
+		return fmt.Errorf("栅格地图仍被 %d 个语义地图用作底图，无法删除", semanticMaps)
+	}
+	if err := s.gridDAO.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	store, err := pcdStorage()
+	if err != nil {
+		logger.Warn("occupancy grid objects not removed", zap.Error(err), zap.Uint("id", id))
+		return nil
+	}
+	removeGridObjects(ctx, store, grid.ImagePath, grid.YAMLPath)
+	return nil
+}
+
+// removeObjects 删除栅格地图的对象，失败只记录日志，由对账报告为孤立对象
+func removeGridObjects(ctx context.Context, store storage.Storage, keys ...string) {
+	for _, key := range keys {
+		if err := store.Remove(ctx, key); err != nil {
+			logger.Warn("failed to remove occupancy grid object", zap.Error(err), zap.String("objectKey", key))
+		}
+	}
+}
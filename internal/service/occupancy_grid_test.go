@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/storage"
+	"robot_scheduler/internal/testutil/mocks"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestOccupancyGridService_ImportROSMapRemovesObjectsOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local, err := storage.NewLocal(storage.LocalConfig{Root: t.TempDir(), Secret: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	storage.SetBackend(local)
+	defer storage.SetBackend(nil)
+
+	mockGridDAO := mocks.NewMockOccupancyGridDAO(ctrl)
+	service := NewOccupancyGridService(mockGridDAO)
+	ctx := context.Background()
+
+	mockGridDAO.EXPECT().Create(ctx, gomock.Any()).Return(errors.New("db down"))
+	yaml := []byte("image: map.pgm\nresolution: 0.05\norigin: [0, 0, 0]\nnegate: 0\noccupied_thresh: 0.65\nfree_thresh: 0.196\nmode: raw\n")
+	_, err = service.ImportROSMap(ctx, &dto.OccupancyGridImportRequest{UserName: "alice"}, yaml, strings.NewReader("P2\n2 1\n255\n0 100\n"))
+	if err == nil {
+		t.Fatal("Expected import to fail")
+	}
+
+	objects, err := local.List(ctx, occupancyObjectPrefix)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(objects) != 0 {
+		t.Errorf("Expected stored objects to be removed, got %+v", objects)
+	}
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"robot_scheduler/internal/config"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/storage"
+
+	"go.uber.org/zap"
+)
+
+// 下载内容：原始点云、降采样预览点云、俯视缩略图
+const (
+	PCDDownloadOriginal  = ""
+	PCDDownloadPreview   = "preview"
+	PCDDownloadThumbnail = "thumbnail"
+)
+
+// PCDDownload 服务端代理下载的点云对象，调用方负责关闭 Object
+type PCDDownload struct {
+	FileID      uint
+	Name        string
+	FileName    string
+	ContentType string
+	Object      io.ReadSeekCloser
+	Info        storage.ObjectInfo
+}
+
+// pcdDownloadTarget 下载内容对应的对象
+type pcdDownloadTarget struct {
+	ObjectKey   string
+	FileName    string
+	ContentType string
+}
+
+// GetDownloadURL 生成点云地图（或其预览、缩略图）的预签名下载链接，地图不存在时返回 nil
+func (s *PCDFileService) GetDownloadURL(ctx context.Context, id uint, variant string) (*dto.PCDFileDownloadURLResponse, error) {
+	logger.Info("generating pcd download url in service", zap.Uint("id", id), zap.String("variant", variant))
+
+	file, err := s.findDownloadableFile(ctx, id)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	target, err := resolvePCDDownload(file, variant)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	expire := 5 * time.Minute
+	if cfg := config.Get(); cfg.Minio != nil && cfg.Minio.DownloadExpire > 0 {
+		expire = time.Duration(cfg.Minio.DownloadExpire) * time.Second
+	}
+
+	u, err := store.PresignGet(ctx, target.ObjectKey, expire, storage.GetURLOptions{
+		ContentDisposition: contentDisposition(target.FileName),
+		ContentType:        target.ContentType,
+	})
+	if err != nil {
+		logger.Error("failed to generate presigned get url", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	resp := &dto.PCDFileDownloadURLResponse{
+		ID:          file.ID,
+		Name:        file.Name,
+		Variant:     variant,
+		FileName:    target.FileName,
+		DownloadURL: u,
+		ExpireAt:    time.Now().Add(expire).Unix(),
+	}
+	if variant == PCDDownloadOriginal {
+		resp.Size = file.Size
+	}
+
+	logger.Info("pcd download url generated successfully", zap.Uint("id", id), zap.String("objectKey", target.ObjectKey))
+	return resp, nil
+}
+
+// OpenDownload 打开点云地图对应的存储对象用于服务端代理下载，地图不存在时返回 nil
+// 返回的对象支持 Seek，可直接交给 http.ServeContent 处理 Range 请求
+func (s *PCDFileService) OpenDownload(ctx context.Context, id uint, variant string) (*PCDDownload, error) {
+	logger.Info("opening pcd download in service", zap.Uint("id", id), zap.String("variant", variant))
+
+	file, err := s.findDownloadableFile(ctx, id)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	target, err := resolvePCDDownload(file, variant)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	object, info, err := store.Get(ctx, target.ObjectKey)
+	if err != nil {
+		logger.Error("failed to open pcd object", zap.Error(err), zap.Uint("id", id))
+		return nil, fmt.Errorf("对象 %s 不存在: %w", target.ObjectKey, err)
+	}
+
+	return &PCDDownload{
+		FileID:      file.ID,
+		Name:        file.Name,
+		FileName:    target.FileName,
+		ContentType: target.ContentType,
+		Object:      object,
+		Info:        info,
+	}, nil
+}
+
+// findDownloadableFile 查询可下载的点云地图，地图不存在时返回 nil
+func (s *PCDFileService) findDownloadableFile(ctx context.Context, id uint) (*entity.PCDFile, error) {
+	if _, err := pcdStorage(); err != nil {
+		logger.Error("object storage not configured")
+		return nil, err
+	}
+
+	file, err := s.pcdDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, nil
+	}
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		logger.Warn("pcd file has no minio object", zap.Uint("id", id))
+		return nil, errors.New("pcd file has no minio object")
+	}
+	return file, nil
+}
+
+// resolvePCDDownload 根据下载内容确定对象 Key、文件名与类型，预览尚未生成时返回错误
+func resolvePCDDownload(file *entity.PCDFile, variant string) (*pcdDownloadTarget, error) {
+	switch variant {
+	case PCDDownloadOriginal:
+		return &pcdDownloadTarget{
+			ObjectKey:   *file.MinioPath,
+			FileName:    pcdDownloadFileName(file),
+			ContentType: "application/octet-stream",
+		}, nil
+	case PCDDownloadPreview, PCDDownloadThumbnail:
+		if file.PreviewStatus == nil || *file.PreviewStatus != entity.PCDJobStatusSucceeded {
+			return nil, errors.New("pcd preview not generated")
+		}
+		name := pcdDownloadFileName(file)
+		base := strings.TrimSuffix(name, path.Ext(name))
+		if variant == PCDDownloadPreview && file.PreviewPath != nil {
+			return &pcdDownloadTarget{
+				ObjectKey:   *file.PreviewPath,
+				FileName:    base + "_preview.pcd",
+				ContentType: "application/octet-stream",
+			}, nil
+		}
+		if variant == PCDDownloadThumbnail && file.ThumbnailPath != nil {
+			return &pcdDownloadTarget{
+				ObjectKey:   *file.ThumbnailPath,
+				FileName:    base + "_thumbnail.png",
+				ContentType: "image/png",
+			}, nil
+		}
+		return nil, errors.New("pcd preview not generated")
+	default:
+		return nil, fmt.Errorf("unknown download variant %q", variant)
+	}
+}
+
+// pcdDownloadFileName 下载文件名取地图名称，缺少扩展名时补 .pcd
+func pcdDownloadFileName(file *entity.PCDFile) string {
+	name := file.Name
+	if !strings.HasSuffix(strings.ToLower(name), ".pcd") {
+		name += ".pcd"
+	}
+	return name
+}
+
+// contentDisposition 构造附件下载头，非 ASCII 文件名由 mime 按 RFC 2231 编码为 filename*，
+// 同时附带替换了非 ASCII 字符的 filename 供不识别 filename* 的客户端使用
+func contentDisposition(fileName string) string {
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": fileName})
+	if !strings.Contains(disposition, "filename*=") {
+		return disposition
+	}
+	fallback := strings.Map(func(r rune) rune {
+		if r > 0x7e || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, fileName)
+	return mime.FormatMediaType("attachment", map[string]string{"filename": fallback}) + strings.TrimPrefix(disposition, "attachment")
+}
+
+// ContentDisposition 代理下载使用的 Content-Disposition 响应头
+func (d *PCDDownload) ContentDisposition() string {
+	return contentDisposition(d.FileName)
+}
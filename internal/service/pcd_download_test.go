@@ -0,0 +1,72 @@
+package service
+
+import (
+	"mime"
+	"robot_scheduler/internal/model/entity"
+	"strings"
+	"testing"
+)
+
+func TestPCDDownloadFileName(t *testing.T) {
+	cases := map[string]string{
+		"一楼地图":       "一楼地图.pcd",
+		"floor1.PCD": "floor1.PCD",
+		"floor2":     "floor2.pcd",
+	}
+	for name, want := range cases {
+		if got := pcdDownloadFileName(&entity.PCDFile{Name: name}); got != want {
+			t.Errorf("pcdDownloadFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestContentDisposition_EncodesNonASCII(t *testing.T) {
+	got := contentDisposition(`一楼"a".pcd`)
+
+	if !strings.HasPrefix(got, `attachment; filename="__\"a\".pcd"`) {
+		t.Errorf("Expected ASCII fallback filename, got %s", got)
+	}
+	if !strings.Contains(got, "filename*=utf-8''%E4%B8%80%E6%A5%BC%22a%22.pcd") {
+		t.Errorf("Expected RFC 2231 encoded filename, got %s", got)
+	}
+	// 按标准解析时以 filename* 为准，还原出原始文件名
+	disposition, params, err := mime.ParseMediaType(got)
+	if err != nil || disposition != "attachment" || params["filename"] != `一楼"a".pcd` {
+		t.Errorf("Expected parsed filename %q, got %q, %v", `一楼"a".pcd`, params["filename"], err)
+	}
+
+	if got := contentDisposition("floor1.pcd"); got != `attachment; filename=floor1.pcd` {
+		t.Errorf("Expected plain filename for ASCII name, got %s", got)
+	}
+}
+
+func TestResolvePCDDownload_Variants(t *testing.T) {
+	minioPath := "pcd/u/1_floor.pcd"
+	file := &entity.PCDFile{Name: "一楼", MinioPath: &minioPath}
+
+	if _, err := resolvePCDDownload(file, PCDDownloadThumbnail); err == nil {
+		t.Error("Expected error before preview is generated")
+	}
+	if _, err := resolvePCDDownload(file, "other"); err == nil {
+		t.Error("Expected error for unknown variant")
+	}
+
+	status := entity.PCDJobStatusSucceeded
+	previewKey, thumbnailKey := pcdPreviewKeys(minioPath)
+	file.PreviewStatus = &status
+	file.PreviewPath = &previewKey
+	file.ThumbnailPath = &thumbnailKey
+
+	target, err := resolvePCDDownload(file, PCDDownloadThumbnail)
+	if err != nil {
+		t.Fatalf("resolvePCDDownload failed: %v", err)
+	}
+	if target.ObjectKey != "pcd/u/1_floor_thumbnail.png" || target.FileName != "一楼_thumbnail.png" || target.ContentType != "image/png" {
+		t.Errorf("Unexpected thumbnail target %+v", target)
+	}
+
+	target, _ = resolvePCDDownload(file, PCDDownloadPreview)
+	if target.ObjectKey != "pcd/u/1_floor_preview.pcd" || target.FileName != "一楼_preview.pcd" {
+		t.Errorf("Unexpected preview target %+v", target)
+	}
+}
@@ -2,35 +2,56 @@ package service
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
-	"robot_scheduler/internal/config"
 	dao "robot_scheduler/internal/dao/interfaces"
 	"robot_scheduler/internal/logger"
-	"robot_scheduler/internal/minio_client"
 	"robot_scheduler/internal/model/dto"
 	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/pcd"
 
 	"go.uber.org/zap"
 )
 
+// ErrPCDFileInUse 点云地图仍被语义地图或进行中的任务引用，不能删除
+var ErrPCDFileInUse = errors.New("pcd file in use")
+
 // PCDFileService 点云地图服务
 type PCDFileService struct {
-	pcdDAO dao.PCDFileDAO
+	pcdDAO      dao.PCDFileDAO
+	uploadDAO   dao.PCDUploadDAO
+	jobDAO      dao.PCDJobDAO
+	versionDAO  dao.PCDFileVersionDAO
+	locationDAO dao.LocationDAO
 }
 
-func NewPCDFileService(pcdDAO dao.PCDFileDAO) *PCDFileService {
+func NewPCDFileService(pcdDAO dao.PCDFileDAO, uploadDAO dao.PCDUploadDAO, jobDAO dao.PCDJobDAO, versionDAO dao.PCDFileVersionDAO, locationDAO dao.LocationDAO) *PCDFileService {
 	return &PCDFileService{
-		pcdDAO: pcdDAO,
+		pcdDAO:      pcdDAO,
+		uploadDAO:   uploadDAO,
+		jobDAO:      jobDAO,
+		versionDAO:  versionDAO,
+		locationDAO: locationDAO,
 	}
 }
 
-// CreatePCDFile 创建点云地图
-func (s *PCDFileService) CreatePCDFile(ctx context.Context, req *dto.PCDFileCreateRequest) (*dto.PCDFileResponse, error) {
+// CreatePCDFile 创建点云地图，operator 为当前登录用户，minioPath 须位于其本人的上传前缀下，去重时只删除其本人上传的对象
+func (s *PCDFileService) CreatePCDFile(ctx context.Context, operator string, req *dto.PCDFileCreateRequest) (*dto.PCDFileResponse, error) {
 	logger.Info("creating pcd file in service", zap.String("name", req.Name))
 
+	if req.MinioPath != nil && *req.MinioPath != "" {
+		if err := checkOwnUpload(operator, *req.MinioPath); err != nil {
+			return nil, err
+		}
+	}
+
 	// 检查名称是否已存在
 	existingFile, err := s.pcdDAO.FindByName(ctx, req.Name)
 	if err != nil {
@@ -53,19 +74,51 @@ func (s *PCDFileService) CreatePCDFile(ctx context.Context, req *dto.PCDFileCrea
 		MinioPath: req.MinioPath,
 		ExtraInfo: req.ExtraInfo,
 	}
+	if file.LocationID, err = resolveLocationID(ctx, s.locationDAO, req.LocationID); err != nil {
+		return nil, err
+	}
+
+	// 已上传到 MinIO 的文件在服务端解析校验，大小以实际对象为准
+	if req.MinioPath != nil && *req.MinioPath != "" {
+		object, err := s.inspectObject(ctx, *req.MinioPath)
+		if err != nil {
+			logger.Warn("pcd file rejected", zap.Error(err), zap.String("minioPath", *req.MinioPath))
+			return nil, fmt.Errorf("点云文件校验失败: %w", err)
+		}
+		applyPCDMetadata(file, object)
+	}
+	duplicate, err := s.linkDuplicateObject(ctx, file)
+	if err != nil {
+		return nil, err
+	}
 
 	// 保存到数据库
 	if err := s.pcdDAO.Create(ctx, file); err != nil {
 		logger.Error("failed to create pcd file in service", zap.Error(err), zap.String("name", req.Name))
 		return nil, err
 	}
+	if err := s.recordVersion(ctx, file, req.UserName, req.Message); err != nil {
+		logger.Error("failed to create pcd file version in service", zap.Error(err), zap.Uint("id", file.ID))
+		return nil, err
+	}
+	if duplicate != nil {
+		s.removeDuplicateObject(ctx, operator, *req.MinioPath, file.ID)
+	}
+
+	if file.MinioPath != nil && *file.MinioPath != "" && !previewReady(duplicate) {
+		s.schedulePreview(ctx, file)
+	}
 
 	logger.Info("pcd file created successfully in service", zap.String("name", req.Name), zap.Uint("id", file.ID))
-	return dto.NewPCDFileResponseFromEntity(file), nil
+	resp := dto.NewPCDFileResponseFromEntity(file)
+	if duplicate != nil {
+		resp.DuplicateOf = &duplicate.ID
+	}
+	return resp, nil
 }
 
-// UpdatePCDFile 更新点云地图
-func (s *PCDFileService) UpdatePCDFile(ctx context.Context, id uint, req *dto.PCDFileUpdateRequest) error {
+// UpdatePCDFile 更新点云地图，operator 为当前登录用户，替换的 minioPath 须位于其本人的上传前缀下，去重时只删除其本人上传的对象
+func (s *PCDFileService) UpdatePCDFile(ctx context.Context, id uint, operator string, req *dto.PCDFileUpdateRequest) error {
 	logger.Info("updating pcd file in service", zap.Uint("id", id))
 
 	// 获取点云地图
@@ -80,6 +133,21 @@ func (s *PCDFileService) UpdatePCDFile(ctx context.Context, id uint, req *dto.PC
 		return errors.New("pcd file not found")
 	}
 
+	// 替换点云文件时生成新版本，旧内容保留在历史版本中
+	objectChanged := req.MinioPath != nil && (file.MinioPath == nil || *file.MinioPath != *req.MinioPath)
+	if objectChanged {
+		if err := checkOwnUpload(operator, *req.MinioPath); err != nil {
+			return err
+		}
+	}
+	contentChanged := objectChanged || (req.Path != nil && *req.Path != file.Path)
+	if contentChanged {
+		if err := s.ensureBaseVersion(ctx, file); err != nil {
+			logger.Error("failed to save base pcd file version", zap.Error(err), zap.Uint("id", id))
+			return err
+		}
+	}
+
 	// 更新字段
 	if req.Name != nil {
 		file.Name = *req.Name
@@ -96,27 +164,90 @@ func (s *PCDFileService) UpdatePCDFile(ctx context.Context, id uint, req *dto.PC
 	if req.Size != nil {
 		file.Size = *req.Size
 	}
-	if req.MinioPath != nil {
+	if objectChanged {
+		object, err := s.inspectObject(ctx, *req.MinioPath)
+		if err != nil {
+			logger.Warn("pcd file rejected", zap.Error(err), zap.String("minioPath", *req.MinioPath))
+			return fmt.Errorf("点云文件校验失败: %w", err)
+		}
 		file.MinioPath = req.MinioPath
+		applyPCDMetadata(file, object)
+	}
+	var duplicate *entity.PCDFile
+	if objectChanged {
+		if duplicate, err = s.linkDuplicateObject(ctx, file); err != nil {
+			return err
+		}
 	}
 	if req.ExtraInfo != nil {
 		file.ExtraInfo = req.ExtraInfo
 	}
+	if req.LocationID != nil {
+		if file.LocationID, err = resolveLocationID(ctx, s.locationDAO, req.LocationID); err != nil {
+			return err
+		}
+	}
 
 	// 保存更新
 	if err := s.pcdDAO.Update(ctx, file); err != nil {
 		logger.Error("failed to update pcd file in service", zap.Error(err), zap.Uint("id", id))
 		return err
 	}
+	if contentChanged {
+		if err := s.recordVersion(ctx, file, file.UserName, req.Message); err != nil {
+			logger.Error("failed to create pcd file version in service", zap.Error(err), zap.Uint("id", id))
+			return err
+		}
+	}
+
+	if duplicate != nil {
+		s.removeDuplicateObject(ctx, operator, *req.MinioPath, file.ID)
+	}
+
+	if objectChanged && !previewReady(duplicate) {
+		s.schedulePreview(ctx, file)
+	}
 
 	logger.Info("pcd file updated successfully in service", zap.Uint("id", id))
 	return nil
 }
 
-// DeletePCDFile 删除点云地图
-func (s *PCDFileService) DeletePCDFile(ctx context.Context, id uint) error {
-	logger.Info("deleting pcd file in service", zap.Uint("id", id))
-	return s.pcdDAO.Delete(ctx, id)
+// schedulePreview 为新的点云对象排队生成预览，失败只记录日志，不影响地图本身的保存
+func (s *PCDFileService) schedulePreview(ctx context.Context, file *entity.PCDFile) {
+	if !previewSettings().enabled {
+		return
+	}
+	if _, err := enqueuePCDJob(ctx, s.pcdDAO, s.jobDAO, file, entity.PCDJobTypePreview, nil); err != nil {
+		logger.Warn("failed to enqueue pcd preview job", zap.Error(err), zap.Uint("id", file.ID))
+	}
+}
+
+// DeletePCDFile 删除点云地图（软删除），MinIO 对象在保留期过后由清理任务删除
+// 仍被语义地图或任务引用时返回 ErrPCDFileInUse，cascade 为 true 时一并删除引用方；
+// 引用方中有待执行或执行中的任务时仍然拒绝，除非 force 为 true
+func (s *PCDFileService) DeletePCDFile(ctx context.Context, id uint, cascade, force bool) error {
+	logger.Info("deleting pcd file in service", zap.Uint("id", id), zap.Bool("cascade", cascade), zap.Bool("force", force))
+
+	semanticMaps, tasks, err := s.pcdDAO.CountDependents(ctx, id)
+	if err != nil {
+		return err
+	}
+	if semanticMaps == 0 {
+		return s.pcdDAO.Delete(ctx, id)
+	}
+
+	if !cascade {
+		logger.Warn("pcd file still referenced", zap.Uint("id", id), zap.Int64("semanticMaps", semanticMaps), zap.Int64("tasks", tasks))
+		return fmt.Errorf("%w: 仍被 %d 个语义地图、%d 个任务引用，如需一并删除请指定 cascade=true", ErrPCDFileInUse, semanticMaps, tasks)
+	}
+	if err := s.pcdDAO.DeleteCascade(ctx, id, force); err != nil {
+		if errors.Is(err, dao.ErrPCDFileHasActiveTasks) {
+			logger.Warn("pcd file has active tasks", zap.Uint("id", id))
+			return fmt.Errorf("%w: 引用该地图的任务仍在等待或执行中，如需强制删除请指定 force=true", ErrPCDFileInUse)
+		}
+		return err
+	}
+	return nil
 }
 
 // GetPCDFileByID 根据ID获取点云地图
@@ -132,8 +263,8 @@ func (s *PCDFileService) GetPCDFileByID(ctx context.Context, id uint) (*dto.PCDF
 	return dto.NewPCDFileResponseFromEntity(file), nil
 }
 
-// ListPCDFiles 分页获取点云地图列表
-func (s *PCDFileService) ListPCDFiles(ctx context.Context, req dto.PageRequest) (*dto.PCDFileListResponse, error) {
+// ListPCDFiles 分页获取点云地图列表，可按位置节点过滤
+func (s *PCDFileService) ListPCDFiles(ctx context.Context, req dto.PCDFileListRequest) (*dto.PCDFileListResponse, error) {
 	logger.Debug("listing pcd files in service with pagination", zap.Int("page", req.Page), zap.Int("pageSize", req.PageSize))
 
 	if req.Page <= 0 {
@@ -145,7 +276,7 @@ func (s *PCDFileService) ListPCDFiles(ctx context.Context, req dto.PageRequest)
 
 	offset := (req.Page - 1) * req.PageSize
 
-	files, total, err := s.pcdDAO.FindPage(ctx, offset, req.PageSize)
+	files, total, err := s.pcdDAO.FindPage(ctx, dao.PCDFileFilter{LocationID: req.LocationID}, offset, req.PageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -169,36 +300,50 @@ func (s *PCDFileService) ListPCDFiles(ctx context.Context, req dto.PageRequest)
 func (s *PCDFileService) GenerateUploadToken(ctx context.Context, userName string, req *dto.PCDFileUploadTokenRequest) (*dto.PCDFileUploadTokenResponse, error) {
 	logger.Info("generating pcd upload token in service", zap.String("fileName", req.FileName), zap.String("userName", userName))
 
-	cfg := config.Get()
-	if cfg == nil || cfg.Minio == nil || !cfg.Minio.Enabled {
-		logger.Error("minio not enabled in config")
-		return nil, errors.New("minio is not enabled")
-	}
-
-	client := minio_client.Client()
-	if client == nil {
-		logger.Error("minio client not initialized")
-		return nil, errors.New("minio client not initialized")
+	store, err := pcdStorage()
+	if err != nil {
+		logger.Error("object storage not configured")
+		return nil, err
 	}
 
 	if userName == "" {
-		userName = "unknown"
+		logger.Warn("pcd upload rejected without user")
+		return nil, fmt.Errorf("%w: 未识别到当前用户", ErrUploadUserRequired)
 	}
 
-	objectKey := fmt.Sprintf("pcd/%s/%d_%s", userName, time.Now().Unix(), req.FileName)
-	expire := 10 * time.Minute
+	objectKey, err := newUploadObjectKey(userName, req.FileName)
+	if err != nil {
+		return nil, err
+	}
+	expire := uploadURLExpire()
 
-	url, err := client.PresignedPutObject(ctx, cfg.Minio.BucketName, objectKey, expire)
+	url, err := store.PresignPut(ctx, objectKey, expire, req.Size)
 	if err != nil {
 		logger.Error("failed to generate presigned put url", zap.Error(err))
 		return nil, err
 	}
 
+	// 记录待完成的上传，供完成接口校验与超时清理
+	upload := &entity.PCDUpload{
+		ObjectKey:    objectKey,
+		UserName:     userName,
+		FileName:     req.FileName,
+		DeclaredSize: req.Size,
+		Checksum:     req.Checksum,
+		SHA256:       req.SHA256,
+		Status:       entity.PCDUploadStatusPending,
+		ExpireAt:     time.Now().Add(expire),
+	}
+	if err := s.uploadDAO.Create(ctx, upload); err != nil {
+		logger.Error("failed to record pcd upload", zap.Error(err), zap.String("objectKey", objectKey))
+		return nil, err
+	}
+
 	resp := &dto.PCDFileUploadTokenResponse{
-		UploadURL: url.String(),
-		Bucket:    cfg.Minio.BucketName,
+		UploadURL: url,
+		Bucket:    store.Bucket(),
 		ObjectKey: objectKey,
-		ExpireAt:  time.Now().Add(expire).Unix(),
+		ExpireAt:  upload.ExpireAt.Unix(),
 	}
 
 	logger.Info("pcd upload token generated successfully",
@@ -210,3 +355,118 @@ func (s *PCDFileService) GenerateUploadToken(ctx context.Context, userName strin
 
 	return resp, nil
 }
+
+// AnalyzePCDFile 重新读取 MinIO 中的点云文件并刷新元数据（用于解析功能上线前创建的地图）
+func (s *PCDFileService) AnalyzePCDFile(ctx context.Context, id uint) (*dto.PCDFileResponse, error) {
+	logger.Info("analyzing pcd file in service", zap.Uint("id", id))
+
+	file, err := s.pcdDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.New("pcd file not found")
+	}
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		return nil, errors.New("pcd file has no minio object")
+	}
+
+	object, err := s.inspectObject(ctx, *file.MinioPath)
+	if err != nil {
+		logger.Warn("pcd file analysis failed", zap.Error(err), zap.Uint("id", id))
+		return nil, fmt.Errorf("点云文件校验失败: %w", err)
+	}
+	if file.SHA256 != nil && !strings.EqualFold(*file.SHA256, object.SHA256) {
+		// 内容与记录不符时保留原 SHA-256，只标记损坏，避免重新解析掩盖对象被篡改
+		corrupted := entity.PCDIntegrityStatusCorrupted
+		now := time.Now()
+		file.IntegrityStatus, file.IntegrityCheckedAt = &corrupted, &now
+		if err := s.pcdDAO.UpdateIntegrity(ctx, file); err != nil {
+			return nil, err
+		}
+		logger.Warn("pcd object corrupted", zap.Uint("id", id), zap.String("expected", *file.SHA256), zap.String("actual", object.SHA256))
+		return nil, errors.New("点云对象内容与记录的 SHA-256 不一致，可能已损坏")
+	}
+	applyPCDMetadata(file, object)
+
+	if err := s.pcdDAO.Update(ctx, file); err != nil {
+		logger.Error("failed to save pcd metadata in service", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	logger.Info("pcd file analyzed successfully in service", zap.Uint("id", id), zap.Int("points", object.Meta.Header.Points))
+	return dto.NewPCDFileResponseFromEntity(file), nil
+}
+
+// pcdObject 服务端读取点云对象得到的信息
+type pcdObject struct {
+	Meta   *pcd.Metadata
+	Size   int64
+	MD5    string // 对象内容 MD5(十六进制)
+	SHA256 string // 对象内容 SHA-256(十六进制)
+}
+
+// inspectObject 读取存储中的点云对象并解析校验，返回元数据、实际大小与内容 MD5、SHA-256
+func (s *PCDFileService) inspectObject(ctx context.Context, objectKey string) (*pcdObject, error) {
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	object, info, err := store.Get(ctx, objectKey)
+	if err != nil {
+		logger.Warn("pcd object not found", zap.Error(err), zap.String("objectKey", objectKey))
+		return nil, fmt.Errorf("对象 %s 不存在: %w", objectKey, err)
+	}
+	defer object.Close()
+
+	// 解析的同时计算 MD5 与 SHA-256，解析结束后读完剩余字节
+	hash, sum := md5.New(), sha256.New()
+	body := io.TeeReader(object, io.MultiWriter(hash, sum))
+	meta, err := pcd.Analyze(body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return nil, err
+	}
+
+	logger.Info("pcd object inspected",
+		zap.String("objectKey", objectKey),
+		zap.Int64("size", info.Size),
+		zap.Int("points", meta.Header.Points),
+		zap.String("data", meta.Header.Data),
+	)
+	return &pcdObject{
+		Meta:   meta,
+		Size:   info.Size,
+		MD5:    hex.EncodeToString(hash.Sum(nil)),
+		SHA256: hex.EncodeToString(sum.Sum(nil)),
+	}, nil
+}
+
+// applyPCDMetadata 将解析结果写入点云地图实体，刚读完全文的对象同时视为完整性校验通过
+func applyPCDMetadata(file *entity.PCDFile, object *pcdObject) {
+	meta := object.Meta
+	h := meta.Header
+	fields := strings.Join(h.Fields, " ")
+	sha := object.SHA256
+	ok := entity.PCDIntegrityStatusOK
+	now := time.Now()
+
+	file.Size = int(object.Size)
+	file.SHA256 = &sha
+	file.IntegrityStatus, file.IntegrityCheckedAt = &ok, &now
+	file.PointCount = &h.Points
+	file.ValidPoints = &meta.ValidPoints
+	file.Fields = &fields
+	file.DataEncoding = &h.Data
+	file.Width = &h.Width
+	file.Height = &h.Height
+	file.MinX, file.MinY, file.MinZ = nil, nil, nil
+	file.MaxX, file.MaxY, file.MaxZ = nil, nil, nil
+	if b := meta.Bounds; b != nil {
+		file.MinX, file.MinY, file.MinZ = &b.MinX, &b.MinY, &b.MinZ
+		file.MaxX, file.MaxY, file.MaxZ = &b.MaxX, &b.MaxY, &b.MaxZ
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/storage"
+	"robot_scheduler/internal/testutil/mocks"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestPCDFileService_DeletePCDFile_RefusesWhenReferenced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockUploadDAO := mocks.NewMockPCDUploadDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mockUploadDAO, mocks.NewMockPCDJobDAO(ctrl), mocks.NewMockPCDFileVersionDAO(ctrl), mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	mockPCDDAO.EXPECT().CountDependents(ctx, uint(1)).Return(int64(2), int64(3), nil).Times(3)
+
+	if err := service.DeletePCDFile(ctx, 1, false, false); !errors.Is(err, ErrPCDFileInUse) {
+		t.Fatalf("Expected ErrPCDFileInUse for referenced pcd file, got %v", err)
+	}
+
+	mockPCDDAO.EXPECT().DeleteCascade(ctx, uint(1), false).Return(dao.ErrPCDFileHasActiveTasks)
+	if err := service.DeletePCDFile(ctx, 1, true, false); !errors.Is(err, ErrPCDFileInUse) {
+		t.Fatalf("Expected ErrPCDFileInUse with active tasks, got %v", err)
+	}
+
+	mockPCDDAO.EXPECT().DeleteCascade(ctx, uint(1), true).Return(nil)
+	if err := service.DeletePCDFile(ctx, 1, true, true); err != nil {
+		t.Fatalf("Expected cascade deletion to succeed, got %v", err)
+	}
+}
+
+func TestPCDFileService_DeletePCDFile_Unreferenced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockUploadDAO := mocks.NewMockPCDUploadDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mockUploadDAO, mocks.NewMockPCDJobDAO(ctrl), mocks.NewMockPCDFileVersionDAO(ctrl), mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	mockPCDDAO.EXPECT().CountDependents(ctx, uint(1)).Return(int64(0), int64(0), nil)
+	mockPCDDAO.EXPECT().Delete(ctx, uint(1)).Return(nil)
+
+	if err := service.DeletePCDFile(ctx, 1, false, false); err != nil {
+		t.Fatalf("DeletePCDFile failed: %v", err)
+	}
+}
+
+func TestPCDFileService_LinkDuplicateObject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mocks.NewMockPCDUploadDAO(ctrl), mocks.NewMockPCDJobDAO(ctrl), mocks.NewMockPCDFileVersionDAO(ctrl), mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	sha := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	existingKey, previewKey := "pcd/alice/1_scan.pcd", "pcd/alice/1_scan_preview.pcd"
+	succeeded := entity.PCDJobStatusSucceeded
+	existing := &entity.PCDFile{
+		Model:       gorm.Model{ID: 7},
+		MinioPath:   &existingKey,
+		PCDMetadata: entity.PCDMetadata{SHA256: &sha},
+		PCDPreview:  entity.PCDPreview{PreviewStatus: &succeeded, PreviewPath: &previewKey},
+	}
+	mockPCDDAO.EXPECT().FindBySHA256(ctx, sha).Return(existing, nil).Times(2)
+
+	uploadKey := "pcd/bob/2_scan_copy.pcd"
+	file := &entity.PCDFile{MinioPath: &uploadKey, PCDMetadata: entity.PCDMetadata{SHA256: &sha}}
+	duplicate, err := service.linkDuplicateObject(ctx, file)
+	if err != nil || duplicate != existing {
+		t.Fatalf("Expected existing file to be reused, got %+v (%v)", duplicate, err)
+	}
+	if *file.MinioPath != existingKey || !previewReady(file) || *file.PreviewPath != previewKey {
+		t.Errorf("Expected object and preview of existing file, got %s %+v", *file.MinioPath, file.PCDPreview)
+	}
+
+	// 已指向同一对象时不算重复
+	if duplicate, _ = service.linkDuplicateObject(ctx, file); duplicate != nil {
+		t.Errorf("Expected no duplicate for the same object, got %+v", duplicate)
+	}
+	// 没有 SHA-256 时不查询
+	if duplicate, _ = service.linkDuplicateObject(ctx, &entity.PCDFile{MinioPath: &uploadKey}); duplicate != nil {
+		t.Errorf("Expected no duplicate without sha256, got %+v", duplicate)
+	}
+}
+
+func TestPCDFileService_RemoveDuplicateObjectOnlyOwnUploads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local, err := storage.NewLocal(storage.LocalConfig{Root: t.TempDir(), Secret: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	storage.SetBackend(local)
+	defer storage.SetBackend(nil)
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockUploadDAO := mocks.NewMockPCDUploadDAO(ctrl)
+	mockVersionDAO := mocks.NewMockPCDFileVersionDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mockUploadDAO, mocks.NewMockPCDJobDAO(ctrl), mockVersionDAO, mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	aliceKey, bobKey, signedKey := "pcd/alice/1_scan.pcd", "pcd/bob/2_scan.pcd", "pcd/alice/3_scan.pcd"
+	for _, key := range []string{aliceKey, bobKey, signedKey} {
+		if err := local.Put(ctx, key, strings.NewReader("data"), 4, ""); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	// 其他用户前缀下的对象不删除，也不查询
+	service.removeDuplicateObject(ctx, "alice", bobKey, 5)
+	// 前缀内但服务端没有为该用户签发过上传凭证的对象不删除
+	mockUploadDAO.EXPECT().FindByObjectKey(ctx, signedKey).Return(nil, nil)
+	service.removeDuplicateObject(ctx, "alice", signedKey, 5)
+
+	mockUploadDAO.EXPECT().FindByObjectKey(ctx, aliceKey).Return(&entity.PCDUpload{ObjectKey: aliceKey, UserName: "alice"}, nil)
+	mockPCDDAO.EXPECT().CountByMinioPath(ctx, aliceKey).Return(int64(0), nil)
+	mockVersionDAO.EXPECT().CountByMinioPath(ctx, aliceKey, uint(5)).Return(int64(0), nil)
+	service.removeDuplicateObject(ctx, "alice", aliceKey, 5)
+
+	for key, kept := range map[string]bool{aliceKey: false, bobKey: true, signedKey: true} {
+		if _, err := local.Stat(ctx, key); (err == nil) != kept {
+			t.Errorf("Object %s: expected kept=%v, got err %v", key, kept, err)
+		}
+	}
+}
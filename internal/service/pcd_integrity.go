@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"robot_scheduler/internal/config"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/storage"
+
+	"go.uber.org/zap"
+)
+
+// pcdIntegrityBatch 每次清理周期最多排队的完整性校验任务数
+const pcdIntegrityBatch = 20
+
+// linkDuplicateObject 查找内容 SHA-256 相同的已有点云地图，找到时把 file 指向其对象并沿用已生成的预览，
+// 返回被复用对象的点云地图；file 已指向该对象或没有可复用的对象时返回 nil
+func (s *PCDFileService) linkDuplicateObject(ctx context.Context, file *entity.PCDFile) (*entity.PCDFile, error) {
+	if file.SHA256 == nil || file.MinioPath == nil || *file.MinioPath == "" {
+		return nil, nil
+	}
+	existing, err := s.pcdDAO.FindBySHA256(ctx, *file.SHA256)
+	if err != nil {
+		logger.Error("failed to find pcd file by sha256", zap.Error(err), zap.String("sha256", *file.SHA256))
+		return nil, err
+	}
+	if existing == nil || sameObject(existing.MinioPath, file.MinioPath) {
+		return nil, nil
+	}
+
+	logger.Info("duplicate pcd content detected",
+		zap.String("objectKey", *file.MinioPath),
+		zap.Uint("existingID", existing.ID),
+		zap.String("existingObjectKey", *existing.MinioPath),
+	)
+	file.MinioPath = existing.MinioPath
+	if previewReady(existing) {
+		file.PCDPreview = existing.PCDPreview
+	}
+	return existing, nil
+}
+
+// removeDuplicateObject 去重后删除不再被任何点云地图或版本引用的重复对象。
+// 只删除服务端为 userName 签发上传凭证、位于其上传前缀下的对象，请求中指定的其他对象一律保留；
+// 删除失败只记录日志，由对账报告为孤立对象
+func (s *PCDFileService) removeDuplicateObject(ctx context.Context, userName, objectKey string, fileID uint) {
+	if !ownUploadKey(userName, objectKey) {
+		return
+	}
+	upload, err := s.uploadDAO.FindByObjectKey(ctx, objectKey)
+	if err != nil || upload == nil || upload.UserName != userName {
+		logger.Info("keeping duplicate pcd object not uploaded by caller", zap.String("objectKey", objectKey), zap.String("userName", userName))
+		return
+	}
+	inUse, err := s.objectInUse(ctx, objectKey, fileID)
+	if err != nil || inUse {
+		return
+	}
+	store, err := pcdStorage()
+	if err != nil {
+		return
+	}
+	if err := store.Remove(ctx, objectKey); err != nil {
+		logger.Warn("failed to remove duplicate pcd object", zap.Error(err), zap.String("objectKey", objectKey))
+		return
+	}
+	logger.Info("duplicate pcd object removed", zap.String("objectKey", objectKey), zap.Uint("id", fileID))
+}
+
+// previewReady 判断点云地图的预览是否已生成，可供共享同一对象的地图直接沿用
+func previewReady(file *entity.PCDFile) bool {
+	return file != nil && file.PreviewStatus != nil && *file.PreviewStatus == entity.PCDJobStatusSucceeded
+}
+
+// EnqueueIntegrityChecks 为从未校验或超过校验周期的点云地图排队完整性校验任务，返回排队数量
+func (s *PCDFileService) EnqueueIntegrityChecks(ctx context.Context) (int, error) {
+	cfg := config.Get()
+	if cfg == nil || cfg.Minio == nil || cfg.Minio.IntegrityInterval <= 0 {
+		return 0, nil
+	}
+
+	interval := time.Duration(cfg.Minio.IntegrityInterval) * time.Hour
+	files, err := s.pcdDAO.FindIntegrityDue(ctx, time.Now().Add(-interval), pcdIntegrityBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, file := range files {
+		if _, err := enqueuePCDJob(ctx, s.pcdDAO, s.jobDAO, file, entity.PCDJobTypeIntegrity, nil); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+
+	if queued > 0 {
+		logger.Info("pcd integrity checks queued", zap.Int("count", queued))
+	}
+	return queued, nil
+}
+
+// EnqueueIntegrityCheck 为点云地图创建完整性校验任务，已有未结束的校验任务时直接返回该任务
+func (s *PCDJobService) EnqueueIntegrityCheck(ctx context.Context, pcdFileID uint) (*dto.PCDJobResponse, error) {
+	logger.Info("enqueueing pcd integrity job in service", zap.Uint("pcdFileID", pcdFileID))
+
+	file, err := s.pcdDAO.FindByID(ctx, pcdFileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.New("pcd file not found")
+	}
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		return nil, errors.New("pcd file has no minio object")
+	}
+
+	job, err := enqueuePCDJob(ctx, s.pcdDAO, s.jobDAO, file, entity.PCDJobTypeIntegrity, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewPCDJobResponseFromEntity(job), nil
+}
+
+// pcdIntegrityResult 完整性校验任务结果
+type pcdIntegrityResult struct {
+	Status   entity.PCDIntegrityStatus `json:"status"`
+	Expected string                    `json:"expected,omitempty"` // 校验前记录的 SHA-256，校验功能上线前的地图为空
+	Actual   string                    `json:"actual,omitempty"`   // 重新计算的 SHA-256
+	Size     int64                     `json:"size"`
+}
+
+// checkIntegrity 重新读取点云对象计算 SHA-256 并与记录比对，结果写回点云地图。
+// 没有记录 SHA-256 的地图以本次结果为基准；对象丢失或内容不一致时标记并记录告警日志，任务本身视为成功
+func (s *PCDJobService) checkIntegrity(ctx context.Context, job *entity.PCDJob) (*pcdIntegrityResult, error) {
+	file, err := s.pcdDAO.FindByID(ctx, job.PCDFileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.New("pcd file not found")
+	}
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		return nil, errors.New("pcd file has no minio object")
+	}
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pcdIntegrityResult{}
+	if file.SHA256 != nil {
+		result.Expected = *file.SHA256
+	}
+
+	sum := sha256.New()
+	object, _, err := store.Get(ctx, *file.MinioPath)
+	if err == nil {
+		result.Size, err = io.Copy(sum, object)
+		object.Close()
+	}
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		result.Status = entity.PCDIntegrityStatusMissing
+		logger.Warn("pcd object missing", zap.Uint("id", file.ID), zap.String("objectKey", *file.MinioPath))
+	case err != nil:
+		return nil, err
+	default:
+		result.Actual = hex.EncodeToString(sum.Sum(nil))
+		result.Status = entity.PCDIntegrityStatusOK
+		if file.SHA256 == nil {
+			file.SHA256 = &result.Actual
+		} else if !strings.EqualFold(*file.SHA256, result.Actual) {
+			result.Status = entity.PCDIntegrityStatusCorrupted
+			logger.Warn("pcd object corrupted",
+				zap.Uint("id", file.ID),
+				zap.String("objectKey", *file.MinioPath),
+				zap.String("expected", *file.SHA256),
+				zap.String("actual", result.Actual),
+			)
+		}
+	}
+
+	now := time.Now()
+	file.IntegrityStatus, file.IntegrityCheckedAt = &result.Status, &now
+	if err := s.pcdDAO.UpdateIntegrity(ctx, file); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"path"
+	"strings"
+	"time"
+
+	"robot_scheduler/internal/config"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/pcd"
+
+	"go.uber.org/zap"
+)
+
+// PCDJobService 点云后台任务服务（上传解析、降采样预览、缩略图、二维栅格、完整性校验等）
+type PCDJobService struct {
+	pcdDAO     dao.PCDFileDAO
+	jobDAO     dao.PCDJobDAO
+	gridDAO    dao.OccupancyGridDAO
+	pcdService *PCDFileService
+}
+
+func NewPCDJobService(pcdDAO dao.PCDFileDAO, jobDAO dao.PCDJobDAO, gridDAO dao.OccupancyGridDAO, pcdService *PCDFileService) *PCDJobService {
+	return &PCDJobService{
+		pcdDAO:     pcdDAO,
+		jobDAO:     jobDAO,
+		gridDAO:    gridDAO,
+		pcdService: pcdService,
+	}
+}
+
+// Run 轮询并依次执行等待中的任务，直到 ctx 取消
+func (s *PCDJobService) Run(ctx context.Context) {
+	settings := previewSettings()
+	logger.Info("starting pcd job worker", zap.Duration("pollInterval", settings.pollInterval))
+
+	if _, err := s.jobDAO.ResetRunning(ctx); err != nil {
+		logger.Warn("failed to reset interrupted pcd jobs", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(settings.pollInterval)
+	defer ticker.Stop()
+	for {
+		// 有任务时连续执行，队列空了再等待
+		for {
+			ran, err := s.RunOnce(ctx)
+			if err != nil {
+				logger.Warn("pcd job worker error", zap.Error(err))
+			}
+			if !ran || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 领取并执行一个任务，没有等待中的任务时返回 false
+func (s *PCDJobService) RunOnce(ctx context.Context) (bool, error) {
+	job, err := s.jobDAO.ClaimNext(ctx)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	logger.Info("running pcd job", zap.Uint("id", job.ID), zap.Uint("pcdFileID", job.PCDFileID), zap.String("type", string(job.Type)))
+
+	result, err := s.execute(ctx, job)
+	if err != nil {
+		logger.Warn("pcd job failed", zap.Error(err), zap.Uint("id", job.ID))
+		msg := err.Error()
+		job.Status = entity.PCDJobStatusFailed
+		job.Error = &msg
+	} else {
+		raw, _ := json.Marshal(result)
+		text := string(raw)
+		job.Status = entity.PCDJobStatusSucceeded
+		job.Result = &text
+		logger.Info("pcd job succeeded", zap.Uint("id", job.ID))
+	}
+	return true, s.jobDAO.Finish(ctx, job)
+}
+
+// execute 按类型执行任务；点云内容由用户上传，处理中的 panic 转为任务失败，避免后台协程崩溃带走整个服务
+func (s *PCDJobService) execute(ctx context.Context, job *entity.PCDJob) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("pcd job panicked", zap.Any("panic", r), zap.Uint("id", job.ID), zap.Stack("stack"))
+			result, err = nil, fmt.Errorf("pcd job panicked: %v", r)
+		}
+	}()
+
+	if err := s.checkJobSource(ctx, job); err != nil {
+		return nil, err
+	}
+
+	switch job.Type {
+	case entity.PCDJobTypeAnalyze:
+		return s.pcdService.analyzeUpload(ctx, job)
+	case entity.PCDJobTypePreview:
+		return s.generatePreview(ctx, job)
+	case entity.PCDJobTypeOccupancy:
+		return s.generateOccupancyGrid(ctx, job)
+	case entity.PCDJobTypeIntegrity:
+		return s.checkIntegrity(ctx, job)
+	default:
+		return nil, fmt.Errorf("unknown pcd job type %q", job.Type)
+	}
+}
+
+// checkJobSource 点云地图的对象在排队后被替换时任务作废，由替换时排队的新任务处理新对象
+func (s *PCDJobService) checkJobSource(ctx context.Context, job *entity.PCDJob) error {
+	if job.SourceKey == nil {
+		return nil
+	}
+	file, err := s.pcdDAO.FindByID(ctx, job.PCDFileID)
+	if err != nil {
+		return err
+	}
+	if file != nil && !sameObject(file.MinioPath, job.SourceKey) {
+		return fmt.Errorf("点云地图对象已替换(任务创建时为 %s)，任务作废", *job.SourceKey)
+	}
+	return nil
+}
+
+// EnqueuePreview 为点云地图创建预览生成任务，已有未结束的任务时直接返回该任务
+func (s *PCDJobService) EnqueuePreview(ctx context.Context, pcdFileID uint) (*dto.PCDJobResponse, error) {
+	logger.Info("enqueueing pcd preview job in service", zap.Uint("pcdFileID", pcdFileID))
+
+	file, err := s.pcdDAO.FindByID(ctx, pcdFileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.New("pcd file not found")
+	}
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		return nil, errors.New("pcd file has no minio object")
+	}
+
+	job, err := enqueuePCDJob(ctx, s.pcdDAO, s.jobDAO, file, entity.PCDJobTypePreview, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewPCDJobResponseFromEntity(job), nil
+}
+
+// ListJobs 查询点云地图的后台任务
+func (s *PCDJobService) ListJobs(ctx context.Context, pcdFileID uint) ([]*dto.PCDJobResponse, error) {
+	jobs, err := s.jobDAO.ListByFile(ctx, pcdFileID)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*dto.PCDJobResponse, 0, len(jobs))
+	for _, j := range jobs {
+		list = append(list, dto.NewPCDJobResponseFromEntity(j))
+	}
+	return list, nil
+}
+
+// pcdPreviewResult 预览任务结果
+type pcdPreviewResult struct {
+	SourcePoints  int     `json:"sourcePoints"`
+	PreviewPoints int     `json:"previewPoints"`
+	LeafSize      float64 `json:"leafSize"`
+	PreviewPath   string  `json:"previewPath"`
+	ThumbnailPath string  `json:"thumbnailPath"`
+}
+
+// generatePreview 读取原始点云做体素降采样，写出预览 PCD 与俯视缩略图
+func (s *PCDJobService) generatePreview(ctx context.Context, job *entity.PCDJob) (result *pcdPreviewResult, err error) {
+	file, err := s.pcdDAO.FindByID(ctx, job.PCDFileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.New("pcd file not found")
+	}
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		return nil, errors.New("pcd file has no minio object")
+	}
+
+	status := entity.PCDJobStatusRunning
+	preview := file.PCDPreview
+	preview.PreviewStatus = &status
+	if err := s.pcdDAO.UpdatePreview(ctx, file.ID, &preview); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			failed := entity.PCDJobStatusFailed
+			preview.PreviewStatus = &failed
+			if updateErr := s.pcdDAO.UpdatePreview(ctx, file.ID, &preview); updateErr != nil {
+				logger.Warn("failed to mark pcd preview failed", zap.Error(updateErr), zap.Uint("id", file.ID))
+			}
+		}
+	}()
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	object, _, err := store.Get(ctx, *file.MinioPath)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	settings := previewSettings()
+	grid := pcd.NewVoxelGrid(settings.leafSize, settings.maxPoints)
+	header, err := pcd.Downsample(object, grid)
+	if err != nil {
+		return nil, fmt.Errorf("点云解析失败: %w", err)
+	}
+	if grid.Len() == 0 {
+		return nil, errors.New("点云没有有效点")
+	}
+	points := grid.Points()
+
+	var pcdBuf bytes.Buffer
+	if err := pcd.WriteBinary(&pcdBuf, points); err != nil {
+		return nil, err
+	}
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, pcd.RenderTopDown(points, settings.thumbnailSize)); err != nil {
+		return nil, err
+	}
+
+	previewKey, thumbnailKey := pcdPreviewKeys(*file.MinioPath)
+	if err := store.Put(ctx, previewKey, &pcdBuf, int64(pcdBuf.Len()), "application/octet-stream"); err != nil {
+		return nil, err
+	}
+	if err := store.Put(ctx, thumbnailKey, &pngBuf, int64(pngBuf.Len()), "image/png"); err != nil {
+		return nil, err
+	}
+
+	succeeded := entity.PCDJobStatusSucceeded
+	count := len(points)
+	leaf := grid.Leaf()
+	preview = entity.PCDPreview{
+		PreviewStatus: &succeeded,
+		PreviewPath:   &previewKey,
+		ThumbnailPath: &thumbnailKey,
+		PreviewPoints: &count,
+		PreviewLeaf:   &leaf,
+	}
+	if err := s.pcdDAO.UpdatePreview(ctx, file.ID, &preview); err != nil {
+		return nil, err
+	}
+
+	return &pcdPreviewResult{
+		SourcePoints:  header.Points,
+		PreviewPoints: count,
+		LeafSize:      leaf,
+		PreviewPath:   previewKey,
+		ThumbnailPath: thumbnailKey,
+	}, nil
+}
+
+// enqueuePCDJob 创建后台任务，预览任务同时将点云地图的预览状态置为等待
+// 已有参数相同、针对同一对象且未结束的同类任务时直接返回该任务；对象已替换时创建新任务，旧任务执行时失败
+func enqueuePCDJob(ctx context.Context, pcdDAO dao.PCDFileDAO, jobDAO dao.PCDJobDAO, file *entity.PCDFile, jobType entity.PCDJobType, params *string) (*entity.PCDJob, error) {
+	active, err := jobDAO.FindActive(ctx, file.ID, jobType)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil && sameJobParams(active.Params, params) && sameObject(active.SourceKey, file.MinioPath) {
+		return active, nil
+	}
+
+	job := &entity.PCDJob{
+		PCDFileID: file.ID,
+		Type:      jobType,
+		Status:    entity.PCDJobStatusPending,
+		Params:    params,
+		SourceKey: file.MinioPath,
+	}
+	if err := jobDAO.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if jobType == entity.PCDJobTypePreview {
+		pending := entity.PCDJobStatusPending
+		file.PreviewStatus = &pending
+		if err := pcdDAO.UpdatePreview(ctx, file.ID, &file.PCDPreview); err != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+func sameJobParams(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// pcdPreviewKeys 预览 PCD 与缩略图的对象 Key，与原始文件位于同一目录
+func pcdPreviewKeys(objectKey string) (previewKey, thumbnailKey string) {
+	base := strings.TrimSuffix(objectKey, path.Ext(objectKey))
+	return base + "_preview.pcd", base + "_thumbnail.png"
+}
+
+type pcdPreviewSettings struct {
+	enabled       bool
+	leafSize      float64
+	maxPoints     int
+	thumbnailSize int
+	pollInterval  time.Duration
+}
+
+// previewSettings 读取预览配置并补全默认值
+func previewSettings() pcdPreviewSettings {
+	settings := pcdPreviewSettings{
+		leafSize:      0.1,
+		maxPoints:     500000,
+		thumbnailSize: 512,
+		pollInterval:  5 * time.Second,
+	}
+	cfg := config.Get()
+	if cfg == nil || cfg.Preview == nil {
+		return settings
+	}
+	p := cfg.Preview
+	settings.enabled = p.Enabled
+	if p.LeafSize > 0 {
+		settings.leafSize = p.LeafSize
+	}
+	if p.MaxPoints > 0 {
+		settings.maxPoints = p.MaxPoints
+	}
+	if p.ThumbnailSize > 0 {
+		settings.thumbnailSize = p.ThumbnailSize
+	}
+	if p.PollInterval > 0 {
+		settings.pollInterval = time.Duration(p.PollInterval) * time.Second
+	}
+	return settings
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"robot_scheduler/internal/config"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/storage"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// pcdMinPartSize S3 要求除最后一片外每片不小于 5MB
+	pcdMinPartSize = 5 << 20
+	// pcdDefaultPartSize 默认分片大小
+	pcdDefaultPartSize = 64 << 20
+	// pcdMaxParts S3 允许的最大分片数
+	pcdMaxParts = 10000
+	// pcdMultipartExpire 未完成分片上传的默认保留时长
+	pcdMultipartExpire = 24 * time.Hour
+)
+
+// InitMultipartUpload 初始化分片上传，返回对象 Key 与分片规划
+func (s *PCDFileService) InitMultipartUpload(ctx context.Context, userName string, req *dto.PCDMultipartInitRequest) (*dto.PCDMultipartInitResponse, error) {
+	logger.Info("initiating pcd multipart upload in service", zap.String("fileName", req.FileName), zap.Int64("size", req.Size), zap.String("userName", userName))
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	if userName == "" {
+		logger.Warn("pcd upload rejected without user")
+		return nil, fmt.Errorf("%w: 未识别到当前用户", ErrUploadUserRequired)
+	}
+
+	partSize, err := planPartSize(req.Size, req.PartSize)
+	if err != nil {
+		return nil, err
+	}
+
+	objectKey, err := newUploadObjectKey(userName, req.FileName)
+	if err != nil {
+		return nil, err
+	}
+	uploadID, err := store.NewMultipart(ctx, objectKey, "application/octet-stream")
+	if err != nil {
+		logger.Error("failed to initiate multipart upload", zap.Error(err), zap.String("objectKey", objectKey))
+		return nil, err
+	}
+
+	upload := &entity.PCDUpload{
+		ObjectKey:         objectKey,
+		UserName:          userName,
+		FileName:          req.FileName,
+		DeclaredSize:      req.Size,
+		Checksum:          req.Checksum,
+		SHA256:            req.SHA256,
+		Status:            entity.PCDUploadStatusPending,
+		ExpireAt:          time.Now().Add(multipartExpire()),
+		MultipartUploadID: &uploadID,
+		PartSize:          &partSize,
+	}
+	if err := s.uploadDAO.Create(ctx, upload); err != nil {
+		logger.Error("failed to record pcd multipart upload", zap.Error(err), zap.String("objectKey", objectKey))
+		if abortErr := store.AbortMultipart(ctx, objectKey, uploadID); abortErr != nil {
+			logger.Warn("failed to abort multipart upload", zap.Error(abortErr), zap.String("objectKey", objectKey))
+		}
+		return nil, err
+	}
+
+	logger.Info("pcd multipart upload initiated successfully", zap.String("objectKey", objectKey), zap.Int64("partSize", partSize))
+	return &dto.PCDMultipartInitResponse{
+		ObjectKey: objectKey,
+		PartSize:  partSize,
+		PartCount: partCount(req.Size, partSize),
+		ExpireAt:  upload.ExpireAt.Unix(),
+	}, nil
+}
+
+// PresignUploadParts 为指定分片生成预签名 PUT URL，并顺延未完成上传的保留时间
+// 可重复调用，用于 URL 过期或断线后续传
+func (s *PCDFileService) PresignUploadParts(ctx context.Context, userName string, req *dto.PCDMultipartPartURLRequest) (*dto.PCDMultipartPartURLResponse, error) {
+	logger.Info("presigning pcd upload parts in service", zap.String("objectKey", req.ObjectKey), zap.Int("parts", len(req.PartNumbers)))
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := s.findMultipartUpload(ctx, userName, req.ObjectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	count := partCount(upload.DeclaredSize, *upload.PartSize)
+	expire := uploadURLExpire()
+	resp := &dto.PCDMultipartPartURLResponse{
+		ObjectKey: upload.ObjectKey,
+		Parts:     make([]*dto.PCDMultipartPartURL, 0, len(req.PartNumbers)),
+		ExpireAt:  time.Now().Add(expire).Unix(),
+	}
+	for _, n := range req.PartNumbers {
+		if n > count {
+			return nil, fmt.Errorf("分片序号 %d 超出范围，共 %d 片", n, count)
+		}
+		u, err := store.PresignPart(ctx, upload.ObjectKey, *upload.MultipartUploadID, n, expire, *upload.PartSize)
+		if err != nil {
+			logger.Error("failed to presign upload part", zap.Error(err), zap.String("objectKey", upload.ObjectKey), zap.Int("partNumber", n))
+			return nil, err
+		}
+		resp.Parts = append(resp.Parts, &dto.PCDMultipartPartURL{PartNumber: n, UploadURL: u})
+	}
+
+	// 仍在上传的分片任务不应被清理
+	if err := s.uploadDAO.ExtendExpire(ctx, upload.ID, time.Now().Add(multipartExpire())); err != nil {
+		if errors.Is(err, dao.ErrPCDUploadNotPending) {
+			return nil, errors.New("pcd upload already completed or expired")
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ListUploadedParts 查询已上传的分片及缺失的分片序号
+func (s *PCDFileService) ListUploadedParts(ctx context.Context, userName, objectKey string) (*dto.PCDMultipartPartsResponse, error) {
+	logger.Debug("listing pcd uploaded parts in service", zap.String("objectKey", objectKey))
+
+	upload, err := s.findMultipartUpload(ctx, userName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := s.listParts(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	count := partCount(upload.DeclaredSize, *upload.PartSize)
+	resp := &dto.PCDMultipartPartsResponse{
+		ObjectKey:    upload.ObjectKey,
+		PartSize:     *upload.PartSize,
+		PartCount:    count,
+		Parts:        make([]*dto.PCDUploadedPart, 0, len(parts)),
+		MissingParts: make([]int, 0),
+		ExpireAt:     upload.ExpireAt.Unix(),
+	}
+	uploaded := make(map[int]bool, len(parts))
+	for _, p := range parts {
+		uploaded[p.PartNumber] = true
+		resp.Parts = append(resp.Parts, &dto.PCDUploadedPart{
+			PartNumber:   p.PartNumber,
+			Size:         p.Size,
+			ETag:         p.ETag,
+			LastModified: p.LastModified,
+		})
+	}
+	for n := 1; n <= count; n++ {
+		if !uploaded[n] {
+			resp.MissingParts = append(resp.MissingParts, n)
+		}
+	}
+	return resp, nil
+}
+
+// CompleteMultipartUpload 合并全部分片，并按普通上传的流程校验后创建点云地图
+// 合并成功但创建地图失败（如名称重复）时可再次调用，此时跳过合并直接校验
+func (s *PCDFileService) CompleteMultipartUpload(ctx context.Context, userName string, req *dto.PCDFileCompleteUploadRequest) (*dto.PCDFileResponse, error) {
+	logger.Info("completing pcd multipart upload in service", zap.String("objectKey", req.ObjectKey))
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := s.findMultipartUpload(ctx, userName, req.ObjectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := s.listParts(ctx, upload)
+	if err != nil {
+		if errors.Is(err, storage.ErrNoSuchUpload) {
+			// 分片已在之前的调用中合并
+			return s.CompleteUpload(ctx, userName, req)
+		}
+		return nil, err
+	}
+
+	count := partCount(upload.DeclaredSize, *upload.PartSize)
+	for i, p := range parts {
+		if p.PartNumber != i+1 {
+			return nil, fmt.Errorf("分片 %d 尚未上传", i+1)
+		}
+	}
+	if len(parts) != count {
+		return nil, fmt.Errorf("分片 %d 尚未上传", len(parts)+1)
+	}
+
+	if err := store.CompleteMultipart(ctx, upload.ObjectKey, *upload.MultipartUploadID, parts); err != nil {
+		logger.Error("failed to complete multipart upload", zap.Error(err), zap.String("objectKey", upload.ObjectKey))
+		return nil, err
+	}
+
+	logger.Info("pcd multipart upload assembled", zap.String("objectKey", upload.ObjectKey), zap.Int("parts", count))
+	return s.CompleteUpload(ctx, userName, req)
+}
+
+// AbortMultipartUpload 放弃分片上传并删除已上传的分片
+func (s *PCDFileService) AbortMultipartUpload(ctx context.Context, userName, objectKey string) error {
+	logger.Info("aborting pcd multipart upload in service", zap.String("objectKey", objectKey))
+
+	store, err := pcdStorage()
+	if err != nil {
+		return err
+	}
+
+	upload, err := s.findMultipartUpload(ctx, userName, objectKey)
+	if err != nil {
+		return err
+	}
+
+	if err := s.uploadDAO.MarkAborted(ctx, upload.ID); err != nil {
+		if errors.Is(err, dao.ErrPCDUploadNotPending) {
+			return errors.New("pcd upload already completed or expired")
+		}
+		return err
+	}
+
+	if err := store.AbortMultipart(ctx, upload.ObjectKey, *upload.MultipartUploadID); err != nil && !errors.Is(err, storage.ErrNoSuchUpload) {
+		logger.Warn("failed to abort multipart upload", zap.Error(err), zap.String("objectKey", upload.ObjectKey))
+		return err
+	}
+
+	logger.Info("pcd multipart upload aborted successfully", zap.String("objectKey", upload.ObjectKey))
+	return nil
+}
+
+// findMultipartUpload 查询当前用户待完成的分片上传记录
+func (s *PCDFileService) findMultipartUpload(ctx context.Context, userName, objectKey string) (*entity.PCDUpload, error) {
+	upload, err := s.findPendingUpload(ctx, userName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	if upload.MultipartUploadID == nil || upload.PartSize == nil {
+		return nil, errors.New("pcd upload is not a multipart upload")
+	}
+	return upload, nil
+}
+
+// listParts 读取全部已上传分片，按分片序号升序
+func (s *PCDFileService) listParts(ctx context.Context, upload *entity.PCDUpload) ([]storage.Part, error) {
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := store.ListParts(ctx, upload.ObjectKey, *upload.MultipartUploadID)
+	if err != nil {
+		logger.Error("failed to list uploaded parts", zap.Error(err), zap.String("objectKey", upload.ObjectKey))
+		return nil, err
+	}
+	return parts, nil
+}
+
+// planPartSize 确定分片大小：不小于 5MB，且分片数不超过 10000
+func planPartSize(size, requested int64) (int64, error) {
+	partSize := requested
+	if partSize <= 0 {
+		partSize = pcdDefaultPartSize
+	}
+	if partSize < pcdMinPartSize {
+		return 0, fmt.Errorf("分片大小不能小于 %d 字节", pcdMinPartSize)
+	}
+	if minSize := (size + pcdMaxParts - 1) / pcdMaxParts; partSize < minSize {
+		if requested > 0 {
+			return 0, fmt.Errorf("分片过多，分片大小至少为 %d 字节", minSize)
+		}
+		partSize = minSize
+	}
+	return partSize, nil
+}
+
+// partCount 计算分片数量
+func partCount(size, partSize int64) int {
+	return int((size + partSize - 1) / partSize)
+}
+
+// multipartExpire 未完成分片上传的保留时长
+func multipartExpire() time.Duration {
+	if cfg := config.Get(); cfg != nil && cfg.Minio != nil && cfg.Minio.MultipartExpire > 0 {
+		return time.Duration(cfg.Minio.MultipartExpire) * time.Hour
+	}
+	return pcdMultipartExpire
+}
+
+// uploadURLExpire 预签名上传 URL 的有效期
+func uploadURLExpire() time.Duration {
+	if cfg := config.Get(); cfg != nil && cfg.Minio != nil && cfg.Minio.UploadExpire > 0 {
+		return time.Duration(cfg.Minio.UploadExpire) * time.Second
+	}
+	return 10 * time.Minute
+}
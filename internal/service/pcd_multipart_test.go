@@ -0,0 +1,53 @@
+package service
+
+import "testing"
+
+func TestPlanPartSize(t *testing.T) {
+	const gb = int64(1) << 30
+
+	size, err := planPartSize(4*gb, 0)
+	if err != nil || size != pcdDefaultPartSize {
+		t.Errorf("Expected default part size, got %d, %v", size, err)
+	}
+	if n := partCount(4*gb, size); n != 64 {
+		t.Errorf("Expected 64 parts, got %d", n)
+	}
+
+	// 超大文件自动放大分片，保证不超过 10000 片
+	size, err = planPartSize(1000*gb, 0)
+	if err != nil {
+		t.Fatalf("planPartSize failed: %v", err)
+	}
+	if n := partCount(1000*gb, size); n > pcdMaxParts {
+		t.Errorf("Expected at most %d parts, got %d", pcdMaxParts, n)
+	}
+
+	if _, err := planPartSize(gb, 1<<20); err == nil {
+		t.Error("Expected part size below 5MB to be rejected")
+	}
+	if _, err := planPartSize(100*gb, pcdMinPartSize); err == nil {
+		t.Error("Expected requested part size producing too many parts to be rejected")
+	}
+
+	if n := partCount(pcdMinPartSize+1, pcdMinPartSize); n != 2 {
+		t.Errorf("Expected 2 parts, got %d", n)
+	}
+}
+
+func TestNewUploadObjectKey(t *testing.T) {
+	// 同一秒内生成的 Key 不能重复，且都位于用户自己的前缀下
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		key, err := newUploadObjectKey("alice", "site.pcd")
+		if err != nil {
+			t.Fatalf("newUploadObjectKey failed: %v", err)
+		}
+		if seen[key] {
+			t.Fatalf("Duplicate object key %q", key)
+		}
+		seen[key] = true
+		if !ownUploadKey("alice", key) {
+			t.Errorf("Expected %q under alice's prefix", key)
+		}
+	}
+}
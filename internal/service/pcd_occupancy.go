@@ -0,0 +1,257 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"robot_scheduler/internal/config"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/pcd"
+
+	"go.uber.org/zap"
+)
+
+// 二维栅格对象的 Key 前缀，与点云对象分开存放
+const occupancyObjectPrefix = "grid/"
+
+// pcdOccupancyParams 栅格生成任务参数，序列化后保存在任务上
+type pcdOccupancyParams struct {
+	Name       string  `json:"name"`
+	UserName   string  `json:"userName"`
+	Resolution float64 `json:"resolution"`
+	MinZ       float64 `json:"minZ"`
+	MaxZ       float64 `json:"maxZ"`
+	MinPoints  int     `json:"minPoints"`
+}
+
+// pcdOccupancyResult 栅格生成任务结果
+type pcdOccupancyResult struct {
+	GridID        uint    `json:"gridId"`
+	Width         int     `json:"width"`
+	Height        int     `json:"height"`
+	OriginX       float64 `json:"originX"`
+	OriginY       float64 `json:"originY"`
+	OccupiedCells int     `json:"occupiedCells"`
+	FreeCells     int     `json:"freeCells"`
+	Outliers      int     `json:"outliers,omitempty"` // 超出坐标范围被忽略的点数
+}
+
+// EnqueueOccupancyGrid 为点云地图创建二维占据栅格生成任务，参数相同且未结束的任务已存在时直接返回该任务
+func (s *PCDJobService) EnqueueOccupancyGrid(ctx context.Context, pcdFileID uint, userName string, req *dto.PCDOccupancyGridRequest) (*dto.PCDJobResponse, error) {
+	logger.Info("enqueueing pcd occupancy grid job in service", zap.Uint("pcdFileID", pcdFileID))
+
+	file, err := s.pcdDAO.FindByID(ctx, pcdFileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.New("pcd file not found")
+	}
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		return nil, errors.New("pcd file has no minio object")
+	}
+
+	params := occupancyDefaults()
+	fileName := pcdDownloadFileName(file)
+	params.Name = strings.TrimSuffix(fileName, path.Ext(fileName))
+	params.UserName = userName
+	if req.Name != nil && strings.TrimSpace(*req.Name) != "" {
+		params.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Resolution != nil {
+		params.Resolution = *req.Resolution
+	}
+	if req.MinZ != nil {
+		params.MinZ = *req.MinZ
+	}
+	if req.MaxZ != nil {
+		params.MaxZ = *req.MaxZ
+	}
+	if req.MinPoints != nil {
+		params.MinPoints = *req.MinPoints
+	}
+	if params.MinZ >= params.MaxZ {
+		return nil, fmt.Errorf("高度带下限 %g 必须小于上限 %g", params.MinZ, params.MaxZ)
+	}
+
+	raw, _ := json.Marshal(params)
+	text := string(raw)
+	job, err := enqueuePCDJob(ctx, s.pcdDAO, s.jobDAO, file, entity.PCDJobTypeOccupancy, &text)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewPCDJobResponseFromEntity(job), nil
+}
+
+// generateOccupancyGrid 按高度带投影点云生成占据栅格，写出 PGM 与 YAML 并登记为点云地图的衍生栅格地图
+func (s *PCDJobService) generateOccupancyGrid(ctx context.Context, job *entity.PCDJob) (*pcdOccupancyResult, error) {
+	if job.Params == nil {
+		return nil, errors.New("occupancy job has no params")
+	}
+	var params pcdOccupancyParams
+	if err := json.Unmarshal([]byte(*job.Params), &params); err != nil {
+		return nil, fmt.Errorf("invalid occupancy job params: %w", err)
+	}
+
+	file, err := s.pcdDAO.FindByID(ctx, job.PCDFileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.New("pcd file not found")
+	}
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		return nil, errors.New("pcd file has no minio object")
+	}
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	builder, err := pcd.NewOccupancyBuilder(pcd.OccupancyOptions{
+		Resolution: params.Resolution,
+		MinZ:       params.MinZ,
+		MaxZ:       params.MaxZ,
+		MinPoints:  params.MinPoints,
+		MaxPixels:  occupancyMaxPixels(),
+		MaxRange:   occupancyMaxRange(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	object, _, err := store.Get(ctx, *file.MinioPath)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	grid, err := builder.Build(object)
+	if err != nil {
+		return nil, fmt.Errorf("栅格生成失败: %w", err)
+	}
+
+	imageKey, yamlKey := occupancyObjectKeys(file.ID, job.ID, params.Name)
+	var pgmBuf, yamlBuf bytes.Buffer
+	if err := grid.WritePGM(&pgmBuf); err != nil {
+		return nil, err
+	}
+	if err := grid.WriteYAML(&yamlBuf, path.Base(imageKey)); err != nil {
+		return nil, err
+	}
+	if err := store.Put(ctx, imageKey, &pgmBuf, int64(pgmBuf.Len()), occupancyImageContentType); err != nil {
+		return nil, err
+	}
+	if err := store.Put(ctx, yamlKey, &yamlBuf, int64(yamlBuf.Len()), occupancyYAMLContentType); err != nil {
+		return nil, err
+	}
+
+	pcdFileID, jobID := file.ID, job.ID
+	minZ, maxZ := params.MinZ, params.MaxZ
+	occupied, free := grid.OccupiedCells, grid.FreeCells
+	record := &entity.OccupancyGrid{
+		Name:           params.Name,
+		Source:         entity.OccupancyGridSourcePCD,
+		PCDFileID:      &pcdFileID,
+		JobID:          &jobID,
+		UserName:       params.UserName,
+		ImagePath:      imageKey,
+		YAMLPath:       yamlKey,
+		Resolution:     grid.Resolution,
+		OriginX:        grid.OriginX,
+		OriginY:        grid.OriginY,
+		Width:          grid.Width,
+		Height:         grid.Height,
+		OccupiedThresh: pcd.OccupiedThresh,
+		FreeThresh:     pcd.FreeThresh,
+		MinZ:           &minZ,
+		MaxZ:           &maxZ,
+		OccupiedCells:  &occupied,
+		FreeCells:      &free,
+	}
+	if err := s.gridDAO.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	return &pcdOccupancyResult{
+		GridID:        record.ID,
+		Width:         grid.Width,
+		Height:        grid.Height,
+		OriginX:       grid.OriginX,
+		OriginY:       grid.OriginY,
+		OccupiedCells: occupied,
+		FreeCells:     free,
+		Outliers:      grid.Outliers,
+	}, nil
+}
+
+// occupancyObjectKeys 栅格图像与描述文件的对象 Key，按任务分目录避免同名覆盖
+func occupancyObjectKeys(pcdFileID, jobID uint, name string) (imageKey, yamlKey string) {
+	base := fmt.Sprintf("%spcd-%d/%d/%s", occupancyObjectPrefix, pcdFileID, jobID, safeObjectName(name))
+	return base + ".pgm", base + ".yaml"
+}
+
+// safeObjectName 将名称中字母、数字、'-'、'_'、'.' 以外的字符替换为 '_'，用作对象与下载文件名
+func safeObjectName(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, name)
+	safe = strings.Trim(safe, ".")
+	if safe == "" {
+		return "map"
+	}
+	return safe
+}
+
+// occupancyDefaults 读取栅格生成的默认参数
+func occupancyDefaults() pcdOccupancyParams {
+	params := pcdOccupancyParams{
+		Resolution: 0.05,
+		MinZ:       0.1,
+		MaxZ:       1.5,
+		MinPoints:  2,
+	}
+	cfg := config.Get()
+	if cfg == nil || cfg.Occupancy == nil {
+		return params
+	}
+	o := cfg.Occupancy
+	if o.Resolution > 0 {
+		params.Resolution = o.Resolution
+	}
+	// 高度带允许为 0 或负数（点云原点在传感器处时地面为负），两者都为 0 才视为未配置
+	if o.MinZ != 0 || o.MaxZ != 0 {
+		params.MinZ, params.MaxZ = o.MinZ, o.MaxZ
+	}
+	if o.MinPoints > 0 {
+		params.MinPoints = o.MinPoints
+	}
+	return params
+}
+
+func occupancyMaxPixels() int {
+	if cfg := config.Get(); cfg != nil && cfg.Occupancy != nil && cfg.Occupancy.MaxPixels > 0 {
+		return cfg.Occupancy.MaxPixels
+	}
+	return 25000000
+}
+
+// occupancyMaxRange 点坐标绝对值上限，未配置时使用 pcd.DefaultMaxRange
+func occupancyMaxRange() float64 {
+	if cfg := config.Get(); cfg != nil && cfg.Occupancy != nil && cfg.Occupancy.MaxRange > 0 {
+		return cfg.Occupancy.MaxRange
+	}
+	return pcd.DefaultMaxRange
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPCDJobService_EnqueueOccupancyGrid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockJobDAO := mocks.NewMockPCDJobDAO(ctrl)
+	service := NewPCDJobService(mockPCDDAO, mockJobDAO, mocks.NewMockOccupancyGridDAO(ctrl), nil)
+	ctx := context.Background()
+
+	minioPath := "pcd/u/1_floor.pcd"
+	file := &entity.PCDFile{Name: "一楼.pcd", MinioPath: &minioPath}
+	file.ID = 1
+	mockPCDDAO.EXPECT().FindByID(ctx, uint(1)).Return(file, nil).Times(2)
+	mockJobDAO.EXPECT().FindActive(ctx, uint(1), entity.PCDJobTypeOccupancy).Return(nil, nil)
+
+	var created *entity.PCDJob
+	mockJobDAO.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, job *entity.PCDJob) error {
+		created = job
+		return nil
+	})
+
+	resolution := 0.1
+	job, err := service.EnqueueOccupancyGrid(ctx, 1, "operator", &dto.PCDOccupancyGridRequest{Resolution: &resolution})
+	if err != nil {
+		t.Fatalf("EnqueueOccupancyGrid failed: %v", err)
+	}
+	if job.Type != entity.PCDJobTypeOccupancy || created.Params == nil {
+		t.Fatalf("Expected occupancy job with params, got %+v", job)
+	}
+
+	var params pcdOccupancyParams
+	if err := json.Unmarshal([]byte(*created.Params), &params); err != nil {
+		t.Fatalf("Invalid params: %v", err)
+	}
+	if params.Name != "一楼" || params.UserName != "operator" || params.Resolution != 0.1 || params.MinZ != 0.1 || params.MaxZ != 1.5 {
+		t.Errorf("Unexpected params %+v", params)
+	}
+
+	// 高度带无效时不创建任务
+	minZ, maxZ := 1.0, 0.5
+	if _, err := service.EnqueueOccupancyGrid(ctx, 1, "operator", &dto.PCDOccupancyGridRequest{MinZ: &minZ, MaxZ: &maxZ}); err == nil {
+		t.Error("Expected error for invalid height band")
+	}
+}
+
+func TestPCDJobService_SourceReplaced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockJobDAO := mocks.NewMockPCDJobDAO(ctrl)
+	service := NewPCDJobService(mockPCDDAO, mockJobDAO, mocks.NewMockOccupancyGridDAO(ctrl), nil)
+	ctx := context.Background()
+
+	oldKey, newKey := "pcd/u/1_old.pcd", "pcd/u/2_new.pcd"
+	file := &entity.PCDFile{MinioPath: &newKey}
+	file.ID = 1
+	stale := &entity.PCDJob{PCDFileID: 1, Type: entity.PCDJobTypeIntegrity, Status: entity.PCDJobStatusPending, SourceKey: &oldKey}
+
+	// 对象已替换时不复用旧任务
+	mockJobDAO.EXPECT().FindActive(ctx, uint(1), entity.PCDJobTypeIntegrity).Return(stale, nil)
+	mockJobDAO.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	job, err := enqueuePCDJob(ctx, mockPCDDAO, mockJobDAO, file, entity.PCDJobTypeIntegrity, nil)
+	if err != nil {
+		t.Fatalf("enqueuePCDJob failed: %v", err)
+	}
+	if job == stale || job.SourceKey == nil || *job.SourceKey != newKey {
+		t.Errorf("Expected a new job for %s, got %+v", newKey, job)
+	}
+
+	// 旧任务执行时作废
+	mockPCDDAO.EXPECT().FindByID(ctx, uint(1)).Return(file, nil)
+	if _, err := service.execute(ctx, stale); err == nil {
+		t.Error("Expected stale job to fail")
+	}
+}
+
+func TestOccupancyObjectKeys(t *testing.T) {
+	imageKey, yamlKey := occupancyObjectKeys(3, 7, "一楼 / east")
+	if imageKey != "grid/pcd-3/7/一楼___east.pgm" || yamlKey != "grid/pcd-3/7/一楼___east.yaml" {
+		t.Errorf("Unexpected keys %s %s", imageKey, yamlKey)
+	}
+	if got := safeObjectName(".."); got != "map" {
+		t.Errorf("Expected fallback name, got %q", got)
+	}
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"robot_scheduler/internal/config"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/storage"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// pcdDeletedRetention 已删除地图对象的默认保留时长
+	pcdDeletedRetention = 7 * 24 * time.Hour
+	// pcdPurgeBatch 单次清理的最大地图数
+	pcdPurgeBatch = 100
+	// pcdObjectPrefix 点云对象的 Key 前缀
+	pcdObjectPrefix = "pcd/"
+	// pcdReconcileInterval 后台对账的间隔
+	pcdReconcileInterval = 24 * time.Hour
+)
+
+// RunUploadSweeper 周期清理超时未完成的上传与超过保留期的已删除地图对象，分批排队完整性校验，
+// 并每天对账一次，将不一致项写入日志，直到 ctx 取消
+func (s *PCDFileService) RunUploadSweeper(ctx context.Context, interval time.Duration) {
+	logger.Info("starting pcd storage cleanup", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var lastReconcile time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := s.SweepExpiredUploads(ctx); err != nil {
+			logger.Warn("pcd upload sweep failed", zap.Error(err))
+		}
+		if _, err := s.PurgeDeletedObjects(ctx); err != nil {
+			logger.Warn("pcd deleted object purge failed", zap.Error(err))
+		}
+		if _, err := s.EnqueueIntegrityChecks(ctx); err != nil {
+			logger.Warn("pcd integrity check scheduling failed", zap.Error(err))
+		}
+		if time.Since(lastReconcile) >= pcdReconcileInterval {
+			lastReconcile = time.Now()
+			s.logReconcile(ctx)
+		}
+	}
+}
+
+// logReconcile 执行一次对账并记录不一致项
+func (s *PCDFileService) logReconcile(ctx context.Context) {
+	report, err := s.Reconcile(ctx)
+	if err != nil {
+		logger.Warn("pcd storage reconcile failed", zap.Error(err))
+		return
+	}
+	for _, o := range report.OrphanObjects {
+		logger.Warn("orphaned pcd object", zap.String("objectKey", o.ObjectKey), zap.Int64("size", o.Size), zap.Time("lastModified", o.LastModified))
+	}
+	for _, m := range report.MissingObjects {
+		logger.Warn("pcd file object missing", zap.Uint("id", m.ID), zap.String("name", m.Name), zap.String("minioPath", m.MinioPath), zap.Bool("deleted", m.Deleted))
+	}
+}
+
+// PurgeDeletedObjects 删除软删除超过保留期的点云地图对应的存储对象，返回清理数量
+func (s *PCDFileService) PurgeDeletedObjects(ctx context.Context) (int, error) {
+	store, err := pcdStorage()
+	if err != nil {
+		return 0, err
+	}
+
+	retention := pcdDeletedRetention
+	if cfg := config.Get(); cfg.Minio != nil && cfg.Minio.DeletedRetention > 0 {
+		retention = time.Duration(cfg.Minio.DeletedRetention) * time.Hour
+	}
+
+	files, err := s.pcdDAO.FindDeletedBefore(ctx, time.Now().Add(-retention), pcdPurgeBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, file := range files {
+		if file.MinioPath != nil && *file.MinioPath != "" {
+			// 对象仍被其他未删除的地图或其版本引用时只标记，不删除
+			inUse, err := s.objectInUse(ctx, *file.MinioPath, file.ID)
+			if err != nil {
+				return purged, err
+			}
+			if !inUse {
+				if err := store.Remove(ctx, *file.MinioPath); err != nil {
+					logger.Warn("failed to remove deleted pcd object", zap.Error(err), zap.Uint("id", file.ID), zap.String("objectKey", *file.MinioPath))
+					continue
+				}
+				// 预览与缩略图随原始对象一起删除，删除失败只记录日志，由对账报告为孤立对象
+				for _, key := range []*string{file.PreviewPath, file.ThumbnailPath} {
+					if key == nil || *key == "" {
+						continue
+					}
+					if err := store.Remove(ctx, *key); err != nil {
+						logger.Warn("failed to remove deleted pcd preview object", zap.Error(err), zap.Uint("id", file.ID), zap.String("objectKey", *key))
+					}
+				}
+			}
+		}
+		if err := s.purgeVersionObjects(ctx, store, file); err != nil {
+			return purged, err
+		}
+		if err := s.pcdDAO.MarkObjectPurged(ctx, file.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	if purged > 0 {
+		logger.Info("deleted pcd objects purged", zap.Int("count", purged))
+	}
+	return purged, nil
+}
+
+// objectInUse 判断对象是否仍被其他未删除的点云地图或其历史版本引用
+func (s *PCDFileService) objectInUse(ctx context.Context, objectKey string, fileID uint) (bool, error) {
+	files, err := s.pcdDAO.CountByMinioPath(ctx, objectKey)
+	if err != nil {
+		return false, err
+	}
+	versions, err := s.versionDAO.CountByMinioPath(ctx, objectKey, fileID)
+	if err != nil {
+		return false, err
+	}
+	return files > 0 || versions > 0, nil
+}
+
+// purgeVersionObjects 删除已删除点云地图历史版本独有的对象，删除失败只记录日志，由对账报告为孤立对象
+func (s *PCDFileService) purgeVersionObjects(ctx context.Context, store storage.Storage, file *entity.PCDFile) error {
+	versions, err := s.versionDAO.FindByFile(ctx, file.ID)
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if v.MinioPath == nil || *v.MinioPath == "" || sameObject(v.MinioPath, file.MinioPath) {
+			continue
+		}
+		inUse, err := s.objectInUse(ctx, *v.MinioPath, file.ID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			continue
+		}
+		if err := store.Remove(ctx, *v.MinioPath); err != nil {
+			logger.Warn("failed to remove deleted pcd version object", zap.Error(err), zap.Uint("id", file.ID), zap.Int("version", v.Version), zap.String("objectKey", *v.MinioPath))
+		}
+	}
+	return nil
+}
+
+// Reconcile 比对存储中的对象与点云地图记录，报告孤立对象与丢失对象的记录，不做任何修改
+func (s *PCDFileService) Reconcile(ctx context.Context) (*dto.PCDReconcileReport, error) {
+	logger.Info("reconciling pcd storage in service")
+
+	store, err := pcdStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.pcdDAO.FindAllWithObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	referenced := make(map[string]bool, len(files))
+	for _, file := range files {
+		referenced[*file.MinioPath] = true
+		if file.PreviewPath != nil {
+			referenced[*file.PreviewPath] = true
+		}
+		if file.ThumbnailPath != nil {
+			referenced[*file.ThumbnailPath] = true
+		}
+	}
+	// 历史版本的对象可用于回滚，不算孤立
+	versionPaths, err := s.versionDAO.FindMinioPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range versionPaths {
+		referenced[p] = true
+	}
+
+	report := &dto.PCDReconcileReport{
+		CheckedFiles:   len(files),
+		OrphanObjects:  make([]*dto.PCDOrphanObject, 0),
+		MissingObjects: make([]*dto.PCDMissingObject, 0),
+	}
+
+	objects, err := store.List(ctx, pcdObjectPrefix)
+	if err != nil {
+		logger.Error("failed to list pcd objects", zap.Error(err))
+		return nil, err
+	}
+	existing := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		report.ScannedObjects++
+		existing[object.Key] = true
+		if referenced[object.Key] {
+			continue
+		}
+
+		// 仍在上传中的对象不算孤立
+		upload, err := s.uploadDAO.FindByObjectKey(ctx, object.Key)
+		if err != nil {
+			return nil, err
+		}
+		if upload != nil && upload.Status == entity.PCDUploadStatusPending {
+			continue
+		}
+		report.OrphanObjects = append(report.OrphanObjects, &dto.PCDOrphanObject{
+			ObjectKey:    object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		})
+	}
+
+	for _, file := range files {
+		if existing[*file.MinioPath] {
+			continue
+		}
+		// 前缀以外的对象不在扫描范围内，单独确认
+		if _, err := store.Stat(ctx, *file.MinioPath); err == nil {
+			continue
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+		report.MissingObjects = append(report.MissingObjects, &dto.PCDMissingObject{
+			ID:        file.ID,
+			Name:      file.Name,
+			MinioPath: *file.MinioPath,
+			Deleted:   file.DeletedAt.Valid,
+		})
+	}
+
+	logger.Info("pcd storage reconciled",
+		zap.Int("scannedObjects", report.ScannedObjects),
+		zap.Int("checkedFiles", report.CheckedFiles),
+		zap.Int("orphanObjects", len(report.OrphanObjects)),
+		zap.Int("missingObjects", len(report.MissingObjects)),
+	)
+	return report, nil
+}
+
+// pcdStorage 返回已初始化的对象存储后端
+func pcdStorage() (storage.Storage, error) {
+	store := storage.Backend()
+	if store == nil {
+		return nil, errors.New("object storage is not configured")
+	}
+	return store, nil
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/storage"
+	"robot_scheduler/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// pcdUploadSweepGrace 上传凭证过期后保留的时长，避免清理仍在传输中的大文件
+	pcdUploadSweepGrace = 30 * time.Minute
+	// pcdUploadSweepBatch 单次清理的最大记录数
+	pcdUploadSweepBatch = 100
+)
+
+// ErrUploadUserRequired 上传点云地图须识别到当前用户，对象 Key 与上传记录按用户隔离
+var ErrUploadUserRequired = errors.New("upload user required")
+
+// pcdUserPrefix 用户上传点云对象的 Key 前缀
+func pcdUserPrefix(userName string) string {
+	return "pcd/" + userName + "/"
+}
+
+// newUploadObjectKey 生成用户上传对象的 Key，时间戳后附随机后缀，避免同一秒内的上传互相覆盖
+func newUploadObjectKey(userName, fileName string) (string, error) {
+	suffix, err := utils.GenerateRandomToken("", 4)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%d_%s_%s", pcdUserPrefix(userName), time.Now().Unix(), suffix, fileName), nil
+}
+
+// ownUploadKey 判断对象 Key 是否位于用户的上传前缀下
+func ownUploadKey(userName, objectKey string) bool {
+	return userName != "" && strings.HasPrefix(objectKey, pcdUserPrefix(userName)) && !strings.Contains(objectKey, "..")
+}
+
+// checkOwnUpload 校验对象 Key 位于当前用户的上传前缀下，不能引用其他用户上传的对象
+func checkOwnUpload(userName, objectKey string) error {
+	if userName == "" {
+		return fmt.Errorf("%w: 未识别到当前用户", ErrUploadUserRequired)
+	}
+	if !ownUploadKey(userName, objectKey) {
+		logger.Warn("pcd upload object key outside user prefix", zap.String("objectKey", objectKey), zap.String("userName", userName))
+		return errors.New("object key does not belong to current user")
+	}
+	return nil
+}
+
+// CompleteUpload 完成点云地图上传：校验对象归属、存在性与大小后创建点云地图，并排队解析任务。
+// 解析点云、比对校验和与按内容去重需要读取全文，由后台任务完成，结果见点云地图的解析状态；
+// 未启用后台任务时在请求内先校验对象，校验失败不创建点云地图，上传保持待完成
+func (s *PCDFileService) CompleteUpload(ctx context.Context, userName string, req *dto.PCDFileCompleteUploadRequest) (*dto.PCDFileResponse, error) {
+	logger.Info("completing pcd upload in service", zap.String("objectKey", req.ObjectKey), zap.String("userName", userName))
+
+	upload, err := s.findPendingUpload(ctx, userName, req.ObjectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	existingFile, err := s.pcdDAO.FindByName(ctx, req.Name)
+	if err != nil {
+		logger.Error("failed to check pcd file name existence", zap.Error(err), zap.String("name", req.Name))
+		return nil, err
+	}
+	if existingFile != nil {
+		logger.Warn("pcd file name already exists", zap.String("name", req.Name))
+		return nil, errors.New("pcd file name already exists")
+	}
+
+	// 只比对对象元信息，全文读取留给解析任务
+	info, err := s.statObject(ctx, req.ObjectKey)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size != upload.DeclaredSize {
+		logger.Warn("pcd upload size mismatch",
+			zap.String("objectKey", req.ObjectKey),
+			zap.Int64("declared", upload.DeclaredSize),
+			zap.Int64("actual", info.Size),
+		)
+		return nil, fmt.Errorf("文件大小不一致: 声明 %d 字节, 实际 %d 字节", upload.DeclaredSize, info.Size)
+	}
+
+	path := req.ObjectKey
+	if req.Path != nil && *req.Path != "" {
+		path = *req.Path
+	}
+	minioPath := req.ObjectKey
+	pending := entity.PCDJobStatusPending
+	file := &entity.PCDFile{
+		Name:      req.Name,
+		Area:      req.Area,
+		Path:      path,
+		UserName:  upload.UserName,
+		Size:      int(info.Size),
+		MinioPath: &minioPath,
+		ExtraInfo: req.ExtraInfo,
+	}
+	file.AnalysisStatus = &pending
+	if file.LocationID, err = resolveLocationID(ctx, s.locationDAO, req.LocationID); err != nil {
+		return nil, err
+	}
+
+	params := &pcdAnalyzeParams{
+		Checksum: upload.Checksum,
+		SHA256:   upload.SHA256,
+		Author:   upload.UserName,
+		Message:  req.Message,
+	}
+	// 后台任务协程随 preview.enabled 启动，未启用时在请求内解析；
+	// 先校验对象再完成上传，格式错误或校验和不一致时不创建点云地图，上传保持待完成可重试
+	inline := !previewSettings().enabled
+	var object *pcdObject
+	if inline {
+		if object, err = s.verifyUpload(ctx, req.ObjectKey, params); err != nil {
+			return nil, err
+		}
+		running := entity.PCDJobStatusRunning
+		file.AnalysisStatus = &running
+	}
+
+	if err := s.uploadDAO.Complete(ctx, upload, file); err != nil {
+		if errors.Is(err, dao.ErrPCDUploadNotPending) {
+			return nil, errors.New("pcd upload already completed or expired")
+		}
+		logger.Error("failed to complete pcd upload in service", zap.Error(err), zap.String("objectKey", req.ObjectKey))
+		return nil, err
+	}
+
+	if inline {
+		result, err := s.applyAnalysis(ctx, file, object, params)
+		if err != nil {
+			s.markAnalysisFailed(ctx, file.ID)
+			return nil, err
+		}
+		logger.Info("pcd upload completed successfully in service", zap.String("objectKey", req.ObjectKey), zap.Uint("id", file.ID))
+		resp := dto.NewPCDFileResponseFromEntity(file)
+		resp.DuplicateOf = result.DuplicateOf
+		return resp, nil
+	}
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	text := string(raw)
+	if _, err := enqueuePCDJob(ctx, s.pcdDAO, s.jobDAO, file, entity.PCDJobTypeAnalyze, &text); err != nil {
+		logger.Error("failed to enqueue pcd analyze job", zap.Error(err), zap.Uint("id", file.ID))
+		s.markAnalysisFailed(ctx, file.ID)
+		return nil, err
+	}
+
+	logger.Info("pcd upload completed successfully in service", zap.String("objectKey", req.ObjectKey), zap.Uint("id", file.ID))
+	return dto.NewPCDFileResponseFromEntity(file), nil
+}
+
+// pcdAnalyzeParams 解析任务参数：上传时声明的校验值与版本说明
+type pcdAnalyzeParams struct {
+	Checksum *string `json:"checksum,omitempty"`
+	SHA256   *string `json:"sha256,omitempty"`
+	Author   string  `json:"author"`
+	Message  *string `json:"message,omitempty"`
+}
+
+// pcdAnalyzeResult 解析任务结果
+type pcdAnalyzeResult struct {
+	Points      int    `json:"points"`
+	ValidPoints int    `json:"validPoints"`
+	SHA256      string `json:"sha256"`
+	DuplicateOf *uint  `json:"duplicateOf,omitempty"` // 内容与该点云地图相同，已复用其对象
+}
+
+// analyzeUpload 执行解析任务
+func (s *PCDFileService) analyzeUpload(ctx context.Context, job *entity.PCDJob) (*pcdAnalyzeResult, error) {
+	file, err := s.pcdDAO.FindByID(ctx, job.PCDFileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.New("pcd file not found")
+	}
+
+	var params pcdAnalyzeParams
+	if job.Params != nil {
+		if err := json.Unmarshal([]byte(*job.Params), &params); err != nil {
+			return nil, fmt.Errorf("invalid analyze job params: %w", err)
+		}
+	}
+	return s.analyzeFile(ctx, file, &params)
+}
+
+// analyzeFile 解析上传完成的点云对象：校验格式与声明的校验和，写入元数据并记录第一个版本，
+// 内容与已有点云地图相同时复用已有对象并删除本次上传的对象，最后排队预览任务。失败时将解析状态置为失败
+func (s *PCDFileService) analyzeFile(ctx context.Context, file *entity.PCDFile, params *pcdAnalyzeParams) (result *pcdAnalyzeResult, err error) {
+	if file.MinioPath == nil || *file.MinioPath == "" {
+		return nil, errors.New("pcd file has no minio object")
+	}
+	if err := s.pcdDAO.UpdateAnalysisStatus(ctx, file.ID, entity.PCDJobStatusRunning); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.markAnalysisFailed(ctx, file.ID)
+		}
+	}()
+
+	object, err := s.verifyUpload(ctx, *file.MinioPath, params)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyAnalysis(ctx, file, object, params)
+}
+
+// verifyUpload 读取并解析上传的点云对象，比对上传时声明的校验和
+func (s *PCDFileService) verifyUpload(ctx context.Context, objectKey string, params *pcdAnalyzeParams) (*pcdObject, error) {
+	object, err := s.inspectObject(ctx, objectKey)
+	if err != nil {
+		logger.Warn("pcd file rejected", zap.Error(err), zap.String("objectKey", objectKey))
+		return nil, fmt.Errorf("点云文件校验失败: %w", err)
+	}
+	if params.Checksum != nil && !strings.EqualFold(*params.Checksum, object.MD5) {
+		logger.Warn("pcd upload checksum mismatch",
+			zap.String("objectKey", objectKey),
+			zap.String("declared", *params.Checksum),
+			zap.String("actual", object.MD5),
+		)
+		return nil, errors.New("文件校验和不一致")
+	}
+	if params.SHA256 != nil && !strings.EqualFold(*params.SHA256, object.SHA256) {
+		logger.Warn("pcd upload sha256 mismatch",
+			zap.String("objectKey", objectKey),
+			zap.String("declared", *params.SHA256),
+			zap.String("actual", object.SHA256),
+		)
+		return nil, errors.New("文件 SHA-256 不一致")
+	}
+	return object, nil
+}
+
+// applyAnalysis 写入已校验对象的元数据并记录第一个版本，内容重复时复用已有对象，最后排队预览任务
+func (s *PCDFileService) applyAnalysis(ctx context.Context, file *entity.PCDFile, object *pcdObject, params *pcdAnalyzeParams) (*pcdAnalyzeResult, error) {
+	objectKey := *file.MinioPath
+	applyPCDMetadata(file, object)
+	duplicate, err := s.linkDuplicateObject(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	succeeded := entity.PCDJobStatusSucceeded
+	file.AnalysisStatus = &succeeded
+	if err := s.pcdDAO.Update(ctx, file); err != nil {
+		return nil, err
+	}
+	if err := s.recordVersion(ctx, file, params.Author, params.Message); err != nil {
+		logger.Error("failed to create pcd file version in service", zap.Error(err), zap.Uint("id", file.ID))
+		return nil, err
+	}
+
+	result := &pcdAnalyzeResult{
+		Points:      object.Meta.Header.Points,
+		ValidPoints: object.Meta.ValidPoints,
+		SHA256:      object.SHA256,
+	}
+	if duplicate != nil {
+		s.removeDuplicateObject(ctx, params.Author, objectKey, file.ID)
+		result.DuplicateOf = &duplicate.ID
+	}
+	if !previewReady(duplicate) {
+		s.schedulePreview(ctx, file)
+	}
+	return result, nil
+}
+
+// markAnalysisFailed 将点云地图的解析状态置为失败，失败只记录日志
+func (s *PCDFileService) markAnalysisFailed(ctx context.Context, id uint) {
+	if err := s.pcdDAO.UpdateAnalysisStatus(ctx, id, entity.PCDJobStatusFailed); err != nil {
+		logger.Warn("failed to mark pcd analysis failed", zap.Error(err), zap.Uint("id", id))
+	}
+}
+
+// findPendingUpload 查询当前用户待完成的上传记录，对象 Key 必须位于用户自己的前缀下
+func (s *PCDFileService) findPendingUpload(ctx context.Context, userName, objectKey string) (*entity.PCDUpload, error) {
+	if err := checkOwnUpload(userName, objectKey); err != nil {
+		return nil, err
+	}
+
+	upload, err := s.uploadDAO.FindByObjectKey(ctx, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	if upload == nil || upload.UserName != userName {
+		logger.Warn("pcd upload not found", zap.String("objectKey", objectKey))
+		return nil, errors.New("pcd upload not found")
+	}
+	if upload.Status != entity.PCDUploadStatusPending {
+		logger.Warn("pcd upload is not pending", zap.String("objectKey", objectKey), zap.String("status", string(upload.Status)))
+		return nil, fmt.Errorf("pcd upload is %s", upload.Status)
+	}
+	return upload, nil
+}
+
+// statObject 查询对象元信息
+func (s *PCDFileService) statObject(ctx context.Context, objectKey string) (storage.ObjectInfo, error) {
+	store, err := pcdStorage()
+	if err != nil {
+		return storage.ObjectInfo{}, err
+	}
+
+	info, err := store.Stat(ctx, objectKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.Warn("pcd object not uploaded", zap.String("objectKey", objectKey))
+			return storage.ObjectInfo{}, fmt.Errorf("对象 %s 尚未上传", objectKey)
+		}
+		logger.Error("failed to stat pcd object", zap.Error(err), zap.String("objectKey", objectKey))
+		return storage.ObjectInfo{}, err
+	}
+	return info, nil
+}
+
+// SweepExpiredUploads 删除超时未完成上传留下的对象并将记录置为已过期，返回清理数量
+func (s *PCDFileService) SweepExpiredUploads(ctx context.Context) (int, error) {
+	store, err := pcdStorage()
+	if err != nil {
+		return 0, err
+	}
+
+	uploads, err := s.uploadDAO.FindExpiredPending(ctx, time.Now().Add(-pcdUploadSweepGrace), pcdUploadSweepBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	swept := 0
+	for _, upload := range uploads {
+		// 先将记录置为过期再删除对象，与并发的完成操作互斥
+		if err := s.uploadDAO.MarkExpired(ctx, upload.ID); err != nil {
+			if errors.Is(err, dao.ErrPCDUploadNotPending) {
+				continue
+			}
+			return swept, err
+		}
+		// 分片上传需先放弃，释放已上传的分片
+		if upload.MultipartUploadID != nil {
+			if err := store.AbortMultipart(ctx, upload.ObjectKey, *upload.MultipartUploadID); err != nil && !errors.Is(err, storage.ErrNoSuchUpload) {
+				logger.Warn("failed to abort expired multipart upload", zap.Error(err), zap.String("objectKey", upload.ObjectKey))
+			}
+		}
+		// 对象不存在时 Remove 同样返回成功
+		if err := store.Remove(ctx, upload.ObjectKey); err != nil {
+			logger.Warn("failed to remove expired pcd upload object", zap.Error(err), zap.String("objectKey", upload.ObjectKey))
+			continue
+		}
+		swept++
+	}
+
+	if swept > 0 {
+		logger.Info("expired pcd uploads swept", zap.Int("count", swept))
+	}
+	return swept, nil
+}
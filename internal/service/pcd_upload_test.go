@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/storage"
+	"robot_scheduler/internal/testutil/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestPCDFileService_CompleteUpload_RejectsForeignPrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockUploadDAO := mocks.NewMockPCDUploadDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mockUploadDAO, mocks.NewMockPCDJobDAO(ctrl), mocks.NewMockPCDFileVersionDAO(ctrl), mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	for _, key := range []string{"pcd/bob/1_a.pcd", "pcd/alice/../bob/1_a.pcd", "other/alice/1_a.pcd"} {
+		_, err := service.CompleteUpload(ctx, "alice", &dto.PCDFileCompleteUploadRequest{
+			ObjectKey: key,
+			Name:      "map",
+			Area:      "A",
+		})
+		if err == nil {
+			t.Errorf("Expected object key %q to be rejected", key)
+		}
+	}
+}
+
+func TestPCDFileService_UploadRequiresUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local, err := storage.NewLocal(storage.LocalConfig{Root: t.TempDir(), Secret: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	storage.SetBackend(local)
+	defer storage.SetBackend(nil)
+
+	service := NewPCDFileService(mocks.NewMockPCDFileDAO(ctrl), mocks.NewMockPCDUploadDAO(ctrl), mocks.NewMockPCDJobDAO(ctrl), mocks.NewMockPCDFileVersionDAO(ctrl), mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	// 未识别到用户时不能落到共享前缀下
+	if _, err := service.GenerateUploadToken(ctx, "", &dto.PCDFileUploadTokenRequest{FileName: "a.pcd"}); !errors.Is(err, ErrUploadUserRequired) {
+		t.Errorf("Expected ErrUploadUserRequired for upload token, got %v", err)
+	}
+	if _, err := service.InitMultipartUpload(ctx, "", &dto.PCDMultipartInitRequest{FileName: "a.pcd", Size: 1 << 20}); !errors.Is(err, ErrUploadUserRequired) {
+		t.Errorf("Expected ErrUploadUserRequired for multipart upload, got %v", err)
+	}
+	if _, err := service.CompleteUpload(ctx, "", &dto.PCDFileCompleteUploadRequest{ObjectKey: "pcd/unknown/1_a.pcd", Name: "map", Area: "A"}); !errors.Is(err, ErrUploadUserRequired) {
+		t.Errorf("Expected ErrUploadUserRequired for complete upload, got %v", err)
+	}
+}
+
+func TestPCDFileService_CompleteUpload_RejectsNonPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockUploadDAO := mocks.NewMockPCDUploadDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mockUploadDAO, mocks.NewMockPCDJobDAO(ctrl), mocks.NewMockPCDFileVersionDAO(ctrl), mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	key := "pcd/alice/1_a.pcd"
+	mockUploadDAO.EXPECT().FindByObjectKey(ctx, key).Return(&entity.PCDUpload{
+		ObjectKey: key,
+		UserName:  "alice",
+		Status:    entity.PCDUploadStatusExpired,
+		ExpireAt:  time.Now().Add(-time.Hour),
+	}, nil)
+
+	_, err := service.CompleteUpload(ctx, "alice", &dto.PCDFileCompleteUploadRequest{
+		ObjectKey: key,
+		Name:      "map",
+		Area:      "A",
+	})
+	if err == nil {
+		t.Fatal("Expected expired upload to be rejected")
+	}
+}
+
+func TestPCDFileService_AnalyzeUpload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local, err := storage.NewLocal(storage.LocalConfig{Root: t.TempDir(), Secret: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	storage.SetBackend(local)
+	defer storage.SetBackend(nil)
+
+	ctx := context.Background()
+	key := "pcd/alice/1_a.pcd"
+	content := "FIELDS x y z\nSIZE 4 4 4\nTYPE F F F\nWIDTH 2\nHEIGHT 1\nPOINTS 2\nDATA ascii\n1 2 3\n4 5 6\n"
+	if err := local.Put(ctx, key, strings.NewReader(content), int64(len(content)), ""); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockVersionDAO := mocks.NewMockPCDFileVersionDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mocks.NewMockPCDUploadDAO(ctrl), mocks.NewMockPCDJobDAO(ctrl), mockVersionDAO, mocks.NewMockLocationDAO(ctrl))
+
+	pending := entity.PCDJobStatusPending
+	newFile := func() *entity.PCDFile {
+		minioPath := key
+		file := &entity.PCDFile{Model: gorm.Model{ID: 3}, Name: "map", UserName: "alice", MinioPath: &minioPath}
+		file.AnalysisStatus = &pending
+		return file
+	}
+	wrong := `{"sha256":"0000","author":"alice"}`
+
+	mockPCDDAO.EXPECT().FindByID(ctx, uint(3)).Return(newFile(), nil)
+	mockPCDDAO.EXPECT().UpdateAnalysisStatus(ctx, uint(3), entity.PCDJobStatusRunning).Return(nil)
+	mockPCDDAO.EXPECT().UpdateAnalysisStatus(ctx, uint(3), entity.PCDJobStatusFailed).Return(nil)
+	if _, err := service.analyzeUpload(ctx, &entity.PCDJob{PCDFileID: 3, Params: &wrong}); err == nil {
+		t.Fatal("Expected sha256 mismatch to fail the analysis")
+	}
+
+	params := `{"author":"alice"}`
+	mockPCDDAO.EXPECT().FindByID(ctx, uint(3)).Return(newFile(), nil)
+	mockPCDDAO.EXPECT().UpdateAnalysisStatus(ctx, uint(3), entity.PCDJobStatusRunning).Return(nil)
+	mockPCDDAO.EXPECT().FindBySHA256(ctx, gomock.Any()).Return(nil, nil)
+	mockPCDDAO.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, f *entity.PCDFile) error {
+		if f.AnalysisStatus == nil || *f.AnalysisStatus != entity.PCDJobStatusSucceeded || f.PointCount == nil || *f.PointCount != 2 {
+			t.Errorf("Unexpected analyzed file %+v", f.PCDMetadata)
+		}
+		return nil
+	})
+	mockVersionDAO.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, v *entity.PCDFileVersion) error {
+		v.Version = 1
+		return nil
+	})
+	result, err := service.analyzeUpload(ctx, &entity.PCDJob{PCDFileID: 3, Params: &params})
+	if err != nil {
+		t.Fatalf("analyzeUpload failed: %v", err)
+	}
+	if result.Points != 2 || result.ValidPoints != 2 || result.DuplicateOf != nil {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestPCDFileService_CompleteUpload_InlineRejectsMalformed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local, err := storage.NewLocal(storage.LocalConfig{Root: t.TempDir(), Secret: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	storage.SetBackend(local)
+	defer storage.SetBackend(nil)
+
+	ctx := context.Background()
+	key := "pcd/alice/1_a.pcd"
+	content := "not a point cloud\n"
+	if err := local.Put(ctx, key, strings.NewReader(content), int64(len(content)), ""); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	mockUploadDAO := mocks.NewMockPCDUploadDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mockUploadDAO, mocks.NewMockPCDJobDAO(ctrl), mocks.NewMockPCDFileVersionDAO(ctrl), mocks.NewMockLocationDAO(ctrl))
+
+	// 未启用后台任务时先校验对象，校验失败不完成上传也不创建点云地图（mock 未预期 Complete）
+	mockUploadDAO.EXPECT().FindByObjectKey(ctx, key).Return(&entity.PCDUpload{
+		ObjectKey:    key,
+		UserName:     "alice",
+		DeclaredSize: int64(len(content)),
+		Status:       entity.PCDUploadStatusPending,
+		ExpireAt:     time.Now().Add(time.Hour),
+	}, nil)
+	mockPCDDAO.EXPECT().FindByName(ctx, "map").Return(nil, nil)
+
+	_, err = service.CompleteUpload(ctx, "alice", &dto.PCDFileCompleteUploadRequest{ObjectKey: key, Name: "map", Area: "A"})
+	if err == nil {
+		t.Fatal("Expected malformed point cloud to be rejected")
+	}
+}
+
+func TestPCDFileService_CreateAndUpdateRejectForeignObject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	service := NewPCDFileService(mockPCDDAO, mocks.NewMockPCDUploadDAO(ctrl), mocks.NewMockPCDJobDAO(ctrl), mocks.NewMockPCDFileVersionDAO(ctrl), mocks.NewMockLocationDAO(ctrl))
+	ctx := context.Background()
+
+	// 不能把其他用户上传的对象挂到自己的点云地图上
+	foreign := "pcd/bob/1_a.pcd"
+	if _, err := service.CreatePCDFile(ctx, "alice", &dto.PCDFileCreateRequest{Name: "map", Area: "A", Path: foreign, UserName: "alice", MinioPath: &foreign}); err == nil {
+		t.Error("Expected create with another user's object to be rejected")
+	}
+
+	own := "pcd/alice/1_a.pcd"
+	mockPCDDAO.EXPECT().FindByID(ctx, uint(3)).Return(&entity.PCDFile{Model: gorm.Model{ID: 3}, Name: "map", MinioPath: &own}, nil)
+	if err := service.UpdatePCDFile(ctx, 3, "alice", &dto.PCDFileUpdateRequest{MinioPath: &foreign}); err == nil {
+		t.Error("Expected update to another user's object to be rejected")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"robot_scheduler/internal/logger"
 	"robot_scheduler/internal/model/dto"
 	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
 
 	"go.uber.org/zap"
 )
@@ -14,74 +15,146 @@ import (
 // SemanticMapService 语义地图服务
 type SemanticMapService struct {
 	semanticDAO dao.SemanticMapDAO
+	versionDAO  dao.SemanticMapVersionDAO
+	pcdDAO      dao.PCDFileDAO
+	gridDAO     dao.OccupancyGridDAO
+	locationDAO dao.LocationDAO
+
+	frameService *FrameService
 }
 
-func NewSemanticMapService(semanticDAO dao.SemanticMapDAO) *SemanticMapService {
+func NewSemanticMapService(semanticDAO dao.SemanticMapDAO, versionDAO dao.SemanticMapVersionDAO, pcdDAO dao.PCDFileDAO, gridDAO dao.OccupancyGridDAO, locationDAO dao.LocationDAO, frameService *FrameService) *SemanticMapService {
 	return &SemanticMapService{
-		semanticDAO: semanticDAO,
+		semanticDAO:  semanticDAO,
+		versionDAO:   versionDAO,
+		pcdDAO:       pcdDAO,
+		gridDAO:      gridDAO,
+		locationDAO:  locationDAO,
+		frameService: frameService,
 	}
 }
 
-// CreateSemanticMap 创建语义地图
+// CreateSemanticMap 创建语义地图，底图为点云地图或二维栅格地图之一
 func (s *SemanticMapService) CreateSemanticMap(ctx context.Context, req *dto.SemanticMapCreateRequest) (*dto.SemanticMapResponse, error) {
-	logger.Info("creating semantic map in service", zap.Uint("pcdFileID", req.PCDFileID))
+	logger.Info("creating semantic map in service", zap.Uintp("pcdFileID", req.PCDFileID), zap.Uintp("occupancyGridID", req.OccupancyGridID))
+
+	info, err := semantic.Parse(req.SemanticInfo)
+	if err != nil {
+		logger.Warn("semantic info rejected", zap.Error(err))
+		return nil, err
+	}
 
 	// 创建语义地图实体
 	semanticMap := &entity.SemanticMap{
-		PCDFileID:    req.PCDFileID,
 		UserName:     req.UserName,
-		SemanticInfo: req.SemanticInfo,
+		SemanticInfo: info.String(),
 		ExtraInfo:    req.ExtraInfo,
 	}
+	if err := s.setBaseLayer(ctx, semanticMap, req.PCDFileID, req.OccupancyGridID); err != nil {
+		logger.Warn("semantic map base layer rejected", zap.Error(err))
+		return nil, err
+	}
+	if semanticMap.LocationID, err = resolveLocationID(ctx, s.locationDAO, req.LocationID); err != nil {
+		return nil, err
+	}
+	if semanticMap.LocationID == nil && semanticMap.PCDFile != nil {
+		// 未指定位置时沿用点云底图的位置节点
+		semanticMap.LocationID = semanticMap.PCDFile.LocationID
+	}
 
 	// 保存到数据库
 	if err := s.semanticDAO.Create(ctx, semanticMap); err != nil {
 		logger.Error("failed to create semantic map in service", zap.Error(err))
 		return nil, err
 	}
+	if err := recordSemanticMapVersion(ctx, s.versionDAO, s.pcdDAO, semanticMap, req.UserName, req.Message); err != nil {
+		logger.Error("failed to create semantic map version in service", zap.Error(err), zap.Uint("id", semanticMap.ID))
+		return nil, err
+	}
+
+	s.refreshCheck(ctx, semanticMap)
 
 	logger.Info("semantic map created successfully in service", zap.Uint("id", semanticMap.ID))
 	return dto.NewSemanticMapResponseFromEntity(semanticMap), nil
 }
 
-// UpdateSemanticMap 更新语义地图
-func (s *SemanticMapService) UpdateSemanticMap(ctx context.Context, id uint, req *dto.SemanticMapUpdateRequest) error {
+// UpdateSemanticMap 更新语义地图，ifMatch 不为空时要求当前修订号与之一致，编辑锁由他人持有时拒绝更新
+func (s *SemanticMapService) UpdateSemanticMap(ctx context.Context, id uint, req *dto.SemanticMapUpdateRequest, editor string, ifMatch *int) (*dto.SemanticMapResponse, error) {
 	logger.Info("updating semantic map in service", zap.Uint("id", id))
 
 	// 获取语义地图
 	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
 	if err != nil {
 		logger.Error("failed to find semantic map for update", zap.Error(err), zap.Uint("id", id))
-		return err
+		return nil, err
 	}
 
 	if semanticMap == nil {
 		logger.Warn("semantic map not found for update", zap.Uint("id", id))
-		return errors.New("semantic map not found")
+		return nil, errors.New("semantic map not found")
+	}
+	if err := checkEditable(semanticMap, editor, ifMatch); err != nil {
+		return nil, err
+	}
+
+	// 编辑语义内容时生成新版本，旧内容保留在历史版本中
+	contentChanged := req.PCDFileID != nil || req.OccupancyGridID != nil || req.SemanticInfo != nil || req.ExtraInfo != nil
+	var versions []*entity.SemanticMapVersion
+	if contentChanged {
+		base, err := s.baseVersion(ctx, semanticMap)
+		if err != nil {
+			return nil, err
+		}
+		if base != nil {
+			versions = append(versions, base)
+		}
 	}
 
 	// 更新字段
-	if req.PCDFileID != nil {
-		semanticMap.PCDFileID = *req.PCDFileID
+	if req.PCDFileID != nil || req.OccupancyGridID != nil {
+		if err := s.setBaseLayer(ctx, semanticMap, req.PCDFileID, req.OccupancyGridID); err != nil {
+			logger.Warn("semantic map base layer rejected", zap.Error(err), zap.Uint("id", id))
+			return nil, err
+		}
 	}
 	if req.UserName != nil {
 		semanticMap.UserName = *req.UserName
 	}
 	if req.SemanticInfo != nil {
-		semanticMap.SemanticInfo = *req.SemanticInfo
+		info, err := semantic.Parse(*req.SemanticInfo)
+		if err != nil {
+			logger.Warn("semantic info rejected", zap.Error(err), zap.Uint("id", id))
+			return nil, err
+		}
+		semanticMap.SemanticInfo = info.String()
 	}
 	if req.ExtraInfo != nil {
 		semanticMap.ExtraInfo = req.ExtraInfo
 	}
+	if req.LocationID != nil {
+		if semanticMap.LocationID, err = resolveLocationID(ctx, s.locationDAO, req.LocationID); err != nil {
+			return nil, err
+		}
+	}
 
-	// 保存更新
-	if err := s.semanticDAO.Update(ctx, semanticMap); err != nil {
+	// 保存更新，内容变化时在同一事务中生成新版本
+	if contentChanged {
+		version, err := newSemanticMapVersion(ctx, s.pcdDAO, semanticMap, semanticMap.UserName, req.Message)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	if err := s.semanticDAO.UpdateWithVersions(ctx, semanticMap, editor, versions...); err != nil {
 		logger.Error("failed to update semantic map in service", zap.Error(err), zap.Uint("id", id))
-		return err
+		return nil, err
+	}
+	if contentChanged {
+		s.refreshCheck(ctx, semanticMap)
 	}
 
-	logger.Info("semantic map updated successfully in service", zap.Uint("id", id))
-	return nil
+	logger.Info("semantic map updated successfully in service", zap.Uint("id", id), zap.Int("revision", semanticMap.Revision))
+	return dto.NewSemanticMapResponseFromEntity(semanticMap), nil
 }
 
 // DeleteSemanticMap 删除语义地图
@@ -103,8 +176,31 @@ func (s *SemanticMapService) GetSemanticMapByID(ctx context.Context, id uint) (*
 	return dto.NewSemanticMapResponseFromEntity(semanticMap), nil
 }
 
-// ListSemanticMaps 分页获取语义地图列表
-func (s *SemanticMapService) ListSemanticMaps(ctx context.Context, req dto.PageRequest) (*dto.SemanticMapListResponse, error) {
+// GetSemanticMapInFrame 获取语义地图，target 不为空时把语义信息中的坐标换算到该坐标系；地图不存在时返回 nil
+func (s *SemanticMapService) GetSemanticMapInFrame(ctx context.Context, id uint, target string) (*dto.SemanticMapResponse, error) {
+	logger.Debug("getting semantic map in frame in service", zap.Uint("id", id), zap.String("frame", target))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	resp := dto.NewSemanticMapResponseFromEntity(semanticMap)
+	toTarget, err := s.framePoseFunc(ctx, resp.Frame, target)
+	if err != nil || toTarget == nil {
+		return resp, err
+	}
+
+	info, err := semantic.Parse(semanticMap.SemanticInfo)
+	if err != nil {
+		return nil, err
+	}
+	info.Transform(toTarget)
+	resp.SemanticInfo, resp.Frame = info.String(), target
+	return resp, nil
+}
+
+// ListSemanticMaps 分页获取语义地图列表，可按位置节点过滤
+func (s *SemanticMapService) ListSemanticMaps(ctx context.Context, req dto.SemanticMapListRequest) (*dto.SemanticMapListResponse, error) {
 	logger.Debug("listing semantic maps in service with pagination", zap.Int("page", req.Page), zap.Int("pageSize", req.PageSize))
 
 	if req.Page <= 0 {
@@ -116,7 +212,7 @@ func (s *SemanticMapService) ListSemanticMaps(ctx context.Context, req dto.PageR
 
 	offset := (req.Page - 1) * req.PageSize
 
-	maps, total, err := s.semanticDAO.FindPage(ctx, offset, req.PageSize)
+	maps, total, err := s.semanticDAO.FindPage(ctx, dao.SemanticMapFilter{LocationID: req.LocationID}, offset, req.PageSize)
 	if err != nil {
 		return nil, err
 	}
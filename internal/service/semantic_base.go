@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/pcd"
+	"robot_scheduler/internal/semantic"
+)
+
+// ErrSemanticBaseLayer 语义地图的底图无效：点云地图与二维栅格地图必须且只能指定一个，且需存在
+var ErrSemanticBaseLayer = errors.New("invalid semantic map base layer")
+
+// setBaseLayer 校验并设置语义地图的底图，pcdFileID 与 gridID 必须且只能指定一个
+func (s *SemanticMapService) setBaseLayer(ctx context.Context, semanticMap *entity.SemanticMap, pcdFileID, gridID *uint) error {
+	if (pcdFileID == nil) == (gridID == nil) {
+		return fmt.Errorf("%w: pcdFileId 与 occupancyGridId 必须且只能指定一个", ErrSemanticBaseLayer)
+	}
+
+	if pcdFileID != nil {
+		pcdFile, err := s.pcdDAO.FindByID(ctx, *pcdFileID)
+		if err != nil {
+			return err
+		}
+		if pcdFile == nil {
+			return fmt.Errorf("%w: 点云地图 %d 不存在", ErrSemanticBaseLayer, *pcdFileID)
+		}
+		if pcdFile.AnalysisStatus != nil && *pcdFile.AnalysisStatus != entity.PCDJobStatusSucceeded {
+			return fmt.Errorf("%w: 点云地图 %d 尚未解析完成或解析失败", ErrSemanticBaseLayer, *pcdFileID)
+		}
+		semanticMap.PCDFileID, semanticMap.PCDFile = &pcdFile.ID, pcdFile
+		semanticMap.OccupancyGridID, semanticMap.OccupancyGrid = nil, nil
+		return nil
+	}
+
+	grid, err := s.gridDAO.FindByID(ctx, *gridID)
+	if err != nil {
+		return err
+	}
+	if grid == nil {
+		return fmt.Errorf("%w: 栅格地图 %d 不存在", ErrSemanticBaseLayer, *gridID)
+	}
+	semanticMap.OccupancyGridID, semanticMap.OccupancyGrid = &grid.ID, grid
+	semanticMap.PCDFileID, semanticMap.PCDFile = nil, nil
+	return nil
+}
+
+// baseBounds 按当前底图ID重新查询底图并返回其水平包围盒。底图不存在时 found 为 false，
+// 点云缺少包围盒元数据时 bounds 为 nil
+func (s *SemanticMapService) baseBounds(ctx context.Context, semanticMap *entity.SemanticMap) (bounds *semantic.Bounds, found bool, err error) {
+	if semanticMap.OccupancyGridID != nil {
+		grid, err := s.gridDAO.FindByID(ctx, *semanticMap.OccupancyGridID)
+		if err != nil || grid == nil {
+			return nil, false, err
+		}
+		return gridBounds(grid), true, nil
+	}
+	if semanticMap.PCDFileID == nil {
+		return nil, false, nil
+	}
+	pcdFile, err := s.pcdDAO.FindByID(ctx, *semanticMap.PCDFileID)
+	if err != nil || pcdFile == nil {
+		return nil, false, err
+	}
+	return pcdBounds(pcdFile), true, nil
+}
+
+// gridBounds 返回二维栅格地图覆盖的水平范围
+func gridBounds(grid *entity.OccupancyGrid) *semantic.Bounds {
+	minX, minY, maxX, maxY := pcd.GridBounds(grid.Width, grid.Height, grid.Resolution, grid.OriginX, grid.OriginY, grid.OriginYaw)
+	return &semantic.Bounds{MinX: minX, MinY: minY, MaxX: maxX, MaxY: maxY}
+}
+
+// framePoseFunc 返回把 from 中的语义坐标换算到 to 的位姿变换，to 为空或与 from 相同时返回 nil
+func (s *SemanticMapService) framePoseFunc(ctx context.Context, from, to string) (func(semantic.Pose) semantic.Pose, error) {
+	if to == "" || to == from {
+		return nil, nil
+	}
+	if from == "" {
+		return nil, fmt.Errorf("%w: 语义地图没有关联底图", frame.ErrNoTransform)
+	}
+	transform, err := s.frameService.Resolve(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return semanticPoseFunc(transform), nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestSemanticMapService_CreateSemanticMapOnGrid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	mockVersionDAO := mocks.NewMockSemanticMapVersionDAO(ctrl)
+	mockGridDAO := mocks.NewMockOccupancyGridDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mockVersionDAO, mocks.NewMockPCDFileDAO(ctrl), mockGridDAO, mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	// 10m x 5m 的栅格，点 (20, 0) 超出范围
+	grid := &entity.OccupancyGrid{Model: gorm.Model{ID: 3}, Resolution: 0.1, Width: 100, Height: 50}
+	mockGridDAO.EXPECT().FindByID(ctx, uint(3)).Return(grid, nil).Times(2)
+	mockSemanticDAO.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.SemanticMap) error {
+		if m.PCDFileID != nil || m.OccupancyGridID == nil || *m.OccupancyGridID != 3 {
+			t.Fatalf("Expected grid base layer, got %+v", m)
+		}
+		m.ID = 1
+		return nil
+	})
+	mockVersionDAO.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, v *entity.SemanticMapVersion) error {
+		if v.OccupancyGridID == nil || v.PCDFileVersion != nil {
+			t.Fatalf("Expected version to record grid base layer, got %+v", v)
+		}
+		v.Version = 1
+		return nil
+	})
+	mockSemanticDAO.EXPECT().UpdateCheck(ctx, uint(1), gomock.Any()).DoAndReturn(func(_ context.Context, _ uint, check *entity.SemanticCheck) error {
+		if check.CheckSeverity == nil || *check.CheckSeverity != string(semantic.SeverityError) {
+			t.Fatalf("Expected out-of-bounds warning, got %+v", check)
+		}
+		return nil
+	})
+
+	gridID := uint(3)
+	resp, err := service.CreateSemanticMap(ctx, &dto.SemanticMapCreateRequest{
+		OccupancyGridID: &gridID,
+		UserName:        "alice",
+		SemanticInfo:    `{"pois":[{"id":"p1","type":"generic","pose":{"x":20,"y":0,"yaw":0}}]}`,
+	})
+	if err != nil {
+		t.Fatalf("CreateSemanticMap failed: %v", err)
+	}
+	if resp.OccupancyGridID == nil || resp.OccupancyGrid == nil || resp.PCDFileID != nil {
+		t.Errorf("Expected response with grid base layer, got %+v", resp)
+	}
+
+	// 底图必须且只能指定一个
+	pcdFileID := uint(2)
+	_, err = service.CreateSemanticMap(ctx, &dto.SemanticMapCreateRequest{PCDFileID: &pcdFileID, OccupancyGridID: &gridID, SemanticInfo: `{}`})
+	if !errors.Is(err, ErrSemanticBaseLayer) {
+		t.Errorf("Expected ErrSemanticBaseLayer, got %v", err)
+	}
+}
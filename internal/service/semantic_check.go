@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"robot_scheduler/internal/config"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
+
+	"go.uber.org/zap"
+)
+
+// semanticCheckBatchSize 后台每轮最多重新检查的语义地图数量
+const semanticCheckBatchSize = 100
+
+// CheckSemanticMap 立即对语义地图做一致性检查并保存报告，地图不存在时返回 nil
+func (s *SemanticMapService) CheckSemanticMap(ctx context.Context, id uint) (*dto.SemanticCheckResponse, error) {
+	logger.Info("checking semantic map in service", zap.Uint("id", id))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	return s.checkAndSave(ctx, semanticMap)
+}
+
+// GetSemanticCheck 获取最近一次一致性检查报告，从未检查过时立即检查；地图不存在时返回 nil
+func (s *SemanticMapService) GetSemanticCheck(ctx context.Context, id uint) (*dto.SemanticCheckResponse, error) {
+	logger.Debug("getting semantic map check in service", zap.Uint("id", id))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	if semanticMap.CheckReport == nil || semanticMap.CheckedAt == nil {
+		return s.checkAndSave(ctx, semanticMap)
+	}
+
+	var report semantic.Report
+	if err := json.Unmarshal([]byte(*semanticMap.CheckReport), &report); err != nil {
+		logger.Warn("stored semantic check report unreadable, checking again", zap.Error(err), zap.Uint("id", id))
+		return s.checkAndSave(ctx, semanticMap)
+	}
+	return dto.NewSemanticCheckResponse(id, semanticMap.CurrentVersion, &report, *semanticMap.CheckedAt), nil
+}
+
+// RunConsistencyChecks 定期重新检查结果已过期（地图或关联点云更新过）的语义地图，直到 ctx 取消
+func (s *SemanticMapService) RunConsistencyChecks(ctx context.Context, interval time.Duration) {
+	logger.Info("starting semantic map consistency checks", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.CheckStale(ctx); err != nil {
+			logger.Warn("semantic map consistency check failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckStale 重新检查一批结果已过期的语义地图，返回检查的数量
+func (s *SemanticMapService) CheckStale(ctx context.Context) (int, error) {
+	maps, err := s.semanticDAO.FindCheckStale(ctx, semanticCheckBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	checked := 0
+	for _, semanticMap := range maps {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := s.checkAndSave(ctx, semanticMap); err != nil {
+			logger.Warn("failed to check semantic map", zap.Error(err), zap.Uint("id", semanticMap.ID))
+			continue
+		}
+		checked++
+	}
+	if checked > 0 {
+		logger.Info("semantic map consistency checks finished", zap.Int("count", checked))
+	}
+	return checked, nil
+}
+
+// refreshCheck 编辑后立即重新检查，失败只记录日志，由后台任务补检
+func (s *SemanticMapService) refreshCheck(ctx context.Context, semanticMap *entity.SemanticMap) {
+	if _, err := s.checkAndSave(ctx, semanticMap); err != nil {
+		logger.Warn("failed to refresh semantic map check", zap.Error(err), zap.Uint("id", semanticMap.ID))
+	}
+}
+
+// checkAndSave 按底图范围检查语义地图并保存报告
+func (s *SemanticMapService) checkAndSave(ctx context.Context, semanticMap *entity.SemanticMap) (*dto.SemanticCheckResponse, error) {
+	report, err := s.check(ctx, semanticMap)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	text := string(raw)
+	severity := string(report.Severity)
+	checkedAt := time.Now()
+	check := &entity.SemanticCheck{CheckSeverity: &severity, CheckReport: &text, CheckedAt: &checkedAt}
+	if err := s.semanticDAO.UpdateCheck(ctx, semanticMap.ID, check); err != nil {
+		return nil, err
+	}
+	semanticMap.SemanticCheck = *check
+
+	logger.Debug("semantic map checked", zap.Uint("id", semanticMap.ID), zap.String("severity", severity), zap.Int("issues", len(report.Issues)))
+	return dto.NewSemanticCheckResponse(semanticMap.ID, semanticMap.CurrentVersion, report, checkedAt), nil
+}
+
+func (s *SemanticMapService) check(ctx context.Context, semanticMap *entity.SemanticMap) (*semantic.Report, error) {
+	info, err := semantic.Parse(semanticMap.SemanticInfo)
+	if err != nil {
+		// 结构化之前保存的自由文本无法检查，报告为错误提示用户整体更新
+		report := semantic.NewReport()
+		report.Add(semantic.SeverityError, semantic.IssueSchemaInvalid, "", nil, "语义信息不符合结构定义: %v", err)
+		return report, nil
+	}
+
+	// 关联的底图可能在加载语义地图后被更换，按当前底图ID重新查询
+	bounds, found, err := s.baseBounds(ctx, semanticMap)
+	if err != nil {
+		return nil, err
+	}
+	report := info.Check(bounds, semanticCheckMargin())
+	switch {
+	case found:
+	case semanticMap.OccupancyGridID != nil:
+		report.Add(semantic.SeverityError, semantic.IssueGridMissing, "", nil, "关联的栅格地图 %d 不存在或已删除", *semanticMap.OccupancyGridID)
+	case semanticMap.PCDFileID != nil:
+		report.Add(semantic.SeverityError, semantic.IssuePCDMissing, "", nil, "关联的点云地图 %d 不存在或已删除", *semanticMap.PCDFileID)
+	default:
+		report.Add(semantic.SeverityError, semantic.IssuePCDMissing, "", nil, "语义地图没有关联底图")
+	}
+	return report, nil
+}
+
+// pcdBounds 返回点云的水平包围盒，点云不存在或缺少元数据时返回 nil
+func pcdBounds(file *entity.PCDFile) *semantic.Bounds {
+	if file == nil || file.MinX == nil || file.MinY == nil || file.MaxX == nil || file.MaxY == nil {
+		return nil
+	}
+	return &semantic.Bounds{MinX: *file.MinX, MinY: *file.MinY, MaxX: *file.MaxX, MaxY: *file.MaxY}
+}
+
+func semanticCheckMargin() float64 {
+	cfg := config.Get()
+	if cfg == nil || cfg.SemanticCheck == nil || cfg.SemanticCheck.BoundsMargin <= 0 {
+		return 0.5
+	}
+	return cfg.SemanticCheck.BoundsMargin
+}
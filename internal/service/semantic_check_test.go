@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestSemanticMapService_CheckSemanticMap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mocks.NewMockSemanticMapVersionDAO(ctrl), mockPCDDAO, mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	info := `{"pois":[{"id":"p1","type":"generic","pose":{"x":20,"y":0,"yaw":0}}],"waypoints":[{"id":"a","x":0,"y":0}]}`
+	minX, minY, maxX, maxY := 0.0, 0.0, 10.0, 10.0
+	pcdFile := &entity.PCDFile{Model: gorm.Model{ID: 2}}
+	pcdFile.MinX, pcdFile.MinY, pcdFile.MaxX, pcdFile.MaxY = &minX, &minY, &maxX, &maxY
+
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(1)).Return(&entity.SemanticMap{Model: gorm.Model{ID: 1}, PCDFileID: &pcdFile.ID, SemanticInfo: info}, nil)
+	mockPCDDAO.EXPECT().FindByID(ctx, uint(2)).Return(pcdFile, nil)
+	mockSemanticDAO.EXPECT().UpdateCheck(ctx, uint(1), gomock.Any()).DoAndReturn(func(_ context.Context, _ uint, check *entity.SemanticCheck) error {
+		if check.CheckSeverity == nil || *check.CheckSeverity != string(semantic.SeverityError) || check.CheckedAt == nil {
+			t.Fatalf("Expected error severity to be saved, got %+v", check)
+		}
+		return nil
+	})
+
+	resp, err := service.CheckSemanticMap(ctx, 1)
+	if err != nil {
+		t.Fatalf("CheckSemanticMap failed: %v", err)
+	}
+	if resp.Severity != semantic.SeverityError || len(resp.Issues) != 1 || resp.Issues[0].Code != semantic.IssueOutOfBounds {
+		t.Fatalf("Expected single out of bounds issue, got %s %+v", resp.Severity, resp.Issues)
+	}
+}
+
+func TestSemanticMapService_CheckSemanticMap_LegacyInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mocks.NewMockSemanticMapVersionDAO(ctrl), mocks.NewMockPCDFileDAO(ctrl), mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(1)).Return(&entity.SemanticMap{Model: gorm.Model{ID: 1}, SemanticInfo: "free text"}, nil)
+	mockSemanticDAO.EXPECT().UpdateCheck(ctx, uint(1), gomock.Any()).Return(nil)
+
+	resp, err := service.CheckSemanticMap(ctx, 1)
+	if err != nil {
+		t.Fatalf("CheckSemanticMap failed: %v", err)
+	}
+	if resp.Issues[0].Code != semantic.IssueSchemaInvalid {
+		t.Fatalf("Expected schema invalid issue, got %+v", resp.Issues)
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
+
+	"go.uber.org/zap"
+)
+
+// ErrSemanticMapVersionNotFound 指定的语义地图版本不存在
+var ErrSemanticMapVersionNotFound = errors.New("semantic map version not found")
+
+// DiffSemanticMap 比较语义地图两个版本的元素差异，to 为空时与当前内容比较；地图不存在时返回 nil
+func (s *SemanticMapService) DiffSemanticMap(ctx context.Context, id uint, from int, to *int) (*dto.SemanticDiffResponse, error) {
+	logger.Info("diffing semantic map in service", zap.Uint("id", id), zap.Int("from", from))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+
+	before, err := s.semanticContent(ctx, semanticMap, &from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.semanticContent(ctx, semanticMap, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := semantic.Diff(before.Map, after.Map)
+	logger.Info("semantic map diffed successfully in service", zap.Uint("id", id), zap.Int("changes", len(changes)))
+	return dto.NewSemanticDiffResponse(id, from, to, changes), nil
+}
+
+// MergeSemanticMap 以 base 版本为共同祖先三方合并两个编辑分支；地图不存在时返回 nil。
+// req.Apply 为 true 且无冲突时将合并结果保存为当前内容，编辑锁与 ifMatch 的校验同其他编辑操作
+func (s *SemanticMapService) MergeSemanticMap(ctx context.Context, id uint, req *dto.SemanticMergeRequest, editor string, ifMatch *int) (*dto.SemanticMergeResponse, error) {
+	logger.Info("merging semantic map in service", zap.Uint("id", id), zap.Int("base", req.Base), zap.Bool("apply", req.Apply))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	if req.Apply {
+		if err := checkEditable(semanticMap, editor, ifMatch); err != nil {
+			return nil, err
+		}
+	}
+
+	base, err := s.semanticContent(ctx, semanticMap, &req.Base)
+	if err != nil {
+		return nil, err
+	}
+	ours, err := s.semanticContent(ctx, semanticMap, req.Ours)
+	if err != nil {
+		return nil, err
+	}
+	var theirs *semantic.Map
+	if req.TheirsInfo != nil {
+		if theirs, err = semantic.Parse(*req.TheirsInfo); err != nil {
+			return nil, err
+		}
+	} else {
+		snapshot, err := s.semanticContent(ctx, semanticMap, req.Theirs)
+		if err != nil {
+			return nil, err
+		}
+		theirs = snapshot.Map
+	}
+
+	merged, conflicts := semantic.Merge(base.Map, ours.Map, theirs)
+	resp := &dto.SemanticMergeResponse{SemanticMapID: id, Merged: merged, Conflicts: conflicts}
+	if len(conflicts) > 0 {
+		logger.Info("semantic map merge has conflicts", zap.Uint("id", id), zap.Int("conflicts", len(conflicts)))
+		return resp, nil
+	}
+
+	if req.Apply {
+		if err := s.saveSemanticInfo(ctx, semanticMap, merged, editor, req.Message); err != nil {
+			return nil, err
+		}
+		resp.Applied = true
+		resp.Revision = semanticMap.Revision
+		logger.Info("semantic map merge applied in service", zap.Uint("id", id), zap.Int("revision", semanticMap.Revision))
+	}
+	return resp, nil
+}
+
+// semanticSnapshot 语义地图某个版本的内容及该版本绑定的底图
+type semanticSnapshot struct {
+	Map             *semantic.Map
+	Version         *int
+	PCDFileID       *uint
+	OccupancyGridID *uint
+}
+
+// semanticContent 解析语义地图指定版本的内容，version 为空时使用当前内容；版本不存在时返回 ErrSemanticMapVersionNotFound
+// 返回的底图与内容来自同一版本，坐标系换算须使用它而不是地图当前的底图
+func (s *SemanticMapService) semanticContent(ctx context.Context, semanticMap *entity.SemanticMap, version *int) (*semanticSnapshot, error) {
+	info := semanticMap.SemanticInfo
+	snapshot := &semanticSnapshot{
+		Version:         semanticMap.CurrentVersion,
+		PCDFileID:       semanticMap.PCDFileID,
+		OccupancyGridID: semanticMap.OccupancyGridID,
+	}
+	if version != nil {
+		v, err := s.versionDAO.FindByVersion(ctx, semanticMap.ID, *version)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, fmt.Errorf("%w: 语义地图 %d 不存在版本 %d", ErrSemanticMapVersionNotFound, semanticMap.ID, *version)
+		}
+		info = v.SemanticInfo
+		snapshot.Version, snapshot.PCDFileID, snapshot.OccupancyGridID = &v.Version, v.PCDFileID, v.OccupancyGridID
+	}
+
+	m, err := semantic.Parse(info)
+	if err != nil {
+		logger.Warn("semantic info does not match schema", zap.Error(err), zap.Uint("id", semanticMap.ID))
+		return nil, fmt.Errorf("语义信息不符合结构定义: %w", err)
+	}
+	snapshot.Map = m
+	return snapshot, nil
+}
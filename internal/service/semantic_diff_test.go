@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestSemanticMapService_DiffSemanticMap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	mockVersionDAO := mocks.NewMockSemanticMapVersionDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mockVersionDAO, mocks.NewMockPCDFileDAO(ctrl), mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	before := `{"waypoints":[{"id":"a","x":0,"y":0},{"id":"b","x":1,"y":0}]}`
+	after := `{"waypoints":[{"id":"a","x":0,"y":2},{"id":"c","x":3,"y":0}]}`
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(1)).Return(&entity.SemanticMap{Model: gorm.Model{ID: 1}, SemanticInfo: after}, nil)
+	mockVersionDAO.EXPECT().FindByVersion(ctx, uint(1), 1).Return(&entity.SemanticMapVersion{SemanticMapID: 1, Version: 1, SemanticInfo: before}, nil)
+
+	resp, err := service.DiffSemanticMap(ctx, 1, 1, nil)
+	if err != nil {
+		t.Fatalf("DiffSemanticMap failed: %v", err)
+	}
+	if resp.Added != 1 || resp.Removed != 1 || resp.Modified != 1 {
+		t.Errorf("Expected 1 added, 1 removed, 1 modified, got %+v", resp)
+	}
+
+	// 版本不存在
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(1)).Return(&entity.SemanticMap{Model: gorm.Model{ID: 1}, SemanticInfo: after}, nil)
+	mockVersionDAO.EXPECT().FindByVersion(ctx, uint(1), 9).Return(nil, nil)
+	if _, err := service.DiffSemanticMap(ctx, 1, 9, nil); !errors.Is(err, ErrSemanticMapVersionNotFound) {
+		t.Fatalf("Expected ErrSemanticMapVersionNotFound, got %v", err)
+	}
+}
+
+func TestSemanticMapService_MergeSemanticMap_ConflictNotApplied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	mockVersionDAO := mocks.NewMockSemanticMapVersionDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mockVersionDAO, mocks.NewMockPCDFileDAO(ctrl), mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	base := `{"waypoints":[{"id":"a","x":0,"y":0}]}`
+	ours := `{"waypoints":[{"id":"a","x":1,"y":0}]}`
+	theirs := `{"waypoints":[{"id":"a","x":2,"y":0}]}`
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(1)).Return(&entity.SemanticMap{Model: gorm.Model{ID: 1}, Revision: 3, SemanticInfo: ours}, nil)
+	mockVersionDAO.EXPECT().FindByVersion(ctx, uint(1), 1).Return(&entity.SemanticMapVersion{SemanticMapID: 1, Version: 1, SemanticInfo: base}, nil)
+
+	// 存在冲突时不保存，mock 未设置 Update 期望
+	resp, err := service.MergeSemanticMap(ctx, 1, &dto.SemanticMergeRequest{Base: 1, TheirsInfo: &theirs, Apply: true}, "bob", nil)
+	if err != nil {
+		t.Fatalf("MergeSemanticMap failed: %v", err)
+	}
+	if resp.Applied || len(resp.Conflicts) != 1 || resp.Conflicts[0].Field != "x" {
+		t.Errorf("Expected one unapplied conflict on x, got %+v", resp)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
+
+	"go.uber.org/zap"
+)
+
+// ListSemanticElements 获取语义地图中指定类型的全部元素，地图不存在时返回 nil。
+// frameName 不为空时坐标换算到该坐标系
+func (s *SemanticMapService) ListSemanticElements(ctx context.Context, id uint, kind semantic.Kind, frameName string) (interface{}, error) {
+	logger.Debug("listing semantic elements in service", zap.Uint("id", id), zap.String("kind", string(kind)), zap.String("frame", frameName))
+
+	semanticMap, info, err := s.findSemanticInfo(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	toTarget, err := s.framePoseFunc(ctx, frame.MapFrame(semanticMap.PCDFileID, semanticMap.OccupancyGridID), frameName)
+	if err != nil {
+		return nil, err
+	}
+	if toTarget != nil {
+		info.Transform(toTarget)
+	}
+	return info.Elements(kind)
+}
+
+// GetSemanticElement 获取语义地图中的单个元素，地图不存在时返回 nil，元素不存在时返回 semantic.ErrElementNotFound。
+// frameName 不为空时坐标换算到该坐标系
+func (s *SemanticMapService) GetSemanticElement(ctx context.Context, id uint, kind semantic.Kind, elementID string, frameName string) (semantic.Element, error) {
+	logger.Debug("getting semantic element in service", zap.Uint("id", id), zap.String("kind", string(kind)), zap.String("elementID", elementID), zap.String("frame", frameName))
+
+	semanticMap, info, err := s.findSemanticInfo(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	element, err := info.Element(kind, elementID)
+	if err != nil {
+		return nil, err
+	}
+	toTarget, err := s.framePoseFunc(ctx, frame.MapFrame(semanticMap.PCDFileID, semanticMap.OccupancyGridID), frameName)
+	if err != nil {
+		return nil, err
+	}
+	if toTarget != nil {
+		semantic.TransformElement(element, toTarget)
+	}
+	return element, nil
+}
+
+// SaveSemanticElement 新增（elementID 为空）或替换语义地图中的单个元素，并生成新版本；地图不存在时返回 nil。
+// ifMatch 不为空时要求当前修订号与之一致；frameName 不为空时请求中的坐标位于该坐标系，保存前换算到地图坐标系，
+// 返回的元素坐标仍位于该坐标系
+func (s *SemanticMapService) SaveSemanticElement(ctx context.Context, id uint, kind semantic.Kind, elementID string, data []byte, author string, ifMatch *int, frameName string) (semantic.Element, error) {
+	logger.Info("saving semantic element in service", zap.Uint("id", id), zap.String("kind", string(kind)), zap.String("elementID", elementID), zap.String("frame", frameName))
+
+	semanticMap, info, err := s.findSemanticInfo(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	if err := checkEditable(semanticMap, author, ifMatch); err != nil {
+		return nil, err
+	}
+	var fromTarget, toTarget func(semantic.Pose) semantic.Pose
+	if frameName != "" {
+		mapFrame := frame.MapFrame(semanticMap.PCDFileID, semanticMap.OccupancyGridID)
+		if fromTarget, err = s.framePoseFunc(ctx, frameName, mapFrame); err != nil {
+			return nil, err
+		}
+		if toTarget, err = s.framePoseFunc(ctx, mapFrame, frameName); err != nil {
+			return nil, err
+		}
+	}
+
+	element, err := info.Put(kind, elementID, data)
+	if err != nil {
+		logger.Warn("semantic element rejected", zap.Error(err), zap.Uint("id", id), zap.String("kind", string(kind)))
+		return nil, err
+	}
+	if fromTarget != nil {
+		semantic.TransformElement(element, fromTarget)
+	}
+	if err := s.saveSemanticInfo(ctx, semanticMap, info, author, nil); err != nil {
+		return nil, err
+	}
+	if toTarget != nil {
+		semantic.TransformElement(element, toTarget)
+	}
+
+	logger.Info("semantic element saved successfully in service", zap.Uint("id", id), zap.String("kind", string(kind)), zap.String("elementID", element.ElementID()))
+	return element, nil
+}
+
+// DeleteSemanticElement 删除语义地图中的单个元素并生成新版本，删除路网节点时一并删除与其相连的边
+func (s *SemanticMapService) DeleteSemanticElement(ctx context.Context, id uint, kind semantic.Kind, elementID string, author string, ifMatch *int) error {
+	logger.Info("deleting semantic element in service", zap.Uint("id", id), zap.String("kind", string(kind)), zap.String("elementID", elementID))
+
+	semanticMap, info, err := s.findSemanticInfo(ctx, id)
+	if err != nil {
+		return err
+	}
+	if semanticMap == nil {
+		logger.Warn("semantic map not found for element deletion", zap.Uint("id", id))
+		return errors.New("semantic map not found")
+	}
+	if err := checkEditable(semanticMap, author, ifMatch); err != nil {
+		return err
+	}
+
+	if err := info.Remove(kind, elementID); err != nil {
+		return err
+	}
+	return s.saveSemanticInfo(ctx, semanticMap, info, author, nil)
+}
+
+// findSemanticInfo 查询语义地图并解析语义信息，地图不存在时返回 nil
+func (s *SemanticMapService) findSemanticInfo(ctx context.Context, id uint) (*entity.SemanticMap, *semantic.Map, error) {
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, nil, err
+	}
+	info, err := semantic.Parse(semanticMap.SemanticInfo)
+	if err != nil {
+		// 结构化之前保存的自由文本无法按元素编辑，需要先整体更新为新结构
+		logger.Warn("stored semantic info does not match schema", zap.Error(err), zap.Uint("id", id))
+		return nil, nil, fmt.Errorf("语义信息不符合结构定义，请先整体更新: %w", err)
+	}
+	return semanticMap, info, nil
+}
+
+// saveSemanticInfo 保存按元素编辑或合并后的语义信息，并在同一事务中生成新版本
+func (s *SemanticMapService) saveSemanticInfo(ctx context.Context, semanticMap *entity.SemanticMap, info *semantic.Map, author string, message *string) error {
+	var versions []*entity.SemanticMapVersion
+	base, err := s.baseVersion(ctx, semanticMap)
+	if err != nil {
+		return err
+	}
+	if base != nil {
+		versions = append(versions, base)
+	}
+
+	semanticMap.SemanticInfo = info.String()
+	version, err := newSemanticMapVersion(ctx, s.pcdDAO, semanticMap, author, message)
+	if err != nil {
+		return err
+	}
+	versions = append(versions, version)
+	if err := s.semanticDAO.UpdateWithVersions(ctx, semanticMap, author, versions...); err != nil {
+		logger.Error("failed to save semantic info in service", zap.Error(err), zap.Uint("id", semanticMap.ID))
+		return err
+	}
+	s.refreshCheck(ctx, semanticMap)
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"robot_scheduler/internal/config"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
+
+	"go.uber.org/zap"
+)
+
+// ErrGeoReferenceNotConfigured 未配置地图坐标系到 WGS84 的换算参数
+var ErrGeoReferenceNotConfigured = errors.New("geo reference not configured")
+
+// ExportSemanticGeoJSON 将语义地图导出为 GeoJSON 要素集合；地图不存在时返回 nil
+func (s *SemanticMapService) ExportSemanticGeoJSON(ctx context.Context, id uint, crs string) (*semantic.FeatureCollection, error) {
+	logger.Info("exporting semantic map geojson in service", zap.Uint("id", id), zap.String("crs", crs))
+
+	transform, err := geoTransform(crs)
+	if err != nil {
+		return nil, err
+	}
+	semanticMap, info, err := s.findSemanticInfo(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	return info.ToGeoJSON(transform), nil
+}
+
+// ImportSemanticGeoJSON 由 GeoJSON 新建语义地图，底图为点云地图或二维栅格地图之一；底图不存在时返回 nil。
+// 要素转换出错时不保存，响应中的报告给出逐要素的问题，其余情况报告中附带按底图范围检查的一致性问题
+func (s *SemanticMapService) ImportSemanticGeoJSON(ctx context.Context, data []byte, req *dto.SemanticGeoJSONImportRequest, editor string) (*dto.SemanticGeoJSONImportResponse, error) {
+	logger.Info("importing semantic map geojson in service", zap.Uint("pcdFileID", req.PCDFileID), zap.Uint("occupancyGridID", req.OccupancyGridID), zap.Bool("dryRun", req.DryRun))
+
+	base := &entity.SemanticMap{}
+	if req.PCDFileID != 0 {
+		base.PCDFileID = &req.PCDFileID
+	}
+	if req.OccupancyGridID != 0 {
+		base.OccupancyGridID = &req.OccupancyGridID
+	}
+	bounds, found, err := s.baseBounds(ctx, base)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	info, resp, err := convertGeoJSON(data, req.CRS, bounds)
+	if err != nil || req.DryRun || !resp.Valid {
+		return resp, err
+	}
+
+	semanticMap, err := s.CreateSemanticMap(ctx, &dto.SemanticMapCreateRequest{
+		PCDFileID:       base.PCDFileID,
+		OccupancyGridID: base.OccupancyGridID,
+		UserName:        editor,
+		SemanticInfo:    info.String(),
+		Message:         req.Message,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Imported, resp.SemanticMap = true, semanticMap
+	return resp, nil
+}
+
+// ReplaceSemanticGeoJSON 以 GeoJSON 内容整体替换语义地图并生成新版本；地图不存在时返回 nil。
+// 编辑锁与 ifMatch 的校验同其他编辑操作，要素转换出错时不保存
+func (s *SemanticMapService) ReplaceSemanticGeoJSON(ctx context.Context, id uint, data []byte, req *dto.SemanticGeoJSONImportRequest, editor string, ifMatch *int) (*dto.SemanticGeoJSONImportResponse, error) {
+	logger.Info("replacing semantic map from geojson in service", zap.Uint("id", id), zap.Bool("dryRun", req.DryRun))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+	if !req.DryRun {
+		if err := checkEditable(semanticMap, editor, ifMatch); err != nil {
+			return nil, err
+		}
+	}
+
+	bounds, _, err := s.baseBounds(ctx, semanticMap)
+	if err != nil {
+		return nil, err
+	}
+	info, resp, err := convertGeoJSON(data, req.CRS, bounds)
+	if err != nil || req.DryRun || !resp.Valid {
+		return resp, err
+	}
+
+	if err := s.saveSemanticInfo(ctx, semanticMap, info, editor, req.Message); err != nil {
+		return nil, err
+	}
+	resp.Imported, resp.SemanticMap = true, dto.NewSemanticMapResponseFromEntity(semanticMap)
+	logger.Info("semantic map replaced from geojson in service", zap.Uint("id", id), zap.Int("revision", semanticMap.Revision))
+	return resp, nil
+}
+
+// convertGeoJSON 转换 GeoJSON 并生成校验报告，转换无错误时追加按底图范围的一致性检查结果
+func convertGeoJSON(data []byte, crs string, bounds *semantic.Bounds) (*semantic.Map, *dto.SemanticGeoJSONImportResponse, error) {
+	transform, err := geoTransform(crs)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, report, err := semantic.FromGeoJSON(data, transform)
+	if err != nil {
+		logger.Warn("geojson rejected", zap.Error(err))
+		return nil, nil, err
+	}
+	valid := report.Severity != semantic.SeverityError
+	if valid {
+		report.Merge(info.Check(bounds, semanticCheckMargin()))
+	}
+	return info, &dto.SemanticGeoJSONImportResponse{Valid: valid, Counts: info.Counts(), Report: report}, nil
+}
+
+// geoTransform 按坐标参考返回换算参数，local 返回 nil
+func geoTransform(crs string) (*semantic.GeoTransform, error) {
+	if crs != dto.GeoJSONCRSWGS84 {
+		return nil, nil
+	}
+	cfg := config.Get()
+	if cfg == nil || cfg.GeoReference == nil || !cfg.GeoReference.Enabled {
+		return nil, ErrGeoReferenceNotConfigured
+	}
+	ref := cfg.GeoReference
+	return &semantic.GeoTransform{
+		OriginLon: ref.OriginLon,
+		OriginLat: ref.OriginLat,
+		Rotation:  ref.Rotation * math.Pi / 180,
+	}, nil
+}
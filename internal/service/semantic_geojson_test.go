@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestSemanticMapService_ImportSemanticGeoJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	mockPCDDAO := mocks.NewMockPCDFileDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mocks.NewMockSemanticMapVersionDAO(ctrl), mockPCDDAO, mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	minX, minY, maxX, maxY := 0.0, 0.0, 10.0, 10.0
+	pcdFile := &entity.PCDFile{Model: gorm.Model{ID: 2}}
+	pcdFile.MinX, pcdFile.MinY, pcdFile.MaxX, pcdFile.MaxY = &minX, &minY, &maxX, &maxY
+	mockPCDDAO.EXPECT().FindByID(ctx, uint(2)).Return(pcdFile, nil).Times(2)
+
+	// 要素转换出错时不保存，mock 未设置 Create 期望
+	bad := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "id": "p", "geometry": {"type": "Point", "coordinates": [1, 1]}, "properties": {"poiType": "toilet"}}
+	]}`
+	resp, err := service.ImportSemanticGeoJSON(ctx, []byte(bad), &dto.SemanticGeoJSONImportRequest{PCDFileID: 2}, "bob")
+	if err != nil {
+		t.Fatalf("ImportSemanticGeoJSON failed: %v", err)
+	}
+	if resp.Valid || resp.Imported {
+		t.Errorf("Expected invalid, unsaved import, got %+v", resp)
+	}
+
+	// 超出点云范围只作为一致性问题报告，dryRun 不保存
+	outside := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "id": "p", "geometry": {"type": "Point", "coordinates": [20, 1]}, "properties": {"kind": "poi"}}
+	]}`
+	resp, err = service.ImportSemanticGeoJSON(ctx, []byte(outside), &dto.SemanticGeoJSONImportRequest{PCDFileID: 2, DryRun: true}, "bob")
+	if err != nil {
+		t.Fatalf("ImportSemanticGeoJSON dry run failed: %v", err)
+	}
+	if !resp.Valid || resp.Imported || resp.Report.Severity != "error" {
+		t.Errorf("Expected valid dry run with out-of-bounds error, got %+v", resp)
+	}
+}
+
+func TestSemanticMapService_ExportSemanticGeoJSON_NoGeoReference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewSemanticMapService(mocks.NewMockSemanticMapDAO(ctrl), mocks.NewMockSemanticMapVersionDAO(ctrl), mocks.NewMockPCDFileDAO(ctrl), mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	if _, err := service.ExportSemanticGeoJSON(context.Background(), 1, dto.GeoJSONCRSWGS84); !errors.Is(err, ErrGeoReferenceNotConfigured) {
+		t.Fatalf("Expected ErrGeoReferenceNotConfigured, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/testutil/mocks"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestSemanticMapService_UpdateSemanticMap_StaleRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mocks.NewMockSemanticMapVersionDAO(ctrl), mocks.NewMockPCDFileDAO(ctrl), mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(1)).Return(&entity.SemanticMap{Model: gorm.Model{ID: 1}, Revision: 4}, nil)
+
+	stale := 3
+	userName := "bob"
+	_, err := service.UpdateSemanticMap(ctx, 1, &dto.SemanticMapUpdateRequest{UserName: &userName}, "bob", &stale)
+	if !errors.Is(err, dao.ErrSemanticMapConflict) {
+		t.Fatalf("Expected ErrSemanticMapConflict, got %v", err)
+	}
+}
+
+func TestSemanticMapService_UpdateSemanticMap_LockedByOther(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mocks.NewMockSemanticMapVersionDAO(ctrl), mocks.NewMockPCDFileDAO(ctrl), mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	owner := "alice"
+	expiresAt := time.Now().Add(time.Minute)
+	semanticMap := &entity.SemanticMap{Model: gorm.Model{ID: 1}, Revision: 2}
+	semanticMap.LockedBy, semanticMap.LockExpiresAt = &owner, &expiresAt
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(1)).Return(semanticMap, nil).Times(2)
+
+	current := 2
+	userName := "bob"
+	if _, err := service.UpdateSemanticMap(ctx, 1, &dto.SemanticMapUpdateRequest{UserName: &userName}, "bob", &current); !errors.Is(err, dao.ErrSemanticMapLocked) {
+		t.Fatalf("Expected ErrSemanticMapLocked for other user, got %v", err)
+	}
+
+	// 锁持有人本人可以更新
+	mockSemanticDAO.EXPECT().UpdateWithVersions(ctx, semanticMap, "alice").DoAndReturn(func(_ context.Context, m *entity.SemanticMap, _ string, _ ...*entity.SemanticMapVersion) error {
+		m.Revision++
+		return nil
+	})
+	resp, err := service.UpdateSemanticMap(ctx, 1, &dto.SemanticMapUpdateRequest{UserName: &owner}, "alice", &current)
+	if err != nil {
+		t.Fatalf("UpdateSemanticMap by lock owner failed: %v", err)
+	}
+	if resp.Revision != 3 {
+		t.Errorf("Expected revision 3, got %d", resp.Revision)
+	}
+}
+
+func TestSemanticMapService_UpdateSemanticMap_ConflictKeepsVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSemanticDAO := mocks.NewMockSemanticMapDAO(ctrl)
+	mockVersionDAO := mocks.NewMockSemanticMapVersionDAO(ctrl)
+	service := NewSemanticMapService(mockSemanticDAO, mockVersionDAO, mocks.NewMockPCDFileDAO(ctrl), mocks.NewMockOccupancyGridDAO(ctrl), mocks.NewMockLocationDAO(ctrl), NewFrameService(mocks.NewMockFrameTransformDAO(ctrl)))
+	ctx := context.Background()
+
+	// 尚无版本的地图：基础版本与新版本随更新一并提交，不单独写入
+	semanticMap := &entity.SemanticMap{Model: gorm.Model{ID: 1}, Revision: 2, SemanticInfo: "old"}
+	mockSemanticDAO.EXPECT().FindByID(ctx, uint(1)).Return(semanticMap, nil)
+	mockSemanticDAO.EXPECT().UpdateWithVersions(ctx, semanticMap, "bob", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *entity.SemanticMap, _ string, versions ...*entity.SemanticMapVersion) error {
+			if versions[0].ExtraInfo != nil || versions[1].ExtraInfo == nil || *versions[1].ExtraInfo != "extra" {
+				t.Errorf("Expected base version with old content followed by new content, got %+v", versions)
+			}
+			return dao.ErrSemanticMapConflict
+		})
+
+	current := 2
+	extra := "extra"
+	if _, err := service.UpdateSemanticMap(ctx, 1, &dto.SemanticMapUpdateRequest{ExtraInfo: &extra}, "bob", &current); !errors.Is(err, dao.ErrSemanticMapConflict) {
+		t.Fatalf("Expected ErrSemanticMapConflict, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"robot_scheduler/internal/frame"
+	"robot_scheduler/internal/logger"
+	"robot_scheduler/internal/model/dto"
+	"robot_scheduler/internal/semantic"
+
+	"go.uber.org/zap"
+)
+
+// PlanPath 在语义地图的路网上规划路径，地图不存在时返回 nil
+// 调度与任务校验可直接在进程内调用本方法，规划失败时返回 semantic.ErrUnknownNode 或 semantic.ErrNoPath
+func (s *SemanticMapService) PlanPath(ctx context.Context, id uint, req *dto.SemanticPlanRequest) (*dto.SemanticPlanResponse, error) {
+	logger.Info("planning path in service", zap.Uint("id", id), zap.String("from", req.From), zap.String("to", req.To), zap.String("deviceType", req.DeviceType))
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, id)
+	if err != nil || semanticMap == nil {
+		return nil, err
+	}
+
+	// 默认使用当前内容，指定版本时使用历史版本的语义信息与底图，保证历史任务可复现
+	snapshot, err := s.semanticContent(ctx, semanticMap, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := semantic.PlanOptions{From: req.From, To: req.To, DeviceType: req.DeviceType}
+	if req.Speed != nil {
+		opts.Speed = *req.Speed
+	}
+	plan, err := snapshot.Map.Plan(opts)
+	if err != nil {
+		logger.Warn("path planning failed", zap.Error(err), zap.Uint("id", id))
+		return nil, err
+	}
+
+	// 规划在地图坐标系中进行，结果中的路网节点按需换算到请求的坐标系
+	mapFrame := frame.MapFrame(snapshot.PCDFileID, snapshot.OccupancyGridID)
+	toTarget, err := s.framePoseFunc(ctx, mapFrame, req.Frame)
+	if err != nil {
+		return nil, err
+	}
+	resp := dto.NewSemanticPlanResponse(id, snapshot.Version, plan)
+	resp.Frame = mapFrame
+	if toTarget != nil {
+		for _, w := range resp.Waypoints {
+			semantic.TransformElement(w, toTarget)
+		}
+		resp.Frame = req.Frame
+	}
+
+	logger.Info("path planned successfully in service", zap.Uint("id", id), zap.Int("waypoints", len(plan.Waypoints)), zap.Float64("cost", plan.Cost))
+	return resp, nil
+}
@@ -3,22 +3,33 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	dao "robot_scheduler/internal/dao/interfaces"
+	"robot_scheduler/internal/frame"
 	"robot_scheduler/internal/logger"
 	"robot_scheduler/internal/model/dto"
 	"robot_scheduler/internal/model/entity"
+	"robot_scheduler/internal/semantic"
 
 	"go.uber.org/zap"
 )
 
 // TaskService 任务服务
 type TaskService struct {
-	taskDAO dao.TaskDAO
+	taskDAO            dao.TaskDAO
+	semanticDAO        dao.SemanticMapDAO
+	semanticVersionDAO dao.SemanticMapVersionDAO
+	pcdDAO             dao.PCDFileDAO
+	frameService       *FrameService
 }
 
-func NewTaskService(taskDAO dao.TaskDAO) *TaskService {
+func NewTaskService(taskDAO dao.TaskDAO, semanticDAO dao.SemanticMapDAO, semanticVersionDAO dao.SemanticMapVersionDAO, pcdDAO dao.PCDFileDAO, frameService *FrameService) *TaskService {
 	return &TaskService{
-		taskDAO: taskDAO,
+		taskDAO:            taskDAO,
+		semanticDAO:        semanticDAO,
+		semanticVersionDAO: semanticVersionDAO,
+		pcdDAO:             pcdDAO,
+		frameService:       frameService,
 	}
 }
 
@@ -26,6 +37,11 @@ func NewTaskService(taskDAO dao.TaskDAO) *TaskService {
 func (s *TaskService) CreateTask(ctx context.Context, req *dto.TaskCreateRequest) (*dto.TaskResponse, error) {
 	logger.Info("creating task in service", zap.Uint("semanticMapID", req.SemanticMapID))
 
+	version, err := s.resolveSemanticMapVersion(ctx, req.SemanticMapID, req.SemanticMapVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	// 创建任务实体
 	task := &entity.Task{
 		SemanticMapID: req.SemanticMapID,
@@ -33,6 +49,8 @@ func (s *TaskService) CreateTask(ctx context.Context, req *dto.TaskCreateRequest
 		TaskInfo:      req.TaskInfo,
 		Status:        &[]entity.TaskStatus{entity.TaskStatusPending}[0],
 		ExtraInfo:     req.ExtraInfo,
+
+		SemanticMapVersion: version,
 	}
 
 	// 保存到数据库
@@ -61,6 +79,19 @@ func (s *TaskService) UpdateTask(ctx context.Context, id uint, req *dto.TaskUpda
 		return errors.New("task not found")
 	}
 
+	// 更换语义地图或指定版本时重新固定版本
+	if (req.SemanticMapID != nil && *req.SemanticMapID != task.SemanticMapID) || req.SemanticMapVersion != nil {
+		mapID := task.SemanticMapID
+		if req.SemanticMapID != nil {
+			mapID = *req.SemanticMapID
+		}
+		version, err := s.resolveSemanticMapVersion(ctx, mapID, req.SemanticMapVersion)
+		if err != nil {
+			return err
+		}
+		task.SemanticMapVersion = version
+	}
+
 	// 更新字段
 	if req.SemanticMapID != nil {
 		task.SemanticMapID = *req.SemanticMapID
@@ -88,15 +119,47 @@ func (s *TaskService) UpdateTask(ctx context.Context, id uint, req *dto.TaskUpda
 	return nil
 }
 
+// resolveSemanticMapVersion 确定任务固定使用的语义地图版本：指定版本时校验其存在，否则取地图的当前版本
+// 版本管理上线前创建的地图没有版本，此时先把其内容保存为第一个版本
+func (s *TaskService) resolveSemanticMapVersion(ctx context.Context, semanticMapID uint, requested *int) (*int, error) {
+	if requested != nil {
+		v, err := s.semanticVersionDAO.FindByVersion(ctx, semanticMapID, *requested)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			logger.Warn("semantic map version not found", zap.Uint("semanticMapID", semanticMapID), zap.Int("version", *requested))
+			return nil, fmt.Errorf("语义地图 %d 不存在版本 %d", semanticMapID, *requested)
+		}
+		return &v.Version, nil
+	}
+
+	semanticMap, err := s.semanticDAO.FindByID(ctx, semanticMapID)
+	if err != nil {
+		return nil, err
+	}
+	if semanticMap == nil {
+		logger.Warn("semantic map not found for task", zap.Uint("semanticMapID", semanticMapID))
+		return nil, errors.New("semantic map not found")
+	}
+	if semanticMap.CurrentVersion == nil {
+		if err := recordSemanticMapVersion(ctx, s.semanticVersionDAO, s.pcdDAO, semanticMap, semanticMap.UserName, nil); err != nil {
+			logger.Error("failed to save base semantic map version", zap.Error(err), zap.Uint("semanticMapID", semanticMapID))
+			return nil, err
+		}
+	}
+	return semanticMap.CurrentVersion, nil
+}
+
 // DeleteTask 删除任务
 func (s *TaskService) DeleteTask(ctx context.Context, id uint) error {
 	logger.Info("deleting task in service", zap.Uint("id", id))
 	return s.taskDAO.Delete(ctx, id)
 }
 
-// GetTaskByID 根据ID获取任务
-func (s *TaskService) GetTaskByID(ctx context.Context, id uint) (*dto.TaskResponse, error) {
-	logger.Debug("getting task by id in service", zap.Uint("id", id))
+// GetTaskByID 根据ID获取任务，frameName 不为空时返回任务固定版本的语义地图内容并换算到该坐标系
+func (s *TaskService) GetTaskByID(ctx context.Context, id uint, frameName string) (*dto.TaskResponse, error) {
+	logger.Debug("getting task by id in service", zap.Uint("id", id), zap.String("frame", frameName))
 	task, err := s.taskDAO.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -104,11 +167,53 @@ func (s *TaskService) GetTaskByID(ctx context.Context, id uint) (*dto.TaskRespon
 	if task == nil {
 		return nil, nil
 	}
-	return dto.NewTaskResponseFromEntity(task), nil
+
+	resp := dto.NewTaskResponseFromEntity(task)
+	if frameName == "" {
+		return resp, nil
+	}
+	graph, err := s.frameService.Graph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.pinnedInFrame(ctx, task, resp, graph, frameName); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// pinnedInFrame 用任务固定版本的语义信息与底图替换响应中语义地图的当前内容，并把坐标换算到 frameName；
+// 未固定版本或版本已不存在时使用语义地图的当前内容
+func (s *TaskService) pinnedInFrame(ctx context.Context, task *entity.Task, resp *dto.TaskResponse, graph *frame.Graph, frameName string) error {
+	content, pcdFileID, gridID := task.SemanticMap.SemanticInfo, task.SemanticMap.PCDFileID, task.SemanticMap.OccupancyGridID
+	if task.SemanticMapVersion != nil {
+		version, err := s.semanticVersionDAO.FindByVersion(ctx, task.SemanticMapID, *task.SemanticMapVersion)
+		if err != nil {
+			return err
+		}
+		if version != nil {
+			content, pcdFileID, gridID = version.SemanticInfo, version.PCDFileID, version.OccupancyGridID
+		}
+	}
+
+	info, err := semantic.Parse(content)
+	if err != nil {
+		return err
+	}
+	transform, err := graph.Resolve(frame.MapFrame(pcdFileID, gridID), frameName)
+	if err != nil {
+		return err
+	}
+	info.Transform(semanticPoseFunc(transform))
+
+	semanticMap := *resp.SemanticMap
+	semanticMap.SemanticInfo, semanticMap.PCDFileID, semanticMap.OccupancyGridID = info.String(), pcdFileID, gridID
+	resp.SemanticMap, resp.Frame = &semanticMap, frameName
+	return nil
 }
 
-// ListTasks 分页获取任务列表
-func (s *TaskService) ListTasks(ctx context.Context, req dto.PageRequest) (*dto.TaskListResponse, error) {
+// ListTasks 分页获取任务列表，可按任务所用语义地图的位置节点过滤
+func (s *TaskService) ListTasks(ctx context.Context, req dto.TaskListRequest) (*dto.TaskListResponse, error) {
 	logger.Debug("listing tasks in service with pagination", zap.Int("page", req.Page), zap.Int("pageSize", req.PageSize))
 
 	if req.Page <= 0 {
@@ -120,7 +225,7 @@ func (s *TaskService) ListTasks(ctx context.Context, req dto.PageRequest) (*dto.
 
 	offset := (req.Page - 1) * req.PageSize
 
-	tasks, total, err := s.taskDAO.FindPage(ctx, offset, req.PageSize)
+	tasks, total, err := s.taskDAO.FindPage(ctx, dao.TaskFilter{LocationID: req.LocationID}, offset, req.PageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -137,5 +242,18 @@ func (s *TaskService) ListTasks(ctx context.Context, req dto.PageRequest) (*dto.
 		Pages:    pages,
 	}
 
-	return dto.NewTaskListResponseFromEntities(tasks, page), nil
+	resp := dto.NewTaskListResponseFromEntities(tasks, page)
+	if req.Frame == "" {
+		return resp, nil
+	}
+	graph, err := s.frameService.Graph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, task := range tasks {
+		if err := s.pinnedInFrame(ctx, task, resp.List[i], graph, req.Frame); err != nil {
+			return nil, fmt.Errorf("任务 %d: %w", task.ID, err)
+		}
+	}
+	return resp, nil
 }
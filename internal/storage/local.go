@@ -0,0 +1,509 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalRoutePath 本地存储签名链接的路由前缀，由本服务提供 GET/PUT
+const LocalRoutePath = "/api/v1/storage/objects"
+
+const (
+	// localTmpDir 写入中的临时文件目录，写完后原子改名
+	localTmpDir = ".tmp"
+	// localMultipartDir 未完成的分片上传目录，每个上传ID一个子目录
+	localMultipartDir = ".multipart"
+	// localMaxParts 与 S3 一致的最大分片数
+	localMaxParts = 10000
+)
+
+var (
+	// ErrInvalidKey 对象 Key 不合法
+	ErrInvalidKey = errors.New("invalid object key")
+	// ErrInvalidSignature 签名链接无效或已过期
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrTooLarge 上传内容超过签名链接允许的大小
+	ErrTooLarge = errors.New("object too large")
+)
+
+// LocalConfig 本地存储参数
+type LocalConfig struct {
+	Root    string // 对象存放目录
+	Secret  string // 签名链接的 HMAC 密钥
+	BaseURL string // 签名链接的外部访问地址，为空时生成相对路径
+}
+
+// Local 基于本地磁盘的存储后端，适用于没有 MinIO 的站点
+//
+// 对象按 Key 存放在根目录下，签名链接指向本服务的 LocalRoutePath，
+// 以 HMAC-SHA256 对请求方法、Key 与查询参数签名，上传链接的查询参数包含允许的最大字节数
+type Local struct {
+	root    string
+	secret  []byte
+	baseURL string
+}
+
+func NewLocal(cfg LocalConfig) (*Local, error) {
+	if cfg.Secret == "" {
+		return nil, errors.New("local storage sign key not configured")
+	}
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range []string{root, filepath.Join(root, localTmpDir), filepath.Join(root, localMultipartDir)} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &Local{
+		root:    root,
+		secret:  []byte(cfg.Secret),
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+	}, nil
+}
+
+func (l *Local) Type() string {
+	return TypeLocal
+}
+
+func (l *Local) Bucket() string {
+	return ""
+}
+
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	target, err := l.objectPath(key)
+	if err != nil {
+		return err
+	}
+	return l.writeFile(target, r, size)
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error) {
+	target, err := l.objectPath(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	f, err := os.Open(target)
+	if err != nil {
+		return nil, ObjectInfo{}, localError(err)
+	}
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		f.Close()
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+	return f, localObjectInfo(key, fi), nil
+}
+
+func (l *Local) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	target, err := l.objectPath(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	fi, err := os.Stat(target)
+	if err != nil {
+		return ObjectInfo{}, localError(err)
+	}
+	if fi.IsDir() {
+		return ObjectInfo{}, ErrNotFound
+	}
+	return localObjectInfo(key, fi), nil
+}
+
+func (l *Local) Remove(ctx context.Context, key string) error {
+	target, err := l.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := make([]ObjectInfo, 0)
+	err := filepath.WalkDir(l.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// 跳过临时文件与分片目录
+			if p != l.root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, localObjectInfo(key, fi))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+func (l *Local) PresignGet(ctx context.Context, key string, expire time.Duration, opts GetURLOptions) (string, error) {
+	if _, err := l.objectPath(key); err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	if opts.ContentDisposition != "" {
+		params.Set("response-content-disposition", opts.ContentDisposition)
+	}
+	if opts.ContentType != "" {
+		params.Set("response-content-type", opts.ContentType)
+	}
+	return l.signURL("GET", key, expire, params), nil
+}
+
+func (l *Local) PresignPut(ctx context.Context, key string, expire time.Duration, maxSize int64) (string, error) {
+	if _, err := l.objectPath(key); err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("maxSize", strconv.FormatInt(maxSize, 10))
+	return l.signURL("PUT", key, expire, params), nil
+}
+
+func (l *Local) NewMultipart(ctx context.Context, key, contentType string) (string, error) {
+	if _, err := l.objectPath(key); err != nil {
+		return "", err
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	uploadID := hex.EncodeToString(b)
+	dir := filepath.Join(l.root, localMultipartDir, uploadID)
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return "", err
+	}
+	// 记录所属对象，防止用其他 Key 的签名链接写入
+	if err := os.WriteFile(filepath.Join(dir, "key"), []byte(key), 0o644); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return uploadID, nil
+}
+
+func (l *Local) PresignPart(ctx context.Context, key, uploadID string, partNumber int, expire time.Duration, maxSize int64) (string, error) {
+	if _, err := l.uploadDir(key, uploadID); err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("partNumber", strconv.Itoa(partNumber))
+	params.Set("uploadId", uploadID)
+	params.Set("maxSize", strconv.FormatInt(maxSize, 10))
+	return l.signURL("PUT", key, expire, params), nil
+}
+
+// PutPart 写入一个分片，同一分片重复上传时覆盖，返回分片 ETag
+func (l *Local) PutPart(ctx context.Context, key, uploadID string, partNumber int, r io.Reader, size int64) (string, error) {
+	dir, err := l.uploadDir(key, uploadID)
+	if err != nil {
+		return "", err
+	}
+	if partNumber < 1 || partNumber > localMaxParts {
+		return "", fmt.Errorf("invalid part number %d", partNumber)
+	}
+	target := filepath.Join(dir, partFileName(partNumber))
+	if err := l.writeFile(target, r, size); err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(target)
+	if err != nil {
+		return "", err
+	}
+	return fileETag(fi), nil
+}
+
+func (l *Local) ListParts(ctx context.Context, key, uploadID string) ([]Part, error) {
+	dir, err := l.uploadDir(key, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, localUploadError(err)
+	}
+	// 分片文件名按序号补零，ReadDir 的字典序即分片顺序
+	parts := make([]Part, 0, len(entries))
+	for _, e := range entries {
+		n, err := strconv.Atoi(e.Name())
+		if err != nil || e.IsDir() {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, Part{PartNumber: n, Size: fi.Size(), ETag: fileETag(fi), LastModified: fi.ModTime()})
+	}
+	return parts, nil
+}
+
+func (l *Local) CompleteMultipart(ctx context.Context, key, uploadID string, parts []Part) error {
+	dir, err := l.uploadDir(key, uploadID)
+	if err != nil {
+		return err
+	}
+	target, err := l.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range parts {
+		fi, err := os.Stat(filepath.Join(dir, partFileName(p.PartNumber)))
+		if err != nil {
+			return fmt.Errorf("part %d: %w", p.PartNumber, localUploadError(err))
+		}
+		if p.ETag != "" && strings.Trim(p.ETag, `"`) != fileETag(fi) {
+			return fmt.Errorf("part %d etag mismatch", p.PartNumber)
+		}
+	}
+	r := &partReader{dir: dir, parts: parts}
+	defer r.Close()
+	if err := l.writeFile(target, r, -1); err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (l *Local) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	dir, err := l.uploadDir(key, uploadID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// Verify 校验签名链接的方法、Key、查询参数与有效期
+func (l *Local) Verify(method, key string, query url.Values) error {
+	params := url.Values{}
+	for k, v := range query {
+		if k != "signature" {
+			params[k] = v
+		}
+	}
+	expires, err := strconv.ParseInt(params.Get("expires"), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(l.sign(method, key, params))) {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expires {
+		return fmt.Errorf("%w: link expired", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// LimitBody 按已校验的上传链接中的 maxSize 限制请求体：声明长度超限时直接返回 ErrTooLarge，
+// 未声明长度时读取超过 maxSize 返回 ErrTooLarge；链接缺少 maxSize 时返回 ErrInvalidSignature
+func (l *Local) LimitBody(query url.Values, body io.Reader, contentLength int64) (io.Reader, error) {
+	maxSize, err := strconv.ParseInt(query.Get("maxSize"), 10, 64)
+	if err != nil || maxSize < 0 {
+		return nil, ErrInvalidSignature
+	}
+	if contentLength > maxSize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds limit %d", ErrTooLarge, contentLength, maxSize)
+	}
+	return &limitedBody{r: io.LimitReader(body, maxSize+1), remaining: maxSize}, nil
+}
+
+// limitedBody 多读一个字节以区分恰好读满与超限
+type limitedBody struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
+
+// signURL 生成指向本服务的签名链接
+func (l *Local) signURL(method, key string, expire time.Duration, params url.Values) string {
+	params.Set("expires", strconv.FormatInt(time.Now().Add(expire).Unix(), 10))
+	params.Set("signature", l.sign(method, key, params))
+
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return l.baseURL + LocalRoutePath + "/" + strings.Join(segments, "/") + "?" + params.Encode()
+}
+
+// sign 签名内容为 方法\nKey\n按参数名排序的查询参数（不含 signature）
+func (l *Local) sign(method, key string, params url.Values) string {
+	unsigned := url.Values{}
+	for k, v := range params {
+		if k != "signature" {
+			unsigned[k] = v
+		}
+	}
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + unsigned.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// objectPath 校验 Key 并返回对象文件路径，Key 须为不含 . 与 .. 段的相对路径，且不能落在内部目录下
+func (l *Local) objectPath(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	for _, s := range strings.Split(key, "/") {
+		if s == "." || s == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	if strings.HasPrefix(key, ".") {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// uploadDir 返回分片上传目录，上传不存在或不属于该 Key 时返回 ErrNoSuchUpload
+func (l *Local) uploadDir(key, uploadID string) (string, error) {
+	if _, err := hex.DecodeString(uploadID); err != nil || uploadID == "" {
+		return "", ErrNoSuchUpload
+	}
+	dir := filepath.Join(l.root, localMultipartDir, uploadID)
+	owner, err := os.ReadFile(filepath.Join(dir, "key"))
+	if err != nil {
+		return "", localUploadError(err)
+	}
+	if string(owner) != key {
+		return "", ErrNoSuchUpload
+	}
+	return dir, nil
+}
+
+// writeFile 先写入临时文件再改名，读者不会看到写了一半的对象；size 为 -1 时不校验长度
+func (l *Local) writeFile(target string, r io.Reader, size int64) error {
+	tmp, err := os.CreateTemp(filepath.Join(l.root, localTmpDir), "put-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && written != size {
+		return fmt.Errorf("size mismatch: expected %d bytes, got %d", size, written)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// partReader 依次读取各分片文件，同一时刻只打开一个文件
+type partReader struct {
+	dir   string
+	parts []Part
+	cur   *os.File
+}
+
+func (r *partReader) Read(b []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if len(r.parts) == 0 {
+				return 0, io.EOF
+			}
+			f, err := os.Open(filepath.Join(r.dir, partFileName(r.parts[0].PartNumber)))
+			if err != nil {
+				return 0, err
+			}
+			r.cur, r.parts = f, r.parts[1:]
+		}
+		n, err := r.cur.Read(b)
+		if err == io.EOF {
+			r.cur.Close()
+			r.cur = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *partReader) Close() error {
+	if r.cur == nil {
+		return nil
+	}
+	return r.cur.Close()
+}
+
+func partFileName(partNumber int) string {
+	return fmt.Sprintf("%05d", partNumber)
+}
+
+// fileETag 本地文件的 ETag 取修改时间与大小，覆盖写入后必然变化
+func fileETag(fi fs.FileInfo) string {
+	return fmt.Sprintf("%x-%x", fi.ModTime().UnixNano(), fi.Size())
+}
+
+// localObjectInfo 本地存储不保存对象类型，下载时由签名链接的 response-content-type 指定
+func localObjectInfo(key string, fi fs.FileInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          key,
+		Size:         fi.Size(),
+		ETag:         fileETag(fi),
+		ContentType:  "application/octet-stream",
+		LastModified: fi.ModTime(),
+	}
+}
+
+func localError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func localUploadError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNoSuchUpload
+	}
+	return err
+}
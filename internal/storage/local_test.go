@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"robot_scheduler/internal/config"
+)
+
+func newTestLocal(t *testing.T) *Local {
+	t.Helper()
+	l, err := NewLocal(LocalConfig{Root: t.TempDir(), Secret: "secret", BaseURL: "http://host:8080/"})
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	return l
+}
+
+func TestLocal_PutGetListRemove(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := context.Background()
+
+	if err := l.Put(ctx, "pcd/alice/1_a.pcd", strings.NewReader("hello"), 5, ""); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := l.Put(ctx, "pcd/alice/2_b.pcd", strings.NewReader("hello"), 3, ""); err == nil {
+		t.Error("Expected size mismatch to be rejected")
+	}
+	if err := l.Put(ctx, "grid/1.pgm", strings.NewReader("P5"), -1, ""); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	object, info, err := l.Get(ctx, "pcd/alice/1_a.pcd")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	data, _ := io.ReadAll(object)
+	object.Close()
+	if string(data) != "hello" || info.Size != 5 || info.ETag == "" {
+		t.Errorf("Unexpected object %q %+v", data, info)
+	}
+
+	objects, err := l.List(ctx, "pcd/")
+	if err != nil || len(objects) != 1 || objects[0].Key != "pcd/alice/1_a.pcd" {
+		t.Fatalf("Expected only the pcd object, got %+v (%v)", objects, err)
+	}
+
+	if err := l.Remove(ctx, "pcd/alice/1_a.pcd"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := l.Remove(ctx, "pcd/alice/1_a.pcd"); err != nil {
+		t.Errorf("Expected removing a missing object to succeed, got %v", err)
+	}
+	if _, err := l.Stat(ctx, "pcd/alice/1_a.pcd"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "pcd/../../x", "./a", ".multipart/x", "a//b"} {
+		if err := l.Put(ctx, key, strings.NewReader(""), 0, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Expected key %q to be rejected, got %v", key, err)
+		}
+	}
+}
+
+func TestLocal_SignedURL(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := context.Background()
+
+	raw, err := l.PresignGet(ctx, "pcd/alice/1_地图.pcd", time.Minute, GetURLOptions{ContentType: "image/png"})
+	if err != nil {
+		t.Fatalf("PresignGet failed: %v", err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("invalid url %q: %v", raw, err)
+	}
+	if u.Host != "host:8080" || u.Path != LocalRoutePath+"/pcd/alice/1_地图.pcd" {
+		t.Errorf("Unexpected url %s", raw)
+	}
+	key := strings.TrimPrefix(u.Path, LocalRoutePath+"/")
+	if err := l.Verify("GET", key, u.Query()); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	// 方法、Key 或参数被修改时签名失效
+	if err := l.Verify("PUT", key, u.Query()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected method change to be rejected, got %v", err)
+	}
+	if err := l.Verify("GET", "pcd/bob/1_地图.pcd", u.Query()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected key change to be rejected, got %v", err)
+	}
+	tampered := u.Query()
+	tampered.Set("response-content-type", "text/html")
+	if err := l.Verify("GET", key, tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected tampered params to be rejected, got %v", err)
+	}
+
+	expired, _ := l.PresignPut(ctx, "pcd/alice/1_a.pcd", -time.Minute, 5)
+	u, _ = url.Parse(expired)
+	if err := l.Verify("PUT", "pcd/alice/1_a.pcd", u.Query()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected expired url to be rejected, got %v", err)
+	}
+}
+
+func TestLocal_LimitBody(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := context.Background()
+	key := "pcd/alice/1_a.pcd"
+
+	raw, err := l.PresignPut(ctx, key, time.Minute, 5)
+	if err != nil {
+		t.Fatalf("PresignPut failed: %v", err)
+	}
+	u, _ := url.Parse(raw)
+	query := u.Query()
+	if err := l.Verify("PUT", key, query); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	// 大小限制参与签名，不能被改大
+	tampered := u.Query()
+	tampered.Set("maxSize", "1000")
+	if err := l.Verify("PUT", key, tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected tampered maxSize to be rejected, got %v", err)
+	}
+
+	if _, err := l.LimitBody(query, strings.NewReader("hello world"), 11); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected declared length over limit to be rejected, got %v", err)
+	}
+
+	// 未声明长度时按实际读取的字节数限制
+	body, err := l.LimitBody(query, strings.NewReader("hello world"), -1)
+	if err != nil {
+		t.Fatalf("LimitBody failed: %v", err)
+	}
+	if err := l.Put(ctx, key, body, -1, ""); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected oversized body to be rejected, got %v", err)
+	}
+	if _, err := l.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected oversized object not to be stored, got %v", err)
+	}
+
+	body, _ = l.LimitBody(query, strings.NewReader("hello"), -1)
+	if err := l.Put(ctx, key, body, -1, ""); err != nil {
+		t.Errorf("Expected body within limit to be stored, got %v", err)
+	}
+
+	missing := url.Values{}
+	if _, err := l.LimitBody(missing, strings.NewReader(""), 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected url without maxSize to be rejected, got %v", err)
+	}
+}
+
+func TestInit_LocalRequiresSignKey(t *testing.T) {
+	defer SetBackend(nil)
+
+	cfg := &config.Config{
+		Storage: &config.StorageConfig{Type: TypeLocal, Local: &config.LocalStorageConfig{Root: t.TempDir()}},
+		Auth:    &config.AuthConfig{JWTSecret: "jwt"},
+	}
+	if err := Init(cfg); err == nil {
+		t.Fatal("Expected local storage without sign key to be refused")
+	}
+	cfg.Storage.Local.SignKey = "jwt"
+	if err := Init(cfg); err == nil {
+		t.Fatal("Expected sign key shared with jwt secret to be refused")
+	}
+	cfg.Storage.Local.SignKey = "storage-key"
+	if err := Init(cfg); err != nil || Backend() == nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+}
+
+func TestLocal_Multipart(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := context.Background()
+	key := "pcd/alice/1_big.pcd"
+
+	uploadID, err := l.NewMultipart(ctx, key, "application/octet-stream")
+	if err != nil {
+		t.Fatalf("NewMultipart failed: %v", err)
+	}
+	if _, err := l.PutPart(ctx, "pcd/bob/1_big.pcd", uploadID, 1, strings.NewReader("x"), 1); !errors.Is(err, ErrNoSuchUpload) {
+		t.Errorf("Expected upload of another key to be rejected, got %v", err)
+	}
+
+	// 乱序上传，重复上传的分片覆盖旧内容
+	for _, p := range []struct {
+		n    int
+		body string
+	}{{2, "world"}, {1, "xxxxx"}, {1, "hello "}} {
+		if _, err := l.PutPart(ctx, key, uploadID, p.n, strings.NewReader(p.body), int64(len(p.body))); err != nil {
+			t.Fatalf("PutPart %d failed: %v", p.n, err)
+		}
+	}
+
+	parts, err := l.ListParts(ctx, key, uploadID)
+	if err != nil || len(parts) != 2 || parts[0].PartNumber != 1 || parts[1].PartNumber != 2 {
+		t.Fatalf("Expected parts 1 and 2, got %+v (%v)", parts, err)
+	}
+	if err := l.CompleteMultipart(ctx, key, uploadID, []Part{{PartNumber: 1, ETag: "stale"}, parts[1]}); err == nil {
+		t.Error("Expected etag mismatch to be rejected")
+	}
+	if err := l.CompleteMultipart(ctx, key, uploadID, parts); err != nil {
+		t.Fatalf("CompleteMultipart failed: %v", err)
+	}
+
+	object, _, err := l.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer object.Close()
+	var buf bytes.Buffer
+	io.Copy(&buf, object)
+	if buf.String() != "hello world" {
+		t.Errorf("Expected assembled object, got %q", buf.String())
+	}
+
+	// 合并后上传不再存在
+	if _, err := l.ListParts(ctx, key, uploadID); !errors.Is(err, ErrNoSuchUpload) {
+		t.Errorf("Expected ErrNoSuchUpload after complete, got %v", err)
+	}
+	if err := l.AbortMultipart(ctx, key, uploadID); !errors.Is(err, ErrNoSuchUpload) {
+		t.Errorf("Expected ErrNoSuchUpload on abort, got %v", err)
+	}
+	if err := l.AbortMultipart(ctx, key, "../../etc"); !errors.Is(err, ErrNoSuchUpload) {
+		t.Errorf("Expected invalid upload id to be rejected, got %v", err)
+	}
+}
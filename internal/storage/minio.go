@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Minio 基于 MinIO（S3 兼容）的存储后端
+type Minio struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewMinio(client *minio.Client, bucket string) *Minio {
+	return &Minio{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+func (m *Minio) Type() string {
+	return TypeMinio
+}
+
+func (m *Minio) Bucket() string {
+	return m.bucket
+}
+
+func (m *Minio) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := m.client.PutObject(ctx, m.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (m *Minio) Get(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error) {
+	object, err := m.client.GetObject(ctx, m.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, minioError(err)
+	}
+	// GetObject 延迟到首次读取才发起请求，先 Stat 确认对象存在
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+		return nil, ObjectInfo{}, minioError(err)
+	}
+	return object, minioObjectInfo(info), nil
+}
+
+func (m *Minio) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	info, err := m.client.StatObject(ctx, m.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, minioError(err)
+	}
+	return minioObjectInfo(info), nil
+}
+
+func (m *Minio) Remove(ctx context.Context, key string) error {
+	return m.client.RemoveObject(ctx, m.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (m *Minio) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := make([]ObjectInfo, 0)
+	for object := range m.client.ListObjects(ctx, m.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		objects = append(objects, minioObjectInfo(object))
+	}
+	return objects, nil
+}
+
+func (m *Minio) PresignGet(ctx context.Context, key string, expire time.Duration, opts GetURLOptions) (string, error) {
+	params := url.Values{}
+	if opts.ContentDisposition != "" {
+		params.Set("response-content-disposition", opts.ContentDisposition)
+	}
+	if opts.ContentType != "" {
+		params.Set("response-content-type", opts.ContentType)
+	}
+	u, err := m.client.PresignedGetObject(ctx, m.bucket, key, expire, params)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (m *Minio) PresignPut(ctx context.Context, key string, expire time.Duration, maxSize int64) (string, error) {
+	u, err := m.client.PresignedPutObject(ctx, m.bucket, key, expire)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (m *Minio) NewMultipart(ctx context.Context, key, contentType string) (string, error) {
+	core := minio.Core{Client: m.client}
+	return core.NewMultipartUpload(ctx, m.bucket, key, minio.PutObjectOptions{ContentType: contentType})
+}
+
+func (m *Minio) PresignPart(ctx context.Context, key, uploadID string, partNumber int, expire time.Duration, maxSize int64) (string, error) {
+	params := url.Values{}
+	params.Set("partNumber", strconv.Itoa(partNumber))
+	params.Set("uploadId", uploadID)
+	u, err := m.client.Presign(ctx, "PUT", m.bucket, key, expire, params)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (m *Minio) ListParts(ctx context.Context, key, uploadID string) ([]Part, error) {
+	core := minio.Core{Client: m.client}
+	parts := make([]Part, 0)
+	marker := 0
+	for {
+		result, err := core.ListObjectParts(ctx, m.bucket, key, uploadID, marker, 1000)
+		if err != nil {
+			return nil, minioError(err)
+		}
+		for _, p := range result.ObjectParts {
+			parts = append(parts, Part{PartNumber: p.PartNumber, Size: p.Size, ETag: p.ETag, LastModified: p.LastModified})
+		}
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+func (m *Minio) CompleteMultipart(ctx context.Context, key, uploadID string, parts []Part) error {
+	complete := make([]minio.CompletePart, 0, len(parts))
+	for _, p := range parts {
+		complete = append(complete, minio.CompletePart{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+	core := minio.Core{Client: m.client}
+	_, err := core.CompleteMultipartUpload(ctx, m.bucket, key, uploadID, complete, minio.PutObjectOptions{})
+	return minioError(err)
+}
+
+func (m *Minio) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	core := minio.Core{Client: m.client}
+	return minioError(core.AbortMultipartUpload(ctx, m.bucket, key, uploadID))
+}
+
+// minioError 将 MinIO 的错误码转换为存储层的错误
+func minioError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey":
+		return ErrNotFound
+	case "NoSuchUpload":
+		return ErrNoSuchUpload
+	}
+	return err
+}
+
+func minioObjectInfo(info minio.ObjectInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		ContentType:  info.ContentType,
+		LastModified: info.LastModified,
+	}
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"robot_scheduler/internal/config"
+	"robot_scheduler/internal/minio_client"
+)
+
+// 存储后端类型
+const (
+	TypeMinio = "minio"
+	TypeLocal = "local"
+)
+
+var (
+	// ErrNotFound 对象不存在
+	ErrNotFound = errors.New("object not found")
+	// ErrNoSuchUpload 分片上传不存在，已合并或已放弃
+	ErrNoSuchUpload = errors.New("multipart upload not found")
+)
+
+// ObjectInfo 对象元信息
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	ContentType  string
+	LastModified time.Time
+}
+
+// Part 已上传的分片
+type Part struct {
+	PartNumber   int
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// GetURLOptions 下载链接附带的响应头
+type GetURLOptions struct {
+	ContentDisposition string
+	ContentType        string
+}
+
+// Storage 对象存储后端
+//
+// 预签名链接交给客户端直接上传下载；MinIO 由 MinIO 服务签发，本地存储由本服务签发并提供访问
+type Storage interface {
+	// Type 存储后端类型
+	Type() string
+	// Bucket 对象所在的 Bucket，本地存储返回空
+	Bucket() string
+
+	// Put 写入对象，已存在时覆盖
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 打开对象，调用方负责关闭；对象不存在时返回 ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error)
+	// Stat 查询对象元信息，对象不存在时返回 ErrNotFound
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Remove 删除对象，对象不存在时同样返回成功
+	Remove(ctx context.Context, key string) error
+	// List 列出 Key 以 prefix 开头的全部对象
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+
+	// PresignGet 生成下载链接
+	PresignGet(ctx context.Context, key string, expire time.Duration, opts GetURLOptions) (string, error)
+	// PresignPut 生成上传链接，maxSize 为允许上传的最大字节数；
+	// MinIO 的预签名 PUT 无法限制大小，由完成上传时按声明大小校验
+	PresignPut(ctx context.Context, key string, expire time.Duration, maxSize int64) (string, error)
+
+	// NewMultipart 初始化分片上传，返回上传ID
+	NewMultipart(ctx context.Context, key, contentType string) (string, error)
+	// PresignPart 生成分片上传链接，maxSize 含义同 PresignPut
+	PresignPart(ctx context.Context, key, uploadID string, partNumber int, expire time.Duration, maxSize int64) (string, error)
+	// ListParts 按分片序号升序列出已上传的分片，上传不存在时返回 ErrNoSuchUpload
+	ListParts(ctx context.Context, key, uploadID string) ([]Part, error)
+	// CompleteMultipart 按给定顺序合并分片
+	CompleteMultipart(ctx context.Context, key, uploadID string, parts []Part) error
+	// AbortMultipart 放弃分片上传并删除已上传的分片，上传不存在时返回 ErrNoSuchUpload
+	AbortMultipart(ctx context.Context, key, uploadID string) error
+}
+
+var backend Storage
+
+// Init 按配置初始化全局存储后端，未配置任何后端时不初始化
+func Init(cfg *config.Config) error {
+	switch t := backendType(cfg); t {
+	case "":
+		return nil
+	case TypeMinio:
+		if cfg.Minio == nil {
+			return errors.New("minio config is missing")
+		}
+		if err := minio_client.Init(cfg.Minio); err != nil {
+			return err
+		}
+		if minio_client.Client() == nil {
+			return errors.New("minio is not enabled")
+		}
+		backend = NewMinio(minio_client.Client(), cfg.Minio.BucketName)
+	case TypeLocal:
+		// 签名密钥泄露即可伪造任意对象的读写链接，不与登录令牌共用
+		if cfg.Storage == nil || cfg.Storage.Local == nil || cfg.Storage.Local.SignKey == "" {
+			return errors.New("storage.local.sign_key is required for local storage")
+		}
+		if cfg.Auth != nil && cfg.Storage.Local.SignKey == cfg.Auth.JWTSecret {
+			return errors.New("storage.local.sign_key must differ from auth.jwt_secret")
+		}
+		local, err := NewLocal(localConfig(cfg))
+		if err != nil {
+			return err
+		}
+		backend = local
+	default:
+		return fmt.Errorf("unknown storage type %q", t)
+	}
+	return nil
+}
+
+// Backend 获取全局存储后端，未初始化时返回 nil
+func Backend() Storage {
+	return backend
+}
+
+// SetBackend 替换全局存储后端
+func SetBackend(s Storage) {
+	backend = s
+}
+
+// backendType 确定存储后端：显式配置优先，其次嵌入式平台使用本地存储，最后按 minio.enabled
+func backendType(cfg *config.Config) string {
+	if cfg == nil {
+		return ""
+	}
+	if cfg.Storage != nil && cfg.Storage.Type != "" {
+		return cfg.Storage.Type
+	}
+	if cfg.Platform != nil && cfg.Platform.Type == "embedded" {
+		return TypeLocal
+	}
+	if cfg.Minio != nil && cfg.Minio.Enabled {
+		return TypeMinio
+	}
+	return ""
+}
+
+// localConfig 补全本地存储配置的默认值
+func localConfig(cfg *config.Config) LocalConfig {
+	local := LocalConfig{Root: "./data/objects"}
+	if cfg.Storage != nil && cfg.Storage.Local != nil {
+		if cfg.Storage.Local.Root != "" {
+			local.Root = cfg.Storage.Local.Root
+		}
+		local.Secret = cfg.Storage.Local.SignKey
+		local.BaseURL = cfg.Storage.Local.BaseURL
+	}
+	return local
+}
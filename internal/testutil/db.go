@@ -37,6 +37,19 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		&entity.SemanticMap{},
 		&entity.Task{},
 		&entity.UserOperation{},
+		&entity.DeviceEnrollmentCode{},
+		&entity.DeviceCredential{},
+		&entity.DeviceTelemetry{},
+		&entity.DeviceAlarm{},
+		&entity.DeviceModel{},
+		&entity.PCDUpload{},
+		&entity.PCDJob{},
+		&entity.OccupancyGrid{},
+		&entity.PCDFileVersion{},
+		&entity.SemanticMapVersion{},
+		&entity.FrameTransform{},
+		&entity.Location{},
+		&entity.MapDeployment{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
@@ -68,7 +68,7 @@ func CreateTestSemanticMap(t *testing.T, db *gorm.DB, pcdFileID uint) *entity.Se
 	t.Helper()
 
 	semanticMap := &entity.SemanticMap{
-		PCDFileID:    pcdFileID,
+		PCDFileID:    &pcdFileID,
 		UserName:     "test_user",
 		SemanticInfo: "test_semantic_info",
 	}
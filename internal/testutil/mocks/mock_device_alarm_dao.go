@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/device_alarm.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/device_alarm.go -destination=internal/testutil/mocks/mock_device_alarm_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	dao "robot_scheduler/internal/dao/interfaces"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeviceAlarmDAO is a mock of DeviceAlarmDAO interface.
+type MockDeviceAlarmDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceAlarmDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceAlarmDAOMockRecorder is the mock recorder for MockDeviceAlarmDAO.
+type MockDeviceAlarmDAOMockRecorder struct {
+	mock *MockDeviceAlarmDAO
+}
+
+// NewMockDeviceAlarmDAO creates a new mock instance.
+func NewMockDeviceAlarmDAO(ctrl *gomock.Controller) *MockDeviceAlarmDAO {
+	mock := &MockDeviceAlarmDAO{ctrl: ctrl}
+	mock.recorder = &MockDeviceAlarmDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceAlarmDAO) EXPECT() *MockDeviceAlarmDAOMockRecorder {
+	return m.recorder
+}
+
+// CountActiveBySeverity mocks base method.
+func (m *MockDeviceAlarmDAO) CountActiveBySeverity(ctx context.Context, deviceID uint, severity entity.AlarmSeverity) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveBySeverity", ctx, deviceID, severity)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveBySeverity indicates an expected call of CountActiveBySeverity.
+func (mr *MockDeviceAlarmDAOMockRecorder) CountActiveBySeverity(ctx, deviceID, severity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveBySeverity", reflect.TypeOf((*MockDeviceAlarmDAO)(nil).CountActiveBySeverity), ctx, deviceID, severity)
+}
+
+// Create mocks base method.
+func (m *MockDeviceAlarmDAO) Create(ctx context.Context, alarm *entity.DeviceAlarm) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, alarm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeviceAlarmDAOMockRecorder) Create(ctx, alarm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceAlarmDAO)(nil).Create), ctx, alarm)
+}
+
+// FindActiveByDeviceAndCode mocks base method.
+func (m *MockDeviceAlarmDAO) FindActiveByDeviceAndCode(ctx context.Context, deviceID uint, code string) (*entity.DeviceAlarm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveByDeviceAndCode", ctx, deviceID, code)
+	ret0, _ := ret[0].(*entity.DeviceAlarm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActiveByDeviceAndCode indicates an expected call of FindActiveByDeviceAndCode.
+func (mr *MockDeviceAlarmDAOMockRecorder) FindActiveByDeviceAndCode(ctx, deviceID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveByDeviceAndCode", reflect.TypeOf((*MockDeviceAlarmDAO)(nil).FindActiveByDeviceAndCode), ctx, deviceID, code)
+}
+
+// FindByID mocks base method.
+func (m *MockDeviceAlarmDAO) FindByID(ctx context.Context, id uint) (*entity.DeviceAlarm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.DeviceAlarm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockDeviceAlarmDAOMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDeviceAlarmDAO)(nil).FindByID), ctx, id)
+}
+
+// FindPage mocks base method.
+func (m *MockDeviceAlarmDAO) FindPage(ctx context.Context, filter dao.DeviceAlarmFilter, offset, limit int) ([]*entity.DeviceAlarm, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, filter, offset, limit)
+	ret0, _ := ret[0].([]*entity.DeviceAlarm)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockDeviceAlarmDAOMockRecorder) FindPage(ctx, filter, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockDeviceAlarmDAO)(nil).FindPage), ctx, filter, offset, limit)
+}
+
+// Update mocks base method.
+func (m *MockDeviceAlarmDAO) Update(ctx context.Context, alarm *entity.DeviceAlarm) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, alarm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeviceAlarmDAOMockRecorder) Update(ctx, alarm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeviceAlarmDAO)(nil).Update), ctx, alarm)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/device_credential.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/device_credential.go -destination=internal/testutil/mocks/mock_device_credential_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeviceCredentialDAO is a mock of DeviceCredentialDAO interface.
+type MockDeviceCredentialDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceCredentialDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceCredentialDAOMockRecorder is the mock recorder for MockDeviceCredentialDAO.
+type MockDeviceCredentialDAOMockRecorder struct {
+	mock *MockDeviceCredentialDAO
+}
+
+// NewMockDeviceCredentialDAO creates a new mock instance.
+func NewMockDeviceCredentialDAO(ctrl *gomock.Controller) *MockDeviceCredentialDAO {
+	mock := &MockDeviceCredentialDAO{ctrl: ctrl}
+	mock.recorder = &MockDeviceCredentialDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceCredentialDAO) EXPECT() *MockDeviceCredentialDAOMockRecorder {
+	return m.recorder
+}
+
+// FindActiveByDeviceID mocks base method.
+func (m *MockDeviceCredentialDAO) FindActiveByDeviceID(ctx context.Context, deviceID uint) ([]*entity.DeviceCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveByDeviceID", ctx, deviceID)
+	ret0, _ := ret[0].([]*entity.DeviceCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActiveByDeviceID indicates an expected call of FindActiveByDeviceID.
+func (mr *MockDeviceCredentialDAOMockRecorder) FindActiveByDeviceID(ctx, deviceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveByDeviceID", reflect.TypeOf((*MockDeviceCredentialDAO)(nil).FindActiveByDeviceID), ctx, deviceID)
+}
+
+// FindByTokenHash mocks base method.
+func (m *MockDeviceCredentialDAO) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.DeviceCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*entity.DeviceCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTokenHash indicates an expected call of FindByTokenHash.
+func (mr *MockDeviceCredentialDAOMockRecorder) FindByTokenHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTokenHash", reflect.TypeOf((*MockDeviceCredentialDAO)(nil).FindByTokenHash), ctx, tokenHash)
+}
+
+// RevokeByDeviceID mocks base method.
+func (m *MockDeviceCredentialDAO) RevokeByDeviceID(ctx context.Context, deviceID uint, revokedBy string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeByDeviceID", ctx, deviceID, revokedBy)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeByDeviceID indicates an expected call of RevokeByDeviceID.
+func (mr *MockDeviceCredentialDAOMockRecorder) RevokeByDeviceID(ctx, deviceID, revokedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeByDeviceID", reflect.TypeOf((*MockDeviceCredentialDAO)(nil).RevokeByDeviceID), ctx, deviceID, revokedBy)
+}
+
+// TouchLastUsed mocks base method.
+func (m *MockDeviceCredentialDAO) TouchLastUsed(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastUsed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastUsed indicates an expected call of TouchLastUsed.
+func (mr *MockDeviceCredentialDAOMockRecorder) TouchLastUsed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastUsed", reflect.TypeOf((*MockDeviceCredentialDAO)(nil).TouchLastUsed), ctx, id)
+}
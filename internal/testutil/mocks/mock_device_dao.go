@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	dao "robot_scheduler/internal/dao/interfaces"
 	entity "robot_scheduler/internal/model/entity"
 
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,21 @@ func (m *MockDeviceDAO) EXPECT() *MockDeviceDAOMockRecorder {
 	return m.recorder
 }
 
+// CountByModelID mocks base method.
+func (m *MockDeviceDAO) CountByModelID(ctx context.Context, modelID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByModelID", ctx, modelID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByModelID indicates an expected call of CountByModelID.
+func (mr *MockDeviceDAOMockRecorder) CountByModelID(ctx, modelID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByModelID", reflect.TypeOf((*MockDeviceDAO)(nil).CountByModelID), ctx, modelID)
+}
+
 // Create mocks base method.
 func (m *MockDeviceDAO) Create(ctx context.Context, device *entity.Device) error {
 	m.ctrl.T.Helper()
@@ -55,6 +71,20 @@ func (mr *MockDeviceDAOMockRecorder) Create(ctx, device any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceDAO)(nil).Create), ctx, device)
 }
 
+// CreateBatch mocks base method.
+func (m *MockDeviceDAO) CreateBatch(ctx context.Context, devices []*entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, devices)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockDeviceDAOMockRecorder) CreateBatch(ctx, devices any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockDeviceDAO)(nil).CreateBatch), ctx, devices)
+}
+
 // Delete mocks base method.
 func (m *MockDeviceDAO) Delete(ctx context.Context, id uint) error {
 	m.ctrl.T.Helper()
@@ -99,10 +129,25 @@ func (mr *MockDeviceDAOMockRecorder) FindByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDeviceDAO)(nil).FindByID), ctx, id)
 }
 
+// FindBySerialNumber mocks base method.
+func (m *MockDeviceDAO) FindBySerialNumber(ctx context.Context, serialNumber string) (*entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySerialNumber", ctx, serialNumber)
+	ret0, _ := ret[0].(*entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySerialNumber indicates an expected call of FindBySerialNumber.
+func (mr *MockDeviceDAOMockRecorder) FindBySerialNumber(ctx, serialNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySerialNumber", reflect.TypeOf((*MockDeviceDAO)(nil).FindBySerialNumber), ctx, serialNumber)
+}
+
 // FindPage mocks base method.
-func (m *MockDeviceDAO) FindPage(ctx context.Context, offset, limit int) ([]*entity.Device, int64, error) {
+func (m *MockDeviceDAO) FindPage(ctx context.Context, filter dao.DeviceFilter, offset, limit int) ([]*entity.Device, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindPage", ctx, offset, limit)
+	ret := m.ctrl.Call(m, "FindPage", ctx, filter, offset, limit)
 	ret0, _ := ret[0].([]*entity.Device)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -110,9 +155,9 @@ func (m *MockDeviceDAO) FindPage(ctx context.Context, offset, limit int) ([]*ent
 }
 
 // FindPage indicates an expected call of FindPage.
-func (mr *MockDeviceDAOMockRecorder) FindPage(ctx, offset, limit any) *gomock.Call {
+func (mr *MockDeviceDAOMockRecorder) FindPage(ctx, filter, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockDeviceDAO)(nil).FindPage), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockDeviceDAO)(nil).FindPage), ctx, filter, offset, limit)
 }
 
 // Update mocks base method.
@@ -128,3 +173,17 @@ func (mr *MockDeviceDAOMockRecorder) Update(ctx, device any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeviceDAO)(nil).Update), ctx, device)
 }
+
+// UpdateLoadedMap mocks base method.
+func (m *MockDeviceDAO) UpdateLoadedMap(ctx context.Context, device *entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoadedMap", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLoadedMap indicates an expected call of UpdateLoadedMap.
+func (mr *MockDeviceDAOMockRecorder) UpdateLoadedMap(ctx, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadedMap", reflect.TypeOf((*MockDeviceDAO)(nil).UpdateLoadedMap), ctx, device)
+}
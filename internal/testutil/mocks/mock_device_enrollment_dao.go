@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/device_enrollment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/device_enrollment.go -destination=internal/testutil/mocks/mock_device_enrollment_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeviceEnrollmentDAO is a mock of DeviceEnrollmentDAO interface.
+type MockDeviceEnrollmentDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceEnrollmentDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceEnrollmentDAOMockRecorder is the mock recorder for MockDeviceEnrollmentDAO.
+type MockDeviceEnrollmentDAOMockRecorder struct {
+	mock *MockDeviceEnrollmentDAO
+}
+
+// NewMockDeviceEnrollmentDAO creates a new mock instance.
+func NewMockDeviceEnrollmentDAO(ctrl *gomock.Controller) *MockDeviceEnrollmentDAO {
+	mock := &MockDeviceEnrollmentDAO{ctrl: ctrl}
+	mock.recorder = &MockDeviceEnrollmentDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceEnrollmentDAO) EXPECT() *MockDeviceEnrollmentDAOMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeviceEnrollmentDAO) Create(ctx context.Context, code *entity.DeviceEnrollmentCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeviceEnrollmentDAOMockRecorder) Create(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceEnrollmentDAO)(nil).Create), ctx, code)
+}
+
+// FindByCodeHash mocks base method.
+func (m *MockDeviceEnrollmentDAO) FindByCodeHash(ctx context.Context, codeHash string) (*entity.DeviceEnrollmentCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCodeHash", ctx, codeHash)
+	ret0, _ := ret[0].(*entity.DeviceEnrollmentCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCodeHash indicates an expected call of FindByCodeHash.
+func (mr *MockDeviceEnrollmentDAOMockRecorder) FindByCodeHash(ctx, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCodeHash", reflect.TypeOf((*MockDeviceEnrollmentDAO)(nil).FindByCodeHash), ctx, codeHash)
+}
+
+// Redeem mocks base method.
+func (m *MockDeviceEnrollmentDAO) Redeem(ctx context.Context, code *entity.DeviceEnrollmentCode, device *entity.Device, credential *entity.DeviceCredential) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, code, device, credential)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockDeviceEnrollmentDAOMockRecorder) Redeem(ctx, code, device, credential any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockDeviceEnrollmentDAO)(nil).Redeem), ctx, code, device, credential)
+}
@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/device_model.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/device_model.go -destination=internal/testutil/mocks/mock_device_model_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	dao "robot_scheduler/internal/dao/interfaces"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeviceModelDAO is a mock of DeviceModelDAO interface.
+type MockDeviceModelDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceModelDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceModelDAOMockRecorder is the mock recorder for MockDeviceModelDAO.
+type MockDeviceModelDAOMockRecorder struct {
+	mock *MockDeviceModelDAO
+}
+
+// NewMockDeviceModelDAO creates a new mock instance.
+func NewMockDeviceModelDAO(ctrl *gomock.Controller) *MockDeviceModelDAO {
+	mock := &MockDeviceModelDAO{ctrl: ctrl}
+	mock.recorder = &MockDeviceModelDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceModelDAO) EXPECT() *MockDeviceModelDAOMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeviceModelDAO) Create(ctx context.Context, model *entity.DeviceModel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeviceModelDAOMockRecorder) Create(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceModelDAO)(nil).Create), ctx, model)
+}
+
+// Delete mocks base method.
+func (m *MockDeviceModelDAO) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeviceModelDAOMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeviceModelDAO)(nil).Delete), ctx, id)
+}
+
+// FindAll mocks base method.
+func (m *MockDeviceModelDAO) FindAll(ctx context.Context) ([]*entity.DeviceModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]*entity.DeviceModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockDeviceModelDAOMockRecorder) FindAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockDeviceModelDAO)(nil).FindAll), ctx)
+}
+
+// FindByCompanyAndName mocks base method.
+func (m *MockDeviceModelDAO) FindByCompanyAndName(ctx context.Context, company entity.CompanyType, name string) (*entity.DeviceModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCompanyAndName", ctx, company, name)
+	ret0, _ := ret[0].(*entity.DeviceModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCompanyAndName indicates an expected call of FindByCompanyAndName.
+func (mr *MockDeviceModelDAOMockRecorder) FindByCompanyAndName(ctx, company, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCompanyAndName", reflect.TypeOf((*MockDeviceModelDAO)(nil).FindByCompanyAndName), ctx, company, name)
+}
+
+// FindByID mocks base method.
+func (m *MockDeviceModelDAO) FindByID(ctx context.Context, id uint) (*entity.DeviceModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.DeviceModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockDeviceModelDAOMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDeviceModelDAO)(nil).FindByID), ctx, id)
+}
+
+// FindPage mocks base method.
+func (m *MockDeviceModelDAO) FindPage(ctx context.Context, filter dao.DeviceModelFilter, offset, limit int) ([]*entity.DeviceModel, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, filter, offset, limit)
+	ret0, _ := ret[0].([]*entity.DeviceModel)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockDeviceModelDAOMockRecorder) FindPage(ctx, filter, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockDeviceModelDAO)(nil).FindPage), ctx, filter, offset, limit)
+}
+
+// Update mocks base method.
+func (m *MockDeviceModelDAO) Update(ctx context.Context, model *entity.DeviceModel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeviceModelDAOMockRecorder) Update(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeviceModelDAO)(nil).Update), ctx, model)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/device_telemetry.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/device_telemetry.go -destination=internal/testutil/mocks/mock_device_telemetry_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeviceTelemetryDAO is a mock of DeviceTelemetryDAO interface.
+type MockDeviceTelemetryDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceTelemetryDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceTelemetryDAOMockRecorder is the mock recorder for MockDeviceTelemetryDAO.
+type MockDeviceTelemetryDAOMockRecorder struct {
+	mock *MockDeviceTelemetryDAO
+}
+
+// NewMockDeviceTelemetryDAO creates a new mock instance.
+func NewMockDeviceTelemetryDAO(ctrl *gomock.Controller) *MockDeviceTelemetryDAO {
+	mock := &MockDeviceTelemetryDAO{ctrl: ctrl}
+	mock.recorder = &MockDeviceTelemetryDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceTelemetryDAO) EXPECT() *MockDeviceTelemetryDAOMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeviceTelemetryDAO) Create(ctx context.Context, telemetry *entity.DeviceTelemetry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, telemetry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeviceTelemetryDAOMockRecorder) Create(ctx, telemetry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceTelemetryDAO)(nil).Create), ctx, telemetry)
+}
+
+// FindLatestByDeviceID mocks base method.
+func (m *MockDeviceTelemetryDAO) FindLatestByDeviceID(ctx context.Context, deviceID uint) (*entity.DeviceTelemetry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLatestByDeviceID", ctx, deviceID)
+	ret0, _ := ret[0].(*entity.DeviceTelemetry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLatestByDeviceID indicates an expected call of FindLatestByDeviceID.
+func (mr *MockDeviceTelemetryDAOMockRecorder) FindLatestByDeviceID(ctx, deviceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatestByDeviceID", reflect.TypeOf((*MockDeviceTelemetryDAO)(nil).FindLatestByDeviceID), ctx, deviceID)
+}
+
+// FindPageByDeviceID mocks base method.
+func (m *MockDeviceTelemetryDAO) FindPageByDeviceID(ctx context.Context, deviceID uint, offset, limit int) ([]*entity.DeviceTelemetry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPageByDeviceID", ctx, deviceID, offset, limit)
+	ret0, _ := ret[0].([]*entity.DeviceTelemetry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPageByDeviceID indicates an expected call of FindPageByDeviceID.
+func (mr *MockDeviceTelemetryDAOMockRecorder) FindPageByDeviceID(ctx, deviceID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPageByDeviceID", reflect.TypeOf((*MockDeviceTelemetryDAO)(nil).FindPageByDeviceID), ctx, deviceID, offset, limit)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/frame_transform.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/frame_transform.go -destination=internal/testutil/mocks/mock_frame_transform_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFrameTransformDAO is a mock of FrameTransformDAO interface.
+type MockFrameTransformDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockFrameTransformDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockFrameTransformDAOMockRecorder is the mock recorder for MockFrameTransformDAO.
+type MockFrameTransformDAOMockRecorder struct {
+	mock *MockFrameTransformDAO
+}
+
+// NewMockFrameTransformDAO creates a new mock instance.
+func NewMockFrameTransformDAO(ctrl *gomock.Controller) *MockFrameTransformDAO {
+	mock := &MockFrameTransformDAO{ctrl: ctrl}
+	mock.recorder = &MockFrameTransformDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFrameTransformDAO) EXPECT() *MockFrameTransformDAOMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFrameTransformDAO) Create(ctx context.Context, transform *entity.FrameTransform) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transform)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFrameTransformDAOMockRecorder) Create(ctx, transform any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFrameTransformDAO)(nil).Create), ctx, transform)
+}
+
+// Delete mocks base method.
+func (m *MockFrameTransformDAO) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFrameTransformDAOMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFrameTransformDAO)(nil).Delete), ctx, id)
+}
+
+// FindAll mocks base method.
+func (m *MockFrameTransformDAO) FindAll(ctx context.Context, frame string) ([]*entity.FrameTransform, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, frame)
+	ret0, _ := ret[0].([]*entity.FrameTransform)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockFrameTransformDAOMockRecorder) FindAll(ctx, frame any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockFrameTransformDAO)(nil).FindAll), ctx, frame)
+}
+
+// FindByID mocks base method.
+func (m *MockFrameTransformDAO) FindByID(ctx context.Context, id uint) (*entity.FrameTransform, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.FrameTransform)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockFrameTransformDAOMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockFrameTransformDAO)(nil).FindByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockFrameTransformDAO) Update(ctx context.Context, transform *entity.FrameTransform) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, transform)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFrameTransformDAOMockRecorder) Update(ctx, transform any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFrameTransformDAO)(nil).Update), ctx, transform)
+}
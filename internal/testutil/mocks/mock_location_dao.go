@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/location.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/location.go -destination=internal/testutil/mocks/mock_location_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLocationDAO is a mock of LocationDAO interface.
+type MockLocationDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocationDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockLocationDAOMockRecorder is the mock recorder for MockLocationDAO.
+type MockLocationDAOMockRecorder struct {
+	mock *MockLocationDAO
+}
+
+// NewMockLocationDAO creates a new mock instance.
+func NewMockLocationDAO(ctrl *gomock.Controller) *MockLocationDAO {
+	mock := &MockLocationDAO{ctrl: ctrl}
+	mock.recorder = &MockLocationDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocationDAO) EXPECT() *MockLocationDAOMockRecorder {
+	return m.recorder
+}
+
+// CountAttached mocks base method.
+func (m *MockLocationDAO) CountAttached(ctx context.Context, id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAttached", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAttached indicates an expected call of CountAttached.
+func (mr *MockLocationDAOMockRecorder) CountAttached(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttached", reflect.TypeOf((*MockLocationDAO)(nil).CountAttached), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockLocationDAO) Create(ctx context.Context, location *entity.Location) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, location)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLocationDAOMockRecorder) Create(ctx, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLocationDAO)(nil).Create), ctx, location)
+}
+
+// Delete mocks base method.
+func (m *MockLocationDAO) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLocationDAOMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLocationDAO)(nil).Delete), ctx, id)
+}
+
+// FindAll mocks base method.
+func (m *MockLocationDAO) FindAll(ctx context.Context) ([]*entity.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]*entity.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockLocationDAOMockRecorder) FindAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockLocationDAO)(nil).FindAll), ctx)
+}
+
+// FindByID mocks base method.
+func (m *MockLocationDAO) FindByID(ctx context.Context, id uint) (*entity.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockLocationDAOMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockLocationDAO)(nil).FindByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockLocationDAO) Update(ctx context.Context, location *entity.Location) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, location)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockLocationDAOMockRecorder) Update(ctx, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockLocationDAO)(nil).Update), ctx, location)
+}
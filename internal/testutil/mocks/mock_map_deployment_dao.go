@@ -0,0 +1,159 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/map_deployment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/map_deployment.go -destination=internal/testutil/mocks/mock_map_deployment_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMapDeploymentDAO is a mock of MapDeploymentDAO interface.
+type MockMapDeploymentDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockMapDeploymentDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockMapDeploymentDAOMockRecorder is the mock recorder for MockMapDeploymentDAO.
+type MockMapDeploymentDAOMockRecorder struct {
+	mock *MockMapDeploymentDAO
+}
+
+// NewMockMapDeploymentDAO creates a new mock instance.
+func NewMockMapDeploymentDAO(ctrl *gomock.Controller) *MockMapDeploymentDAO {
+	mock := &MockMapDeploymentDAO{ctrl: ctrl}
+	mock.recorder = &MockMapDeploymentDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMapDeploymentDAO) EXPECT() *MockMapDeploymentDAOMockRecorder {
+	return m.recorder
+}
+
+// ClaimNext mocks base method.
+func (m *MockMapDeploymentDAO) ClaimNext(ctx context.Context) (*entity.MapDeployment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNext", ctx)
+	ret0, _ := ret[0].(*entity.MapDeployment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNext indicates an expected call of ClaimNext.
+func (mr *MockMapDeploymentDAOMockRecorder) ClaimNext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNext", reflect.TypeOf((*MockMapDeploymentDAO)(nil).ClaimNext), ctx)
+}
+
+// Create mocks base method.
+func (m *MockMapDeploymentDAO) Create(ctx context.Context, deployment *entity.MapDeployment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, deployment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMapDeploymentDAOMockRecorder) Create(ctx, deployment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMapDeploymentDAO)(nil).Create), ctx, deployment)
+}
+
+// FindActive mocks base method.
+func (m *MockMapDeploymentDAO) FindActive(ctx context.Context, deviceID uint) (*entity.MapDeployment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActive", ctx, deviceID)
+	ret0, _ := ret[0].(*entity.MapDeployment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActive indicates an expected call of FindActive.
+func (mr *MockMapDeploymentDAOMockRecorder) FindActive(ctx, deviceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActive", reflect.TypeOf((*MockMapDeploymentDAO)(nil).FindActive), ctx, deviceID)
+}
+
+// FindByID mocks base method.
+func (m *MockMapDeploymentDAO) FindByID(ctx context.Context, id uint) (*entity.MapDeployment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.MapDeployment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockMapDeploymentDAOMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockMapDeploymentDAO)(nil).FindByID), ctx, id)
+}
+
+// Finish mocks base method.
+func (m *MockMapDeploymentDAO) Finish(ctx context.Context, deployment *entity.MapDeployment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, deployment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockMapDeploymentDAOMockRecorder) Finish(ctx, deployment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockMapDeploymentDAO)(nil).Finish), ctx, deployment)
+}
+
+// ListByDevice mocks base method.
+func (m *MockMapDeploymentDAO) ListByDevice(ctx context.Context, deviceID uint) ([]*entity.MapDeployment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByDevice", ctx, deviceID)
+	ret0, _ := ret[0].([]*entity.MapDeployment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByDevice indicates an expected call of ListByDevice.
+func (mr *MockMapDeploymentDAOMockRecorder) ListByDevice(ctx, deviceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByDevice", reflect.TypeOf((*MockMapDeploymentDAO)(nil).ListByDevice), ctx, deviceID)
+}
+
+// ResetRunning mocks base method.
+func (m *MockMapDeploymentDAO) ResetRunning(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetRunning", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetRunning indicates an expected call of ResetRunning.
+func (mr *MockMapDeploymentDAOMockRecorder) ResetRunning(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetRunning", reflect.TypeOf((*MockMapDeploymentDAO)(nil).ResetRunning), ctx)
+}
+
+// UpdateProgress mocks base method.
+func (m *MockMapDeploymentDAO) UpdateProgress(ctx context.Context, id uint, sentBytes, totalBytes int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProgress", ctx, id, sentBytes, totalBytes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProgress indicates an expected call of UpdateProgress.
+func (mr *MockMapDeploymentDAOMockRecorder) UpdateProgress(ctx, id, sentBytes, totalBytes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProgress", reflect.TypeOf((*MockMapDeploymentDAO)(nil).UpdateProgress), ctx, id, sentBytes, totalBytes)
+}
@@ -0,0 +1,184 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/map_version.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/map_version.go -destination=internal/testutil/mocks/mock_map_version_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPCDFileVersionDAO is a mock of PCDFileVersionDAO interface.
+type MockPCDFileVersionDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockPCDFileVersionDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockPCDFileVersionDAOMockRecorder is the mock recorder for MockPCDFileVersionDAO.
+type MockPCDFileVersionDAOMockRecorder struct {
+	mock *MockPCDFileVersionDAO
+}
+
+// NewMockPCDFileVersionDAO creates a new mock instance.
+func NewMockPCDFileVersionDAO(ctrl *gomock.Controller) *MockPCDFileVersionDAO {
+	mock := &MockPCDFileVersionDAO{ctrl: ctrl}
+	mock.recorder = &MockPCDFileVersionDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPCDFileVersionDAO) EXPECT() *MockPCDFileVersionDAOMockRecorder {
+	return m.recorder
+}
+
+// CountByMinioPath mocks base method.
+func (m *MockPCDFileVersionDAO) CountByMinioPath(ctx context.Context, minioPath string, excludeFileID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByMinioPath", ctx, minioPath, excludeFileID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByMinioPath indicates an expected call of CountByMinioPath.
+func (mr *MockPCDFileVersionDAOMockRecorder) CountByMinioPath(ctx, minioPath, excludeFileID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByMinioPath", reflect.TypeOf((*MockPCDFileVersionDAO)(nil).CountByMinioPath), ctx, minioPath, excludeFileID)
+}
+
+// Create mocks base method.
+func (m *MockPCDFileVersionDAO) Create(ctx context.Context, version *entity.PCDFileVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPCDFileVersionDAOMockRecorder) Create(ctx, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPCDFileVersionDAO)(nil).Create), ctx, version)
+}
+
+// FindByFile mocks base method.
+func (m *MockPCDFileVersionDAO) FindByFile(ctx context.Context, pcdFileID uint) ([]*entity.PCDFileVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFile", ctx, pcdFileID)
+	ret0, _ := ret[0].([]*entity.PCDFileVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByFile indicates an expected call of FindByFile.
+func (mr *MockPCDFileVersionDAOMockRecorder) FindByFile(ctx, pcdFileID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFile", reflect.TypeOf((*MockPCDFileVersionDAO)(nil).FindByFile), ctx, pcdFileID)
+}
+
+// FindByVersion mocks base method.
+func (m *MockPCDFileVersionDAO) FindByVersion(ctx context.Context, pcdFileID uint, version int) (*entity.PCDFileVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByVersion", ctx, pcdFileID, version)
+	ret0, _ := ret[0].(*entity.PCDFileVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByVersion indicates an expected call of FindByVersion.
+func (mr *MockPCDFileVersionDAOMockRecorder) FindByVersion(ctx, pcdFileID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByVersion", reflect.TypeOf((*MockPCDFileVersionDAO)(nil).FindByVersion), ctx, pcdFileID, version)
+}
+
+// FindMinioPaths mocks base method.
+func (m *MockPCDFileVersionDAO) FindMinioPaths(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMinioPaths", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMinioPaths indicates an expected call of FindMinioPaths.
+func (mr *MockPCDFileVersionDAOMockRecorder) FindMinioPaths(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMinioPaths", reflect.TypeOf((*MockPCDFileVersionDAO)(nil).FindMinioPaths), ctx)
+}
+
+// MockSemanticMapVersionDAO is a mock of SemanticMapVersionDAO interface.
+type MockSemanticMapVersionDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockSemanticMapVersionDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockSemanticMapVersionDAOMockRecorder is the mock recorder for MockSemanticMapVersionDAO.
+type MockSemanticMapVersionDAOMockRecorder struct {
+	mock *MockSemanticMapVersionDAO
+}
+
+// NewMockSemanticMapVersionDAO creates a new mock instance.
+func NewMockSemanticMapVersionDAO(ctrl *gomock.Controller) *MockSemanticMapVersionDAO {
+	mock := &MockSemanticMapVersionDAO{ctrl: ctrl}
+	mock.recorder = &MockSemanticMapVersionDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSemanticMapVersionDAO) EXPECT() *MockSemanticMapVersionDAOMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSemanticMapVersionDAO) Create(ctx context.Context, version *entity.SemanticMapVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSemanticMapVersionDAOMockRecorder) Create(ctx, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSemanticMapVersionDAO)(nil).Create), ctx, version)
+}
+
+// FindByMap mocks base method.
+func (m *MockSemanticMapVersionDAO) FindByMap(ctx context.Context, semanticMapID uint) ([]*entity.SemanticMapVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByMap", ctx, semanticMapID)
+	ret0, _ := ret[0].([]*entity.SemanticMapVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByMap indicates an expected call of FindByMap.
+func (mr *MockSemanticMapVersionDAOMockRecorder) FindByMap(ctx, semanticMapID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByMap", reflect.TypeOf((*MockSemanticMapVersionDAO)(nil).FindByMap), ctx, semanticMapID)
+}
+
+// FindByVersion mocks base method.
+func (m *MockSemanticMapVersionDAO) FindByVersion(ctx context.Context, semanticMapID uint, version int) (*entity.SemanticMapVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByVersion", ctx, semanticMapID, version)
+	ret0, _ := ret[0].(*entity.SemanticMapVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByVersion indicates an expected call of FindByVersion.
+func (mr *MockSemanticMapVersionDAOMockRecorder) FindByVersion(ctx, semanticMapID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByVersion", reflect.TypeOf((*MockSemanticMapVersionDAO)(nil).FindByVersion), ctx, semanticMapID, version)
+}
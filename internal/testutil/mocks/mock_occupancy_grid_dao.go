@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/occupancy_grid.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/occupancy_grid.go -destination=internal/testutil/mocks/mock_occupancy_grid_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOccupancyGridDAO is a mock of OccupancyGridDAO interface.
+type MockOccupancyGridDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockOccupancyGridDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockOccupancyGridDAOMockRecorder is the mock recorder for MockOccupancyGridDAO.
+type MockOccupancyGridDAOMockRecorder struct {
+	mock *MockOccupancyGridDAO
+}
+
+// NewMockOccupancyGridDAO creates a new mock instance.
+func NewMockOccupancyGridDAO(ctrl *gomock.Controller) *MockOccupancyGridDAO {
+	mock := &MockOccupancyGridDAO{ctrl: ctrl}
+	mock.recorder = &MockOccupancyGridDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOccupancyGridDAO) EXPECT() *MockOccupancyGridDAOMockRecorder {
+	return m.recorder
+}
+
+// CountSemanticMaps mocks base method.
+func (m *MockOccupancyGridDAO) CountSemanticMaps(ctx context.Context, id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSemanticMaps", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSemanticMaps indicates an expected call of CountSemanticMaps.
+func (mr *MockOccupancyGridDAOMockRecorder) CountSemanticMaps(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSemanticMaps", reflect.TypeOf((*MockOccupancyGridDAO)(nil).CountSemanticMaps), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockOccupancyGridDAO) Create(ctx context.Context, grid *entity.OccupancyGrid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, grid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOccupancyGridDAOMockRecorder) Create(ctx, grid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOccupancyGridDAO)(nil).Create), ctx, grid)
+}
+
+// Delete mocks base method.
+func (m *MockOccupancyGridDAO) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockOccupancyGridDAOMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOccupancyGridDAO)(nil).Delete), ctx, id)
+}
+
+// FindByID mocks base method.
+func (m *MockOccupancyGridDAO) FindByID(ctx context.Context, id uint) (*entity.OccupancyGrid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.OccupancyGrid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockOccupancyGridDAOMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockOccupancyGridDAO)(nil).FindByID), ctx, id)
+}
+
+// FindByPCDFile mocks base method.
+func (m *MockOccupancyGridDAO) FindByPCDFile(ctx context.Context, pcdFileID uint) ([]*entity.OccupancyGrid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPCDFile", ctx, pcdFileID)
+	ret0, _ := ret[0].([]*entity.OccupancyGrid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPCDFile indicates an expected call of FindByPCDFile.
+func (mr *MockOccupancyGridDAOMockRecorder) FindByPCDFile(ctx, pcdFileID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPCDFile", reflect.TypeOf((*MockOccupancyGridDAO)(nil).FindByPCDFile), ctx, pcdFileID)
+}
+
+// FindPage mocks base method.
+func (m *MockOccupancyGridDAO) FindPage(ctx context.Context, offset, limit int) ([]*entity.OccupancyGrid, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, offset, limit)
+	ret0, _ := ret[0].([]*entity.OccupancyGrid)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockOccupancyGridDAOMockRecorder) FindPage(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockOccupancyGridDAO)(nil).FindPage), ctx, offset, limit)
+}
@@ -12,7 +12,9 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	dao "robot_scheduler/internal/dao/interfaces"
 	entity "robot_scheduler/internal/model/entity"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +43,37 @@ func (m *MockPCDFileDAO) EXPECT() *MockPCDFileDAOMockRecorder {
 	return m.recorder
 }
 
+// CountByMinioPath mocks base method.
+func (m *MockPCDFileDAO) CountByMinioPath(ctx context.Context, minioPath string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByMinioPath", ctx, minioPath)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByMinioPath indicates an expected call of CountByMinioPath.
+func (mr *MockPCDFileDAOMockRecorder) CountByMinioPath(ctx, minioPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByMinioPath", reflect.TypeOf((*MockPCDFileDAO)(nil).CountByMinioPath), ctx, minioPath)
+}
+
+// CountDependents mocks base method.
+func (m *MockPCDFileDAO) CountDependents(ctx context.Context, id uint) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDependents", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountDependents indicates an expected call of CountDependents.
+func (mr *MockPCDFileDAOMockRecorder) CountDependents(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDependents", reflect.TypeOf((*MockPCDFileDAO)(nil).CountDependents), ctx, id)
+}
+
 // Create mocks base method.
 func (m *MockPCDFileDAO) Create(ctx context.Context, file *entity.PCDFile) error {
 	m.ctrl.T.Helper()
@@ -69,6 +102,20 @@ func (mr *MockPCDFileDAOMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPCDFileDAO)(nil).Delete), ctx, id)
 }
 
+// DeleteCascade mocks base method.
+func (m *MockPCDFileDAO) DeleteCascade(ctx context.Context, id uint, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCascade", ctx, id, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCascade indicates an expected call of DeleteCascade.
+func (mr *MockPCDFileDAOMockRecorder) DeleteCascade(ctx, id, force any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCascade", reflect.TypeOf((*MockPCDFileDAO)(nil).DeleteCascade), ctx, id, force)
+}
+
 // FindAll mocks base method.
 func (m *MockPCDFileDAO) FindAll(ctx context.Context) ([]*entity.PCDFile, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +131,21 @@ func (mr *MockPCDFileDAOMockRecorder) FindAll(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockPCDFileDAO)(nil).FindAll), ctx)
 }
 
+// FindAllWithObjects mocks base method.
+func (m *MockPCDFileDAO) FindAllWithObjects(ctx context.Context) ([]*entity.PCDFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllWithObjects", ctx)
+	ret0, _ := ret[0].([]*entity.PCDFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllWithObjects indicates an expected call of FindAllWithObjects.
+func (mr *MockPCDFileDAOMockRecorder) FindAllWithObjects(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllWithObjects", reflect.TypeOf((*MockPCDFileDAO)(nil).FindAllWithObjects), ctx)
+}
+
 // FindByID mocks base method.
 func (m *MockPCDFileDAO) FindByID(ctx context.Context, id uint) (*entity.PCDFile, error) {
 	m.ctrl.T.Helper()
@@ -114,10 +176,55 @@ func (mr *MockPCDFileDAOMockRecorder) FindByName(ctx, name any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockPCDFileDAO)(nil).FindByName), ctx, name)
 }
 
+// FindBySHA256 mocks base method.
+func (m *MockPCDFileDAO) FindBySHA256(ctx context.Context, sha256 string) (*entity.PCDFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySHA256", ctx, sha256)
+	ret0, _ := ret[0].(*entity.PCDFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySHA256 indicates an expected call of FindBySHA256.
+func (mr *MockPCDFileDAOMockRecorder) FindBySHA256(ctx, sha256 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySHA256", reflect.TypeOf((*MockPCDFileDAO)(nil).FindBySHA256), ctx, sha256)
+}
+
+// FindDeletedBefore mocks base method.
+func (m *MockPCDFileDAO) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*entity.PCDFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedBefore", ctx, before, limit)
+	ret0, _ := ret[0].([]*entity.PCDFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedBefore indicates an expected call of FindDeletedBefore.
+func (mr *MockPCDFileDAOMockRecorder) FindDeletedBefore(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedBefore", reflect.TypeOf((*MockPCDFileDAO)(nil).FindDeletedBefore), ctx, before, limit)
+}
+
+// FindIntegrityDue mocks base method.
+func (m *MockPCDFileDAO) FindIntegrityDue(ctx context.Context, before time.Time, limit int) ([]*entity.PCDFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIntegrityDue", ctx, before, limit)
+	ret0, _ := ret[0].([]*entity.PCDFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIntegrityDue indicates an expected call of FindIntegrityDue.
+func (mr *MockPCDFileDAOMockRecorder) FindIntegrityDue(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIntegrityDue", reflect.TypeOf((*MockPCDFileDAO)(nil).FindIntegrityDue), ctx, before, limit)
+}
+
 // FindPage mocks base method.
-func (m *MockPCDFileDAO) FindPage(ctx context.Context, offset, limit int) ([]*entity.PCDFile, int64, error) {
+func (m *MockPCDFileDAO) FindPage(ctx context.Context, filter dao.PCDFileFilter, offset, limit int) ([]*entity.PCDFile, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindPage", ctx, offset, limit)
+	ret := m.ctrl.Call(m, "FindPage", ctx, filter, offset, limit)
 	ret0, _ := ret[0].([]*entity.PCDFile)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -125,9 +232,23 @@ func (m *MockPCDFileDAO) FindPage(ctx context.Context, offset, limit int) ([]*en
 }
 
 // FindPage indicates an expected call of FindPage.
-func (mr *MockPCDFileDAOMockRecorder) FindPage(ctx, offset, limit any) *gomock.Call {
+func (mr *MockPCDFileDAOMockRecorder) FindPage(ctx, filter, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockPCDFileDAO)(nil).FindPage), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockPCDFileDAO)(nil).FindPage), ctx, filter, offset, limit)
+}
+
+// MarkObjectPurged mocks base method.
+func (m *MockPCDFileDAO) MarkObjectPurged(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkObjectPurged", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkObjectPurged indicates an expected call of MarkObjectPurged.
+func (mr *MockPCDFileDAOMockRecorder) MarkObjectPurged(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkObjectPurged", reflect.TypeOf((*MockPCDFileDAO)(nil).MarkObjectPurged), ctx, id)
 }
 
 // Update mocks base method.
@@ -143,3 +264,45 @@ func (mr *MockPCDFileDAOMockRecorder) Update(ctx, file any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPCDFileDAO)(nil).Update), ctx, file)
 }
+
+// UpdateAnalysisStatus mocks base method.
+func (m *MockPCDFileDAO) UpdateAnalysisStatus(ctx context.Context, id uint, status entity.PCDJobStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAnalysisStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAnalysisStatus indicates an expected call of UpdateAnalysisStatus.
+func (mr *MockPCDFileDAOMockRecorder) UpdateAnalysisStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAnalysisStatus", reflect.TypeOf((*MockPCDFileDAO)(nil).UpdateAnalysisStatus), ctx, id, status)
+}
+
+// UpdateIntegrity mocks base method.
+func (m *MockPCDFileDAO) UpdateIntegrity(ctx context.Context, file *entity.PCDFile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIntegrity", ctx, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIntegrity indicates an expected call of UpdateIntegrity.
+func (mr *MockPCDFileDAOMockRecorder) UpdateIntegrity(ctx, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIntegrity", reflect.TypeOf((*MockPCDFileDAO)(nil).UpdateIntegrity), ctx, file)
+}
+
+// UpdatePreview mocks base method.
+func (m *MockPCDFileDAO) UpdatePreview(ctx context.Context, id uint, preview *entity.PCDPreview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreview", ctx, id, preview)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePreview indicates an expected call of UpdatePreview.
+func (mr *MockPCDFileDAOMockRecorder) UpdatePreview(ctx, id, preview any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreview", reflect.TypeOf((*MockPCDFileDAO)(nil).UpdatePreview), ctx, id, preview)
+}
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/pcd_job.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/pcd_job.go -destination=internal/testutil/mocks/mock_pcd_job_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPCDJobDAO is a mock of PCDJobDAO interface.
+type MockPCDJobDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockPCDJobDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockPCDJobDAOMockRecorder is the mock recorder for MockPCDJobDAO.
+type MockPCDJobDAOMockRecorder struct {
+	mock *MockPCDJobDAO
+}
+
+// NewMockPCDJobDAO creates a new mock instance.
+func NewMockPCDJobDAO(ctrl *gomock.Controller) *MockPCDJobDAO {
+	mock := &MockPCDJobDAO{ctrl: ctrl}
+	mock.recorder = &MockPCDJobDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPCDJobDAO) EXPECT() *MockPCDJobDAOMockRecorder {
+	return m.recorder
+}
+
+// ClaimNext mocks base method.
+func (m *MockPCDJobDAO) ClaimNext(ctx context.Context) (*entity.PCDJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNext", ctx)
+	ret0, _ := ret[0].(*entity.PCDJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNext indicates an expected call of ClaimNext.
+func (mr *MockPCDJobDAOMockRecorder) ClaimNext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNext", reflect.TypeOf((*MockPCDJobDAO)(nil).ClaimNext), ctx)
+}
+
+// Create mocks base method.
+func (m *MockPCDJobDAO) Create(ctx context.Context, job *entity.PCDJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPCDJobDAOMockRecorder) Create(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPCDJobDAO)(nil).Create), ctx, job)
+}
+
+// FindActive mocks base method.
+func (m *MockPCDJobDAO) FindActive(ctx context.Context, pcdFileID uint, jobType entity.PCDJobType) (*entity.PCDJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActive", ctx, pcdFileID, jobType)
+	ret0, _ := ret[0].(*entity.PCDJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActive indicates an expected call of FindActive.
+func (mr *MockPCDJobDAOMockRecorder) FindActive(ctx, pcdFileID, jobType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActive", reflect.TypeOf((*MockPCDJobDAO)(nil).FindActive), ctx, pcdFileID, jobType)
+}
+
+// Finish mocks base method.
+func (m *MockPCDJobDAO) Finish(ctx context.Context, job *entity.PCDJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockPCDJobDAOMockRecorder) Finish(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockPCDJobDAO)(nil).Finish), ctx, job)
+}
+
+// ListByFile mocks base method.
+func (m *MockPCDJobDAO) ListByFile(ctx context.Context, pcdFileID uint) ([]*entity.PCDJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByFile", ctx, pcdFileID)
+	ret0, _ := ret[0].([]*entity.PCDJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByFile indicates an expected call of ListByFile.
+func (mr *MockPCDJobDAOMockRecorder) ListByFile(ctx, pcdFileID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFile", reflect.TypeOf((*MockPCDJobDAO)(nil).ListByFile), ctx, pcdFileID)
+}
+
+// ResetRunning mocks base method.
+func (m *MockPCDJobDAO) ResetRunning(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetRunning", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetRunning indicates an expected call of ResetRunning.
+func (mr *MockPCDJobDAOMockRecorder) ResetRunning(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetRunning", reflect.TypeOf((*MockPCDJobDAO)(nil).ResetRunning), ctx)
+}
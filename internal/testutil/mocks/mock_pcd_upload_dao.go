@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/dao/interfaces/pcd_upload.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/dao/interfaces/pcd_upload.go -destination=internal/testutil/mocks/mock_pcd_upload_dao.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "robot_scheduler/internal/model/entity"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPCDUploadDAO is a mock of PCDUploadDAO interface.
+type MockPCDUploadDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockPCDUploadDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockPCDUploadDAOMockRecorder is the mock recorder for MockPCDUploadDAO.
+type MockPCDUploadDAOMockRecorder struct {
+	mock *MockPCDUploadDAO
+}
+
+// NewMockPCDUploadDAO creates a new mock instance.
+func NewMockPCDUploadDAO(ctrl *gomock.Controller) *MockPCDUploadDAO {
+	mock := &MockPCDUploadDAO{ctrl: ctrl}
+	mock.recorder = &MockPCDUploadDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPCDUploadDAO) EXPECT() *MockPCDUploadDAOMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockPCDUploadDAO) Complete(ctx context.Context, upload *entity.PCDUpload, file *entity.PCDFile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, upload, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockPCDUploadDAOMockRecorder) Complete(ctx, upload, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockPCDUploadDAO)(nil).Complete), ctx, upload, file)
+}
+
+// Create mocks base method.
+func (m *MockPCDUploadDAO) Create(ctx context.Context, upload *entity.PCDUpload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, upload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPCDUploadDAOMockRecorder) Create(ctx, upload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPCDUploadDAO)(nil).Create), ctx, upload)
+}
+
+// ExtendExpire mocks base method.
+func (m *MockPCDUploadDAO) ExtendExpire(ctx context.Context, id uint, expireAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendExpire", ctx, id, expireAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendExpire indicates an expected call of ExtendExpire.
+func (mr *MockPCDUploadDAOMockRecorder) ExtendExpire(ctx, id, expireAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendExpire", reflect.TypeOf((*MockPCDUploadDAO)(nil).ExtendExpire), ctx, id, expireAt)
+}
+
+// FindByObjectKey mocks base method.
+func (m *MockPCDUploadDAO) FindByObjectKey(ctx context.Context, objectKey string) (*entity.PCDUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByObjectKey", ctx, objectKey)
+	ret0, _ := ret[0].(*entity.PCDUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByObjectKey indicates an expected call of FindByObjectKey.
+func (mr *MockPCDUploadDAOMockRecorder) FindByObjectKey(ctx, objectKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByObjectKey", reflect.TypeOf((*MockPCDUploadDAO)(nil).FindByObjectKey), ctx, objectKey)
+}
+
+// FindExpiredPending mocks base method.
+func (m *MockPCDUploadDAO) FindExpiredPending(ctx context.Context, before time.Time, limit int) ([]*entity.PCDUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpiredPending", ctx, before, limit)
+	ret0, _ := ret[0].([]*entity.PCDUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpiredPending indicates an expected call of FindExpiredPending.
+func (mr *MockPCDUploadDAOMockRecorder) FindExpiredPending(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiredPending", reflect.TypeOf((*MockPCDUploadDAO)(nil).FindExpiredPending), ctx, before, limit)
+}
+
+// MarkAborted mocks base method.
+func (m *MockPCDUploadDAO) MarkAborted(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAborted", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAborted indicates an expected call of MarkAborted.
+func (mr *MockPCDUploadDAOMockRecorder) MarkAborted(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAborted", reflect.TypeOf((*MockPCDUploadDAO)(nil).MarkAborted), ctx, id)
+}
+
+// MarkExpired mocks base method.
+func (m *MockPCDUploadDAO) MarkExpired(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExpired", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkExpired indicates an expected call of MarkExpired.
+func (mr *MockPCDUploadDAOMockRecorder) MarkExpired(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExpired", reflect.TypeOf((*MockPCDUploadDAO)(nil).MarkExpired), ctx, id)
+}
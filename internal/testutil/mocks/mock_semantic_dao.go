@@ -12,7 +12,9 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	dao "robot_scheduler/internal/dao/interfaces"
 	entity "robot_scheduler/internal/model/entity"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +43,20 @@ func (m *MockSemanticMapDAO) EXPECT() *MockSemanticMapDAOMockRecorder {
 	return m.recorder
 }
 
+// AcquireLock mocks base method.
+func (m *MockSemanticMapDAO) AcquireLock(ctx context.Context, id uint, userName string, now, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireLock", ctx, id, userName, now, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcquireLock indicates an expected call of AcquireLock.
+func (mr *MockSemanticMapDAOMockRecorder) AcquireLock(ctx, id, userName, now, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLock", reflect.TypeOf((*MockSemanticMapDAO)(nil).AcquireLock), ctx, id, userName, now, expiresAt)
+}
+
 // Create mocks base method.
 func (m *MockSemanticMapDAO) Create(ctx context.Context, semanticMap *entity.SemanticMap) error {
 	m.ctrl.T.Helper()
@@ -99,10 +115,25 @@ func (mr *MockSemanticMapDAOMockRecorder) FindByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockSemanticMapDAO)(nil).FindByID), ctx, id)
 }
 
+// FindCheckStale mocks base method.
+func (m *MockSemanticMapDAO) FindCheckStale(ctx context.Context, limit int) ([]*entity.SemanticMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCheckStale", ctx, limit)
+	ret0, _ := ret[0].([]*entity.SemanticMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCheckStale indicates an expected call of FindCheckStale.
+func (mr *MockSemanticMapDAOMockRecorder) FindCheckStale(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCheckStale", reflect.TypeOf((*MockSemanticMapDAO)(nil).FindCheckStale), ctx, limit)
+}
+
 // FindPage mocks base method.
-func (m *MockSemanticMapDAO) FindPage(ctx context.Context, offset, limit int) ([]*entity.SemanticMap, int64, error) {
+func (m *MockSemanticMapDAO) FindPage(ctx context.Context, filter dao.SemanticMapFilter, offset, limit int) ([]*entity.SemanticMap, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindPage", ctx, offset, limit)
+	ret := m.ctrl.Call(m, "FindPage", ctx, filter, offset, limit)
 	ret0, _ := ret[0].([]*entity.SemanticMap)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -110,21 +141,68 @@ func (m *MockSemanticMapDAO) FindPage(ctx context.Context, offset, limit int) ([
 }
 
 // FindPage indicates an expected call of FindPage.
-func (mr *MockSemanticMapDAOMockRecorder) FindPage(ctx, offset, limit any) *gomock.Call {
+func (mr *MockSemanticMapDAOMockRecorder) FindPage(ctx, filter, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockSemanticMapDAO)(nil).FindPage), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockSemanticMapDAO)(nil).FindPage), ctx, filter, offset, limit)
+}
+
+// ReleaseLock mocks base method.
+func (m *MockSemanticMapDAO) ReleaseLock(ctx context.Context, id uint, userName string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseLock", ctx, id, userName, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseLock indicates an expected call of ReleaseLock.
+func (mr *MockSemanticMapDAOMockRecorder) ReleaseLock(ctx, id, userName, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLock", reflect.TypeOf((*MockSemanticMapDAO)(nil).ReleaseLock), ctx, id, userName, now)
 }
 
 // Update mocks base method.
-func (m *MockSemanticMapDAO) Update(ctx context.Context, semanticMap *entity.SemanticMap) error {
+func (m *MockSemanticMapDAO) Update(ctx context.Context, semanticMap *entity.SemanticMap, editor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, semanticMap)
+	ret := m.ctrl.Call(m, "Update", ctx, semanticMap, editor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockSemanticMapDAOMockRecorder) Update(ctx, semanticMap any) *gomock.Call {
+func (mr *MockSemanticMapDAOMockRecorder) Update(ctx, semanticMap, editor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSemanticMapDAO)(nil).Update), ctx, semanticMap, editor)
+}
+
+// UpdateCheck mocks base method.
+func (m *MockSemanticMapDAO) UpdateCheck(ctx context.Context, id uint, check *entity.SemanticCheck) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCheck", ctx, id, check)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCheck indicates an expected call of UpdateCheck.
+func (mr *MockSemanticMapDAOMockRecorder) UpdateCheck(ctx, id, check any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCheck", reflect.TypeOf((*MockSemanticMapDAO)(nil).UpdateCheck), ctx, id, check)
+}
+
+// UpdateWithVersions mocks base method.
+func (m *MockSemanticMapDAO) UpdateWithVersions(ctx context.Context, semanticMap *entity.SemanticMap, editor string, versions ...*entity.SemanticMapVersion) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, semanticMap, editor}
+	for _, a := range versions {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateWithVersions", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWithVersions indicates an expected call of UpdateWithVersions.
+func (mr *MockSemanticMapDAOMockRecorder) UpdateWithVersions(ctx, semanticMap, editor any, versions ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSemanticMapDAO)(nil).Update), ctx, semanticMap)
+	varargs := append([]any{ctx, semanticMap, editor}, versions...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithVersions", reflect.TypeOf((*MockSemanticMapDAO)(nil).UpdateWithVersions), varargs...)
 }
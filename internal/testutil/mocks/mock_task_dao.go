@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	dao "robot_scheduler/internal/dao/interfaces"
 	entity "robot_scheduler/internal/model/entity"
 
 	gomock "go.uber.org/mock/gomock"
@@ -100,9 +101,9 @@ func (mr *MockTaskDAOMockRecorder) FindByID(ctx, id any) *gomock.Call {
 }
 
 // FindPage mocks base method.
-func (m *MockTaskDAO) FindPage(ctx context.Context, offset, limit int) ([]*entity.Task, int64, error) {
+func (m *MockTaskDAO) FindPage(ctx context.Context, filter dao.TaskFilter, offset, limit int) ([]*entity.Task, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindPage", ctx, offset, limit)
+	ret := m.ctrl.Call(m, "FindPage", ctx, filter, offset, limit)
 	ret0, _ := ret[0].([]*entity.Task)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -110,9 +111,9 @@ func (m *MockTaskDAO) FindPage(ctx context.Context, offset, limit int) ([]*entit
 }
 
 // FindPage indicates an expected call of FindPage.
-func (mr *MockTaskDAOMockRecorder) FindPage(ctx, offset, limit any) *gomock.Call {
+func (mr *MockTaskDAOMockRecorder) FindPage(ctx, filter, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockTaskDAO)(nil).FindPage), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockTaskDAO)(nil).FindPage), ctx, filter, offset, limit)
 }
 
 // Update mocks base method.
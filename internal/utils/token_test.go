@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateRandomToken_Format(t *testing.T) {
+	token, err := GenerateRandomToken("dev_", 16)
+	if err != nil {
+		t.Fatalf("GenerateRandomToken failed: %v", err)
+	}
+
+	if !strings.HasPrefix(token, "dev_") {
+		t.Errorf("Expected prefix dev_, got %s", token)
+	}
+
+	if len(token) != len("dev_")+32 {
+		t.Errorf("Expected length %d, got %d", len("dev_")+32, len(token))
+	}
+}
+
+func TestGenerateRandomToken_Unique(t *testing.T) {
+	a, _ := GenerateRandomToken("", 16)
+	b, _ := GenerateRandomToken("", 16)
+
+	if a == b {
+		t.Error("Expected two generated tokens to differ")
+	}
+}
+
+func TestHashToken_Deterministic(t *testing.T) {
+	if HashToken("abc") != HashToken("abc") {
+		t.Error("Expected same hash for same input")
+	}
+
+	if HashToken("abc") == HashToken("abd") {
+		t.Error("Expected different hash for different input")
+	}
+
+	// SHA-256("abc")
+	expected := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if HashToken("abc") != expected {
+		t.Errorf("Expected %s, got %s", expected, HashToken("abc"))
+	}
+}